@@ -70,6 +70,10 @@ echo -n '\.' >> import.sql
 psql -d testdb < import.sql
 psql -d testdb -c "SELECT * FROM playground"  | grep brown
 
+# Check that COPY TO STDOUT streams rows in the text and CSV formats.
+psql -d testdb -c "COPY playground (equip_id, color) TO STDOUT" | grep "^1.blue$"
+psql -d testdb -c "COPY (SELECT type, location FROM playground WHERE equip_id = 2) TO STDOUT WITH CSV" | grep "^swing,northwest$"
+
 # Test that the app name set in the pgwire init exchange is propagated
 # down the session.
 psql -d testdb -c "show application_name" | grep psql
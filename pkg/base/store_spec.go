@@ -50,6 +50,10 @@ type StoreSpec struct {
 	SizePercent float64
 	InMemory    bool
 	Attributes  roachpb.Attributes
+	// Encryption is set if the store is encrypted at rest. It is populated
+	// from the --enterprise-encryption flag by
+	// PopulateStoreSpecsWithEncryption.
+	Encryption *StoreEncryptionSpec
 }
 
 // String returns a fully parsable version of the store spec.
@@ -271,6 +275,174 @@ func (ssl *StoreSpecList) Set(value string) error {
 	return nil
 }
 
+// PlaintextKeyName is the name used in place of a key file in an encryption
+// spec to denote that files are not encrypted.
+const PlaintextKeyName = "plain"
+
+// StoreEncryptionSpec contains the details that can be specified in the cli
+// pertaining to the --enterprise-encryption flag.
+type StoreEncryptionSpec struct {
+	// Path is the path of the store the spec applies to.
+	Path string
+	// KeyPath is the path of the file containing the key used to encrypt new
+	// files. It is empty if new files are written in plaintext.
+	KeyPath string
+	// OldKeyPath is the path of the file containing the key that was active
+	// before the last key rotation. It is empty if files were previously
+	// written in plaintext.
+	OldKeyPath string
+}
+
+// String returns a fully parsable version of the encryption spec.
+func (es StoreEncryptionSpec) String() string {
+	keyName := func(path string) string {
+		if path == "" {
+			return PlaintextKeyName
+		}
+		return path
+	}
+	return fmt.Sprintf("path=%s,key=%s,old-key=%s", es.Path, keyName(es.KeyPath), keyName(es.OldKeyPath))
+}
+
+// NewStoreEncryptionSpec parses the string passed into an
+// --enterprise-encryption flag and returns a StoreEncryptionSpec if it is
+// correctly parsed. There are three required fields, comma separated:
+// - path=xxx The path of the store the spec applies to. It must match the path
+//   of a --store flag.
+// - key=xxx The path of the file containing the key used to encrypt new files,
+//   or "plain" to write new files in plaintext.
+// - old-key=xxx The path of the file containing the key that was previously
+//   used to encrypt files, or "plain" if files were previously written in
+//   plaintext. Files encrypted with the old key remain readable until they
+//   have all been rewritten with the new key.
+// A key file contains a raw AES key of 16, 24 or 32 bytes.
+func NewStoreEncryptionSpec(value string) (StoreEncryptionSpec, error) {
+	const usage = "path=<store path>,key=<key file>,old-key=<old key file>"
+	var es StoreEncryptionSpec
+	used := make(map[string]struct{})
+	for _, split := range strings.Split(value, ",") {
+		if len(split) == 0 {
+			continue
+		}
+		subSplits := strings.SplitN(split, "=", 2)
+		if len(subSplits) == 1 {
+			return StoreEncryptionSpec{}, fmt.Errorf("field not in the form <key>=<value>: %s", split)
+		}
+		field := strings.ToLower(subSplits[0])
+		value := subSplits[1]
+		if _, ok := used[field]; ok {
+			return StoreEncryptionSpec{}, fmt.Errorf("%s field was used twice in encryption definition", field)
+		}
+		used[field] = struct{}{}
+		if len(value) == 0 {
+			return StoreEncryptionSpec{}, fmt.Errorf("no value specified for %s", field)
+		}
+
+		// keyPath returns the absolute path of a key file, or the empty
+		// string for plaintext.
+		keyPath := func(value string) (string, error) {
+			if value == PlaintextKeyName {
+				return "", nil
+			}
+			path, err := filepath.Abs(value)
+			if err != nil {
+				return "", errors.Wrapf(err, "could not find absolute path for %s", value)
+			}
+			return path, nil
+		}
+
+		var err error
+		switch field {
+		case "path":
+			es.Path, err = filepath.Abs(value)
+			if err != nil {
+				return StoreEncryptionSpec{}, errors.Wrapf(err, "could not find absolute path for %s", value)
+			}
+		case "key":
+			es.KeyPath, err = keyPath(value)
+		case "old-key":
+			es.OldKeyPath, err = keyPath(value)
+		default:
+			return StoreEncryptionSpec{}, fmt.Errorf("%s is not a valid enterprise-encryption field", field)
+		}
+		if err != nil {
+			return StoreEncryptionSpec{}, err
+		}
+	}
+	for _, field := range []string{"path", "key", "old-key"} {
+		if _, ok := used[field]; !ok {
+			return StoreEncryptionSpec{}, fmt.Errorf("%s field not specified, expected %s", field, usage)
+		}
+	}
+	return es, nil
+}
+
+// StoreEncryptionSpecList contains a slice of StoreEncryptionSpecs that
+// implements pflag's value interface.
+type StoreEncryptionSpecList struct {
+	Specs []StoreEncryptionSpec
+}
+
+var _ pflag.Value = &StoreEncryptionSpecList{}
+
+// String returns a string representation of all the StoreEncryptionSpecs.
+// This is part of pflag's value interface.
+func (esl StoreEncryptionSpecList) String() string {
+	var buffer bytes.Buffer
+	for _, es := range esl.Specs {
+		fmt.Fprintf(&buffer, "--enterprise-encryption=%s ", es)
+	}
+	// Trim the extra space from the end if it exists.
+	if l := buffer.Len(); l > 0 {
+		buffer.Truncate(l - 1)
+	}
+	return buffer.String()
+}
+
+// Type returns the underlying type in string form. This is part of pflag's
+// value interface.
+func (esl *StoreEncryptionSpecList) Type() string {
+	return "StoreEncryptionSpec"
+}
+
+// Set adds a new value to the StoreEncryptionSpecList. It is the important
+// part of pflag's value interface.
+func (esl *StoreEncryptionSpecList) Set(value string) error {
+	spec, err := NewStoreEncryptionSpec(value)
+	if err != nil {
+		return err
+	}
+	esl.Specs = append(esl.Specs, spec)
+	return nil
+}
+
+// PopulateStoreSpecsWithEncryption sets the Encryption field of each store
+// spec which has a matching encryption spec. It returns an error if an
+// encryption spec does not match exactly one on-disk store.
+func PopulateStoreSpecsWithEncryption(
+	storeSpecs StoreSpecList, encryptionSpecs StoreEncryptionSpecList,
+) error {
+	for _, es := range encryptionSpecs.Specs {
+		es := es
+		found := false
+		for j := range storeSpecs.Specs {
+			ss := &storeSpecs.Specs[j]
+			if ss.InMemory || ss.Path != es.Path {
+				continue
+			}
+			if ss.Encryption != nil {
+				return fmt.Errorf("store with path %s already has an encryption setting", ss.Path)
+			}
+			ss.Encryption = &es
+			found = true
+		}
+		if !found {
+			return fmt.Errorf("no store with path %s found for encryption setting: %s", es.Path, es)
+		}
+	}
+	return nil
+}
+
 // JoinListType is a slice of strings that implements pflag's value
 // interface.
 type JoinListType []string
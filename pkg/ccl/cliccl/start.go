@@ -0,0 +1,49 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/LICENSE
+
+package cliccl
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/cli"
+	"github.com/cockroachdb/cockroach/pkg/cli/cliflags"
+)
+
+var enterpriseEncryption = cliflags.FlagInfo{
+	Name: "enterprise-encryption",
+	Description: `
+Encryption at rest options for a store (enterprise feature). The "path" field
+must match the path of a store, and the "key" and "old-key" fields are the
+paths of files containing a raw AES key of 16, 24 or 32 bytes, or "plain" to
+disable encryption, for example:
+<PRE>
+
+  --enterprise-encryption=path=/mnt/ssd01,key=/keys/new.key,old-key=/keys/old.key
+
+</PRE>
+New files, including the raft log and temporary files written by the store,
+are encrypted with "key". This includes the data written by RESTORE and
+IMPORT, which is applied through the same write path as other writes. Existing
+files encrypted with "old-key", or in plaintext, remain readable and are
+gradually rewritten with the new key by compactions, which allows keys to be
+rotated by restarting the node with the previous key as "old-key". Until then,
+the data in those files is protected only by the old key, if any. The progress
+of a rotation is reported in the store details of the status server. To
+encrypt a store for the first time, use "old-key=plain".`,
+}
+
+// storeEncryptionSpecs holds the values of the --enterprise-encryption flags.
+var storeEncryptionSpecs base.StoreEncryptionSpecList
+
+func init() {
+	cli.AddStartFlag(&storeEncryptionSpecs, enterpriseEncryption)
+	// Match the encryption settings to the stores they apply to.
+	cli.AddStoreSpecsHook(func(storeSpecs base.StoreSpecList) error {
+		return base.PopulateStoreSpecsWithEncryption(storeSpecs, storeEncryptionSpecs)
+	})
+}
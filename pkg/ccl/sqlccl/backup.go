@@ -313,7 +313,7 @@ func Backup(
 
 	for _, desc := range sqlDescs {
 		if dbDesc := desc.GetDatabase(); dbDesc != nil {
-			if err := p.CheckPrivilege(dbDesc, privilege.SELECT); err != nil {
+			if err := p.CheckPrivilege(ctx, dbDesc, privilege.SELECT); err != nil {
 				return BackupDescriptor{}, err
 			}
 		}
@@ -328,7 +328,7 @@ func Backup(
 	}
 
 	for _, desc := range tables {
-		if err := p.CheckPrivilege(desc, privilege.SELECT); err != nil {
+		if err := p.CheckPrivilege(ctx, desc, privilege.SELECT); err != nil {
 			return BackupDescriptor{}, err
 		}
 	}
@@ -349,20 +349,26 @@ func Backup(
 	// completed requests as a rough measure of progress.
 	spans := splitSpansByRanges(spansForAllTableIndexes(tables), ranges)
 
-	mu := struct {
-		syncutil.Mutex
-		files    []BackupDescriptor_File
-		dataSize int64
-	}{}
-
-	progressLogger := jobProgressLogger{
-		jobLogger:   jobLogger,
-		totalChunks: len(spans),
-	}
-
 	for _, desc := range tables {
 		jobLogger.Job.DescriptorIDs = append(jobLogger.Job.DescriptorIDs, desc.GetID())
 	}
+	// The descriptor is completed with the exported files as the backup
+	// progresses.
+	desc := BackupDescriptor{
+		StartTime:     startTime,
+		EndTime:       endTime,
+		Descriptors:   sqlDescs,
+		Spans:         spans,
+		FormatVersion: BackupFormatInitialVersion,
+		BuildInfo:     build.GetInfo(),
+		NodeID:        p.ExecCfg().NodeID.Get(),
+		ClusterID:     p.ExecCfg().ClusterID(),
+	}
+	descBuf, err := desc.Marshal()
+	if err != nil {
+		return BackupDescriptor{}, err
+	}
+	jobLogger.Job.Details = sql.BackupJobDetails{URI: uri, BackupDescriptor: descBuf}
 	if err := jobLogger.Created(ctx); err != nil {
 		return BackupDescriptor{}, err
 	}
@@ -370,6 +376,89 @@ func Backup(
 		return BackupDescriptor{}, err
 	}
 
+	return runBackup(ctx, p.ExecCfg(), jobLogger)
+}
+
+// runBackup exports the spans of the backup described by the details of the
+// started jobLogger, starting from the last checkpoint in the details, and then
+// writes the backup descriptor.
+func runBackup(
+	ctx context.Context, execCfg *sql.ExecutorConfig, jobLogger *sql.JobLogger,
+) (BackupDescriptor, error) {
+	details, ok := jobLogger.Job.Details.(sql.BackupJobDetails)
+	if !ok {
+		return BackupDescriptor{}, errors.Errorf(
+			"unexpected details type %T for a BACKUP job", jobLogger.Job.Details)
+	}
+	var desc BackupDescriptor
+	if err := desc.Unmarshal(details.BackupDescriptor); err != nil {
+		return BackupDescriptor{}, errors.Wrap(err, "unmarshalling backup descriptor")
+	}
+
+	exportStore, err := exportStorageFromURI(ctx, details.URI)
+	if err != nil {
+		return BackupDescriptor{}, err
+	}
+	defer exportStore.Close()
+
+	db := execCfg.DB
+	spans := desc.Spans
+
+	// Export requests finish out of order, so the checkpoint recorded with the
+	// job's progress is the start key of the first span that has not yet been
+	// exported, along with the files of the spans below it. The spans are
+	// sorted, so a resumed backup can skip the spans below the checkpoint.
+	mu := struct {
+		syncutil.Mutex
+		files     [][]BackupDescriptor_File
+		dataSizes []int64
+		finished  []bool
+		highWater int
+	}{
+		files:     make([][]BackupDescriptor_File, len(spans)),
+		dataSizes: make([]int64, len(spans)),
+		finished:  make([]bool, len(spans)),
+	}
+	for mu.highWater < len(spans) && spans[mu.highWater].EndKey.Compare(details.HighWater) <= 0 {
+		mu.finished[mu.highWater] = true
+		mu.highWater++
+	}
+	resumedSpans := mu.highWater
+
+	// withFiles returns a copy of the descriptor with the files of the first n
+	// spans. It must be called with mu held.
+	withFiles := func(n int) BackupDescriptor {
+		d := desc
+		d.Files = append([]BackupDescriptor_File(nil), desc.Files...)
+		for i := resumedSpans; i < n; i++ {
+			d.Files = append(d.Files, mu.files[i]...)
+			d.DataSize += mu.dataSizes[i]
+		}
+		return d
+	}
+
+	progressLogger := jobProgressLogger{
+		jobLogger:     jobLogger,
+		totalChunks:   len(spans),
+		resumedChunks: resumedSpans,
+	}
+	progressLogger.checkpointFn = func() (interface{}, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		checkpoint := details
+		d := withFiles(mu.highWater)
+		var err error
+		if checkpoint.BackupDescriptor, err = d.Marshal(); err != nil {
+			return nil, err
+		}
+		if mu.highWater == len(spans) {
+			checkpoint.HighWater = spans[len(spans)-1].EndKey
+		} else {
+			checkpoint.HighWater = spans[mu.highWater].Key
+		}
+		return checkpoint, nil
+	}
+
 	// We're already limiting these on the server-side, but sending all the
 	// Export requests at once would fill up distsender/grpc/something and cause
 	// all sorts of badness (node liveness timeouts leading to mass leaseholder
@@ -386,26 +475,34 @@ func Backup(
 	// TODO(dan): Make this limiting per node.
 	//
 	// TODO(dan): See if there's some better solution than rate-limiting #14798.
-	maxConcurrentExports := clusterNodeCount(p.ExecCfg().Gossip) * storageccl.ExportRequestLimit
+	maxConcurrentExports := clusterNodeCount(execCfg.Gossip) * storageccl.ExportRequestLimit
 	exportsSem := make(chan struct{}, maxConcurrentExports)
 
-	header := roachpb.Header{Timestamp: endTime}
+	header := roachpb.Header{Timestamp: desc.EndTime}
 	g, gCtx := errgroup.WithContext(ctx)
-	for i := range spans {
+	for i := mu.highWater; i < len(spans); i++ {
+		// Stop issuing requests while the job is paused. The requests in flight
+		// are allowed to finish and their progress is still recorded.
+		if err := progressLogger.waitWhilePaused(ctx); err != nil {
+			// Wait for the requests in flight, so they don't race with the
+			// cleanup of the failed job.
+			_ = g.Wait()
+			return BackupDescriptor{}, err
+		}
 		select {
 		case exportsSem <- struct{}{}:
 		case <-ctx.Done():
 			return BackupDescriptor{}, ctx.Err()
 		}
 
-		span := spans[i]
+		i, span := i, spans[i]
 		g.Go(func() error {
 			defer func() { <-exportsSem }()
 
 			req := &roachpb.ExportRequest{
 				Span:      span,
 				Storage:   exportStore.Conf(),
-				StartTime: startTime,
+				StartTime: desc.StartTime,
 			}
 			res, pErr := client.SendWrappedWith(gCtx, db.GetSender(), header, req)
 			if pErr != nil {
@@ -413,12 +510,16 @@ func Backup(
 			}
 			mu.Lock()
 			for _, file := range res.(*roachpb.ExportResponse).Files {
-				mu.files = append(mu.files, BackupDescriptor_File{
+				mu.files[i] = append(mu.files[i], BackupDescriptor_File{
 					Span:   file.Span,
 					Path:   file.Path,
 					Sha512: file.Sha512,
 				})
-				mu.dataSize += file.DataSize
+				mu.dataSizes[i] += file.DataSize
+			}
+			mu.finished[i] = true
+			for mu.highWater < len(mu.finished) && mu.finished[mu.highWater] {
+				mu.highWater++
 			}
 			mu.Unlock()
 			if err := progressLogger.chunkFinished(ctx); err != nil {
@@ -431,23 +532,10 @@ func Backup(
 		})
 	}
 
-	if err = g.Wait(); err != nil {
+	if err := g.Wait(); err != nil {
 		return BackupDescriptor{}, errors.Wrapf(err, "exporting %d ranges", len(spans))
 	}
-	files, dataSize := mu.files, mu.dataSize // No more concurrency, so this is safe.
-
-	desc := BackupDescriptor{
-		StartTime:     startTime,
-		EndTime:       endTime,
-		Descriptors:   sqlDescs,
-		Spans:         spans,
-		Files:         files,
-		DataSize:      dataSize,
-		FormatVersion: BackupFormatInitialVersion,
-		BuildInfo:     build.GetInfo(),
-		NodeID:        p.ExecCfg().NodeID.Get(),
-		ClusterID:     p.ExecCfg().ClusterID(),
-	}
+	desc = withFiles(len(spans)) // No more concurrency, so this is safe.
 	sort.Sort(backupFileDescriptors(desc.Files))
 
 	descBuf, err := desc.Marshal()
@@ -461,6 +549,12 @@ func Backup(
 	return desc, nil
 }
 
+// resumeBackup is the JobResumeHook of BACKUP jobs.
+func resumeBackup(ctx context.Context, execCfg *sql.ExecutorConfig, job *sql.JobLogger) error {
+	_, err := runBackup(ctx, execCfg, job)
+	return err
+}
+
 func backupPlanHook(
 	baseCtx context.Context, stmt parser.Statement, p sql.PlanHookState,
 ) (func() ([]parser.Datums, error), sqlbase.ResultColumns, error) {
@@ -523,7 +617,7 @@ func backupPlanHook(
 		if err != nil {
 			return nil, err
 		}
-		jobLogger := sql.NewJobLogger(p.ExecCfg().DB, p.LeaseMgr(), sql.JobRecord{
+		jobLogger := p.ExecCfg().JobRegistry.NewJobLogger(sql.JobRecord{
 			Description: description,
 			Username:    p.User(),
 			Details:     sql.BackupJobDetails{},
@@ -561,4 +655,5 @@ func backupPlanHook(
 
 func init() {
 	sql.AddPlanHook(backupPlanHook)
+	sql.AddJobResumeHook(sql.JobTypeBackup, resumeBackup)
 }
@@ -46,6 +46,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util"
 	"github.com/cockroachdb/cockroach/pkg/util/encoding"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
 	"github.com/cockroachdb/cockroach/pkg/util/randutil"
 	"github.com/cockroachdb/cockroach/pkg/util/stop"
 )
@@ -523,6 +524,193 @@ func TestBackupRestoreSystemJobs(t *testing.T) {
 	}
 }
 
+// TestRestoreJobPauseResumeCancel checks that a running RESTORE stops issuing
+// Import requests while its job is paused, makes only the remaining requests
+// once it is resumed, and stops and ends canceled if it is canceled.
+func TestRestoreJobPauseResumeCancel(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	// Check the job's status, and record its progress, after every request.
+	defer func(old time.Duration) { progressTimeThreshold = old }(progressTimeThreshold)
+	progressTimeThreshold = 0
+
+	// While blockImports is set, every Import response is announced on
+	// importStarted and then held until the test sends on allowImport. All
+	// Import responses are counted in importCount.
+	var blockImports int32
+	var importCount int64
+	importStarted := make(chan struct{})
+	allowImport := make(chan struct{})
+	params := base.TestClusterArgs{}
+	params.ServerArgs.Knobs.Store = &storage.StoreTestingKnobs{
+		TestingResponseFilter: func(ba roachpb.BatchRequest, br *roachpb.BatchResponse) *roachpb.Error {
+			for _, res := range br.Responses {
+				if res.Import == nil {
+					continue
+				}
+				atomic.AddInt64(&importCount, 1)
+				if atomic.LoadInt32(&blockImports) == 1 {
+					importStarted <- struct{}{}
+					<-allowImport
+				}
+			}
+			return nil
+		},
+	}
+
+	// A single node restores one range at a time, so at most one Import
+	// request is in flight.
+	const numAccounts = 1000
+	_, dir, _, sqlDB, cleanupFn := backupRestoreTestSetupWithParams(t, singleNode, numAccounts, params)
+	defer cleanupFn()
+
+	sqlDB.Exec(`BACKUP DATABASE bench TO $1`, dir)
+
+	// Restore the backup once without interruption, to learn how many Import
+	// requests a restore of it makes.
+	sqlDB.Exec(`CREATE DATABASE baseline`)
+	sqlDB.Exec(`RESTORE bench.* FROM $1 WITH OPTIONS ('into_db'='baseline')`, dir)
+	totalImports := atomic.LoadInt64(&importCount)
+	if totalImports < 4 {
+		t.Fatalf("expected at least 4 Import requests, got %d", totalImports)
+	}
+
+	// startRestore starts a RESTORE into a new database and returns the ID of
+	// its job once its first Import response is held by the filter.
+	startRestore := func(db string) (int64, chan error) {
+		sqlDB.Exec(fmt.Sprintf(`CREATE DATABASE %s`, db))
+		atomic.StoreInt64(&importCount, 0)
+		atomic.StoreInt32(&blockImports, 1)
+		jobDone := make(chan error, 1)
+		go func() {
+			_, err := sqlDB.DB.Exec(
+				fmt.Sprintf(`RESTORE bench.* FROM $1 WITH OPTIONS ('into_db'='%s')`, db), dir,
+			)
+			jobDone <- err
+		}()
+		<-importStarted
+		var jobID int64
+		sqlDB.QueryRow(
+			`SELECT id FROM crdb_internal.jobs WHERE type = 'RESTORE' AND status = 'running'`,
+		).Scan(&jobID)
+		return jobID, jobDone
+	}
+
+	// allowImportsFor lets every Import request which is made in the next d
+	// finish, and returns how many were.
+	allowImportsFor := func(d time.Duration) int {
+		var n int
+		deadline := time.After(d)
+		for {
+			select {
+			case <-importStarted:
+				allowImport <- struct{}{}
+				n++
+			case <-deadline:
+				return n
+			}
+		}
+	}
+
+	// waitForJob lets the remaining Import requests finish and returns the
+	// result of the RESTORE.
+	waitForJob := func(jobDone chan error) error {
+		defer atomic.StoreInt32(&blockImports, 0)
+		for {
+			select {
+			case <-importStarted:
+				allowImport <- struct{}{}
+			case err := <-jobDone:
+				return err
+			}
+		}
+	}
+
+	t.Run("pause and resume", func(t *testing.T) {
+		jobID, jobDone := startRestore("bench_paused")
+		allowImport <- struct{}{}
+		<-importStarted
+		sqlDB.Exec(`PAUSE JOB $1`, jobID)
+
+		// The request which was in flight when the job was paused finishes,
+		// and so may the next one, if it was made before the job noticed that
+		// it was paused. No request is made after that, even though the job
+		// checks its status several times.
+		allowImport <- struct{}{}
+		if n := allowImportsFor(3 * time.Second); n > 1 {
+			t.Fatalf("expected at most 1 Import request after pausing the job, got %d", n)
+		}
+		completed := atomic.LoadInt64(&importCount)
+
+		var status string
+		var fraction float32
+		var payloadBytes []byte
+		sqlDB.QueryRow(
+			`SELECT status, fraction_completed FROM crdb_internal.jobs WHERE id = $1`, jobID,
+		).Scan(&status, &fraction)
+		sqlDB.QueryRow(`SELECT payload FROM system.jobs WHERE id = $1`, jobID).Scan(&payloadBytes)
+		if e := sql.JobStatusPaused; sql.JobStatus(status) != e {
+			t.Fatalf("expected status %s, got %s", e, status)
+		}
+		if e, a := completed, int64(fraction*float32(totalImports)+0.5); e != a {
+			t.Fatalf("expected progress of %d/%d Import requests, got %f", e, totalImports, fraction)
+		}
+		var payload sql.JobPayload
+		if err := protoutil.Unmarshal(payloadBytes, &payload); err != nil {
+			t.Fatal(err)
+		}
+		if len(payload.GetRestore().HighWater) == 0 {
+			t.Fatal("expected the paused job to have recorded a checkpoint")
+		}
+
+		// Once resumed, the job makes only the requests it had not made
+		// before it was paused.
+		sqlDB.Exec(`RESUME JOB $1`, jobID)
+		if err := waitForJob(jobDone); err != nil {
+			t.Fatal(err)
+		}
+		if a := atomic.LoadInt64(&importCount); a != totalImports {
+			t.Fatalf("expected %d Import requests in total, got %d", totalImports, a)
+		}
+
+		sqlDB.QueryRow(`SELECT status FROM crdb_internal.jobs WHERE id = $1`, jobID).Scan(&status)
+		if e := sql.JobStatusSucceeded; sql.JobStatus(status) != e {
+			t.Fatalf("expected status %s, got %s", e, status)
+		}
+		var rowCount int
+		sqlDB.QueryRow(`SELECT COUNT(*) FROM bench_paused.bank`).Scan(&rowCount)
+		if rowCount != numAccounts {
+			t.Fatalf("expected %d rows, got %d", numAccounts, rowCount)
+		}
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		jobID, jobDone := startRestore("bench_canceled")
+		sqlDB.Exec(`CANCEL JOB $1`, jobID)
+		if err := waitForJob(jobDone); !testutils.IsError(
+			err, fmt.Sprintf("job %d was canceled", jobID),
+		) {
+			t.Fatalf("expected the RESTORE to be canceled, got %v", err)
+		}
+		if a := atomic.LoadInt64(&importCount); a >= totalImports {
+			t.Fatalf("expected fewer than %d Import requests, got %d", totalImports, a)
+		}
+
+		var status string
+		sqlDB.QueryRow(`SELECT status FROM crdb_internal.jobs WHERE id = $1`, jobID).Scan(&status)
+		if e := sql.JobStatusCanceled; sql.JobStatus(status) != e {
+			t.Fatalf("expected status %s, got %s", e, status)
+		}
+		var tableCount int
+		sqlDB.QueryRow(
+			`SELECT COUNT(*) FROM information_schema.tables WHERE table_schema = 'bench_canceled'`,
+		).Scan(&tableCount)
+		if tableCount != 0 {
+			t.Fatalf("expected no restored tables, got %d", tableCount)
+		}
+	})
+}
+
 func TestBackupRestoreInterleaved(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	const numAccounts = 10
@@ -0,0 +1,680 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/LICENSE
+
+package sqlccl
+
+import (
+	"bytes"
+	"encoding/csv"
+	"io"
+	"io/ioutil"
+	"os"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/storageccl"
+	"github.com/cockroachdb/cockroach/pkg/config"
+	"github.com/cockroachdb/cockroach/pkg/internal/client"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/distsqlrun"
+	"github.com/cockroachdb/cockroach/pkg/sql/mon"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/storage/engine"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+	"github.com/cockroachdb/cockroach/pkg/util/tracing"
+)
+
+const (
+	importOptionDelimiter = "delimiter"
+	importOptionComment   = "comment"
+	importOptionNullIf    = "nullif"
+	importOptionTemp      = "temp"
+
+	// defaultCSVTableID is the ID with which the KVs of an imported table are
+	// written to the temporary SSTables. They are rewritten to the ID the
+	// table is assigned when they are ingested, as in RESTORE.
+	defaultCSVTableID sqlbase.ID = 51
+)
+
+// csvOptions holds the options of an IMPORT of CSV files.
+type csvOptions struct {
+	comma   rune
+	comment rune
+	// nullif, if non-nil, is the string that is imported as NULL.
+	nullif *string
+}
+
+func parseCSVOptions(opts parser.KVOptions) (csvOptions, error) {
+	csvOpts := csvOptions{comma: ','}
+	singleRune := func(name string) (rune, error) {
+		s, _ := opts.Get(name)
+		if utf8.RuneCountInString(s) != 1 {
+			return 0, errors.Errorf("option %q must be a single character", name)
+		}
+		r, _ := utf8.DecodeRuneInString(s)
+		return r, nil
+	}
+	var err error
+	if _, ok := opts.Get(importOptionDelimiter); ok {
+		if csvOpts.comma, err = singleRune(importOptionDelimiter); err != nil {
+			return csvOptions{}, err
+		}
+	}
+	if _, ok := opts.Get(importOptionComment); ok {
+		if csvOpts.comment, err = singleRune(importOptionComment); err != nil {
+			return csvOptions{}, err
+		}
+	}
+	if nullif, ok := opts.Get(importOptionNullIf); ok {
+		csvOpts.nullif = &nullif
+	}
+	return csvOpts, nil
+}
+
+func importJobDescription(
+	importStmt *parser.Import, files []string, opts parser.KVOptions,
+) (string, error) {
+	stmt := *importStmt
+	stmt.Files = make(parser.Exprs, len(files))
+	for i, f := range files {
+		sf, err := storageccl.SanitizeExportStorageURI(f)
+		if err != nil {
+			return "", err
+		}
+		stmt.Files[i] = parser.NewDString(sf)
+	}
+	stmt.Options = make(parser.KVOptions, len(opts))
+	for i, opt := range opts {
+		stmt.Options[i] = opt
+		if opt.Key == importOptionTemp {
+			sf, err := storageccl.SanitizeExportStorageURI(opt.Value)
+			if err != nil {
+				return "", err
+			}
+			stmt.Options[i].Value = sf
+		}
+	}
+	return stmt.String(), nil
+}
+
+// makeCSVTableDescriptor creates the descriptor of the table created by an
+// IMPORT in the given database. The table is given defaultCSVTableID.
+func makeCSVTableDescriptor(
+	ctx context.Context,
+	importStmt *parser.Import,
+	parentDB *sqlbase.DatabaseDescriptor,
+	evalCtx *parser.EvalContext,
+) (*sqlbase.TableDescriptor, error) {
+	// Foreign keys would have to be validated against the existing data of
+	// the referenced tables, and CHECK constraints against the imported rows.
+	for _, def := range importStmt.CreateDefs {
+		switch def := def.(type) {
+		case *parser.ForeignKeyConstraintTableDef:
+			return nil, errors.Errorf("foreign keys are not supported by IMPORT: %s", parser.AsString(def))
+		case *parser.CheckConstraintTableDef:
+			return nil, errors.Errorf("CHECK constraints are not supported by IMPORT: %s", parser.AsString(def))
+		case *parser.ColumnTableDef:
+			if def.HasFKConstraint() {
+				return nil, errors.Errorf("foreign keys are not supported by IMPORT: %s", parser.AsString(def))
+			}
+			if len(def.CheckExprs) > 0 {
+				return nil, errors.Errorf("CHECK constraints are not supported by IMPORT: %s", parser.AsString(def))
+			}
+		}
+	}
+	create := &parser.CreateTable{
+		Table: importStmt.Table,
+		Defs:  importStmt.CreateDefs,
+	}
+	affected := make(map[sqlbase.ID]*sqlbase.TableDescriptor)
+	// A nil txn is safe because it is only used by sql.MakeTableDesc to resolve
+	// FKs and interleaved tables, neither of which are allowed here.
+	desc, err := sql.MakeTableDesc(
+		ctx, nil /* txn */, sql.NilVirtualTabler, nil /* searchPath */, create, parentDB.ID,
+		defaultCSVTableID, parentDB.GetPrivileges(), affected, parentDB.Name, evalCtx,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &desc, nil
+}
+
+// importConversionFraction is the fraction of an IMPORT job that is complete
+// once its CSV files have been converted. The restore of the converted files
+// is the rest of the job.
+const importConversionFraction = 0.5
+
+// convertCSVToSSTs reads the rows of the given CSV files, converts them into
+// the KVs of tableDesc and writes them, sorted, as SSTables in the export
+// storage at tempURI, along with a BackupDescriptor of them. The returned
+// BackupDescriptor can then be restored, like a backup, to ingest the table.
+//
+// The KVs are sorted in tempStorage, under an account of diskMonitor, so only
+// the KVs of one SSTable are held in memory at a time. progressFn is called
+// with the fraction of the conversion that is complete after each file.
+func convertCSVToSSTs(
+	ctx context.Context,
+	files []string,
+	tableDesc *sqlbase.TableDescriptor,
+	tempURI string,
+	opts csvOptions,
+	evalCtx parser.EvalContext,
+	ts hlc.Timestamp,
+	tempStorage engine.Engine,
+	diskMonitor *mon.MemoryMonitor,
+	progressFn func(float32) error,
+) (BackupDescriptor, error) {
+	// The CSV files hold the visible columns; the hidden ones (e.g. the rowid
+	// column added to tables without a primary key) get their default values.
+	var visibleCols []sqlbase.ColumnDescriptor
+	for _, col := range tableDesc.Columns {
+		if !col.Hidden {
+			visibleCols = append(visibleCols, col)
+		}
+	}
+	var parse parser.Parser
+	cols, defaultExprs, err := sql.ProcessDefaultColumns(visibleCols, tableDesc, &parse, &evalCtx)
+	if err != nil {
+		return BackupDescriptor{}, errors.Wrap(err, "process default columns")
+	}
+	ri, err := sqlbase.MakeRowInserter(nil /* txn */, tableDesc, nil /* fkTables */, cols, true /* checkFKs */)
+	if err != nil {
+		return BackupDescriptor{}, errors.Wrap(err, "make row inserter")
+	}
+
+	sorter := makeKVSorter(tempStorage, diskMonitor)
+	defer sorter.Close(ctx)
+	var sortErr error
+	b := inserter(func(kv roachpb.KeyValue) {
+		if sortErr == nil {
+			sortErr = sorter.Add(ctx, kv)
+		}
+	})
+	for i, file := range files {
+		if err := readCSVFile(ctx, file, opts, len(visibleCols), func(record []string) error {
+			row := make(parser.Datums, len(record))
+			for i, field := range record {
+				if opts.nullif != nil && field == *opts.nullif {
+					row[i] = parser.DNull
+					continue
+				}
+				var err error
+				row[i], err = parser.ParseStringAs(
+					visibleCols[i].Type.ToDatumType(), field, evalCtx.GetLocation())
+				if err != nil {
+					return errors.Wrapf(err, "column %q", visibleCols[i].Name)
+				}
+			}
+			row, err := sql.GenerateInsertRow(
+				defaultExprs, ri.InsertColIDtoRowIndex, cols, evalCtx, tableDesc, row)
+			if err != nil {
+				return err
+			}
+			if err := ri.InsertRow(ctx, b, row, true /* ignoreConflicts */); err != nil {
+				return err
+			}
+			return sortErr
+		}); err != nil {
+			return BackupDescriptor{}, errors.Wrapf(err, "reading %s", file)
+		}
+		// Writing the SSTables is counted as one more file.
+		if err := progressFn(float32(i+1) / float32(len(files)+1)); err != nil {
+			return BackupDescriptor{}, err
+		}
+	}
+
+	dir, err := exportStorageFromURI(ctx, tempURI)
+	if err != nil {
+		return BackupDescriptor{}, errors.Wrap(err, "export storage from URI")
+	}
+	defer dir.Close()
+	// SSTables are written to a local file before they're uploaded to
+	// non-local storage.
+	tempPrefix, err := ioutil.TempDir("", "import")
+	if err != nil {
+		return BackupDescriptor{}, err
+	}
+	defer func() {
+		if err := os.RemoveAll(tempPrefix); err != nil {
+			log.Warningf(ctx, "could not remove temporary directory %s: %s", tempPrefix, err)
+		}
+	}()
+
+	backupDesc := BackupDescriptor{
+		EndTime: ts,
+		Spans:   spansForAllTableIndexes([]*sqlbase.TableDescriptor{tableDesc}),
+		Descriptors: []sqlbase.Descriptor{
+			{Union: &sqlbase.Descriptor_Table{Table: tableDesc}},
+		},
+		FormatVersion: BackupFormatInitialVersion,
+	}
+	// Each SSTable becomes (at least) one range when it's ingested.
+	sstMaxBytes := config.DefaultZoneConfig().RangeMaxBytes / 2
+	if err := sorter.Chunks(sstMaxBytes, func(kvs []engine.MVCCKeyValue) error {
+		return errors.Wrap(writeSST(ctx, &backupDesc, dir, tempPrefix, kvs, ts), "writeSST")
+	}); err != nil {
+		return BackupDescriptor{}, err
+	}
+
+	descBuf, err := backupDesc.Marshal()
+	if err != nil {
+		return BackupDescriptor{}, errors.Wrap(err, "marshal backup descriptor")
+	}
+	if err := dir.WriteFile(ctx, BackupDescriptorName, bytes.NewReader(descBuf)); err != nil {
+		return BackupDescriptor{}, errors.Wrap(err, "uploading backup descriptor")
+	}
+	if err := progressFn(1); err != nil {
+		return BackupDescriptor{}, err
+	}
+	backupDesc.Dir = dir.Conf()
+	return backupDesc, nil
+}
+
+// kvSorterBatchSize is the number of bytes buffered by a kvSorter before they
+// are written to the temporary storage engine.
+const kvSorterBatchSize = 256 << 10 // 256 KiB
+
+// kvSorter sorts the KVs of an IMPORT in the node's temporary storage engine,
+// so that the size of an import is not limited by memory.
+//
+// Each KV is stored under a key made up of the sorter's unique prefix, the
+// encoding of the KV's key and a sequence number (so that KVs with the same
+// key are kept, and reported as duplicates by Chunks). The value is the KV's
+// value.
+type kvSorter struct {
+	engine engine.Engine
+	batch  engine.Batch
+	prefix roachpb.Key
+
+	// len is the number of KVs in the sorter. It is also used as the sequence
+	// number of the next KV.
+	len int
+	// batchBytes is the number of bytes buffered in batch.
+	batchBytes int
+	// diskAcc accounts for the temporary storage used by the sorter.
+	diskAcc mon.BoundAccount
+
+	scratchKey []byte
+}
+
+func makeKVSorter(tempStorage engine.Engine, diskMonitor *mon.MemoryMonitor) kvSorter {
+	return kvSorter{
+		engine:  tempStorage,
+		batch:   tempStorage.NewWriteOnlyBatch(),
+		prefix:  distsqlrun.NewTempStoragePrefix(),
+		diskAcc: diskMonitor.MakeBoundAccount(),
+	}
+}
+
+// Add adds a KV to the sorter.
+func (s *kvSorter) Add(ctx context.Context, kv roachpb.KeyValue) error {
+	key := append(s.scratchKey[:0], s.prefix...)
+	key = encoding.EncodeBytesAscending(key, kv.Key)
+	key = encoding.EncodeUvarintAscending(key, uint64(s.len))
+	s.scratchKey = key
+	val := kv.Value.RawBytes
+
+	if err := s.diskAcc.Grow(ctx, int64(len(key)+len(val))); err != nil {
+		return pgerror.NewErrorf(pgerror.CodeDiskFullError,
+			"temporary storage budget exceeded: %v", err)
+	}
+	if err := s.batch.Put(engine.MVCCKey{Key: key}, val); err != nil {
+		return err
+	}
+	s.len++
+	s.batchBytes += len(key) + len(val)
+	if s.batchBytes >= kvSorterBatchSize {
+		return s.flush()
+	}
+	return nil
+}
+
+// flush writes the buffered KVs to the engine.
+func (s *kvSorter) flush() error {
+	if s.batchBytes == 0 {
+		return nil
+	}
+	err := s.batch.Commit(false /* sync */)
+	s.batch.Close()
+	s.batch = s.engine.NewWriteOnlyBatch()
+	s.batchBytes = 0
+	return err
+}
+
+// Chunks calls fn with the KVs of the sorter, sorted by key, in chunks of at
+// least chunkBytes bytes (except for the last one). It returns an error if two
+// KVs have the same key.
+func (s *kvSorter) Chunks(chunkBytes int64, fn func([]engine.MVCCKeyValue) error) error {
+	if err := s.flush(); err != nil {
+		return err
+	}
+	iter := s.engine.NewIterator(false /* prefix */)
+	defer iter.Close()
+
+	var chunk []engine.MVCCKeyValue
+	var size int64
+	var prevKey roachpb.Key
+	for iter.Seek(engine.MVCCKey{Key: s.prefix}); ; iter.Next() {
+		if ok, err := iter.Valid(); err != nil {
+			return err
+		} else if !ok || !bytes.HasPrefix(iter.UnsafeKey().Key, s.prefix) {
+			break
+		}
+		_, key, err := encoding.DecodeBytesAscending(iter.UnsafeKey().Key[len(s.prefix):], nil)
+		if err != nil {
+			return err
+		}
+		if prevKey.Equal(key) {
+			return errors.Errorf("duplicate key: %s", roachpb.Key(key))
+		}
+		prevKey = key
+		val := iter.Value()
+		chunk = append(chunk, engine.MVCCKeyValue{Key: engine.MVCCKey{Key: key}, Value: val})
+		size += int64(len(key) + len(val))
+		if size >= chunkBytes {
+			if err := fn(chunk); err != nil {
+				return err
+			}
+			chunk, size = nil, 0
+		}
+	}
+	if len(chunk) > 0 {
+		return fn(chunk)
+	}
+	return nil
+}
+
+// Close removes the KVs of the sorter from the engine and releases the
+// sorter's resources.
+func (s *kvSorter) Close(ctx context.Context) {
+	s.batch.Close()
+	if s.len > 0 {
+		if err := s.engine.ClearRange(
+			engine.MVCCKey{Key: s.prefix}, engine.MVCCKey{Key: s.prefix.PrefixEnd()},
+		); err != nil {
+			log.Warningf(ctx, "could not clear temporary storage: %s", err)
+		}
+	}
+	s.diskAcc.Close(ctx)
+}
+
+// readCSVFile calls fn with each record of the CSV file at the given URI.
+func readCSVFile(
+	ctx context.Context, uri string, opts csvOptions, numFields int, fn func([]string) error,
+) error {
+	conf, err := storageccl.ExportStorageConfFromURI(uri)
+	if err != nil {
+		return err
+	}
+	// The URI names the file, so the ExportStorage is made for its directory.
+	var basename string
+	switch conf.Provider {
+	case roachpb.ExportStorageProvider_LocalFile:
+		conf.LocalFile.Path, basename = splitExportStoragePath(conf.LocalFile.Path)
+	case roachpb.ExportStorageProvider_Http:
+		conf.HttpPath.BaseUri, basename = splitExportStoragePath(conf.HttpPath.BaseUri)
+	case roachpb.ExportStorageProvider_S3:
+		conf.S3Config.Prefix, basename = splitExportStoragePath(conf.S3Config.Prefix)
+	case roachpb.ExportStorageProvider_GoogleCloud:
+		conf.GoogleCloudConfig.Prefix, basename = splitExportStoragePath(conf.GoogleCloudConfig.Prefix)
+	case roachpb.ExportStorageProvider_Azure:
+		conf.AzureConfig.Prefix, basename = splitExportStoragePath(conf.AzureConfig.Prefix)
+	default:
+		return errors.Errorf("unsupported storage provider %s for %s", conf.Provider, uri)
+	}
+	dir, err := storageccl.MakeExportStorage(ctx, conf)
+	if err != nil {
+		return err
+	}
+	defer dir.Close()
+	f, err := dir.ReadFile(ctx, basename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	cr := csv.NewReader(f)
+	cr.Comma = opts.comma
+	cr.Comment = opts.comment
+	cr.FieldsPerRecord = numFields
+	for i := 1; ; i++ {
+		record, err := cr.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := fn(record); err != nil {
+			return errors.Wrapf(err, "row %d", i)
+		}
+	}
+}
+
+// splitExportStoragePath splits the path of a file in an ExportStorage into
+// the path of its directory and its basename.
+func splitExportStoragePath(path string) (dir, basename string) {
+	i := len(path) - 1
+	for i >= 0 && path[i] != '/' {
+		i--
+	}
+	return path[:i+1], path[i+1:]
+}
+
+// doImport creates a table like tableDesc, whose ID is defaultCSVTableID, in
+// parentDB and imports the given CSV files into it: they are converted into a
+// backup at tempURI, which is then restored. Both phases are tracked by
+// jobLogger.
+func doImport(
+	ctx context.Context,
+	p sql.PlanHookState,
+	parentDB *sqlbase.DatabaseDescriptor,
+	files []string,
+	tempURI string,
+	tableDesc *sqlbase.TableDescriptor,
+	opts csvOptions,
+	jobLogger *sql.JobLogger,
+) (int64, error) {
+	db := *p.ExecCfg().DB
+	// The IDs of the new table are assigned to a copy, since the converted KVs
+	// are written with tableDesc's IDs.
+	newTableDesc := protoutil.Clone(tableDesc).(*sqlbase.TableDescriptor)
+	tables := []*sqlbase.TableDescriptor{newTableDesc}
+
+	if err := db.Txn(ctx, func(ctx context.Context, txn *client.Txn) error {
+		databasesByID := map[sqlbase.ID]*sqlbase.DatabaseDescriptor{parentDB.ID: parentDB}
+		return reassignParentIDs(ctx, txn, p, databasesByID, tables, nil /* opt */)
+	}); err != nil {
+		return 0, err
+	}
+	newTableIDs, rekeys, err := reassignTableIDs(ctx, db, tables, nil /* opt */)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, id := range newTableIDs {
+		jobLogger.Job.DescriptorIDs = append(jobLogger.Job.DescriptorIDs, id)
+	}
+	details := sql.ImportJobDetails{
+		Files:   files,
+		TempURI: tempURI,
+		Restore: sql.RestoreJobDetails{URIs: []string{tempURI}, TableRekeys: rekeys},
+	}
+	jobLogger.Job.Details = details
+	if err := jobLogger.Created(ctx); err != nil {
+		return 0, err
+	}
+	if err := jobLogger.Started(ctx); err != nil {
+		return 0, err
+	}
+
+	distSQLSrv := p.ExecCfg().DistSQLSrv
+	backupDesc, err := convertCSVToSSTs(
+		ctx, files, tableDesc, tempURI, opts, *p.EvalContext(), p.ExecCfg().Clock.Now(),
+		distSQLSrv.TempStorage, distSQLSrv.DiskMonitor,
+		func(fraction float32) error {
+			return jobLogger.Progressed(ctx, importConversionFraction*fraction)
+		},
+	)
+	if err != nil {
+		return 0, err
+	}
+	// The restore of the converted files is resumed like a RESTORE if this
+	// node fails.
+	details.Converted = true
+	if err := jobLogger.ProgressedWithDetails(ctx, importConversionFraction, details); err != nil {
+		return 0, err
+	}
+	jobLogger.Job.Details = details
+	return runRestore(ctx, p.ExecCfg(), []BackupDescriptor{backupDesc}, jobLogger)
+}
+
+// resumeImport is the JobResumeHook of IMPORT jobs. The conversion of the CSV
+// files is not checkpointed, so only jobs that had converted them, and were
+// restoring the converted files, can be resumed.
+func resumeImport(ctx context.Context, execCfg *sql.ExecutorConfig, job *sql.JobLogger) error {
+	details, ok := job.Job.Details.(sql.ImportJobDetails)
+	if !ok {
+		return errors.Errorf("unexpected details type %T for an IMPORT job", job.Job.Details)
+	}
+	if !details.Converted {
+		return errors.New("the conversion of the CSV files of an IMPORT cannot be resumed")
+	}
+	backupDescs, err := loadBackupDescs(ctx, details.Restore.URIs)
+	if err != nil {
+		return err
+	}
+	_, err = runRestore(ctx, execCfg, backupDescs, job)
+	return err
+}
+
+func importPlanHook(
+	baseCtx context.Context, stmt parser.Statement, p sql.PlanHookState,
+) (func() ([]parser.Datums, error), sqlbase.ResultColumns, error) {
+	importStmt, ok := stmt.(*parser.Import)
+	if !ok {
+		return nil, nil, nil
+	}
+
+	if err := p.RequireSuperUser("IMPORT"); err != nil {
+		return nil, nil, err
+	}
+
+	filesFn, err := p.TypeAsStringArray(importStmt.Files, "IMPORT")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	header := sqlbase.ResultColumns{
+		{Name: "job_id", Typ: parser.TypeInt},
+		{Name: "status", Typ: parser.TypeString},
+		{Name: "fraction_completed", Typ: parser.TypeFloat},
+		{Name: "bytes", Typ: parser.TypeInt},
+	}
+	fn := func() ([]parser.Datums, error) {
+		// TODO(dan): Move this span into sql.
+		ctx, span := tracing.ChildSpan(baseCtx, stmt.StatementTag())
+		defer tracing.FinishSpan(span)
+
+		files, err := filesFn()
+		if err != nil {
+			return nil, err
+		}
+		csvOpts, err := parseCSVOptions(importStmt.Options)
+		if err != nil {
+			return nil, err
+		}
+		temp, ok := importStmt.Options.Get(importOptionTemp)
+		if !ok {
+			return nil, errors.Errorf("must provide a temporary storage location with the %q option",
+				importOptionTemp)
+		}
+
+		evalCtx := p.EvalContext()
+		tn, err := importStmt.Table.NormalizeWithDatabaseName(evalCtx.Database)
+		if err != nil {
+			return nil, err
+		}
+		// Fail fast, before the files are converted, if the table cannot be
+		// created.
+		var parentDB *sqlbase.DatabaseDescriptor
+		if err := p.ExecCfg().DB.Txn(ctx, func(ctx context.Context, txn *client.Txn) error {
+			dbID, err := txn.Get(ctx, sqlbase.MakeNameMetadataKey(0, tn.Database()))
+			if err != nil {
+				return err
+			}
+			if !dbID.Exists() {
+				return sqlbase.NewUndefinedDatabaseError(tn.Database())
+			}
+			parentDB, err = sqlbase.GetDatabaseDescFromID(ctx, txn, sqlbase.ID(dbID.ValueInt()))
+			if err != nil {
+				return err
+			}
+			if err := p.CheckPrivilege(ctx, parentDB, privilege.CREATE); err != nil {
+				return err
+			}
+			res, err := txn.Get(ctx, sqlbase.MakeNameMetadataKey(parentDB.ID, tn.Table()))
+			if err != nil {
+				return err
+			}
+			if res.Exists() {
+				return sqlbase.NewRelationAlreadyExistsError(tn.Table())
+			}
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+
+		tableDesc, err := makeCSVTableDescriptor(ctx, importStmt, parentDB, evalCtx)
+		if err != nil {
+			return nil, err
+		}
+
+		description, err := importJobDescription(importStmt, files, importStmt.Options)
+		if err != nil {
+			return nil, err
+		}
+		jobLogger := p.ExecCfg().JobRegistry.NewJobLogger(sql.JobRecord{
+			Description: description,
+			Username:    p.User(),
+			Details:     sql.ImportJobDetails{},
+		})
+		dataSize, err := doImport(ctx, p, parentDB, files, temp, tableDesc, csvOpts, &jobLogger)
+		if err != nil {
+			jobLogger.Failed(ctx, err)
+			return nil, err
+		}
+		if err := jobLogger.Succeeded(ctx); err != nil {
+			// An error while marking the job as successful is not important enough to
+			// merit failing the entire import.
+			log.Errorf(ctx, "IMPORT ignoring error while marking job %d (%s) as successful: %+v",
+				jobLogger.JobID(), description, err)
+		}
+		return []parser.Datums{{
+			parser.NewDInt(parser.DInt(*jobLogger.JobID())),
+			parser.NewDString(string(sql.JobStatusSucceeded)),
+			parser.NewDFloat(parser.DFloat(1.0)),
+			parser.NewDInt(parser.DInt(dataSize)),
+		}}, nil
+	}
+	return fn, header, nil
+}
+
+func init() {
+	sql.AddPlanHook(importPlanHook)
+	sql.AddJobResumeHook(sql.JobTypeImport, resumeImport)
+}
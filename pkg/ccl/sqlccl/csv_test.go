@@ -0,0 +1,156 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/LICENSE
+
+package sqlccl
+
+import (
+	"fmt"
+	"io/ioutil"
+	"math"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/context"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/mon"
+	"github.com/cockroachdb/cockroach/pkg/storage/engine"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+)
+
+func TestImportCSV(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	_, dir, _, sqlDB, cleanupFn := backupRestoreTestSetup(t, singleNode, 0)
+	defer cleanupFn()
+	rawDir := strings.TrimPrefix(dir, "nodelocal://")
+
+	files := map[string]string{
+		"a.csv": "# id|name|balance\n1|one|10\n3|three|\n",
+		"b.csv": "2|two|20\n# comment\n4||40\n",
+		"c.csv": "5|five|50\n1|dup|10\n",
+	}
+	for name, data := range files {
+		if err := ioutil.WriteFile(filepath.Join(rawDir, name), []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	importStmt := func(table, temp string) string {
+		return fmt.Sprintf(`IMPORT TABLE bench.%s (id INT PRIMARY KEY, name STRING, balance INT, INDEX (name))
+			CSV DATA ($1, $2)
+			WITH OPTIONS ('delimiter'='|', 'comment'='#', 'nullif'='', 'temp'='%s')`, table, temp)
+	}
+	sqlDB.Exec(importStmt("t", dir+"/temp-t"), dir+"/a.csv", dir+"/b.csv")
+
+	sqlDB.CheckQueryResults(`SELECT * FROM bench.t ORDER BY id`, [][]string{
+		{"1", "one", "10"},
+		{"2", "two", "20"},
+		{"3", "three", "NULL"},
+		{"4", "NULL", "40"},
+	})
+	sqlDB.CheckQueryResults(`SELECT id FROM bench.t@t_name_idx WHERE name > 'p'`, [][]string{
+		{"3"},
+		{"2"},
+	})
+	sqlDB.CheckQueryResults(
+		`SELECT type, status, fraction_completed FROM crdb_internal.jobs
+			WHERE description LIKE 'IMPORT TABLE bench.t %'`,
+		[][]string{{"IMPORT", "succeeded", "1"}},
+	)
+
+	if _, err := sqlDB.DB.Exec(importStmt("t", dir+"/temp-t2"),
+		dir+"/a.csv", dir+"/b.csv"); !testutils.IsError(err, `relation "t" already exists`) {
+		t.Fatalf("expected already exists error, got: %v", err)
+	}
+	if _, err := sqlDB.DB.Exec(importStmt("u", dir+"/temp-u"),
+		dir+"/a.csv", dir+"/c.csv"); !testutils.IsError(err, "duplicate key") {
+		t.Fatalf("expected duplicate key error, got: %v", err)
+	}
+	sqlDB.CheckQueryResults(
+		`SELECT type, status FROM crdb_internal.jobs WHERE description LIKE 'IMPORT TABLE bench.u %'`,
+		[][]string{{"IMPORT", "failed"}},
+	)
+	if _, err := sqlDB.DB.Exec(`IMPORT TABLE bench.v (id INT) CSV DATA ($1)`,
+		dir+"/a.csv"); !testutils.IsError(err, `temporary storage location`) {
+		t.Fatalf("expected missing temp option error, got: %v", err)
+	}
+}
+
+func TestKVSorter(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	tempEngine := engine.NewInMem(roachpb.Attributes{}, 1<<20)
+	defer tempEngine.Close()
+	diskMonitor := mon.MakeUnlimitedMonitor(ctx, "test-disk", nil, nil, math.MaxInt64)
+	defer diskMonitor.Stop(ctx)
+
+	// Another user of the temporary storage engine, whose KVs must not be
+	// returned by the sorter.
+	other := makeKVSorter(tempEngine, &diskMonitor)
+	defer other.Close(ctx)
+	otherKV := roachpb.KeyValue{Key: roachpb.Key("b"), Value: roachpb.MakeValueFromString("other")}
+	if err := other.Add(ctx, otherKV); err != nil {
+		t.Fatal(err)
+	}
+	if err := other.flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	add := func(s *kvSorter, keys ...string) {
+		for _, k := range keys {
+			kv := roachpb.KeyValue{Key: roachpb.Key(k), Value: roachpb.MakeValueFromString(k)}
+			if err := s.Add(ctx, kv); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	t.Run("sorted", func(t *testing.T) {
+		s := makeKVSorter(tempEngine, &diskMonitor)
+		defer s.Close(ctx)
+		add(&s, "d", "b", "e", "a", "c")
+
+		// Each chunk is closed once it holds at least one KV.
+		var chunks [][]string
+		if err := s.Chunks(1, func(kvs []engine.MVCCKeyValue) error {
+			var chunk []string
+			for _, kv := range kvs {
+				v := roachpb.Value{RawBytes: kv.Value}
+				val, err := v.GetBytes()
+				if err != nil {
+					return err
+				}
+				if string(val) != string(kv.Key.Key) {
+					return fmt.Errorf("expected value %q for key %s, got %q", kv.Key.Key, kv.Key.Key, val)
+				}
+				chunk = append(chunk, string(kv.Key.Key))
+			}
+			chunks = append(chunks, chunk)
+			return nil
+		}); err != nil {
+			t.Fatal(err)
+		}
+		if expected := [][]string{{"a"}, {"b"}, {"c"}, {"d"}, {"e"}}; fmt.Sprint(chunks) != fmt.Sprint(expected) {
+			t.Fatalf("expected chunks %v, got %v", expected, chunks)
+		}
+	})
+
+	t.Run("duplicate", func(t *testing.T) {
+		s := makeKVSorter(tempEngine, &diskMonitor)
+		defer s.Close(ctx)
+		add(&s, "b", "a", "b")
+		err := s.Chunks(math.MaxInt64, func([]engine.MVCCKeyValue) error { return nil })
+		if !testutils.IsError(err, "duplicate key") {
+			t.Fatalf("expected duplicate key error, got: %v", err)
+		}
+	})
+}
@@ -24,16 +24,26 @@ import (
 // back, we issue a progress update only if a) it's been a duration of
 // progressTimeThreshold since the last update, or b) the difference between the
 // last logged fractionCompleted and the current fractionCompleted is more than
-// progressFractionThreshold.
-const (
-	progressTimeThreshold     = time.Second
-	progressFractionThreshold = 0.05
-)
+// progressFractionThreshold. The job's status is checked at most once every
+// progressTimeThreshold, too, which tests lower to pause jobs deterministically.
+const progressFractionThreshold = 0.05
+
+var progressTimeThreshold = time.Second
 
 type jobProgressLogger struct {
 	// These fields must be externally initialized.
 	jobLogger   *sql.JobLogger
 	totalChunks int
+	// resumedChunks is the number of chunks that had already been completed
+	// when the job was resumed, if it was.
+	resumedChunks int
+	// startFraction is the fraction of the job that was completed before the
+	// chunks were started, for jobs of which they are only the last phase.
+	startFraction float32
+
+	// checkpointFn, if set, returns the job details to record along with each
+	// progress update, which allows the job to resume from that point later.
+	checkpointFn func() (interface{}, error)
 
 	// The remaining fields are for internal use only.
 	mu struct {
@@ -41,13 +51,15 @@ type jobProgressLogger struct {
 		completedChunks      int
 		lastReportedAt       time.Time
 		lastReportedFraction float32
+		lastCheckedAt        time.Time
 	}
 }
 
 func (jpl *jobProgressLogger) chunkFinished(ctx context.Context) error {
 	jpl.mu.Lock()
 	jpl.mu.completedChunks++
-	fraction := float32(jpl.mu.completedChunks) / float32(jpl.totalChunks)
+	fraction := jpl.startFraction + (1-jpl.startFraction)*
+		float32(jpl.resumedChunks+jpl.mu.completedChunks)/float32(jpl.totalChunks)
 	shouldLogProgress := fraction-jpl.mu.lastReportedFraction > progressFractionThreshold ||
 		jpl.mu.lastReportedAt.Add(progressTimeThreshold).Before(timeutil.Now())
 	if shouldLogProgress {
@@ -57,7 +69,31 @@ func (jpl *jobProgressLogger) chunkFinished(ctx context.Context) error {
 	jpl.mu.Unlock()
 
 	if shouldLogProgress {
-		return jpl.jobLogger.Progressed(ctx, fraction)
+		var details interface{}
+		if jpl.checkpointFn != nil {
+			var err error
+			if details, err = jpl.checkpointFn(); err != nil {
+				return err
+			}
+		}
+		return jpl.jobLogger.ProgressedWithDetails(ctx, fraction, details)
+	}
+	return nil
+}
+
+// waitWhilePaused blocks while the job is paused and returns an error if the
+// job has been canceled. To avoid hammering the system.jobs table, the job's
+// status is read at most once every progressTimeThreshold.
+func (jpl *jobProgressLogger) waitWhilePaused(ctx context.Context) error {
+	jpl.mu.Lock()
+	shouldCheck := jpl.mu.lastCheckedAt.Add(progressTimeThreshold).Before(timeutil.Now())
+	if shouldCheck {
+		jpl.mu.lastCheckedAt = timeutil.Now()
+	}
+	jpl.mu.Unlock()
+
+	if shouldCheck {
+		return jpl.jobLogger.WaitWhilePaused(ctx)
 	}
 	return nil
 }
@@ -160,7 +160,7 @@ func reassignParentIDs(
 				return errors.Wrapf(err, "failed to lookup parent DB %d", table.ParentID)
 			}
 
-			if err := p.CheckPrivilege(parentDB, privilege.CREATE); err != nil {
+			if err := p.CheckPrivilege(ctx, parentDB, privilege.CREATE); err != nil {
 				return err
 			}
 
@@ -178,21 +178,10 @@ func reassignParentIDs(
 // in the restoring cluster, as well as fixing cross-table references to use the
 // new IDs. It returns a slice of TableRekeys which can be used to transform KV
 // data to reflect the ID remapping done in the descriptors.
-//
-// TODO(dan): For backward compatibility, KeyRewriter, which is a subset of the
-// information returned by the TableRekeys, is also returned. Remove this when
-// we can.
 func reassignTableIDs(
 	ctx context.Context, db client.DB, tables []*sqlbase.TableDescriptor, opt parser.KVOptions,
-) (
-	map[sqlbase.ID]sqlbase.ID,
-	*storageccl.KeyRewriter,
-	[]roachpb.ImportRequest_TableRekey,
-	error,
-) {
+) (map[sqlbase.ID]sqlbase.ID, []roachpb.ImportRequest_TableRekey, error) {
 	var newTableIDs map[sqlbase.ID]sqlbase.ID
-	var rekeys []roachpb.ImportRequest_TableRekey
-
 	if err := db.Txn(ctx, func(ctx context.Context, txn *client.Txn) error {
 		newTableIDs = make(map[sqlbase.ID]sqlbase.ID, len(tables))
 		for _, table := range tables {
@@ -201,36 +190,40 @@ func reassignTableIDs(
 				return err
 			}
 			newTableIDs[table.ID] = newTableID
-			oldID := table.ID
-			table.ID = newTableID
-
-			desc := sqlbase.Descriptor{
-				Union: &sqlbase.Descriptor_Table{Table: table},
-			}
-			newDescBytes, err := desc.Marshal()
-			if err != nil {
-				return errors.Wrap(err, "marshalling descriptor")
-			}
-			rekeys = append(rekeys, roachpb.ImportRequest_TableRekey{
-				OldID:   uint32(oldID),
-				NewDesc: newDescBytes,
-			})
 		}
 		return nil
 	}); err != nil {
-		return nil, nil, nil, err
+		return nil, nil, err
+	}
+
+	oldTableIDs := make([]sqlbase.ID, len(tables))
+	for i, table := range tables {
+		oldTableIDs[i] = table.ID
+		table.ID = newTableIDs[table.ID]
 	}
 
 	if err := reassignReferencedTables(tables, newTableIDs, opt); err != nil {
-		return nil, nil, nil, err
+		return nil, nil, err
 	}
 
-	kr, err := storageccl.MakeKeyRewriter(rekeys)
-	if err != nil {
-		return nil, nil, nil, err
+	// The rekeys are also used to resume the restore, so they must hold the
+	// final descriptors, with their references updated.
+	rekeys := make([]roachpb.ImportRequest_TableRekey, len(tables))
+	for i, table := range tables {
+		desc := sqlbase.Descriptor{
+			Union: &sqlbase.Descriptor_Table{Table: table},
+		}
+		newDescBytes, err := desc.Marshal()
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "marshalling descriptor")
+		}
+		rekeys[i] = roachpb.ImportRequest_TableRekey{
+			OldID:   uint32(oldTableIDs[i]),
+			NewDesc: newDescBytes,
+		}
 	}
 
-	return newTableIDs, kr, rekeys, nil
+	return newTableIDs, rekeys, nil
 }
 
 func reassignReferencedTables(
@@ -663,10 +656,6 @@ func Restore(
 		return 0, err
 	}
 
-	// We get the spans of the restoring tables _as they appear in the backup_,
-	// that is, in the 'old' keyspace, before we reassign the table IDs.
-	spans := spansForAllTableIndexes(tables)
-
 	// Assign new IDs to the tables and update all references to use the new IDs,
 	// and get TableRekeys to use when importing their raw data.
 	//
@@ -674,22 +663,16 @@ func Restore(
 	// restore since restarts would be terrible (and our bulk import primitive
 	// are non-transactional), but this does mean if something fails during Import,
 	// we've "leaked" the IDs, in that the generator will have been incremented.
-	newTableIDs, kr, rekeys, err := reassignTableIDs(ctx, db, tables, opt)
+	newTableIDs, rekeys, err := reassignTableIDs(ctx, db, tables, opt)
 	if err != nil {
 		// We expect user-facing usage errors here, so don't wrapf.
 		return 0, err
 	}
 
-	// Pivot the backups, which are grouped by time, into requests for import,
-	// which are grouped by keyrange.
-	importRequests, _, err := makeImportRequests(spans, backupDescs)
-	if err != nil {
-		return 0, errors.Wrapf(err, "making import requests for %d backups", len(backupDescs))
-	}
-
 	for _, desc := range newTableIDs {
 		jobLogger.Job.DescriptorIDs = append(jobLogger.Job.DescriptorIDs, desc)
 	}
+	jobLogger.Job.Details = sql.RestoreJobDetails{URIs: uris, TableRekeys: rekeys}
 	if err := jobLogger.Created(ctx); err != nil {
 		return 0, err
 	}
@@ -697,16 +680,112 @@ func Restore(
 		return 0, err
 	}
 
+	return runRestore(ctx, p.ExecCfg(), backupDescs, jobLogger)
+}
+
+// runRestore imports the data of the tables described by the details of the
+// started jobLogger out of the given backups, starting from the last
+// checkpoint in the details, and then writes the table descriptors. The
+// jobLogger is either that of a RESTORE or of an IMPORT, of which the restore
+// of the converted CSV files is the last phase.
+func runRestore(
+	ctx context.Context,
+	execCfg *sql.ExecutorConfig,
+	backupDescs []BackupDescriptor,
+	jobLogger *sql.JobLogger,
+) (int64, error) {
+	db := *execCfg.DB
+	var details sql.RestoreJobDetails
+	var startFraction float32
+	switch d := jobLogger.Job.Details.(type) {
+	case sql.RestoreJobDetails:
+		details = d
+	case sql.ImportJobDetails:
+		details, startFraction = d.Restore, importConversionFraction
+	default:
+		return 0, errors.Errorf("unexpected details type %T for a RESTORE job", jobLogger.Job.Details)
+	}
+	rekeys := details.TableRekeys
+
+	tables := make([]*sqlbase.TableDescriptor, len(rekeys))
+	oldTables := make([]*sqlbase.TableDescriptor, len(rekeys))
+	for i, rekey := range rekeys {
+		var desc sqlbase.Descriptor
+		if err := desc.Unmarshal(rekey.NewDesc); err != nil {
+			return 0, errors.Wrap(err, "unmarshalling descriptor")
+		}
+		table := desc.GetTable()
+		if table == nil {
+			return 0, errors.Errorf("expected a table descriptor, got %v", desc)
+		}
+		tables[i] = table
+		oldTable := *table
+		oldTable.ID = sqlbase.ID(rekey.OldID)
+		oldTables[i] = &oldTable
+	}
+
+	// We get the spans of the restoring tables _as they appear in the backup_,
+	// that is, in the 'old' keyspace, before we reassigned the table IDs.
+	spans := spansForAllTableIndexes(oldTables)
+
+	kr, err := storageccl.MakeKeyRewriter(rekeys)
+	if err != nil {
+		return 0, err
+	}
+
+	// Pivot the backups, which are grouped by time, into requests for import,
+	// which are grouped by keyrange.
+	importRequests, _, err := makeImportRequests(spans, backupDescs)
+	if err != nil {
+		return 0, errors.Wrapf(err, "making import requests for %d backups", len(backupDescs))
+	}
+
+	// Import requests finish out of order, so the checkpoint recorded with the
+	// job's progress is the start key of the first request that has not yet
+	// finished. Everything below it has been imported, so a resumed restore can
+	// skip those requests.
+	mu := struct {
+		syncutil.Mutex
+		dataSize  int64
+		finished  []bool
+		highWater int
+	}{
+		finished: make([]bool, len(importRequests)),
+	}
+	for mu.highWater < len(importRequests) &&
+		importRequests[mu.highWater].EndKey.Compare(details.HighWater) <= 0 {
+		mu.finished[mu.highWater] = true
+		mu.highWater++
+	}
+	remaining := importRequests[mu.highWater:]
+
 	progressLogger := jobProgressLogger{
-		jobLogger:   jobLogger,
-		totalChunks: len(importRequests),
+		jobLogger:     jobLogger,
+		totalChunks:   len(importRequests),
+		resumedChunks: mu.highWater,
+		startFraction: startFraction,
+	}
+	progressLogger.checkpointFn = func() (interface{}, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		checkpoint := details
+		if mu.highWater == len(importRequests) {
+			checkpoint.HighWater = importRequests[len(importRequests)-1].EndKey
+		} else {
+			checkpoint.HighWater = importRequests[mu.highWater].Key
+		}
+		if importDetails, ok := jobLogger.Job.Details.(sql.ImportJobDetails); ok {
+			importDetails.Restore = checkpoint
+			return importDetails, nil
+		}
+		return checkpoint, nil
 	}
 
 	// The Import (and resulting WriteBatch) requests made below run on
 	// leaseholders, so presplit the ranges to balance the work among many
 	// nodes
-	splitKeys := make([]roachpb.Key, len(importRequests))
-	for i, r := range importRequests {
+	splitKeys := make([]roachpb.Key, len(remaining))
+	for i, r := range remaining {
 		var ok bool
 		splitKeys[i], ok, _ = kr.RewriteKey(append([]byte(nil), r.Key...))
 		if !ok {
@@ -714,7 +793,7 @@ func Restore(
 		}
 	}
 	if err := presplitRanges(ctx, db, splitKeys); err != nil {
-		return 0, errors.Wrapf(err, "presplitting %d ranges", len(importRequests))
+		return 0, errors.Wrapf(err, "presplitting %d ranges", len(remaining))
 	}
 	{
 		newSpans := spansForAllTableIndexes(tables)
@@ -741,7 +820,7 @@ func Restore(
 			})
 		}
 		if err := g.Wait(); err != nil {
-			return 0, errors.Wrapf(err, "scattering %d ranges", len(importRequests))
+			return 0, errors.Wrapf(err, "scattering %d ranges", len(remaining))
 		}
 	}
 
@@ -758,23 +837,26 @@ func Restore(
 	// TODO(dan): Make this limiting per node.
 	//
 	// TODO(dan): See if there's some better solution than rate-limiting #14798.
-	maxConcurrentImports := clusterNodeCount(p.ExecCfg().Gossip)
+	maxConcurrentImports := clusterNodeCount(execCfg.Gossip)
 	importsSem := make(chan struct{}, maxConcurrentImports)
 
-	mu := struct {
-		syncutil.Mutex
-		dataSize int64
-	}{}
-
 	g, gCtx := errgroup.WithContext(ctx)
-	for i := range importRequests {
+	for i := mu.highWater; i < len(importRequests); i++ {
+		// Stop issuing requests while the job is paused. The requests in flight
+		// are allowed to finish and their progress is still recorded.
+		if err := progressLogger.waitWhilePaused(ctx); err != nil {
+			// Wait for the requests in flight, so they don't race with the
+			// cleanup of the failed job.
+			_ = g.Wait()
+			return 0, err
+		}
 		select {
 		case importsSem <- struct{}{}:
 		case <-ctx.Done():
 			return 0, ctx.Err()
 		}
 
-		ir := importRequests[i]
+		i, ir := i, importRequests[i]
 		g.Go(func() error {
 			defer func() { <-importsSem }()
 
@@ -784,6 +866,10 @@ func Restore(
 			}
 			mu.Lock()
 			mu.dataSize += res.DataSize
+			mu.finished[i] = true
+			for mu.highWater < len(mu.finished) && mu.finished[mu.highWater] {
+				mu.highWater++
+			}
 			mu.Unlock()
 			if err := progressLogger.chunkFinished(gCtx); err != nil {
 				// Errors while updating progress are not important enough to merit
@@ -816,6 +902,20 @@ func Restore(
 	return mu.dataSize, nil
 }
 
+// resumeRestore is the JobResumeHook of RESTORE jobs.
+func resumeRestore(ctx context.Context, execCfg *sql.ExecutorConfig, job *sql.JobLogger) error {
+	details, ok := job.Job.Details.(sql.RestoreJobDetails)
+	if !ok {
+		return errors.Errorf("unexpected details type %T for a RESTORE job", job.Job.Details)
+	}
+	backupDescs, err := loadBackupDescs(ctx, details.URIs)
+	if err != nil {
+		return err
+	}
+	_, err = runRestore(ctx, execCfg, backupDescs, job)
+	return err
+}
+
 func restorePlanHook(
 	baseCtx context.Context, stmt parser.Statement, p sql.PlanHookState,
 ) (func() ([]parser.Datums, error), sqlbase.ResultColumns, error) {
@@ -855,7 +955,7 @@ func restorePlanHook(
 		if err != nil {
 			return nil, err
 		}
-		jobLogger := sql.NewJobLogger(p.ExecCfg().DB, p.LeaseMgr(), sql.JobRecord{
+		jobLogger := p.ExecCfg().JobRegistry.NewJobLogger(sql.JobRecord{
 			Description: description,
 			Username:    p.User(),
 			Details:     sql.RestoreJobDetails{},
@@ -893,4 +993,5 @@ func restorePlanHook(
 
 func init() {
 	sql.AddPlanHook(restorePlanHook)
+	sql.AddJobResumeHook(sql.JobTypeRestore, resumeRestore)
 }
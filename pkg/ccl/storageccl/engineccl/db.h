@@ -20,6 +20,11 @@ extern "C" {
 DBStatus DBBatchReprVerify(
   DBSlice repr, DBKey start, DBKey end, int64_t now_nanos, MVCCStatsResult* stats);
 
+// DBEncryptionCTRCrypt encrypts or decrypts data in place with AES in counter
+// mode, as the contents of an encrypted file starting at the given offset.
+// It is exposed for testing.
+DBStatus DBEncryptionCTRCrypt(DBSlice key, DBSlice iv, uint64_t offset, DBSlice data);
+
 #ifdef __cplusplus
 }  // extern "C"
 #endif
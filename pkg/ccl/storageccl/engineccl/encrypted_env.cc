@@ -0,0 +1,618 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/LICENSE
+
+#include <algorithm>
+#include <map>
+#include <memory>
+#include <string.h>
+#include <openssl/evp.h>
+#include <openssl/rand.h>
+#include "rocksdb/env.h"
+#include "../../../storage/engine/db_internal.h"
+#include "db.h"
+
+// Encryption at rest.
+//
+// Files are encrypted with AES in counter mode (CTR), as implemented by
+// OpenSSL's libcrypto, which uses the AES-NI instructions of the processor
+// when they are available. Each encrypted file
+// starts with a header of kFileHeaderSize bytes which contains a magic
+// string, the ID of the key the file is encrypted with and the random
+// initialization vector (IV) of the file. The logical contents of the file
+// follow the header. The header is padded to a multiple of the block size
+// of the filesystem so that writes of the file contents remain aligned.
+//
+// As the key ID is recorded in each file, the files of a store can be
+// encrypted with different keys: when the active key is rotated, new files
+// are encrypted with the new key while existing files remain readable as
+// long as the old key is provided. Existing files are gradually rewritten
+// with the new key by compactions. Files without the magic string are
+// plaintext, which allows encryption to be enabled on an existing store.
+//
+// Counter mode provides confidentiality but not integrity: the encrypted
+// data is not authenticated, and the checksums of RocksDB, which detect
+// accidental corruption, do not protect against deliberate tampering by
+// someone with write access to the store. As a stream cipher, counter mode
+// also must never encrypt two different plaintexts at the same position of
+// a file with the same key and IV; EncryptedWritableFile enforces that.
+
+namespace {
+
+const char kFileMagic[] = "CRDBENC1";
+const size_t kFileMagicSize = 8;
+const size_t kKeyIDSize = 32;
+const size_t kAESBlockSize = 16;
+const size_t kFileHeaderSize = 4096;
+
+// AESCTRCipher returns the OpenSSL cipher for AES in counter mode with keys
+// of the given size, or nullptr if it is not the size of an AES key.
+const EVP_CIPHER* AESCTRCipher(size_t key_size) {
+  switch (key_size) {
+    case 16:
+      return EVP_aes_128_ctr();
+    case 24:
+      return EVP_aes_192_ctr();
+    case 32:
+      return EVP_aes_256_ctr();
+  }
+  return nullptr;
+}
+
+// CTRCipherStream encrypts and decrypts the contents of a file with AES in
+// counter mode, using the implementation of OpenSSL's libcrypto. The counter
+// of each block is the IV of the file plus the index of the block, as a
+// big-endian 128-bit integer. Any range of the file can be encrypted or
+// decrypted independently, which is required for random access reads.
+class CTRCipherStream {
+ public:
+  CTRCipherStream(const std::string& key, const std::string& iv)
+      : key_(key),
+        iv_(iv) {
+  }
+
+  // Crypt encrypts or decrypts (the two are the same in counter mode) n
+  // bytes in place, which start at the given offset in the file contents.
+  rocksdb::Status Crypt(uint64_t offset, char* data, size_t n) const {
+    if (n == 0) {
+      return rocksdb::Status::OK();
+    }
+    const EVP_CIPHER* cipher = AESCTRCipher(key_.size());
+    if (cipher == nullptr || iv_.size() != kAESBlockSize) {
+      return rocksdb::Status::InvalidArgument("invalid AES key or IV size");
+    }
+
+    // The counter of the block containing offset.
+    uint8_t counter[kAESBlockSize];
+    memcpy(counter, iv_.data(), kAESBlockSize);
+    uint64_t carry = offset / kAESBlockSize;
+    for (int i = kAESBlockSize - 1; i >= 0 && carry > 0; i--) {
+      const uint64_t sum = counter[i] + (carry & 0xff);
+      counter[i] = sum & 0xff;
+      carry = (carry >> 8) + (sum >> 8);
+    }
+
+    std::unique_ptr<EVP_CIPHER_CTX, void (*)(EVP_CIPHER_CTX*)> ctx(
+        EVP_CIPHER_CTX_new(), EVP_CIPHER_CTX_free);
+    if (ctx == nullptr ||
+        EVP_EncryptInit_ex(ctx.get(), cipher, nullptr,
+                           reinterpret_cast<const uint8_t*>(key_.data()), counter) != 1) {
+      return rocksdb::Status::IOError("unable to initialize AES cipher");
+    }
+    int len;
+    // Discard the keystream of the bytes of the first block which precede
+    // offset.
+    uint8_t skip[kAESBlockSize] = {};
+    const int skip_len = offset % kAESBlockSize;
+    if (skip_len > 0 && EVP_EncryptUpdate(ctx.get(), skip, &len, skip, skip_len) != 1) {
+      return rocksdb::Status::IOError("AES encryption failed");
+    }
+    // In counter mode, OpenSSL processes the data as a stream and does not
+    // buffer partial blocks. EVP_EncryptUpdate takes an int length, so large
+    // buffers are processed in chunks.
+    uint8_t* p = reinterpret_cast<uint8_t*>(data);
+    while (n > 0) {
+      const int chunk = static_cast<int>(std::min<size_t>(n, 1 << 30));
+      if (EVP_EncryptUpdate(ctx.get(), p, &len, p, chunk) != 1) {
+        return rocksdb::Status::IOError("AES encryption failed");
+      }
+      p += chunk;
+      n -= chunk;
+    }
+    return rocksdb::Status::OK();
+  }
+
+ private:
+  const std::string key_;
+  const std::string iv_;
+};
+
+// FileHeader is the parsed header of an encrypted file.
+struct FileHeader {
+  std::string key_id;
+  std::string iv;
+
+  // Encode returns the header as written at the start of the file.
+  std::string Encode() const {
+    std::string buf(kFileHeaderSize, '\0');
+    memcpy(&buf[0], kFileMagic, kFileMagicSize);
+    memcpy(&buf[kFileMagicSize], key_id.data(), kKeyIDSize);
+    memcpy(&buf[kFileMagicSize + kKeyIDSize], iv.data(), kAESBlockSize);
+    return buf;
+  }
+};
+
+// ReadFileHeader reads the header of a file from its beginning. It sets
+// *encrypted to false if the file is plaintext.
+rocksdb::Status ReadFileHeader(
+    rocksdb::SequentialFile* file, bool* encrypted, FileHeader* header) {
+  std::string buf(kFileHeaderSize, '\0');
+  size_t n = 0;
+  while (n < kFileHeaderSize) {
+    rocksdb::Slice result;
+    rocksdb::Status s = file->Read(kFileHeaderSize - n, &result, &buf[n]);
+    if (!s.ok()) {
+      return s;
+    }
+    if (result.size() == 0) {
+      break;
+    }
+    if (result.data() != &buf[n]) {
+      memmove(&buf[n], result.data(), result.size());
+    }
+    n += result.size();
+  }
+  *encrypted = n >= kFileMagicSize && memcmp(buf.data(), kFileMagic, kFileMagicSize) == 0;
+  if (!*encrypted) {
+    return rocksdb::Status::OK();
+  }
+  if (n < kFileHeaderSize) {
+    return rocksdb::Status::Corruption("truncated encryption header");
+  }
+  header->key_id = buf.substr(kFileMagicSize, kKeyIDSize);
+  header->iv = buf.substr(kFileMagicSize + kKeyIDSize, kAESBlockSize);
+  return rocksdb::Status::OK();
+}
+
+// PlainOptions returns the options used to open the underlying files of
+// encrypted files. Memory mapping and direct I/O are disabled as the file
+// contents need to be copied to be encrypted or decrypted anyway.
+rocksdb::EnvOptions PlainOptions(const rocksdb::EnvOptions& options) {
+  rocksdb::EnvOptions plain_options(options);
+  plain_options.use_mmap_reads = false;
+  plain_options.use_mmap_writes = false;
+  plain_options.use_direct_reads = false;
+  plain_options.use_direct_writes = false;
+  return plain_options;
+}
+
+class EncryptedSequentialFile : public rocksdb::SequentialFile {
+ public:
+  EncryptedSequentialFile(std::unique_ptr<rocksdb::SequentialFile> file,
+                          std::unique_ptr<CTRCipherStream> stream)
+      : file_(std::move(file)),
+        stream_(std::move(stream)),
+        offset_(0) {
+  }
+
+  rocksdb::Status Read(size_t n, rocksdb::Slice* result, char* scratch) override {
+    rocksdb::Status s = file_->Read(n, result, scratch);
+    if (!s.ok()) {
+      return s;
+    }
+    if (result->data() != scratch) {
+      memmove(scratch, result->data(), result->size());
+      *result = rocksdb::Slice(scratch, result->size());
+    }
+    s = stream_->Crypt(offset_, scratch, result->size());
+    if (!s.ok()) {
+      return s;
+    }
+    offset_ += result->size();
+    return s;
+  }
+
+  rocksdb::Status Skip(uint64_t n) override {
+    rocksdb::Status s = file_->Skip(n);
+    if (s.ok()) {
+      offset_ += n;
+    }
+    return s;
+  }
+
+  rocksdb::Status InvalidateCache(size_t offset, size_t length) override {
+    return file_->InvalidateCache(offset + kFileHeaderSize, length);
+  }
+
+ private:
+  std::unique_ptr<rocksdb::SequentialFile> file_;
+  std::unique_ptr<CTRCipherStream> stream_;
+  uint64_t offset_;
+};
+
+class EncryptedRandomAccessFile : public rocksdb::RandomAccessFile {
+ public:
+  EncryptedRandomAccessFile(std::unique_ptr<rocksdb::RandomAccessFile> file,
+                            std::unique_ptr<CTRCipherStream> stream)
+      : file_(std::move(file)),
+        stream_(std::move(stream)) {
+  }
+
+  rocksdb::Status Read(uint64_t offset, size_t n, rocksdb::Slice* result,
+                       char* scratch) const override {
+    rocksdb::Status s = file_->Read(offset + kFileHeaderSize, n, result, scratch);
+    if (!s.ok()) {
+      return s;
+    }
+    if (result->data() != scratch) {
+      memmove(scratch, result->data(), result->size());
+      *result = rocksdb::Slice(scratch, result->size());
+    }
+    return stream_->Crypt(offset, scratch, result->size());
+  }
+
+  size_t GetUniqueId(char* id, size_t max_size) const override {
+    return file_->GetUniqueId(id, max_size);
+  }
+
+  void Hint(AccessPattern pattern) override {
+    file_->Hint(pattern);
+  }
+
+  rocksdb::Status InvalidateCache(size_t offset, size_t length) override {
+    return file_->InvalidateCache(offset + kFileHeaderSize, length);
+  }
+
+ private:
+  std::unique_ptr<rocksdb::RandomAccessFile> file_;
+  std::unique_ptr<CTRCipherStream> stream_;
+};
+
+class EncryptedWritableFile : public rocksdb::WritableFile {
+ public:
+  EncryptedWritableFile(std::unique_ptr<rocksdb::WritableFile> file,
+                        std::unique_ptr<CTRCipherStream> stream)
+      : file_(std::move(file)),
+        stream_(std::move(stream)),
+        offset_(0),
+        written_(0) {
+  }
+
+  rocksdb::Status Append(const rocksdb::Slice& data) override {
+    if (offset_ < written_) {
+      // The file was truncated, and the keystream at offset_ was already
+      // used to encrypt the discarded data. Encrypting new data with it
+      // would reveal the XOR of the two plaintexts.
+      return rocksdb::Status::NotSupported(
+          "cannot overwrite truncated data of an encrypted file");
+    }
+    std::string buf(data.data(), data.size());
+    rocksdb::Status s = stream_->Crypt(offset_, &buf[0], buf.size());
+    if (!s.ok()) {
+      return s;
+    }
+    s = file_->Append(buf);
+    if (s.ok()) {
+      offset_ += data.size();
+      written_ = offset_;
+    }
+    return s;
+  }
+
+  // Truncate truncates the file contents to the given size. Appending to
+  // the file afterwards is only allowed if no data was ever encrypted past
+  // that size, such as when RocksDB truncates the preallocated space of a
+  // file before closing it.
+  rocksdb::Status Truncate(uint64_t size) override {
+    rocksdb::Status s = file_->Truncate(size + kFileHeaderSize);
+    if (s.ok()) {
+      offset_ = size;
+    }
+    return s;
+  }
+
+  rocksdb::Status Close() override { return file_->Close(); }
+  rocksdb::Status Flush() override { return file_->Flush(); }
+  rocksdb::Status Sync() override { return file_->Sync(); }
+  rocksdb::Status Fsync() override { return file_->Fsync(); }
+  bool IsSyncThreadSafe() const override { return file_->IsSyncThreadSafe(); }
+
+  void SetIOPriority(rocksdb::Env::IOPriority pri) override {
+    file_->SetIOPriority(pri);
+  }
+
+  rocksdb::Env::IOPriority GetIOPriority() override {
+    return file_->GetIOPriority();
+  }
+
+  uint64_t GetFileSize() override {
+    return offset_;
+  }
+
+  void SetPreallocationBlockSize(size_t size) override {
+    file_->SetPreallocationBlockSize(size);
+  }
+
+  void GetPreallocationStatus(size_t* block_size,
+                              size_t* last_allocated_block) override {
+    file_->GetPreallocationStatus(block_size, last_allocated_block);
+  }
+
+  size_t GetUniqueId(char* id, size_t max_size) const override {
+    return file_->GetUniqueId(id, max_size);
+  }
+
+  rocksdb::Status InvalidateCache(size_t offset, size_t length) override {
+    return file_->InvalidateCache(offset + kFileHeaderSize, length);
+  }
+
+  rocksdb::Status RangeSync(uint64_t offset, uint64_t nbytes) override {
+    return file_->RangeSync(offset + kFileHeaderSize, nbytes);
+  }
+
+  void PrepareWrite(size_t offset, size_t len) override {
+    file_->PrepareWrite(offset + kFileHeaderSize, len);
+  }
+
+ private:
+  std::unique_ptr<rocksdb::WritableFile> file_;
+  std::unique_ptr<CTRCipherStream> stream_;
+  uint64_t offset_;
+  // written_ is the end of the file contents which were ever encrypted.
+  uint64_t written_;
+};
+
+// CTREncryptedEnv is the EncryptedEnv used for encryption at rest. It
+// encrypts new files with its active key, if any.
+//
+// Note that the info logs of RocksDB are written in plaintext, as they do
+// not contain user data.
+class CTREncryptedEnv : public EncryptedEnv {
+ public:
+  explicit CTREncryptedEnv(rocksdb::Env* base_env)
+      : EncryptedEnv(base_env) {
+  }
+
+  // AddKey makes a key available to decrypt files, and to encrypt new
+  // files if it is made active by SetActiveKeyID.
+  rocksdb::Status AddKey(const std::string& id, const std::string& key) {
+    if (id.size() != kKeyIDSize) {
+      return rocksdb::Status::InvalidArgument("invalid encryption key ID");
+    }
+    if (key.size() != 16 && key.size() != 24 && key.size() != 32) {
+      return rocksdb::Status::InvalidArgument(
+          "encryption key must be 16, 24 or 32 bytes long");
+    }
+    keys_[id] = key;
+    return rocksdb::Status::OK();
+  }
+
+  // SetActiveKeyID sets the key used to encrypt new files. An empty ID
+  // causes new files to be written in plaintext.
+  rocksdb::Status SetActiveKeyID(const std::string& id) {
+    if (!id.empty() && keys_.find(id) == keys_.end()) {
+      return rocksdb::Status::InvalidArgument("unknown active encryption key");
+    }
+    active_key_id_ = id;
+    return rocksdb::Status::OK();
+  }
+
+  std::string ActiveKeyID() const override {
+    return active_key_id_;
+  }
+
+  rocksdb::Status GetFileKeyID(const std::string& fname, std::string* id) override {
+    bool encrypted;
+    FileHeader header;
+    rocksdb::Status s = GetFileHeader(fname, &encrypted, &header);
+    if (!s.ok()) {
+      return s;
+    }
+    *id = encrypted ? header.key_id : "";
+    return rocksdb::Status::OK();
+  }
+
+  rocksdb::Status NewSequentialFile(const std::string& fname,
+                                    std::unique_ptr<rocksdb::SequentialFile>* result,
+                                    const rocksdb::EnvOptions& options) override {
+    std::unique_ptr<rocksdb::SequentialFile> file;
+    rocksdb::Status s = target()->NewSequentialFile(fname, &file, PlainOptions(options));
+    if (!s.ok()) {
+      return s;
+    }
+    bool encrypted;
+    FileHeader header;
+    s = ReadFileHeader(file.get(), &encrypted, &header);
+    if (!s.ok()) {
+      return s;
+    }
+    if (!encrypted) {
+      // The header was consumed, reopen the file from its beginning.
+      return target()->NewSequentialFile(fname, result, options);
+    }
+    std::unique_ptr<CTRCipherStream> stream;
+    s = NewStream(fname, header, &stream);
+    if (!s.ok()) {
+      return s;
+    }
+    result->reset(new EncryptedSequentialFile(std::move(file), std::move(stream)));
+    return rocksdb::Status::OK();
+  }
+
+  rocksdb::Status NewRandomAccessFile(const std::string& fname,
+                                      std::unique_ptr<rocksdb::RandomAccessFile>* result,
+                                      const rocksdb::EnvOptions& options) override {
+    bool encrypted;
+    FileHeader header;
+    rocksdb::Status s = GetFileHeader(fname, &encrypted, &header);
+    if (!s.ok()) {
+      return s;
+    }
+    if (!encrypted) {
+      return target()->NewRandomAccessFile(fname, result, options);
+    }
+    std::unique_ptr<CTRCipherStream> stream;
+    s = NewStream(fname, header, &stream);
+    if (!s.ok()) {
+      return s;
+    }
+    std::unique_ptr<rocksdb::RandomAccessFile> file;
+    s = target()->NewRandomAccessFile(fname, &file, PlainOptions(options));
+    if (!s.ok()) {
+      return s;
+    }
+    result->reset(new EncryptedRandomAccessFile(std::move(file), std::move(stream)));
+    return rocksdb::Status::OK();
+  }
+
+  rocksdb::Status NewWritableFile(const std::string& fname,
+                                  std::unique_ptr<rocksdb::WritableFile>* result,
+                                  const rocksdb::EnvOptions& options) override {
+    if (active_key_id_.empty()) {
+      return target()->NewWritableFile(fname, result, options);
+    }
+    FileHeader header;
+    header.key_id = active_key_id_;
+    rocksdb::Status s = RandomIV(&header.iv);
+    if (!s.ok()) {
+      return s;
+    }
+    std::unique_ptr<CTRCipherStream> stream;
+    s = NewStream(fname, header, &stream);
+    if (!s.ok()) {
+      return s;
+    }
+    std::unique_ptr<rocksdb::WritableFile> file;
+    s = target()->NewWritableFile(fname, &file, PlainOptions(options));
+    if (!s.ok()) {
+      return s;
+    }
+    s = file->Append(header.Encode());
+    if (!s.ok()) {
+      return s;
+    }
+    result->reset(new EncryptedWritableFile(std::move(file), std::move(stream)));
+    return rocksdb::Status::OK();
+  }
+
+  rocksdb::Status ReuseWritableFile(const std::string& fname,
+                                    const std::string& old_fname,
+                                    std::unique_ptr<rocksdb::WritableFile>* result,
+                                    const rocksdb::EnvOptions& options) override {
+    // The reused file is rewritten from its beginning, so it gets a new
+    // header (and IV).
+    rocksdb::Status s = RenameFile(old_fname, fname);
+    if (!s.ok()) {
+      return s;
+    }
+    return NewWritableFile(fname, result, options);
+  }
+
+  rocksdb::Status NewRandomRWFile(const std::string& fname,
+                                  std::unique_ptr<rocksdb::RandomRWFile>* result,
+                                  const rocksdb::EnvOptions& options) override {
+    return rocksdb::Status::NotSupported(
+        "random read-write files are not supported with encryption at rest");
+  }
+
+  rocksdb::Status GetFileSize(const std::string& fname, uint64_t* size) override {
+    rocksdb::Status s = target()->GetFileSize(fname, size);
+    if (!s.ok()) {
+      return s;
+    }
+    bool encrypted;
+    FileHeader header;
+    s = GetFileHeader(fname, &encrypted, &header);
+    if (!s.ok()) {
+      return s;
+    }
+    if (encrypted) {
+      *size -= kFileHeaderSize;
+    }
+    return rocksdb::Status::OK();
+  }
+
+  rocksdb::Status GetChildrenFileAttributes(
+      const std::string& dir, std::vector<FileAttributes>* result) override {
+    // Use the default implementation which calls GetFileSize for each file,
+    // rather than that of the wrapped Env.
+    return rocksdb::Env::GetChildrenFileAttributes(dir, result);
+  }
+
+ private:
+  rocksdb::Status GetFileHeader(const std::string& fname, bool* encrypted,
+                                FileHeader* header) {
+    std::unique_ptr<rocksdb::SequentialFile> file;
+    rocksdb::Status s = target()->NewSequentialFile(fname, &file, rocksdb::EnvOptions());
+    if (!s.ok()) {
+      return s;
+    }
+    return ReadFileHeader(file.get(), encrypted, header);
+  }
+
+  rocksdb::Status NewStream(const std::string& fname, const FileHeader& header,
+                            std::unique_ptr<CTRCipherStream>* stream) {
+    auto it = keys_.find(header.key_id);
+    if (it == keys_.end()) {
+      return rocksdb::Status::InvalidArgument(
+          fname + " is encrypted with an unknown key", HexID(header.key_id));
+    }
+    stream->reset(new CTRCipherStream(it->second, header.iv));
+    return rocksdb::Status::OK();
+  }
+
+  // RandomIV generates the IV of a new file with the cryptographically
+  // secure random number generator of OpenSSL.
+  static rocksdb::Status RandomIV(std::string* iv) {
+    iv->assign(kAESBlockSize, '\0');
+    if (RAND_bytes(reinterpret_cast<uint8_t*>(&(*iv)[0]), kAESBlockSize) != 1) {
+      return rocksdb::Status::IOError("unable to generate a random IV");
+    }
+    return rocksdb::Status::OK();
+  }
+
+  static std::string HexID(const std::string& id) {
+    static const char kHexDigits[] = "0123456789abcdef";
+    std::string hex;
+    for (unsigned char c : id) {
+      hex.push_back(kHexDigits[c >> 4]);
+      hex.push_back(kHexDigits[c & 0xf]);
+    }
+    return hex;
+  }
+
+  std::map<std::string, std::string> keys_;
+  std::string active_key_id_;
+};
+
+}  // namespace
+
+rocksdb::Status NewEncryptedEnv(
+    rocksdb::Env* base_env, const DBOptions& db_opts, EncryptedEnv** env) {
+  std::unique_ptr<CTREncryptedEnv> e(new CTREncryptedEnv(base_env));
+  for (const DBEncryptionKey* k : {&db_opts.current_key, &db_opts.old_key}) {
+    if (k->key.len == 0) {
+      continue;
+    }
+    rocksdb::Status s = e->AddKey(ToString(k->id), ToString(k->key));
+    if (!s.ok()) {
+      return s;
+    }
+  }
+  rocksdb::Status s = e->SetActiveKeyID(
+      db_opts.current_key.key.len == 0 ? "" : ToString(db_opts.current_key.id));
+  if (!s.ok()) {
+    return s;
+  }
+  *env = e.release();
+  return rocksdb::Status::OK();
+}
+
+DBStatus DBEncryptionCTRCrypt(DBSlice key, DBSlice iv, uint64_t offset, DBSlice data) {
+  CTRCipherStream stream(ToString(key), ToString(iv));
+  return ToDBStatus(stream.Crypt(offset, data.data, data.len));
+}
@@ -0,0 +1,232 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/LICENSE
+
+package engineccl
+
+import (
+	"bytes"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/storage/engine"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+)
+
+func TestEncryptedRocksDB(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	dir, err := ioutil.TempDir("", t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := os.RemoveAll(dir); err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	writeKey := func(name string, key []byte) string {
+		path := filepath.Join(dir, name)
+		if err := ioutil.WriteFile(path, key, 0600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	oldKey := writeKey("old.key", bytes.Repeat([]byte{1}, 16))
+	newKey := writeKey("new.key", bytes.Repeat([]byte{2}, 32))
+	dbDir := filepath.Join(dir, "db")
+
+	open := func(opts engine.EncryptionOptions) (*engine.RocksDB, error) {
+		return engine.NewEncryptedRocksDB(
+			roachpb.Attributes{}, dbDir, engine.RocksDBCache{}, 0, engine.DefaultMaxOpenFiles, opts,
+		)
+	}
+	status := func(db *engine.RocksDB) engine.EncryptionStatus {
+		s, err := db.GetEncryptionStatus()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s == nil {
+			t.Fatal("expected an encryption status")
+		}
+		if s.TotalFiles == 0 || s.TotalBytes == 0 {
+			t.Fatalf("expected live files, got %+v", s)
+		}
+		return *s
+	}
+
+	key := engine.MakeMVCCMetadataKey(roachpb.Key("a"))
+	value := []byte("a plaintext value that must not appear on disk")
+
+	// Write some data with the old key and flush it to an sstable.
+	db, err := open(engine.EncryptionOptions{KeyFile: oldKey})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Put(key, value); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if s := status(db); s.ActiveKeyFiles != s.TotalFiles || s.ActiveKeyBytes != s.TotalBytes {
+		t.Fatalf("expected all files to use the active key, got %+v", s)
+	}
+	db.Close()
+
+	// Neither the sstable nor the write-ahead log may contain the value in
+	// plaintext. The info LOG files only contain RocksDB's own messages.
+	files, err := ioutil.ReadDir(dbDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range files {
+		if strings.HasPrefix(f.Name(), "LOG") {
+			continue
+		}
+		data, err := ioutil.ReadFile(filepath.Join(dbDir, f.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if bytes.Contains(data, value) {
+			t.Errorf("found plaintext value in %s", f.Name())
+		}
+	}
+
+	// Files encrypted with an unknown key cannot be read.
+	if db, err := open(engine.EncryptionOptions{KeyFile: newKey}); err == nil {
+		db.Close()
+		t.Fatal("expected an error opening the store without its old key")
+	} else if !testutils.IsError(err, "encrypted with an unknown key") {
+		t.Fatal(err)
+	}
+
+	// Rotate the key. The old data is still readable and new files use the
+	// new key.
+	db, err = open(engine.EncryptionOptions{KeyFile: newKey, OldKeyFile: oldKey})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if v, err := db.Get(key); err != nil {
+		t.Fatal(err)
+	} else if !bytes.Equal(v, value) {
+		t.Fatalf("expected %q, got %q", value, v)
+	}
+	if s := status(db); s.ActiveKeyFiles == 0 || s.ActiveKeyFiles == s.TotalFiles {
+		t.Fatalf("expected files encrypted with both keys, got %+v", s)
+	}
+}
+
+func mustDecodeHex(t *testing.T, s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+// TestEncryptionAESKnownAnswers checks the block cipher against the example
+// vectors of FIPS-197, Appendix C. Encrypting zeros in counter mode with the
+// plaintext block as IV yields the encryption of that block.
+func TestEncryptionAESKnownAnswers(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	const plaintext = "00112233445566778899aabbccddeeff"
+	testCases := []struct {
+		name       string
+		key        string
+		ciphertext string
+	}{
+		{"AES-128", "000102030405060708090a0b0c0d0e0f",
+			"69c4e0d86a7b0430d8cdb78070b4c55a"},
+		{"AES-192", "000102030405060708090a0b0c0d0e0f1011121314151617",
+			"dda97ca4864cdfe06eaf70a0ec0d7191"},
+		{"AES-256", "000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f",
+			"8ea2b7ca516745bfeafc49904b496089"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			data := make([]byte, 16)
+			if err := ctrCrypt(mustDecodeHex(t, tc.key), mustDecodeHex(t, plaintext), 0, data); err != nil {
+				t.Fatal(err)
+			}
+			if actual := hex.EncodeToString(data); actual != tc.ciphertext {
+				t.Fatalf("expected %s, got %s", tc.ciphertext, actual)
+			}
+		})
+	}
+}
+
+// TestEncryptionCTRKnownAnswers checks counter mode against the vectors of
+// NIST SP 800-38A, F.5, when starting at each offset of the file contents.
+func TestEncryptionCTRKnownAnswers(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	const iv = "f0f1f2f3f4f5f6f7f8f9fafbfcfdfeff"
+	const plaintext = "6bc1bee22e409f96e93d7e117393172a" +
+		"ae2d8a571e03ac9c9eb76fac45af8e51" +
+		"30c81c46a35ce411e5fbc1191a0a52ef" +
+		"f69f2445df4f9b17ad2b417be66c3710"
+	testCases := []struct {
+		name       string
+		key        string
+		ciphertext string
+	}{
+		{"AES-128", "2b7e151628aed2a6abf7158809cf4f3c",
+			"874d6191b620e3261bef6864990db6ce" +
+				"9806f66b7970fdff8617187bb9fffdff" +
+				"5ae4df3edbd5d35e5b4f09020db03eab" +
+				"1e031dda2fbe03d1792170a0f3009cee"},
+		{"AES-192", "8e73b0f7da0e6452c810f32b809079e562f8ead2522c6b7b",
+			"1abc932417521ca24f2b0459fe7e6e0b" +
+				"090339ec0aa6faefd5ccc2c6f4ce8e94" +
+				"1e36b26bd1ebc670d1bd1d665620abf7" +
+				"4f78a7f6d29809585a97daec58c6b050"},
+		{"AES-256", "603deb1015ca71be2b73aef0857d77811f352c073b6108d72d9810a30914dff4",
+			"601ec313775789a5b7a7f504bbf3d228" +
+				"f443e3ca4d62b59aca84e990cacaf5c5" +
+				"2b0930daa23de94ce87017ba2d84988d" +
+				"dfc9c58db67aada613c2dd08457941a6"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			key := mustDecodeHex(t, tc.key)
+			plain := mustDecodeHex(t, plaintext)
+			expected := mustDecodeHex(t, tc.ciphertext)
+			// Offsets which are not a multiple of the block size start in
+			// the middle of a block.
+			for offset := 0; offset < len(plain); offset++ {
+				data := append([]byte(nil), plain[offset:]...)
+				if err := ctrCrypt(key, mustDecodeHex(t, iv), uint64(offset), data); err != nil {
+					t.Fatal(err)
+				}
+				if !bytes.Equal(data, expected[offset:]) {
+					t.Fatalf("offset %d: expected %x, got %x", offset, expected[offset:], data)
+				}
+				// Decryption is the same operation.
+				if err := ctrCrypt(key, mustDecodeHex(t, iv), uint64(offset), data); err != nil {
+					t.Fatal(err)
+				}
+				if !bytes.Equal(data, plain[offset:]) {
+					t.Fatalf("offset %d: expected %x, got %x", offset, plain[offset:], data)
+				}
+			}
+		})
+	}
+
+	if err := ctrCrypt(make([]byte, 15), mustDecodeHex(t, iv), 0, make([]byte, 16)); !testutils.IsError(err, "invalid AES key") {
+		t.Fatalf("expected invalid key error, got: %v", err)
+	}
+}
@@ -23,6 +23,7 @@ import (
 // #cgo LDFLAGS: -lprotobuf
 // #cgo LDFLAGS: -lrocksdb
 // #cgo LDFLAGS: -lsnappy
+// #cgo LDFLAGS: -lcrypto
 // #cgo CXXFLAGS: -std=c++11 -Werror -Wall -Wno-sign-compare
 // #cgo linux LDFLAGS: -lrt -lpthread
 // #cgo windows LDFLAGS: -lrpcrt4
@@ -88,6 +89,16 @@ func VerifyBatchRepr(
 	return cStatsToGoStats(stats, nowNanos)
 }
 
+// ctrCrypt encrypts or decrypts data in place with the AES counter mode
+// cipher used for encryption at rest, as the contents of a file with the
+// given IV starting at the given offset. It is used to test the cipher
+// against known answers.
+func ctrCrypt(key, iv []byte, offset uint64, data []byte) error {
+	return statusToError(C.DBEncryptionCTRCrypt(
+		goToCSlice(key), goToCSlice(iv), C.uint64_t(offset), goToCSlice(data),
+	))
+}
+
 // TODO(dan): The following are all duplicated from storage/engine/rocksdb.go,
 // but if you export the ones there and reuse them here, it doesn't work.
 //
@@ -101,6 +101,26 @@ left unspecified, defaults to 25% of the physical memory, or 512MB if
 the memory size cannot be determined.`,
 	}
 
+	SQLTempStorage = FlagInfo{
+		Name: "max-disk-temp-storage",
+		Description: `
+Maximum size in bytes of the temporary storage used by SQL queries whose
+intermediate data does not fit in memory, for example large sorts, hash
+joins and aggregations. Size suffixes are supported (e.g. 1GB and 1GiB).
+If left unspecified, defaults to 32GiB.`,
+	}
+
+	TempDir = FlagInfo{
+		Name: "temp-dir",
+		Description: `
+The directory in which the temporary storage used by SQL queries is
+created. The node creates a new "cockroach-temp" subdirectory of it, which
+is removed when the node stops or, if the node exits without removing it,
+when the node restarts. Other contents of the directory are left alone. If
+left unspecified, the first store's directory is used, or memory if the
+first store is in memory.`,
+	}
+
 	Cache = FlagInfo{
 		Name: "cache",
 		Description: `
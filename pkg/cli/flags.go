@@ -172,6 +172,23 @@ func varFlag(f *pflag.FlagSet, value pflag.Value, flagInfo cliflags.FlagInfo) {
 	setFlagFromEnv(f, flagInfo)
 }
 
+// storeSpecsHooks are run on the store specs of the start command before the
+// stores are created. See AddStoreSpecsHook.
+var storeSpecsHooks []func(base.StoreSpecList) error
+
+// AddStartFlag registers a flag of the start command. It allows CCL code to
+// add the flags of enterprise features, which OSS builds do not have.
+func AddStartFlag(value pflag.Value, flagInfo cliflags.FlagInfo) {
+	varFlag(startCmd.Flags(), value, flagInfo)
+}
+
+// AddStoreSpecsHook registers a function which is run on the store specs of
+// the start command before the stores are created. It allows CCL code to
+// apply the flags it registered with AddStartFlag to the stores.
+func AddStoreSpecsHook(fn func(base.StoreSpecList) error) {
+	storeSpecsHooks = append(storeSpecsHooks, fn)
+}
+
 func init() {
 	// Change the logging defaults for the main cockroach binary.
 	// The value is overridden after command-line parsing.
@@ -272,6 +289,11 @@ func init() {
 
 		sqlSize := humanizeutil.NewBytesValue(&serverCfg.SQLMemoryPoolSize)
 		varFlag(f, sqlSize, cliflags.SQLMem)
+
+		// Temporary storage flags.
+		tempStorageSize := humanizeutil.NewBytesValue(&serverCfg.TempStorageMaxSizeBytes)
+		varFlag(f, tempStorageSize, cliflags.SQLTempStorage)
+		stringFlag(f, &serverCfg.TempDir, cliflags.TempDir, "")
 	}
 
 	for _, cmd := range certCmds {
@@ -87,6 +87,24 @@ func TestSQLMemoryPoolFlagValue(t *testing.T) {
 	}
 }
 
+func TestTempStorageFlagValues(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	f := startCmd.Flags()
+	args := []string{"--max-disk-temp-storage", "1GB", "--temp-dir", "/mnt/tmp"}
+	if err := f.Parse(args); err != nil {
+		t.Fatal(err)
+	}
+
+	const expectedTempStorageSize = 1000 * 1000 * 1000
+	if expectedTempStorageSize != serverCfg.TempStorageMaxSizeBytes {
+		t.Errorf("expected %d, but got %d", expectedTempStorageSize, serverCfg.TempStorageMaxSizeBytes)
+	}
+	if serverCfg.TempDir != "/mnt/tmp" {
+		t.Errorf("expected temp dir %q, but got %q", "/mnt/tmp", serverCfg.TempDir)
+	}
+}
+
 func TestClockOffsetFlagValue(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 
@@ -297,6 +297,14 @@ func runStart(cmd *cobra.Command, args []string) error {
 	serverCfg.SSLCertsDir = serverSSLCertsDir
 	serverCfg.User = security.NodeUser
 
+	// Apply the flags of enterprise features, such as encryption at rest, to
+	// the stores. They are only registered in CCL builds.
+	for _, fn := range storeSpecsHooks {
+		if err := fn(serverCfg.Stores); err != nil {
+			return err
+		}
+	}
+
 	signalCh := make(chan os.Signal, 1)
 	signal.Notify(signalCh, syscall.SIGINT, syscall.SIGTERM, syscall.SIGQUIT)
 
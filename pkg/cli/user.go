@@ -70,7 +70,7 @@ func runLsUsers(cmd *cobra.Command, args []string) error {
 	}
 	defer conn.Close()
 	return runQueryAndFormatResults(conn, os.Stdout,
-		makeQuery(`SELECT username FROM system.users`), cliCtx.tableDisplayFormat)
+		makeQuery(`SELECT username FROM system.users WHERE "isRole" = false`), cliCtx.tableDisplayFormat)
 }
 
 // A rmUserCmd command removes the user for the specified username.
@@ -93,7 +93,7 @@ func runRmUser(cmd *cobra.Command, args []string) error {
 	}
 	defer conn.Close()
 	return runQueryAndFormatResults(conn, os.Stdout,
-		makeQuery(`DELETE FROM system.users WHERE username=$1`, args[0]),
+		makeQuery(`DELETE FROM system.users WHERE username=$1 AND "isRole" = false`, args[0]),
 		cliCtx.tableDisplayFormat)
 }
 
@@ -113,16 +113,47 @@ func queryZone(conn *sqlConn, id sqlbase.ID) (config.ZoneConfig, bool, error) {
 	return zone, true, unmarshalProto(vals[0], &zone)
 }
 
+// queryZonePath returns the zone config that applies to the last ID in path,
+// along with the ID of the object it belongs to. Placeholder zone configs,
+// which only store subzones, are skipped.
 func queryZonePath(conn *sqlConn, path []sqlbase.ID) (sqlbase.ID, config.ZoneConfig, error) {
 	for i := len(path) - 1; i >= 0; i-- {
 		zone, found, err := queryZone(conn, path[i])
-		if err != nil || found {
-			return path[i], zone, err
+		if err != nil {
+			return 0, config.ZoneConfig{}, err
+		}
+		if found && !zone.IsSubzonePlaceholder() {
+			return path[i], zone, nil
 		}
 	}
 	return 0, config.ZoneConfig{}, nil
 }
 
+func queryTableDescriptor(conn *sqlConn, id sqlbase.ID) (*sqlbase.TableDescriptor, error) {
+	rows, err := makeQuery(`SELECT descriptor FROM system.descriptor WHERE id = $1`, id)(conn)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	if len(rows.Columns()) != 1 {
+		return nil, fmt.Errorf("unexpected result columns: %d", len(rows.Columns()))
+	}
+	vals := make([]driver.Value, 1)
+	if err := rows.Next(vals); err != nil {
+		return nil, err
+	}
+	desc := &sqlbase.Descriptor{}
+	if err := unmarshalProto(vals[0], desc); err != nil {
+		return nil, err
+	}
+	tableDesc := desc.GetTable()
+	if tableDesc == nil {
+		return nil, fmt.Errorf("descriptor %d is not a table", id)
+	}
+	return tableDesc, nil
+}
+
 func queryDescriptors(conn *sqlConn) (map[sqlbase.ID]*sqlbase.Descriptor, error) {
 	rows, err := makeQuery(`SELECT descriptor FROM system.descriptor`)(conn)
 	if err != nil {
@@ -226,13 +257,101 @@ func parseZoneName(s string) ([]string, error) {
 	return names, nil
 }
 
+// A zoneSpecifier identifies the database, table, index or partition a zone
+// config applies to. The zone configs of indexes and partitions are stored as
+// subzones of the zone config of their table.
+type zoneSpecifier struct {
+	// names is the database or table name, as returned by parseZoneName.
+	names []string
+	// At most one of index or partition is set, for subzones.
+	index     string
+	partition string
+}
+
+func (z zoneSpecifier) isSubzone() bool {
+	return z.index != "" || z.partition != ""
+}
+
+// parseZoneSpecifier parses a zone name as accepted by parseZoneName, the name
+// of an index (<database.table@index>) or the name of a partition
+// (<database.table.partition>).
+func parseZoneSpecifier(s string) (zoneSpecifier, error) {
+	var z zoneSpecifier
+	// TODO(dan): like parseZoneName, this does not handle names that contain
+	// "@" or "." and need to be escaped.
+	if i := strings.LastIndex(s, "@"); i >= 0 {
+		s, z.index = s[:i], s[i+1:]
+		if z.index == "" {
+			return zoneSpecifier{}, fmt.Errorf("malformed name: %s", s)
+		}
+	} else if parts := strings.Split(s, "."); len(parts) == 3 {
+		s, z.partition = strings.Join(parts[:2], "."), parts[2]
+	}
+	var err error
+	if z.names, err = parseZoneName(s); err != nil {
+		return zoneSpecifier{}, err
+	}
+	if z.isSubzone() && len(z.names) != 2 {
+		return zoneSpecifier{}, fmt.Errorf("malformed name: %s; indexes and partitions "+
+			"must be qualified with a database and table name", s)
+	}
+	return z, nil
+}
+
+// resolveSubzone returns the descriptor of the table containing the index or
+// partition identified by z, along with the index ID and partition name of
+// its subzone.
+func resolveSubzone(
+	conn *sqlConn, z zoneSpecifier, tableID sqlbase.ID,
+) (*sqlbase.TableDescriptor, uint32, string, error) {
+	tableDesc, err := queryTableDescriptor(conn, tableID)
+	if err != nil {
+		return nil, 0, "", err
+	}
+	if z.index != "" {
+		normName := parser.Name(z.index).Normalize()
+		for _, index := range tableDesc.AllNonDropIndexes() {
+			if parser.ReNormalizeName(index.Name) == normName {
+				return tableDesc, uint32(index.ID), "", nil
+			}
+		}
+		return nil, 0, "", fmt.Errorf("index %q does not exist", z.index)
+	}
+	index := tableDesc.FindIndexByPartitionName(z.partition)
+	if index == nil {
+		return nil, 0, "", fmt.Errorf("partition %q does not exist", z.partition)
+	}
+	// Use the name of the partition as it appears in the descriptor, which is
+	// what the subzone spans are generated from.
+	normName := parser.Name(z.partition).Normalize()
+	for _, name := range index.Partitioning.PartitionNames() {
+		if parser.ReNormalizeName(name) == normName {
+			return tableDesc, uint32(index.ID), name, nil
+		}
+	}
+	return nil, 0, "", fmt.Errorf("partition %q does not exist", z.partition)
+}
+
+// subzoneName returns the name of a subzone of the table with the given name,
+// as accepted by parseZoneSpecifier.
+func subzoneName(tableName string, tableDesc *sqlbase.TableDescriptor, s config.Subzone) string {
+	if s.PartitionName != "" {
+		return tableName + "." + parser.Name(s.PartitionName).String()
+	}
+	index, err := tableDesc.FindIndexByID(sqlbase.IndexID(s.IndexID))
+	if err != nil {
+		return fmt.Sprintf("%s@[%d]", tableName, s.IndexID)
+	}
+	return tableName + "@" + parser.Name(index.Name).String()
+}
+
 // A getZoneCmd command displays a zone config.
 var getZoneCmd = &cobra.Command{
-	Use:   "get [options] <database[.table]>",
+	Use:   "get [options] <database[.table[.partition]]|database.table@index>",
 	Short: "fetches and displays the zone config",
 	Long: `
-Fetches and displays the zone configuration for the specified database or
-table.
+Fetches and displays the zone configuration for the specified database, table,
+index or partition.
 `,
 	RunE: MaybeDecorateGRPCError(runGetZone),
 }
@@ -244,10 +363,11 @@ func runGetZone(cmd *cobra.Command, args []string) error {
 		return usageAndError(cmd)
 	}
 
-	names, err := parseZoneName(args[0])
+	zs, err := parseZoneSpecifier(args[0])
 	if err != nil {
 		return err
 	}
+	names := zs.names
 
 	conn, err := getPasswordAndMakeSQLClient()
 	if err != nil {
@@ -264,6 +384,29 @@ func runGetZone(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	if zs.isSubzone() {
+		tableID := path[len(path)-1]
+		tableDesc, indexID, partition, err := resolveSubzone(conn, zs, tableID)
+		if err != nil {
+			return err
+		}
+		tableZone, found, err := queryZone(conn, tableID)
+		if err != nil {
+			return err
+		}
+		if found {
+			if subzone := tableZone.GetSubzone(indexID, partition); subzone != nil {
+				fmt.Println(subzoneName(strings.Join(names, "."), tableDesc, *subzone))
+				res, err := yaml.Marshal(subzone.Config)
+				if err != nil {
+					return err
+				}
+				fmt.Print(string(res))
+				return nil
+			}
+		}
+	}
+
 	id, zone, err := queryZonePath(conn, path)
 	if err != nil {
 		return err
@@ -339,7 +482,8 @@ func runLsZones(cmd *cobra.Command, args []string) error {
 			continue
 		}
 		var name string
-		if tableDesc := desc.GetTable(); tableDesc != nil {
+		tableDesc := desc.GetTable()
+		if tableDesc != nil {
 			dbDesc, ok := descs[tableDesc.ParentID]
 			if !ok {
 				continue
@@ -347,7 +491,15 @@ func runLsZones(cmd *cobra.Command, args []string) error {
 			name = parser.Name(dbDesc.GetName()).String() + "."
 		}
 		name += parser.Name(desc.GetName()).String()
-		output = append(output, name)
+		zone := zones[id]
+		if !zone.IsSubzonePlaceholder() {
+			output = append(output, name)
+		}
+		if tableDesc != nil {
+			for _, s := range zone.Subzones {
+				output = append(output, subzoneName(name, tableDesc, s))
+			}
+		}
 	}
 
 	for id, zoneName := range specialZonesByID {
@@ -366,10 +518,11 @@ func runLsZones(cmd *cobra.Command, args []string) error {
 
 // A rmZoneCmd command removes a zone config.
 var rmZoneCmd = &cobra.Command{
-	Use:   "rm [options] <database[.table]>",
+	Use:   "rm [options] <database[.table[.partition]]|database.table@index>",
 	Short: "remove a zone config",
 	Long: `
-Remove an existing zone config for the specified database or table.
+Remove an existing zone config for the specified database, table, index or
+partition.
 `,
 	RunE: MaybeDecorateGRPCError(runRmZone),
 }
@@ -379,7 +532,7 @@ func runRmZone(cmd *cobra.Command, args []string) error {
 		return usageAndError(cmd)
 	}
 
-	names, err := parseZoneName(args[0])
+	zs, err := parseZoneSpecifier(args[0])
 	if err != nil {
 		return err
 	}
@@ -391,7 +544,7 @@ func runRmZone(cmd *cobra.Command, args []string) error {
 	defer conn.Close()
 
 	return conn.ExecTxn(func(conn *sqlConn) error {
-		path, err := queryDescriptorIDPath(conn, names)
+		path, err := queryDescriptorIDPath(conn, zs.names)
 		if err != nil {
 			if err == io.EOF {
 				fmt.Printf("%s not found\n", args[0])
@@ -404,27 +557,54 @@ func runRmZone(cmd *cobra.Command, args []string) error {
 			return fmt.Errorf("unable to remove special zone %s", args[0])
 		}
 
-		if err := runQueryAndFormatResults(conn, os.Stdout,
-			makeQuery(`DELETE FROM system.zones WHERE id=$1`, id), cliCtx.tableDisplayFormat); err != nil {
+		zone, found, err := queryZone(conn, id)
+		if err != nil {
 			return err
 		}
+		if zs.isSubzone() {
+			tableDesc, indexID, partition, err := resolveSubzone(conn, zs, id)
+			if err != nil {
+				return err
+			}
+			if !found || !zone.DeleteSubzone(indexID, partition) {
+				fmt.Printf("%s has no zone config\n", args[0])
+				return nil
+			}
+			zone.SubzoneSpans = sqlbase.GenerateSubzoneSpans(tableDesc, zone.Subzones)
+		} else if found && len(zone.Subzones) > 0 {
+			// Keep the subzones of the table in a placeholder zone config.
+			zone = config.ZoneConfig{Subzones: zone.Subzones, SubzoneSpans: zone.SubzoneSpans}
+		} else {
+			zone = config.ZoneConfig{}
+		}
 
-		return nil
+		if zone.NumReplicas == 0 && len(zone.Subzones) == 0 {
+			return runQueryAndFormatResults(conn, os.Stdout,
+				makeQuery(`DELETE FROM system.zones WHERE id=$1`, id), cliCtx.tableDisplayFormat)
+		}
+		buf, err := protoutil.Marshal(&zone)
+		if err != nil {
+			return err
+		}
+		return runQueryAndFormatResults(conn, os.Stdout,
+			makeQuery(`UPSERT INTO system.zones (id, config) VALUES ($1, $2)`, id, buf),
+			cliCtx.tableDisplayFormat)
 	})
 }
 
 // A setZoneCmd command creates a new or updates an existing zone config.
 var setZoneCmd = &cobra.Command{
-	Use:   "set [options] <database[.table]> <zone-config>",
+	Use:   "set [options] <database[.table[.partition]]|database.table@index> <zone-config>",
 	Short: "create or update zone config for object ID",
 	Long: `
-Create or update the zone config for the specified database or table to the
-specified zone-config.
+Create or update the zone config for the specified database, table, index or
+partition to the specified zone-config.
 
 The zone config format has the following YAML schema:
 
   num_replicas: <num>
   constraints: [comma-separated attribute list]
+  lease_preferences: [[comma-separated attribute list], ...]
   range_min_bytes: <size-in-bytes>
   range_max_bytes: <size-in-bytes>
   gc:
@@ -436,8 +616,12 @@ num_replicas: 3
 constraints: [ssd, -mem]
 EOF
 
+The leaseholder of each range is placed on a store satisfying the first lease
+preference that is satisfied by any live replica of the range, e.g.:
+lease_preferences: [[+region=us-east], [+region=us-west]]
+
 Note that the specified zone config is merged with the existing zone config for
-the database or table.
+the database, table, index or partition.
 `,
 	RunE: MaybeDecorateGRPCError(runSetZone),
 }
@@ -474,13 +658,13 @@ func runSetZone(cmd *cobra.Command, args []string) error {
 	}
 	defer conn.Close()
 
-	names, err := parseZoneName(args[0])
+	zs, err := parseZoneSpecifier(args[0])
 	if err != nil {
 		return err
 	}
 
 	return conn.ExecTxn(func(conn *sqlConn) error {
-		path, err := queryDescriptorIDPath(conn, names)
+		path, err := queryDescriptorIDPath(conn, zs.names)
 		if err != nil {
 			if err == io.EOF {
 				fmt.Printf("%s not found\n", args[0])
@@ -494,10 +678,34 @@ func runSetZone(cmd *cobra.Command, args []string) error {
 				"try setting your config on the entire \"system\" database instead")
 		}
 
+		id := path[len(path)-1]
 		_, zone, err := queryZonePath(conn, path)
 		if err != nil {
 			return err
 		}
+		ownZone, found, err := queryZone(conn, id)
+		if err != nil {
+			return err
+		}
+
+		var tableDesc *sqlbase.TableDescriptor
+		var subzone config.Subzone
+		if zs.isSubzone() {
+			tableDesc, subzone.IndexID, subzone.PartitionName, err = resolveSubzone(conn, zs, id)
+			if err != nil {
+				return err
+			}
+			// Merge onto the zone config that currently applies to the index or
+			// partition, which never has subzones of its own.
+			if s := ownZone.GetSubzone(subzone.IndexID, subzone.PartitionName); found && s != nil {
+				zone = s.Config
+			}
+			zone.Subzones, zone.SubzoneSpans = nil, nil
+		} else if found {
+			// The subzones of the object are not affected by changes to its own zone
+			// config.
+			zone.Subzones, zone.SubzoneSpans = ownZone.Subzones, ownZone.SubzoneSpans
+		}
 		// Convert it to proto and marshal it again to put into the table. This is a
 		// bit more tedious than taking protos directly, but yaml is a more widely
 		// understood format.
@@ -515,12 +723,22 @@ func runSetZone(cmd *cobra.Command, args []string) error {
 			return err
 		}
 
-		buf, err := protoutil.Marshal(&zone)
+		newZone := zone
+		if zs.isSubzone() {
+			// The zone config of the index or partition is stored as a subzone of
+			// the table's zone config, which is a placeholder if the table does not
+			// have one of its own.
+			newZone = ownZone
+			subzone.Config = zone
+			newZone.SetSubzone(subzone)
+			newZone.SubzoneSpans = sqlbase.GenerateSubzoneSpans(tableDesc, newZone.Subzones)
+		}
+
+		buf, err := protoutil.Marshal(&newZone)
 		if err != nil {
 			return fmt.Errorf("unable to parse zone config file %q: %s", args[1], err)
 		}
 
-		id := path[len(path)-1]
 		_, _, _, err = runQuery(conn, makeQuery(
 			`UPSERT INTO system.zones (id, config) VALUES ($1, $2)`,
 			id, buf), false)
@@ -130,6 +130,24 @@ func (c *Constraints) UnmarshalYAML(unmarshal func(interface{}) error) error {
 	return nil
 }
 
+var _ yaml.Marshaler = LeasePreference{}
+var _ yaml.Unmarshaler = &LeasePreference{}
+
+// MarshalYAML implements yaml.Marshaler.
+func (l LeasePreference) MarshalYAML() (interface{}, error) {
+	return Constraints{Constraints: l.Constraints}.MarshalYAML()
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (l *LeasePreference) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var c Constraints
+	if err := c.UnmarshalYAML(unmarshal); err != nil {
+		return err
+	}
+	l.Constraints = c.Constraints
+	return nil
+}
+
 // DefaultZoneConfig is the default zone configuration used when no custom
 // config has been specified.
 func DefaultZoneConfig() ZoneConfig {
@@ -170,22 +188,139 @@ func (z ZoneConfig) Validate() error {
 		return fmt.Errorf("RangeMinBytes %d is greater than or equal to RangeMaxBytes %d",
 			z.RangeMinBytes, z.RangeMaxBytes)
 	}
+	for _, l := range z.LeasePreferences {
+		if len(l.Constraints) == 0 {
+			return fmt.Errorf("every lease preference must include at least one constraint")
+		}
+	}
+	for _, s := range z.Subzones {
+		if len(s.Config.Subzones) > 0 || len(s.Config.SubzoneSpans) > 0 {
+			return fmt.Errorf("subzones cannot have subzones of their own")
+		}
+		if err := s.Config.Validate(); err != nil {
+			return err
+		}
+	}
+	for _, span := range z.SubzoneSpans {
+		if span.SubzoneIndex < 0 || int(span.SubzoneIndex) >= len(z.Subzones) {
+			return fmt.Errorf("subzone span refers to nonexistent subzone %d", span.SubzoneIndex)
+		}
+	}
 	return nil
 }
 
+// IsSubzonePlaceholder returns whether the zone config exists only to store
+// subzones. The configuration fields (e.g., RangeMinBytes) of a placeholder
+// zone config are unset and must not be used.
+func (z ZoneConfig) IsSubzonePlaceholder() bool {
+	// A valid zone config must have at least one replica, so NumReplicas is
+	// used as a sentinel for placeholders.
+	return z.NumReplicas == 0 && len(z.Subzones) > 0
+}
+
+// GetSubzone returns the most specific Subzone that applies to the specified
+// index ID and partition, if any exists. The partition can be left
+// unspecified to get the Subzone for an entire index, if it exists.
+func (z *ZoneConfig) GetSubzone(indexID uint32, partition string) *Subzone {
+	for i := range z.Subzones {
+		s := &z.Subzones[i]
+		if s.IndexID == indexID && s.PartitionName == partition {
+			return s
+		}
+	}
+	if partition != "" {
+		return z.GetSubzone(indexID, "")
+	}
+	return nil
+}
+
+// SetSubzone installs subzone into the ZoneConfig, overwriting any existing
+// subzone with the same IndexID and PartitionName.
+//
+// The SubzoneSpans are not updated; the caller is responsible for
+// regenerating them.
+func (z *ZoneConfig) SetSubzone(subzone Subzone) {
+	for i := range z.Subzones {
+		s := &z.Subzones[i]
+		if s.IndexID == subzone.IndexID && s.PartitionName == subzone.PartitionName {
+			s.Config = subzone.Config
+			return
+		}
+	}
+	z.Subzones = append(z.Subzones, subzone)
+}
+
+// DeleteSubzone removes the subzone with the specified index ID and partition
+// name. It returns whether a subzone was removed.
+//
+// The SubzoneSpans are not updated; the caller is responsible for
+// regenerating them.
+func (z *ZoneConfig) DeleteSubzone(indexID uint32, partition string) bool {
+	for i, s := range z.Subzones {
+		if s.IndexID == indexID && s.PartitionName == partition {
+			z.Subzones = append(z.Subzones[:i], z.Subzones[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// GetSubzoneForKeySuffix returns the subzone covering the key with the given
+// suffix, that is, the key with its table prefix removed, or nil if the key is
+// not covered by a subzone.
+func (z *ZoneConfig) GetSubzoneForKeySuffix(keySuffix []byte) *Subzone {
+	// SubzoneSpans are sorted and non-overlapping, so find the first span
+	// ending after the key and check whether it also starts at or before it.
+	i := sort.Search(len(z.SubzoneSpans), func(i int) bool {
+		return bytes.Compare(keySuffix, z.SubzoneSpans[i].endKey()) < 0
+	})
+	if i == len(z.SubzoneSpans) {
+		return nil
+	}
+	span := z.SubzoneSpans[i]
+	if bytes.Compare(span.Key, keySuffix) > 0 {
+		return nil
+	}
+	return &z.Subzones[span.SubzoneIndex]
+}
+
+// endKey returns the exclusive end of the span.
+func (s SubzoneSpan) endKey() roachpb.Key {
+	if len(s.EndKey) == 0 {
+		return s.Key.PrefixEnd()
+	}
+	return s.EndKey
+}
+
+// MakeZoneKey returns the key for 'id's entry in the system.zones table.
+func MakeZoneKey(id uint32) roachpb.Key {
+	k := keys.MakeTablePrefix(keys.ZonesTableID)
+	k = encoding.EncodeUvarintAscending(k, keys.ZonesTablePrimaryIndexID)
+	k = encoding.EncodeUvarintAscending(k, uint64(id))
+	return keys.MakeFamilyKey(k, keys.ZonesTableConfigColumnID)
+}
+
 // ObjectIDForKey returns the object ID (table or database) for 'key',
 // or (_, false) if not within the structured key space.
 func ObjectIDForKey(key roachpb.RKey) (uint32, bool) {
+	id, _, ok := DecodeObjectID(key)
+	return id, ok
+}
+
+// DecodeObjectID decodes the object ID (table or database) from the front of
+// 'key'. It returns the ID, the remainder of the key and whether the key was
+// within the structured key space.
+func DecodeObjectID(key roachpb.RKey) (uint32, []byte, bool) {
 	if key.Equal(roachpb.RKeyMax) {
-		return 0, false
+		return 0, nil, false
 	}
 	if encoding.PeekType(key) != encoding.Int {
 		// TODO(marc): this should eventually return SystemDatabaseID.
-		return 0, false
+		return 0, nil, false
 	}
 	// Consume first encoded int.
-	_, id64, err := encoding.DecodeUvarintAscending(key)
-	return uint32(id64), err == nil
+	rem, id64, err := encoding.DecodeUvarintAscending(key)
+	return uint32(id64), rem, err == nil
 }
 
 // Equal checks for equality.
@@ -345,29 +480,45 @@ func (s SystemConfig) GetLargestObjectID(maxID uint32) (uint32, error) {
 
 // GetZoneConfigForKey looks up the zone config for the range containing 'key'.
 // It is the caller's responsibility to ensure that the range does not need to be split.
+//
+// If the key is covered by a subzone of its table (i.e. an index or a
+// partition of an index with its own zone config), the subzone's config is
+// returned.
 func (s SystemConfig) GetZoneConfigForKey(key roachpb.RKey) (ZoneConfig, error) {
-	objectID, ok := ObjectIDForKey(key)
+	objectID, keySuffix, ok := DecodeObjectID(key)
 	if !ok {
 		// Not in the structured data namespace.
 		objectID = keys.RootNamespaceID
+		keySuffix = nil
 	} else if objectID <= keys.MaxReservedDescID {
 		// For now, you can only set a zone config on the system database as a whole,
 		// not on any of its constituent tables. This is largely because all the
 		// "system config" tables are colocated in the same range by default and
 		// thus couldn't be managed separately.
 		objectID = keys.SystemDatabaseID
+		keySuffix = nil
 	}
 
 	// Special-case known system ranges to their special zone configs.
 	if key.Equal(roachpb.RKeyMin) || bytes.HasPrefix(key, keys.Meta1Prefix) || bytes.HasPrefix(key, keys.Meta2Prefix) {
 		objectID = keys.MetaRangesID
+		keySuffix = nil
 	} else if bytes.HasPrefix(key, keys.TimeseriesPrefix) {
 		objectID = keys.TimeseriesRangesID
+		keySuffix = nil
 	} else if bytes.HasPrefix(key, keys.SystemPrefix) {
 		objectID = keys.SystemRangesID
+		keySuffix = nil
 	}
 
-	return s.getZoneConfigForID(objectID)
+	zone, err := s.getZoneConfigForID(objectID)
+	if err != nil {
+		return ZoneConfig{}, err
+	}
+	if subzone := zone.GetSubzoneForKeySuffix(keySuffix); subzone != nil {
+		return subzone.Config, nil
+	}
+	return zone, nil
 }
 
 // getZoneConfigForID looks up the zone config for the object (table or database)
@@ -450,7 +601,12 @@ func (s SystemConfig) ComputeSplitKey(startKey, endKey roachpb.RKey) roachpb.RKe
 		startID = keys.MaxSystemConfigDescID + 1
 	} else {
 		// The start key is either already a split key, or after the split
-		// key for its ID. We can skip straight to the next one.
+		// key for its ID. The table may still need to be split at the
+		// boundaries of its subzones, but otherwise we can skip straight to the
+		// next one.
+		if splitKey := s.findSubzoneSplitKey(startID, startKey, endKey); splitKey != nil {
+			return splitKey
+		}
 		startID++
 	}
 
@@ -501,6 +657,37 @@ func (s SystemConfig) ComputeSplitKey(startKey, endKey roachpb.RKey) roachpb.RKe
 	return findSplitKey(startID, endID)
 }
 
+// findSubzoneSplitKey returns the first boundary of a subzone span of the table
+// with the given ID that lies strictly within [startKey, endKey), or nil if
+// there is none.
+func (s SystemConfig) findSubzoneSplitKey(id uint32, startKey, endKey roachpb.RKey) roachpb.RKey {
+	zoneVal := s.GetValue(MakeZoneKey(id))
+	if zoneVal == nil {
+		return nil
+	}
+	var zone ZoneConfig
+	if err := zoneVal.GetProto(&zone); err != nil {
+		log.Errorf(context.TODO(), "unable to decode zone config for ID %d: %s", id, err)
+		return nil
+	}
+	tablePrefix := keys.MakeTablePrefix(id)
+	for _, span := range zone.SubzoneSpans {
+		// The spans are sorted, so their boundaries are visited in ascending
+		// order.
+		for _, boundary := range []roachpb.Key{span.Key, span.endKey()} {
+			key := make(roachpb.RKey, 0, len(tablePrefix)+len(boundary))
+			key = append(append(key, tablePrefix...), boundary...)
+			if !key.Less(endKey) {
+				return nil
+			}
+			if startKey.Less(key) {
+				return key
+			}
+		}
+	}
+	return nil
+}
+
 // NeedsSplit returns whether the range [startKey, endKey) needs a split due
 // to zone configs.
 func (s SystemConfig) NeedsSplit(startKey, endKey roachpb.RKey) bool {
@@ -425,6 +425,159 @@ func TestGetZoneConfigForKey(t *testing.T) {
 	}
 }
 
+// subzoneTestZone returns a table zone config with subzones for index 1,
+// partition "p1" of index 1 (covering keys /1/1 through /1/3) and index 2.
+func subzoneTestZone() config.ZoneConfig {
+	indexKey := func(vals ...uint64) roachpb.Key {
+		var k roachpb.Key
+		for _, v := range vals {
+			k = encoding.EncodeUvarintAscending(k, v)
+		}
+		return k
+	}
+	return config.ZoneConfig{
+		NumReplicas: 1,
+		Subzones: []config.Subzone{
+			{IndexID: 1, Config: config.ZoneConfig{NumReplicas: 2}},
+			{IndexID: 1, PartitionName: "p1", Config: config.ZoneConfig{NumReplicas: 3}},
+			{IndexID: 2, Config: config.ZoneConfig{NumReplicas: 4}},
+		},
+		SubzoneSpans: []config.SubzoneSpan{
+			{Key: indexKey(1), EndKey: indexKey(1, 1), SubzoneIndex: 0},
+			{Key: indexKey(1, 1), EndKey: indexKey(1, 3), SubzoneIndex: 1},
+			{Key: indexKey(1, 3), EndKey: indexKey(2), SubzoneIndex: 0},
+			{Key: indexKey(2), SubzoneIndex: 2},
+		},
+	}
+}
+
+func TestGetZoneConfigForKeySubzones(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	const tableID = keys.MaxReservedDescID + 1
+	key := func(vals ...uint64) roachpb.RKey {
+		k := keys.MakeTablePrefix(tableID)
+		for _, v := range vals {
+			k = encoding.EncodeUvarintAscending(k, v)
+		}
+		return k
+	}
+
+	testCases := []struct {
+		key         roachpb.RKey
+		numReplicas int32
+	}{
+		{key(), 1},
+		{key(1), 2},
+		{key(1, 0), 2},
+		{key(1, 1), 3},
+		{key(1, 2, 7), 3},
+		{key(1, 3), 2},
+		{key(2), 4},
+		{key(2, 5), 4},
+		{key(3), 1},
+	}
+
+	originalZoneConfigHook := config.ZoneConfigHook
+	defer func() {
+		config.ZoneConfigHook = originalZoneConfigHook
+	}()
+	config.ZoneConfigHook = func(_ config.SystemConfig, id uint32) (config.ZoneConfig, bool, error) {
+		if id == tableID {
+			return subzoneTestZone(), true, nil
+		}
+		return config.ZoneConfig{}, false, nil
+	}
+	var cfg config.SystemConfig
+	for tcNum, tc := range testCases {
+		zone, err := cfg.GetZoneConfigForKey(tc.key)
+		if err != nil {
+			t.Fatalf("#%d: GetZoneConfigForKey(%v) got error: %v", tcNum, tc.key, err)
+		}
+		if zone.NumReplicas != tc.numReplicas {
+			t.Errorf("#%d: GetZoneConfigForKey(%v) got %d replicas; want %d",
+				tcNum, tc.key, zone.NumReplicas, tc.numReplicas)
+		}
+	}
+}
+
+func TestComputeSplitKeySubzones(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	const start = keys.MaxReservedDescID + 1
+	key := func(tableID uint32, vals ...uint64) roachpb.RKey {
+		k := keys.MakeTablePrefix(tableID)
+		for _, v := range vals {
+			k = encoding.EncodeUvarintAscending(k, v)
+		}
+		return k
+	}
+
+	zone := subzoneTestZone()
+	var zoneVal roachpb.Value
+	if err := zoneVal.SetProto(&zone); err != nil {
+		t.Fatal(err)
+	}
+	values := append(sqlbase.MakeMetadataSchema().GetInitialValues(),
+		descriptor(start), descriptor(start+1),
+		roachpb.KeyValue{Key: config.MakeZoneKey(start), Value: zoneVal})
+	sort.Sort(roachpb.KeyValueByKey(values))
+	cfg := config.SystemConfig{Values: values}
+
+	tableSplit := roachpb.RKey(keys.MakeRowSentinelKey(keys.MakeTablePrefix(start + 1)))
+	testCases := []struct {
+		start, end roachpb.RKey
+		split      roachpb.RKey
+	}{
+		{key(start), key(start + 2), key(start, 1)},
+		{key(start, 1), key(start + 2), key(start, 1, 1)},
+		{key(start, 1, 1), key(start + 2), key(start, 1, 3)},
+		{key(start, 1, 2), key(start, 1, 3), nil},
+		{key(start, 1, 3), key(start + 2), key(start, 2)},
+		{key(start, 2), key(start + 2), key(start, 3)},
+		{key(start, 3), key(start + 2), tableSplit},
+		{key(start, 3), key(start + 1), nil},
+	}
+	for tcNum, tc := range testCases {
+		splitKey := cfg.ComputeSplitKey(tc.start, tc.end)
+		if !splitKey.Equal(tc.split) {
+			t.Errorf("#%d: bad split:\ngot: %v\nexpected: %v", tcNum, splitKey, tc.split)
+		}
+	}
+}
+
+func TestZoneConfigSubzones(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	zone := subzoneTestZone()
+	if s := zone.GetSubzone(1, "p1"); s == nil || s.Config.NumReplicas != 3 {
+		t.Errorf("expected subzone for partition p1, got %+v", s)
+	}
+	if s := zone.GetSubzone(1, "p2"); s == nil || s.Config.NumReplicas != 2 {
+		t.Errorf("expected subzone for index 1, got %+v", s)
+	}
+	if s := zone.GetSubzone(3, ""); s != nil {
+		t.Errorf("expected no subzone for index 3, got %+v", s)
+	}
+
+	zone.SetSubzone(config.Subzone{IndexID: 2, Config: config.ZoneConfig{NumReplicas: 5}})
+	zone.SetSubzone(config.Subzone{IndexID: 3, Config: config.ZoneConfig{NumReplicas: 6}})
+	if len(zone.Subzones) != 4 {
+		t.Fatalf("expected 4 subzones, got %d", len(zone.Subzones))
+	}
+	if s := zone.GetSubzone(2, ""); s == nil || s.Config.NumReplicas != 5 {
+		t.Errorf("expected updated subzone for index 2, got %+v", s)
+	}
+	if !zone.DeleteSubzone(3, "") || zone.DeleteSubzone(3, "") {
+		t.Errorf("expected exactly one subzone for index 3 to be deleted")
+	}
+
+	placeholder := config.ZoneConfig{Subzones: zone.Subzones}
+	if !placeholder.IsSubzonePlaceholder() || zone.IsSubzonePlaceholder() {
+		t.Errorf("unexpected IsSubzonePlaceholder results")
+	}
+}
+
 func TestZoneConfigValidate(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 
@@ -463,6 +616,24 @@ func TestZoneConfigValidate(t *testing.T) {
 			},
 			"is greater than or equal to RangeMaxBytes",
 		},
+		{
+			config.ZoneConfig{
+				NumReplicas:      1,
+				RangeMaxBytes:    config.DefaultZoneConfig().RangeMaxBytes,
+				LeasePreferences: []config.LeasePreference{{}},
+			},
+			"every lease preference must include at least one constraint",
+		},
+		{
+			config.ZoneConfig{
+				NumReplicas:   1,
+				RangeMaxBytes: config.DefaultZoneConfig().RangeMaxBytes,
+				LeasePreferences: []config.LeasePreference{
+					{Constraints: []config.Constraint{{Type: config.Constraint_REQUIRED, Value: "a"}}},
+				},
+			},
+			"",
+		},
 	}
 	for i, c := range testCases {
 		err := c.cfg.Validate()
@@ -502,6 +673,30 @@ func TestZoneConfigMarshalYAML(t *testing.T) {
 				},
 			},
 		},
+		LeasePreferences: []config.LeasePreference{
+			{
+				Constraints: []config.Constraint{
+					{
+						Type:  config.Constraint_REQUIRED,
+						Key:   "duck",
+						Value: "foo",
+					},
+				},
+			},
+			{
+				Constraints: []config.Constraint{
+					{
+						Type:  config.Constraint_PROHIBITED,
+						Key:   "duck",
+						Value: "foo",
+					},
+					{
+						Type:  config.Constraint_POSITIVE,
+						Value: "bar",
+					},
+				},
+			},
+		},
 	}
 
 	expected := `range_min_bytes: 1
@@ -510,6 +705,7 @@ gc:
   ttlseconds: 1
 num_replicas: 1
 constraints: [foo, +duck=foo, -duck=foo]
+lease_preferences: [[+duck=foo], [-duck=foo, bar]]
 `
 
 	body, err := yaml.Marshal(original)
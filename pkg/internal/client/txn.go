@@ -220,6 +220,42 @@ func (txn *Txn) OrigTimestamp() hlc.Timestamp {
 	return txn.mu.Proto.OrigTimestamp
 }
 
+// Sequence returns the sequence number of the last request sent through the
+// transaction. The writes performed after the call can be undone by passing
+// the returned value to RollbackToSequence.
+func (txn *Txn) Sequence() int32 {
+	txn.mu.Lock()
+	defer txn.mu.Unlock()
+	return txn.mu.Proto.Sequence
+}
+
+// RollbackToSequence undoes the writes performed by the transaction after
+// Sequence returned seq, which is only valid if the transaction has not
+// been restarted since. The writes are not removed immediately: their
+// sequence numbers are marked as ignored, which hides them from the
+// transaction's reads and discards them when the transaction's intents are
+// resolved.
+func (txn *Txn) RollbackToSequence(seq int32) {
+	txn.mu.Lock()
+	defer txn.mu.Unlock()
+	if seq >= txn.mu.Proto.Sequence {
+		return
+	}
+	// The new range covers every sequence number after seq, so it absorbs
+	// all the ranges it overlaps or is adjacent to. A new slice is built as
+	// the previous one may be shared with requests in flight.
+	newRange := enginepb.IgnoredSeqNumRange{Start: seq + 1, End: txn.mu.Proto.Sequence}
+	var ignored []enginepb.IgnoredSeqNumRange
+	for _, r := range txn.mu.Proto.IgnoredSeqNums {
+		if r.End+1 < newRange.Start {
+			ignored = append(ignored, r)
+		} else if r.Start < newRange.Start {
+			newRange.Start = r.Start
+		}
+	}
+	txn.mu.Proto.IgnoredSeqNums = append(ignored, newRange)
+}
+
 // AnchorKey returns the transaction's anchor key. The caller should treat the
 // returned byte slice as immutable.
 func (txn *Txn) AnchorKey() []byte {
@@ -910,6 +946,14 @@ func (txn *Txn) send(
 				"unexpected retryable error at the client.Txn level: (%T) %s",
 				pErr.GetDetail(), pErr)
 		}
+		if errTxn := pErr.GetTxn(); !ok && errTxn != nil &&
+			roachpb.TxnIDEqual(errTxn.ID, txn.mu.Proto.ID) && errTxn.Epoch == txn.mu.Proto.Epoch &&
+			txn.mu.Proto.Sequence < errTxn.Sequence {
+			// Some of the writes of the failed batch may have been performed
+			// at sequence numbers up to that of the error's txn. Account for
+			// them, so that rolling back to a savepoint undoes them as well.
+			txn.mu.Proto.Sequence = errTxn.Sequence
+		}
 		return nil, pErr
 	}
 
@@ -30,6 +30,7 @@ import (
 	"golang.org/x/sync/errgroup"
 
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/storage/engine/enginepb"
 	"github.com/cockroachdb/cockroach/pkg/testutils"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
@@ -836,6 +837,36 @@ func TestUpdateDeadlineMaybe(t *testing.T) {
 	}
 }
 
+// TestRollbackToSequence verifies that rolling back to a sequence number
+// ignores the sequence numbers used since, merging overlapping ranges.
+func TestRollbackToSequence(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	clock := hlc.NewClock(hlc.UnixNano, 0)
+	db := NewDB(newTestSender(nil), clock)
+	txn := NewTxn(db)
+
+	testCases := []struct {
+		seq, rollbackTo int32
+		expected        []enginepb.IgnoredSeqNumRange
+	}{
+		// Rolling back to the current sequence is a no-op.
+		{3, 3, nil},
+		{5, 3, []enginepb.IgnoredSeqNumRange{{Start: 4, End: 5}}},
+		{8, 6, []enginepb.IgnoredSeqNumRange{{Start: 4, End: 5}, {Start: 7, End: 8}}},
+		// Adjacent ranges are merged.
+		{10, 8, []enginepb.IgnoredSeqNumRange{{Start: 4, End: 5}, {Start: 7, End: 10}}},
+		// Overlapped ranges are absorbed.
+		{12, 2, []enginepb.IgnoredSeqNumRange{{Start: 3, End: 12}}},
+	}
+	for i, tc := range testCases {
+		txn.Proto().Sequence = tc.seq
+		txn.RollbackToSequence(tc.rollbackTo)
+		if ignored := txn.Proto().IgnoredSeqNums; !reflect.DeepEqual(tc.expected, ignored) {
+			t.Errorf("%d: expected %v, got %v", i, tc.expected, ignored)
+		}
+	}
+}
+
 // TestConcurrentTxnRequests verifies that multiple requests can be executed on
 // a transaction at the same time from multiple goroutines. It makes sure that
 // exactly one BeginTxnRequest and one EndTxnRequest are sent.
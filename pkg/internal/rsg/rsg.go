@@ -26,7 +26,9 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/internal/rsg/yacc"
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
 	"github.com/cockroachdb/cockroach/pkg/util/duration"
+	"github.com/cockroachdb/cockroach/pkg/util/ipaddr"
 	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 )
 
 // RSG is a random syntax generator.
@@ -237,6 +239,14 @@ func (r *RSG) GenerateRandomArg(typ parser.Type) string {
 	case parser.TypeInterval:
 		d := duration.Duration{Nanos: r.Int63()}
 		v = fmt.Sprintf(`'%s'`, &parser.DInterval{Duration: d})
+	case parser.TypeJSON:
+		v = fmt.Sprintf(`'{"a": %d}'`, r.Intn(100))
+	case parser.TypeUUID:
+		u := uuid.NewPopulatedUUID(r)
+		v = fmt.Sprintf(`'%s'`, u)
+	case parser.TypeINet:
+		ipAddr := ipaddr.RandIPAddr(r)
+		v = fmt.Sprintf(`'%s'`, ipAddr)
 	case parser.TypeIntArray,
 		parser.TypeStringArray,
 		parser.TypeOid,
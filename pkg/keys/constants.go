@@ -289,11 +289,13 @@ const (
 	// Reserved IDs for other system tables. If you're adding a new system table,
 	// it probably belongs here.
 	// NOTE: IDs must be <= MaxReservedDescID.
-	LeaseTableID      = 11
-	EventLogTableID   = 12
-	RangeEventTableID = 13
-	UITableID         = 14
-	JobsTableID       = 15
+	LeaseTableID           = 11
+	EventLogTableID        = 12
+	RangeEventTableID      = 13
+	UITableID              = 14
+	JobsTableID            = 15
+	RoleMembersTableID     = 19
+	TableStatisticsTableID = 20
 
 	// Reserved IDs used to refer to certain parts of the system ranges that
 	// come before the system config span and user table ranges.
@@ -302,3 +304,10 @@ const (
 	SystemRangesID     = 17
 	TimeseriesRangesID = 18
 )
+
+// IDs within the system.zones table, needed to construct zone config keys
+// outside of the sql package.
+const (
+	ZonesTablePrimaryIndexID = 1
+	ZonesTableConfigColumnID = 2
+)
@@ -587,6 +587,19 @@ func MakeRowSentinelKey(key []byte) []byte {
 	return MakeFamilyKey(key, SentinelFamilyID)
 }
 
+// SequenceIndexID is the index ID under which the value of a sequence is
+// stored. Sequences have no indexes of their own, so the ID cannot clash.
+const SequenceIndexID = 1
+
+// MakeSequenceKey returns the key used to store the value of a sequence.
+// The value is an integer incremented with Increment requests.
+func MakeSequenceKey(tableID uint32) []byte {
+	key := MakeTablePrefix(tableID)
+	key = encoding.EncodeUvarintAscending(key, SequenceIndexID) // Index id
+	key = encoding.EncodeUvarintAscending(key, 0)               // Primary key value
+	return MakeRowSentinelKey(key)
+}
+
 // EnsureSafeSplitKey transforms an SQL table key such that it is a valid split key
 // (i.e. does not occur in the middle of a row).
 func EnsureSafeSplitKey(key roachpb.Key) (roachpb.Key, error) {
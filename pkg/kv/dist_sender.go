@@ -707,6 +707,20 @@ func (ds *DistSender) divideAndSendBatchToRanges(
 			if br != nil {
 				pErr.UpdateTxn(br.Txn)
 			}
+			// Parts of the batch may have been applied at any of the
+			// sequence numbers used for it. Make sure the error's txn
+			// reflects them, so that a txn which rolls back to a savepoint
+			// after the error ignores all of them. errNo1PCTxn is shared
+			// and is handled by the caller.
+			if ba.Txn != nil && pErr != errNo1PCTxn {
+				if errTxn := pErr.GetTxn(); errTxn == nil {
+					pErr.SetTxn(ba.Txn)
+				} else if errTxn.Sequence < ba.Txn.Sequence {
+					txn := errTxn.Clone()
+					txn.Sequence = ba.Txn.Sequence
+					pErr.SetTxn(&txn)
+				}
+			}
 		}
 	}()
 
@@ -65,6 +65,28 @@ var backwardCompatibleMigrations = []migrationDescriptor{
 		name:   "enable diagnostics reporting",
 		workFn: optIntToDiagnosticsStatReporting,
 	},
+	{
+		// uuid_v4() now returns a UUID, which can't be stored in the BYTES
+		// uniqueID column without a cast.
+		name:   "cast UniqueID default to BYTES in system.eventlog",
+		workFn: eventlogUniqueIDDefaultBytes,
+	},
+	{
+		name:           "create system.role_members table",
+		workFn:         createRoleMembersTable,
+		newDescriptors: 1,
+		newRanges:      1,
+	},
+	{
+		name:   "add isRole column to system.users",
+		workFn: addUsersIsRoleColumn,
+	},
+	{
+		name:           "create system.table_statistics table",
+		workFn:         createTableStatisticsTable,
+		newDescriptors: 1,
+		newRanges:      1,
+	},
 }
 
 // migrationDescriptor describes a single migration hook that's used to modify
@@ -340,6 +362,30 @@ func eventlogUniqueIDDefault(ctx context.Context, r runner) error {
 	return err
 }
 
+// eventlogUniqueIDDefaultBytes casts the default of system.eventlog.uniqueID
+// to BYTES, since uuid_v4() now returns a UUID.
+func eventlogUniqueIDDefaultBytes(ctx context.Context, r runner) error {
+	const alterStmt = "ALTER TABLE system.eventlog ALTER COLUMN uniqueID SET DEFAULT uuid_v4()::BYTES;"
+
+	// System tables can only be modified by a privileged internal user.
+	session := r.newRootSession(ctx)
+	defer session.Finish(r.sqlExecutor)
+
+	// Retry a limited number of times because returning an error and letting
+	// the node kill itself is better than holding the migration lease for an
+	// arbitrarily long time.
+	var err error
+	for retry := retry.Start(retry.Options{MaxRetries: 5}); retry.Next(); {
+		res := r.sqlExecutor.ExecuteStatements(session, alterStmt, nil)
+		err = checkQueryResults(res.ResultList, 1)
+		if err == nil {
+			break
+		}
+		log.Warningf(ctx, "failed attempt to update system.eventlog schema: %s", err)
+	}
+	return err
+}
+
 // TODO(a-robinson): Write unit test for this.
 func createJobsTable(ctx context.Context, r runner) error {
 	// We install the table at the KV layer so that we can choose a known ID in
@@ -372,6 +418,60 @@ func createSettingsTable(ctx context.Context, r runner) error {
 	})
 }
 
+func createRoleMembersTable(ctx context.Context, r runner) error {
+	// We install the table at the KV layer so that we can choose a known ID in
+	// the reserved ID space. (The SQL layer doesn't allow this.)
+	return r.db.Txn(ctx, func(ctx context.Context, txn *client.Txn) error {
+		b := txn.NewBatch()
+		desc := sqlbase.RoleMembersTable
+		b.CPut(sqlbase.MakeNameMetadataKey(desc.GetParentID(), desc.GetName()), desc.GetID(), nil)
+		b.CPut(sqlbase.MakeDescMetadataKey(desc.GetID()), sqlbase.WrapDescriptor(&desc), nil)
+		if err := txn.SetSystemConfigTrigger(); err != nil {
+			return err
+		}
+		return txn.Run(ctx, b)
+	})
+}
+
+func addUsersIsRoleColumn(ctx context.Context, r runner) error {
+	// Fresh clusters are bootstrapped with the column, in which case this is a
+	// no-op.
+	const alterStmt = `ALTER TABLE system.users ADD COLUMN IF NOT EXISTS "isRole" BOOL NOT NULL DEFAULT false`
+
+	// System tables can only be modified by a privileged internal user.
+	session := r.newRootSession(ctx)
+	defer session.Finish(r.sqlExecutor)
+
+	// Retry a limited number of times because returning an error and letting
+	// the node kill itself is better than holding the migration lease for an
+	// arbitrarily long time.
+	var err error
+	for retry := retry.Start(retry.Options{MaxRetries: 5}); retry.Next(); {
+		res := r.sqlExecutor.ExecuteStatements(session, alterStmt, nil)
+		err = checkQueryResults(res.ResultList, 1)
+		if err == nil {
+			break
+		}
+		log.Warningf(ctx, "failed attempt to update system.users schema: %s", err)
+	}
+	return err
+}
+
+func createTableStatisticsTable(ctx context.Context, r runner) error {
+	// We install the table at the KV layer so that we can choose a known ID in
+	// the reserved ID space. (The SQL layer doesn't allow this.)
+	return r.db.Txn(ctx, func(ctx context.Context, txn *client.Txn) error {
+		b := txn.NewBatch()
+		desc := sqlbase.TableStatisticsTable
+		b.CPut(sqlbase.MakeNameMetadataKey(desc.GetParentID(), desc.GetName()), desc.GetID(), nil)
+		b.CPut(sqlbase.MakeDescMetadataKey(desc.GetID()), sqlbase.WrapDescriptor(&desc), nil)
+		if err := txn.SetSystemConfigTrigger(); err != nil {
+			return err
+		}
+		return txn.Run(ctx, b)
+	})
+}
+
 var reportingOptOut = envutil.EnvOrDefaultBool("COCKROACH_SKIP_ENABLING_DIAGNOSTIC_REPORTING", false)
 
 func optIntToDiagnosticsStatReporting(ctx context.Context, r runner) error {
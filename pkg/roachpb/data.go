@@ -722,6 +722,7 @@ func (t Transaction) Clone() Transaction {
 	// Note that we're not cloning the span keys under the assumption that the
 	// keys themselves are not mutable.
 	t.Intents = append([]Span(nil), t.Intents...)
+	t.IgnoredSeqNums = append([]enginepb.IgnoredSeqNumRange(nil), t.IgnoredSeqNums...)
 	return t
 }
 
@@ -858,6 +859,8 @@ func (t *Transaction) Restart(
 	t.WriteTooOld = false
 	t.RetryOnPush = false
 	t.Sequence = 0
+	// Sequence numbers are reused in the new epoch.
+	t.IgnoredSeqNums = nil
 }
 
 // Update ratchets priority, timestamp and original timestamp values (among
@@ -879,6 +882,12 @@ func (t *Transaction) Update(o *Transaction) {
 	}
 	if t.Epoch < o.Epoch {
 		t.Epoch = o.Epoch
+		t.IgnoredSeqNums = o.IgnoredSeqNums
+	} else if t.Epoch == o.Epoch &&
+		numIgnoredSeqNums(t.IgnoredSeqNums) < numIgnoredSeqNums(o.IgnoredSeqNums) {
+		// Rolling back to a savepoint only ever adds to the ignored sequence
+		// numbers, so the larger set is the more recent one.
+		t.IgnoredSeqNums = o.IgnoredSeqNums
 	}
 	t.Timestamp.Forward(o.Timestamp)
 	t.LastHeartbeat.Forward(o.LastHeartbeat)
@@ -909,6 +918,16 @@ func (t *Transaction) Update(o *Transaction) {
 	}
 }
 
+// numIgnoredSeqNums returns the number of sequence numbers in the given
+// ranges.
+func numIgnoredSeqNums(ranges []enginepb.IgnoredSeqNumRange) int32 {
+	var n int32
+	for _, r := range ranges {
+		n += r.End - r.Start + 1
+	}
+	return n
+}
+
 // UpgradePriority sets transaction priority to the maximum of current
 // priority and the specified minPriority. The exception is if the
 // current priority is set to the minimum, in which case the minimum
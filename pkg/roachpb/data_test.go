@@ -402,11 +402,12 @@ var nonZeroTxn = Transaction{
 			u := uuid.MakeV4()
 			return &u
 		}(),
-		Epoch:      2,
-		Timestamp:  makeTS(20, 21),
-		Priority:   957356782,
-		Sequence:   123,
-		BatchIndex: 1,
+		Epoch:          2,
+		Timestamp:      makeTS(20, 21),
+		Priority:       957356782,
+		Sequence:       123,
+		BatchIndex:     1,
+		IgnoredSeqNums: []enginepb.IgnoredSeqNumRange{{Start: 10, End: 20}},
 	},
 	Name:               "name",
 	Status:             COMMITTED,
@@ -445,6 +446,42 @@ func TestTransactionUpdate(t *testing.T) {
 	}
 }
 
+// TestTransactionUpdateIgnoredSeqNums verifies that Update keeps the most
+// recent set of ignored sequence numbers, and that they are reset on restart.
+func TestTransactionUpdateIgnoredSeqNums(t *testing.T) {
+	txn := *NewTransaction("test", Key("a"), NormalUserPriority, enginepb.SERIALIZABLE, makeTS(1, 0), 0)
+	txn.IgnoredSeqNums = []enginepb.IgnoredSeqNumRange{{Start: 3, End: 5}}
+
+	// A superset of the ignored sequence numbers replaces them.
+	o := txn.Clone()
+	o.IgnoredSeqNums = []enginepb.IgnoredSeqNumRange{{Start: 2, End: 7}}
+	txn.Update(&o)
+	if e := o.IgnoredSeqNums; !reflect.DeepEqual(e, txn.IgnoredSeqNums) {
+		t.Fatalf("expected %v, got %v", e, txn.IgnoredSeqNums)
+	}
+
+	// An older set doesn't.
+	o.IgnoredSeqNums = []enginepb.IgnoredSeqNumRange{{Start: 3, End: 5}}
+	txn.Update(&o)
+	if e := []enginepb.IgnoredSeqNumRange{{Start: 2, End: 7}}; !reflect.DeepEqual(e, txn.IgnoredSeqNums) {
+		t.Fatalf("expected %v, got %v", e, txn.IgnoredSeqNums)
+	}
+
+	// A newer epoch always wins.
+	o.Epoch++
+	o.IgnoredSeqNums = nil
+	txn.Update(&o)
+	if len(txn.IgnoredSeqNums) != 0 {
+		t.Fatalf("expected no ignored sequence numbers, got %v", txn.IgnoredSeqNums)
+	}
+
+	txn.IgnoredSeqNums = []enginepb.IgnoredSeqNumRange{{Start: 1, End: 1}}
+	txn.Restart(NormalUserPriority, 0, makeTS(2, 0))
+	if len(txn.IgnoredSeqNums) != 0 {
+		t.Fatalf("expected no ignored sequence numbers after restart, got %v", txn.IgnoredSeqNums)
+	}
+}
+
 func TestTransactionClone(t *testing.T) {
 	txn := nonZeroTxn.Clone()
 
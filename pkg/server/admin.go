@@ -654,7 +654,7 @@ func (s *adminServer) Users(
 	args := sql.SessionArgs{User: s.getUser(req)}
 	ctx, session := s.NewContextAndSessionForRPC(ctx, args)
 	defer session.Finish(s.server.sqlExecutor)
-	query := "SELECT username FROM system.users"
+	query := `SELECT username FROM system.users WHERE "isRole" = false`
 	r := s.server.sqlExecutor.ExecuteStatements(session, query, nil)
 	defer r.Close(ctx)
 	if err := s.checkQueryResults(r.ResultList, 1); err != nil {
@@ -21,6 +21,8 @@ import (
 	"io/ioutil"
 	"math"
 	"net"
+	"os"
+	"path/filepath"
 	"runtime"
 	"strconv"
 	"strings"
@@ -54,6 +56,10 @@ const (
 	defaultScanMaxIdleTime          = 200 * time.Millisecond
 	defaultMetricsSampleInterval    = 10 * time.Second
 	defaultStorePath                = "cockroach-data"
+	defaultTempStorageMaxSizeBytes  = 32 << 30  // 32 GiB
+	defaultTempStorageInMemSize     = 100 << 20 // 100 MiB
+	defaultTempStorageDirPrefix     = "cockroach-temp"
+	tempDirsRecordFilename          = "temp-dirs-record.txt"
 	defaultEventLogEnabled          = true
 
 	minimumNetworkFileDescriptors     = 256
@@ -103,6 +109,16 @@ type Config struct {
 	// used by SQL clients to store row data in server RAM.
 	SQLMemoryPoolSize int64
 
+	// TempDir is the path of the directory holding the temporary storage engine
+	// used by DistSQL processors to spill intermediate results to disk. If
+	// empty, a directory under the first store's path is used, or an in-memory
+	// engine if the first store is in memory.
+	TempDir string
+
+	// TempStorageMaxSizeBytes is the maximum number of bytes of temporary
+	// storage that DistSQL processors can use on this node.
+	TempStorageMaxSizeBytes int64
+
 	// Parsed values.
 
 	// NodeAttributes is the parsed representation of Attrs.
@@ -330,6 +346,7 @@ func MakeConfig() Config {
 		MaxOffset:                base.DefaultMaxClockOffset,
 		CacheSize:                defaultCacheSize,
 		SQLMemoryPoolSize:        defaultSQLMemoryPoolSize,
+		TempStorageMaxSizeBytes:  defaultTempStorageMaxSizeBytes,
 		ScanInterval:             defaultScanInterval,
 		ScanMaxIdleTime:          defaultScanMaxIdleTime,
 		ConsistencyCheckInterval: defaultConsistencyCheckInterval,
@@ -418,13 +435,26 @@ func (cfg *Config) CreateEngines() (Engines, error) {
 					spec.SizePercent, spec.Path, humanizeutil.IBytes(sizeInBytes), humanizeutil.IBytes(base.MinimumStoreSize))
 			}
 
-			eng, err := engine.NewRocksDB(
-				spec.Attributes,
-				spec.Path,
-				cache,
-				sizeInBytes,
-				openFileLimitPerStore,
-			)
+			var eng *engine.RocksDB
+			var err error
+			if spec.Encryption != nil {
+				eng, err = engine.NewEncryptedRocksDB(
+					spec.Attributes,
+					spec.Path,
+					cache,
+					sizeInBytes,
+					openFileLimitPerStore,
+					encryptionOptions(spec.Encryption),
+				)
+			} else {
+				eng, err = engine.NewRocksDB(
+					spec.Attributes,
+					spec.Path,
+					cache,
+					sizeInBytes,
+					openFileLimitPerStore,
+				)
+			}
 			if err != nil {
 				return Engines{}, err
 			}
@@ -442,6 +472,119 @@ func (cfg *Config) CreateEngines() (Engines, error) {
 	return enginesCopy, nil
 }
 
+// CreateTempEngine creates the engine used by DistSQL processors to store
+// temporary data that does not fit in their memory budget. The engine is
+// stored in a new directory, whose name starts with "cockroach-temp", inside
+// cfg.TempDir (or the first store's directory). The directory is removed when
+// the engine is closed. Its path is also recorded in the first store's
+// directory, so that if the node exits without closing the engine, the
+// directory is removed when the node restarts. Only directories created this
+// way are ever removed.
+func (cfg *Config) CreateTempEngine() (engine.Engine, error) {
+	var recordDir string
+	if len(cfg.Stores.Specs) > 0 && !cfg.Stores.Specs[0].InMemory {
+		recordDir = cfg.Stores.Specs[0].Path
+	}
+	parentDir := cfg.TempDir
+	if parentDir == "" {
+		if recordDir == "" {
+			return engine.NewInMem(roachpb.Attributes{}, defaultTempStorageInMemSize), nil
+		}
+		parentDir = recordDir
+	}
+
+	if recordDir != "" {
+		if err := cleanupTempDirs(recordDir); err != nil {
+			return nil, err
+		}
+	}
+	if err := os.MkdirAll(parentDir, 0755); err != nil {
+		return nil, errors.Wrapf(err, "could not create directory %s", parentDir)
+	}
+	tempDir, err := ioutil.TempDir(parentDir, defaultTempStorageDirPrefix)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not create temporary directory in %s", parentDir)
+	}
+	if recordDir != "" {
+		if err := os.MkdirAll(recordDir, 0755); err != nil {
+			return nil, errors.Wrapf(err, "could not create directory %s", recordDir)
+		}
+		recordPath := filepath.Join(recordDir, tempDirsRecordFilename)
+		if err := ioutil.WriteFile(recordPath, []byte(tempDir+"\n"), 0644); err != nil {
+			return nil, errors.Wrapf(err, "could not record temporary directory in %s", recordPath)
+		}
+	}
+
+	cache := engine.NewRocksDBCache(0)
+	defer cache.Release()
+	var eng *engine.RocksDB
+	if len(cfg.Stores.Specs) > 0 && cfg.Stores.Specs[0].Encryption != nil {
+		// Temporary files are encrypted like the store they are stored next
+		// to by default.
+		eng, err = engine.NewEncryptedRocksDB(
+			roachpb.Attributes{}, tempDir, cache, 0 /* maxSize */, engine.DefaultMaxOpenFiles,
+			encryptionOptions(cfg.Stores.Specs[0].Encryption),
+		)
+	} else {
+		eng, err = engine.NewRocksDB(
+			roachpb.Attributes{}, tempDir, cache, 0 /* maxSize */, engine.DefaultMaxOpenFiles,
+		)
+	}
+	if err != nil {
+		_ = os.RemoveAll(tempDir)
+		return nil, errors.Wrapf(err, "could not create temporary storage engine in %s", tempDir)
+	}
+	log.Infof(context.TODO(), "temporary storage engine initialized in %s", tempDir)
+	return &tempEngine{Engine: eng, dir: tempDir}, nil
+}
+
+// cleanupTempDirs removes the temporary directories recorded in the given
+// store directory by a previous run of CreateTempEngine.
+func cleanupTempDirs(recordDir string) error {
+	recordPath := filepath.Join(recordDir, tempDirsRecordFilename)
+	contents, err := ioutil.ReadFile(recordPath)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return errors.Wrapf(err, "could not read %s", recordPath)
+	}
+	for _, dir := range strings.Split(string(contents), "\n") {
+		// Guard against removing anything which was not created by
+		// CreateTempEngine, even if the record was tampered with.
+		if dir == "" || !strings.HasPrefix(filepath.Base(dir), defaultTempStorageDirPrefix) {
+			continue
+		}
+		if err := os.RemoveAll(dir); err != nil {
+			return errors.Wrapf(err, "could not remove temporary directory %s", dir)
+		}
+	}
+	return nil
+}
+
+// tempEngine is a temporary storage engine whose directory is removed when
+// it is closed.
+type tempEngine struct {
+	engine.Engine
+	dir string
+}
+
+// Close implements the engine.Engine interface.
+func (e *tempEngine) Close() {
+	e.Engine.Close()
+	if err := os.RemoveAll(e.dir); err != nil {
+		log.Warningf(context.TODO(), "could not remove temporary directory %s: %v", e.dir, err)
+	}
+}
+
+// encryptionOptions converts the encryption spec of a store to the options
+// of its engine.
+func encryptionOptions(spec *base.StoreEncryptionSpec) engine.EncryptionOptions {
+	return engine.EncryptionOptions{
+		KeyFile:    spec.KeyPath,
+		OldKeyFile: spec.OldKeyPath,
+	}
+}
+
 // InitNode parses node attributes and initializes the gossip bootstrap
 // resolvers.
 func (cfg *Config) InitNode() error {
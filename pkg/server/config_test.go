@@ -17,8 +17,11 @@
 package server
 
 import (
+	"io/ioutil"
 	"os"
+	"path/filepath"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -212,3 +215,71 @@ func TestFilterGossipBootstrapResolvers(t *testing.T) {
 		t.Fatalf("expected resolver to be %q; got %q", resolverSpecs[1], filtered[0].Addr())
 	}
 }
+
+// TestCreateTempEngine verifies that the temporary storage engine is created
+// in its own subdirectory of the temp dir, and that nothing else in the temp
+// dir is removed, either when the engine is closed or when the node restarts
+// after exiting without closing it.
+func TestCreateTempEngine(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	testDir, err := ioutil.TempDir("", "TestCreateTempEngine")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.RemoveAll(testDir) }()
+	parentDir := filepath.Join(testDir, "tmp")
+
+	// Unrelated contents of the temp dir, including a directory with the
+	// prefix of the temporary storage directories.
+	unrelated := []string{
+		filepath.Join(parentDir, "keep"),
+		filepath.Join(parentDir, "cockroach-temp-keep"),
+	}
+	for _, dir := range unrelated {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	checkExists := func(dir string, expected bool) {
+		_, err := os.Stat(dir)
+		if exists := !os.IsNotExist(err); exists != expected {
+			t.Fatalf("expected %s to exist: %t, got %t (%v)", dir, expected, exists, err)
+		}
+	}
+
+	cfg := MakeConfig()
+	cfg.TempDir = parentDir
+	cfg.Stores = base.StoreSpecList{Specs: []base.StoreSpec{{Path: filepath.Join(testDir, "store")}}}
+
+	createTempEngine := func() *tempEngine {
+		eng, err := cfg.CreateTempEngine()
+		if err != nil {
+			t.Fatal(err)
+		}
+		tempEng := eng.(*tempEngine)
+		if filepath.Dir(tempEng.dir) != parentDir ||
+			!strings.HasPrefix(filepath.Base(tempEng.dir), "cockroach-temp") {
+			t.Fatalf("unexpected temporary directory %s", tempEng.dir)
+		}
+		return tempEng
+	}
+
+	// Simulate a node exiting without removing its temporary directory.
+	eng1 := createTempEngine()
+	eng1.Engine.Close()
+	checkExists(eng1.dir, true)
+
+	// The directory of the previous run is removed on restart.
+	eng2 := createTempEngine()
+	checkExists(eng1.dir, false)
+	checkExists(eng2.dir, true)
+
+	// The directory is removed when the engine is closed.
+	eng2.Close()
+	checkExists(eng2.dir, false)
+
+	checkExists(parentDir, true)
+	for _, dir := range unrelated {
+		checkExists(dir, true)
+	}
+}
@@ -113,6 +113,9 @@ type Server struct {
 	raftTransport      *storage.RaftTransport
 	stopper            *stop.Stopper
 	sqlExecutor        *sql.Executor
+	execCfg            *sql.ExecutorConfig
+	sessionRegistry    *sql.SessionRegistry
+	jobRegistry        *sql.JobRegistry
 	leaseMgr           *sql.LeaseManager
 	engines            Engines
 	internalMemMetrics sql.MemoryMetrics
@@ -271,6 +274,27 @@ func NewServer(cfg Config, stopper *stop.Stopper) (*Server, error) {
 	s.registry.AddMetric(distSQLMetrics.CurBytesCount)
 	s.registry.AddMetric(distSQLMetrics.MaxBytesHist)
 
+	// Set up the temporary storage used by DistSQL processors to spill to disk
+	// and the monitor that caps its usage.
+	tempEngine, err := cfg.CreateTempEngine()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create temporary storage engine")
+	}
+	s.stopper.AddCloser(tempEngine)
+	tempStorageMetrics := sql.MakeMemMetrics("distsql-temp-storage", cfg.HistogramWindowInterval())
+	s.registry.AddMetric(tempStorageMetrics.CurBytesCount)
+	s.registry.AddMetric(tempStorageMetrics.MaxBytesHist)
+	tempStorageMonitor := mon.MakeMonitor(
+		"distsql-temp-storage",
+		tempStorageMetrics.CurBytesCount,
+		tempStorageMetrics.MaxBytesHist,
+		1024*1024,     /* increment */
+		math.MaxInt64, /* noteworthy */
+	)
+	tempStorageMonitor.Start(
+		context.Background(), nil, mon.MakeStandaloneBudget(s.cfg.TempStorageMaxSizeBytes),
+	)
+
 	// Set up the DistSQL server.
 	distSQLCfg := distsqlrun.ServerConfig{
 		AmbientContext: s.cfg.AmbientCtx,
@@ -284,6 +308,9 @@ func NewServer(cfg Config, stopper *stop.Stopper) (*Server, error) {
 		ParentMemoryMonitor: &rootSQLMemoryMonitor,
 		Counter:             distSQLMetrics.CurBytesCount,
 		Hist:                distSQLMetrics.MaxBytesHist,
+
+		TempStorage: tempEngine,
+		DiskMonitor: &tempStorageMonitor,
 	}
 	if s.cfg.TestingKnobs.DistSQL != nil {
 		distSQLCfg.TestingKnobs = *s.cfg.TestingKnobs.DistSQL.(*distsqlrun.TestingKnobs)
@@ -291,50 +318,12 @@ func NewServer(cfg Config, stopper *stop.Stopper) (*Server, error) {
 	s.distSQLServer = distsqlrun.NewServer(ctx, distSQLCfg)
 	distsqlrun.RegisterDistSQLServer(s.grpc, s.distSQLServer)
 
+	s.sessionRegistry = sql.MakeSessionRegistry()
+
 	// Set up admin memory metrics for use by admin SQL executors.
 	s.adminMemMetrics = sql.MakeMemMetrics("admin", cfg.HistogramWindowInterval())
 	s.registry.AddMetricStruct(s.adminMemMetrics)
 
-	// Set up Executor
-	execCfg := sql.ExecutorConfig{
-		AmbientCtx:              s.cfg.AmbientCtx,
-		ClusterID:               s.ClusterID,
-		NodeID:                  &s.nodeIDContainer,
-		DB:                      s.db,
-		Gossip:                  s.gossip,
-		DistSender:              s.distSender,
-		RPCContext:              s.rpcContext,
-		LeaseManager:            s.leaseMgr,
-		Clock:                   s.clock,
-		DistSQLSrv:              s.distSQLServer,
-		HistogramWindowInterval: s.cfg.HistogramWindowInterval(),
-		RangeDescriptorCache:    s.distSender.RangeDescriptorCache(),
-		LeaseHolderCache:        s.distSender.LeaseHolderCache(),
-	}
-	if s.cfg.TestingKnobs.SQLExecutor != nil {
-		execCfg.TestingKnobs = s.cfg.TestingKnobs.SQLExecutor.(*sql.ExecutorTestingKnobs)
-	} else {
-		execCfg.TestingKnobs = &sql.ExecutorTestingKnobs{}
-	}
-	if s.cfg.TestingKnobs.SQLSchemaChanger != nil {
-		execCfg.SchemaChangerTestingKnobs =
-			s.cfg.TestingKnobs.SQLSchemaChanger.(*sql.SchemaChangerTestingKnobs)
-	} else {
-		execCfg.SchemaChangerTestingKnobs = &sql.SchemaChangerTestingKnobs{}
-	}
-	s.sqlExecutor = sql.NewExecutor(execCfg, s.stopper)
-	s.registry.AddMetricStruct(s.sqlExecutor)
-
-	s.pgServer = pgwire.MakeServer(
-		s.cfg.AmbientCtx,
-		s.cfg.Config,
-		s.sqlExecutor,
-		&s.internalMemMetrics,
-		&rootSQLMemoryMonitor,
-		s.cfg.HistogramWindowInterval(),
-	)
-	s.registry.AddMetricStruct(s.pgServer.Metrics())
-
 	s.tsDB = ts.NewDB(s.db)
 	s.tsServer = ts.MakeServer(s.cfg.AmbientCtx, s.tsDB, s.cfg.TimeSeriesServerConfig, s.stopper)
 
@@ -390,11 +379,60 @@ func NewServer(cfg Config, stopper *stop.Stopper) (*Server, error) {
 		s.rpcContext,
 		s.node.stores,
 		s.stopper,
+		s.sessionRegistry,
 	)
 	for _, gw := range []grpcGatewayServer{s.admin, s.status, &s.tsServer} {
 		gw.RegisterService(s.grpc)
 	}
 
+	s.jobRegistry = sql.MakeJobRegistry(
+		s.cfg.AmbientCtx, s.db, s.leaseMgr, &s.nodeIDContainer, s.nodeLiveness)
+
+	// Set up Executor
+	execCfg := sql.ExecutorConfig{
+		AmbientCtx:              s.cfg.AmbientCtx,
+		ClusterID:               s.ClusterID,
+		NodeID:                  &s.nodeIDContainer,
+		DB:                      s.db,
+		Gossip:                  s.gossip,
+		DistSender:              s.distSender,
+		RPCContext:              s.rpcContext,
+		LeaseManager:            s.leaseMgr,
+		Clock:                   s.clock,
+		DistSQLSrv:              s.distSQLServer,
+		HistogramWindowInterval: s.cfg.HistogramWindowInterval(),
+		RangeDescriptorCache:    s.distSender.RangeDescriptorCache(),
+		LeaseHolderCache:        s.distSender.LeaseHolderCache(),
+		SessionRegistry:         s.sessionRegistry,
+		StatusServer:            s.status,
+		JobRegistry:             s.jobRegistry,
+		TableStatsCache:         sql.NewTableStatisticsCache(s.db, s.leaseMgr),
+	}
+	if s.cfg.TestingKnobs.SQLExecutor != nil {
+		execCfg.TestingKnobs = s.cfg.TestingKnobs.SQLExecutor.(*sql.ExecutorTestingKnobs)
+	} else {
+		execCfg.TestingKnobs = &sql.ExecutorTestingKnobs{}
+	}
+	if s.cfg.TestingKnobs.SQLSchemaChanger != nil {
+		execCfg.SchemaChangerTestingKnobs =
+			s.cfg.TestingKnobs.SQLSchemaChanger.(*sql.SchemaChangerTestingKnobs)
+	} else {
+		execCfg.SchemaChangerTestingKnobs = &sql.SchemaChangerTestingKnobs{}
+	}
+	s.execCfg = &execCfg
+	s.sqlExecutor = sql.NewExecutor(execCfg, s.stopper)
+	s.registry.AddMetricStruct(s.sqlExecutor)
+
+	s.pgServer = pgwire.MakeServer(
+		s.cfg.AmbientCtx,
+		s.cfg.Config,
+		s.sqlExecutor,
+		&s.internalMemMetrics,
+		&rootSQLMemoryMonitor,
+		s.cfg.HistogramWindowInterval(),
+	)
+	s.registry.AddMetricStruct(s.pgServer.Metrics())
+
 	return s, nil
 }
 
@@ -851,6 +889,10 @@ func (s *Server) Start(ctx context.Context) error {
 		log.Fatal(ctx, err)
 	}
 	log.Infof(ctx, "done ensuring all necessary migrations have run")
+
+	// The job registry adopts orphaned jobs, which requires the system.jobs
+	// table created by the migrations above.
+	s.jobRegistry.Start(ctx, s.stopper, s.execCfg, sql.DefaultJobAdoptInterval)
 	close(serveSQL)
 	log.Info(ctx, "serving sql connections")
 
@@ -43,6 +43,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/rpc"
 	"github.com/cockroachdb/cockroach/pkg/server/serverpb"
 	"github.com/cockroachdb/cockroach/pkg/server/status"
+	"github.com/cockroachdb/cockroach/pkg/sql"
 	"github.com/cockroachdb/cockroach/pkg/storage"
 	"github.com/cockroachdb/cockroach/pkg/util/httputil"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
@@ -94,6 +95,8 @@ type statusServer struct {
 	rpcCtx       *rpc.Context
 	stores       *storage.Stores
 	stopper      *stop.Stopper
+
+	sessionRegistry *sql.SessionRegistry
 }
 
 // newStatusServer allocates and returns a statusServer.
@@ -106,6 +109,7 @@ func newStatusServer(
 	rpcCtx *rpc.Context,
 	stores *storage.Stores,
 	stopper *stop.Stopper,
+	sessionRegistry *sql.SessionRegistry,
 ) *statusServer {
 	ambient.AddLogTag("status", nil)
 	server := &statusServer{
@@ -117,6 +121,8 @@ func newStatusServer(
 		rpcCtx:         rpcCtx,
 		stores:         stores,
 		stopper:        stopper,
+
+		sessionRegistry: sessionRegistry,
 	}
 
 	return server
@@ -742,6 +748,184 @@ func (s *statusServer) SpanStats(
 	return output, nil
 }
 
+// ListLocalSessions returns a list of SQL sessions on this node. Only root
+// may see the sessions of other users.
+func (s *statusServer) ListLocalSessions(
+	ctx context.Context, req *serverpb.ListSessionsRequest,
+) (*serverpb.ListSessionsResponse, error) {
+	sessions := s.sessionRegistry.SerializeAll(req.Username)
+	return &serverpb.ListSessionsResponse{Sessions: sessions}, nil
+}
+
+// ListSessions returns a list of SQL sessions on all live nodes in the
+// cluster. Nodes that cannot be reached are reported in the response's
+// errors rather than failing the whole request.
+func (s *statusServer) ListSessions(
+	ctx context.Context, req *serverpb.ListSessionsRequest,
+) (*serverpb.ListSessionsResponse, error) {
+	ctx = s.AnnotateCtx(ctx)
+
+	type nodeResponse struct {
+		nodeID roachpb.NodeID
+		resp   *serverpb.ListSessionsResponse
+		err    error
+	}
+
+	isLiveMap := s.nodeLiveness.GetIsLiveMap()
+	numNodes := 0
+	responses := make(chan nodeResponse)
+	nodeCtx, cancel := context.WithTimeout(ctx, base.NetworkTimeout)
+	defer cancel()
+	for nodeID, alive := range isLiveMap {
+		if !alive {
+			continue
+		}
+		nodeID := nodeID
+		if err := s.stopper.RunAsyncTask(nodeCtx, func(ctx context.Context) {
+			status, err := s.dialNode(nodeID)
+			var sessionsResponse *serverpb.ListSessionsResponse
+			if err == nil {
+				sessionsResponse, err = status.ListLocalSessions(ctx, req)
+			}
+			response := nodeResponse{
+				nodeID: nodeID,
+				resp:   sessionsResponse,
+				err:    err,
+			}
+
+			select {
+			case responses <- response:
+				// Response processed.
+			case <-ctx.Done():
+				// Context completed, response no longer needed.
+			}
+		}); err != nil {
+			return nil, err
+		}
+		numNodes++
+	}
+
+	var output serverpb.ListSessionsResponse
+	for remainingResponses := numNodes; remainingResponses > 0; remainingResponses-- {
+		select {
+		case resp := <-responses:
+			if resp.err != nil {
+				output.Errors = append(output.Errors, serverpb.ListSessionsError{
+					NodeID:  resp.nodeID,
+					Message: resp.err.Error(),
+				})
+				continue
+			}
+			output.Sessions = append(output.Sessions, resp.resp.Sessions...)
+		case <-nodeCtx.Done():
+			return nil, nodeCtx.Err()
+		}
+	}
+	return &output, nil
+}
+
+// CancelQuery cancels the query with the given ID on the given node. Only
+// root may cancel the queries of other users. If a pgwire cancel key is
+// given instead, the active queries of the session with that key are
+// canceled.
+func (s *statusServer) CancelQuery(
+	ctx context.Context, req *serverpb.CancelQueryRequest,
+) (*serverpb.CancelQueryResponse, error) {
+	ctx = s.AnnotateCtx(ctx)
+	nodeID, local, err := s.parseNodeID(req.NodeId)
+	if err != nil {
+		return nil, grpc.Errorf(codes.InvalidArgument, err.Error())
+	}
+	if !local {
+		status, err := s.dialNode(nodeID)
+		if err != nil {
+			return nil, err
+		}
+		return status.CancelQuery(ctx, req)
+	}
+
+	output := &serverpb.CancelQueryResponse{}
+	if req.CancelKey != 0 {
+		output.Canceled = s.sessionRegistry.CancelQueriesByKey(req.CancelKey)
+		return output, nil
+	}
+	output.Canceled, err = s.sessionRegistry.CancelQuery(req.QueryID, req.Username)
+	if err != nil {
+		output.Error = err.Error()
+	}
+	return output, nil
+}
+
+// CancelSession cancels the session with the given ID on the given node,
+// closing its client connection. Only root may cancel the sessions of other
+// users.
+func (s *statusServer) CancelSession(
+	ctx context.Context, req *serverpb.CancelSessionRequest,
+) (*serverpb.CancelSessionResponse, error) {
+	ctx = s.AnnotateCtx(ctx)
+	nodeID, local, err := s.parseNodeID(req.NodeId)
+	if err != nil {
+		return nil, grpc.Errorf(codes.InvalidArgument, err.Error())
+	}
+	if !local {
+		status, err := s.dialNode(nodeID)
+		if err != nil {
+			return nil, err
+		}
+		return status.CancelSession(ctx, req)
+	}
+
+	output := &serverpb.CancelSessionResponse{}
+	output.Canceled, err = s.sessionRegistry.CancelSession(req.SessionID, req.Username)
+	if err != nil {
+		output.Error = err.Error()
+	}
+	return output, nil
+}
+
+// Stores returns details for each store on the given node, including the
+// status of its encryption at rest.
+func (s *statusServer) Stores(
+	ctx context.Context, req *serverpb.StoresRequest,
+) (*serverpb.StoresResponse, error) {
+	ctx = s.AnnotateCtx(ctx)
+	nodeID, local, err := s.parseNodeID(req.NodeId)
+	if err != nil {
+		return nil, grpc.Errorf(codes.InvalidArgument, err.Error())
+	}
+	if !local {
+		status, err := s.dialNode(nodeID)
+		if err != nil {
+			return nil, err
+		}
+		return status.Stores(ctx, req)
+	}
+
+	resp := &serverpb.StoresResponse{}
+	err = s.stores.VisitStores(func(store *storage.Store) error {
+		encStatus, err := store.Engine().GetEncryptionStatus()
+		if err != nil {
+			return err
+		}
+		storeDetails := serverpb.StoreDetails{StoreID: store.Ident.StoreID}
+		if encStatus != nil {
+			storeDetails.EncryptionStatus = &serverpb.EncryptionStatus{
+				ActiveKeyID:    encStatus.ActiveKeyID,
+				TotalFiles:     encStatus.TotalFiles,
+				TotalBytes:     encStatus.TotalBytes,
+				ActiveKeyFiles: encStatus.ActiveKeyFiles,
+				ActiveKeyBytes: encStatus.ActiveKeyBytes,
+			}
+		}
+		resp.Stores = append(resp.Stores, storeDetails)
+		return nil
+	})
+	if err != nil {
+		return nil, grpc.Errorf(codes.Internal, err.Error())
+	}
+	return resp, nil
+}
+
 // jsonWrapper provides a wrapper on any slice data type being
 // marshaled to JSON. This prevents a security vulnerability
 // where a phishing attack can trick a user's browser into
@@ -453,6 +453,26 @@ func TestRangesResponse(t *testing.T) {
 	}
 }
 
+func TestStoresResponse(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	ts := startServer(t)
+	defer ts.Stopper().Stop(context.TODO())
+
+	var response serverpb.StoresResponse
+	if err := getStatusJSONProto(ts, "stores/local", &response); err != nil {
+		t.Fatal(err)
+	}
+	if len(response.Stores) != 1 {
+		t.Fatalf("expected 1 store, got %+v", response.Stores)
+	}
+	if store := response.Stores[0]; store.StoreID != 1 {
+		t.Errorf("expected store 1, got %d", store.StoreID)
+	} else if store.EncryptionStatus != nil {
+		t.Errorf("expected no encryption status for unencrypted store, got %+v",
+			store.EncryptionStatus)
+	}
+}
+
 func TestRaftDebug(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	s := startServer(t)
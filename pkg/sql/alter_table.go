@@ -56,7 +56,7 @@ func (p *planner) AlterTable(ctx context.Context, n *parser.AlterTable) (planNod
 		return nil, sqlbase.NewUndefinedTableError(tn.String())
 	}
 
-	if err := p.CheckPrivilege(tableDesc, privilege.CREATE); err != nil {
+	if err := p.CheckPrivilege(ctx, tableDesc, privilege.CREATE); err != nil {
 		return nil, err
 	}
 	return &alterTableNode{n: n, p: p, tableDesc: tableDesc}, nil
@@ -396,6 +396,24 @@ func (n *alterTableNode) Start(ctx context.Context) error {
 				return errors.Errorf("validating %s constraint %q unsupported", constraint.Kind, t.Constraint)
 			}
 
+		case *parser.AlterTableSetAudit:
+			if err := n.p.RequireSuperUser("change the auditing settings of a table"); err != nil {
+				return err
+			}
+			var mode sqlbase.TableDescriptor_AuditMode
+			switch t.Mode {
+			case parser.AuditModeDisable:
+				mode = sqlbase.TableDescriptor_DISABLED
+			case parser.AuditModeReadWrite:
+				mode = sqlbase.TableDescriptor_READWRITE
+			default:
+				return errors.Errorf("unknown audit mode: %s", t.Mode)
+			}
+			if n.tableDesc.AuditMode != mode {
+				n.tableDesc.AuditMode = mode
+				descriptorChanged = true
+			}
+
 		case parser.ColumnMutationCmd:
 			// Column mutations
 			status, i, err := n.tableDesc.FindColumnByName(t.GetColumn())
@@ -499,9 +517,7 @@ func applyColumnMutation(
 			col.DefaultExpr = nil
 		} else {
 			colDatumType := col.Type.ToDatumType()
-			if _, err := sqlbase.SanitizeVarFreeExpr(
-				t.Default, colDatumType, "DEFAULT", searchPath,
-			); err != nil {
+			if _, err := sqlbase.SanitizeDefaultExpr(t.Default, colDatumType, searchPath); err != nil {
 				return err
 			}
 			s := parser.Serialize(t.Default)
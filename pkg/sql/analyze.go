@@ -325,6 +325,11 @@ func simplifyOneAndExpr(
 		return left, right, true
 	}
 
+	if lcmp.Operator == parser.Contains || rcmp.Operator == parser.Contains {
+		// Containment doesn't combine with other comparisons.
+		return left, right, true
+	}
+
 	if lcmp.Operator == parser.IsNot || rcmp.Operator == parser.IsNot {
 		switch lcmp.Operator {
 		case parser.EQ, parser.GT, parser.GE, parser.LT, parser.LE, parser.In:
@@ -882,6 +887,11 @@ func simplifyOneOrExpr(
 		return left, right, true
 	}
 
+	if lcmp.Operator == parser.Contains || rcmp.Operator == parser.Contains {
+		// Containment doesn't combine with other comparisons.
+		return left, right, true
+	}
+
 	if lcmp.Operator == parser.IsNot || rcmp.Operator == parser.IsNot {
 		switch lcmp.Operator {
 		case parser.Is:
@@ -1429,6 +1439,13 @@ func simplifyComparisonExpr(
 			return n, true
 		case parser.NE, parser.GE, parser.LE:
 			return n, true
+		case parser.Contains:
+			// "a @> x" can be used during index selection to restrict the keys
+			// scanned in an inverted index.
+			switch left.(type) {
+			case *parser.IndexedVar:
+				return n, true
+			}
 		case parser.GT:
 			// This simplification is necessary so that subsequent transformation of
 			// > constraint to >= can use Datum.Next without concern about whether a
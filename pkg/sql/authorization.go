@@ -17,6 +17,8 @@ package sql
 import (
 	"fmt"
 
+	"golang.org/x/net/context"
+
 	"github.com/cockroachdb/cockroach/pkg/security"
 	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
@@ -26,11 +28,11 @@ import (
 type AuthorizationAccessor interface {
 	// CheckPrivilege verifies that the user has `privilege` on `descriptor`.
 	CheckPrivilege(
-		descriptor sqlbase.DescriptorProto, privilege privilege.Kind,
+		ctx context.Context, descriptor sqlbase.DescriptorProto, privilege privilege.Kind,
 	) error
 
 	// anyPrivilege verifies that the user has any privilege on `descriptor`.
-	anyPrivilege(descriptor sqlbase.DescriptorProto) error
+	anyPrivilege(ctx context.Context, descriptor sqlbase.DescriptorProto) error
 
 	// SuperUser errors if the session user is the super-user (i.e. root).
 	// Includes the named action in thhe error message.
@@ -41,20 +43,40 @@ var _ AuthorizationAccessor = &planner{}
 
 // CheckPrivilege implements the AuthorizationAccessor interface.
 func (p *planner) CheckPrivilege(
-	descriptor sqlbase.DescriptorProto, privilege privilege.Kind,
+	ctx context.Context, descriptor sqlbase.DescriptorProto, privilege privilege.Kind,
 ) error {
-	if descriptor.GetPrivileges().CheckPrivilege(p.session.User, privilege) {
+	privs := descriptor.GetPrivileges()
+	if privs.CheckPrivilege(p.session.User, privilege) {
 		return nil
 	}
+	// The user may also have the privilege through one of its roles.
+	roles, err := p.sessionUserRoles(ctx)
+	if err != nil {
+		return err
+	}
+	for role := range roles {
+		if privs.CheckPrivilege(role, privilege) {
+			return nil
+		}
+	}
 	return fmt.Errorf("user %s does not have %s privilege on %s %s",
 		p.session.User, privilege, descriptor.TypeName(), descriptor.GetName())
 }
 
 // anyPrivilege implements the AuthorizationAccessor interface.
-func (p *planner) anyPrivilege(descriptor sqlbase.DescriptorProto) error {
+func (p *planner) anyPrivilege(ctx context.Context, descriptor sqlbase.DescriptorProto) error {
 	if userCanSeeDescriptor(descriptor, p.session.User) {
 		return nil
 	}
+	roles, err := p.sessionUserRoles(ctx)
+	if err != nil {
+		return err
+	}
+	for role := range roles {
+		if userCanSeeDescriptor(descriptor, role) {
+			return nil
+		}
+	}
 	return fmt.Errorf("user %s has no privileges on %s %s",
 		p.session.User, descriptor.TypeName(), descriptor.GetName())
 }
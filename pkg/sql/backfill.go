@@ -292,7 +292,7 @@ func (sc *SchemaChanger) truncateIndexes(
 					return err
 				}
 
-				rd, err := sqlbase.MakeRowDeleter(txn, tableDesc, nil, nil, false)
+				rd, err := sqlbase.MakeRowDeleter(txn, tableDesc, nil, nil, false, nil)
 				if err != nil {
 					return err
 				}
@@ -0,0 +1,130 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/server/serverpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+)
+
+type cancelQueryNode struct {
+	p       *planner
+	queryID parser.TypedExpr
+}
+
+// CancelQuery cancels a running query, which may be running on any node of
+// the cluster.
+// Privileges: None.
+//   Notes: users other than root may only cancel their own queries.
+func (p *planner) CancelQuery(ctx context.Context, n *parser.CancelQuery) (planNode, error) {
+	typedQueryID, err := p.analyzeExpr(
+		ctx,
+		n.ID,
+		nil,
+		parser.IndexedVarHelper{},
+		parser.TypeString,
+		true, /* requireType */
+		"CANCEL QUERY",
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &cancelQueryNode{
+		p:       p,
+		queryID: typedQueryID,
+	}, nil
+}
+
+func (n *cancelQueryNode) Start(ctx context.Context) error {
+	statusServer := n.p.session.execCfg.StatusServer
+	if statusServer == nil {
+		return errors.New("CANCEL QUERY is not supported on this node")
+	}
+
+	queryIDDatum, err := n.queryID.Eval(&n.p.evalCtx)
+	if err != nil {
+		return err
+	}
+	queryIDString, ok := queryIDDatum.(*parser.DString)
+	if !ok {
+		return pgerror.NewErrorf(pgerror.CodeInvalidParameterValueError,
+			"query ID must be a string, not %s", queryIDDatum.ResolvedType())
+	}
+	queryID := string(*queryIDString)
+
+	nodeID, ok := nodeIDFromID(queryID)
+	if !ok {
+		return pgerror.NewErrorf(pgerror.CodeInvalidParameterValueError,
+			"invalid query ID %q", queryID)
+	}
+
+	request := &serverpb.CancelQueryRequest{
+		NodeId:   fmt.Sprintf("%d", nodeID),
+		QueryID:  queryID,
+		Username: n.p.session.User,
+	}
+	response, err := statusServer.CancelQuery(ctx, request)
+	if err != nil {
+		return err
+	}
+
+	if !response.Canceled {
+		return errors.Errorf("could not cancel query %s: %s", queryID, response.Error)
+	}
+
+	return nil
+}
+
+func (*cancelQueryNode) Next(context.Context) (bool, error) { return false, nil }
+func (*cancelQueryNode) Close(context.Context)              {}
+func (*cancelQueryNode) Columns() sqlbase.ResultColumns     { return make(sqlbase.ResultColumns, 0) }
+func (*cancelQueryNode) Ordering() orderingInfo             { return orderingInfo{} }
+func (*cancelQueryNode) Values() parser.Datums              { return parser.Datums{} }
+func (*cancelQueryNode) DebugValues() debugValues           { return debugValues{} }
+func (*cancelQueryNode) MarkDebug(mode explainMode)         {}
+func (*cancelQueryNode) Spans(context.Context) (_, _ roachpb.Spans, _ error) {
+	panic("unimplemented")
+}
+
+// CancelQueriesByKey cancels the active queries of the session with the given
+// pgwire cancel key (see Session.RegisterCancelKey), which may be open on any
+// node of the cluster. As in Postgres, knowledge of the key is sufficient to
+// cancel the queries. It returns whether the session was found.
+func (e *Executor) CancelQueriesByKey(ctx context.Context, key uint64) (bool, error) {
+	if e.cfg.StatusServer == nil {
+		if e.cfg.SessionRegistry == nil {
+			return false, nil
+		}
+		return e.cfg.SessionRegistry.CancelQueriesByKey(key), nil
+	}
+	request := &serverpb.CancelQueryRequest{
+		NodeId:    fmt.Sprintf("%d", cancelKeyNodeID(key)),
+		CancelKey: key,
+	}
+	response, err := e.cfg.StatusServer.CancelQuery(ctx, request)
+	if err != nil {
+		return false, err
+	}
+	return response.Canceled, nil
+}
@@ -0,0 +1,108 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/server/serverpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+)
+
+type cancelSessionNode struct {
+	p         *planner
+	sessionID parser.TypedExpr
+}
+
+// CancelSession cancels a session, which may be open on any node of the
+// cluster, closing its client connection.
+// Privileges: None.
+//   Notes: users other than root may only cancel their own sessions.
+func (p *planner) CancelSession(ctx context.Context, n *parser.CancelSession) (planNode, error) {
+	typedSessionID, err := p.analyzeExpr(
+		ctx,
+		n.ID,
+		nil,
+		parser.IndexedVarHelper{},
+		parser.TypeString,
+		true, /* requireType */
+		"CANCEL SESSION",
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &cancelSessionNode{
+		p:         p,
+		sessionID: typedSessionID,
+	}, nil
+}
+
+func (n *cancelSessionNode) Start(ctx context.Context) error {
+	statusServer := n.p.session.execCfg.StatusServer
+	if statusServer == nil {
+		return errors.New("CANCEL SESSION is not supported on this node")
+	}
+
+	sessionIDDatum, err := n.sessionID.Eval(&n.p.evalCtx)
+	if err != nil {
+		return err
+	}
+	sessionIDString, ok := sessionIDDatum.(*parser.DString)
+	if !ok {
+		return pgerror.NewErrorf(pgerror.CodeInvalidParameterValueError,
+			"session ID must be a string, not %s", sessionIDDatum.ResolvedType())
+	}
+	sessionID := string(*sessionIDString)
+
+	nodeID, ok := nodeIDFromID(sessionID)
+	if !ok {
+		return pgerror.NewErrorf(pgerror.CodeInvalidParameterValueError,
+			"invalid session ID %q", sessionID)
+	}
+
+	request := &serverpb.CancelSessionRequest{
+		NodeId:    fmt.Sprintf("%d", nodeID),
+		SessionID: sessionID,
+		Username:  n.p.session.User,
+	}
+	response, err := statusServer.CancelSession(ctx, request)
+	if err != nil {
+		return err
+	}
+
+	if !response.Canceled {
+		return errors.Errorf("could not cancel session %s: %s", sessionID, response.Error)
+	}
+
+	return nil
+}
+
+func (*cancelSessionNode) Next(context.Context) (bool, error) { return false, nil }
+func (*cancelSessionNode) Close(context.Context)              {}
+func (*cancelSessionNode) Columns() sqlbase.ResultColumns     { return make(sqlbase.ResultColumns, 0) }
+func (*cancelSessionNode) Ordering() orderingInfo             { return orderingInfo{} }
+func (*cancelSessionNode) Values() parser.Datums              { return parser.Datums{} }
+func (*cancelSessionNode) DebugValues() debugValues           { return debugValues{} }
+func (*cancelSessionNode) MarkDebug(mode explainMode)         {}
+func (*cancelSessionNode) Spans(context.Context) (_, _ roachpb.Spans, _ error) {
+	panic("unimplemented")
+}
@@ -29,14 +29,35 @@ func init() {
 }
 
 // GetZoneConfig returns the zone config for the object with 'id'.
+//
+// If the object is a table whose zone config is only a placeholder for the
+// zone configs of its indexes and partitions, the zone config of its parent
+// is returned with the table's subzones copied onto it.
 func GetZoneConfig(cfg config.SystemConfig, id uint32) (config.ZoneConfig, bool, error) {
 	// Look in the zones table.
 	if zoneVal := cfg.GetValue(sqlbase.MakeZoneKey(sqlbase.ID(id))); zoneVal != nil {
-		// We're done.
 		zone, err := config.MigrateZoneConfig(zoneVal)
-		return zone, true, err
+		if err != nil || !zone.IsSubzonePlaceholder() {
+			// We're done.
+			return zone, true, err
+		}
+		parentZone, found, err := getParentZoneConfig(cfg, id)
+		if err != nil {
+			return config.ZoneConfig{}, false, err
+		}
+		if !found {
+			parentZone = config.DefaultZoneConfig()
+		}
+		parentZone.Subzones, parentZone.SubzoneSpans = zone.Subzones, zone.SubzoneSpans
+		return parentZone, true, nil
 	}
 
+	return getParentZoneConfig(cfg, id)
+}
+
+// getParentZoneConfig returns the zone config that the object with 'id'
+// inherits when it has no zone config of its own.
+func getParentZoneConfig(cfg config.SystemConfig, id uint32) (config.ZoneConfig, bool, error) {
 	// No zone config for this ID. We need to figure out if it's a database
 	// or table. Lookup its descriptor.
 	if descVal := cfg.GetValue(sqlbase.MakeDescMetadataKey(sqlbase.ID(id))); descVal != nil {
@@ -31,6 +31,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/testutils"
 	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
 	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
 )
@@ -189,7 +190,7 @@ func TestGetZoneConfig(t *testing.T) {
 	// Here is the list of dbs/tables and whether they have a custom zone config:
 	// db1: true
 	//   tb1: true
-	//   tb2: false
+	//   tb2: placeholder with a subzone for the primary index
 	// db1: false
 	//   tb1: true
 	//   tb2: false
@@ -205,9 +206,24 @@ func TestGetZoneConfig(t *testing.T) {
 		NumReplicas: 1,
 		Constraints: config.Constraints{Constraints: []config.Constraint{{Value: "db2.tb1"}}},
 	}
+	tb12IndexCfg := config.ZoneConfig{
+		NumReplicas: 1,
+		Constraints: config.Constraints{Constraints: []config.Constraint{{Value: "db1.tb2@primary"}}},
+	}
+	tb12Cfg := config.ZoneConfig{
+		Subzones: []config.Subzone{{IndexID: 1, Config: tb12IndexCfg}},
+		SubzoneSpans: []config.SubzoneSpan{
+			{Key: roachpb.Key(encoding.EncodeUvarintAscending(nil, 1))},
+		},
+	}
+	// The placeholder inherits everything but its subzones from db1.
+	tb12EffectiveCfg := db1Cfg
+	tb12EffectiveCfg.Subzones = tb12Cfg.Subzones
+	tb12EffectiveCfg.SubzoneSpans = tb12Cfg.SubzoneSpans
 	for objID, objZone := range map[uint32]config.ZoneConfig{
 		db1:  db1Cfg,
 		tb11: tb11Cfg,
+		tb12: tb12Cfg,
 		tb21: tb21Cfg,
 	} {
 		buf, err := protoutil.Marshal(&objZone)
@@ -233,7 +249,9 @@ func TestGetZoneConfig(t *testing.T) {
 			{keys.MakeTablePrefix(db1), db1Cfg},
 			{keys.MakeTablePrefix(db2), defaultZoneConfig},
 			{keys.MakeTablePrefix(tb11), tb11Cfg},
-			{keys.MakeTablePrefix(tb12), db1Cfg},
+			{keys.MakeTablePrefix(tb12), tb12EffectiveCfg},
+			{encoding.EncodeUvarintAscending(keys.MakeTablePrefix(tb12), 1), tb12IndexCfg},
+			{encoding.EncodeUvarintAscending(keys.MakeTablePrefix(tb12), 2), tb12EffectiveCfg},
 			{keys.MakeTablePrefix(tb21), tb21Cfg},
 			{keys.MakeTablePrefix(tb22), defaultZoneConfig},
 		}
@@ -0,0 +1,109 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"golang.org/x/net/context"
+
+	"github.com/cockroachdb/cockroach/pkg/internal/client"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+)
+
+// controlJobNode implements PAUSE JOB, RESUME JOB and CANCEL JOB.
+type controlJobNode struct {
+	p     *planner
+	jobID parser.TypedExpr
+	// controlFn changes the status of the job in the system.jobs table.
+	controlFn func(context.Context, InternalExecutor, *client.Txn, int64) error
+}
+
+// PauseJob pauses a running job. The job stops making progress the next time
+// it checks its status.
+// Privileges: root user.
+func (p *planner) PauseJob(ctx context.Context, n *parser.PauseJob) (planNode, error) {
+	return p.controlJob(ctx, n.ID, "PAUSE JOB", pauseJob)
+}
+
+// ResumeJob resumes a paused job.
+// Privileges: root user.
+func (p *planner) ResumeJob(ctx context.Context, n *parser.ResumeJob) (planNode, error) {
+	return p.controlJob(ctx, n.ID, "RESUME JOB", resumeJob)
+}
+
+// CancelJob cancels a pending, running or paused job. The job abandons its
+// work the next time it checks its status.
+// Privileges: root user.
+func (p *planner) CancelJob(ctx context.Context, n *parser.CancelJob) (planNode, error) {
+	return p.controlJob(ctx, n.ID, "CANCEL JOB", cancelJob)
+}
+
+func (p *planner) controlJob(
+	ctx context.Context,
+	jobID parser.Expr,
+	op string,
+	controlFn func(context.Context, InternalExecutor, *client.Txn, int64) error,
+) (planNode, error) {
+	if err := p.RequireSuperUser(op); err != nil {
+		return nil, err
+	}
+
+	typedJobID, err := p.analyzeExpr(
+		ctx,
+		jobID,
+		nil,
+		parser.IndexedVarHelper{},
+		parser.TypeInt,
+		true, /* requireType */
+		op,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &controlJobNode{
+		p:         p,
+		jobID:     typedJobID,
+		controlFn: controlFn,
+	}, nil
+}
+
+func (n *controlJobNode) Start(ctx context.Context) error {
+	jobIDDatum, err := n.jobID.Eval(&n.p.evalCtx)
+	if err != nil {
+		return err
+	}
+	jobID, ok := jobIDDatum.(*parser.DInt)
+	if !ok {
+		return pgerror.NewErrorf(pgerror.CodeInvalidParameterValueError,
+			"job ID must be an integer, not %s", jobIDDatum.ResolvedType())
+	}
+
+	ex := InternalExecutor{LeaseManager: n.p.LeaseMgr()}
+	return n.controlFn(ctx, ex, n.p.txn, int64(*jobID))
+}
+
+func (*controlJobNode) Next(context.Context) (bool, error) { return false, nil }
+func (*controlJobNode) Close(context.Context)              {}
+func (*controlJobNode) Columns() sqlbase.ResultColumns     { return make(sqlbase.ResultColumns, 0) }
+func (*controlJobNode) Ordering() orderingInfo             { return orderingInfo{} }
+func (*controlJobNode) Values() parser.Datums              { return parser.Datums{} }
+func (*controlJobNode) DebugValues() debugValues           { return debugValues{} }
+func (*controlJobNode) MarkDebug(mode explainMode)         {}
+func (*controlJobNode) Spans(context.Context) (_, _ roachpb.Spans, _ error) {
+	panic("unimplemented")
+}
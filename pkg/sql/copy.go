@@ -223,6 +223,24 @@ func (n *copyNode) addRow(ctx context.Context, line []byte) error {
 				break
 			}
 			d, err = parser.ParseDInterval(s)
+		case parser.TypeJSON:
+			s, err = decodeCopy(s)
+			if err != nil {
+				break
+			}
+			d, err = parser.ParseDJSON(s)
+		case parser.TypeUUID:
+			s, err = decodeCopy(s)
+			if err != nil {
+				break
+			}
+			d, err = parser.ParseDUuidFromString(s)
+		case parser.TypeINet:
+			s, err = decodeCopy(s)
+			if err != nil {
+				break
+			}
+			d, err = parser.ParseDIPAddrFromINetString(s)
 		case parser.TypeString:
 			s, err = decodeCopy(s)
 			d = parser.NewDString(s)
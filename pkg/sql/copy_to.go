@@ -0,0 +1,152 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+)
+
+// CopyFormat is the format of the data sent by a COPY TO.
+type CopyFormat int
+
+const (
+	// CopyFormatText is the tab-delimited text format of postgres.
+	CopyFormatText CopyFormat = iota
+	// CopyFormatCSV is the comma-separated values format.
+	CopyFormatCSV
+)
+
+// CopyOutWriter is implemented by the client connection of a session to
+// stream the rows of a COPY TO back to the client as they are produced.
+type CopyOutWriter interface {
+	// BeginCopyOut signals the start of a COPY TO with the given columns.
+	BeginCopyOut(columns sqlbase.ResultColumns, format CopyFormat) error
+	// WriteCopyRow sends a single row of a COPY TO.
+	WriteCopyRow(row parser.Datums) error
+}
+
+// COPY TO is executed like a query, but its rows are not accumulated in the
+// statement's result: copyToNode.run sends them directly to the session's
+// CopyOutWriter, so the full result never needs to be held in memory. Only
+// the number of rows is returned in the result. Because the rows are sent
+// to the client during execution, a COPY TO cannot be retried once it has
+// started; see execParsed.
+//
+// See: https://www.postgresql.org/docs/9.6/static/sql-copy.html
+type copyToNode struct {
+	source planNode
+	format CopyFormat
+}
+
+func (n *copyToNode) Columns() sqlbase.ResultColumns  { return n.source.Columns() }
+func (n *copyToNode) Ordering() orderingInfo          { return n.source.Ordering() }
+func (n *copyToNode) Values() parser.Datums           { return n.source.Values() }
+func (n *copyToNode) DebugValues() debugValues        { return n.source.DebugValues() }
+func (n *copyToNode) MarkDebug(mode explainMode)      { n.source.MarkDebug(mode) }
+func (n *copyToNode) Start(ctx context.Context) error { return n.source.Start(ctx) }
+func (n *copyToNode) Close(ctx context.Context)       { n.source.Close(ctx) }
+
+func (n *copyToNode) Next(ctx context.Context) (bool, error) {
+	return n.source.Next(ctx)
+}
+
+func (n *copyToNode) Spans(ctx context.Context) (_, _ roachpb.Spans, _ error) {
+	return n.source.Spans(ctx)
+}
+
+// CopyTo begins a COPY TO.
+// Privileges: SELECT on the table or the tables of the query.
+func (p *planner) CopyTo(ctx context.Context, n *parser.CopyTo) (planNode, error) {
+	if !n.Stdout {
+		return nil, pgerror.NewError(pgerror.CodeFeatureNotSupportedError,
+			"COPY TO is only supported with STDOUT")
+	}
+	if p.session.copyOut == nil {
+		return nil, errors.New("COPY TO STDOUT is not supported by this client")
+	}
+
+	var format CopyFormat
+	switch strings.ToLower(n.FileFormat) {
+	case "", "text":
+		format = CopyFormatText
+	case "csv":
+		format = CopyFormatCSV
+	default:
+		return nil, pgerror.NewErrorf(pgerror.CodeFeatureNotSupportedError,
+			"COPY format %q is not supported", n.FileFormat)
+	}
+
+	sel := n.Query
+	if sel == nil {
+		// COPY table [(columns)] TO is equivalent to COPY (SELECT columns FROM
+		// table) TO.
+		var exprs parser.SelectExprs
+		if len(n.Columns) == 0 {
+			exprs = parser.SelectExprs{{Expr: parser.StarExpr()}}
+		} else {
+			exprs = make(parser.SelectExprs, len(n.Columns))
+			for i, col := range n.Columns {
+				exprs[i] = parser.SelectExpr{Expr: col}
+			}
+		}
+		sel = &parser.Select{
+			Select: &parser.SelectClause{
+				Exprs: exprs,
+				From:  &parser.From{Tables: parser.TableExprs{&n.Table}},
+			},
+		}
+	}
+
+	source, err := p.Select(ctx, sel, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &copyToNode{source: source, format: format}, nil
+}
+
+// run sends the rows of the COPY TO to w and returns the number of rows
+// sent. It must be called after the node is started.
+func (n *copyToNode) run(ctx context.Context, w CopyOutWriter) (int, error) {
+	columns := n.Columns()
+	for _, c := range columns {
+		if err := checkResultType(c.Typ); err != nil {
+			return 0, err
+		}
+	}
+	if err := w.BeginCopyOut(columns, n.format); err != nil {
+		return 0, err
+	}
+
+	count := 0
+	next, err := n.Next(ctx)
+	for ; next; next, err = n.Next(ctx) {
+		if err := ctx.Err(); err != nil {
+			return count, err
+		}
+		if err := w.WriteCopyRow(n.Values()); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, err
+}
@@ -17,6 +17,8 @@
 package sql
 
 import (
+	"bytes"
+	"fmt"
 	"reflect"
 	"sort"
 	"time"
@@ -26,8 +28,10 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/build"
 	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/server/serverpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
 )
 
 var crdbInternal = virtualSchema{
@@ -39,6 +43,10 @@ var crdbInternal = virtualSchema{
 		crdbInternalSchemaChangesTable,
 		crdbInternalStmtStatsTable,
 		crdbInternalJobsTable,
+		crdbInternalLocalQueriesTable,
+		crdbInternalClusterQueriesTable,
+		crdbInternalLocalSessionsTable,
+		crdbInternalClusterSessionsTable,
 	},
 }
 
@@ -332,6 +340,203 @@ CREATE TABLE crdb_internal.jobs (
 	},
 }
 
+// queriesTableSchemaPattern is used by both crdb_internal.node_queries and
+// crdb_internal.cluster_queries.
+const queriesTableSchemaPattern = `
+CREATE TABLE crdb_internal.%s (
+  query_id         STRING,         -- the cluster-unique ID of the query
+  node_id          INT NOT NULL,   -- the node on which the query is running
+  username         STRING,         -- the user running the query
+  start            TIMESTAMP,      -- the start time of the query
+  query            STRING,         -- the SQL code of the query
+  client_address   STRING,         -- the address of the client that issued the query
+  application_name STRING,         -- the name of the application as per SET application_name
+  distributed      BOOL            -- whether the query is running distributed
+);
+`
+
+// crdbInternalLocalQueriesTable exposes the list of running queries
+// on the current node. The results are dependent on the current user.
+var crdbInternalLocalQueriesTable = virtualSchemaTable{
+	schema: fmt.Sprintf(queriesTableSchemaPattern, "node_queries"),
+	populate: func(ctx context.Context, p *planner, addRow func(...parser.Datum) error) error {
+		statusServer := p.session.execCfg.StatusServer
+		if statusServer == nil {
+			return errors.New("cannot list queries from this context")
+		}
+		req := &serverpb.ListSessionsRequest{Username: p.session.User}
+		response, err := statusServer.ListLocalSessions(ctx, req)
+		if err != nil {
+			return err
+		}
+		return populateQueriesTable(ctx, addRow, response)
+	},
+}
+
+// crdbInternalClusterQueriesTable exposes the list of running queries
+// on the entire cluster. The result is dependent on the current user.
+var crdbInternalClusterQueriesTable = virtualSchemaTable{
+	schema: fmt.Sprintf(queriesTableSchemaPattern, "cluster_queries"),
+	populate: func(ctx context.Context, p *planner, addRow func(...parser.Datum) error) error {
+		statusServer := p.session.execCfg.StatusServer
+		if statusServer == nil {
+			return errors.New("cannot list queries from this context")
+		}
+		req := &serverpb.ListSessionsRequest{Username: p.session.User}
+		response, err := statusServer.ListSessions(ctx, req)
+		if err != nil {
+			return err
+		}
+		return populateQueriesTable(ctx, addRow, response)
+	},
+}
+
+func populateQueriesTable(
+	ctx context.Context, addRow func(...parser.Datum) error, response *serverpb.ListSessionsResponse,
+) error {
+	for _, session := range response.Sessions {
+		for _, query := range session.ActiveQueries {
+			if err := addRow(
+				parser.NewDString(query.ID),
+				parser.NewDInt(parser.DInt(session.NodeID)),
+				parser.NewDString(session.Username),
+				parser.MakeDTimestamp(query.Start, time.Microsecond),
+				parser.NewDString(query.Sql),
+				parser.NewDString(session.ClientAddress),
+				parser.NewDString(session.ApplicationName),
+				parser.MakeDBool(parser.DBool(query.IsDistributed)),
+			); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, rpcErr := range response.Errors {
+		log.Warning(ctx, rpcErr.Message)
+		// Add a row with this node ID, and nulls for all other columns.
+		if err := addRow(
+			parser.DNull,
+			parser.NewDInt(parser.DInt(rpcErr.NodeID)),
+			parser.DNull,
+			parser.DNull,
+			parser.NewDString(fmt.Sprintf("-- error: %s", rpcErr.Message)),
+			parser.DNull,
+			parser.DNull,
+			parser.DNull,
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sessionsTableSchemaPattern is used by both crdb_internal.node_sessions and
+// crdb_internal.cluster_sessions.
+const sessionsTableSchemaPattern = `
+CREATE TABLE crdb_internal.%s (
+  node_id            INT NOT NULL,   -- the node on which the session is open
+  session_id         STRING,         -- the cluster-unique ID of the session
+  username           STRING,         -- the user of the session
+  client_address     STRING,         -- the address of the client
+  application_name   STRING,         -- the name of the application as per SET application_name
+  active_queries     STRING,         -- the SQL code of the queries currently running
+  last_active_query  STRING,         -- the SQL code of the most recent query started
+  session_start      TIMESTAMP,      -- the time at which the session was opened
+  active_txn_start   TIMESTAMP       -- the start time of the open transaction, if any
+);
+`
+
+// crdbInternalLocalSessionsTable exposes the list of open sessions
+// on the current node. The results are dependent on the current user.
+var crdbInternalLocalSessionsTable = virtualSchemaTable{
+	schema: fmt.Sprintf(sessionsTableSchemaPattern, "node_sessions"),
+	populate: func(ctx context.Context, p *planner, addRow func(...parser.Datum) error) error {
+		statusServer := p.session.execCfg.StatusServer
+		if statusServer == nil {
+			return errors.New("cannot list sessions from this context")
+		}
+		req := &serverpb.ListSessionsRequest{Username: p.session.User}
+		response, err := statusServer.ListLocalSessions(ctx, req)
+		if err != nil {
+			return err
+		}
+		return populateSessionsTable(ctx, addRow, response)
+	},
+}
+
+// crdbInternalClusterSessionsTable exposes the list of open sessions
+// on the entire cluster. The results are dependent on the current user.
+var crdbInternalClusterSessionsTable = virtualSchemaTable{
+	schema: fmt.Sprintf(sessionsTableSchemaPattern, "cluster_sessions"),
+	populate: func(ctx context.Context, p *planner, addRow func(...parser.Datum) error) error {
+		statusServer := p.session.execCfg.StatusServer
+		if statusServer == nil {
+			return errors.New("cannot list sessions from this context")
+		}
+		req := &serverpb.ListSessionsRequest{Username: p.session.User}
+		response, err := statusServer.ListSessions(ctx, req)
+		if err != nil {
+			return err
+		}
+		return populateSessionsTable(ctx, addRow, response)
+	},
+}
+
+func populateSessionsTable(
+	ctx context.Context, addRow func(...parser.Datum) error, response *serverpb.ListSessionsResponse,
+) error {
+	for _, session := range response.Sessions {
+		// Only the text of the active queries is listed here; the queries
+		// themselves are detailed in crdb_internal.node_queries.
+		var activeQueries bytes.Buffer
+		for i, query := range session.ActiveQueries {
+			if i > 0 {
+				activeQueries.WriteString("; ")
+			}
+			activeQueries.WriteString(query.Sql)
+		}
+
+		activeTxnStart := parser.DNull
+		if session.ActiveTxnStart != nil {
+			activeTxnStart = parser.MakeDTimestamp(*session.ActiveTxnStart, time.Microsecond)
+		}
+
+		if err := addRow(
+			parser.NewDInt(parser.DInt(session.NodeID)),
+			parser.NewDString(session.ID),
+			parser.NewDString(session.Username),
+			parser.NewDString(session.ClientAddress),
+			parser.NewDString(session.ApplicationName),
+			parser.NewDString(activeQueries.String()),
+			parser.NewDString(session.LastActiveQuery),
+			parser.MakeDTimestamp(session.Start, time.Microsecond),
+			activeTxnStart,
+		); err != nil {
+			return err
+		}
+	}
+
+	for _, rpcErr := range response.Errors {
+		log.Warning(ctx, rpcErr.Message)
+		// Add a row with this node ID, the error in the active queries
+		// column, and nulls for all other columns.
+		if err := addRow(
+			parser.NewDInt(parser.DInt(rpcErr.NodeID)),
+			parser.DNull,
+			parser.DNull,
+			parser.DNull,
+			parser.DNull,
+			parser.NewDString(fmt.Sprintf("-- error: %s", rpcErr.Message)),
+			parser.DNull,
+			parser.DNull,
+			parser.DNull,
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 type stmtList []stmtKey
 
 func (s stmtList) Len() int {
@@ -149,7 +149,7 @@ func (p *planner) CreateIndex(ctx context.Context, n *parser.CreateIndex) (planN
 		return nil, err
 	}
 
-	if err := p.CheckPrivilege(tableDesc, privilege.CREATE); err != nil {
+	if err := p.CheckPrivilege(ctx, tableDesc, privilege.CREATE); err != nil {
 		return nil, err
 	}
 
@@ -178,6 +178,9 @@ func (n *createIndexNode) Start(ctx context.Context) error {
 		Unique:           n.n.Unique,
 		StoreColumnNames: n.n.Storing.ToStrings(),
 	}
+	if n.n.Inverted {
+		indexDesc.Type = sqlbase.IndexDescriptor_INVERTED
+	}
 	if err := indexDesc.FillColumns(n.n.Columns); err != nil {
 		return err
 	}
@@ -202,6 +205,15 @@ func (n *createIndexNode) Start(ctx context.Context) error {
 		}
 	}
 
+	if n.n.PartitionBy != nil {
+		index := n.tableDesc.Mutations[mutationIdx].GetIndex()
+		if err := addPartitioning(
+			n.tableDesc, index, n.n.PartitionBy, n.p.session.SearchPath, &n.p.evalCtx,
+		); err != nil {
+			return err
+		}
+	}
+
 	if err := n.p.txn.Put(
 		ctx,
 		sqlbase.MakeDescMetadataKey(n.tableDesc.GetID()),
@@ -248,8 +260,11 @@ func (*createIndexNode) Spans(context.Context) (_, _ roachpb.Spans, _ error) {
 
 type createUserNode struct {
 	p        *planner
-	n        *parser.CreateUser
+	name     parser.Name
 	password string
+	// isRole is set for CREATE ROLE. Roles cannot log in; they group
+	// privileges which are inherited by their members.
+	isRole bool
 }
 
 // CreateUser creates a user.
@@ -266,7 +281,7 @@ func (p *planner) CreateUser(ctx context.Context, n *parser.CreateUser) (planNod
 		return nil, err
 	}
 
-	if err := p.CheckPrivilege(tDesc, privilege.INSERT); err != nil {
+	if err := p.CheckPrivilege(ctx, tDesc, privilege.INSERT); err != nil {
 		return nil, err
 	}
 
@@ -278,7 +293,7 @@ func (p *planner) CreateUser(ctx context.Context, n *parser.CreateUser) (planNod
 		}
 	}
 
-	return &createUserNode{p: p, n: n, password: resolvedPassword}, nil
+	return &createUserNode{p: p, name: n.Name, password: resolvedPassword}, nil
 }
 
 const usernameHelp = "usernames are case insensitive, must start with a letter " +
@@ -313,7 +328,7 @@ func (n *createUserNode) Start(ctx context.Context) error {
 		}
 	}
 
-	normalizedUsername, err := NormalizeAndValidateUsername(string(n.n.Name))
+	normalizedUsername, err := NormalizeAndValidateUsername(string(n.name))
 	if err != nil {
 		return err
 	}
@@ -323,13 +338,18 @@ func (n *createUserNode) Start(ctx context.Context) error {
 		ctx,
 		"create-user",
 		n.p.txn,
-		"INSERT INTO system.users VALUES ($1, $2);",
+		`INSERT INTO system.users (username, "hashedPassword", "isRole") VALUES ($1, $2, $3);`,
 		normalizedUsername,
 		hashedPassword,
+		n.isRole,
 	)
 	if err != nil {
 		if sqlbase.IsUniquenessConstraintViolationError(err) {
-			err = errors.Errorf("user %s already exists", normalizedUsername)
+			kind := "user"
+			if n.isRole {
+				kind = "role"
+			}
+			err = errors.Errorf("%s %s already exists", kind, normalizedUsername)
 		}
 		return err
 	} else if rowsAffected != 1 {
@@ -377,7 +397,7 @@ func (p *planner) CreateView(ctx context.Context, n *parser.CreateView) (planNod
 		return nil, err
 	}
 
-	if err := p.CheckPrivilege(dbDesc, privilege.CREATE); err != nil {
+	if err := p.CheckPrivilege(ctx, dbDesc, privilege.CREATE); err != nil {
 		return nil, err
 	}
 
@@ -534,6 +554,108 @@ func (*createViewNode) Spans(context.Context) (_, _ roachpb.Spans, _ error) {
 	panic("unimplemented")
 }
 
+type createSequenceNode struct {
+	p      *planner
+	n      *parser.CreateSequence
+	dbDesc *sqlbase.DatabaseDescriptor
+}
+
+// CreateSequence creates a sequence.
+// Privileges: CREATE on database.
+//   Notes: postgres requires CREATE on database.
+func (p *planner) CreateSequence(ctx context.Context, n *parser.CreateSequence) (planNode, error) {
+	name, err := n.Name.NormalizeWithDatabaseName(p.session.Database)
+	if err != nil {
+		return nil, err
+	}
+
+	dbDesc, err := MustGetDatabaseDesc(ctx, p.txn, p.getVirtualTabler(), name.Database())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.CheckPrivilege(ctx, dbDesc, privilege.CREATE); err != nil {
+		return nil, err
+	}
+
+	return &createSequenceNode{p: p, n: n, dbDesc: dbDesc}, nil
+}
+
+func (n *createSequenceNode) Start(ctx context.Context) error {
+	seqName := n.n.Name.TableName().Table()
+	tKey := tableKey{parentID: n.dbDesc.ID, name: seqName}
+	key := tKey.Key()
+	if exists, err := descExists(ctx, n.p.txn, key); err == nil && exists {
+		if n.n.IfNotExists {
+			return nil
+		}
+		return sqlbase.NewRelationAlreadyExistsError(tKey.Name())
+	} else if err != nil {
+		return err
+	}
+
+	id, err := GenerateUniqueDescID(ctx, n.p.txn)
+	if err != nil {
+		return err
+	}
+
+	// Inherit permissions from the database descriptor.
+	privs := n.dbDesc.GetPrivileges()
+
+	desc, err := MakeSequenceTableDesc(seqName, n.n.Options, n.dbDesc.ID, id, privs)
+	if err != nil {
+		return err
+	}
+
+	if err := desc.ValidateTable(); err != nil {
+		return err
+	}
+
+	if err := n.p.createDescriptorWithID(ctx, key, id, &desc); err != nil {
+		return err
+	}
+
+	// Initialize the sequence value so that the first call to nextval()
+	// returns the start value.
+	seqValueKey := keys.MakeSequenceKey(uint32(id))
+	b := &client.Batch{}
+	b.Inc(seqValueKey, desc.SequenceOpts.Start-desc.SequenceOpts.Increment)
+	if err := n.p.txn.Run(ctx, b); err != nil {
+		return err
+	}
+
+	if err := desc.Validate(ctx, n.p.txn); err != nil {
+		return err
+	}
+
+	// Log Create Sequence event. This is an auditable log event and is
+	// recorded in the same transaction as the table descriptor update.
+	return MakeEventLogger(n.p.LeaseMgr()).InsertEventRecord(
+		ctx,
+		n.p.txn,
+		EventLogCreateSequence,
+		int32(desc.ID),
+		int32(n.p.evalCtx.NodeID),
+		struct {
+			SequenceName string
+			Statement    string
+			User         string
+		}{n.n.Name.String(), n.n.String(), n.p.session.User},
+	)
+}
+
+func (*createSequenceNode) Next(context.Context) (bool, error) { return false, nil }
+func (*createSequenceNode) Close(context.Context)              {}
+func (*createSequenceNode) Columns() sqlbase.ResultColumns     { return make(sqlbase.ResultColumns, 0) }
+func (*createSequenceNode) Ordering() orderingInfo             { return orderingInfo{} }
+func (*createSequenceNode) Values() parser.Datums              { return parser.Datums{} }
+func (*createSequenceNode) DebugValues() debugValues           { return debugValues{} }
+func (*createSequenceNode) MarkDebug(mode explainMode)         {}
+
+func (*createSequenceNode) Spans(context.Context) (_, _ roachpb.Spans, _ error) {
+	panic("unimplemented")
+}
+
 type createTableNode struct {
 	p          *planner
 	n          *parser.CreateTable
@@ -556,7 +678,7 @@ func (p *planner) CreateTable(ctx context.Context, n *parser.CreateTable) (planN
 		return nil, err
 	}
 
-	if err := p.CheckPrivilege(dbDesc, privilege.CREATE); err != nil {
+	if err := p.CheckPrivilege(ctx, dbDesc, privilege.CREATE); err != nil {
 		return nil, err
 	}
 
@@ -616,6 +738,7 @@ func hoistConstraints(n *parser.CreateTable) {
 					FromCols: parser.NameList{col.Name},
 					ToCols:   targetCol,
 					Name:     col.References.ConstraintName,
+					Actions:  col.References.Actions,
 				})
 				col.References.Table = parser.NormalizableTableName{}
 			}
@@ -915,11 +1038,17 @@ func resolveFK(
 		}
 	}
 
+	if err := checkFKActions(srcCols, d.Actions); err != nil {
+		return err
+	}
+
 	ref := sqlbase.ForeignKeyReference{
 		Table:           target.ID,
 		Index:           targetIdx.ID,
 		Name:            constraintName,
 		SharedPrefixLen: int32(len(srcCols)),
+		OnDelete:        sqlbase.ForeignKeyReferenceActionValue[d.Actions.Delete],
+		OnUpdate:        sqlbase.ForeignKeyReferenceActionValue[d.Actions.Update],
 	}
 	if mode == sqlbase.ConstraintValidity_Unvalidated {
 		ref.Validity = sqlbase.ConstraintValidity_Unvalidated
@@ -957,6 +1086,29 @@ func resolveFK(
 	return nil
 }
 
+// checkFKActions verifies that the referencing columns of a foreign key
+// can hold the values its SET NULL and SET DEFAULT actions will store.
+func checkFKActions(srcCols []sqlbase.ColumnDescriptor, actions parser.ReferenceActions) error {
+	for _, action := range []parser.ReferenceAction{actions.Delete, actions.Update} {
+		for _, col := range srcCols {
+			if col.Nullable {
+				continue
+			}
+			switch action {
+			case parser.SetNull:
+				return fmt.Errorf("cannot add a SET NULL action on column %q which has a NOT NULL constraint",
+					col.Name)
+			case parser.SetDefault:
+				if col.DefaultExpr == nil {
+					return fmt.Errorf("cannot add a SET DEFAULT action on column %q which has a NOT NULL constraint and no default value",
+						col.Name)
+				}
+			}
+		}
+	}
+	return nil
+}
+
 // Adds an index to a table descriptor (that is in the process of being created)
 // that will support using `srcCols` as the referencing (src) side of an FK.
 func addIndexForFK(
@@ -1266,9 +1418,6 @@ func MakeTableDesc(
 	for _, def := range n.Defs {
 		if d, ok := def.(*parser.ColumnTableDef); ok {
 			if !desc.IsVirtualTable() {
-				if _, ok := d.Type.(*parser.ArrayColType); ok {
-					return desc, util.UnimplementedWithIssueErrorf(2115, "ARRAY column types are unsupported")
-				}
 				if _, ok := d.Type.(*parser.VectorColType); ok {
 					return desc, util.UnimplementedWithIssueErrorf(2115, "VECTOR column types are unsupported")
 				}
@@ -1297,6 +1446,11 @@ func MakeTableDesc(
 	}
 
 	var primaryIndexColumnSet map[string]struct{}
+	// The partitioning of each index is recorded here and added once column IDs
+	// have been allocated. partitionedIndexes is keyed by position in
+	// desc.Indexes.
+	primaryPartitionBy := n.PartitionBy
+	partitionedIndexes := map[int]*parser.PartitionBy{}
 	for _, def := range n.Defs {
 		switch d := def.(type) {
 		case *parser.ColumnTableDef:
@@ -1307,6 +1461,9 @@ func MakeTableDesc(
 				Name:             string(d.Name),
 				StoreColumnNames: d.Storing.ToStrings(),
 			}
+			if d.Inverted {
+				idx.Type = sqlbase.IndexDescriptor_INVERTED
+			}
 			if err := idx.FillColumns(d.Columns); err != nil {
 				return desc, err
 			}
@@ -1316,6 +1473,9 @@ func MakeTableDesc(
 			if d.Interleave != nil {
 				return desc, util.UnimplementedWithIssueErrorf(9148, "use CREATE INDEX to make interleaved indexes")
 			}
+			if d.PartitionBy != nil {
+				partitionedIndexes[len(desc.Indexes)-1] = d.PartitionBy
+			}
 		case *parser.UniqueConstraintTableDef:
 			idx := sqlbase.IndexDescriptor{
 				Name:             string(d.Name),
@@ -1337,6 +1497,16 @@ func MakeTableDesc(
 			if d.Interleave != nil {
 				return desc, util.UnimplementedWithIssueErrorf(9148, "use CREATE INDEX to make interleaved indexes")
 			}
+			if d.PartitionBy != nil {
+				if d.PrimaryKey {
+					if primaryPartitionBy != nil {
+						return desc, errors.New("primary key cannot be partitioned twice")
+					}
+					primaryPartitionBy = d.PartitionBy
+				} else {
+					partitionedIndexes[len(desc.Indexes)-1] = d.PartitionBy
+				}
+			}
 
 		case *parser.CheckConstraintTableDef, *parser.ForeignKeyConstraintTableDef, *parser.FamilyTableDef:
 			// pass, handled below.
@@ -1378,6 +1548,21 @@ func MakeTableDesc(
 		}
 	}
 
+	if primaryPartitionBy != nil {
+		if err := addPartitioning(
+			&desc, &desc.PrimaryIndex, primaryPartitionBy, searchPath, evalCtx,
+		); err != nil {
+			return desc, err
+		}
+	}
+	for i := range desc.Indexes {
+		if partBy, ok := partitionedIndexes[i]; ok {
+			if err := addPartitioning(&desc, &desc.Indexes[i], partBy, searchPath, evalCtx); err != nil {
+				return desc, err
+			}
+		}
+	}
+
 	// With all structural elements in place and IDs allocated, we can resolve the
 	// constraints and qualifications.
 	// FKs are resolved after the descriptor is otherwise complete and IDs have
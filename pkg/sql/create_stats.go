@@ -0,0 +1,356 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+
+	"github.com/cockroachdb/cockroach/pkg/internal/client"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/distsqlrun"
+	"github.com/cockroachdb/cockroach/pkg/sql/mon"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+)
+
+const (
+	// createStatsSampleSize is the number of rows sampled by CREATE STATISTICS
+	// to build histograms.
+	createStatsSampleSize = 10000
+	// createStatsHistogramBuckets is the maximum number of buckets in the
+	// histograms built by CREATE STATISTICS.
+	createStatsHistogramBuckets = 200
+)
+
+// createStatsNode implements CREATE STATISTICS.
+type createStatsNode struct {
+	p         *planner
+	n         *parser.CreateStats
+	tableDesc *sqlbase.TableDescriptor
+	columnIDs []sqlbase.ColumnID
+}
+
+// CreateStatistics computes statistics on a set of columns of a table and
+// stores them in system.table_statistics, where they are used by the planner.
+// Privileges: SELECT on table.
+func (p *planner) CreateStatistics(ctx context.Context, n *parser.CreateStats) (planNode, error) {
+	tn, err := n.Table.NormalizeWithDatabaseName(p.session.Database)
+	if err != nil {
+		return nil, err
+	}
+
+	tableDesc, err := mustGetTableDesc(ctx, p.txn, p.getVirtualTabler(), tn)
+	if err != nil {
+		return nil, err
+	}
+	if tableDesc.IsVirtualTable() {
+		return nil, sqlbase.NewWrongObjectTypeError(tn.String(), "table")
+	}
+
+	if err := p.CheckPrivilege(ctx, tableDesc, privilege.SELECT); err != nil {
+		return nil, err
+	}
+
+	columnIDs := make([]sqlbase.ColumnID, len(n.ColumnNames))
+	seen := make(map[sqlbase.ColumnID]struct{}, len(n.ColumnNames))
+	for i, name := range n.ColumnNames {
+		col, err := tableDesc.FindActiveColumnByName(name)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := seen[col.ID]; ok {
+			return nil, fmt.Errorf("column %q appears more than once in statistics", col.Name)
+		}
+		seen[col.ID] = struct{}{}
+		columnIDs[i] = col.ID
+	}
+
+	return &createStatsNode{p: p, n: n, tableDesc: tableDesc, columnIDs: columnIDs}, nil
+}
+
+// Start runs the CREATE STATISTICS job to completion. As with BACKUP, the job
+// runs in its own transactions, independently of the transaction of the
+// statement.
+func (n *createStatsNode) Start(ctx context.Context) error {
+	details := CreateStatsJobDetails{
+		Name:      string(n.n.Name),
+		TableID:   n.tableDesc.ID,
+		ColumnIDs: n.columnIDs,
+	}
+	jobLogger := n.p.ExecCfg().JobRegistry.NewJobLogger(JobRecord{
+		Description:   n.n.String(),
+		Username:      n.p.session.User,
+		DescriptorIDs: sqlbase.IDs{n.tableDesc.ID},
+		Details:       details,
+	})
+	if err := jobLogger.Created(ctx); err != nil {
+		return err
+	}
+	if err := jobLogger.Started(ctx); err != nil {
+		jobLogger.Failed(ctx, err)
+		return err
+	}
+	if err := runCreateStats(ctx, n.p.ExecCfg(), n.p.session.distSQLPlanner, details); err != nil {
+		jobLogger.Failed(ctx, err)
+		return err
+	}
+	if err := jobLogger.Succeeded(ctx); err != nil {
+		// The statistics have been created; an error while marking the job as
+		// successful is not important enough to fail the statement.
+		log.Errorf(ctx, "CREATE STATISTICS ignoring error while marking job %d as successful: %+v",
+			*jobLogger.JobID(), err)
+	}
+	return nil
+}
+
+func (*createStatsNode) Next(context.Context) (bool, error) { return false, nil }
+func (*createStatsNode) Close(context.Context)              {}
+func (*createStatsNode) Columns() sqlbase.ResultColumns     { return make(sqlbase.ResultColumns, 0) }
+func (*createStatsNode) Ordering() orderingInfo             { return orderingInfo{} }
+func (*createStatsNode) Values() parser.Datums              { return parser.Datums{} }
+func (*createStatsNode) DebugValues() debugValues           { return debugValues{} }
+func (*createStatsNode) MarkDebug(mode explainMode)         {}
+func (*createStatsNode) Spans(context.Context) (_, _ roachpb.Spans, _ error) {
+	panic("unimplemented")
+}
+
+// runCreateStats computes the statistic described by details and inserts it
+// into system.table_statistics.
+//
+// The rows of the table are read by table readers on the nodes holding the
+// data; a sampler processor on each of these nodes computes a distinct count
+// sketch and a sample of the rows, and a single sample aggregator on this node
+// merges them into the final statistic.
+func runCreateStats(
+	ctx context.Context, execCfg *ExecutorConfig, dsp *distSQLPlanner, details CreateStatsJobDetails,
+) error {
+	var sink *sqlbase.RowContainer
+	monitor := mon.MakeUnlimitedMonitor(ctx, "create-stats", nil, nil, math.MaxInt64)
+	defer monitor.Stop(ctx)
+	defer func() {
+		if sink != nil {
+			sink.Close(ctx)
+		}
+	}()
+
+	if err := execCfg.DB.Txn(ctx, func(ctx context.Context, txn *client.Txn) error {
+		tableDesc, err := sqlbase.GetTableDescFromID(ctx, txn, details.TableID)
+		if err != nil {
+			return err
+		}
+		if tableDesc.Dropped() {
+			return errors.Errorf("table %q is being dropped", tableDesc.Name)
+		}
+
+		if sink != nil {
+			// The transaction is being retried.
+			sink.Close(ctx)
+		}
+		sink = sqlbase.NewRowContainer(
+			monitor.MakeBoundAccount(),
+			sqlbase.ColTypeInfoFromColTypes(statsAggregatorOutputTypes),
+			0, /* rowCapacity */
+		)
+
+		recv, err := makeDistSQLReceiver(
+			ctx,
+			sink,
+			execCfg.RangeDescriptorCache,
+			execCfg.LeaseHolderCache,
+			txn,
+			nil, /* updateClock */
+		)
+		if err != nil {
+			return err
+		}
+		planCtx := dsp.NewPlanningCtx(ctx, txn)
+		plan, err := dsp.createStatsPlan(&planCtx, tableDesc, details.ColumnIDs, details.Name)
+		if err != nil {
+			return err
+		}
+		dsp.FinalizePlan(&planCtx, &plan)
+		evalCtx := createSchemaChangeEvalCtx(txn.OrigTimestamp())
+		if err := dsp.Run(&planCtx, txn, &plan, &recv, evalCtx); err != nil {
+			return err
+		}
+		return recv.err
+	}); err != nil {
+		return err
+	}
+
+	if sink.Len() != 1 {
+		return errors.Errorf("expected 1 statistic, got %d", sink.Len())
+	}
+	row := sink.At(0)
+	columnIDs := parser.NewDArray(parser.TypeInt)
+	for _, id := range details.ColumnIDs {
+		if err := columnIDs.Append(parser.NewDInt(parser.DInt(id))); err != nil {
+			return err
+		}
+	}
+	var name interface{}
+	if details.Name != "" {
+		name = details.Name
+	}
+
+	const insertTableStatisticStmt = `
+INSERT INTO system.table_statistics ("tableID", name, "columnIDs", "rowCount", "distinctCount", "nullCount", histogram)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+`
+	ie := InternalExecutor{LeaseManager: execCfg.LeaseManager}
+	if err := execCfg.DB.Txn(ctx, func(ctx context.Context, txn *client.Txn) error {
+		_, err := ie.ExecuteStatementInTransaction(
+			ctx, "insert-table-statistic", txn, insertTableStatisticStmt,
+			int(details.TableID), name, columnIDs, row[1], row[2], row[3], row[4],
+		)
+		return err
+	}); err != nil {
+		return err
+	}
+
+	if execCfg.TableStatsCache != nil {
+		execCfg.TableStatsCache.InvalidateTableStats(details.TableID)
+	}
+	return nil
+}
+
+// statsAggregatorOutputTypes are the types of the columns produced
+// by the sample aggregator: the sketch index, the row, distinct and NULL
+// counts and the encoded histogram (if any).
+var statsAggregatorOutputTypes = []sqlbase.ColumnType{
+	{Kind: sqlbase.ColumnType_INT},
+	{Kind: sqlbase.ColumnType_INT},
+	{Kind: sqlbase.ColumnType_INT},
+	{Kind: sqlbase.ColumnType_INT},
+	{Kind: sqlbase.ColumnType_BYTES},
+}
+
+// createStatsPlan creates the physical plan computing the statistic on the
+// given columns of a table.
+func (dsp *distSQLPlanner) createStatsPlan(
+	planCtx *planningCtx,
+	desc *sqlbase.TableDescriptor,
+	columnIDs []sqlbase.ColumnID,
+	statName string,
+) (physicalPlan, error) {
+	// Set up a full scan of the primary index which only returns the columns
+	// of the statistic.
+	scan := &scanNode{desc: *desc}
+	if err := scan.initDescDefaults(publicColumns, nil /* wantedColumns */); err != nil {
+		return physicalPlan{}, err
+	}
+	scan.spans = []roachpb.Span{scan.desc.PrimaryIndexSpan()}
+	outCols := make([]uint32, len(columnIDs))
+	for i, id := range columnIDs {
+		idx, ok := scan.colIdxMap[id]
+		if !ok {
+			return physicalPlan{}, errors.Errorf("column %d does not exist", id)
+		}
+		outCols[i] = uint32(idx)
+	}
+	plan, err := dsp.createTableReaders(planCtx, scan, outCols)
+	if err != nil {
+		return physicalPlan{}, err
+	}
+
+	sketch := distsqlrun.SketchSpec{
+		Columns:  make([]uint32, len(outCols)),
+		StatName: statName,
+	}
+	for i, col := range outCols {
+		sketch.Columns[i] = uint32(plan.planToStreamColMap[col])
+	}
+	// Histograms are only built on single columns which can be key-encoded.
+	if len(columnIDs) == 1 {
+		switch scan.cols[outCols[0]].Type.Kind {
+		case sqlbase.ColumnType_ARRAY, sqlbase.ColumnType_JSON:
+		default:
+			sketch.GenerateHistogram = true
+			sketch.HistogramMaxBuckets = createStatsHistogramBuckets
+		}
+	}
+	sketches := []distsqlrun.SketchSpec{sketch}
+
+	// The samplers output the sampled rows followed by the rank and the sketch
+	// columns.
+	samplerOutTypes := make([]sqlbase.ColumnType, 0, len(plan.ResultTypes)+5)
+	samplerOutTypes = append(samplerOutTypes, plan.ResultTypes...)
+	samplerOutTypes = append(samplerOutTypes,
+		sqlbase.ColumnType{Kind: sqlbase.ColumnType_INT},   // rank
+		sqlbase.ColumnType{Kind: sqlbase.ColumnType_INT},   // sketch index
+		sqlbase.ColumnType{Kind: sqlbase.ColumnType_INT},   // number of rows
+		sqlbase.ColumnType{Kind: sqlbase.ColumnType_INT},   // number of NULLs
+		sqlbase.ColumnType{Kind: sqlbase.ColumnType_BYTES}, // sketch data
+	)
+	plan.AddNoGroupingStage(
+		distsqlrun.ProcessorCoreUnion{Sampler: &distsqlrun.SamplerSpec{
+			Sketches:   sketches,
+			SampleSize: createStatsSampleSize,
+		}},
+		distsqlrun.PostProcessSpec{},
+		samplerOutTypes,
+		distsqlrun.Ordering{},
+	)
+
+	plan.AddSingleGroupStage(
+		dsp.nodeDesc.NodeID,
+		distsqlrun.ProcessorCoreUnion{SampleAggregator: &distsqlrun.SampleAggregatorSpec{
+			Sketches:   sketches,
+			SampleSize: createStatsSampleSize,
+		}},
+		distsqlrun.PostProcessSpec{},
+		statsAggregatorOutputTypes,
+	)
+
+	plan.planToStreamColMap = make([]int, len(statsAggregatorOutputTypes))
+	for i := range plan.planToStreamColMap {
+		plan.planToStreamColMap[i] = i
+	}
+	return plan, nil
+}
+
+// resumeCreateStats resumes a CREATE STATISTICS job adopted from a dead node.
+// The statistic is computed from scratch.
+func resumeCreateStats(ctx context.Context, execCfg *ExecutorConfig, job *JobLogger) error {
+	details, ok := job.Job.Details.(CreateStatsJobDetails)
+	if !ok {
+		return errors.Errorf("unexpected details type %T for a CREATE STATISTICS job", job.Job.Details)
+	}
+	nodeDesc, err := execCfg.Gossip.GetNodeDescriptor(execCfg.NodeID.Get())
+	if err != nil {
+		return err
+	}
+	// TODO(radu): investigate using the same distSQLPlanner from the executor.
+	dsp := newDistSQLPlanner(
+		*nodeDesc,
+		execCfg.RPCContext,
+		execCfg.DistSQLSrv,
+		execCfg.DistSender,
+		execCfg.Gossip,
+		execCfg.LeaseManager.stopper,
+		DistSQLPlannerTestingKnobs{},
+	)
+	return runCreateStats(ctx, execCfg, dsp, details)
+}
+
+func init() {
+	AddJobResumeHook(JobTypeCreateStats, resumeCreateStats)
+}
@@ -30,6 +30,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/migrations"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/server"
+	"github.com/cockroachdb/cockroach/pkg/sql"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/testutils"
 	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
@@ -352,3 +353,56 @@ func TestParallelCreateConflictingTables(t *testing.T) {
 		descIDStart,
 	)
 }
+
+// TestUUIDV4BytesDefaultUpgrade checks that tables created before uuid_v4()
+// returned a UUID, whose descriptors store `uuid_v4()` as the default
+// expression of a BYTES column, keep working.
+func TestUUIDV4BytesDefaultUpgrade(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	// The descriptor changes made must have an immediate effect
+	// so disable leases on tables.
+	defer sql.TestDisableTableLeases()()
+	params, _ := createTestServerParams()
+	s, sqlDB, kvDB := serverutils.StartServer(t, params)
+	defer s.Stopper().Stop(context.TODO())
+
+	if _, err := sqlDB.Exec(`
+CREATE DATABASE t;
+CREATE TABLE t.test (k INT PRIMARY KEY, id BYTES DEFAULT uuid_v4()::BYTES);
+`); err != nil {
+		t.Fatal(err)
+	}
+
+	// Rewrite the default expression the way older versions stored it.
+	tableDesc := sqlbase.GetTableDescriptor(kvDB, "t", "test")
+	oldDefault := "uuid_v4()"
+	tableDesc.Columns[1].DefaultExpr = &oldDefault
+	if err := kvDB.Put(
+		context.TODO(),
+		sqlbase.MakeDescMetadataKey(tableDesc.ID),
+		sqlbase.WrapDescriptor(tableDesc),
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, stmt := range []string{
+		`INSERT INTO t.test (k) VALUES (1)`,
+		`UPSERT INTO t.test (k) VALUES (2)`,
+		`INSERT INTO t.test VALUES (3, DEFAULT)`,
+		`UPDATE t.test SET id = DEFAULT WHERE k = 1`,
+	} {
+		if _, err := sqlDB.Exec(stmt); err != nil {
+			t.Fatalf("%s: %v", stmt, err)
+		}
+	}
+
+	var count, length int
+	if err := sqlDB.QueryRow(
+		`SELECT COUNT(DISTINCT id), MIN(LENGTH(id)) FROM t.test`,
+	).Scan(&count, &length); err != nil {
+		t.Fatal(err)
+	}
+	if count != 3 || length != 16 {
+		t.Fatalf("expected 3 distinct 16-byte ids, got %d ids of length %d", count, length)
+	}
+}
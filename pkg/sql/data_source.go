@@ -308,6 +308,19 @@ func (p *planner) getDataSource(
 ) (planDataSource, error) {
 	switch t := src.(type) {
 	case *parser.NormalizableTableName:
+		// Is this perhaps a reference to a common table expression?
+		cteName, err := t.Normalize()
+		if err != nil {
+			return planDataSource{}, err
+		}
+		ds, foundCTE, err := p.getCTEDataSource(ctx, cteName)
+		if err != nil {
+			return planDataSource{}, err
+		}
+		if foundCTE {
+			return ds, nil
+		}
+
 		// Usual case: a table.
 		tn, err := p.QualifyWithDatabase(ctx, t)
 		if err != nil {
@@ -520,7 +533,7 @@ func (p *planner) getPlanForDesc(
 
 	// This name designates a real table.
 	scan := p.Scan()
-	if err := scan.initTable(p, desc, hints, scanVisibility, wantedColumns); err != nil {
+	if err := scan.initTable(ctx, p, desc, hints, scanVisibility, wantedColumns); err != nil {
 		return planDataSource{}, err
 	}
 
@@ -551,13 +564,19 @@ func (p *planner) getViewPlan(
 	// SELECT privileges on the view, which is intended to allow for exposing
 	// some subset of a restricted table's data to less privileged users.
 	if !p.skipSelectPrivilegeChecks {
-		if err := p.CheckPrivilege(desc, privilege.SELECT); err != nil {
+		if err := p.CheckPrivilege(ctx, desc, privilege.SELECT); err != nil {
 			return planDataSource{}, err
 		}
 		p.skipSelectPrivilegeChecks = true
 		defer func() { p.skipSelectPrivilegeChecks = false }()
 	}
 
+	popWith, err := p.initWith(ctx, sel.With)
+	if err != nil {
+		return planDataSource{}, err
+	}
+	defer popWith(ctx)
+
 	// TODO(a-robinson): Support ORDER BY and LIMIT in views. Is it as simple as
 	// just passing the entire select here or will inserting an ORDER BY in the
 	// middle of a query plan break things?
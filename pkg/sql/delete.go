@@ -49,6 +49,12 @@ type deleteNode struct {
 func (p *planner) Delete(
 	ctx context.Context, n *parser.Delete, desiredTypes []parser.Type,
 ) (planNode, error) {
+	popWith, err := p.initWith(ctx, n.With)
+	if err != nil {
+		return nil, err
+	}
+	defer popWith(ctx)
+
 	tn, err := p.getAliasedTableName(n.Table)
 	if err != nil {
 		return nil, err
@@ -70,7 +76,10 @@ func (p *planner) Delete(
 	if err := p.fillFKTableMap(ctx, fkTables); err != nil {
 		return nil, err
 	}
-	rd, err := sqlbase.MakeRowDeleter(p.txn, en.tableDesc, fkTables, requestedCols, sqlbase.CheckFKs)
+	p.auditFKTables(en.tableDesc, fkTables, sqlbase.CheckDeletes)
+	rd, err := sqlbase.MakeRowDeleter(
+		p.txn, en.tableDesc, fkTables, requestedCols, sqlbase.CheckFKs, &p.evalCtx,
+	)
 	if err != nil {
 		return nil, err
 	}
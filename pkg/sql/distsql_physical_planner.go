@@ -229,7 +229,7 @@ func (dsp *distSQLPlanner) checkSupportForNode(node planNode) (distRecommendatio
 		return rec, nil
 
 	case *joinNode:
-		if n.joinType != joinTypeInner {
+		if n.algorithm != lookupJoin && n.joinType != joinTypeInner {
 			return 0, errors.Errorf("only inner join supported")
 		}
 		if err := dsp.checkExpr(n.pred.onCond); err != nil {
@@ -1190,6 +1190,121 @@ ColLoop:
 	return plan, nil
 }
 
+// createPlanForLookupJoin plans a join as a lookup join: the rows produced by
+// the left side are fed to JoinReaders which look up the matching rows in an
+// index of the right side's table (see joinNode.lookup). The right side is
+// never scanned.
+func (dsp *distSQLPlanner) createPlanForLookupJoin(
+	planCtx *planningCtx, n *joinNode,
+) (physicalPlan, error) {
+	plan, err := dsp.createPlanForNode(planCtx, n.left.plan)
+	if err != nil {
+		return physicalPlan{}, err
+	}
+	right := n.right.plan.(*scanNode)
+
+	joinReaderSpec := distsqlrun.JoinReaderSpec{
+		Table:    right.desc,
+		IndexIdx: uint32(n.lookup.indexIdx),
+		Type:     distsqlrun.JoinType_INNER,
+	}
+	if n.joinType == joinTypeLeftOuter {
+		joinReaderSpec.Type = distsqlrun.JoinType_LEFT_OUTER
+	}
+	for _, eqIdx := range n.lookup.eqIndices {
+		leftCol := plan.planToStreamColMap[n.pred.leftEqualityIndices[eqIdx]]
+		joinReaderSpec.LookupColumns = append(joinReaderSpec.LookupColumns, uint32(leftCol))
+	}
+
+	// The internal columns of the JoinReader are the columns of the left stream
+	// followed by all the columns of the table.
+	numLeftStreamCols := len(plan.ResultTypes)
+	types := make([]sqlbase.ColumnType, 0, numLeftStreamCols+len(right.desc.Columns))
+	types = append(types, plan.ResultTypes...)
+	for _, col := range right.desc.Columns {
+		types = append(types, col.Type)
+	}
+
+	// joinColMap maps the join columns (see createPlanForJoin) to the internal
+	// columns of the JoinReader.
+	joinColMap := make([]int, 0, len(n.columns))
+	for i := 0; i < n.pred.numMergedEqualityColumns; i++ {
+		joinColMap = append(joinColMap, plan.planToStreamColMap[n.pred.leftEqualityIndices[i]])
+	}
+	for i := 0; i < n.pred.numLeftCols; i++ {
+		joinColMap = append(joinColMap, plan.planToStreamColMap[i])
+	}
+	for i := 0; i < n.pred.numRightCols; i++ {
+		joinColMap = append(joinColMap, numLeftStreamCols+i)
+	}
+
+	// The ON expression of the JoinReader contains the equality constraints
+	// that aren't used for the lookup, the ON condition of the join and the
+	// filter of the right side's scan.
+	ivarHelper := distsqlplan.MakeTypeIndexedVarHelper(types)
+	var onCond parser.TypedExpr
+	usedForLookup := make([]bool, len(n.pred.leftEqualityIndices))
+	for _, eqIdx := range n.lookup.eqIndices {
+		usedForLookup[eqIdx] = true
+	}
+	for i, used := range usedForLookup {
+		if used {
+			continue
+		}
+		onCond = mergeConj(onCond, parser.NewTypedComparisonExpr(
+			parser.EQ,
+			ivarHelper.IndexedVar(plan.planToStreamColMap[n.pred.leftEqualityIndices[i]]),
+			ivarHelper.IndexedVar(numLeftStreamCols+n.pred.rightEqualityIndices[i]),
+		))
+	}
+	onCond = mergeConj(onCond, exprConvertVars(n.pred.onCond,
+		func(expr parser.VariableExpr) (bool, parser.Expr) {
+			return true, ivarHelper.IndexedVar(joinColMap[expr.(*parser.IndexedVar).Idx])
+		}))
+	onCond = mergeConj(onCond, exprConvertVars(right.filter,
+		func(expr parser.VariableExpr) (bool, parser.Expr) {
+			return true, ivarHelper.IndexedVar(numLeftStreamCols + expr.(*parser.IndexedVar).Idx)
+		}))
+	joinReaderSpec.OnExpr = distsqlplan.MakeExpression(onCond, nil)
+
+	post := distsqlrun.PostProcessSpec{
+		Projection: true,
+	}
+	joinToStreamColMap := makePlanToStreamColMap(len(n.columns))
+	for i, col := range joinColMap {
+		if !n.columns[i].Omitted {
+			joinToStreamColMap[i] = len(post.OutputColumns)
+			post.OutputColumns = append(post.OutputColumns, uint32(col))
+		}
+	}
+	outputTypes := getTypesForPlanResult(n, joinToStreamColMap)
+
+	if distributeIndexJoin && len(plan.ResultRouters) > 1 {
+		// Instantiate one join reader for every stream.
+		plan.AddNoGroupingStage(
+			distsqlrun.ProcessorCoreUnion{JoinReader: &joinReaderSpec},
+			post,
+			outputTypes,
+			distsqlrun.Ordering{},
+		)
+	} else {
+		// Use a single join reader (if there is a single stream, on that node; if
+		// not, on the gateway node).
+		node := dsp.nodeDesc.NodeID
+		if len(plan.ResultRouters) == 1 {
+			node = plan.Processors[plan.ResultRouters[0]].Node
+		}
+		plan.AddSingleGroupStage(
+			node,
+			distsqlrun.ProcessorCoreUnion{JoinReader: &joinReaderSpec},
+			post,
+			outputTypes,
+		)
+	}
+	plan.planToStreamColMap = joinToStreamColMap
+	return plan, nil
+}
+
 // getTypesForPlanResult returns the types of the elements in the result streams
 // of a plan that corresponds to a given planNode. If planToSreamColMap is nil,
 // a 1-1 mapping is assumed.
@@ -1241,6 +1356,10 @@ func (dsp *distSQLPlanner) createPlanForJoin(
 	//
 	//  - The routers of the joiner processors are the result routers of the plan.
 
+	if n.algorithm == lookupJoin {
+		return dsp.createPlanForLookupJoin(planCtx, n)
+	}
+
 	leftPlan, err := dsp.createPlanForNode(planCtx, n.left.plan)
 	if err != nil {
 		return physicalPlan{}, err
@@ -1373,6 +1492,23 @@ func (dsp *distSQLPlanner) createPlanForJoin(
 		joinerSpec.OnExpr = distsqlplan.MakeExpression(n.pred.onCond, joinColMap)
 	}
 
+	// Use a merge joiner if the join was planned with a merge join (see
+	// joinNode.chooseAlgorithm), and a hash joiner otherwise.
+	var core distsqlrun.ProcessorCoreUnion
+	var leftOrdering, rightOrdering distsqlrun.Ordering
+	if n.algorithm == mergeJoin {
+		leftOrdering = dsp.convertOrdering(n.leftMergeOrdering, leftPlan.planToStreamColMap)
+		rightOrdering = dsp.convertOrdering(n.rightMergeOrdering, rightPlan.planToStreamColMap)
+		core.MergeJoiner = &distsqlrun.MergeJoinerSpec{
+			LeftOrdering:  leftOrdering,
+			RightOrdering: rightOrdering,
+			OnExpr:        joinerSpec.OnExpr,
+			Type:          joinerSpec.Type,
+		}
+	} else {
+		core.HashJoiner = &joinerSpec
+	}
+
 	pIdxStart := distsqlplan.ProcessorIdx(len(p.Processors))
 
 	if len(nodes) == 1 {
@@ -1383,7 +1519,7 @@ func (dsp *distSQLPlanner) createPlanForJoin(
 					{ColumnTypes: leftTypes},
 					{ColumnTypes: rightTypes},
 				},
-				Core:   distsqlrun.ProcessorCoreUnion{HashJoiner: &joinerSpec},
+				Core:   core,
 				Post:   post,
 				Output: []distsqlrun.OutputRouterSpec{{Type: distsqlrun.OutputRouterSpec_PASS_THROUGH}},
 			},
@@ -1402,7 +1538,7 @@ func (dsp *distSQLPlanner) createPlanForJoin(
 						{ColumnTypes: leftTypes},
 						{ColumnTypes: rightTypes},
 					},
-					Core:   distsqlrun.ProcessorCoreUnion{HashJoiner: &joinerSpec},
+					Core:   core,
 					Post:   post,
 					Output: []distsqlrun.OutputRouterSpec{{Type: distsqlrun.OutputRouterSpec_PASS_THROUGH}},
 				},
@@ -1432,11 +1568,11 @@ func (dsp *distSQLPlanner) createPlanForJoin(
 	for bucket := 0; bucket < len(nodes); bucket++ {
 		pIdx := pIdxStart + distsqlplan.ProcessorIdx(bucket)
 
-		// Connect left routers to the processor's first input. Currently the join
-		// node doesn't care about the orderings of the left and right results.
-		p.MergeResultStreams(leftRouters, bucket, distsqlrun.Ordering{}, pIdx, 0)
+		// Connect left routers to the processor's first input. Only merge joiners
+		// care about the orderings of the left and right results.
+		p.MergeResultStreams(leftRouters, bucket, leftOrdering, pIdx, 0)
 		// Connect right routers to the processor's second input.
-		p.MergeResultStreams(rightRouters, bucket, distsqlrun.Ordering{}, pIdx, 1)
+		p.MergeResultStreams(rightRouters, bucket, rightOrdering, pIdx, 1)
 
 		p.ResultRouters = append(p.ResultRouters, pIdx)
 	}
@@ -17,6 +17,7 @@
 package distsqlrun
 
 import (
+	"bytes"
 	"strings"
 	"sync"
 	"unsafe"
@@ -24,6 +25,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/mon"
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/tracing"
 	"github.com/pkg/errors"
@@ -68,6 +70,11 @@ func GetAggregateInfo(
 //
 // aggregator's output schema is comprised of what is specified by the
 // accompanying SELECT expressions.
+//
+// If the groups don't fit in the memory budget, the rows of the groups that
+// are not already in memory are stored in temporary storage, sorted by the
+// grouping columns. These groups are aggregated one at a time after the
+// in-memory groups have been emitted.
 type aggregator struct {
 	flowCtx     *FlowCtx
 	input       RowSource
@@ -81,6 +88,9 @@ type aggregator struct {
 	inputCols columns
 	buckets   map[string]struct{} // The set of bucket keys.
 
+	// diskRows is set once the aggregator has spilled to disk.
+	diskRows *diskRowContainer
+
 	out procOutputHelper
 }
 
@@ -148,6 +158,11 @@ func (ag *aggregator) Run(ctx context.Context, wg *sync.WaitGroup) {
 			}
 		}
 	}()
+	defer func() {
+		if ag.diskRows != nil {
+			ag.diskRows.Close(ctx)
+		}
+	}()
 
 	ctx = log.WithLogTag(ctx, "Agg", nil)
 	ctx, span := tracing.ChildSpan(ctx, "aggregator")
@@ -167,33 +182,27 @@ func (ag *aggregator) Run(ctx context.Context, wg *sync.WaitGroup) {
 
 	// Queries like `SELECT MAX(n) FROM t` expect a row of NULLs if nothing was
 	// aggregated.
-	if len(ag.buckets) < 1 && len(ag.groupCols) == 0 {
+	if len(ag.buckets) < 1 && len(ag.groupCols) == 0 && ag.diskRows == nil {
 		ag.buckets[""] = struct{}{}
 	}
 
 	// Render the results.
-	var consumerDone bool
 	row := make(sqlbase.EncDatumRow, len(ag.funcs))
+	var consumerDone bool
+	var err error
 	for bucket := range ag.buckets {
-		for i, f := range ag.funcs {
-			result, err := f.get(bucket)
-			if err != nil {
-				DrainAndClose(ctx, ag.out.output, err, ag.input)
-				return
-			}
-			if result == nil {
-				// Special case useful when this is a local stage of a distributed
-				// aggregation.
-				result = parser.DNull
-			}
-			row[i] = sqlbase.DatumToEncDatum(ag.outputTypes[i], result)
-		}
-
-		consumerDone = !emitHelper(ctx, &ag.out, row, ProducerMetadata{})
-		if consumerDone {
+		if consumerDone, err = ag.emitBucket(ctx, bucket, row); err != nil || consumerDone {
 			break
 		}
 	}
+	if err == nil && !consumerDone && ag.diskRows != nil {
+		ag.resetBuckets(ctx)
+		consumerDone, err = ag.emitDiskGroups(ctx, row)
+	}
+	if err != nil {
+		DrainAndClose(ctx, ag.out.output, err, ag.input)
+		return
+	}
 	// If the consumer has been found to be done, emitHelper() already closed the
 	// output.
 	if !consumerDone {
@@ -201,6 +210,81 @@ func (ag *aggregator) Run(ctx context.Context, wg *sync.WaitGroup) {
 	}
 }
 
+// emitBucket renders the results of the aggregations for the given bucket into
+// row and sends it to the output. It returns true if the consumer doesn't need
+// any more rows, in which case the output has been closed.
+func (ag *aggregator) emitBucket(
+	ctx context.Context, bucket string, row sqlbase.EncDatumRow,
+) (consumerDone bool, err error) {
+	for i, f := range ag.funcs {
+		result, err := f.get(bucket)
+		if err != nil {
+			return false, err
+		}
+		if result == nil {
+			// Special case useful when this is a local stage of a distributed
+			// aggregation.
+			result = parser.DNull
+		}
+		row[i] = sqlbase.DatumToEncDatum(ag.outputTypes[i], result)
+	}
+	return !emitHelper(ctx, &ag.out, row, ProducerMetadata{}), nil
+}
+
+// emitDiskGroups aggregates and emits the groups that were spilled to disk.
+// The rows are sorted by the grouping columns, so the groups are aggregated
+// one at a time.
+func (ag *aggregator) emitDiskGroups(
+	ctx context.Context, row sqlbase.EncDatumRow,
+) (consumerDone bool, err error) {
+	it, err := ag.diskRows.NewIterator(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer it.Close()
+
+	var bucket, scratch []byte
+	started := false
+	for ; ; it.Next() {
+		ok, err := it.Valid()
+		if err != nil {
+			return false, err
+		}
+		var inputRow sqlbase.EncDatumRow
+		if ok {
+			if inputRow, err = it.Row(); err != nil {
+				return false, err
+			}
+			scratch, _, err = encodeColumnsOfRow(
+				&ag.datumAlloc, scratch[:0], inputRow, ag.groupCols, true, /* encodeNull */
+			)
+			if err != nil {
+				return false, err
+			}
+		}
+		if !ok || !started || !bytes.Equal(scratch, bucket) {
+			if started {
+				// We've seen all the rows of the previous group.
+				if consumerDone, err := ag.emitBucket(ctx, string(bucket), row); err != nil || consumerDone {
+					return consumerDone, err
+				}
+				ag.resetBuckets(ctx)
+			}
+			if !ok {
+				return false, nil
+			}
+			started = true
+			bucket = append(bucket[:0], scratch...)
+			if err := ag.addBucket(ctx, bucket); err != nil {
+				return false, err
+			}
+		}
+		if err := ag.accumulateRow(ctx, inputRow, bucket); err != nil {
+			return false, err
+		}
+	}
+}
+
 // accumulateRows reads and accumulates all input rows.
 // If no error is return, it means that all the rows from the input have been
 // consumed.
@@ -246,23 +330,91 @@ func (ag *aggregator) accumulateRows(ctx context.Context) (err error) {
 		if err != nil {
 			return err
 		}
+		scratch = encoded[:0]
 
-		if err := ag.bucketsAcc.Grow(ctx, int64(len(encoded))); err != nil {
+		if _, ok := ag.buckets[string(encoded)]; !ok {
+			// Once we've spilled to disk, rows of new groups are stored there.
+			spill := ag.diskRows != nil || ag.flowCtx.testingKnobs.ForceDiskSpill
+			if !spill {
+				if err := ag.addBucket(ctx, encoded); err != nil {
+					if !canSpillToDisk(ag.flowCtx, err) {
+						return err
+					}
+					log.VEventf(ctx, 1, "aggregator spilling to disk: %v", err)
+					spill = true
+				}
+			}
+			if spill {
+				if ag.diskRows == nil {
+					diskRows := makeDiskRowContainer(ag.flowCtx, ag.input.Types(), ag.groupOrdering())
+					ag.diskRows = &diskRows
+				}
+				if err := ag.diskRows.AddRow(ctx, row); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+		if err := ag.accumulateRow(ctx, row, encoded); err != nil {
 			return err
 		}
+	}
+}
 
-		ag.buckets[string(encoded)] = struct{}{}
-		// Feed the func holders for this bucket the non-grouping datums.
-		for i, colIdx := range ag.inputCols {
-			if err := row[colIdx].EnsureDecoded(&ag.datumAlloc); err != nil {
-				return err
-			}
-			if err := ag.funcs[i].add(ctx, encoded, row[colIdx].Datum); err != nil {
-				return err
-			}
+// addBucket adds a new bucket, accounting for the memory used by its key and
+// by the aggregate functions that will be created for it. If the memory budget
+// is exhausted, the bucket is not added.
+func (ag *aggregator) addBucket(ctx context.Context, bucket []byte) error {
+	// TODO(radu): we should account for the size of the aggregate function
+	// implementations (this needs to be done in each aggregate constructor).
+	usage := int64(len(bucket)) + int64(len(ag.funcs))*(int64(len(bucket))+sizeOfAggregateFunc)
+	if err := ag.bucketsAcc.Grow(ctx, usage); err != nil {
+		return err
+	}
+	ag.buckets[string(bucket)] = struct{}{}
+	return nil
+}
+
+// accumulateRow feeds the func holders for the given bucket the non-grouping
+// datums of row.
+func (ag *aggregator) accumulateRow(
+	ctx context.Context, row sqlbase.EncDatumRow, bucket []byte,
+) error {
+	for i, colIdx := range ag.inputCols {
+		if err := row[colIdx].EnsureDecoded(&ag.datumAlloc); err != nil {
+			return err
+		}
+		if err := ag.funcs[i].add(ctx, bucket, row[colIdx].Datum); err != nil {
+			return err
 		}
-		scratch = encoded[:0]
 	}
+	return nil
+}
+
+// resetBuckets closes the aggregate functions of all the buckets and releases
+// their memory.
+func (ag *aggregator) resetBuckets(ctx context.Context) {
+	for _, f := range ag.funcs {
+		for _, aggFunc := range f.buckets {
+			aggFunc.Close(ctx)
+		}
+		f.buckets = make(map[string]parser.AggregateFunc)
+		if f.seen != nil {
+			f.seen = make(map[string]struct{})
+		}
+	}
+	ag.buckets = make(map[string]struct{})
+	ag.bucketsAcc.Clear(ctx)
+}
+
+// groupOrdering returns the ordering on the grouping columns used to sort the
+// rows spilled to disk.
+func (ag *aggregator) groupOrdering() sqlbase.ColumnOrdering {
+	ordering := make(sqlbase.ColumnOrdering, len(ag.groupCols))
+	for i, colIdx := range ag.groupCols {
+		ordering[i] = sqlbase.ColumnOrderInfo{ColIdx: int(colIdx), Direction: encoding.Ascending}
+	}
+	return ordering
 }
 
 type aggregateFuncHolder struct {
@@ -304,17 +456,11 @@ func (a *aggregateFuncHolder) add(ctx context.Context, bucket []byte, d parser.D
 
 	impl, ok := a.buckets[string(bucket)]
 	if !ok {
-		// TODO(radu): we should account for the size of impl (this needs to be done
-		// in each aggregate constructor).
-		impl = a.create(&a.group.flowCtx.evalCtx)
-		usage := int64(len(bucket))
-		usage += sizeOfAggregateFunc
+		// The memory used by impl was accounted for by aggregator.addBucket.
 		// TODO(radu): this model of each func having a map of buckets (one per
 		// group) for each func plus a global map is very wasteful. We should have a
 		// single map that stores all the AggregateFuncs.
-		if err := a.bucketsMemAcc.Grow(ctx, usage); err != nil {
-			return err
-		}
+		impl = a.create(&a.group.flowCtx.evalCtx)
 		a.buckets[string(bucket)] = impl
 	}
 
@@ -274,52 +274,59 @@ func TestAggregator(t *testing.T) {
 	}
 
 	for _, c := range testCases {
-		ags := c.spec
+		for _, spill := range []bool{false, true} {
+			ags := c.spec
 
-		var types []sqlbase.ColumnType
-		if len(c.input) == 0 {
-			types = []sqlbase.ColumnType{columnTypeInt}
-		}
-		in := NewRowBuffer(types, c.input, RowBufferArgs{})
-		out := &RowBuffer{}
-
-		monitor := mon.MakeUnlimitedMonitor(context.Background(), "test", nil, nil, math.MaxInt64)
-		flowCtx := FlowCtx{
-			evalCtx: parser.EvalContext{Mon: &monitor},
-		}
+			types := intColumnTypes(c.input)
+			if len(c.input) == 0 {
+				types = []sqlbase.ColumnType{columnTypeInt}
+			}
+			in := NewRowBuffer(types, c.input, RowBufferArgs{})
+			out := &RowBuffer{}
 
-		ag, err := newAggregator(&flowCtx, &ags, in, &PostProcessSpec{}, out)
-		if err != nil {
-			t.Fatal(err)
-		}
+			monitor := mon.MakeUnlimitedMonitor(context.Background(), "test", nil, nil, math.MaxInt64)
+			flowCtx := FlowCtx{
+				evalCtx: parser.EvalContext{Mon: &monitor},
+			}
+			cleanup := func() {}
+			if spill {
+				cleanup = setTempStorage(&flowCtx, true /* forceDiskSpill */)
+			}
 
-		ag.Run(context.Background(), nil)
+			ag, err := newAggregator(&flowCtx, &ags, in, &PostProcessSpec{}, out)
+			if err != nil {
+				t.Fatal(err)
+			}
 
-		var expected []string
-		for _, row := range c.expected {
-			expected = append(expected, row.String())
-		}
-		sort.Strings(expected)
-		expStr := strings.Join(expected, "")
+			ag.Run(context.Background(), nil)
 
-		var rets []string
-		for {
-			row, meta := out.Next()
-			if !meta.Empty() {
-				t.Fatalf("unexpected metadata: %v", meta)
+			var expected []string
+			for _, row := range c.expected {
+				expected = append(expected, row.String())
 			}
-			if row == nil {
-				break
+			sort.Strings(expected)
+			expStr := strings.Join(expected, "")
+
+			var rets []string
+			for {
+				row, meta := out.Next()
+				if !meta.Empty() {
+					t.Fatalf("unexpected metadata: %v", meta)
+				}
+				if row == nil {
+					break
+				}
+				rets = append(rets, row.String())
 			}
-			rets = append(rets, row.String())
-		}
-		sort.Strings(rets)
-		retStr := strings.Join(rets, "")
+			sort.Strings(rets)
+			retStr := strings.Join(rets, "")
 
-		if expStr != retStr {
-			t.Errorf("invalid results; expected:\n   %s\ngot:\n   %s",
-				expStr, retStr)
+			if expStr != retStr {
+				t.Errorf("invalid results (spill: %t); expected:\n   %s\ngot:\n   %s",
+					spill, expStr, retStr)
+			}
+			cleanup()
+			monitor.Stop(context.Background())
 		}
-		monitor.Stop(context.Background())
 	}
 }
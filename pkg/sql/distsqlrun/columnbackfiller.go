@@ -169,6 +169,7 @@ func (cb *columnBackfiller) runChunk(
 		requestedCols = append(requestedCols, cb.added...)
 		ru, err := sqlbase.MakeRowUpdater(
 			txn, &tableDesc, fkTables, cb.updateCols, requestedCols, sqlbase.RowUpdaterOnlyColumns,
+			&cb.flowCtx.evalCtx,
 		)
 		if err != nil {
 			return err
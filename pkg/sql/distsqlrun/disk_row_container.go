@@ -0,0 +1,227 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package distsqlrun
+
+import (
+	"bytes"
+	"sync/atomic"
+
+	"golang.org/x/net/context"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/mon"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/storage/engine"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+)
+
+// diskRowContainerBatchSize is the number of bytes buffered by a
+// diskRowContainer before they are written to the temporary storage engine.
+const diskRowContainerBatchSize = 256 << 10 // 256 KiB
+
+// tempStoragePrefixes is used to generate the unique key prefixes under which
+// the users of this node's temporary storage engine store their data.
+var tempStoragePrefixes uint64
+
+// NewTempStoragePrefix returns a key prefix that no other user of this node's
+// temporary storage engine stores data under.
+func NewTempStoragePrefix() roachpb.Key {
+	id := atomic.AddUint64(&tempStoragePrefixes, 1)
+	return roachpb.Key(encoding.EncodeUvarintAscending(nil, id))
+}
+
+// diskRowContainer is a container of rows backed by the node's temporary
+// storage engine. It is used by processors that have exhausted their memory
+// budget.
+//
+// Each row is stored under a key made up of the container's unique prefix,
+// the key encodings of the ordering columns and a sequence number (so that
+// rows with equal ordering columns are kept, in insertion order). The value is
+// the VALUE encoding of the entire row. Iterating over the container thus
+// produces the rows sorted according to the ordering, which makes the
+// container a simple external sorter.
+type diskRowContainer struct {
+	engine   engine.Engine
+	batch    engine.Batch
+	prefix   roachpb.Key
+	types    []sqlbase.ColumnType
+	ordering sqlbase.ColumnOrdering
+
+	// len is the number of rows in the container. It is also used as the
+	// sequence number of the next row.
+	len int
+	// batchBytes is the number of bytes buffered in batch.
+	batchBytes int
+	// diskAcc accounts for the temporary storage used by the container.
+	diskAcc mon.BoundAccount
+
+	scratchKey []byte
+	scratchVal []byte
+	datumAlloc sqlbase.DatumAlloc
+}
+
+// makeDiskRowContainer creates a diskRowContainer storing rows of the given
+// types, sorted according to ordering. The caller must ensure the flow has
+// temporary storage configured (see canSpillToDisk).
+func makeDiskRowContainer(
+	flowCtx *FlowCtx, types []sqlbase.ColumnType, ordering sqlbase.ColumnOrdering,
+) diskRowContainer {
+	return diskRowContainer{
+		engine:   flowCtx.tempStorage,
+		batch:    flowCtx.tempStorage.NewWriteOnlyBatch(),
+		prefix:   NewTempStoragePrefix(),
+		types:    types,
+		ordering: ordering,
+		diskAcc:  flowCtx.diskMonitor.MakeBoundAccount(),
+	}
+}
+
+// Len returns the number of rows in the container.
+func (d *diskRowContainer) Len() int {
+	return d.len
+}
+
+// AddRow adds a row to the container.
+func (d *diskRowContainer) AddRow(ctx context.Context, row sqlbase.EncDatumRow) error {
+	if len(row) != len(d.types) {
+		log.Fatalf(ctx, "invalid row length %d, expected %d", len(row), len(d.types))
+	}
+	key := append(d.scratchKey[:0], d.prefix...)
+	var err error
+	for _, o := range d.ordering {
+		enc := sqlbase.DatumEncoding_ASCENDING_KEY
+		if o.Direction == encoding.Descending {
+			enc = sqlbase.DatumEncoding_DESCENDING_KEY
+		}
+		if key, err = row[o.ColIdx].Encode(&d.datumAlloc, enc, key); err != nil {
+			return err
+		}
+	}
+	key = encoding.EncodeUvarintAscending(key, uint64(d.len))
+	val := d.scratchVal[:0]
+	for i := range row {
+		if val, err = row[i].Encode(&d.datumAlloc, sqlbase.DatumEncoding_VALUE, val); err != nil {
+			return err
+		}
+	}
+	d.scratchKey, d.scratchVal = key, val
+
+	if err := d.diskAcc.Grow(ctx, int64(len(key)+len(val))); err != nil {
+		return pgerror.NewErrorf(pgerror.CodeDiskFullError,
+			"temporary storage budget exceeded: %v", err)
+	}
+	if err := d.batch.Put(engine.MVCCKey{Key: key}, val); err != nil {
+		return err
+	}
+	d.len++
+	d.batchBytes += len(key) + len(val)
+	if d.batchBytes >= diskRowContainerBatchSize {
+		return d.flush()
+	}
+	return nil
+}
+
+// flush writes the buffered rows to the engine.
+func (d *diskRowContainer) flush() error {
+	if d.batchBytes == 0 {
+		return nil
+	}
+	err := d.batch.Commit(false /* sync */)
+	d.batch.Close()
+	d.batch = d.engine.NewWriteOnlyBatch()
+	d.batchBytes = 0
+	return err
+}
+
+// Close removes the rows of the container from the engine and releases the
+// container's resources.
+func (d *diskRowContainer) Close(ctx context.Context) {
+	d.batch.Close()
+	if d.len > 0 {
+		if err := d.engine.ClearRange(
+			engine.MVCCKey{Key: d.prefix}, engine.MVCCKey{Key: d.prefix.PrefixEnd()},
+		); err != nil {
+			log.Warningf(ctx, "could not clear temporary storage: %s", err)
+		}
+	}
+	d.diskAcc.Close(ctx)
+}
+
+// NewIterator returns an iterator over the rows of the container, in the
+// order given by the container's ordering. No rows can be added to the
+// container while the iterator is open.
+func (d *diskRowContainer) NewIterator(ctx context.Context) (*diskRowIterator, error) {
+	if err := d.flush(); err != nil {
+		return nil, err
+	}
+	it := &diskRowIterator{
+		d:    d,
+		iter: d.engine.NewIterator(false /* prefix */),
+		row:  make(sqlbase.EncDatumRow, len(d.types)),
+	}
+	it.iter.Seek(engine.MVCCKey{Key: d.prefix})
+	return it, nil
+}
+
+// diskRowIterator iterates over the rows of a diskRowContainer.
+type diskRowIterator struct {
+	d    *diskRowContainer
+	iter engine.Iterator
+	row  sqlbase.EncDatumRow
+}
+
+// Valid returns whether the iterator is positioned on a row.
+func (it *diskRowIterator) Valid() (bool, error) {
+	if ok, err := it.iter.Valid(); !ok || err != nil {
+		return false, err
+	}
+	return bytes.HasPrefix(it.iter.UnsafeKey().Key, it.d.prefix), nil
+}
+
+// Next advances the iterator to the next row.
+func (it *diskRowIterator) Next() {
+	it.iter.Next()
+}
+
+// Row returns the row the iterator is positioned on. The slice is reused so it
+// is only valid until the next call to Row.
+func (it *diskRowIterator) Row() (sqlbase.EncDatumRow, error) {
+	buf := it.iter.Value()
+	for i := range it.row {
+		var err error
+		it.row[i], buf, err = sqlbase.EncDatumFromBuffer(it.d.types[i], sqlbase.DatumEncoding_VALUE, buf)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return it.row, nil
+}
+
+// Close releases the iterator.
+func (it *diskRowIterator) Close() {
+	it.iter.Close()
+}
+
+// canSpillToDisk returns whether err is a memory budget error that a
+// processor can recover from by moving its rows to temporary storage.
+func canSpillToDisk(flowCtx *FlowCtx, err error) bool {
+	if flowCtx.tempStorage == nil {
+		return false
+	}
+	pgErr, ok := pgerror.GetPGCause(err)
+	return ok && pgErr.Code == pgerror.CodeOutOfMemoryError
+}
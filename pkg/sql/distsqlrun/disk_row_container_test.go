@@ -0,0 +1,159 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package distsqlrun
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/net/context"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/mon"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/storage/engine"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/randutil"
+)
+
+// setTempStorage configures flowCtx to use a new in-memory temporary storage
+// engine and returns a function that releases it.
+func setTempStorage(flowCtx *FlowCtx, forceDiskSpill bool) func() {
+	tempEngine := engine.NewInMem(roachpb.Attributes{}, 1<<20)
+	diskMonitor := mon.MakeUnlimitedMonitor(context.Background(), "test-disk", nil, nil, math.MaxInt64)
+	flowCtx.tempStorage = tempEngine
+	flowCtx.diskMonitor = &diskMonitor
+	flowCtx.testingKnobs.ForceDiskSpill = forceDiskSpill
+	return func() {
+		diskMonitor.Stop(context.Background())
+		tempEngine.Close()
+	}
+}
+
+func TestDiskRowContainer(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	monitor := mon.MakeUnlimitedMonitor(ctx, "test", nil, nil, math.MaxInt64)
+	defer monitor.Stop(ctx)
+	flowCtx := FlowCtx{evalCtx: parser.EvalContext{Mon: &monitor}}
+	defer setTempStorage(&flowCtx, false /* forceDiskSpill */)()
+
+	rng, _ := randutil.NewPseudoRand()
+	columnTypeInt := sqlbase.ColumnType{Kind: sqlbase.ColumnType_INT}
+	types := []sqlbase.ColumnType{columnTypeInt, columnTypeInt, columnTypeInt}
+	ordering := sqlbase.ColumnOrdering{
+		{ColIdx: 1, Direction: encoding.Ascending},
+		{ColIdx: 0, Direction: encoding.Descending},
+	}
+
+	const numRows = 1000
+	rows := make(sqlbase.EncDatumRows, numRows)
+	d := makeDiskRowContainer(&flowCtx, types, ordering)
+	for i := range rows {
+		rows[i] = make(sqlbase.EncDatumRow, len(types))
+		for j := range rows[i] {
+			var datum parser.Datum = parser.DNull
+			if rng.Intn(10) != 0 {
+				datum = parser.NewDInt(parser.DInt(rng.Intn(20)))
+			}
+			rows[i][j] = sqlbase.DatumToEncDatum(columnTypeInt, datum)
+		}
+		if err := d.AddRow(ctx, rows[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if d.Len() != numRows {
+		t.Fatalf("expected %d rows, got %d", numRows, d.Len())
+	}
+
+	it, err := d.NewIterator(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var datumAlloc sqlbase.DatumAlloc
+	var prev sqlbase.EncDatumRow
+	n := 0
+	for ; ; it.Next() {
+		if ok, err := it.Valid(); err != nil {
+			t.Fatal(err)
+		} else if !ok {
+			break
+		}
+		row, err := it.Row()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if prev != nil {
+			if cmp, err := prev.Compare(&datumAlloc, ordering, &flowCtx.evalCtx, row); err != nil {
+				t.Fatal(err)
+			} else if cmp > 0 {
+				t.Fatalf("rows out of order: %s before %s", prev, row)
+			}
+		}
+		prev = append(prev[:0], row...)
+		n++
+	}
+	it.Close()
+	if n != numRows {
+		t.Fatalf("expected to iterate over %d rows, got %d", numRows, n)
+	}
+
+	d.Close(ctx)
+	iter := flowCtx.tempStorage.NewIterator(false /* prefix */)
+	defer iter.Close()
+	iter.Seek(engine.NilKey)
+	if ok, err := iter.Valid(); err != nil {
+		t.Fatal(err)
+	} else if ok {
+		t.Fatalf("expected temporary storage to be empty, found key %s", iter.Key())
+	}
+}
+
+func TestDiskRowContainerBudget(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	monitor := mon.MakeUnlimitedMonitor(ctx, "test", nil, nil, math.MaxInt64)
+	defer monitor.Stop(ctx)
+	tempEngine := engine.NewInMem(roachpb.Attributes{}, 1<<20)
+	defer tempEngine.Close()
+	diskMonitor := mon.MakeMonitor("test-disk", nil, nil, 1 /* increment */, math.MaxInt64)
+	diskMonitor.Start(ctx, nil, mon.MakeStandaloneBudget(100))
+	defer diskMonitor.Stop(ctx)
+	flowCtx := FlowCtx{
+		evalCtx:     parser.EvalContext{Mon: &monitor},
+		tempStorage: tempEngine,
+		diskMonitor: &diskMonitor,
+	}
+
+	columnTypeInt := sqlbase.ColumnType{Kind: sqlbase.ColumnType_INT}
+	d := makeDiskRowContainer(&flowCtx, []sqlbase.ColumnType{columnTypeInt}, nil /* ordering */)
+	defer d.Close(ctx)
+	row := sqlbase.EncDatumRow{sqlbase.DatumToEncDatum(columnTypeInt, parser.NewDInt(1))}
+	for i := 0; i < 100; i++ {
+		if err := d.AddRow(ctx, row); err != nil {
+			pgErr, ok := pgerror.GetPGCause(err)
+			if !ok || pgErr.Code != pgerror.CodeDiskFullError {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			return
+		}
+	}
+	t.Fatal("expected temporary storage budget to be exceeded")
+}
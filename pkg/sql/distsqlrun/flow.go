@@ -27,7 +27,9 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/internal/client"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/rpc"
+	"github.com/cockroachdb/cockroach/pkg/sql/mon"
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/storage/engine"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 )
@@ -68,6 +70,12 @@ type FlowCtx struct {
 	// run.
 	nodeID       roachpb.NodeID
 	testingKnobs TestingKnobs
+
+	// tempStorage is used by the processors to spill data that does not fit in
+	// their memory budget to disk. It is nil if spilling is disabled.
+	tempStorage engine.Engine
+	// diskMonitor is used to account for the usage of tempStorage.
+	diskMonitor *mon.MemoryMonitor
 }
 
 func (flowCtx *FlowCtx) setupTxn() *client.Txn {
@@ -114,6 +122,14 @@ type Flow struct {
 	doneFn func()
 
 	status flowStatus
+
+	// ctxCancel is used for the cancellation of the flow's context; it is set
+	// when the flow is started. Canceling the context of a flow stops its
+	// processors and makes its inbound streams return an error, which is
+	// propagated to the flows on the other side of the streams.
+	ctxCancel context.CancelFunc
+	// ctxDone is the Done channel of the flow's context.
+	ctxDone <-chan struct{}
 }
 
 func newFlow(flowCtx FlowCtx, flowReg *flowRegistry, syncFlowConsumer RowReceiver) *Flow {
@@ -305,6 +321,8 @@ func (f *Flow) Start(ctx context.Context, doneFn func()) {
 		ctx, 1, "starting (%d processors, %d outboxes)", len(f.outboxes), len(f.processors),
 	)
 	f.status = FlowRunning
+	ctx, f.ctxCancel = context.WithCancel(ctx)
+	f.ctxDone = ctx.Done()
 
 	// Once we call RegisterFlow, the inbound streams become accessible; we must
 	// set up the WaitGroup counter before.
@@ -315,6 +333,7 @@ func (f *Flow) Start(ctx context.Context, doneFn func()) {
 		log.Infof(ctx, "registered flow %s", f.id.Short())
 	}
 	for _, o := range f.outboxes {
+		o.flowCtxCancel = f.ctxCancel
 		o.start(ctx, &f.waitGroup)
 	}
 	for _, p := range f.processors {
@@ -343,6 +362,9 @@ func (f *Flow) Cleanup(ctx context.Context) {
 	if f.status != FlowNotStarted {
 		f.flowRegistry.UnregisterFlow(f.id)
 	}
+	if f.ctxCancel != nil {
+		f.ctxCancel()
+	}
 	f.status = FlowFinished
 	f.doneFn()
 	f.doneFn = nil
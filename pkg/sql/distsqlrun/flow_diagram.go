@@ -114,6 +114,15 @@ func (jr *JoinReaderSpec) summary() (string, []string) {
 	details := []string{
 		fmt.Sprintf("%s@%s", index, jr.Table.Name),
 	}
+	if len(jr.LookupColumns) > 0 {
+		details = append(details, fmt.Sprintf("Lookup: %s", colListStr(jr.LookupColumns)))
+		if jr.Type != JoinType_INNER {
+			details = append(details, fmt.Sprintf("Type: %s", jr.Type.String()))
+		}
+		if jr.OnExpr.Expr != "" {
+			details = append(details, fmt.Sprintf("ON %s", jr.OnExpr.Expr))
+		}
+	}
 	return "JoinReader", details
 }
 
@@ -158,6 +167,26 @@ func (d *DistinctSpec) summary() (string, []string) {
 	return "Distinct", details
 }
 
+func (s *SamplerSpec) summary() (string, []string) {
+	details := []string{fmt.Sprintf("SampleSize: %d", s.SampleSize)}
+	for _, sk := range s.Sketches {
+		details = append(details, fmt.Sprintf("Stat: %s", colListStr(sk.Columns)))
+	}
+	return "Sampler", details
+}
+
+func (s *SampleAggregatorSpec) summary() (string, []string) {
+	details := []string{fmt.Sprintf("SampleSize: %d", s.SampleSize)}
+	for _, sk := range s.Sketches {
+		s := fmt.Sprintf("Stat: %s", colListStr(sk.Columns))
+		if sk.GenerateHistogram {
+			s = fmt.Sprintf("%s (%d buckets)", s, sk.HistogramMaxBuckets)
+		}
+		details = append(details, s)
+	}
+	return "SampleAggregator", details
+}
+
 func (is *InputSyncSpec) summary() (string, []string) {
 	switch is.Type {
 	case InputSyncSpec_UNORDERED:
@@ -17,6 +17,8 @@
 package distsqlrun
 
 import (
+	"hash"
+	"hash/fnv"
 	"sync"
 	"unsafe"
 
@@ -42,6 +44,15 @@ type bucket struct {
 const sizeOfBucket = int64(unsafe.Sizeof(bucket{}))
 const sizeOfRowIdx = int64(unsafe.Sizeof(int(0)))
 
+// hashJoinerNumPartitions is the number of partitions each input is split
+// into when the hashJoiner spills to disk.
+const hashJoinerNumPartitions = 16
+
+// hashJoinerMaxPartitionLevel is the number of times a pair of partitions
+// whose right partition still doesn't fit in the memory budget is split into
+// smaller partitions, before they are joined in chunks instead.
+const hashJoinerMaxPartitionLevel = 3
+
 // HashJoiner performs hash join, it has two input streams and one output.
 //
 // It works by reading the entire right stream and putting it in a hash
@@ -50,9 +61,20 @@ const sizeOfRowIdx = int64(unsafe.Sizeof(int(0)))
 // guaranteed that results that involve the left stream preserve the ordering;
 // i.e. all results that stem from left row (i) precede results that stem from
 // left row (i+1).
+//
+// If the right stream doesn't fit in the memory budget, the hashJoiner falls
+// back to a grace hash join: both streams are partitioned by a hash of their
+// equality columns into partitions stored in temporary storage, and each pair
+// of partitions is then joined in memory. A pair of partitions whose right
+// partition doesn't fit in memory either is partitioned again with a different
+// hash, and if that doesn't divide it, e.g. because most of its rows have the
+// same equality columns, the right partition is joined in chunks that fit in
+// memory. In this case, the ordering of the left stream is not preserved.
 type hashJoiner struct {
 	joinerBase
 
+	flowCtx *FlowCtx
+
 	// All the rows are stored in this container. The buckets reference these rows
 	// by index.
 	rows rowContainer
@@ -65,6 +87,13 @@ type hashJoiner struct {
 	rightEqCols columns
 	buckets     map[string]bucket
 	datumAlloc  sqlbase.DatumAlloc
+
+	// leftPartitions and rightPartitions are set once the hashJoiner has spilled
+	// to disk.
+	leftPartitions  []diskRowContainer
+	rightPartitions []diskRowContainer
+	hasher          hash.Hash32
+	scratch         []byte
 }
 
 var _ processor = &hashJoiner{}
@@ -78,6 +107,7 @@ func newHashJoiner(
 	output RowReceiver,
 ) (*hashJoiner, error) {
 	h := &hashJoiner{
+		flowCtx:     flowCtx,
 		leftEqCols:  columns(spec.LeftEqColumns),
 		rightEqCols: columns(spec.RightEqColumns),
 		buckets:     make(map[string]bucket),
@@ -114,6 +144,7 @@ func (h *hashJoiner) Run(ctx context.Context, wg *sync.WaitGroup) {
 
 	defer h.bucketsAcc.Close(ctx)
 	defer h.rows.Close(ctx)
+	defer h.closePartitions(ctx)
 
 	moreRows, err := h.buildPhase(ctx)
 	if err != nil {
@@ -128,20 +159,14 @@ func (h *hashJoiner) Run(ctx context.Context, wg *sync.WaitGroup) {
 		return
 	}
 
-	if h.joinType == rightOuter || h.joinType == fullOuter {
-		for k, bucket := range h.buckets {
-			if err := h.bucketsAcc.Grow(
-				ctx, int64(sizeOfBoolSlice+uintptr(len(bucket.rows))*sizeOfBool),
-			); err != nil {
-				DrainAndClose(ctx, h.out.output, err, h.leftSource)
-				return
-			}
-			bucket.seen = make([]bool, len(bucket.rows))
-			h.buckets[k] = bucket
-		}
+	if h.rightPartitions != nil {
+		log.VEventf(ctx, 1, "build phase complete, right stream spilled to disk")
+		moreRows, err = h.graceJoinPhase(ctx)
+	} else {
+		h.initSeen()
+		log.VEventf(ctx, 1, "build phase complete")
+		moreRows, err = h.probePhase(ctx)
 	}
-	log.VEventf(ctx, 1, "build phase complete")
-	moreRows, err = h.probePhase(ctx)
 	if moreRows || err != nil {
 		// We got an error. We still want to drain. Any error encountered while
 		// draining will be swallowed, and the original error will be forwarded to
@@ -159,6 +184,9 @@ func (h *hashJoiner) Run(ctx context.Context, wg *sync.WaitGroup) {
 // output (for outer joins). In such cases it is possible that the buildPhase
 // will fully satisfy the consumer.
 //
+// If the memory budget is exhausted and temporary storage is available, the
+// rows are instead partitioned into h.rightPartitions.
+//
 // Returns true if more rows are needed to be passed to the output, false
 // otherwise. If it returns false, both the inputs and the output have been
 // properly drained and/or closed.
@@ -209,29 +237,93 @@ func (h *hashJoiner) buildPhase(ctx context.Context) (bool, error) {
 			continue
 		}
 
+		if h.rightPartitions == nil && h.flowCtx.testingKnobs.ForceDiskSpill {
+			if err := h.spillRightRows(ctx); err != nil {
+				return false, err
+			}
+		}
+		if h.rightPartitions != nil {
+			if err := h.rightPartitions[h.partitionIdx(encoded, 0 /* level */)].AddRow(ctx, rrow); err != nil {
+				return false, err
+			}
+			continue
+		}
+
 		rowIdx := h.rows.Len()
-		if err := h.rows.AddRow(ctx, rrow); err != nil {
-			return false, err
+		if err := h.addRowToBuckets(ctx, rrow, encoded); err != nil {
+			if !canSpillToDisk(h.flowCtx, err) {
+				return false, err
+			}
+			log.VEventf(ctx, 1, "hash joiner spilling to disk: %v", err)
+			// The row may or may not have made it into h.rows.
+			rowAdded := h.rows.Len() > rowIdx
+			if err := h.spillRightRows(ctx); err != nil {
+				return false, err
+			}
+			if !rowAdded {
+				if err := h.rightPartitions[h.partitionIdx(encoded, 0 /* level */)].AddRow(ctx, rrow); err != nil {
+					return false, err
+				}
+			}
 		}
+	}
+}
+
+// addRowToBuckets adds a right row with the given encoding of its equality
+// columns to the in-memory hash table.
+func (h *hashJoiner) addRowToBuckets(
+	ctx context.Context, rrow sqlbase.EncDatumRow, encoded []byte,
+) error {
+	rowIdx := h.rows.Len()
+	if err := h.rows.AddRow(ctx, rrow); err != nil {
+		return err
+	}
 
-		b, bucketExists := h.buckets[string(encoded)]
+	b, bucketExists := h.buckets[string(encoded)]
 
-		// Acount for the memory usage of rowIdx, map key, and bucket.
-		usage := sizeOfRowIdx
+	// Acount for the memory usage of rowIdx, map key, and bucket, as well as
+	// that of the seen slices allocated by initSeen.
+	usage := sizeOfRowIdx
+	if !bucketExists {
+		usage += int64(len(encoded))
+		usage += sizeOfBucket
+	}
+	if h.joinType == rightOuter || h.joinType == fullOuter {
+		usage += int64(sizeOfBool)
 		if !bucketExists {
-			usage += int64(len(encoded))
-			usage += sizeOfBucket
+			usage += int64(sizeOfBoolSlice)
 		}
+	}
 
-		if err := h.bucketsAcc.Grow(ctx, usage); err != nil {
-			return false, err
-		}
+	if err := h.bucketsAcc.Grow(ctx, usage); err != nil {
+		return err
+	}
 
-		b.rows = append(b.rows, rowIdx)
-		h.buckets[string(encoded)] = b
+	b.rows = append(b.rows, rowIdx)
+	h.buckets[string(encoded)] = b
+	return nil
+}
+
+// initSeen allocates the seen slices of the buckets, which are needed to
+// produce the unmatched right rows of RIGHT OUTER and FULL OUTER joins. Their
+// memory has already been accounted for by addRowToBuckets.
+func (h *hashJoiner) initSeen() {
+	if h.joinType != rightOuter && h.joinType != fullOuter {
+		return
+	}
+	for k, bucket := range h.buckets {
+		bucket.seen = make([]bool, len(bucket.rows))
+		h.buckets[k] = bucket
 	}
 }
 
+// clearBuckets empties the in-memory hash table.
+func (h *hashJoiner) clearBuckets(ctx context.Context) {
+	h.rows.Clear(ctx)
+	h.buckets = make(map[string]bucket)
+	h.bucketsAcc.Clear(ctx)
+}
+
 // probePhase uses our constructed hash map of rows seen from the right stream,
 // we probe the map for each row retrieved from the left stream outputting the
 // merging of the two rows if matched. Behaviour for outer joins is as expected,
@@ -245,22 +337,49 @@ func (h *hashJoiner) buildPhase(ctx context.Context) (bool, error) {
 func (h *hashJoiner) probePhase(ctx context.Context) (bool, error) {
 	var scratch []byte
 
-	// If moreRowsNeeded is returned false, then both the input and the output
-	// have been drained and closed.
-	// If an error is returned, the input/output have not been drained and closed.
-	renderAndEmit := func(lrow sqlbase.EncDatumRow, rrow sqlbase.EncDatumRow,
-	) (moreRowsNeeded bool, failedOnCond bool, err error) {
-		row, failedOnCond, err := h.render(lrow, rrow)
+	for {
+		lrow, meta := h.leftSource.Next()
+		if !meta.Empty() {
+			if meta.Err != nil {
+				return true, meta.Err
+			}
+			if !emitHelper(
+				ctx, &h.out, nil /* row */, meta, h.leftSource, h.rightSource) {
+				return false, nil
+			}
+			continue
+		}
+
+		if lrow == nil {
+			break
+		}
+
+		encoded, hasNull, err := encodeColumnsOfRow(&h.datumAlloc, scratch, lrow, h.leftEqCols, false /* encodeNull */)
 		if err != nil {
-			return false, false, err
+			return true, err
 		}
-		if row != nil {
-			moreRowsNeeded := emitHelper(ctx, &h.out, row, ProducerMetadata{}, h.leftSource)
-			return moreRowsNeeded, failedOnCond, nil
+		scratch = encoded[:0]
+
+		if moreRowsNeeded, err := h.probeRow(ctx, lrow, encoded, hasNull); !moreRowsNeeded || err != nil {
+			return moreRowsNeeded, err
 		}
-		return true, failedOnCond, nil
 	}
 
+	if moreRowsNeeded, err := h.emitUnmatchedRight(ctx); !moreRowsNeeded || err != nil {
+		return moreRowsNeeded, err
+	}
+	h.out.close()
+	return false, nil
+}
+
+// graceJoinPhase is used instead of probePhase when the right stream has been
+// spilled to disk. The left stream is partitioned the same way as the right
+// stream, after which each pair of partitions is joined in memory.
+//
+// The return values are symmetric with probePhase().
+func (h *hashJoiner) graceJoinPhase(ctx context.Context) (bool, error) {
+	h.leftPartitions = h.makePartitions(h.leftSource.Types())
+	var scratch []byte
 	for {
 		lrow, meta := h.leftSource.Next()
 		if !meta.Empty() {
@@ -285,53 +404,461 @@ func (h *hashJoiner) probePhase(ctx context.Context) (bool, error) {
 		scratch = encoded[:0]
 
 		if hasNull {
-			// A row that has a NULL in an equality column will not match anything.
-			// Output it or throw it away.
-			if h.joinType == leftOuter || h.joinType == fullOuter {
-				moreRowsNeeded, _, err := renderAndEmit(lrow, nil)
-				if !moreRowsNeeded || err != nil {
-					return moreRowsNeeded, err
-				}
+			// Rows with NULLs don't match anything, so there is no need to store
+			// them.
+			if moreRowsNeeded, err := h.probeRow(ctx, lrow, nil, hasNull); !moreRowsNeeded || err != nil {
+				return moreRowsNeeded, err
 			}
 			continue
 		}
+		if err := h.leftPartitions[h.partitionIdx(encoded, 0 /* level */)].AddRow(ctx, lrow); err != nil {
+			return true, err
+		}
+	}
+
+	for i := range h.rightPartitions {
+		moreRowsNeeded, err := h.joinPartitions(
+			ctx, &h.leftPartitions[i], &h.rightPartitions[i], 0, /* level */
+		)
+		if !moreRowsNeeded || err != nil {
+			return moreRowsNeeded, err
+		}
+	}
+	h.out.close()
+	return false, nil
+}
 
-		if b, ok := h.buckets[string(encoded)]; ok {
-			for i, rrowIdx := range b.rows {
-				rrow := h.rows.EncRow(rrowIdx)
-				moreRowsNeeded, failedOnCond, err := renderAndEmit(lrow, rrow)
+// joinPartitions joins a pair of partitions created at the given level of
+// partitioning by building a hash table from the right partition and probing
+// it with the rows of the left partition. If the right partition doesn't fit
+// in the memory budget, the pair is partitioned further or joined in chunks.
+func (h *hashJoiner) joinPartitions(
+	ctx context.Context, left, right *diskRowContainer, level int,
+) (bool, error) {
+	defer h.clearBuckets(ctx)
 
-				if !moreRowsNeeded || err != nil {
-					return moreRowsNeeded, err
-				}
-				if !failedOnCond && (h.joinType == rightOuter || h.joinType == fullOuter) {
-					b.seen[i] = true
-				}
+	it, err := right.NewIterator(ctx)
+	if err != nil {
+		return true, err
+	}
+	for ; ; it.Next() {
+		if ok, err := it.Valid(); err != nil {
+			it.Close()
+			return true, err
+		} else if !ok {
+			break
+		}
+		rrow, err := it.Row()
+		if err == nil {
+			h.scratch, _, err = encodeColumnsOfRow(
+				&h.datumAlloc, h.scratch[:0], rrow, h.rightEqCols, false, /* encodeNull */
+			)
+		}
+		if err == nil {
+			err = h.addRowToBuckets(ctx, rrow, h.scratch)
+		}
+		if err != nil {
+			it.Close()
+			if !canSpillToDisk(h.flowCtx, err) {
+				return true, err
 			}
-		} else {
-			if h.joinType == leftOuter || h.joinType == fullOuter {
-				if moreRowsNeeded, _, err := renderAndEmit(lrow, nil); !moreRowsNeeded || err != nil {
-					return moreRowsNeeded, err
+			h.clearBuckets(ctx)
+			if level < hashJoinerMaxPartitionLevel {
+				log.VEventf(ctx, 2, "hash joiner repartitioning %d right rows: %v", right.Len(), err)
+				return h.repartitionAndJoin(ctx, left, right, level+1)
+			}
+			log.VEventf(ctx, 2, "hash joiner joining %d right rows in chunks: %v", right.Len(), err)
+			return h.joinPartitionsInChunks(ctx, left, right)
+		}
+	}
+	it.Close()
+	h.initSeen()
+
+	it, err = left.NewIterator(ctx)
+	if err != nil {
+		return true, err
+	}
+	defer it.Close()
+	for ; ; it.Next() {
+		if ok, err := it.Valid(); err != nil {
+			return true, err
+		} else if !ok {
+			break
+		}
+		lrow, err := it.Row()
+		if err != nil {
+			return true, err
+		}
+		h.scratch, _, err = encodeColumnsOfRow(
+			&h.datumAlloc, h.scratch[:0], lrow, h.leftEqCols, false, /* encodeNull */
+		)
+		if err != nil {
+			return true, err
+		}
+		if moreRowsNeeded, err := h.probeRow(ctx, lrow, h.scratch, false /* hasNull */); !moreRowsNeeded || err != nil {
+			return moreRowsNeeded, err
+		}
+	}
+	return h.emitUnmatchedRight(ctx)
+}
+
+// repartitionAndJoin joins a pair of partitions whose right partition doesn't
+// fit in the memory budget by splitting both into partitions at the given
+// level, which hashes rows differently than the level the pair was created
+// at, and joining each resulting pair of partitions.
+func (h *hashJoiner) repartitionAndJoin(
+	ctx context.Context, left, right *diskRowContainer, level int,
+) (bool, error) {
+	leftPartitions := h.makePartitions(h.leftSource.Types())
+	rightPartitions := h.makePartitions(h.rightSource.Types())
+	defer func() {
+		for i := range leftPartitions {
+			leftPartitions[i].Close(ctx)
+			rightPartitions[i].Close(ctx)
+		}
+	}()
+
+	if err := h.partitionRows(ctx, right, rightPartitions, h.rightEqCols, level); err != nil {
+		return true, err
+	}
+	for i := range rightPartitions {
+		if rightPartitions[i].Len() == right.Len() {
+			// All the rows hash to the same partition, most likely because they
+			// have the same equality columns, so partitioning them further won't
+			// help.
+			log.VEventf(ctx, 2, "hash joiner joining %d right rows in chunks", right.Len())
+			return h.joinPartitionsInChunks(ctx, left, right)
+		}
+	}
+	if err := h.partitionRows(ctx, left, leftPartitions, h.leftEqCols, level); err != nil {
+		return true, err
+	}
+	for i := range rightPartitions {
+		moreRowsNeeded, err := h.joinPartitions(ctx, &leftPartitions[i], &rightPartitions[i], level)
+		if !moreRowsNeeded || err != nil {
+			return moreRowsNeeded, err
+		}
+	}
+	return true, nil
+}
+
+// partitionRows adds the rows of src to the partitions of the given level that
+// their equality columns hash to.
+func (h *hashJoiner) partitionRows(
+	ctx context.Context, src *diskRowContainer, partitions []diskRowContainer, eqCols columns, level int,
+) error {
+	it, err := src.NewIterator(ctx)
+	if err != nil {
+		return err
+	}
+	defer it.Close()
+	for ; ; it.Next() {
+		if ok, err := it.Valid(); err != nil {
+			return err
+		} else if !ok {
+			return nil
+		}
+		row, err := it.Row()
+		if err != nil {
+			return err
+		}
+		h.scratch, _, err = encodeColumnsOfRow(
+			&h.datumAlloc, h.scratch[:0], row, eqCols, false, /* encodeNull */
+		)
+		if err != nil {
+			return err
+		}
+		if err := partitions[h.partitionIdx(h.scratch, level)].AddRow(ctx, row); err != nil {
+			return err
+		}
+	}
+}
+
+// joinPartitionsInChunks joins a pair of partitions whose right partition
+// doesn't fit in the memory budget and can't be partitioned any further. The
+// right partition is read into the hash table in chunks that fit in the memory
+// budget, and the whole left partition is probed against each chunk in turn,
+// as in a block nested loop join.
+func (h *hashJoiner) joinPartitionsInChunks(
+	ctx context.Context, left, right *diskRowContainer,
+) (bool, error) {
+	defer h.clearBuckets(ctx)
+
+	// found records which left rows had a bucket in any of the chunks. The
+	// left rows without one don't match anything and are emitted once all the
+	// chunks have been probed.
+	var found []bool
+	foundAcc := h.flowCtx.evalCtx.Mon.MakeBoundAccount()
+	defer foundAcc.Close(ctx)
+	if h.joinType == leftOuter || h.joinType == fullOuter {
+		if err := foundAcc.Grow(
+			ctx, int64(sizeOfBoolSlice+uintptr(left.Len())*sizeOfBool),
+		); err != nil {
+			return true, err
+		}
+		found = make([]bool, left.Len())
+	}
+
+	it, err := right.NewIterator(ctx)
+	if err != nil {
+		return true, err
+	}
+	defer it.Close()
+	for done := false; !done; {
+		h.clearBuckets(ctx)
+		for {
+			ok, err := it.Valid()
+			if err != nil {
+				return true, err
+			}
+			if !ok {
+				done = true
+				break
+			}
+			rrow, err := it.Row()
+			if err != nil {
+				return true, err
+			}
+			h.scratch, _, err = encodeColumnsOfRow(
+				&h.datumAlloc, h.scratch[:0], rrow, h.rightEqCols, false, /* encodeNull */
+			)
+			if err != nil {
+				return true, err
+			}
+			if err := h.addRowToBuckets(ctx, rrow, h.scratch); err != nil {
+				if len(h.buckets) == 0 || !canSpillToDisk(h.flowCtx, err) {
+					return true, err
 				}
+				// The chunk is full; the row will be added to the next one.
+				break
 			}
+			it.Next()
+		}
+		if len(h.buckets) == 0 {
+			break
+		}
+		h.initSeen()
+
+		if moreRowsNeeded, err := h.probeChunk(ctx, left, found); !moreRowsNeeded || err != nil {
+			return moreRowsNeeded, err
+		}
+		if moreRowsNeeded, err := h.emitUnmatchedRight(ctx); !moreRowsNeeded || err != nil {
+			return moreRowsNeeded, err
 		}
 	}
 
-	if h.joinType == rightOuter || h.joinType == fullOuter {
-		// Produce results for unmatched right rows (for RIGHT OUTER or FULL OUTER).
-		for _, b := range h.buckets {
-			for i, seen := range b.seen {
-				if !seen {
-					rrow := h.rows.EncRow(b.rows[i])
-					if moreRowsNeeded, _, err := renderAndEmit(nil, rrow); !moreRowsNeeded || err != nil {
-						return moreRowsNeeded, err
-					}
+	if found == nil {
+		return true, nil
+	}
+	lit, err := left.NewIterator(ctx)
+	if err != nil {
+		return true, err
+	}
+	defer lit.Close()
+	for i := 0; ; i++ {
+		if ok, err := lit.Valid(); err != nil {
+			return true, err
+		} else if !ok {
+			return true, nil
+		}
+		if !found[i] {
+			lrow, err := lit.Row()
+			if err != nil {
+				return true, err
+			}
+			if moreRowsNeeded, _, err := h.renderAndEmit(ctx, lrow, nil); !moreRowsNeeded || err != nil {
+				return moreRowsNeeded, err
+			}
+		}
+		lit.Next()
+	}
+}
+
+// probeChunk probes the hash table, which holds a chunk of a right partition,
+// with every row of a left partition and emits the matches. If found is not
+// nil, the left rows which have a bucket in the chunk are marked in it.
+func (h *hashJoiner) probeChunk(
+	ctx context.Context, left *diskRowContainer, found []bool,
+) (bool, error) {
+	it, err := left.NewIterator(ctx)
+	if err != nil {
+		return true, err
+	}
+	defer it.Close()
+	for i := 0; ; i++ {
+		if ok, err := it.Valid(); err != nil {
+			return true, err
+		} else if !ok {
+			return true, nil
+		}
+		lrow, err := it.Row()
+		if err != nil {
+			return true, err
+		}
+		h.scratch, _, err = encodeColumnsOfRow(
+			&h.datumAlloc, h.scratch[:0], lrow, h.leftEqCols, false, /* encodeNull */
+		)
+		if err != nil {
+			return true, err
+		}
+		if b, ok := h.buckets[string(h.scratch)]; ok {
+			if found != nil {
+				found[i] = true
+			}
+			if moreRowsNeeded, err := h.emitBucket(ctx, lrow, b); !moreRowsNeeded || err != nil {
+				return moreRowsNeeded, err
+			}
+		}
+		it.Next()
+	}
+}
+
+// probeRow looks up the matches of a left row in the hash table and emits the
+// resulting rows. encoded is the encoding of the row's equality columns; if
+// hasNull is set, the row cannot match anything.
+//
+// If false is returned, both the inputs and the output have been drained
+// and/or closed.
+func (h *hashJoiner) probeRow(
+	ctx context.Context, lrow sqlbase.EncDatumRow, encoded []byte, hasNull bool,
+) (bool, error) {
+	if hasNull {
+		// A row that has a NULL in an equality column will not match anything.
+		// Output it or throw it away.
+		if h.joinType == leftOuter || h.joinType == fullOuter {
+			moreRowsNeeded, _, err := h.renderAndEmit(ctx, lrow, nil)
+			return moreRowsNeeded, err
+		}
+		return true, nil
+	}
+
+	if b, ok := h.buckets[string(encoded)]; ok {
+		return h.emitBucket(ctx, lrow, b)
+	}
+	if h.joinType == leftOuter || h.joinType == fullOuter {
+		if moreRowsNeeded, _, err := h.renderAndEmit(ctx, lrow, nil); !moreRowsNeeded || err != nil {
+			return moreRowsNeeded, err
+		}
+	}
+	return true, nil
+}
+
+// emitBucket emits the results of joining a left row with the right rows of
+// the bucket with the same equality columns.
+//
+// If false is returned, both the inputs and the output have been drained
+// and/or closed.
+func (h *hashJoiner) emitBucket(
+	ctx context.Context, lrow sqlbase.EncDatumRow, b bucket,
+) (bool, error) {
+	for i, rrowIdx := range b.rows {
+		rrow := h.rows.EncRow(rrowIdx)
+		moreRowsNeeded, failedOnCond, err := h.renderAndEmit(ctx, lrow, rrow)
+
+		if !moreRowsNeeded || err != nil {
+			return moreRowsNeeded, err
+		}
+		if !failedOnCond && (h.joinType == rightOuter || h.joinType == fullOuter) {
+			b.seen[i] = true
+		}
+	}
+	return true, nil
+}
+
+// emitUnmatchedRight produces results for the right rows in the hash table that
+// didn't match any left row (for RIGHT OUTER or FULL OUTER).
+func (h *hashJoiner) emitUnmatchedRight(ctx context.Context) (bool, error) {
+	if h.joinType != rightOuter && h.joinType != fullOuter {
+		return true, nil
+	}
+	for _, b := range h.buckets {
+		for i, seen := range b.seen {
+			if !seen {
+				rrow := h.rows.EncRow(b.rows[i])
+				if moreRowsNeeded, _, err := h.renderAndEmit(ctx, nil, rrow); !moreRowsNeeded || err != nil {
+					return moreRowsNeeded, err
 				}
 			}
 		}
 	}
-	h.out.close()
-	return false, nil
+	return true, nil
+}
+
+// renderAndEmit renders the join of the given rows and emits the result, if
+// any.
+//
+// If moreRowsNeeded is returned false, then both the input and the output
+// have been drained and closed.
+// If an error is returned, the input/output have not been drained and closed.
+func (h *hashJoiner) renderAndEmit(
+	ctx context.Context, lrow sqlbase.EncDatumRow, rrow sqlbase.EncDatumRow,
+) (moreRowsNeeded bool, failedOnCond bool, err error) {
+	row, failedOnCond, err := h.render(lrow, rrow)
+	if err != nil {
+		return false, false, err
+	}
+	if row != nil {
+		moreRowsNeeded := emitHelper(ctx, &h.out, row, ProducerMetadata{}, h.leftSource)
+		return moreRowsNeeded, failedOnCond, nil
+	}
+	return true, failedOnCond, nil
+}
+
+// makePartitions creates the disk containers used to partition an input when
+// spilling to disk.
+func (h *hashJoiner) makePartitions(types []sqlbase.ColumnType) []diskRowContainer {
+	partitions := make([]diskRowContainer, hashJoinerNumPartitions)
+	for i := range partitions {
+		partitions[i] = makeDiskRowContainer(h.flowCtx, types, nil /* ordering */)
+	}
+	return partitions
+}
+
+// closePartitions releases the disk containers created by makePartitions.
+func (h *hashJoiner) closePartitions(ctx context.Context) {
+	for i := range h.leftPartitions {
+		h.leftPartitions[i].Close(ctx)
+	}
+	for i := range h.rightPartitions {
+		h.rightPartitions[i].Close(ctx)
+	}
+}
+
+// partitionIdx returns the partition that a row with the given encoding of
+// its equality columns belongs to at the given level of partitioning. Each
+// level seeds the hash differently, so that the rows of a partition are spread
+// over several partitions when it is partitioned again.
+func (h *hashJoiner) partitionIdx(encoded []byte, level int) int {
+	if h.hasher == nil {
+		h.hasher = fnv.New32a()
+	}
+	h.hasher.Reset()
+	_, _ = h.hasher.Write([]byte{byte(level)})
+	_, _ = h.hasher.Write(encoded)
+	// The low bits of an FNV hash only depend on the low bits of the input
+	// bytes, so the partition is derived from the high bits.
+	return int(uint64(h.hasher.Sum32()) * hashJoinerNumPartitions >> 32)
+}
+
+// spillRightRows moves the right rows accumulated in memory to
+// h.rightPartitions and releases the in-memory hash table.
+func (h *hashJoiner) spillRightRows(ctx context.Context) error {
+	h.rightPartitions = h.makePartitions(h.rightSource.Types())
+	for i := 0; i < h.rows.Len(); i++ {
+		row := h.rows.EncRow(i)
+		var err error
+		h.scratch, _, err = encodeColumnsOfRow(
+			&h.datumAlloc, h.scratch[:0], row, h.rightEqCols, false, /* encodeNull */
+		)
+		if err != nil {
+			return err
+		}
+		if err := h.rightPartitions[h.partitionIdx(h.scratch, 0 /* level */)].AddRow(ctx, row); err != nil {
+			return err
+		}
+	}
+	h.clearBuckets(ctx)
+	return nil
 }
 
 // encodeColumnsOfRow returns the encoding for the grouping columns. This is
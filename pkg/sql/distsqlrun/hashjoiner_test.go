@@ -17,6 +17,7 @@
 package distsqlrun
 
 import (
+	"fmt"
 	"math"
 	"sort"
 	"strings"
@@ -488,30 +489,139 @@ func TestHashJoiner(t *testing.T) {
 	monitor := mon.MakeUnlimitedMonitor(context.Background(), "test", nil, nil, math.MaxInt64)
 	defer monitor.Stop(context.Background())
 	for _, c := range testCases {
-		t.Run("", func(t *testing.T) {
-			hs := c.spec
-			leftInput := NewRowBuffer(nil /* types */, c.inputs[0], RowBufferArgs{})
-			rightInput := NewRowBuffer(nil /* types */, c.inputs[1], RowBufferArgs{})
-			out := &RowBuffer{}
-			flowCtx := FlowCtx{evalCtx: parser.EvalContext{Mon: &monitor}}
-
-			post := PostProcessSpec{Projection: true, OutputColumns: c.outCols}
-			h, err := newHashJoiner(&flowCtx, &hs, leftInput, rightInput, &post, out)
-			if err != nil {
-				t.Fatal(err)
-			}
-
-			h.Run(context.Background(), nil)
-
-			if !out.ProducerClosed {
-				t.Fatalf("output RowReceiver not closed")
-			}
-
-			if err := checkExpectedRows(c.expected, out); err != nil {
-				t.Fatal(err)
-			}
-		})
+		for _, spill := range []bool{false, true} {
+			t.Run(fmt.Sprintf("spill=%t", spill), func(t *testing.T) {
+				hs := c.spec
+				leftInput := NewRowBuffer(intColumnTypes(c.inputs[0]), c.inputs[0], RowBufferArgs{})
+				rightInput := NewRowBuffer(intColumnTypes(c.inputs[1]), c.inputs[1], RowBufferArgs{})
+				out := &RowBuffer{}
+				flowCtx := FlowCtx{evalCtx: parser.EvalContext{Mon: &monitor}}
+				if spill {
+					defer setTempStorage(&flowCtx, true /* forceDiskSpill */)()
+				}
+
+				post := PostProcessSpec{Projection: true, OutputColumns: c.outCols}
+				h, err := newHashJoiner(&flowCtx, &hs, leftInput, rightInput, &post, out)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				h.Run(context.Background(), nil)
+
+				if !out.ProducerClosed {
+					t.Fatalf("output RowReceiver not closed")
+				}
+
+				if err := checkExpectedRows(c.expected, out); err != nil {
+					t.Fatal(err)
+				}
+			})
+		}
+	}
+}
+
+// TestHashJoinerSpillBeyondBudget verifies that the hashJoiner can join a right
+// stream much larger than the memory budget, even when its partitions don't
+// fit in the budget either, because all the rows have the same key.
+func TestHashJoinerSpillBeyondBudget(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	columnTypeInt := sqlbase.ColumnType{Kind: sqlbase.ColumnType_INT}
+	intDatum := func(i int) sqlbase.EncDatum {
+		return sqlbase.DatumToEncDatum(columnTypeInt, parser.NewDInt(parser.DInt(i)))
+	}
+	null := sqlbase.EncDatum{Datum: parser.DNull}
+
+	const numRightRows = 4000
+	const budget = 8 << 10
+
+	testCases := []struct {
+		name string
+		// key returns the key of the i-th right row.
+		key func(i int) int
+	}{
+		{"single-key", func(int) int { return 0 }},
+		{"distinct-keys", func(i int) int { return i }},
+	}
+	for _, c := range testCases {
+		for _, joinType := range []JoinType{JoinType_INNER, JoinType_LEFT_OUTER, JoinType_FULL_OUTER} {
+			t.Run(fmt.Sprintf("%s/%s", c.name, joinType), func(t *testing.T) {
+				// The left rows with key -1 don't match any right row, and those
+				// right rows whose key isn't 0 or 1 don't match any left row.
+				leftKeys := []int{0, 1, -1}
+				var left sqlbase.EncDatumRows
+				for i, key := range leftKeys {
+					left = append(left, sqlbase.EncDatumRow{intDatum(key), intDatum(-1 - i)})
+				}
+				var right sqlbase.EncDatumRows
+				var expected sqlbase.EncDatumRows
+				for i := 0; i < numRightRows; i++ {
+					key := c.key(i)
+					right = append(right, sqlbase.EncDatumRow{intDatum(key), intDatum(i)})
+					matched := false
+					for j, leftKey := range leftKeys {
+						if leftKey == key {
+							expected = append(expected, sqlbase.EncDatumRow{left[j][1], right[i][1]})
+							matched = true
+						}
+					}
+					if !matched && joinType == JoinType_FULL_OUTER {
+						expected = append(expected, sqlbase.EncDatumRow{null, right[i][1]})
+					}
+				}
+				if joinType != JoinType_INNER {
+					expected = append(expected, sqlbase.EncDatumRow{intDatum(-3), null})
+					if c.key(1) != 1 {
+						expected = append(expected, sqlbase.EncDatumRow{intDatum(-2), null})
+					}
+				}
+
+				monitor := mon.MakeMonitor("test", nil, nil, 1 /* increment */, math.MaxInt64)
+				monitor.Start(ctx, nil, mon.MakeStandaloneBudget(budget))
+				defer monitor.Stop(ctx)
+				flowCtx := FlowCtx{evalCtx: parser.EvalContext{Mon: &monitor}}
+				defer setTempStorage(&flowCtx, false /* forceDiskSpill */)()
+
+				spec := HashJoinerSpec{
+					LeftEqColumns:  []uint32{0},
+					RightEqColumns: []uint32{0},
+					Type:           joinType,
+				}
+				post := PostProcessSpec{Projection: true, OutputColumns: []uint32{1, 3}}
+				leftInput := NewRowBuffer(intColumnTypes(left), left, RowBufferArgs{})
+				rightInput := NewRowBuffer(intColumnTypes(right), right, RowBufferArgs{})
+				out := &RowBuffer{}
+				h, err := newHashJoiner(&flowCtx, &spec, leftInput, rightInput, &post, out)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				h.Run(ctx, nil)
+
+				if !out.ProducerClosed {
+					t.Fatalf("output RowReceiver not closed")
+				}
+				if err := checkExpectedRows(expected, out); err != nil {
+					t.Fatal(err)
+				}
+			})
+		}
+	}
+}
+
+// intColumnTypes returns the column types of rows made up of INT columns.
+// Unlike the types inferred by NewRowBuffer, these are not thrown off by NULLs
+// in the first row, which matters when the rows are decoded from disk.
+func intColumnTypes(rows sqlbase.EncDatumRows) []sqlbase.ColumnType {
+	if len(rows) == 0 {
+		return nil
+	}
+	types := make([]sqlbase.ColumnType, len(rows[0]))
+	for i := range types {
+		types[i] = sqlbase.ColumnType{Kind: sqlbase.ColumnType_INT}
 	}
+	return types
 }
 
 func checkExpectedRows(expectedRows sqlbase.EncDatumRows, results *RowBuffer) error {
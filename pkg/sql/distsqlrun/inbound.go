@@ -21,6 +21,7 @@ import (
 
 	"golang.org/x/net/context"
 
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/pkg/errors"
 )
@@ -29,11 +30,18 @@ import (
 // them to a RowReceiver. Optionally processes an initial StreamMessage that was
 // already received (because the first message contains the flow and stream IDs,
 // it needs to be received before we can get here).
+//
+// If the flow's context is canceled, an error is returned, which is also
+// propagated to the producer so that it can stop.
 func ProcessInboundStream(
-	ctx context.Context, stream DistSQL_FlowStreamServer, firstMsg *ProducerMessage, dst RowReceiver,
+	ctx context.Context,
+	stream DistSQL_FlowStreamServer,
+	firstMsg *ProducerMessage,
+	dst RowReceiver,
+	f *Flow,
 ) error {
 
-	err := processInboundStreamHelper(ctx, stream, firstMsg, dst)
+	err := processInboundStreamHelper(ctx, stream, firstMsg, dst, f)
 
 	// err, if set, will also be propagated to the producer
 	// as the last record that the producer gets.
@@ -50,9 +58,40 @@ func ProcessInboundStream(
 	return nil
 }
 
+// recvResult is the result of a stream.Recv() call, as passed from the
+// goroutine receiving from a stream in processInboundStreamHelper.
+type recvResult struct {
+	msg *ProducerMessage
+	err error
+}
+
 func processInboundStreamHelper(
-	ctx context.Context, stream DistSQL_FlowStreamServer, firstMsg *ProducerMessage, dst RowReceiver,
+	ctx context.Context,
+	stream DistSQL_FlowStreamServer,
+	firstMsg *ProducerMessage,
+	dst RowReceiver,
+	f *Flow,
 ) error {
+	// Receive from the stream in a separate goroutine, so that we can stop
+	// waiting for messages when the flow is canceled. Once we return, the RPC
+	// completes and the pending Recv() call fails, which stops the goroutine.
+	recvCh := make(chan recvResult)
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	go func() {
+		for {
+			msg, err := stream.Recv()
+			select {
+			case recvCh <- recvResult{msg: msg, err: err}:
+			case <-stopCh:
+				return
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+
 	var finalErr error
 	draining := false
 	var sd StreamDecoder
@@ -62,17 +101,22 @@ func processInboundStreamHelper(
 			msg = firstMsg
 			firstMsg = nil
 		} else {
-			var err error
-			msg, err = stream.Recv()
-			if err != nil {
-				if err != io.EOF {
+			var res recvResult
+			select {
+			case res = <-recvCh:
+			case <-f.ctxDone:
+				return sqlbase.NewQueryCanceledError()
+			}
+			if res.err != nil {
+				if res.err != io.EOF {
 					// Communication error.
 					return errors.Wrap(
-						err, log.MakeMessage(ctx, "communication error", nil /* args */))
+						res.err, log.MakeMessage(ctx, "communication error", nil /* args */))
 				}
 				// End of the stream.
 				return finalErr
 			}
+			msg = res.msg
 		}
 
 		err := sd.AddMessage(msg)
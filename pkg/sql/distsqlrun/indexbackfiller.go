@@ -112,7 +112,7 @@ func (ib *indexBackfiller) runChunk(
 	for i, m := range mutations {
 		added[i] = *m.GetIndex()
 	}
-	secondaryIndexEntries := make([]sqlbase.IndexEntry, len(mutations))
+	secondaryIndexEntries := make([]sqlbase.IndexEntry, 0, len(mutations))
 	err := ib.flowCtx.clientDB.Txn(ctx, func(ctx context.Context, txn *client.Txn) error {
 		if ib.flowCtx.testingKnobs.RunBeforeBackfillChunk != nil {
 			if err := ib.flowCtx.testingKnobs.RunBeforeBackfillChunk(sp); err != nil {
@@ -153,9 +153,10 @@ func (ib *indexBackfiller) runChunk(
 			if err := sqlbase.EncDatumRowToDatums(ib.rowVals, encRow, &ib.da); err != nil {
 				return err
 			}
-			if err := sqlbase.EncodeSecondaryIndexes(
+			secondaryIndexEntries, err = sqlbase.EncodeSecondaryIndexes(
 				&ib.spec.Table, added, ib.colIdxMap,
-				ib.rowVals, secondaryIndexEntries); err != nil {
+				ib.rowVals, secondaryIndexEntries[:0])
+			if err != nil {
 				return err
 			}
 			for _, secondaryIndexEntry := range secondaryIndexEntries {
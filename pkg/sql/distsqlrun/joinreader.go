@@ -23,6 +23,7 @@ import (
 	"golang.org/x/net/context"
 
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/tracing"
@@ -33,6 +34,13 @@ import (
 // nodes that "own" the respective ranges, and send out flows on those nodes.
 const joinReaderBatchSize = 100
 
+// joinReader performs KV lookups for the rows of its input stream. It operates
+// in one of two modes (see JoinReaderSpec):
+//  - index join: each input row contains a primary key and the joinReader
+//    retrieves the corresponding table row.
+//  - lookup join: the lookup columns of each input row provide the values for
+//    a prefix of the columns of an index; the joinReader retrieves all the
+//    matching table rows and joins them with the input row.
 type joinReader struct {
 	flowCtx *FlowCtx
 
@@ -43,6 +51,29 @@ type joinReader struct {
 
 	input RowSource
 	out   procOutputHelper
+
+	// The following fields are only used for lookup joins.
+
+	// lookupCols are the input columns that provide the values for a prefix of
+	// the index columns.
+	lookupCols columns
+	// indexCols contains, for each lookup column, the index (in the table
+	// columns) of the corresponding index column.
+	indexCols []int
+	joinType  joinType
+	onCond    exprHelper
+
+	// inputRows holds the input rows of the current batch.
+	inputRows []sqlbase.EncDatumRow
+	// matched indicates, for each input row of the current batch, whether the
+	// row was joined with at least one table row.
+	matched []bool
+	// keyVals is scratch space used to generate lookup keys.
+	keyVals sqlbase.EncDatumRow
+	// emptyRight contains NULLs for all the table columns; it is used to output
+	// unmatched input rows in LEFT OUTER joins.
+	emptyRight  sqlbase.EncDatumRow
+	combinedRow sqlbase.EncDatumRow
 }
 
 var _ processor = &joinReader{}
@@ -54,29 +85,102 @@ func newJoinReader(
 	post *PostProcessSpec,
 	output RowReceiver,
 ) (*joinReader, error) {
-	if spec.IndexIdx != 0 {
-		// TODO(radu): for now we only support joining with the primary index
-		return nil, errors.Errorf("join with index not implemented")
+	isLookupJoin := len(spec.LookupColumns) > 0
+	if spec.IndexIdx != 0 && !isLookupJoin {
+		// TODO(radu): for now we only support index joins with the primary index.
+		return nil, errors.Errorf("index join with secondary index not implemented")
 	}
 
 	jr := &joinReader{
-		flowCtx: flowCtx,
-		desc:    spec.Table,
-		input:   input,
+		flowCtx:    flowCtx,
+		desc:       spec.Table,
+		input:      input,
+		lookupCols: spec.LookupColumns,
+	}
+
+	tableTypes := make([]sqlbase.ColumnType, len(spec.Table.Columns))
+	for i := range tableTypes {
+		tableTypes[i] = spec.Table.Columns[i].Type
 	}
+	types := tableTypes
+	numInputCols := 0
+
+	if isLookupJoin {
+		switch spec.Type {
+		case JoinType_INNER, JoinType_LEFT_OUTER:
+		default:
+			return nil, errors.Errorf("lookup join of type %s not supported", spec.Type)
+		}
+		jr.joinType = joinType(spec.Type)
+
+		inputTypes := input.Types()
+		numInputCols = len(inputTypes)
+		for _, c := range jr.lookupCols {
+			if int(c) >= numInputCols {
+				return nil, errors.Errorf("invalid lookup column %d", c)
+			}
+		}
+
+		types = make([]sqlbase.ColumnType, 0, numInputCols+len(tableTypes))
+		types = append(types, inputTypes...)
+		types = append(types, tableTypes...)
+
+		jr.emptyRight = make(sqlbase.EncDatumRow, len(tableTypes))
+		for i := range jr.emptyRight {
+			jr.emptyRight[i].Datum = parser.DNull
+		}
+		jr.combinedRow = make(sqlbase.EncDatumRow, len(types))
+		jr.keyVals = make(sqlbase.EncDatumRow, len(jr.lookupCols))
 
-	types := make([]sqlbase.ColumnType, len(spec.Table.Columns))
-	for i := range types {
-		types[i] = spec.Table.Columns[i].Type
+		if err := jr.onCond.init(spec.OnExpr, types, &flowCtx.evalCtx); err != nil {
+			return nil, err
+		}
 	}
 
 	if err := jr.out.init(post, types, &flowCtx.evalCtx, output); err != nil {
 		return nil, err
 	}
 
+	// The fetcher only produces the table columns.
+	neededCols := jr.out.neededColumns()[numInputCols:]
+	if isLookupJoin {
+		if jr.onCond.expr != nil {
+			for i := range neededCols {
+				if jr.onCond.vars.IndexedVarUsed(numInputCols + i) {
+					neededCols[i] = true
+				}
+			}
+		}
+		// We need the values of the index columns that correspond to the lookup
+		// columns to match the table rows with the input rows.
+		index := &jr.desc.PrimaryIndex
+		if spec.IndexIdx > 0 {
+			if int(spec.IndexIdx) > len(jr.desc.Indexes) {
+				return nil, errors.Errorf("invalid indexIdx %d", spec.IndexIdx)
+			}
+			index = &jr.desc.Indexes[spec.IndexIdx-1]
+		}
+		if len(jr.lookupCols) > len(index.ColumnIDs) {
+			return nil, errors.Errorf(
+				"%d lookup columns, but index %s only has %d columns",
+				len(jr.lookupCols), index.Name, len(index.ColumnIDs),
+			)
+		}
+		colIdxMap := make(map[sqlbase.ColumnID]int, len(jr.desc.Columns))
+		for i, c := range jr.desc.Columns {
+			colIdxMap[c.ID] = i
+		}
+		jr.indexCols = make([]int, len(jr.lookupCols))
+		for i := range jr.indexCols {
+			colIdx := colIdxMap[index.ColumnIDs[i]]
+			jr.indexCols[i] = colIdx
+			neededCols[colIdx] = true
+		}
+	}
+
 	var err error
 	jr.index, _, err = initRowFetcher(
-		&jr.fetcher, &jr.desc, int(spec.IndexIdx), false /* reverse */, jr.out.neededColumns(),
+		&jr.fetcher, &jr.desc, int(spec.IndexIdx), false /* reverse */, neededCols,
 	)
 	if err != nil {
 		return nil, err
@@ -85,6 +189,10 @@ func newJoinReader(
 	return jr, nil
 }
 
+func (jr *joinReader) isLookupJoin() bool {
+	return len(jr.lookupCols) > 0
+}
+
 func (jr *joinReader) generateKey(
 	row sqlbase.EncDatumRow, alloc *sqlbase.DatumAlloc, primaryKeyPrefix []byte,
 ) (roachpb.Key, error) {
@@ -98,6 +206,48 @@ func (jr *joinReader) generateKey(
 	return sqlbase.MakeKeyFromEncDatums(row, &jr.desc, index, primaryKeyPrefix, alloc)
 }
 
+// generateLookupKey generates the index key prefix for the values of the lookup
+// columns of an input row. Returns a nil key if any of the values is NULL, in
+// which case the row can't have any matches.
+func (jr *joinReader) generateLookupKey(
+	inputRow sqlbase.EncDatumRow, alloc *sqlbase.DatumAlloc, indexKeyPrefix []byte,
+) (roachpb.Key, error) {
+	for i, c := range jr.lookupCols {
+		if inputRow[c].IsNull() {
+			return nil, nil
+		}
+		jr.keyVals[i] = inputRow[c]
+	}
+	return sqlbase.MakeKeyFromEncDatums(jr.keyVals, &jr.desc, jr.index, indexKeyPrefix, alloc)
+}
+
+// generateTableRowKey generates the index key prefix for the values of the
+// index columns of a table row that correspond to the lookup columns. The key
+// is the same as the one generated by generateLookupKey for the matching input
+// rows.
+func (jr *joinReader) generateTableRowKey(
+	tableRow sqlbase.EncDatumRow, alloc *sqlbase.DatumAlloc, indexKeyPrefix []byte,
+) (roachpb.Key, error) {
+	for i, c := range jr.indexCols {
+		jr.keyVals[i] = tableRow[c]
+	}
+	return sqlbase.MakeKeyFromEncDatums(jr.keyVals, &jr.desc, jr.index, indexKeyPrefix, alloc)
+}
+
+// renderLookupRow joins an input row with a matching table row. Returns nil if
+// the row doesn't pass the ON condition.
+func (jr *joinReader) renderLookupRow(
+	inputRow, tableRow sqlbase.EncDatumRow,
+) (sqlbase.EncDatumRow, error) {
+	n := copy(jr.combinedRow, inputRow)
+	copy(jr.combinedRow[n:], tableRow)
+	passesOnCond, err := jr.onCond.evalFilter(jr.combinedRow)
+	if err != nil || !passesOnCond {
+		return nil, err
+	}
+	return jr.combinedRow, nil
+}
+
 // mainLoop runs the mainLoop and returns any error.
 //
 // If no error is returned, the input has been drained and the output has been
@@ -105,11 +255,16 @@ func (jr *joinReader) generateKey(
 // should drain and close the output. The caller should also pass the returned
 // error to the consumer.
 func (jr *joinReader) mainLoop(ctx context.Context) error {
-	primaryKeyPrefix := sqlbase.MakeIndexKeyPrefix(&jr.desc, jr.index.ID)
+	indexKeyPrefix := sqlbase.MakeIndexKeyPrefix(&jr.desc, jr.index.ID)
 
 	var alloc sqlbase.DatumAlloc
+	var rowAlloc sqlbase.EncDatumRowAlloc
 	spans := make(roachpb.Spans, 0, joinReaderBatchSize)
 
+	// For lookup joins, keyToInputRows maps each lookup key of the current
+	// batch to the input rows (indexes in jr.inputRows) with that key.
+	var keyToInputRows map[string][]int
+
 	txn := jr.flowCtx.setupTxn()
 
 	log.VEventf(ctx, 1, "starting")
@@ -121,7 +276,13 @@ func (jr *joinReader) mainLoop(ctx context.Context) error {
 		// TODO(radu): figure out how to send smaller batches if the source has
 		// a soft limit (perhaps send the batch out if we don't get a result
 		// within a certain amount of time).
-		for spans = spans[:0]; len(spans) < joinReaderBatchSize; {
+		spans = spans[:0]
+		if jr.isLookupJoin() {
+			jr.inputRows = jr.inputRows[:0]
+			keyToInputRows = make(map[string][]int)
+		}
+		numRows := 0
+		for numRows < joinReaderBatchSize {
 			row, meta := jr.input.Next()
 			if !meta.Empty() {
 				if meta.Err != nil {
@@ -133,36 +294,67 @@ func (jr *joinReader) mainLoop(ctx context.Context) error {
 				continue
 			}
 			if row == nil {
-				if len(spans) == 0 {
-					// No fetching needed since we have collected no spans and
+				if numRows == 0 {
+					// No fetching needed since we have collected no rows and
 					// the input has signalled that no more records are coming.
 					jr.out.close()
 					return nil
 				}
 				break
 			}
+			numRows++
+
+			if !jr.isLookupJoin() {
+				key, err := jr.generateKey(row, &alloc, indexKeyPrefix)
+				if err != nil {
+					return err
+				}
 
-			key, err := jr.generateKey(row, &alloc, primaryKeyPrefix)
+				spans = append(spans, roachpb.Span{
+					Key:    key,
+					EndKey: key.PrefixEnd(),
+				})
+				continue
+			}
+
+			jr.inputRows = append(jr.inputRows, rowAlloc.CopyRow(row))
+			key, err := jr.generateLookupKey(row, &alloc, indexKeyPrefix)
 			if err != nil {
 				return err
 			}
+			if key == nil {
+				continue
+			}
+			rows, ok := keyToInputRows[string(key)]
+			if !ok {
+				// Input rows with the same lookup values share a span.
+				spans = append(spans, roachpb.Span{
+					Key:    key,
+					EndKey: key.PrefixEnd(),
+				})
+			}
+			keyToInputRows[string(key)] = append(rows, len(jr.inputRows)-1)
+		}
 
-			spans = append(spans, roachpb.Span{
-				Key:    key,
-				EndKey: key.PrefixEnd(),
-			})
+		if jr.isLookupJoin() {
+			jr.matched = jr.matched[:0]
+			for range jr.inputRows {
+				jr.matched = append(jr.matched, false)
+			}
 		}
 
-		err := jr.fetcher.StartScan(ctx, txn, spans, false /* no batch limits */, 0)
-		if err != nil {
-			log.Errorf(ctx, "scan error: %s", err)
-			return err
+		if len(spans) > 0 {
+			err := jr.fetcher.StartScan(ctx, txn, spans, false /* no batch limits */, 0)
+			if err != nil {
+				log.Errorf(ctx, "scan error: %s", err)
+				return err
+			}
 		}
 
 		// TODO(radu): we are consuming all results from a fetch before starting
 		// the next batch. We could start the next batch early while we are
 		// outputting rows.
-		for {
+		for len(spans) > 0 {
 			fetcherRow, err := jr.fetcher.NextRow(ctx)
 			if err != nil {
 				return err
@@ -172,13 +364,48 @@ func (jr *joinReader) mainLoop(ctx context.Context) error {
 				break
 			}
 
-			// Emit the row; stop if no more rows are needed.
-			if !emitHelper(ctx, &jr.out, fetcherRow, ProducerMetadata{}, jr.input) {
-				return nil
+			if !jr.isLookupJoin() {
+				// Emit the row; stop if no more rows are needed.
+				if !emitHelper(ctx, &jr.out, fetcherRow, ProducerMetadata{}, jr.input) {
+					return nil
+				}
+				continue
+			}
+
+			key, err := jr.generateTableRowKey(fetcherRow, &alloc, indexKeyPrefix)
+			if err != nil {
+				return err
+			}
+			for _, i := range keyToInputRows[string(key)] {
+				renderedRow, err := jr.renderLookupRow(jr.inputRows[i], fetcherRow)
+				if err != nil {
+					return err
+				}
+				if renderedRow == nil {
+					continue
+				}
+				jr.matched[i] = true
+				if !emitHelper(ctx, &jr.out, renderedRow, ProducerMetadata{}, jr.input) {
+					return nil
+				}
+			}
+		}
+
+		if jr.joinType == leftOuter {
+			// Emit the input rows that had no matches, padded with NULLs.
+			for i, inputRow := range jr.inputRows {
+				if jr.matched[i] {
+					continue
+				}
+				n := copy(jr.combinedRow, inputRow)
+				copy(jr.combinedRow[n:], jr.emptyRight)
+				if !emitHelper(ctx, &jr.out, jr.combinedRow, ProducerMetadata{}, jr.input) {
+					return nil
+				}
 			}
 		}
 
-		if len(spans) != joinReaderBatchSize {
+		if numRows != joinReaderBatchSize {
 			// This was the last batch.
 			jr.out.close()
 			return nil
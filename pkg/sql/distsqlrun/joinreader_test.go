@@ -150,6 +150,163 @@ func TestJoinReader(t *testing.T) {
 	}
 }
 
+func TestJoinReaderLookup(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	s, sqlDB, kvDB := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop(context.TODO())
+
+	// Create the same table as in TestJoinReader:
+	//
+	//  |     a    |     b    |         sum         |         s           |
+	//  |-----------------------------------------------------------------|
+	//  | rowId/10 | rowId%10 | rowId/10 + rowId%10 | IntToEnglish(rowId) |
+
+	aFn := func(row int) parser.Datum {
+		return parser.NewDInt(parser.DInt(row / 10))
+	}
+	bFn := func(row int) parser.Datum {
+		return parser.NewDInt(parser.DInt(row % 10))
+	}
+	sumFn := func(row int) parser.Datum {
+		return parser.NewDInt(parser.DInt(row/10 + row%10))
+	}
+
+	sqlutils.CreateTable(t, sqlDB, "t",
+		"a INT, b INT, sum INT, s STRING, PRIMARY KEY (a,b), INDEX bs (b,s)",
+		99,
+		sqlutils.ToRowFn(aFn, bFn, sumFn, sqlutils.RowEnglishFn))
+
+	td := sqlbase.GetTableDescriptor(kvDB, "test", "t")
+
+	intType := sqlbase.ColumnType{Kind: sqlbase.ColumnType_INT}
+	intD := func(i int) parser.Datum {
+		return parser.NewDInt(parser.DInt(i))
+	}
+
+	// The internal columns of the join reader are the input columns followed by
+	// the table columns (a, b, sum, s).
+	testCases := []struct {
+		description string
+		spec        JoinReaderSpec
+		post        PostProcessSpec
+		inputTypes  []sqlbase.ColumnType
+		input       [][]parser.Datum
+		expected    string
+	}{
+		{
+			description: "primary index prefix, duplicate lookup values",
+			spec: JoinReaderSpec{
+				LookupColumns: []uint32{0},
+				OnExpr:        Expression{Expr: "@3 < 3"}, // b < 3
+				Type:          JoinType_INNER,
+			},
+			post: PostProcessSpec{
+				Projection:    true,
+				OutputColumns: []uint32{0, 2},
+			},
+			inputTypes: []sqlbase.ColumnType{intType},
+			input: [][]parser.Datum{
+				{intD(1)},
+				{intD(5)},
+				{intD(1)},
+			},
+			expected: "[[1 0] [1 0] [1 1] [1 1] [1 2] [1 2] [5 0] [5 1] [5 2]]",
+		},
+		{
+			description: "secondary index prefix",
+			spec: JoinReaderSpec{
+				IndexIdx:      1,
+				LookupColumns: []uint32{0},
+				OnExpr:        Expression{Expr: "@2 < 3"}, // a < 3
+				Type:          JoinType_INNER,
+			},
+			post: PostProcessSpec{
+				Projection:    true,
+				OutputColumns: []uint32{0, 4},
+			},
+			inputTypes: []sqlbase.ColumnType{intType},
+			input: [][]parser.Datum{
+				{intD(3)},
+				{parser.DNull},
+			},
+			expected: "[[3 'one-three'] [3 'three'] [3 'two-three']]",
+		},
+		{
+			description: "left outer join on the full primary key",
+			spec: JoinReaderSpec{
+				LookupColumns: []uint32{1, 0},
+				OnExpr:        Expression{Expr: "@5 > 3"}, // sum > 3
+				Type:          JoinType_LEFT_OUTER,
+			},
+			post: PostProcessSpec{
+				Projection:    true,
+				OutputColumns: []uint32{0, 1, 5},
+			},
+			inputTypes: []sqlbase.ColumnType{intType, intType},
+			input: [][]parser.Datum{
+				{intD(2), intD(1)},
+				{intD(0), intD(20)},
+				{intD(5), intD(0)},
+				{intD(1), parser.DNull},
+			},
+			expected: "[[5 0 'five'] [2 1 NULL] [0 20 NULL] [1 NULL NULL]]",
+		},
+	}
+	for _, c := range testCases {
+		t.Run(c.description, func(t *testing.T) {
+			flowCtx := FlowCtx{
+				evalCtx:  parser.EvalContext{},
+				txnProto: &roachpb.Transaction{},
+				// Pass a DB without a TxnCoordSender.
+				remoteTxnDB: client.NewDB(s.DistSender(), s.Clock()),
+			}
+
+			rows := make(sqlbase.EncDatumRows, len(c.input))
+			for i, row := range c.input {
+				rows[i] = make(sqlbase.EncDatumRow, len(row))
+				for j, d := range row {
+					rows[i][j] = sqlbase.DatumToEncDatum(c.inputTypes[j], d)
+				}
+			}
+			in := NewRowBuffer(c.inputTypes, rows, RowBufferArgs{})
+			out := &RowBuffer{}
+
+			spec := c.spec
+			spec.Table = *td
+			jr, err := newJoinReader(&flowCtx, &spec, in, &c.post, out)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			jr.Run(context.Background(), nil)
+
+			if !in.Done {
+				t.Fatal("joinReader didn't consume all the rows")
+			}
+			if !out.ProducerClosed {
+				t.Fatalf("output RowReceiver not closed")
+			}
+
+			var res sqlbase.EncDatumRows
+			for {
+				row, meta := out.Next()
+				if !meta.Empty() {
+					t.Fatalf("unexpected metadata: %v", meta)
+				}
+				if row == nil {
+					break
+				}
+				res = append(res, row)
+			}
+
+			if result := res.String(); result != c.expected {
+				t.Errorf("invalid results: %s, expected %s'", result, c.expected)
+			}
+		})
+	}
+}
+
 // TestJoinReaderDrain tests various scenarios in which a joinReader's consumer
 // is closed.
 func TestJoinReaderDrain(t *testing.T) {
@@ -59,6 +59,11 @@ type outbox struct {
 
 	err error
 	wg  *sync.WaitGroup
+
+	// flowCtxCancel is the cancellation function for this outbox's flow's
+	// context. It is called when the consumer goes away with an error, so
+	// that the rest of the flow stops producing rows nobody will read.
+	flowCtxCancel context.CancelFunc
 }
 
 var _ RowReceiver = &outbox{}
@@ -242,6 +247,9 @@ func (m *outbox) mainLoop(ctx context.Context) error {
 				// the stream is not used any more.
 				m.stream = nil
 				m.syncFlowStream = nil
+				if m.flowCtxCancel != nil {
+					m.flowCtxCancel()
+				}
 				return drainSignal.err
 			}
 			drainCh = nil
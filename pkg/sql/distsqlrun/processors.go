@@ -409,5 +409,17 @@ func newProcessor(
 		}
 		return newAlgebraicSetOp(flowCtx, core.SetOp, inputs[0], inputs[1], post, outputs[0])
 	}
+	if core.Sampler != nil {
+		if err := checkNumInOut(inputs, outputs, 1, 1); err != nil {
+			return nil, err
+		}
+		return newSampler(flowCtx, core.Sampler, inputs[0], post, outputs[0])
+	}
+	if core.SampleAggregator != nil {
+		if err := checkNumInOut(inputs, outputs, 1, 1); err != nil {
+			return nil, err
+		}
+		return newSampleAggregator(flowCtx, core.SampleAggregator, inputs[0], post, outputs[0])
+	}
 	return nil, errors.Errorf("unsupported processor core %s", core)
 }
@@ -0,0 +1,241 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package distsqlrun
+
+import (
+	"sync"
+
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/stats"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/tracing"
+)
+
+// sampleAggregator is a processor that aggregates the results of multiple
+// sampler processors. See the comment for SampleAggregatorSpec for the input
+// and output schemas.
+type sampleAggregator struct {
+	flowCtx  *FlowCtx
+	input    RowSource
+	sr       stats.SampleReservoir
+	sketches []sketchInfo
+
+	datumAlloc sqlbase.DatumAlloc
+	out        procOutputHelper
+
+	// Input column indices for special columns.
+	rankCol      int
+	sketchIdxCol int
+	numRowsCol   int
+	numNullsCol  int
+	sketchCol    int
+}
+
+var _ processor = &sampleAggregator{}
+
+// sampleAggregatorOutputTypes are the types of the columns produced by a
+// sampleAggregator.
+var sampleAggregatorOutputTypes = []sqlbase.ColumnType{
+	intColumnType,   // sketch index
+	intColumnType,   // row count
+	intColumnType,   // distinct count
+	intColumnType,   // null count
+	bytesColumnType, // histogram
+}
+
+func newSampleAggregator(
+	flowCtx *FlowCtx,
+	spec *SampleAggregatorSpec,
+	input RowSource,
+	post *PostProcessSpec,
+	output RowReceiver,
+) (*sampleAggregator, error) {
+	for _, s := range spec.Sketches {
+		if len(s.Columns) == 0 {
+			return nil, errors.Errorf("no columns for sketch")
+		}
+		if s.GenerateHistogram && len(s.Columns) != 1 {
+			return nil, errors.Errorf("histograms are only supported on a single column")
+		}
+	}
+
+	// The input has the columns of the sampled rows followed by the rank and
+	// the four sketch columns.
+	inTypes := input.Types()
+	if len(inTypes) < 5 {
+		return nil, errors.Errorf("sample aggregator input has only %d columns", len(inTypes))
+	}
+	rankCol := len(inTypes) - 5
+	s := &sampleAggregator{
+		flowCtx:      flowCtx,
+		input:        input,
+		sketches:     make([]sketchInfo, len(spec.Sketches)),
+		rankCol:      rankCol,
+		sketchIdxCol: rankCol + 1,
+		numRowsCol:   rankCol + 2,
+		numNullsCol:  rankCol + 3,
+		sketchCol:    rankCol + 4,
+	}
+	for i := range spec.Sketches {
+		s.sketches[i] = sketchInfo{
+			spec:   spec.Sketches[i],
+			sketch: stats.NewSketch(),
+		}
+	}
+	s.sr.Init(int(spec.SampleSize))
+
+	if err := s.out.init(post, sampleAggregatorOutputTypes, &flowCtx.evalCtx, output); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Run is part of the processor interface.
+func (s *sampleAggregator) Run(ctx context.Context, wg *sync.WaitGroup) {
+	if wg != nil {
+		defer wg.Done()
+	}
+
+	ctx = log.WithLogTag(ctx, "SampleAggregator", nil)
+	ctx, span := tracing.ChildSpan(ctx, "sample aggregator")
+	defer tracing.FinishSpan(span)
+
+	if log.V(2) {
+		log.Infof(ctx, "starting sample aggregator process")
+		defer log.Infof(ctx, "exiting sample aggregator")
+	}
+
+	earlyExit, err := s.mainLoop(ctx)
+	if err != nil {
+		DrainAndClose(ctx, s.out.output, err, s.input)
+	} else if !earlyExit {
+		s.out.close()
+	}
+}
+
+// mainLoop aggregates all the input rows and emits one row for each sketch.
+// earlyExit is set if the consumer no longer needs rows, in which case the
+// inputs and the output have already been closed.
+func (s *sampleAggregator) mainLoop(ctx context.Context) (earlyExit bool, _ error) {
+	for {
+		row, meta := s.input.Next()
+		if !meta.Empty() {
+			if meta.Err != nil {
+				return false, meta.Err
+			}
+			if !emitHelper(ctx, &s.out, nil /* row */, meta, s.input) {
+				// No cleanup required; emitHelper() took care of it.
+				return true, nil
+			}
+			continue
+		}
+		if row == nil {
+			break
+		}
+
+		if err := row[s.rankCol].EnsureDecoded(&s.datumAlloc); err != nil {
+			return false, err
+		}
+		if rank := row[s.rankCol].Datum; rank != parser.DNull {
+			// This is a sampled row.
+			s.sr.SampleRow(row[:s.rankCol], uint64(*rank.(*parser.DInt)))
+			continue
+		}
+		// This is a sketch row.
+		if err := s.addSketchRow(row); err != nil {
+			return false, err
+		}
+	}
+
+	outRow := make(sqlbase.EncDatumRow, len(sampleAggregatorOutputTypes))
+	for i, si := range s.sketches {
+		histogram := parser.DNull
+		if si.spec.GenerateHistogram {
+			h, err := s.generateHistogram(si)
+			if err != nil {
+				return false, err
+			}
+			data, err := h.Marshal()
+			if err != nil {
+				return false, err
+			}
+			histogram = parser.NewDBytes(parser.DBytes(data))
+		}
+		outRow[0] = sqlbase.DatumToEncDatum(intColumnType, parser.NewDInt(parser.DInt(i)))
+		outRow[1] = sqlbase.DatumToEncDatum(intColumnType, parser.NewDInt(parser.DInt(si.numRows)))
+		outRow[2] = sqlbase.DatumToEncDatum(
+			intColumnType, parser.NewDInt(parser.DInt(si.sketch.Estimate())),
+		)
+		outRow[3] = sqlbase.DatumToEncDatum(intColumnType, parser.NewDInt(parser.DInt(si.numNulls)))
+		outRow[4] = sqlbase.DatumToEncDatum(bytesColumnType, histogram)
+		if !emitHelper(ctx, &s.out, outRow, ProducerMetadata{}) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// addSketchRow merges the sketch information produced by a sampler.
+func (s *sampleAggregator) addSketchRow(row sqlbase.EncDatumRow) error {
+	for _, col := range []int{s.sketchIdxCol, s.numRowsCol, s.numNullsCol, s.sketchCol} {
+		if err := row[col].EnsureDecoded(&s.datumAlloc); err != nil {
+			return err
+		}
+		if row[col].Datum == parser.DNull {
+			return errors.Errorf("unexpected NULL in sketch row %s", row)
+		}
+	}
+	sketchIdx := int(*row[s.sketchIdxCol].Datum.(*parser.DInt))
+	if sketchIdx < 0 || sketchIdx >= len(s.sketches) {
+		return errors.Errorf("invalid sketch index %d", sketchIdx)
+	}
+	si := &s.sketches[sketchIdx]
+	si.numRows += int64(*row[s.numRowsCol].Datum.(*parser.DInt))
+	si.numNulls += int64(*row[s.numNullsCol].Datum.(*parser.DInt))
+
+	var sketch stats.Sketch
+	if err := sketch.UnmarshalBinary([]byte(*row[s.sketchCol].Datum.(*parser.DBytes))); err != nil {
+		return err
+	}
+	si.sketch.Merge(&sketch)
+	return nil
+}
+
+// generateHistogram builds a histogram on the column of the given sketch from
+// the sampled rows.
+func (s *sampleAggregator) generateHistogram(si sketchInfo) (stats.HistogramData, error) {
+	col := si.spec.Columns[0]
+	var values parser.Datums
+	for _, sample := range s.sr.Get() {
+		ed := &sample.Row[col]
+		if err := ed.EnsureDecoded(&s.datumAlloc); err != nil {
+			return stats.HistogramData{}, err
+		}
+		if ed.Datum != parser.DNull {
+			values = append(values, ed.Datum)
+		}
+	}
+	numRows := si.numRows - si.numNulls
+	if numRows < int64(len(values)) {
+		numRows = int64(len(values))
+	}
+	return stats.EquiDepthHistogram(
+		&s.flowCtx.evalCtx, values, numRows, int(si.spec.HistogramMaxBuckets),
+	)
+}
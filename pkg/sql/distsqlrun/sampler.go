@@ -0,0 +1,250 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package distsqlrun
+
+import (
+	"math/rand"
+	"sync"
+
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/stats"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/cockroach/pkg/util/tracing"
+)
+
+// sketchInfo contains the specification and run-time state for each sketch.
+type sketchInfo struct {
+	spec     SketchSpec
+	sketch   *stats.Sketch
+	numNulls int64
+	numRows  int64
+}
+
+// sampler is a processor that computes table statistics from the input
+// stream: it samples a set of rows and computes cardinality estimation
+// sketches. See the comment for SamplerSpec for the output schema.
+type sampler struct {
+	flowCtx  *FlowCtx
+	input    RowSource
+	sr       stats.SampleReservoir
+	sketches []sketchInfo
+	rng      *rand.Rand
+	outTypes []sqlbase.ColumnType
+
+	datumAlloc sqlbase.DatumAlloc
+	out        procOutputHelper
+
+	// Output column indices for special columns.
+	rankCol      int
+	sketchIdxCol int
+	numRowsCol   int
+	numNullsCol  int
+	sketchCol    int
+}
+
+var _ processor = &sampler{}
+
+var (
+	intColumnType   = sqlbase.ColumnType{Kind: sqlbase.ColumnType_INT}
+	bytesColumnType = sqlbase.ColumnType{Kind: sqlbase.ColumnType_BYTES}
+)
+
+func newSampler(
+	flowCtx *FlowCtx, spec *SamplerSpec, input RowSource, post *PostProcessSpec, output RowReceiver,
+) (*sampler, error) {
+	for _, s := range spec.Sketches {
+		if len(s.Columns) == 0 {
+			return nil, errors.Errorf("no columns for sketch")
+		}
+	}
+
+	s := &sampler{
+		flowCtx:  flowCtx,
+		input:    input,
+		sketches: make([]sketchInfo, len(spec.Sketches)),
+		rng:      rand.New(rand.NewSource(timeutil.Now().UnixNano())),
+	}
+	for i := range spec.Sketches {
+		s.sketches[i] = sketchInfo{
+			spec:   spec.Sketches[i],
+			sketch: stats.NewSketch(),
+		}
+	}
+
+	s.sr.Init(int(spec.SampleSize))
+
+	inTypes := input.Types()
+	outTypes := make([]sqlbase.ColumnType, 0, len(inTypes)+5)
+
+	// First columns are the same as the input.
+	outTypes = append(outTypes, inTypes...)
+
+	// An INT column for the rank of each row.
+	s.rankCol = len(outTypes)
+	outTypes = append(outTypes, intColumnType)
+
+	// An INT column indicating the sketch index.
+	s.sketchIdxCol = len(outTypes)
+	outTypes = append(outTypes, intColumnType)
+
+	// An INT column indicating the number of rows processed.
+	s.numRowsCol = len(outTypes)
+	outTypes = append(outTypes, intColumnType)
+
+	// An INT column indicating the number of rows that have a NULL in any
+	// sketch column.
+	s.numNullsCol = len(outTypes)
+	outTypes = append(outTypes, intColumnType)
+
+	// A BYTES column with the sketch data.
+	s.sketchCol = len(outTypes)
+	outTypes = append(outTypes, bytesColumnType)
+
+	s.outTypes = outTypes
+	if err := s.out.init(post, outTypes, &flowCtx.evalCtx, output); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Run is part of the processor interface.
+func (s *sampler) Run(ctx context.Context, wg *sync.WaitGroup) {
+	if wg != nil {
+		defer wg.Done()
+	}
+
+	ctx = log.WithLogTag(ctx, "Sampler", nil)
+	ctx, span := tracing.ChildSpan(ctx, "sampler")
+	defer tracing.FinishSpan(span)
+
+	if log.V(2) {
+		log.Infof(ctx, "starting sampler process")
+		defer log.Infof(ctx, "exiting sampler")
+	}
+
+	earlyExit, err := s.mainLoop(ctx)
+	if err != nil {
+		DrainAndClose(ctx, s.out.output, err, s.input)
+	} else if !earlyExit {
+		s.out.close()
+	}
+}
+
+// mainLoop reads all the input rows and emits the sampled rows followed by
+// one row for each sketch. earlyExit is set if the consumer no longer needs
+// rows, in which case the inputs and the output have already been closed.
+func (s *sampler) mainLoop(ctx context.Context) (earlyExit bool, _ error) {
+	var buf []byte
+	for {
+		row, meta := s.input.Next()
+		if !meta.Empty() {
+			if meta.Err != nil {
+				return false, meta.Err
+			}
+			if !emitHelper(ctx, &s.out, nil /* row */, meta, s.input) {
+				// No cleanup required; emitHelper() took care of it.
+				return true, nil
+			}
+			continue
+		}
+		if row == nil {
+			break
+		}
+
+		for i := range s.sketches {
+			if err := s.sketches[i].addRow(row, &s.datumAlloc, &buf); err != nil {
+				return false, err
+			}
+		}
+
+		// Use Int63 so we don't have headaches converting to DInt.
+		rank := uint64(s.rng.Int63())
+		s.sr.SampleRow(row, rank)
+	}
+
+	outRow := make(sqlbase.EncDatumRow, len(s.outTypes))
+	for i := range outRow {
+		outRow[i] = sqlbase.DatumToEncDatum(s.outTypes[i], parser.DNull)
+	}
+	// Emit the sampled rows.
+	for _, sample := range s.sr.Get() {
+		copy(outRow, sample.Row)
+		outRow[s.rankCol] = sqlbase.DatumToEncDatum(
+			intColumnType, parser.NewDInt(parser.DInt(sample.Rank)),
+		)
+		if !emitHelper(ctx, &s.out, outRow, ProducerMetadata{}) {
+			return true, nil
+		}
+	}
+	// Release the memory for the sampled rows.
+	s.sr = stats.SampleReservoir{}
+
+	// Emit the sketch rows.
+	for i := range outRow {
+		outRow[i] = sqlbase.DatumToEncDatum(s.outTypes[i], parser.DNull)
+	}
+	for i, si := range s.sketches {
+		outRow[s.sketchIdxCol] = sqlbase.DatumToEncDatum(
+			intColumnType, parser.NewDInt(parser.DInt(i)),
+		)
+		outRow[s.numRowsCol] = sqlbase.DatumToEncDatum(
+			intColumnType, parser.NewDInt(parser.DInt(si.numRows)),
+		)
+		outRow[s.numNullsCol] = sqlbase.DatumToEncDatum(
+			intColumnType, parser.NewDInt(parser.DInt(si.numNulls)),
+		)
+		data, err := si.sketch.MarshalBinary()
+		if err != nil {
+			return false, err
+		}
+		outRow[s.sketchCol] = sqlbase.DatumToEncDatum(
+			bytesColumnType, parser.NewDBytes(parser.DBytes(data)),
+		)
+		if !emitHelper(ctx, &s.out, outRow, ProducerMetadata{}) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// addRow adds a row to the sketch. Rows which have a NULL on any of the
+// columns of the sketch are only counted as NULLs.
+func (si *sketchInfo) addRow(
+	row sqlbase.EncDatumRow, da *sqlbase.DatumAlloc, buf *[]byte,
+) error {
+	si.numRows++
+	*buf = (*buf)[:0]
+	for _, col := range si.spec.Columns {
+		if row[col].IsNull() {
+			si.numNulls++
+			return nil
+		}
+		var err error
+		// We need to use a consistent encoding for the values of a column, as
+		// the rows may come with different encodings; see the similar comment
+		// in distinct.encode.
+		*buf, err = row[col].Encode(da, sqlbase.DatumEncoding_VALUE, *buf)
+		if err != nil {
+			return err
+		}
+	}
+	si.sketch.Insert(*buf)
+	return nil
+}
@@ -0,0 +1,185 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package distsqlrun
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/net/context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/mon"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/stats"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+)
+
+// readRowsNoMeta returns all the rows in buf, failing the test if it
+// contains any metadata.
+func readRowsNoMeta(t *testing.T, buf *RowBuffer) sqlbase.EncDatumRows {
+	var res sqlbase.EncDatumRows
+	for {
+		row, meta := buf.Next()
+		if !meta.Empty() {
+			t.Fatalf("unexpected metadata: %v", meta)
+		}
+		if row == nil {
+			return res
+		}
+		res = append(res, row)
+	}
+}
+
+// TestSamplerSampleAggregator runs two samplers on disjoint sets of rows and
+// aggregates their results with a sampleAggregator.
+func TestSamplerSampleAggregator(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	monitor := mon.MakeUnlimitedMonitor(context.Background(), "test", nil, nil, math.MaxInt64)
+	defer monitor.Stop(context.Background())
+	flowCtx := FlowCtx{evalCtx: parser.EvalContext{Mon: &monitor}}
+
+	const numRows = 1000
+	const sampleSize = 100
+
+	// The rows have a unique column a and a column b with the values 0 to 9;
+	// b is NULL on one row out of 100.
+	types := []sqlbase.ColumnType{intColumnType, intColumnType}
+	var rows [2]sqlbase.EncDatumRows
+	for i := 0; i < numRows; i++ {
+		b := parser.Datum(parser.NewDInt(parser.DInt(i % 10)))
+		if i%100 == 0 {
+			b = parser.DNull
+		}
+		row := sqlbase.EncDatumRow{
+			sqlbase.DatumToEncDatum(intColumnType, parser.NewDInt(parser.DInt(i))),
+			sqlbase.DatumToEncDatum(intColumnType, b),
+		}
+		rows[i%2] = append(rows[i%2], row)
+	}
+
+	sketchSpecs := []SketchSpec{
+		{Columns: []uint32{0}, StatName: "a"},
+		{
+			Columns:             []uint32{1},
+			GenerateHistogram:   true,
+			HistogramMaxBuckets: 4,
+			StatName:            "b",
+		},
+	}
+
+	var samplerOutputs sqlbase.EncDatumRows
+	for i := range rows {
+		in := NewRowBuffer(types, rows[i], RowBufferArgs{})
+		out := &RowBuffer{}
+		spec := &SamplerSpec{Sketches: sketchSpecs, SampleSize: sampleSize}
+		s, err := newSampler(&flowCtx, spec, in, &PostProcessSpec{}, out)
+		if err != nil {
+			t.Fatal(err)
+		}
+		s.Run(context.Background(), nil)
+		if !out.ProducerClosed {
+			t.Fatalf("output RowReceiver not closed")
+		}
+		outRows := readRowsNoMeta(t, out)
+		if len(outRows) != sampleSize+len(sketchSpecs) {
+			t.Fatalf("expected %d rows, got %d", sampleSize+len(sketchSpecs), len(outRows))
+		}
+		samplerOutputs = append(samplerOutputs, outRows...)
+	}
+
+	samplerOutTypes := append(types, intColumnType, intColumnType, intColumnType, intColumnType, bytesColumnType)
+	in := NewRowBuffer(samplerOutTypes, samplerOutputs, RowBufferArgs{})
+	out := &RowBuffer{}
+	spec := &SampleAggregatorSpec{Sketches: sketchSpecs, SampleSize: sampleSize}
+	agg, err := newSampleAggregator(&flowCtx, spec, in, &PostProcessSpec{}, out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	agg.Run(context.Background(), nil)
+	if !out.ProducerClosed {
+		t.Fatalf("output RowReceiver not closed")
+	}
+	results := readRowsNoMeta(t, out)
+	if len(results) != len(sketchSpecs) {
+		t.Fatalf("expected %d rows, got %s", len(sketchSpecs), results)
+	}
+
+	var alloc sqlbase.DatumAlloc
+	intVal := func(ed sqlbase.EncDatum) int64 {
+		if err := ed.EnsureDecoded(&alloc); err != nil {
+			t.Fatal(err)
+		}
+		return int64(*ed.Datum.(*parser.DInt))
+	}
+
+	expected := []struct {
+		minDistinct, maxDistinct int64
+		nulls                    int64
+	}{
+		{minDistinct: 980, maxDistinct: 1020, nulls: 0},
+		{minDistinct: 10, maxDistinct: 10, nulls: 10},
+	}
+	for i, r := range results {
+		if idx := intVal(r[0]); idx != int64(i) {
+			t.Errorf("expected sketch %d, got %d", i, idx)
+		}
+		if rowCount := intVal(r[1]); rowCount != numRows {
+			t.Errorf("sketch %d: expected %d rows, got %d", i, numRows, rowCount)
+		}
+		if distinct := intVal(r[2]); distinct < expected[i].minDistinct ||
+			distinct > expected[i].maxDistinct {
+			t.Errorf("sketch %d: expected %d-%d distinct values, got %d",
+				i, expected[i].minDistinct, expected[i].maxDistinct, distinct)
+		}
+		if nulls := intVal(r[3]); nulls != expected[i].nulls {
+			t.Errorf("sketch %d: expected %d NULLs, got %d", i, expected[i].nulls, nulls)
+		}
+	}
+
+	// Only the second sketch has a histogram.
+	if err := results[0][4].EnsureDecoded(&alloc); err != nil {
+		t.Fatal(err)
+	}
+	if results[0][4].Datum != parser.DNull {
+		t.Errorf("expected no histogram for the first sketch")
+	}
+	if err := results[1][4].EnsureDecoded(&alloc); err != nil {
+		t.Fatal(err)
+	}
+	var h stats.HistogramData
+	if err := h.Unmarshal([]byte(*results[1][4].Datum.(*parser.DBytes))); err != nil {
+		t.Fatal(err)
+	}
+	if len(h.Buckets) == 0 || len(h.Buckets) > 4 {
+		t.Fatalf("expected 1 to 4 buckets, got %v", h)
+	}
+	var total int64
+	for _, b := range h.Buckets {
+		total += b.NumEq + b.NumRange
+	}
+	// The counts are scaled from the sample, so they may be off by a few rows
+	// due to rounding.
+	if total < numRows-10-2*int64(len(h.Buckets)) || total > numRows-10 {
+		t.Errorf("expected about %d rows in the histogram, got %d", numRows-10, total)
+	}
+	if _, last, err := encoding.DecodeVarintAscending(h.Buckets[len(h.Buckets)-1].UpperBound); err != nil {
+		t.Fatal(err)
+	} else if last != 9 {
+		t.Errorf("expected the last bucket to end at 9, got %d", last)
+	}
+}
@@ -31,6 +31,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/mon"
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/storage/engine"
 	"github.com/cockroachdb/cockroach/pkg/util/envutil"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/metric"
@@ -88,6 +89,15 @@ type ServerConfig struct {
 	ParentMemoryMonitor *mon.MemoryMonitor
 	Counter             *metric.Counter
 	Hist                *metric.Histogram
+
+	// TempStorage is used by processors to store data that does not fit in
+	// their memory budget. If nil, processors fail with a memory budget error
+	// instead of spilling to disk.
+	TempStorage engine.Engine
+	// DiskMonitor is used to monitor and cap the processors' usage of
+	// TempStorage.
+	DiskMonitor *mon.MemoryMonitor
+
 	// NodeID is the id of the node on which this Server is running.
 	NodeID *base.NodeIDContainer
 }
@@ -198,6 +208,8 @@ func (ds *ServerImpl) setupFlow(
 		remoteTxnDB:    ds.FlowDB,
 		testingKnobs:   ds.TestingKnobs,
 		nodeID:         nodeID,
+		tempStorage:    ds.TempStorage,
+		diskMonitor:    ds.DiskMonitor,
 	}
 
 	ctx = flowCtx.AnnotateCtx(ctx)
@@ -297,7 +309,7 @@ func (ds *ServerImpl) flowStreamInt(ctx context.Context, stream DistSQL_FlowStre
 	}
 	defer cleanup()
 	log.VEventf(ctx, 1, "connected inbound stream %s/%d", flowID.Short(), streamID)
-	return ProcessInboundStream(f.AnnotateCtx(ctx), stream, msg, receiver)
+	return ProcessInboundStream(f.AnnotateCtx(ctx), stream, msg, receiver, f)
 }
 
 // FlowStream is part of the DistSQLServer interface.
@@ -325,6 +337,11 @@ type TestingKnobs struct {
 	// executing the chunk. It is always called even when the backfill
 	// function returns an error, or if the table has already been dropped.
 	RunAfterBackfillChunk func()
+
+	// ForceDiskSpill forces the processors that can use temporary storage to
+	// spill their rows to disk right away, instead of only when they exhaust
+	// their memory budget.
+	ForceDiskSpill bool
 }
 
 // ModuleTestingKnobs is part of the base.ModuleTestingKnobs interface.
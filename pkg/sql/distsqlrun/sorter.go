@@ -110,3 +110,61 @@ func (s *sorter) Run(ctx context.Context, wg *sync.WaitGroup) {
 	}
 	DrainAndClose(ctx, s.out.output, sortErr, s.rawInput)
 }
+
+// addRow adds a row to the rows being sorted. The row is added to rows unless
+// diskRows is set or the memory budget is exhausted, in which case all the
+// buffered rows are moved to a diskRowContainer. The diskRowContainer holding
+// the rows, if any, is returned.
+func (s *sorter) addRow(
+	ctx context.Context, rows *rowContainer, diskRows *diskRowContainer, row sqlbase.EncDatumRow,
+) (*diskRowContainer, error) {
+	if diskRows != nil {
+		return diskRows, diskRows.AddRow(ctx, row)
+	}
+	if !s.flowCtx.testingKnobs.ForceDiskSpill {
+		err := rows.AddRow(ctx, row)
+		if err == nil || !canSpillToDisk(s.flowCtx, err) {
+			return nil, err
+		}
+		log.VEventf(ctx, 1, "sorter spilling to disk: %v", err)
+	}
+
+	d := makeDiskRowContainer(s.flowCtx, s.rawInput.Types(), s.ordering)
+	for i := 0; i < rows.Len(); i++ {
+		if err := d.AddRow(ctx, rows.EncRow(i)); err != nil {
+			d.Close(ctx)
+			return nil, err
+		}
+	}
+	rows.Clear(ctx)
+	if err := d.AddRow(ctx, row); err != nil {
+		d.Close(ctx)
+		return nil, err
+	}
+	return &d, nil
+}
+
+// emitDiskRows sends the rows stored in diskRows, in sorted order, to the
+// output. It returns false if the consumer doesn't need any more rows.
+func (s *sorter) emitDiskRows(ctx context.Context, diskRows *diskRowContainer) (bool, error) {
+	it, err := diskRows.NewIterator(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer it.Close()
+	for ; ; it.Next() {
+		if ok, err := it.Valid(); err != nil {
+			return false, err
+		} else if !ok {
+			return true, nil
+		}
+		row, err := it.Row()
+		if err != nil {
+			return false, err
+		}
+		consumerStatus, err := s.out.emitRow(ctx, row)
+		if err != nil || consumerStatus != NeedMoreRows {
+			return false, err
+		}
+	}
+}
@@ -173,43 +173,52 @@ func TestSorter(t *testing.T) {
 	monitor := mon.MakeUnlimitedMonitor(context.Background(), "test", nil, nil, math.MaxInt64)
 	defer monitor.Stop(context.Background())
 	for _, c := range testCases {
-		ss := c.spec
-		types := make([]sqlbase.ColumnType, len(c.input[0]))
-		for i := range types {
-			types[i] = columnTypeInt
-		}
-		in := NewRowBuffer(types, c.input, RowBufferArgs{})
-		out := &RowBuffer{}
-		flowCtx := FlowCtx{
-			evalCtx: parser.EvalContext{Mon: &monitor},
-		}
-
-		s, err := newSorter(&flowCtx, &ss, in, &c.post, out)
-		if err != nil {
-			t.Fatal(err)
-		}
-		s.Run(context.Background(), nil)
-		if !out.ProducerClosed {
-			t.Fatalf("output RowReceiver not closed")
-		}
+		for _, spill := range []bool{false, true} {
+			if spill && c.post.Limit != 0 {
+				// The top-k strategy never spills to disk.
+				continue
+			}
+			ss := c.spec
+			types := make([]sqlbase.ColumnType, len(c.input[0]))
+			for i := range types {
+				types[i] = columnTypeInt
+			}
+			in := NewRowBuffer(types, c.input, RowBufferArgs{})
+			out := &RowBuffer{}
+			flowCtx := FlowCtx{
+				evalCtx: parser.EvalContext{Mon: &monitor},
+			}
+			if spill {
+				defer setTempStorage(&flowCtx, true /* forceDiskSpill */)()
+			}
 
-		var retRows sqlbase.EncDatumRows
-		for {
-			row, meta := out.Next()
-			if !meta.Empty() {
-				t.Fatalf("unexpected metadata: %v", meta)
+			s, err := newSorter(&flowCtx, &ss, in, &c.post, out)
+			if err != nil {
+				t.Fatal(err)
 			}
-			if row == nil {
-				break
+			s.Run(context.Background(), nil)
+			if !out.ProducerClosed {
+				t.Fatalf("output RowReceiver not closed")
 			}
-			retRows = append(retRows, row)
-		}
 
-		expStr := c.expected.String()
-		retStr := retRows.String()
-		if expStr != retStr {
-			t.Errorf("invalid results; expected:\n   %s\ngot:\n   %s",
-				expStr, retStr)
+			var retRows sqlbase.EncDatumRows
+			for {
+				row, meta := out.Next()
+				if !meta.Empty() {
+					t.Fatalf("unexpected metadata: %v", meta)
+				}
+				if row == nil {
+					break
+				}
+				retRows = append(retRows, row)
+			}
+
+			expStr := c.expected.String()
+			retStr := retRows.String()
+			if expStr != retStr {
+				t.Errorf("invalid results (spill: %t); expected:\n   %s\ngot:\n   %s",
+					spill, expStr, retStr)
+			}
 		}
 	}
 }
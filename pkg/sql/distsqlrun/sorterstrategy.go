@@ -58,8 +58,16 @@ func newSortAllStrategy(rows rowContainer) sorterStrategy {
 //  - loads all rows into memory;
 //  - runs sort.Sort to sort rows in place;
 //  - sends each row out to the output stream.
+// If the rows don't fit in the memory budget, they are moved to a
+// diskRowContainer which sorts them externally.
 func (ss *sortAllStrategy) Execute(ctx context.Context, s *sorter) error {
 	defer ss.rows.Close(ctx)
+	var diskRows *diskRowContainer
+	defer func() {
+		if diskRows != nil {
+			diskRows.Close(ctx)
+		}
+	}()
 	for {
 		row, err := s.input.NextRow()
 		if err != nil {
@@ -68,10 +76,14 @@ func (ss *sortAllStrategy) Execute(ctx context.Context, s *sorter) error {
 		if row == nil {
 			break
 		}
-		if err := ss.rows.AddRow(ctx, row); err != nil {
+		if diskRows, err = s.addRow(ctx, &ss.rows, diskRows, row); err != nil {
 			return err
 		}
 	}
+	if diskRows != nil {
+		_, err := s.emitDiskRows(ctx, diskRows)
+		return err
+	}
 	ss.rows.Sort()
 
 	for ss.rows.Len() > 0 {
@@ -182,6 +194,13 @@ func newSortChunksStrategy(rows rowContainer) sorterStrategy {
 
 func (ss *sortChunksStrategy) Execute(ctx context.Context, s *sorter) error {
 	defer ss.rows.Close(ctx)
+	// diskRows is set if the current chunk doesn't fit in the memory budget.
+	var diskRows *diskRowContainer
+	defer func() {
+		if diskRows != nil {
+			diskRows.Close(ctx)
+		}
+	}()
 	// pivoted is a helper function that determines if the given row shares the same values for the
 	// first s.matchLen ordering columns with the given pivot.
 	pivoted := func(row, pivot sqlbase.EncDatumRow) (bool, error) {
@@ -208,7 +227,7 @@ func (ss *sortChunksStrategy) Execute(ctx context.Context, s *sorter) error {
 			if log.V(3) {
 				log.Infof(ctx, "pushing row %s", nextRow)
 			}
-			if err := ss.rows.AddRow(ctx, nextRow); err != nil {
+			if diskRows, err = s.addRow(ctx, &ss.rows, diskRows, nextRow); err != nil {
 				return err
 			}
 
@@ -237,6 +256,20 @@ func (ss *sortChunksStrategy) Execute(ctx context.Context, s *sorter) error {
 			break
 		}
 
+		if diskRows != nil {
+			// The chunk was spilled to disk, where it is already sorted.
+			moreRowsNeeded, err := s.emitDiskRows(ctx, diskRows)
+			diskRows.Close(ctx)
+			diskRows = nil
+			if err != nil || !moreRowsNeeded {
+				return err
+			}
+			if nextRow == nil {
+				break
+			}
+			continue
+		}
+
 		// Sort the rows that have been pushed onto the buffer.
 		ss.rows.Sort()
 
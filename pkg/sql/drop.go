@@ -24,6 +24,7 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/config"
 	"github.com/cockroachdb/cockroach/pkg/internal/client"
+	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
 	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
@@ -65,7 +66,7 @@ func (p *planner) DropDatabase(ctx context.Context, n *parser.DropDatabase) (pla
 		return nil, sqlbase.NewUndefinedDatabaseError(string(n.Name))
 	}
 
-	if err := p.CheckPrivilege(dbDesc, privilege.DROP); err != nil {
+	if err := p.CheckPrivilege(ctx, dbDesc, privilege.DROP); err != nil {
 		return nil, err
 	}
 
@@ -248,7 +249,7 @@ func (p *planner) DropIndex(ctx context.Context, n *parser.DropIndex) (planNode,
 			return nil, err
 		}
 
-		if err := p.CheckPrivilege(tableDesc, privilege.CREATE); err != nil {
+		if err := p.CheckPrivilege(ctx, tableDesc, privilege.CREATE); err != nil {
 			return nil, err
 		}
 
@@ -529,6 +530,93 @@ func (*dropViewNode) Spans(context.Context) (_, _ roachpb.Spans, _ error) {
 	panic("unimplemented")
 }
 
+type dropSequenceNode struct {
+	p  *planner
+	n  *parser.DropSequence
+	td []*sqlbase.TableDescriptor
+}
+
+// DropSequence drops a sequence.
+// Privileges: DROP on sequence.
+//   Notes: postgres allows only the sequence owner to DROP a sequence.
+func (p *planner) DropSequence(ctx context.Context, n *parser.DropSequence) (planNode, error) {
+	td := make([]*sqlbase.TableDescriptor, 0, len(n.Names))
+	for _, name := range n.Names {
+		tn, err := name.NormalizeTableName()
+		if err != nil {
+			return nil, err
+		}
+		if err := tn.QualifyWithDatabase(p.session.Database); err != nil {
+			return nil, err
+		}
+
+		droppedDesc, err := p.dropTableOrViewPrepare(ctx, tn)
+		if err != nil {
+			return nil, err
+		}
+		if droppedDesc == nil {
+			if n.IfExists {
+				continue
+			}
+			// Sequence does not exist, but we want it to: error out.
+			return nil, sqlbase.NewUndefinedSequenceError(name.String())
+		}
+		if !droppedDesc.IsSequence() {
+			return nil, sqlbase.NewWrongObjectTypeError(name.String(), "sequence")
+		}
+		td = append(td, droppedDesc)
+	}
+
+	if len(td) == 0 {
+		return &emptyNode{}, nil
+	}
+	return &dropSequenceNode{p: p, n: n, td: td}, nil
+}
+
+func (n *dropSequenceNode) Start(ctx context.Context) error {
+	for i := range n.td {
+		droppedDesc := n.td[i]
+		// Sequences have no indexes and cannot be depended on by views, so
+		// there is nothing to cascade to.
+		if err := n.p.initiateDropTable(ctx, droppedDesc); err != nil {
+			return err
+		}
+		n.p.session.setTestingVerifyMetadata(func(systemConfig config.SystemConfig) error {
+			return verifyDropTableMetadata(systemConfig, droppedDesc.ID, "sequence")
+		})
+		// Log a Drop Sequence event for this sequence. This is an auditable log
+		// event and is recorded in the same transaction as the table descriptor
+		// update.
+		if err := MakeEventLogger(n.p.LeaseMgr()).InsertEventRecord(
+			ctx,
+			n.p.txn,
+			EventLogDropSequence,
+			int32(droppedDesc.ID),
+			int32(n.p.evalCtx.NodeID),
+			struct {
+				SequenceName string
+				Statement    string
+				User         string
+			}{droppedDesc.Name, n.n.String(), n.p.session.User},
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (*dropSequenceNode) Next(context.Context) (bool, error) { return false, nil }
+func (*dropSequenceNode) Close(context.Context)              {}
+func (*dropSequenceNode) Columns() sqlbase.ResultColumns     { return make(sqlbase.ResultColumns, 0) }
+func (*dropSequenceNode) Ordering() orderingInfo             { return orderingInfo{} }
+func (*dropSequenceNode) Values() parser.Datums              { return parser.Datums{} }
+func (*dropSequenceNode) DebugValues() debugValues           { return debugValues{} }
+func (*dropSequenceNode) MarkDebug(mode explainMode)         {}
+
+func (*dropSequenceNode) Spans(context.Context) (_, _ roachpb.Spans, _ error) {
+	panic("unimplemented")
+}
+
 type dropTableNode struct {
 	p  *planner
 	n  *parser.DropTable
@@ -614,7 +702,7 @@ func (p *planner) canRemoveFK(
 	if behavior != parser.DropCascade {
 		return nil, fmt.Errorf("%q is referenced by foreign key from table %q", from, table.Name)
 	}
-	if err := p.CheckPrivilege(table, privilege.CREATE); err != nil {
+	if err := p.CheckPrivilege(ctx, table, privilege.CREATE); err != nil {
 		return nil, err
 	}
 	return table, nil
@@ -637,7 +725,7 @@ func (p *planner) canRemoveInterleave(
 		return util.UnimplementedWithIssueErrorf(
 			8036, "%q is interleaved by table %q", from, table.Name)
 	}
-	if err := p.CheckPrivilege(table, privilege.CREATE); err != nil {
+	if err := p.CheckPrivilege(ctx, table, privilege.CREATE); err != nil {
 		return err
 	}
 	return nil
@@ -664,7 +752,7 @@ func (p *planner) canRemoveDependentViewGeneric(
 	if err != nil {
 		return err
 	}
-	if err := p.CheckPrivilege(viewDesc, privilege.DROP); err != nil {
+	if err := p.CheckPrivilege(ctx, viewDesc, privilege.DROP); err != nil {
 		return err
 	}
 	// If this view is depended on by other views, we have to check them as well.
@@ -794,7 +882,7 @@ func (p *planner) dropTableOrViewPrepare(
 		return nil, err
 	}
 
-	if err := p.CheckPrivilege(tableDesc, privilege.DROP); err != nil {
+	if err := p.CheckPrivilege(ctx, tableDesc, privilege.DROP); err != nil {
 		return nil, err
 	}
 	return tableDesc, nil
@@ -1033,7 +1121,12 @@ func truncateAndDropTable(
 		}
 	}
 
-	if err := truncateTableInChunks(ctx, tableDesc, db); err != nil {
+	if tableDesc.IsSequence() {
+		// A sequence has no rows; its value is stored in a single key.
+		if err := db.Del(ctx, keys.MakeSequenceKey(uint32(tableDesc.ID))); err != nil {
+			return err
+		}
+	} else if err := truncateTableInChunks(ctx, tableDesc, db); err != nil {
 		return err
 	}
 
@@ -0,0 +1,137 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+
+	"golang.org/x/net/context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+)
+
+// auditLogger writes the SQL audit log, which records every statement
+// touching a table on which auditing was enabled with ALTER TABLE ...
+// EXPERIMENTAL_AUDIT. Its entries are synced to disk before the statement
+// returns, in files named cockroach-sql-audit.*.log in the log directory.
+var auditLogger = log.NewSecondaryLogger("sql-audit", true /* forceSyncWrites */)
+
+// auditEvent is an access to a table with auditing enabled, recorded by the
+// planner when it resolves the descriptor of a table to be scanned or written.
+type auditEvent struct {
+	desc    *sqlbase.TableDescriptor
+	writing bool
+}
+
+// maybeAudit records an auditEvent if auditing is enabled on the table. It is
+// called whether or not the user has the privileges needed for the access, so
+// that denied accesses are audited too.
+func (p *planner) maybeAudit(desc *sqlbase.TableDescriptor, writing bool) {
+	if desc.AuditMode == sqlbase.TableDescriptor_DISABLED {
+		return
+	}
+	for i := range p.auditEvents {
+		if ev := &p.auditEvents[i]; ev.desc.ID == desc.ID {
+			ev.writing = ev.writing || writing
+			return
+		}
+	}
+	p.auditEvents = append(p.auditEvents, auditEvent{desc: desc, writing: writing})
+}
+
+// auditFKTables records auditEvents for the tables looked up by
+// fillFKTableMap for the foreign keys of a table whose rows are changed as
+// specified by usage. These tables are read by foreign key checks, and those
+// with a cascading referential action on a table whose rows are deleted or
+// updated are written as well.
+func (p *planner) auditFKTables(
+	table *sqlbase.TableDescriptor, m sqlbase.TableLookupsByID, usage sqlbase.FKCheck,
+) {
+	written := map[sqlbase.ID]bool{}
+	if usage != sqlbase.CheckInserts {
+		written[table.ID] = true
+	}
+	for changed := len(written) > 0; changed; {
+		changed = false
+		for id, lookup := range m {
+			if lookup.Table == nil || written[id] {
+				continue
+			}
+			for _, idx := range lookup.Table.AllNonDropIndexes() {
+				fk := idx.ForeignKey
+				if fk.IsSet() && written[fk.Table] &&
+					(fk.OnDelete.IsCascading() || fk.OnUpdate.IsCascading()) {
+					written[id], changed = true, true
+					break
+				}
+			}
+		}
+	}
+	for id, lookup := range m {
+		if lookup.Table != nil {
+			p.maybeAudit(lookup.Table, written[id])
+		}
+	}
+}
+
+// maybeLogStatement writes one entry to the SQL audit log for every audited
+// table the statement accessed. rows is the number of rows returned or
+// affected and err the error of the statement, if any.
+func (p *planner) maybeLogStatement(
+	ctx context.Context, stmt parser.Statement, rows int, err error,
+) {
+	if len(p.auditEvents) == 0 {
+		return
+	}
+
+	var placeholders bytes.Buffer
+	values := p.semaCtx.Placeholders.Values
+	placeholders.WriteByte('{')
+	for i := 1; i <= len(values); i++ {
+		if i > 1 {
+			placeholders.WriteString(", ")
+		}
+		name := strconv.Itoa(i)
+		fmt.Fprintf(&placeholders, "$%s: ", name)
+		if v, ok := values[name]; ok {
+			placeholders.WriteString(v.String())
+		} else {
+			placeholders.WriteString("?")
+		}
+	}
+	placeholders.WriteByte('}')
+
+	errStr := ""
+	if err != nil {
+		errStr = err.Error()
+	}
+
+	s := p.session
+	for _, ev := range p.auditEvents {
+		access := "r"
+		if ev.writing {
+			access = "rw"
+		}
+		auditLogger.Logf(ctx,
+			"user=%s app=%q client=%s table=%q access=%s stmt=%q placeholders=%s rows=%d err=%q",
+			s.User, s.ApplicationName, s.clientAddr, ev.desc.Name, access,
+			stmt.String(), placeholders.String(), rows, errStr,
+		)
+	}
+}
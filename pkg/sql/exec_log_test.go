@@ -0,0 +1,98 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql_test
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/context"
+
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+)
+
+// readAuditLog returns the contents of the SQL audit log files.
+func readAuditLog(t *testing.T) string {
+	log.Flush()
+	files, err := log.ListLogFiles()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var contents []string
+	for _, f := range files {
+		if !strings.Contains(f.Name, "sql-audit") {
+			continue
+		}
+		r, err := log.GetLogReader(f.Name, true /* restricted */)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := ioutil.ReadAll(r)
+		r.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		contents = append(contents, string(b))
+	}
+	return strings.Join(contents, "")
+}
+
+// TestAuditLog verifies that statements accessing an audited table are
+// logged, including when the table is read through a view or written by a
+// cascading foreign key action.
+func TestAuditLog(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.ScopeWithoutShowLogs(t).Close(t)
+
+	params, _ := createTestServerParams()
+	s, rawSQLDB, _ := serverutils.StartServer(t, params)
+	defer s.Stopper().Stop(context.TODO())
+	db := sqlutils.MakeSQLRunner(t, rawSQLDB)
+
+	db.Exec(`CREATE DATABASE d`)
+	db.Exec(`CREATE TABLE d.parent (k INT PRIMARY KEY)`)
+	db.Exec(`CREATE TABLE d.child (
+		k INT PRIMARY KEY, p INT REFERENCES d.parent ON DELETE CASCADE, INDEX (p)
+	)`)
+	db.Exec(`CREATE VIEW d.v AS SELECT k FROM d.child`)
+	db.Exec(`INSERT INTO d.parent VALUES (1), (2)`)
+	db.Exec(`INSERT INTO d.child VALUES (1, 1), (2, 2)`)
+	db.Exec(`ALTER TABLE d.child EXPERIMENTAL_AUDIT SET READ WRITE`)
+
+	testCases := []struct {
+		stmt     string
+		expected string
+	}{
+		{`SELECT * FROM d.child`, `table="child" access=r stmt="SELECT * FROM d.child"`},
+		{`SELECT * FROM d.v`, `table="child" access=r stmt="SELECT * FROM d.v"`},
+		{`DELETE FROM d.parent WHERE k = 1`, `table="child" access=rw stmt="DELETE FROM d.parent WHERE k = 1"`},
+	}
+	for _, tc := range testCases {
+		db.Exec(tc.stmt)
+		if contents := readAuditLog(t); !strings.Contains(contents, tc.expected) {
+			t.Errorf("%s: expected audit log to contain %s, got:\n%s", tc.stmt, tc.expected, contents)
+		}
+	}
+
+	// Statements which do not touch the audited table are not logged.
+	db.Exec(`SELECT * FROM d.parent`)
+	if contents := readAuditLog(t); strings.Contains(contents, `stmt="SELECT * FROM d.parent"`) {
+		t.Errorf("unexpected audit log entry for table parent:\n%s", contents)
+	}
+}
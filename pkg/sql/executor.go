@@ -36,6 +36,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/kv"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/rpc"
+	"github.com/cockroachdb/cockroach/pkg/server/serverpb"
 	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/sql/distsqlplan"
 	"github.com/cockroachdb/cockroach/pkg/sql/distsqlrun"
@@ -182,6 +183,14 @@ func (r *Result) Close(ctx context.Context) {
 	}
 }
 
+// numRows returns the number of rows returned or affected by the statement.
+func (r *Result) numRows() int {
+	if r.Type == parser.Rows && r.Rows != nil {
+		return r.Rows.Len()
+	}
+	return r.RowsAffected
+}
+
 // An Executor executes SQL statements.
 // Executor is thread-safe.
 type Executor struct {
@@ -251,6 +260,19 @@ type ExecutorConfig struct {
 	// Caches updated by DistSQL.
 	RangeDescriptorCache *kv.RangeDescriptorCache
 	LeaseHolderCache     *kv.LeaseHolderCache
+
+	// SessionRegistry holds the sessions of the clients connected to this
+	// node, for use by SHOW QUERIES and CANCEL QUERY.
+	SessionRegistry *SessionRegistry
+	// StatusServer is used to reach the other nodes of the cluster, for
+	// listing and canceling queries cluster-wide.
+	StatusServer serverpb.StatusServer
+	// JobRegistry tracks the jobs running on this node and adopts the jobs
+	// orphaned by dead nodes.
+	JobRegistry *JobRegistry
+	// TableStatsCache caches the statistics of the tables, which are used
+	// by the planner to estimate row counts.
+	TableStatsCache *TableStatisticsCache
 }
 
 var _ base.ModuleTestingKnobs = &ExecutorTestingKnobs{}
@@ -404,6 +426,30 @@ func (e *Executor) updateSystemConfig(cfg config.SystemConfig) {
 	e.systemConfigCond.Broadcast()
 }
 
+// generateID generates a cluster-wide unique ID for a query or session based
+// on the node's ID and the current HLC timestamp. The node ID is encoded in
+// the last eight hex digits so that requests concerning the query or session
+// can be routed to its node; see nodeIDFromID.
+func (e *Executor) generateID() string {
+	timestamp := e.cfg.Clock.Now()
+	nodeID := e.cfg.NodeID.Get()
+	return fmt.Sprintf("%016x%08x%08x", timestamp.WallTime, uint32(timestamp.Logical), uint32(nodeID))
+}
+
+// nodeIDFromID extracts the ID of the node running a query or session from
+// its ID, as generated by generateID. It returns false if the ID is
+// malformed.
+func nodeIDFromID(id string) (roachpb.NodeID, bool) {
+	if len(id) != 32 {
+		return 0, false
+	}
+	nodeID, err := strconv.ParseUint(id[24:], 16, 32)
+	if err != nil {
+		return 0, false
+	}
+	return roachpb.NodeID(nodeID), true
+}
+
 // getDatabaseCache returns a database cache with a copy of the latest
 // system config.
 func (e *Executor) getDatabaseCache() *databaseCache {
@@ -555,6 +601,12 @@ func (session *Session) CopyEnd(ctx context.Context) {
 	session.copyFrom = nil
 }
 
+// SetCopyOutWriter sets the writer to which the rows of COPY TO statements
+// are streamed. COPY TO is rejected in sessions without a writer.
+func (session *Session) SetCopyOutWriter(w CopyOutWriter) {
+	session.copyOut = w
+}
+
 // blockConfigUpdates blocks any gossip updates to the system config
 // until the unlock function returned is called. Useful in tests.
 func (e *Executor) blockConfigUpdates() func() {
@@ -621,6 +673,17 @@ func (e *Executor) execRequest(
 		err = fmt.Errorf("unexpected copy command")
 	} else {
 		stmts, err = parser.Parse(sql)
+		if err == nil && len(stmts) > 1 {
+			// The results of the statements are sent to the client after all of
+			// them have executed, while the rows of a COPY TO are sent during its
+			// execution.
+			for _, stmt := range stmts {
+				if _, ok := stmt.(*parser.CopyTo); ok {
+					err = errors.New("COPY TO STDOUT must be the only statement in the query")
+					break
+				}
+			}
+		}
 	}
 	session.phaseTimes[sessionEndParse] = timeutil.Now()
 
@@ -695,12 +758,16 @@ func (e *Executor) execParsed(
 				}
 			}
 			txnState.resetForNewSQLTxn(e, session)
-			txnState.autoRetry = true
+			// The rows of a COPY TO are sent to the client as they are
+			// produced, so it cannot be retried.
+			_, isCopyTo := stmtsToExec[0].(*parser.CopyTo)
+			txnState.autoRetry = !isCopyTo
 			txnState.sqlTimestamp = e.cfg.Clock.PhysicalTime()
 			if execOpt.AutoCommit {
 				txnState.txn.SetDebugName(sqlImplicitTxnName)
 			} else {
 				txnState.txn.SetDebugName(sqlTxnName)
+				session.setTxnStart(txnState.sqlTimestamp)
 			}
 		} else {
 			txnState.autoRetry = false
@@ -798,7 +865,8 @@ func (e *Executor) execParsed(
 		}
 
 		// Sanity check about not leaving KV txns open on errors.
-		if err != nil && txnState.txn != nil && !txnState.txn.IsFinalized() {
+		if err != nil && txnState.txn != nil && !txnState.txn.IsFinalized() &&
+			!txnState.canRollbackToSavepoint() {
 			if _, retryable := err.(*roachpb.HandledRetryableTxnError); !retryable {
 				log.Fatalf(session.Ctx(), "got a non-retryable error but the KV "+
 					"transaction is not finalized. TxnState: %s, err: %s\n"+
@@ -826,13 +894,14 @@ func (e *Executor) execParsed(
 		// If we're no longer in a transaction, finish the trace.
 		if txnState.State == NoTxn {
 			txnState.finishSQLTxn(session.context)
+			session.setTxnStart(time.Time{})
 		}
 
 		// If the txn is in any state but Open, exec the schema changes. They'll
 		// short-circuit themselves if the mutation that queued them has been
 		// rolled back from the table descriptor.
 		stmtsExecuted := stmts[:len(stmtsToExec)-len(remainingStmts)]
-		if txnState.State != Open {
+		if txnState.State != Open && !txnState.canRollbackToSavepoint() {
 			session.checkTestingVerifyMetadataInitialOrDie(e, stmts)
 			session.checkTestingVerifyMetadataOrDie(e, stmtsExecuted)
 
@@ -899,6 +968,8 @@ func runTxnAttempt(
 	if automaticRetryCount > 0 {
 		session.TxnState.State = origState
 		session.TxnState.commitSeen = false
+		session.TxnState.savepoints = nil
+		session.TxnState.roleMemberships = nil
 	}
 
 	results, remainingStmts, err := e.execStmtsInCurrentTxn(
@@ -1064,6 +1135,8 @@ func runShowTransactionState(session *Session, implicitTxn bool) (Result, error)
 // - COMMIT / ROLLBACK: aborts the current transaction.
 // - ROLLBACK TO SAVEPOINT / SAVEPOINT: reopens the current transaction,
 //   allowing it to be retried.
+// - ROLLBACK TO SAVEPOINT name: reopens the current transaction, undoing the
+//   writes performed since the savepoint was established.
 func (e *Executor) execStmtInAbortedTxn(session *Session, stmt parser.Statement) (Result, error) {
 	txnState := &session.TxnState
 	if txnState.State != Aborted && txnState.State != RestartWait {
@@ -1072,7 +1145,7 @@ func (e *Executor) execStmtInAbortedTxn(session *Session, stmt parser.Statement)
 	// TODO(andrei/cuongdo): Figure out what statements to count here.
 	switch s := stmt.(type) {
 	case *parser.CommitTransaction, *parser.RollbackTransaction:
-		if txnState.State == RestartWait {
+		if txnState.State == RestartWait || txnState.canRollbackToSavepoint() {
 			return rollbackSQLTransaction(txnState), nil
 		}
 		// Reset the state to allow new transactions to start.
@@ -1094,33 +1167,35 @@ func (e *Executor) execStmtInAbortedTxn(session *Session, stmt parser.Statement)
 		default:
 			panic("unreachable")
 		}
-		if err := parser.ValidateRestartCheckpoint(spName); err != nil {
+		if err := parser.ValidateRestartCheckpoint(spName); err == nil {
+			if txnState.State == RestartWait {
+				// Reset the state. Txn is Open again.
+				txnState.State = Open
+				// TODO(andrei/cdo): add a counter for user-directed retries.
+				return Result{}, nil
+			}
+			err := sqlbase.NewTransactionAbortedError(fmt.Sprintf(
+				"SAVEPOINT %s has not been used or a non-retriable error was encountered",
+				parser.RestartSavepointName))
 			return Result{}, err
 		}
-		if txnState.State == RestartWait {
-			// Reset the state. Txn is Open again.
-			txnState.State = Open
-			// TODO(andrei/cdo): add a counter for user-directed retries.
-			return Result{}, nil
+		// Other savepoints can be rolled back to after a non-retriable error.
+		if n, ok := s.(*parser.RollbackToSavepoint); ok && txnState.State == Aborted {
+			return rollbackToSavepoint(txnState, n.Savepoint)
 		}
-		err := sqlbase.NewTransactionAbortedError(fmt.Sprintf(
-			"SAVEPOINT %s has not been used or a non-retriable error was encountered",
-			parser.RestartSavepointName))
+	}
+	if txnState.State == RestartWait {
+		err := sqlbase.NewTransactionAbortedError(
+			"Expected \"ROLLBACK TO SAVEPOINT COCKROACH_RESTART\"" /* customMsg */)
+		// If we were waiting for a restart, but the client failed to perform it,
+		// we'll cleanup the txn. The client is not respecting the protocol, so
+		// there seems to be little point in staying in RestartWait (plus,
+		// higher-level code asserts that we're only in RestartWait when returning
+		// retryable errors to the client).
+		txnState.updateStateAndCleanupOnErr(err, e)
 		return Result{}, err
-	default:
-		if txnState.State == RestartWait {
-			err := sqlbase.NewTransactionAbortedError(
-				"Expected \"ROLLBACK TO SAVEPOINT COCKROACH_RESTART\"" /* customMsg */)
-			// If we were waiting for a restart, but the client failed to perform it,
-			// we'll cleanup the txn. The client is not respecting the protocol, so
-			// there seems to be little point in staying in RestartWait (plus,
-			// higher-level code asserts that we're only in RestartWait when returning
-			// retryable errors to the client).
-			txnState.updateStateAndCleanupOnErr(err, e)
-			return Result{}, err
-		}
-		return Result{}, sqlbase.NewTransactionAbortedError("" /* customMsg */)
 	}
+	return Result{}, sqlbase.NewTransactionAbortedError("" /* customMsg */)
 }
 
 // execStmtInCommitWaitTxn executes a statement in a txn that's in state
@@ -1241,7 +1316,7 @@ func (e *Executor) execStmtInOpenTxn(
 		return commitSQLTransaction(txnState, commit)
 	case *parser.ReleaseSavepoint:
 		if err := parser.ValidateRestartCheckpoint(s.Savepoint); err != nil {
-			return Result{}, err
+			return releaseSavepoint(txnState, s.Savepoint)
 		}
 		// ReleaseSavepoint is executed fully here; there's no planNode for it
 		// and a planner is not involved at all.
@@ -1252,8 +1327,15 @@ func (e *Executor) execStmtInOpenTxn(
 		// Notice that we don't return any errors on rollback.
 		return rollbackSQLTransaction(txnState), nil
 	case *parser.Savepoint:
+		// Note that Savepoint doesn't have a corresponding plan node.
+		// This here is all the execution there is.
 		if err := parser.ValidateRestartCheckpoint(s.Name); err != nil {
-			return Result{}, err
+			txnState.savepoints = append(txnState.savepoints, savepoint{
+				name:              s.Name,
+				seq:               txnState.txn.Sequence(),
+				numSchemaChangers: len(txnState.schemaChangers.schemaChangers),
+			})
+			return Result{}, nil
 		}
 		// We want to disallow SAVEPOINTs to be issued after a transaction has
 		// started running. The client txn's statement count indicates how many
@@ -1262,19 +1344,22 @@ func (e *Executor) execStmtInOpenTxn(
 			return Result{}, errors.Errorf("SAVEPOINT %s needs to be the first statement in a "+
 				"transaction", parser.RestartSavepointName)
 		}
-		// Note that Savepoint doesn't have a corresponding plan node.
-		// This here is all the execution there is.
 		txnState.retryIntent = true
 		return Result{}, nil
 	case *parser.RollbackToSavepoint:
-		err := parser.ValidateRestartCheckpoint(s.Savepoint)
+		if err := parser.ValidateRestartCheckpoint(s.Savepoint); err != nil {
+			return rollbackToSavepoint(txnState, s.Savepoint)
+		}
 		// If commands have already been sent through the transaction,
 		// restart the client txn's proto to increment the epoch. The SQL
-		// txn's state is already set to OPEN.
-		if err == nil && txnState.txn.CommandCount() > 0 {
+		// txn's state is already set to OPEN. The writes performed since
+		// any other savepoint are discarded along with the epoch.
+		if txnState.txn.CommandCount() > 0 {
 			txnState.txn.Proto().Restart(0, 0, hlc.Timestamp{})
 		}
-		return Result{}, err
+		txnState.savepoints = nil
+		txnState.roleMemberships = nil
+		return Result{}, nil
 	case *parser.Prepare:
 		name := s.Name.String()
 		if session.PreparedStatements.Exists(name) {
@@ -1343,6 +1428,18 @@ func (e *Executor) execStmtInOpenTxn(
 	planner.avoidCachedDescriptors = avoidCachedDescriptors
 	planner.phaseTimes[plannerStartExecStmt] = timeutil.Now()
 
+	// Register the query with the session so that it can be listed by SHOW
+	// QUERIES and canceled by CANCEL QUERY. Canceling the query cancels the
+	// context that it runs in.
+	queryID := e.generateID()
+	ctx, cancelQuery := context.WithCancel(session.Ctx())
+	session.addActiveQuery(queryID, &queryMeta{
+		start:     planner.phaseTimes[plannerStartExecStmt],
+		stmt:      stmt,
+		ctxCancel: cancelQuery,
+	})
+	query := activeQuery{id: queryID, ctx: ctx, cancel: cancelQuery}
+
 	var result Result
 	if parallelize && !implicitTxn {
 		// Only run statements asynchronously through the parallelize queue if the
@@ -1350,11 +1447,12 @@ func (e *Executor) execStmtInOpenTxn(
 		// statements outside of a transaction are run synchronously with mocked
 		// results, which has the same effect as running asynchronously but
 		// immediately blocking.
-		result, err = e.execStmtInParallel(stmt, planner)
+		result, err = e.execStmtInParallel(query, stmt, planner)
 	} else {
 		planner.autoCommit = implicitTxn && !e.cfg.TestingKnobs.DisableAutoCommit
-		result, err = e.execStmt(stmt, planner,
+		result, err = e.execStmt(query, stmt, planner,
 			automaticRetryCount, parallelize /* mockResults */)
+		query.finish(session)
 	}
 
 	if err != nil {
@@ -1371,7 +1469,7 @@ func (e *Executor) execStmtInOpenTxn(
 
 	tResult := &traceResult{tag: result.PGTag, count: -1}
 	switch result.Type {
-	case parser.RowsAffected:
+	case parser.RowsAffected, parser.CopyOut:
 		tResult.count = result.RowsAffected
 	case parser.Rows:
 		tResult.count = result.Rows.Len()
@@ -1397,7 +1495,8 @@ func stmtAllowedInImplicitTxn(stmt parser.Statement) bool {
 
 // rollbackSQLTransaction rolls back a transaction. All errors are swallowed.
 func rollbackSQLTransaction(txnState *txnState) Result {
-	if txnState.State != Open && txnState.State != RestartWait {
+	if txnState.State != Open && txnState.State != RestartWait &&
+		!txnState.canRollbackToSavepoint() {
 		panic(fmt.Sprintf("rollbackSQLTransaction called on txn in wrong state: %s (txn: %s)",
 			txnState.State, txnState.txn.Proto()))
 	}
@@ -1412,6 +1511,50 @@ func rollbackSQLTransaction(txnState *txnState) Result {
 	return result
 }
 
+// findSavepoint returns the index of the innermost savepoint with the given
+// name, or an error if there is no such savepoint.
+func findSavepoint(txnState *txnState, name string) (int, error) {
+	for i := len(txnState.savepoints) - 1; i >= 0; i-- {
+		if txnState.savepoints[i].name == name {
+			return i, nil
+		}
+	}
+	return 0, pgerror.NewErrorf(pgerror.CodeInvalidSavepointSpecificationError,
+		"savepoint %s does not exist", name)
+}
+
+// releaseSavepoint destroys a savepoint and the savepoints established after
+// it. The writes performed since then are kept.
+func releaseSavepoint(txnState *txnState, name string) (Result, error) {
+	i, err := findSavepoint(txnState, name)
+	if err != nil {
+		return Result{}, err
+	}
+	txnState.savepoints = txnState.savepoints[:i]
+	return Result{}, nil
+}
+
+// rollbackToSavepoint undoes the writes and schema changes performed since
+// a savepoint was established and destroys the savepoints established after
+// it. The savepoint itself remains, and the txn is reopened if it was
+// Aborted.
+func rollbackToSavepoint(txnState *txnState, name string) (Result, error) {
+	i, err := findSavepoint(txnState, name)
+	if err != nil {
+		return Result{}, err
+	}
+	txnState.txn.RollbackToSequence(txnState.savepoints[i].seq)
+	// The descriptors written by DDL statements since the savepoint were
+	// rolled back along with the other writes, so their schema changes must
+	// not run either.
+	scc := &txnState.schemaChangers
+	scc.schemaChangers = scc.schemaChangers[:txnState.savepoints[i].numSchemaChangers]
+	txnState.savepoints = txnState.savepoints[:i+1]
+	txnState.roleMemberships = nil
+	txnState.State = Open
+	return Result{}, nil
+}
+
 type commitType int
 
 const (
@@ -1448,9 +1591,10 @@ func commitSQLTransaction(txnState *txnState, commitType commitType) (Result, er
 
 // exectDistSQL converts a classic plan to a distributed SQL physical plan and
 // runs it.
-func (e *Executor) execDistSQL(planner *planner, tree planNode, result *Result) error {
+func (e *Executor) execDistSQL(
+	ctx context.Context, planner *planner, tree planNode, result *Result,
+) error {
 	// Note: if we just want the row count, result.Rows is nil here.
-	ctx := planner.session.Ctx()
 	recv, err := makeDistSQLReceiver(
 		ctx, result.Rows,
 		e.cfg.RangeDescriptorCache, e.cfg.LeaseHolderCache,
@@ -1477,8 +1621,9 @@ func (e *Executor) execDistSQL(planner *planner, tree planNode, result *Result)
 
 // execClassic runs a plan using the classic (non-distributed) SQL
 // implementation.
-func (e *Executor) execClassic(planner *planner, plan planNode, result *Result) error {
-	ctx := planner.session.Ctx()
+func (e *Executor) execClassic(
+	ctx context.Context, planner *planner, plan planNode, result *Result,
+) error {
 	if err := planner.startPlan(ctx, plan); err != nil {
 		return err
 	}
@@ -1494,6 +1639,9 @@ func (e *Executor) execClassic(planner *planner, plan planNode, result *Result)
 	case parser.Rows:
 		next, err := plan.Next(ctx)
 		for ; next; next, err = plan.Next(ctx) {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
 			// The plan.Values Datums needs to be copied on each iteration.
 			values := plan.Values()
 
@@ -1513,6 +1661,13 @@ func (e *Executor) execClassic(planner *planner, plan planNode, result *Result)
 		if n, ok := plan.(*createTableNode); ok && n.n.As() {
 			result.RowsAffected += n.count
 		}
+
+	case parser.CopyOut:
+		count, err := plan.(*copyToNode).run(ctx, planner.session.copyOut)
+		if err != nil {
+			return err
+		}
+		result.RowsAffected += count
 	}
 	return nil
 }
@@ -1528,6 +1683,11 @@ func (e *Executor) shouldUseDistSQL(planner *planner, plan planNode) (bool, erro
 	if _, ok := plan.(*emptyNode); ok {
 		return false, nil
 	}
+	// COPY TO streams its rows to the client as they are produced, which
+	// distSQL doesn't support.
+	if _, ok := plan.(*copyToNode); ok {
+		return false, nil
+	}
 
 	var err error
 	var distribute bool
@@ -1585,22 +1745,53 @@ func makeRes(stmt parser.Statement, planner *planner, plan planNode) (Result, er
 	return result, nil
 }
 
+// activeQuery is a query registered with its session's list of active
+// queries, along with the context that it executes in.
+type activeQuery struct {
+	id     string
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// finish removes the query from its session's list of active queries and
+// releases the resources associated with its context.
+func (q activeQuery) finish(session *Session) {
+	session.removeActiveQuery(q.id)
+	q.cancel()
+}
+
+// convertCanceledErr returns a query cancellation error in place of err if
+// the query's context was canceled during execution.
+func (q activeQuery) convertCanceledErr(err error) error {
+	if err != nil && q.ctx.Err() == context.Canceled {
+		return sqlbase.NewQueryCanceledError()
+	}
+	return err
+}
+
 // execStmt executes the statement synchronously and returns the statement's result.
 // If mockResults is set, these results will be replaced by the "zero value" of the
 // statement's result type, identical to the mock results returned by execStmtInParallel.
 func (e *Executor) execStmt(
-	stmt parser.Statement, planner *planner, automaticRetryCount int, mockResults bool,
+	query activeQuery,
+	stmt parser.Statement,
+	planner *planner,
+	automaticRetryCount int,
+	mockResults bool,
 ) (Result, error) {
 	session := planner.session
+	ctx := query.ctx
 
 	planner.phaseTimes[plannerStartLogicalPlan] = timeutil.Now()
-	plan, err := planner.makePlan(session.Ctx(), stmt)
+	plan, err := planner.makePlan(ctx, stmt)
 	planner.phaseTimes[plannerEndLogicalPlan] = timeutil.Now()
 	if err != nil {
+		err = query.convertCanceledErr(err)
+		planner.maybeLogStatement(ctx, stmt, 0, err)
 		return Result{}, err
 	}
 
-	defer plan.Close(session.Ctx())
+	defer plan.Close(ctx)
 
 	result, err := makeRes(stmt, planner, plan)
 	if err != nil {
@@ -1609,27 +1800,30 @@ func (e *Executor) execStmt(
 
 	useDistSQL, err := e.shouldUseDistSQL(planner, plan)
 	if err != nil {
-		result.Close(session.Ctx())
+		result.Close(ctx)
 		return Result{}, err
 	}
+	session.setQueryExecutionMode(query.id, useDistSQL)
 
 	planner.phaseTimes[plannerStartExecStmt] = timeutil.Now()
 	if useDistSQL {
-		err = e.execDistSQL(planner, plan, &result)
+		err = e.execDistSQL(ctx, planner, plan, &result)
 	} else {
-		err = e.execClassic(planner, plan, &result)
+		err = e.execClassic(ctx, planner, plan, &result)
 	}
+	err = query.convertCanceledErr(err)
 	planner.phaseTimes[plannerEndExecStmt] = timeutil.Now()
 	e.recordStatementSummary(
 		planner, stmt, useDistSQL, automaticRetryCount, result, err,
 	)
+	planner.maybeLogStatement(ctx, stmt, result.numRows(), err)
 	if err != nil {
-		result.Close(session.Ctx())
+		result.Close(ctx)
 		return Result{}, err
 	}
 
 	if mockResults {
-		result.Close(session.Ctx())
+		result.Close(ctx)
 		return makeRes(stmt, planner, plan)
 	}
 	return result, nil
@@ -1641,23 +1835,33 @@ func (e *Executor) execStmt(
 // - parser.Rows -> an empty set of rows
 // - parser.RowsAffected -> zero rows affected
 //
+// The query is removed from the session's active queries once its
+// asynchronous execution completes.
+//
 // TODO(nvanbenschoten): We do not currently support parallelizing distributed SQL
 // queries, so this method can only be used with classical SQL.
-func (e *Executor) execStmtInParallel(stmt parser.Statement, planner *planner) (Result, error) {
+func (e *Executor) execStmtInParallel(
+	query activeQuery, stmt parser.Statement, planner *planner,
+) (Result, error) {
 	session := planner.session
-	ctx := session.Ctx()
+	ctx := query.ctx
 
 	plan, err := planner.makePlan(ctx, stmt)
 	if err != nil {
+		query.finish(session)
+		err = query.convertCanceledErr(err)
+		planner.maybeLogStatement(ctx, stmt, 0, err)
 		return Result{}, err
 	}
 
 	mockResult, err := makeRes(stmt, planner, plan)
 	if err != nil {
+		query.finish(session)
 		return Result{}, err
 	}
 
 	session.parallelizeQueue.Add(ctx, plan, func(plan planNode) error {
+		defer query.finish(session)
 		defer plan.Close(ctx)
 
 		result, err := makeRes(stmt, planner, plan)
@@ -1667,9 +1871,10 @@ func (e *Executor) execStmtInParallel(stmt parser.Statement, planner *planner) (
 		defer result.Close(ctx)
 
 		planner.phaseTimes[plannerStartExecStmt] = timeutil.Now()
-		err = e.execClassic(planner, plan, &result)
+		err = query.convertCanceledErr(e.execClassic(ctx, planner, plan, &result))
 		planner.phaseTimes[plannerEndExecStmt] = timeutil.Now()
 		e.recordStatementSummary(planner, stmt, false, 0, result, err)
+		planner.maybeLogStatement(ctx, stmt, result.numRows(), err)
 		return err
 	})
 	return mockResult, nil
@@ -1780,6 +1985,9 @@ func checkResultType(typ parser.Type) error {
 	case parser.TypeTimestamp:
 	case parser.TypeTimestampTZ:
 	case parser.TypeInterval:
+	case parser.TypeJSON:
+	case parser.TypeUUID:
+	case parser.TypeINet:
 	case parser.TypeStringArray:
 	case parser.TypeNameArray:
 	case parser.TypeIntArray:
@@ -1795,6 +2003,7 @@ func checkResultType(typ parser.Type) error {
 		switch {
 		case istype(parser.TypeCollatedString):
 		case istype(parser.TypeTuple):
+		case istype(parser.TypeAnyArray):
 		case istype(parser.TypePlaceholder):
 			return errors.Errorf("could not determine data type of %s", typ)
 		default:
@@ -84,10 +84,7 @@ func (e *Executor) recordStatementSummary(
 	runLatRaw := phaseTimes[plannerEndExecStmt].Sub(phaseTimes[plannerStartExecStmt])
 
 	// Collect the statistics.
-	numRows := result.RowsAffected
-	if result.Type == parser.Rows {
-		numRows = result.Rows.Len()
-	}
+	numRows := result.numRows()
 
 	runLat := runLatRaw.Seconds()
 
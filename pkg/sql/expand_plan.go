@@ -84,6 +84,9 @@ func doExpandPlan(
 			return plan, err
 		}
 
+	case *copyToNode:
+		n.source, err = doExpandPlan(ctx, p, params, n.source)
+
 	case *explainTraceNode:
 		n.plan, err = doExpandPlan(ctx, p, noParams, n.plan)
 		if err != nil {
@@ -125,12 +128,35 @@ func doExpandPlan(
 	case *filterNode:
 		n.source.plan, err = doExpandPlan(ctx, p, params, n.source.plan)
 
+	case *recursiveCTENode:
+		n.initial, err = doExpandPlan(ctx, p, noParams, n.initial)
+		if err != nil {
+			return plan, err
+		}
+		n.recursive, err = doExpandPlan(ctx, p, noParams, n.recursive)
+
 	case *joinNode:
+		// Reorder the joins before their operands are expanded, so that the
+		// filters of the operands can still be analyzed.
+		var newPlan planNode
+		newPlan, err = p.optimizeJoinOrder(ctx, n)
+		if err != nil {
+			return plan, err
+		}
+		if newPlan != plan {
+			return doExpandPlan(ctx, p, params, newPlan)
+		}
+		// The rows of the operands are estimated before the expansion of
+		// their filters into index spans.
+		est := p.estimateJoinRows(ctx, n)
 		n.left.plan, err = doExpandPlan(ctx, p, noParams, n.left.plan)
 		if err != nil {
 			return plan, err
 		}
 		n.right.plan, err = doExpandPlan(ctx, p, noParams, n.right.plan)
+		if err == nil {
+			n.chooseAlgorithm(est)
+		}
 
 	case *ordinalityNode:
 		// If there's a desired ordering on the ordinality column, drop it.
@@ -256,16 +282,25 @@ func doExpandPlan(
 
 	case *valuesNode:
 	case *alterTableNode:
+	case *cancelQueryNode:
+	case *cancelSessionNode:
+	case *controlJobNode:
 	case *copyNode:
 	case *createDatabaseNode:
 	case *createIndexNode:
+	case *createSequenceNode:
+	case *createStatsNode:
 	case *createUserNode:
 	case *dropDatabaseNode:
 	case *dropIndexNode:
+	case *dropRoleNode:
+	case *dropSequenceNode:
 	case *dropTableNode:
 	case *dropViewNode:
 	case *emptyNode:
 	case *hookFnNode:
+	case *grantRoleNode:
+	case *revokeRoleNode:
 	case *valueGenerator:
 	case *showRangesNode:
 	case *scatterNode:
@@ -427,6 +462,9 @@ func simplifyOrderings(plan planNode, usefulOrdering sqlbase.ColumnOrdering) pla
 	case *explainDistSQLNode:
 		n.plan = simplifyOrderings(n.plan, nil)
 
+	case *copyToNode:
+		n.source = simplifyOrderings(n.source, usefulOrdering)
+
 	case *explainTraceNode:
 		n.plan = simplifyOrderings(n.plan, nil)
 
@@ -443,12 +481,17 @@ func simplifyOrderings(plan planNode, usefulOrdering sqlbase.ColumnOrdering) pla
 		n.right = simplifyOrderings(n.right, nil)
 		n.left = simplifyOrderings(n.left, nil)
 
+	case *recursiveCTENode:
+		n.initial = simplifyOrderings(n.initial, nil)
+		n.recursive = simplifyOrderings(n.recursive, nil)
+
 	case *filterNode:
 		n.source.plan = simplifyOrderings(n.source.plan, usefulOrdering)
 
 	case *joinNode:
-		n.left.plan = simplifyOrderings(n.left.plan, nil)
-		n.right.plan = simplifyOrderings(n.right.plan, nil)
+		// A merge join relies on the orderings of its operands.
+		n.left.plan = simplifyOrderings(n.left.plan, n.leftMergeOrdering)
+		n.right.plan = simplifyOrderings(n.right.plan, n.rightMergeOrdering)
 
 	case *ordinalityNode:
 		// The ordinality node either passes through the source ordering, or if
@@ -540,16 +583,25 @@ func simplifyOrderings(plan planNode, usefulOrdering sqlbase.ColumnOrdering) pla
 
 	case *valuesNode:
 	case *alterTableNode:
+	case *cancelQueryNode:
+	case *cancelSessionNode:
+	case *controlJobNode:
 	case *copyNode:
 	case *createDatabaseNode:
 	case *createIndexNode:
+	case *createSequenceNode:
+	case *createStatsNode:
 	case *createUserNode:
 	case *dropDatabaseNode:
 	case *dropIndexNode:
+	case *dropRoleNode:
+	case *dropSequenceNode:
 	case *dropTableNode:
 	case *dropViewNode:
 	case *emptyNode:
 	case *hookFnNode:
+	case *grantRoleNode:
+	case *revokeRoleNode:
 	case *valueGenerator:
 	case *showRangesNode:
 	case *scatterNode:
@@ -272,6 +272,21 @@ func (p *planner) propagateFilters(
 			return plan, extraFilter, err
 		}
 
+	case *copyToNode:
+		if n.source, err = p.triggerFilterPropagation(ctx, n.source); err != nil {
+			return plan, extraFilter, err
+		}
+
+	case *recursiveCTENode:
+		// Filters can't propagate into a recursive CTE: the rows of an
+		// iteration are the input of the next one.
+		if n.initial, err = p.triggerFilterPropagation(ctx, n.initial); err != nil {
+			return plan, extraFilter, err
+		}
+		if n.recursive, err = p.triggerFilterPropagation(ctx, n.recursive); err != nil {
+			return plan, extraFilter, err
+		}
+
 	case *createTableNode:
 		if n.n.As() {
 			if n.sourcePlan, err = p.triggerFilterPropagation(ctx, n.sourcePlan); err != nil {
@@ -332,16 +347,25 @@ func (p *planner) propagateFilters(
 		}
 
 	case *alterTableNode:
+	case *cancelQueryNode:
+	case *cancelSessionNode:
+	case *controlJobNode:
 	case *copyNode:
 	case *createDatabaseNode:
 	case *createIndexNode:
+	case *createSequenceNode:
+	case *createStatsNode:
 	case *createUserNode:
 	case *delayedNode:
 	case *dropDatabaseNode:
 	case *dropIndexNode:
+	case *dropRoleNode:
+	case *dropSequenceNode:
 	case *dropTableNode:
 	case *dropViewNode:
 	case *hookFnNode:
+	case *grantRoleNode:
+	case *revokeRoleNode:
 	case *valueGenerator:
 	case *valuesNode:
 	case *showRangesNode:
@@ -26,7 +26,9 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/stats"
 	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/cockroach/pkg/util/json"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/pkg/errors"
 )
@@ -164,8 +166,26 @@ func (p *planner) selectIndex(
 		// number of disjunctive expressions we should limit how many indexes we
 		// use.
 
+		tableStats := p.getTableStats(ctx, &s.desc)
 		for _, c := range candidates {
-			c.analyzeExprs(exprs)
+			c.tableStats = tableStats
+			c.analyzeExprs(&p.evalCtx, exprs)
+		}
+	}
+
+	// Eliminate inverted indexes which aren't constrained: scanning all their
+	// entries would produce each row many times.
+	for i := 0; i < len(candidates); {
+		if candidates[i].index.Type == sqlbase.IndexDescriptor_INVERTED &&
+			len(candidates[i].constraints) == 0 {
+			if s.specifiedIndex != nil {
+				return nil, fmt.Errorf("inverted index \"%s\" can only be used with a "+
+					"containment (@>) constraint", s.specifiedIndex.Name)
+			}
+			candidates[i] = candidates[len(candidates)-1]
+			candidates = candidates[:len(candidates)-1]
+		} else {
+			i++
 		}
 	}
 
@@ -223,7 +243,11 @@ func (p *planner) selectIndex(
 		return &emptyNode{}, nil
 	}
 
-	s.filter = applyIndexConstraints(&p.evalCtx, s.filter, c.constraints)
+	if c.index.Type != sqlbase.IndexDescriptor_INVERTED {
+		// The constraints of an inverted index only select a superset of the
+		// matching rows, so the filter must be kept in its entirety.
+		s.filter = applyIndexConstraints(&p.evalCtx, s.filter, c.constraints)
+	}
 	if s.filter != nil {
 		// Constraint propagation may have produced new constant sub-expressions.
 		// Propagate them and check if s.filter can be applied prematurely.
@@ -342,6 +366,9 @@ type indexInfo struct {
 	covering    bool // Does the index cover the required IndexedVars?
 	reverse     bool
 	exactPrefix int
+	// tableStats are the statistics of the table, used to estimate the
+	// fraction of the rows of the table scanned through the index.
+	tableStats []*TableStatistic
 }
 
 func (v *indexInfo) init(s *scanNode) {
@@ -365,7 +392,7 @@ func (v *indexInfo) init(s *scanNode) {
 
 // analyzeExprs examines the range map to determine the cost of using the
 // index.
-func (v *indexInfo) analyzeExprs(exprs []parser.TypedExprs) {
+func (v *indexInfo) analyzeExprs(evalCtx *parser.EvalContext, exprs []parser.TypedExprs) {
 	if err := v.makeOrConstraints(exprs); err != nil {
 		panic(err)
 	}
@@ -377,6 +404,11 @@ func (v *indexInfo) analyzeExprs(exprs []parser.TypedExprs) {
 		// The index isn't being restricted at all, bump the cost significantly to
 		// make any index which does restrict the keys more desirable.
 		v.cost *= 1000
+	} else if selectivity, ok := v.estimateSelectivity(evalCtx); ok {
+		// We have statistics on the columns of the index: scale the cost by the
+		// estimated fraction of the rows of the table that are scanned, in a way
+		// that is consistent with the cost of unrestricted indexes above.
+		v.cost *= 1 + 999*selectivity
 	} else {
 		// When we have multiple indexConstraints, each one is for a top-level
 		// disjunction (OR); together they are no more restrictive than any one of
@@ -400,6 +432,121 @@ func (v *indexInfo) analyzeExprs(exprs []parser.TypedExprs) {
 	}
 }
 
+// estimateSelectivity uses the statistics of the table to estimate the
+// fraction of the rows of the table which satisfy the constraints of the index.
+// The second return value is false if there are no statistics for the first
+// column of the index, in which case the caller should fall back on heuristics.
+//
+// Each top-level disjunction contributes the product of the selectivities of
+// the constraints on the columns for which we have statistics (which assumes
+// the columns are independent); the selectivities of the disjunctions are
+// added up.
+func (v *indexInfo) estimateSelectivity(evalCtx *parser.EvalContext) (float64, bool) {
+	if len(v.index.ColumnIDs) == 0 ||
+		findColumnStat(v.tableStats, v.index.ColumnIDs[0]) == nil {
+		return 0, false
+	}
+	var alloc sqlbase.DatumAlloc
+	var selectivity float64
+	for _, cset := range v.constraints {
+		s := 1.0
+		colIdx := 0
+		for _, c := range cset {
+			if c.tupleMap == nil {
+				if stat := findColumnStat(v.tableStats, v.index.ColumnIDs[colIdx]); stat != nil {
+					s *= v.constraintSelectivity(evalCtx, &alloc, c, stat)
+				}
+			}
+			colIdx += c.numColumns()
+		}
+		selectivity += s
+	}
+	if selectivity > 1 {
+		selectivity = 1
+	}
+	return selectivity, true
+}
+
+// constraintSelectivity estimates the fraction of the rows of the table which
+// satisfy a constraint on a single column, given the statistic on that column.
+func (v *indexInfo) constraintSelectivity(
+	evalCtx *parser.EvalContext, alloc *sqlbase.DatumAlloc, c indexConstraint, stat *TableStatistic,
+) float64 {
+	if stat.RowCount == 0 {
+		return 0
+	}
+	rowCount := float64(stat.RowCount)
+	nonNullRows := float64(stat.RowCount - stat.NullCount)
+
+	var hist *stats.Histogram
+	if stat.Histogram != nil {
+		col, err := v.desc.FindColumnByID(stat.ColumnIDs[0])
+		if err == nil {
+			hist, err = stats.DecodeHistogram(alloc, col.Type.ToDatumType(), stat.Histogram)
+		}
+		if err != nil {
+			// The histogram is not usable (e.g. the type of the column changed);
+			// fall back on the counts.
+			hist = nil
+		}
+	}
+	equalRows := func(d parser.Datum) float64 {
+		if d == parser.DNull {
+			return 0
+		}
+		if hist != nil {
+			if rows, ok := hist.EqualRows(evalCtx, d); ok {
+				return rows
+			}
+		}
+		if stat.DistinctCount == 0 {
+			return 0
+		}
+		return nonNullRows / float64(stat.DistinctCount)
+	}
+
+	if c.start != nil && c.start == c.end {
+		switch c.start.Operator {
+		case parser.EQ:
+			return equalRows(c.start.Right.(parser.Datum)) / rowCount
+		case parser.In:
+			var rows float64
+			for _, d := range c.start.Right.(*parser.DTuple).D {
+				rows += equalRows(d)
+			}
+			return rows / rowCount
+		}
+	}
+	if c.end != nil && c.end.Operator == parser.Is && c.end.Right == parser.DNull {
+		return float64(stat.NullCount) / rowCount
+	}
+
+	// We have a range, possibly unbounded on either side. The start and end
+	// of the constraint are swapped for descending columns, so we look at the
+	// operators to find the lower and upper bounds.
+	var lower, upper parser.Datum
+	for _, e := range []*parser.ComparisonExpr{c.start, c.end} {
+		if e == nil {
+			continue
+		}
+		switch e.Operator {
+		case parser.GE, parser.GT:
+			lower = e.Right.(parser.Datum)
+		case parser.LE, parser.LT:
+			upper = e.Right.(parser.Datum)
+		}
+	}
+	if lower == nil && upper == nil {
+		// The constraint is IS NOT NULL.
+		return nonNullRows / rowCount
+	}
+	if hist != nil {
+		return hist.RangeRows(evalCtx, lower, upper) / rowCount
+	}
+	// Without a histogram, assume a range contains a third of the values.
+	return nonNullRows / 3 / rowCount
+}
+
 // analyzeOrdering analyzes the ordering provided by the index and determines
 // if it matches the ordering requested by the query. Non-matching orderings
 // increase the cost of using the index.
@@ -466,6 +613,12 @@ func getColVarIdx(expr parser.Expr) (ok bool, colIdx int) {
 // analyzed expressions. Each element of constraints corresponds to one
 // of the top-level disjunctions and is generated using makeIndexConstraint.
 func (v *indexInfo) makeOrConstraints(orExprs []parser.TypedExprs) error {
+	if v.index.Type == sqlbase.IndexDescriptor_INVERTED && len(orExprs) > 1 {
+		// The spans of the different disjunctions of an inverted index would
+		// overlap without the overlap being visible in their keys, and rows
+		// would be returned multiple times.
+		return nil
+	}
 	constraints := make(orIndexConstraints, len(orExprs))
 	for i, e := range orExprs {
 		var err error
@@ -526,6 +679,10 @@ func (v *indexInfo) makeOrConstraints(orExprs []parser.TypedExprs) error {
 // simplify to "a < 1 OR a >= 2" which is also the same as "a != 1", but not so
 // obvious based on comparisons of the constants.
 func (v *indexInfo) makeIndexConstraints(andExprs parser.TypedExprs) (indexConstraints, error) {
+	if v.index.Type == sqlbase.IndexDescriptor_INVERTED {
+		return v.makeInvertedIndexConstraints(andExprs), nil
+	}
+
 	var constraints indexConstraints
 
 	trueStartDone := false
@@ -787,6 +944,33 @@ func (v *indexInfo) makeIndexConstraints(andExprs parser.TypedExprs) (indexConst
 	return constraints, nil
 }
 
+// makeInvertedIndexConstraints generates the constraint of an inverted index
+// for a set of conjunctions. Inverted indexes can only be constrained by a
+// containment expression "a @> x" for which there is an inverted index key
+// that all the documents containing x have (see
+// json.EncodeContainingInvertedIndexKey). The first such expression is used.
+func (v *indexInfo) makeInvertedIndexConstraints(andExprs parser.TypedExprs) indexConstraints {
+	colID := v.index.ColumnIDs[0]
+	for _, e := range andExprs {
+		c, ok := e.(*parser.ComparisonExpr)
+		if !ok || c.Operator != parser.Contains {
+			continue
+		}
+		if ok, colIdx := getColVarIdx(c.Left); !ok || v.desc.Columns[colIdx].ID != colID {
+			continue
+		}
+		d, ok := c.Right.(*parser.DJSON)
+		if !ok {
+			continue
+		}
+		if _, ok := json.EncodeContainingInvertedIndexKey(nil, d.JSON); !ok {
+			continue
+		}
+		return indexConstraints{{start: c, end: c}}
+	}
+	return nil
+}
+
 // isCoveringIndex returns true if all of the columns needed from the scanNode are contained within
 // the index. This allows a scan of only the index to be performed without requiring subsequent
 // lookup of the full row.
@@ -795,6 +979,11 @@ func (v *indexInfo) isCoveringIndex(scan *scanNode) bool {
 		// The primary key index always covers all of the columns.
 		return true
 	}
+	if v.index.Type == sqlbase.IndexDescriptor_INVERTED {
+		// The rows found in an inverted index always need to be checked
+		// against the filter using the values from the primary index.
+		return false
+	}
 
 	for i, needed := range scan.valNeededForCol {
 		if needed {
@@ -1028,6 +1217,9 @@ func makeSpans(
 	if len(constraints) == 0 {
 		return roachpb.Spans{tableDesc.IndexSpan(index.ID)}, nil
 	}
+	if index.Type == sqlbase.IndexDescriptor_INVERTED {
+		return makeInvertedIndexSpans(constraints, tableDesc, index)
+	}
 	var allLogicalSpans []logicalSpan
 	for _, c := range constraints {
 		s, err := makeLogicalSpansForIndexConstraints(c, tableDesc, index)
@@ -1043,6 +1235,30 @@ func makeSpans(
 	return mergeAndSortSpans(allSpans), nil
 }
 
+// makeInvertedIndexSpans constructs the span of an inverted index given its
+// constraint (see makeInvertedIndexConstraints): the span contains all the
+// entries with the inverted index key selected by the constraint.
+func makeInvertedIndexSpans(
+	constraints orIndexConstraints,
+	tableDesc *sqlbase.TableDescriptor,
+	index *sqlbase.IndexDescriptor,
+) (roachpb.Spans, error) {
+	if len(constraints) != 1 || len(constraints[0]) != 1 {
+		return nil, errors.Errorf("invalid inverted index constraints: %s", constraints)
+	}
+	c := constraints[0][0].start
+	d, ok := c.Right.(*parser.DJSON)
+	if !ok {
+		return nil, errors.Errorf("invalid inverted index constraint: %s", c)
+	}
+	key, ok := json.EncodeContainingInvertedIndexKey(
+		sqlbase.MakeIndexKeyPrefix(tableDesc, index.ID), d.JSON)
+	if !ok {
+		return nil, errors.Errorf("invalid inverted index constraint: %s", c)
+	}
+	return roachpb.Spans{{Key: key, EndKey: roachpb.Key(key).PrefixEnd()}}, nil
+}
+
 // logicalSpan is a higher-level representation of a span that uses Datums
 // instead of bytes. TODO(radu): In the future, I think we probably want to
 // remove the expressions in indexConstraints as well and directly put in
@@ -179,7 +179,7 @@ func makeConstraints(
 		index:    index,
 		covering: true,
 	}
-	c.analyzeExprs(exprs)
+	c.analyzeExprs(evalCtx, exprs)
 	if equiv && len(exprs) == 1 {
 		expr = joinAndExprs(exprs[0])
 	}
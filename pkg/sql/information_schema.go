@@ -18,6 +18,7 @@ package sql
 
 import (
 	"sort"
+	"strconv"
 
 	"github.com/pkg/errors"
 	"golang.org/x/net/context"
@@ -40,6 +41,7 @@ var informationSchema = virtualSchema{
 		informationSchemaKeyColumnUsageTable,
 		informationSchemaSchemataTable,
 		informationSchemaSchemataTablePrivileges,
+		informationSchemaSequencesTable,
 		informationSchemaStatisticsTable,
 		informationSchemaTableConstraintTable,
 		informationSchemaTablePrivileges,
@@ -117,6 +119,10 @@ CREATE TABLE information_schema.columns (
 `,
 	populate: func(ctx context.Context, p *planner, addRow func(...parser.Datum) error) error {
 		return forEachTableDesc(ctx, p, func(db *sqlbase.DatabaseDescriptor, table *sqlbase.TableDescriptor) error {
+			if table.IsSequence() {
+				// As in postgres, the value column of a sequence is not listed.
+				return nil
+			}
 			// Table descriptors already holds columns in-order.
 			visible := 0
 			return forEachColumnInTable(table, func(column *sqlbase.ColumnDescriptor) error {
@@ -272,6 +278,48 @@ CREATE TABLE information_schema.schema_privileges (
 	},
 }
 
+var informationSchemaSequencesTable = virtualSchemaTable{
+	schema: `
+CREATE TABLE information_schema.sequences (
+	SEQUENCE_CATALOG STRING NOT NULL DEFAULT '',
+	SEQUENCE_SCHEMA STRING NOT NULL DEFAULT '',
+	SEQUENCE_NAME STRING NOT NULL DEFAULT '',
+	DATA_TYPE STRING NOT NULL DEFAULT '',
+	NUMERIC_PRECISION INT NOT NULL DEFAULT 0,
+	NUMERIC_PRECISION_RADIX INT NOT NULL DEFAULT 0,
+	NUMERIC_SCALE INT NOT NULL DEFAULT 0,
+	START_VALUE STRING NOT NULL DEFAULT '',
+	MINIMUM_VALUE STRING NOT NULL DEFAULT '',
+	MAXIMUM_VALUE STRING NOT NULL DEFAULT '',
+	INCREMENT STRING NOT NULL DEFAULT '',
+	CYCLE_OPTION STRING NOT NULL DEFAULT ''
+);
+`,
+	populate: func(ctx context.Context, p *planner, addRow func(...parser.Datum) error) error {
+		return forEachTableDesc(ctx, p, func(db *sqlbase.DatabaseDescriptor, table *sqlbase.TableDescriptor) error {
+			if !table.IsSequence() {
+				return nil
+			}
+			// As in postgres, the values are reported as strings.
+			opts := table.SequenceOpts
+			return addRow(
+				defString,                     // sequence_catalog
+				parser.NewDString(db.Name),    // sequence_schema
+				parser.NewDString(table.Name), // sequence_name
+				parser.NewDString("INT"),      // data_type
+				parser.NewDInt(64),            // numeric_precision
+				parser.NewDInt(2),             // numeric_precision_radix
+				parser.NewDInt(0),             // numeric_scale
+				parser.NewDString(strconv.FormatInt(opts.Start, 10)),     // start_value
+				parser.NewDString(strconv.FormatInt(opts.MinValue, 10)),  // minimum_value
+				parser.NewDString(strconv.FormatInt(opts.MaxValue, 10)),  // maximum_value
+				parser.NewDString(strconv.FormatInt(opts.Increment, 10)), // increment
+				noString, // cycle_option
+			)
+		})
+	},
+}
+
 var (
 	indexDirectionNA   = parser.NewDString("N/A")
 	indexDirectionAsc  = parser.NewDString(sqlbase.IndexDescriptor_ASC.String())
@@ -482,6 +530,7 @@ var (
 	tableTypeSystemView = parser.NewDString("SYSTEM VIEW")
 	tableTypeBaseTable  = parser.NewDString("BASE TABLE")
 	tableTypeView       = parser.NewDString("VIEW")
+	tableTypeSequence   = parser.NewDString("SEQUENCE")
 )
 
 var informationSchemaTablesTable = virtualSchemaTable{
@@ -500,6 +549,8 @@ CREATE TABLE information_schema.tables (
 				tableType = tableTypeSystemView
 			} else if table.IsView() {
 				tableType = tableTypeView
+			} else if table.IsSequence() {
+				tableType = tableTypeSequence
 			}
 			return addRow(
 				defString,                     // table_catalog
@@ -785,8 +836,10 @@ func forEachColumnInIndex(
 	return nil
 }
 
-func forEachUser(ctx context.Context, p *planner, fn func(username string) error) error {
-	query := `SELECT username FROM system.users`
+func forEachUser(
+	ctx context.Context, p *planner, fn func(username string, isRole bool) error,
+) error {
+	query := `SELECT username, "isRole" FROM system.users`
 	plan, err := p.query(ctx, query)
 	if err != nil {
 		return nil
@@ -798,7 +851,7 @@ func forEachUser(ctx context.Context, p *planner, fn func(username string) error
 
 	// TODO(cuongdo/asubiotto): Get rid of root user special-casing if/when a row
 	// for "root" exists in system.user.
-	if err := fn(security.RootUser); err != nil {
+	if err := fn(security.RootUser, false); err != nil {
 		return err
 	}
 
@@ -812,7 +865,40 @@ func forEachUser(ctx context.Context, p *planner, fn func(username string) error
 		}
 		row := plan.Values()
 		username := parser.MustBeDString(row[0])
-		if err := fn(string(username)); err != nil {
+		isRole := *row[1].(*parser.DBool)
+		if err := fn(string(username), bool(isRole)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func forEachRoleMembership(
+	ctx context.Context, p *planner, fn func(role, member string, isAdmin bool) error,
+) error {
+	query := `SELECT "role", "member", "isAdmin" FROM system.role_members`
+	plan, err := p.query(ctx, query)
+	if err != nil {
+		return err
+	}
+	defer plan.Close(ctx)
+	if err := p.startPlan(ctx, plan); err != nil {
+		return err
+	}
+
+	for {
+		next, err := plan.Next(ctx)
+		if err != nil {
+			return err
+		}
+		if !next {
+			break
+		}
+		row := plan.Values()
+		role := parser.MustBeDString(row[0])
+		member := parser.MustBeDString(row[1])
+		isAdmin := *row[2].(*parser.DBool)
+		if err := fn(string(role), string(member), bool(isAdmin)); err != nil {
 			return err
 		}
 	}
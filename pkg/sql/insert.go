@@ -55,6 +55,12 @@ type insertNode struct {
 func (p *planner) Insert(
 	ctx context.Context, n *parser.Insert, desiredTypes []parser.Type,
 ) (planNode, error) {
+	popWith, err := p.initWith(ctx, n.With)
+	if err != nil {
+		return nil, err
+	}
+	defer popWith(ctx)
+
 	tn, err := p.getAliasedTableName(n.Table)
 	if err != nil {
 		return nil, err
@@ -66,7 +72,7 @@ func (p *planner) Insert(
 	}
 	if n.OnConflict != nil {
 		if !n.OnConflict.DoNothing {
-			if err := p.CheckPrivilege(en.tableDesc, privilege.UPDATE); err != nil {
+			if err := p.CheckPrivilege(ctx, en.tableDesc, privilege.UPDATE); err != nil {
 				return nil, err
 			}
 		}
@@ -132,6 +138,7 @@ func (p *planner) Insert(
 	if err := p.fillFKTableMap(ctx, fkTables); err != nil {
 		return nil, err
 	}
+	p.auditFKTables(en.tableDesc, fkTables, sqlbase.CheckInserts)
 	ri, err := sqlbase.MakeRowInserter(p.txn, en.tableDesc, fkTables, cols, sqlbase.CheckFKs)
 	if err != nil {
 		return nil, err
@@ -190,10 +197,12 @@ func (p *planner) Insert(
 			if err := p.fillFKTableMap(ctx, fkTables); err != nil {
 				return nil, err
 			}
+			p.auditFKTables(en.tableDesc, fkTables, sqlbase.CheckUpdates)
 			tw = &tableUpserter{
 				ri:            ri,
 				autoCommit:    p.autoCommit,
 				fkTables:      fkTables,
+				evalCtx:       &p.evalCtx,
 				updateCols:    updateCols,
 				conflictIndex: *conflictIndex,
 				evaler:        helper,
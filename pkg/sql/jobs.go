@@ -17,6 +17,7 @@
 package sql
 
 import (
+	"fmt"
 	"time"
 
 	"golang.org/x/net/context"
@@ -42,6 +43,13 @@ type JobLogger struct {
 	ex    InternalExecutor
 	jobID *int64
 	Job   JobRecord
+
+	// registry is the JobRegistry that created the JobLogger, if any. Only
+	// jobs created through a registry are leased and can be adopted.
+	registry *JobRegistry
+	// lease is the lease on the job held by this JobLogger. Updates to a job
+	// whose lease is now held by someone else fail.
+	lease *JobLease
 }
 
 // JobRecord stores the job fields that are not automatically managed by
@@ -66,9 +74,17 @@ const (
 	JobStatusFailed JobStatus = "failed"
 	// JobStatusSucceeded is for jobs that have successfully completed.
 	JobStatusSucceeded JobStatus = "succeeded"
+	// JobStatusPaused is for jobs that were paused by the user. Paused jobs stop
+	// making progress until they are resumed.
+	JobStatusPaused JobStatus = "paused"
+	// JobStatusCanceled is for jobs that were canceled by the user. Canceled
+	// jobs cannot be resumed.
+	JobStatusCanceled JobStatus = "canceled"
 )
 
-// NewJobLogger creates a new JobLogger.
+// NewJobLogger creates a new JobLogger. Jobs tracked by such a JobLogger are
+// not leased, so they are never adopted by another node; use
+// JobRegistry.NewJobLogger for jobs that should be.
 func NewJobLogger(db *client.DB, leaseMgr *LeaseManager, job JobRecord) JobLogger {
 	return JobLogger{
 		db:  db,
@@ -92,23 +108,35 @@ func (jl *JobLogger) Created(ctx context.Context) error {
 		Username:      jl.Job.Username,
 		DescriptorIDs: jl.Job.DescriptorIDs,
 	}
-	switch d := jl.Job.Details.(type) {
-	case BackupJobDetails:
-		payload.Details = &JobPayload_Backup{Backup: &d}
-	case RestoreJobDetails:
-		payload.Details = &JobPayload_Restore{Restore: &d}
-	default:
-		return errors.Errorf("JobLogger: unsupported job details type %T", d)
+	if err := payload.setDetails(jl.Job.Details); err != nil {
+		return err
 	}
-	return jl.insertJobRecord(ctx, payload)
+	if jl.registry != nil {
+		lease, err := jl.registry.newLease()
+		if err != nil {
+			return err
+		}
+		payload.Lease, jl.lease = lease, lease
+	}
+	if err := jl.insertJobRecord(ctx, payload); err != nil {
+		return err
+	}
+	if jl.registry != nil {
+		jl.registry.register(*jl.jobID)
+	}
+	return nil
 }
 
 // Started marks the tracked job as started.
 func (jl *JobLogger) Started(ctx context.Context) error {
-	return jl.updateJobRecord(ctx, JobStatusRunning, func(payload *JobPayload) (bool, error) {
+	return jl.updateJobRecord(ctx, func(status *JobStatus, payload *JobPayload) (bool, error) {
 		if payload.StartedMicros != 0 {
-			return false, errors.Errorf("JobLogger: job %d already started", jl.jobID)
+			return false, errors.Errorf("JobLogger: job %d already started", *jl.jobID)
+		}
+		if *status != JobStatusPending {
+			return false, &InvalidJobStatusError{id: *jl.jobID, status: *status, op: "start"}
 		}
+		*status = JobStatusRunning
 		payload.StartedMicros = jobTimestamp(timeutil.Now())
 		return true, nil
 	})
@@ -118,41 +146,69 @@ func (jl *JobLogger) Started(ctx context.Context) error {
 // fractionCompleted that is less than the currently-recorded fractionCompleted
 // will be silently ignored.
 func (jl *JobLogger) Progressed(ctx context.Context, fractionCompleted float32) error {
+	return jl.ProgressedWithDetails(ctx, fractionCompleted, nil)
+}
+
+// ProgressedWithDetails is like Progressed, but additionally replaces the
+// recorded details of the job with details, unless details is nil. Jobs use
+// this to checkpoint their progress, so that they can be resumed later.
+//
+// Progress can be recorded for paused jobs, whose in-flight work is allowed to
+// finish, but it is an error to record progress for a canceled job.
+func (jl *JobLogger) ProgressedWithDetails(
+	ctx context.Context, fractionCompleted float32, details interface{},
+) error {
 	if fractionCompleted < 0.0 || fractionCompleted > 1.0 {
 		return errors.Errorf(
 			"JobLogger: fractionCompleted %f is outside allowable range [0.0, 1.0] (job %d)",
 			fractionCompleted, jl.jobID,
 		)
 	}
-	return jl.updateJobRecord(ctx, JobStatusRunning, func(payload *JobPayload) (bool, error) {
+	return jl.updateJobRecord(ctx, func(status *JobStatus, payload *JobPayload) (bool, error) {
+		if *status == JobStatusCanceled {
+			return false, jl.canceledError()
+		}
 		if payload.StartedMicros == 0 {
-			return false, errors.Errorf("JobLogger: job %d not started", jl.jobID)
+			return false, errors.Errorf("JobLogger: job %d not started", *jl.jobID)
 		}
 		if payload.FinishedMicros != 0 {
-			return false, errors.Errorf("JobLogger: job %d already finished", jl.jobID)
+			return false, errors.Errorf("JobLogger: job %d already finished", *jl.jobID)
 		}
-		if fractionCompleted <= payload.FractionCompleted {
-			return false, nil
+		doUpdate := false
+		if details != nil {
+			if err := payload.setDetails(details); err != nil {
+				return false, err
+			}
+			doUpdate = true
 		}
-		payload.FractionCompleted = fractionCompleted
-		return true, nil
+		if fractionCompleted > payload.FractionCompleted {
+			payload.FractionCompleted = fractionCompleted
+			doUpdate = true
+		}
+		return doUpdate, nil
 	})
 }
 
 // Failed marks the tracked job as having failed with the given error. Any
 // errors encountered while updating the jobs table are logged but not returned,
 // under the assumption that the the caller is already handling a more important
-// error and doesn't care about this one.
+// error and doesn't care about this one. A canceled job is left as is, since
+// its failure is the expected result of the cancellation.
 func (jl *JobLogger) Failed(ctx context.Context, err error) {
 	// To simplify cleanup routines, it is not an error to call Failed on a job
 	// that was never Created.
 	if jl.jobID == nil {
 		return
 	}
-	internalErr := jl.updateJobRecord(ctx, JobStatusFailed, func(payload *JobPayload) (bool, error) {
+	defer jl.unregister()
+	internalErr := jl.updateJobRecord(ctx, func(status *JobStatus, payload *JobPayload) (bool, error) {
+		if *status == JobStatusCanceled {
+			return false, nil
+		}
 		if payload.FinishedMicros != 0 {
-			return false, errors.Errorf("JobLogger: job %d already finished", jl.jobID)
+			return false, errors.Errorf("JobLogger: job %d already finished", *jl.jobID)
 		}
+		*status = JobStatusFailed
 		payload.Error = err.Error()
 		payload.FinishedMicros = jobTimestamp(timeutil.Now())
 		return true, nil
@@ -166,16 +222,81 @@ func (jl *JobLogger) Failed(ctx context.Context, err error) {
 // Succeeded marks the tracked job as having succeeded and sets its fraction
 // completed to 1.0.
 func (jl *JobLogger) Succeeded(ctx context.Context) error {
-	return jl.updateJobRecord(ctx, JobStatusSucceeded, func(payload *JobPayload) (bool, error) {
+	defer jl.unregister()
+	return jl.updateJobRecord(ctx, func(status *JobStatus, payload *JobPayload) (bool, error) {
+		if *status == JobStatusCanceled {
+			return false, jl.canceledError()
+		}
 		if payload.FinishedMicros != 0 {
-			return false, errors.Errorf("JobLogger: job %d already finished", jl.jobID)
+			return false, errors.Errorf("JobLogger: job %d already finished", *jl.jobID)
 		}
+		*status = JobStatusSucceeded
 		payload.FinishedMicros = jobTimestamp(timeutil.Now())
 		payload.FractionCompleted = 1.0
 		return true, nil
 	})
 }
 
+// jobPausedPollInterval is how often a paused job checks whether it has been
+// resumed or canceled.
+var jobPausedPollInterval = time.Second
+
+// WaitWhilePaused blocks for as long as the tracked job is paused. It returns
+// an error if the job has been canceled, in which case the caller is expected
+// to abandon its work and report the error. Long-running jobs are expected to
+// call WaitWhilePaused periodically, at points where it is safe to stop work.
+func (jl *JobLogger) WaitWhilePaused(ctx context.Context) error {
+	if jl.jobID == nil {
+		return errors.New("JobLogger cannot check job status: job not created")
+	}
+	for {
+		var status JobStatus
+		if err := jl.db.Txn(ctx, func(ctx context.Context, txn *client.Txn) error {
+			var payload *JobPayload
+			var err error
+			status, payload, err = loadJob(ctx, jl.ex, txn, *jl.jobID)
+			if err != nil {
+				return err
+			}
+			return jl.checkLease(payload)
+		}); err != nil {
+			return err
+		}
+		switch status {
+		case JobStatusCanceled:
+			return jl.canceledError()
+		case JobStatusPaused:
+			log.VEventf(ctx, 2, "job %d is paused", *jl.jobID)
+		default:
+			return nil
+		}
+		select {
+		case <-time.After(jobPausedPollInterval):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (jl *JobLogger) canceledError() error {
+	return errors.Errorf("job %d was canceled", *jl.jobID)
+}
+
+// checkLease returns an error if the job, as read from the jobs table, is no
+// longer leased to this JobLogger, i.e. it has been adopted by another node.
+func (jl *JobLogger) checkLease(payload *JobPayload) error {
+	if jl.lease == nil || jl.lease.equal(payload.Lease) {
+		return nil
+	}
+	return errors.Errorf("job %d: lease lost to node %d", *jl.jobID, payload.Lease.NodeID)
+}
+
+func (jl *JobLogger) unregister() {
+	if jl.registry != nil && jl.jobID != nil {
+		jl.registry.unregister(*jl.jobID)
+	}
+}
+
 func (jl *JobLogger) insertJobRecord(ctx context.Context, payload *JobPayload) error {
 	if jl.jobID != nil {
 		return errors.Errorf("JobLogger cannot create job: job %d already created", jl.jobID)
@@ -201,54 +322,144 @@ func (jl *JobLogger) insertJobRecord(ctx context.Context, payload *JobPayload) e
 }
 
 func (jl *JobLogger) updateJobRecord(
-	ctx context.Context, newStatus JobStatus, updateFn func(*JobPayload) (doUpdate bool, err error),
+	ctx context.Context, updateFn func(*JobStatus, *JobPayload) (doUpdate bool, err error),
 ) error {
 	if jl.jobID == nil {
 		return errors.New("JobLogger cannot update job: job not created")
 	}
 
 	return jl.db.Txn(ctx, func(ctx context.Context, txn *client.Txn) error {
-		const selectStmt = "SELECT payload FROM system.jobs WHERE id = $1"
-		row, err := jl.ex.QueryRowInTransaction(ctx, "log-job", txn, selectStmt, *jl.jobID)
-		if err != nil {
-			return err
-		}
+		return updateJob(ctx, jl.ex, txn, *jl.jobID, func(status *JobStatus, payload *JobPayload) (bool, error) {
+			if err := jl.checkLease(payload); err != nil {
+				return false, err
+			}
+			return updateFn(status, payload)
+		})
+	})
+}
 
-		payload, err := unmarshalJobPayload(row[0])
-		if err != nil {
-			return err
-		}
-		doUpdate, err := updateFn(payload)
-		if err != nil {
-			return err
-		}
-		if !doUpdate {
-			return nil
-		}
-		payload.ModifiedMicros = jobTimestamp(timeutil.Now())
-		payloadBytes, err := protoutil.Marshal(payload)
-		if err != nil {
-			return err
-		}
+// loadJob reads the status and payload of the job with the given ID from the
+// system.jobs table.
+func loadJob(
+	ctx context.Context, ex InternalExecutor, txn *client.Txn, jobID int64,
+) (JobStatus, *JobPayload, error) {
+	const selectStmt = "SELECT status, payload FROM system.jobs WHERE id = $1"
+	row, err := ex.QueryRowInTransaction(ctx, "log-job", txn, selectStmt, jobID)
+	if err != nil {
+		return "", nil, err
+	}
+	if row == nil {
+		return "", nil, errors.Errorf("job with ID %d does not exist", jobID)
+	}
 
-		const updateStmt = "UPDATE system.jobs SET status = $1, payload = $2 WHERE id = $3"
-		n, err := jl.ex.ExecuteStatementInTransaction(
-			ctx, "job-update", txn, updateStmt, newStatus, payloadBytes, *jl.jobID)
-		if err != nil {
-			return err
+	status, ok := row[0].(*parser.DString)
+	if !ok {
+		return "", nil, errors.Errorf("JobLogger: expected string status, but got %T", row[0])
+	}
+	payload, err := unmarshalJobPayload(row[1])
+	if err != nil {
+		return "", nil, err
+	}
+	return JobStatus(*status), payload, nil
+}
+
+// updateJob reads the job with the given ID, lets updateFn modify its status
+// and payload, and writes the job back if updateFn requests it.
+func updateJob(
+	ctx context.Context,
+	ex InternalExecutor,
+	txn *client.Txn,
+	jobID int64,
+	updateFn func(*JobStatus, *JobPayload) (doUpdate bool, err error),
+) error {
+	status, payload, err := loadJob(ctx, ex, txn, jobID)
+	if err != nil {
+		return err
+	}
+	doUpdate, err := updateFn(&status, payload)
+	if err != nil {
+		return err
+	}
+	if !doUpdate {
+		return nil
+	}
+	payload.ModifiedMicros = jobTimestamp(timeutil.Now())
+	payloadBytes, err := protoutil.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	const updateStmt = "UPDATE system.jobs SET status = $1, payload = $2 WHERE id = $3"
+	n, err := ex.ExecuteStatementInTransaction(
+		ctx, "job-update", txn, updateStmt, status, payloadBytes, jobID)
+	if err != nil {
+		return err
+	}
+	if n != 1 {
+		return errors.Errorf("JobLogger: expected exactly one row affected, but %d rows affected by job update", n)
+	}
+
+	return nil
+}
+
+// InvalidJobStatusError is the error returned when an operation is not valid
+// for a job in its current status, e.g. resuming a job that is not paused.
+type InvalidJobStatusError struct {
+	id     int64
+	status JobStatus
+	op     string
+}
+
+func (e *InvalidJobStatusError) Error() string {
+	return fmt.Sprintf("cannot %s %s job (id %d)", e.op, e.status, e.id)
+}
+
+// pauseJob marks the running job with the given ID as paused. The job notices
+// this the next time it checks its status and stops making progress until it
+// is resumed.
+func pauseJob(ctx context.Context, ex InternalExecutor, txn *client.Txn, jobID int64) error {
+	return updateJob(ctx, ex, txn, jobID, func(status *JobStatus, _ *JobPayload) (bool, error) {
+		if *status != JobStatusRunning {
+			return false, &InvalidJobStatusError{id: jobID, status: *status, op: "pause"}
 		}
-		if n != 1 {
-			return errors.Errorf("JobLogger: expected exactly one row affected, but %d rows affected by job update", n)
+		*status = JobStatusPaused
+		return true, nil
+	})
+}
+
+// resumeJob marks the paused job with the given ID as running again.
+func resumeJob(ctx context.Context, ex InternalExecutor, txn *client.Txn, jobID int64) error {
+	return updateJob(ctx, ex, txn, jobID, func(status *JobStatus, _ *JobPayload) (bool, error) {
+		if *status != JobStatusPaused {
+			return false, &InvalidJobStatusError{id: jobID, status: *status, op: "resume"}
 		}
+		*status = JobStatusRunning
+		return true, nil
+	})
+}
 
-		return nil
+// cancelJob marks the unfinished job with the given ID as canceled. The job
+// notices this the next time it checks its status and abandons its work.
+// Canceled jobs cannot be resumed.
+func cancelJob(ctx context.Context, ex InternalExecutor, txn *client.Txn, jobID int64) error {
+	return updateJob(ctx, ex, txn, jobID, func(status *JobStatus, payload *JobPayload) (bool, error) {
+		switch *status {
+		case JobStatusPending, JobStatusRunning, JobStatusPaused:
+		default:
+			return false, &InvalidJobStatusError{id: jobID, status: *status, op: "cancel"}
+		}
+		*status = JobStatusCanceled
+		payload.FinishedMicros = jobTimestamp(timeutil.Now())
+		return true, nil
 	})
 }
 
 // Job types are named for the SQL query that creates them.
 const (
-	JobTypeBackup  string = "BACKUP"
-	JobTypeRestore string = "RESTORE"
+	JobTypeBackup      string = "BACKUP"
+	JobTypeRestore     string = "RESTORE"
+	JobTypeCreateStats string = "CREATE STATISTICS"
+	JobTypeImport      string = "IMPORT"
 )
 
 func (jp *JobPayload) typ() string {
@@ -257,11 +468,47 @@ func (jp *JobPayload) typ() string {
 		return JobTypeBackup
 	case *JobPayload_Restore:
 		return JobTypeRestore
+	case *JobPayload_CreateStats:
+		return JobTypeCreateStats
+	case *JobPayload_Import:
+		return JobTypeImport
 	default:
 		panic("JobPayload.typ called on a payload with an unknown details type")
 	}
 }
 
+func (jp *JobPayload) setDetails(details interface{}) error {
+	switch d := details.(type) {
+	case BackupJobDetails:
+		jp.Details = &JobPayload_Backup{Backup: &d}
+	case RestoreJobDetails:
+		jp.Details = &JobPayload_Restore{Restore: &d}
+	case CreateStatsJobDetails:
+		jp.Details = &JobPayload_CreateStats{CreateStats: &d}
+	case ImportJobDetails:
+		jp.Details = &JobPayload_Import{Import: &d}
+	default:
+		return errors.Errorf("JobLogger: unsupported job details type %T", d)
+	}
+	return nil
+}
+
+// details returns the details of the job, as passed to setDetails.
+func (jp *JobPayload) details() interface{} {
+	switch d := jp.Details.(type) {
+	case *JobPayload_Backup:
+		return *d.Backup
+	case *JobPayload_Restore:
+		return *d.Restore
+	case *JobPayload_CreateStats:
+		return *d.CreateStats
+	case *JobPayload_Import:
+		return *d.Import
+	default:
+		return nil
+	}
+}
+
 func unmarshalJobPayload(datum parser.Datum) (*JobPayload, error) {
 	payload := &JobPayload{}
 	bytes, ok := datum.(*parser.DBytes)
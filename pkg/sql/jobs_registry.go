@@ -0,0 +1,315 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/internal/client"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/pkg/errors"
+)
+
+// DefaultJobAdoptInterval is the default interval at which each node looks
+// for orphaned jobs to adopt.
+var DefaultJobAdoptInterval = 30 * time.Second
+
+// JobResumeHook resumes the execution of an adopted job of a given type from
+// the last checkpoint recorded in the details of job.Job. The job has already
+// been started, so the hook must not call Created or Started, and it must not
+// mark the job as finished either: the registry does that based on the
+// returned error.
+type JobResumeHook func(ctx context.Context, execCfg *ExecutorConfig, job *JobLogger) error
+
+var jobResumeHooks = map[string]JobResumeHook{}
+
+// AddJobResumeHook registers the hook used to resume adopted jobs of the given
+// type (e.g. JobTypeBackup). Jobs without a registered hook are never adopted.
+func AddJobResumeHook(typ string, hook JobResumeHook) {
+	jobResumeHooks[typ] = hook
+}
+
+// TestingSetJobResumeHook overrides the hook used to resume adopted jobs of the
+// given type and returns a function that restores the previous hook.
+func TestingSetJobResumeHook(typ string, hook JobResumeHook) func() {
+	prev, ok := jobResumeHooks[typ]
+	jobResumeHooks[typ] = hook
+	return func() {
+		if ok {
+			jobResumeHooks[typ] = prev
+		} else {
+			delete(jobResumeHooks, typ)
+		}
+	}
+}
+
+// JobRegistry creates the JobLoggers of the jobs started on this node and
+// keeps track of the jobs this node is running.
+//
+// Every job is leased to the node running it. The lease records the node's
+// liveness epoch at the time the lease was acquired, so it expires once the
+// node's liveness record is no longer live or its epoch is incremented, which
+// happens when the node restarts or fails to heartbeat. The registry
+// periodically scans the jobs table for running or paused jobs with expired
+// leases, takes over their leases and resumes them using the JobResumeHook
+// registered for their type. A JobLogger notices that its job has been
+// adopted by another node the next time it updates the job, and fails.
+type JobRegistry struct {
+	ac           log.AmbientContext
+	db           *client.DB
+	ex           InternalExecutor
+	nodeID       *base.NodeIDContainer
+	nodeLiveness *storage.NodeLiveness
+
+	mu struct {
+		syncutil.Mutex
+		// jobs holds the IDs of the jobs currently running on this node.
+		jobs map[int64]struct{}
+	}
+}
+
+// MakeJobRegistry creates a new JobRegistry.
+func MakeJobRegistry(
+	ac log.AmbientContext,
+	db *client.DB,
+	leaseMgr *LeaseManager,
+	nodeID *base.NodeIDContainer,
+	nodeLiveness *storage.NodeLiveness,
+) *JobRegistry {
+	r := &JobRegistry{
+		ac:           ac,
+		db:           db,
+		ex:           InternalExecutor{LeaseManager: leaseMgr},
+		nodeID:       nodeID,
+		nodeLiveness: nodeLiveness,
+	}
+	r.mu.jobs = make(map[int64]struct{})
+	return r
+}
+
+// NewJobLogger creates a new JobLogger for a job that will run on this node.
+// The job is leased to this node when it is created.
+func (r *JobRegistry) NewJobLogger(job JobRecord) JobLogger {
+	return JobLogger{
+		db:       r.db,
+		ex:       r.ex,
+		registry: r,
+		Job:      job,
+	}
+}
+
+// Start starts the goroutine that adopts orphaned jobs every adoptInterval.
+func (r *JobRegistry) Start(
+	ctx context.Context, stopper *stop.Stopper, execCfg *ExecutorConfig, adoptInterval time.Duration,
+) {
+	stopper.RunWorker(ctx, func(ctx context.Context) {
+		ticker := time.NewTicker(adoptInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if err := r.maybeAdoptJobs(ctx, stopper, execCfg); err != nil {
+					log.Errorf(ctx, "error while adopting jobs: %+v", err)
+				}
+			case <-stopper.ShouldQuiesce():
+				return
+			}
+		}
+	})
+}
+
+func (r *JobRegistry) newLease() (*JobLease, error) {
+	liveness, err := r.nodeLiveness.Self()
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to determine the liveness epoch of this node")
+	}
+	return &JobLease{NodeID: r.nodeID.Get(), Epoch: liveness.Epoch}, nil
+}
+
+func (r *JobRegistry) register(jobID int64) {
+	r.mu.Lock()
+	r.mu.jobs[jobID] = struct{}{}
+	r.mu.Unlock()
+}
+
+func (r *JobRegistry) unregister(jobID int64) {
+	r.mu.Lock()
+	delete(r.mu.jobs, jobID)
+	r.mu.Unlock()
+}
+
+// isLeaseExpired returns whether the given lease on the job with the given ID
+// is held by a node that is no longer running the job.
+func (r *JobRegistry) isLeaseExpired(jobID int64, lease *JobLease) (bool, error) {
+	if lease.NodeID == r.nodeID.Get() {
+		// This node does not need to consult liveness about its own leases: a
+		// job it is running has not expired, even if its epoch was incremented
+		// in the meantime. A job leased to the current incarnation of this
+		// node has not expired either, as it may not have been registered yet
+		// by the JobLogger that created it. Only jobs leased to a previous
+		// incarnation of this node are orphaned.
+		r.mu.Lock()
+		_, running := r.mu.jobs[jobID]
+		r.mu.Unlock()
+		if running {
+			return false, nil
+		}
+		liveness, err := r.nodeLiveness.Self()
+		if err != nil {
+			return false, err
+		}
+		return lease.Epoch < liveness.Epoch, nil
+	}
+	liveness, err := r.nodeLiveness.GetLiveness(lease.NodeID)
+	if err != nil {
+		return false, err
+	}
+	if liveness.Epoch > lease.Epoch {
+		return true, nil
+	}
+	live, err := r.nodeLiveness.IsLive(lease.NodeID)
+	if err != nil {
+		return false, err
+	}
+	return !live, nil
+}
+
+// maybeAdoptJobs adopts the running and paused jobs whose lease has expired.
+func (r *JobRegistry) maybeAdoptJobs(
+	ctx context.Context, stopper *stop.Stopper, execCfg *ExecutorConfig,
+) error {
+	var rows []parser.Datums
+	if err := r.db.Txn(ctx, func(ctx context.Context, txn *client.Txn) error {
+		const stmt = `SELECT id, payload FROM system.jobs WHERE status IN ($1, $2) ORDER BY created`
+		var err error
+		rows, err = r.ex.QueryRowsInTransaction(
+			ctx, "adopt-jobs", txn, stmt, JobStatusRunning, JobStatusPaused)
+		return err
+	}); err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		jobID := int64(*row[0].(*parser.DInt))
+		payload, err := unmarshalJobPayload(row[1])
+		if err != nil {
+			return err
+		}
+		if payload.Lease == nil {
+			// Jobs created without a registry cannot be adopted.
+			continue
+		}
+		resume := jobResumeHooks[payload.typ()]
+		if resume == nil {
+			continue
+		}
+		if expired, err := r.isLeaseExpired(jobID, payload.Lease); err != nil {
+			log.VEventf(ctx, 2, "unable to determine whether the lease on job %d expired: %s", jobID, err)
+			continue
+		} else if !expired {
+			continue
+		}
+		if err := r.adopt(ctx, stopper, execCfg, jobID, payload.Lease, resume); err != nil {
+			log.Errorf(ctx, "unable to adopt job %d: %+v", jobID, err)
+		}
+	}
+	return nil
+}
+
+// adopt takes over the lease on the job with the given ID, provided it is
+// still held by oldLease, and resumes the job in an async task.
+func (r *JobRegistry) adopt(
+	ctx context.Context,
+	stopper *stop.Stopper,
+	execCfg *ExecutorConfig,
+	jobID int64,
+	oldLease *JobLease,
+	resume JobResumeHook,
+) error {
+	lease, err := r.newLease()
+	if err != nil {
+		return err
+	}
+	var payload *JobPayload
+	if err := r.db.Txn(ctx, func(ctx context.Context, txn *client.Txn) error {
+		return updateJob(ctx, r.ex, txn, jobID, func(status *JobStatus, p *JobPayload) (bool, error) {
+			if *status != JobStatusRunning && *status != JobStatusPaused {
+				return false, &InvalidJobStatusError{id: jobID, status: *status, op: "adopt"}
+			}
+			if !p.Lease.equal(oldLease) {
+				return false, errors.Errorf("job %d was adopted by node %d", jobID, p.Lease.NodeID)
+			}
+			p.Lease = lease
+			payload = p
+			return true, nil
+		})
+	}); err != nil {
+		return err
+	}
+	log.Infof(ctx, "adopted job %d (%s) from node %d", jobID, payload.Description, oldLease.NodeID)
+
+	jl := JobLogger{
+		db:       r.db,
+		ex:       r.ex,
+		jobID:    &jobID,
+		registry: r,
+		lease:    lease,
+		Job: JobRecord{
+			Description:   payload.Description,
+			Username:      payload.Username,
+			DescriptorIDs: payload.DescriptorIDs,
+			Details:       payload.details(),
+		},
+	}
+	r.register(jobID)
+	resumeCtx := r.ac.AnnotateCtx(context.Background())
+	if err := stopper.RunAsyncTask(resumeCtx, func(ctx context.Context) {
+		ctx = stopper.WithCancel(ctx)
+		err := resume(ctx, execCfg, &jl)
+		if err != nil && ctx.Err() != nil {
+			// The node is shutting down. Leave the job as is, so it is adopted
+			// by another node.
+			log.Warningf(ctx, "job %d interrupted: %s", jobID, err)
+			r.unregister(jobID)
+			return
+		}
+		if err != nil {
+			jl.Failed(ctx, err)
+			return
+		}
+		if err := jl.Succeeded(ctx); err != nil {
+			log.Errorf(ctx, "error while marking job %d (%s) as successful: %+v",
+				jobID, payload.Description, err)
+		}
+	}); err != nil {
+		r.unregister(jobID)
+		return err
+	}
+	return nil
+}
+
+func (l *JobLease) equal(other *JobLease) bool {
+	if l == nil || other == nil {
+		return l == other
+	}
+	return *l == *other
+}
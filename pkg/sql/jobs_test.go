@@ -25,13 +25,16 @@ import (
 	"github.com/pkg/errors"
 	"golang.org/x/net/context"
 
+	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/sql"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/storage"
 	"github.com/cockroachdb/cockroach/pkg/testutils"
 	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
 	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/gogo/protobuf/proto"
 	"github.com/kr/pretty"
 	"github.com/lib/pq"
 )
@@ -123,7 +126,7 @@ func verifyJobRecord(
 	if started.Valid && created.Time.After(started.Time) {
 		return errors.Errorf("created time %v is after started time %v", created, started)
 	}
-	if status == sql.JobStatusRunning {
+	if status == sql.JobStatusRunning || status == sql.JobStatusPaused {
 		return verifyModifiedAgainst("started", started.Time)
 	}
 
@@ -398,4 +401,182 @@ func TestJobLogger(t *testing.T) {
 			t.Fatal(err)
 		}
 	})
+
+	t.Run("paused, resumed and canceled jobs", func(t *testing.T) {
+		db := sqlutils.MakeSQLRunner(t, rawSQLDB)
+		job := sql.JobRecord{Details: sql.RestoreJobDetails{}}
+		expectation := jobExpectation{
+			Job:    job,
+			Type:   sql.JobTypeRestore,
+			Before: timeutil.Now(),
+		}
+		logger := sql.NewJobLogger(kvDB, s.LeaseManager().(*sql.LeaseManager), job)
+		if err := logger.Created(ctx); err != nil {
+			t.Fatal(err)
+		}
+		jobID := *logger.JobID()
+		if _, err := rawSQLDB.Exec(`PAUSE JOB $1`, jobID); !testutils.IsError(err, `cannot pause pending job`) {
+			t.Fatalf("expected 'cannot pause pending job' error, but got %v", err)
+		}
+		if err := logger.Started(ctx); err != nil {
+			t.Fatal(err)
+		}
+
+		db.Exec(`PAUSE JOB $1`, jobID)
+		if err := verifyJobRecord(db, sql.JobStatusPaused, expectation); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := rawSQLDB.Exec(`PAUSE JOB $1`, jobID); !testutils.IsError(err, `cannot pause paused job`) {
+			t.Fatalf("expected 'cannot pause paused job' error, but got %v", err)
+		}
+
+		// Work in flight when the job is paused can still record its progress,
+		// but the job stays paused.
+		if err := logger.ProgressedWithDetails(
+			ctx, 0.5, sql.RestoreJobDetails{HighWater: []byte("b")},
+		); err != nil {
+			t.Fatal(err)
+		}
+		expectation.FractionCompleted = 0.5
+		if err := verifyJobRecord(db, sql.JobStatusPaused, expectation); err != nil {
+			t.Fatal(err)
+		}
+		var payloadBytes []byte
+		db.QueryRow(`SELECT payload FROM system.jobs WHERE id = $1`, jobID).Scan(&payloadBytes)
+		var payload sql.JobPayload
+		if err := proto.Unmarshal(payloadBytes, &payload); err != nil {
+			t.Fatal(err)
+		}
+		if e, a := "b", string(payload.GetRestore().HighWater); e != a {
+			t.Fatalf("expected high water %q, got %q", e, a)
+		}
+
+		waitErr := make(chan error)
+		go func() {
+			waitErr <- logger.WaitWhilePaused(ctx)
+		}()
+		db.Exec(`RESUME JOB $1`, jobID)
+		if err := <-waitErr; err != nil {
+			t.Fatal(err)
+		}
+		if err := verifyJobRecord(db, sql.JobStatusRunning, expectation); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := rawSQLDB.Exec(`RESUME JOB $1`, jobID); !testutils.IsError(err, `cannot resume running job`) {
+			t.Fatalf("expected 'cannot resume running job' error, but got %v", err)
+		}
+
+		db.Exec(`CANCEL JOB $1`, jobID)
+		if err := logger.WaitWhilePaused(ctx); !testutils.IsError(err, `job \d+ was canceled`) {
+			t.Fatalf("expected 'job was canceled' error, but got %v", err)
+		}
+		if err := logger.Progressed(ctx, 0.7); !testutils.IsError(err, `job \d+ was canceled`) {
+			t.Fatalf("expected 'job was canceled' error, but got %v", err)
+		}
+		// Failing a canceled job leaves it canceled.
+		logger.Failed(ctx, errors.New("canceled"))
+		if err := verifyJobRecord(db, sql.JobStatusCanceled, expectation); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := rawSQLDB.Exec(`CANCEL JOB $1`, jobID); !testutils.IsError(err, `cannot cancel canceled job`) {
+			t.Fatalf("expected 'cannot cancel canceled job' error, but got %v", err)
+		}
+		if _, err := rawSQLDB.Exec(`RESUME JOB $1`, jobID); !testutils.IsError(err, `cannot resume canceled job`) {
+			t.Fatalf("expected 'cannot resume canceled job' error, but got %v", err)
+		}
+	})
+
+	t.Run("controlling a nonexistent job fails", func(t *testing.T) {
+		if _, err := rawSQLDB.Exec(`CANCEL JOB 42`); !testutils.IsError(err, `job with ID 42 does not exist`) {
+			t.Fatalf("expected 'job does not exist' error, but got %v", err)
+		}
+	})
+}
+
+func TestJobRegistryAdoptsOrphanedJobs(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	defer func(oldInterval time.Duration) {
+		sql.DefaultJobAdoptInterval = oldInterval
+	}(sql.DefaultJobAdoptInterval)
+	sql.DefaultJobAdoptInterval = 10 * time.Millisecond
+
+	resumed := make(chan sql.RestoreJobDetails, 1)
+	defer sql.TestingSetJobResumeHook(sql.JobTypeRestore,
+		func(_ context.Context, _ *sql.ExecutorConfig, job *sql.JobLogger) error {
+			resumed <- job.Job.Details.(sql.RestoreJobDetails)
+			return nil
+		})()
+
+	params, _ := createTestServerParams()
+	s, rawSQLDB, kvDB := serverutils.StartServer(t, params)
+	defer s.Stopper().Stop(context.TODO())
+	db := sqlutils.MakeSQLRunner(t, rawSQLDB)
+
+	insertRunningJob := func(description string, epoch int64, highWater string) int64 {
+		payload, err := proto.Marshal(&sql.JobPayload{
+			Description:   description,
+			StartedMicros: timeutil.Now().UnixNano() / time.Microsecond.Nanoseconds(),
+			Lease:         &sql.JobLease{NodeID: s.NodeID(), Epoch: epoch},
+			Details: &sql.JobPayload_Restore{
+				Restore: &sql.RestoreJobDetails{HighWater: []byte(highWater)},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var jobID int64
+		db.QueryRow(
+			`INSERT INTO system.jobs (status, payload) VALUES ($1, $2) RETURNING id`,
+			sql.JobStatusRunning, payload,
+		).Scan(&jobID)
+		return jobID
+	}
+
+	// A running job leased to the current incarnation of this node is not
+	// orphaned, even though this node is not running it: a job is only
+	// registered once the JobLogger creating it has inserted it.
+	var liveness storage.Liveness
+	if err := kvDB.GetProto(context.TODO(), keys.NodeLivenessKey(s.NodeID()), &liveness); err != nil {
+		t.Fatal(err)
+	}
+	insertRunningJob("current", liveness.Epoch, "a")
+
+	// A running job leased to this node, which is not running it, was orphaned
+	// by a previous incarnation of the node.
+	jobID := insertRunningJob("orphan", 0, "b")
+
+	select {
+	case details := <-resumed:
+		if string(details.HighWater) != "b" {
+			t.Fatalf("expected job to resume from checkpoint b, but got %q", details.HighWater)
+		}
+	case <-time.After(testutils.DefaultSucceedsSoonDuration):
+		t.Fatal("timed out waiting for the orphaned job to be adopted")
+	}
+
+	testutils.SucceedsSoon(t, func() error {
+		var status string
+		var payloadBytes []byte
+		db.QueryRow(`SELECT status, payload FROM system.jobs WHERE id = $1`, jobID).Scan(
+			&status, &payloadBytes)
+		if status != string(sql.JobStatusSucceeded) {
+			return errors.Errorf("expected adopted job to succeed, but its status is %s", status)
+		}
+		var adopted sql.JobPayload
+		if err := proto.Unmarshal(payloadBytes, &adopted); err != nil {
+			return err
+		}
+		if adopted.Lease == nil || adopted.Lease.NodeID != s.NodeID() || adopted.Lease.Epoch == 0 {
+			return errors.Errorf("expected job to be leased to the current incarnation of node %d, "+
+				"but got lease %+v", s.NodeID(), adopted.Lease)
+		}
+		return nil
+	})
+
+	select {
+	case details := <-resumed:
+		t.Fatalf("unexpected adoption of job with checkpoint %q", details.HighWater)
+	case <-time.After(10 * sql.DefaultJobAdoptInterval):
+	}
 }
@@ -38,6 +38,28 @@ const (
 	joinTypeFullOuter
 )
 
+// joinAlgorithm is the algorithm used to execute a join with DistSQL. Local
+// execution always uses a hash join.
+type joinAlgorithm int
+
+const (
+	hashJoin joinAlgorithm = iota
+	mergeJoin
+	lookupJoin
+)
+
+func (a joinAlgorithm) String() string {
+	switch a {
+	case hashJoin:
+		return "hash"
+	case mergeJoin:
+		return "merge"
+	case lookupJoin:
+		return "lookup"
+	}
+	return fmt.Sprintf("joinAlgorithm(%d)", int(a))
+}
+
 // bucket here is the set of rows for a given group key (comprised of
 // columns specified by the join constraints), 'seen' is used to determine if
 // there was a matching row in the opposite stream.
@@ -135,6 +157,20 @@ type joinNode struct {
 	// pred represents the join predicate.
 	pred *joinPredicate
 
+	// costBased is set for the joins of a tree of inner joins that was
+	// considered by the join reordering pass (see join_order.go).
+	costBased bool
+	// algorithm is chosen once the operands are expanded (see
+	// chooseAlgorithm). Only the joins which are costBased can use merge
+	// joins.
+	algorithm joinAlgorithm
+	// left/rightMergeOrdering are the orderings of the left and right
+	// operands on the equality columns, when algorithm is mergeJoin.
+	leftMergeOrdering, rightMergeOrdering sqlbase.ColumnOrdering
+	// lookup describes the index of the right side's table used when
+	// algorithm is lookupJoin.
+	lookup lookupJoinInfo
+
 	// columns contains the metadata for the results of this node.
 	columns sqlbase.ResultColumns
 
@@ -595,11 +631,16 @@ func (n *joinNode) DebugValues() debugValues {
 
 // Close implements the planNode interface.
 func (n *joinNode) Close(ctx context.Context) {
+	n.closeBuffers(ctx)
+	n.right.plan.Close(ctx)
+	n.left.plan.Close(ctx)
+}
+
+// closeBuffers releases the memory used by the join, without closing its
+// operands.
+func (n *joinNode) closeBuffers(ctx context.Context) {
 	n.buffer.Close(ctx)
 	n.buffer = nil
 	n.buckets.Close(ctx)
 	n.bucketsMemAcc.Wtxn(n.planner.session).Close(ctx)
-
-	n.right.plan.Close(ctx)
-	n.left.plan.Close(ctx)
 }
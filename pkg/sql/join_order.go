@@ -0,0 +1,625 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"bytes"
+
+	"golang.org/x/net/context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+)
+
+// This file implements the cost-based reordering of trees of inner joins.
+//
+// The joins of a query are initially planned in the order in which the tables
+// appear in the FROM clause. When the plan is expanded, a maximal tree of inner
+// joins without merged (USING/NATURAL) columns is collected into a join graph:
+// the relations are the leaves of the tree, and the edges are the conjuncts of
+// the join predicates (both the equality columns and the ON conditions). If
+// the number of rows of every relation can be estimated from table statistics,
+// a join order is enumerated (with dynamic programming over all the subsets of
+// relations for small graphs, and greedily for larger ones) and, if it is
+// estimated to be cheaper than the original order, the tree is rebuilt in the
+// new order. A renderNode on top of the new tree restores the original order of
+// the columns.
+//
+// Once the operands of these joins are expanded, the join algorithm is chosen
+// (see joinNode.chooseAlgorithm).
+
+const (
+	// joinOrderMaxDPRelations is the maximum number of relations for which
+	// all the join orders are enumerated; larger graphs are ordered greedily.
+	joinOrderMaxDPRelations = 8
+
+	// joinOrderMaxRelations is the maximum number of relations of a join
+	// graph, as limited by relSet.
+	joinOrderMaxRelations = 64
+
+	// defaultEqualitySelectivity is the fraction of the rows of a cross
+	// product estimated to satisfy an equality between two columns for which
+	// there are no statistics.
+	defaultEqualitySelectivity = 0.1
+
+	// defaultFilterSelectivity is the fraction of the rows of a cross product
+	// estimated to satisfy a join condition other than an equality.
+	defaultFilterSelectivity = 1.0 / 3
+)
+
+// relSet is a set of relations of a joinGraph.
+type relSet uint64
+
+// contains returns true if all the relations in o are in s.
+func (s relSet) contains(o relSet) bool {
+	return s&o == o
+}
+
+// joinConjunct is a conjunct of the join predicates of a join graph.
+type joinConjunct struct {
+	// rels is the set of relations referenced by the conjunct.
+	rels relSet
+	// selectivity is the estimated fraction of the rows of the cross product
+	// of rels that satisfy the conjunct.
+	selectivity float64
+	// expr is the conjunct; its IndexedVars refer to the columns of the
+	// original join tree.
+	expr parser.TypedExpr
+}
+
+// joinGraph describes the relations joined by a tree of inner joins and the
+// predicates between them.
+type joinGraph struct {
+	// rows contains the estimated number of rows of each relation.
+	rows      []float64
+	conjuncts []joinConjunct
+
+	// cards memoizes the results of cardinality.
+	cards map[relSet]float64
+}
+
+// joinTree is a join order for (a subset of) the relations of a join graph:
+// either a single relation, or a join of two trees.
+type joinTree struct {
+	rels relSet
+	// rel is the relation of a leaf.
+	rel int
+	// left and right are the operands of a join; they are nil for leaves.
+	left, right *joinTree
+	// cost is the estimated cost of executing the tree.
+	cost float64
+}
+
+// equal returns true if the two trees join the same relations in the same
+// order.
+func (t *joinTree) equal(o *joinTree) bool {
+	if t.left == nil || o.left == nil {
+		return t.left == nil && o.left == nil && t.rel == o.rel
+	}
+	return t.left.equal(o.left) && t.right.equal(o.right)
+}
+
+// cardinality returns the estimated number of rows of the join of the
+// relations in s.
+func (g *joinGraph) cardinality(s relSet) float64 {
+	if c, ok := g.cards[s]; ok {
+		return c
+	}
+	c := 1.0
+	for i, rows := range g.rows {
+		if s.contains(1 << uint(i)) {
+			c *= rows
+		}
+	}
+	for _, conj := range g.conjuncts {
+		if s.contains(conj.rels) {
+			c *= conj.selectivity
+		}
+	}
+	if g.cards == nil {
+		g.cards = make(map[relSet]float64)
+	}
+	g.cards[s] = c
+	return c
+}
+
+// leaf returns the tree for a single relation.
+func (g *joinGraph) leaf(rel int) *joinTree {
+	return &joinTree{rels: 1 << uint(rel), rel: rel}
+}
+
+// join returns the tree joining left and right. The cost of a join is the
+// number of rows it produces plus the number of rows of its right operand,
+// which is buffered by the hash joiners; the cost of a tree is the sum of the
+// costs of its joins.
+func (g *joinGraph) join(left, right *joinTree) *joinTree {
+	rels := left.rels | right.rels
+	return &joinTree{
+		rels:  rels,
+		left:  left,
+		right: right,
+		cost:  left.cost + right.cost + g.cardinality(rels) + g.cardinality(right.rels),
+	}
+}
+
+// recost recomputes the costs of the joins of a tree built before all the
+// conjuncts of the graph were known.
+func (g *joinGraph) recost(t *joinTree) *joinTree {
+	if t.left == nil {
+		return t
+	}
+	return g.join(g.recost(t.left), g.recost(t.right))
+}
+
+// bestOrder returns the cheapest join order found for all the relations of
+// the graph.
+func (g *joinGraph) bestOrder() *joinTree {
+	if len(g.rows) <= joinOrderMaxDPRelations {
+		return g.dpOrder()
+	}
+	return g.greedyOrder()
+}
+
+// dpOrder finds the cheapest join order by dynamic programming: the best tree
+// for each subset of relations is the cheapest join of the best trees of two
+// complementary subsets. Bushy trees and cross products are considered.
+func (g *joinGraph) dpOrder() *joinTree {
+	all := relSet(1)<<uint(len(g.rows)) - 1
+	best := make([]*joinTree, all+1)
+	for i := range g.rows {
+		best[1<<uint(i)] = g.leaf(i)
+	}
+	for s := relSet(1); s <= all; s++ {
+		if s&(s-1) == 0 {
+			// Single relation.
+			continue
+		}
+		// Enumerate the non-empty proper subsets of s.
+		for l := (s - 1) & s; l > 0; l = (l - 1) & s {
+			t := g.join(best[l], best[s&^l])
+			if best[s] == nil || t.cost < best[s].cost {
+				best[s] = t
+			}
+		}
+	}
+	return best[all]
+}
+
+// greedyOrder builds a join order by repeatedly joining the two trees whose
+// join is the cheapest.
+func (g *joinGraph) greedyOrder() *joinTree {
+	trees := make([]*joinTree, len(g.rows))
+	for i := range g.rows {
+		trees[i] = g.leaf(i)
+	}
+	for len(trees) > 1 {
+		var best *joinTree
+		var bestL, bestR int
+		for l := range trees {
+			for r := range trees {
+				if l == r {
+					continue
+				}
+				if t := g.join(trees[l], trees[r]); best == nil || t.cost < best.cost {
+					best, bestL, bestR = t, l, r
+				}
+			}
+		}
+		trees[bestL] = best
+		trees = append(trees[:bestR], trees[bestR+1:]...)
+	}
+	return trees[0]
+}
+
+// joinOrderer collects a tree of inner joins into a joinGraph and rebuilds it
+// in the order chosen for the graph.
+type joinOrderer struct {
+	p     *planner
+	graph joinGraph
+
+	// sources, scans, tableStats and firstCol describe each relation of the
+	// graph: its data source, the scanNode of the data source, the
+	// statistics of the scanned table and the index of its first column in
+	// the columns of the original tree.
+	sources    []planDataSource
+	scans      []*scanNode
+	tableStats [][]*TableStatistic
+	firstCol   []int
+
+	// joins are the joins of the original tree.
+	joins []*joinNode
+
+	// info describes the columns of the original tree. The IndexedVars of the
+	// conjuncts of the graph refer to these columns.
+	info       *dataSourceInfo
+	ivarHelper parser.IndexedVarHelper
+}
+
+var _ parser.IndexedVarContainer = &joinOrderer{}
+
+// IndexedVarEval implements the parser.IndexedVarContainer interface.
+func (o *joinOrderer) IndexedVarEval(idx int, ctx *parser.EvalContext) (parser.Datum, error) {
+	panic("no eval allowed in joinOrderer")
+}
+
+// IndexedVarResolvedType implements the parser.IndexedVarContainer interface.
+func (o *joinOrderer) IndexedVarResolvedType(idx int) parser.Type {
+	return o.info.sourceColumns[idx].Typ
+}
+
+// IndexedVarFormat implements the parser.IndexedVarContainer interface.
+func (o *joinOrderer) IndexedVarFormat(buf *bytes.Buffer, f parser.FmtFlags, idx int) {
+	o.info.FormatVar(buf, f, idx)
+}
+
+// isReorderableJoin returns true if the join can be part of a tree of joins
+// collected by the joinOrderer.
+func isReorderableJoin(plan planNode) bool {
+	n, ok := plan.(*joinNode)
+	return ok && n.joinType == joinTypeInner && n.pred.numMergedEqualityColumns == 0
+}
+
+// optimizeJoinOrder reorders the tree of inner joins rooted at n, if the
+// number of rows of all the joined relations can be estimated and a cheaper
+// join order is found. The joins of the tree are marked for the choice of a
+// join algorithm in either case. The operands of the joins must not have been
+// expanded yet.
+func (p *planner) optimizeJoinOrder(ctx context.Context, n *joinNode) (planNode, error) {
+	if n.costBased || !isReorderableJoin(n) {
+		return n, nil
+	}
+	o := &joinOrderer{p: p, info: n.pred.info}
+	o.ivarHelper = parser.MakeIndexedVarHelper(o, len(n.pred.info.sourceColumns))
+	orig, ok := o.collect(ctx, planDataSource{info: n.pred.info, plan: n}, 0)
+	if !ok {
+		return n, nil
+	}
+	for _, j := range o.joins {
+		j.costBased = true
+	}
+
+	orig = o.graph.recost(orig)
+	best := o.graph.bestOrder()
+	if best.equal(orig) || best.cost >= orig.cost {
+		return n, nil
+	}
+	return o.rebuild(ctx, best)
+}
+
+// collect adds the relations and predicates of a tree of joins to the graph.
+// offset is the index of the first column of src in the columns of the
+// original tree. It returns the (uncosted) tree of the original join order, or
+// false if the tree cannot be reordered.
+func (o *joinOrderer) collect(
+	ctx context.Context, src planDataSource, offset int,
+) (*joinTree, bool) {
+	if !isReorderableJoin(src.plan) {
+		return o.addRelation(ctx, src, offset)
+	}
+	n := src.plan.(*joinNode)
+	rightOffset := offset + len(n.left.info.sourceColumns)
+	left, ok := o.collect(ctx, n.left, offset)
+	if !ok {
+		return nil, false
+	}
+	right, ok := o.collect(ctx, n.right, rightOffset)
+	if !ok {
+		return nil, false
+	}
+	o.joins = append(o.joins, n)
+
+	for i := range n.pred.leftEqualityIndices {
+		eq := parser.NewTypedComparisonExpr(
+			parser.EQ,
+			o.ivarHelper.IndexedVar(offset+n.pred.leftEqualityIndices[i]),
+			o.ivarHelper.IndexedVar(rightOffset+n.pred.rightEqualityIndices[i]),
+		)
+		if !o.addConjunct(eq) {
+			return nil, false
+		}
+	}
+	if n.pred.onCond != nil {
+		for _, e := range splitAndExpr(&o.p.evalCtx, n.pred.onCond, nil) {
+			conv := func(expr parser.VariableExpr) (bool, parser.Expr) {
+				if iv, ok := expr.(*parser.IndexedVar); ok {
+					return true, o.ivarHelper.IndexedVar(offset + iv.Idx)
+				}
+				return false, expr
+			}
+			if !exprCheckVars(e, conv) || !o.addConjunct(exprConvertVars(e, conv)) {
+				return nil, false
+			}
+		}
+	}
+	return &joinTree{rels: left.rels | right.rels, left: left, right: right}, true
+}
+
+// addRelation adds a leaf of the join tree to the graph. Only table scans for
+// which there are statistics are supported.
+func (o *joinOrderer) addRelation(
+	ctx context.Context, src planDataSource, offset int,
+) (*joinTree, bool) {
+	if len(o.sources) == joinOrderMaxRelations {
+		return nil, false
+	}
+	scan, ok := src.plan.(*scanNode)
+	if !ok {
+		return nil, false
+	}
+	rows, tableStats := o.p.estimateScanRows(ctx, scan)
+	if tableStats == nil {
+		return nil, false
+	}
+	rel := len(o.sources)
+	o.sources = append(o.sources, src)
+	o.scans = append(o.scans, scan)
+	o.tableStats = append(o.tableStats, tableStats)
+	o.firstCol = append(o.firstCol, offset)
+	o.graph.rows = append(o.graph.rows, rows)
+	return o.graph.leaf(rel), true
+}
+
+// relationForColumn returns the relation providing the given column of the
+// original tree.
+func (o *joinOrderer) relationForColumn(col int) int {
+	// The relations are collected from left to right, so their first columns
+	// are increasing.
+	rel := 0
+	for i, first := range o.firstCol {
+		if first <= col {
+			rel = i
+		}
+	}
+	return rel
+}
+
+// addConjunct adds a conjunct of the join predicates to the graph. It returns
+// false if the conjunct doesn't refer to at least two relations, which can
+// only happen if it could not be pushed down into a join operand.
+func (o *joinOrderer) addConjunct(expr parser.TypedExpr) bool {
+	var rels relSet
+	var numRels int
+	exprCheckVars(expr, func(v parser.VariableExpr) (bool, parser.Expr) {
+		if iv, ok := v.(*parser.IndexedVar); ok {
+			if r := relSet(1) << uint(o.relationForColumn(iv.Idx)); !rels.contains(r) {
+				rels |= r
+				numRels++
+			}
+		}
+		return true, v
+	})
+	if numRels < 2 {
+		return false
+	}
+	o.graph.conjuncts = append(o.graph.conjuncts, joinConjunct{
+		rels:        rels,
+		selectivity: o.estimateSelectivity(expr),
+		expr:        expr,
+	})
+	return true
+}
+
+// estimateSelectivity estimates the fraction of the rows of the cross product
+// of the relations referenced by a conjunct which satisfy it. The selectivity
+// of an equality between two columns is 1/max(d1, d2), where d1 and d2 are the
+// numbers of distinct values in the columns.
+func (o *joinOrderer) estimateSelectivity(expr parser.TypedExpr) float64 {
+	c, ok := expr.(*parser.ComparisonExpr)
+	if !ok || c.Operator != parser.EQ {
+		return defaultFilterSelectivity
+	}
+	lhs, ok := c.Left.(*parser.IndexedVar)
+	if !ok {
+		return defaultFilterSelectivity
+	}
+	rhs, ok := c.Right.(*parser.IndexedVar)
+	if !ok {
+		return defaultFilterSelectivity
+	}
+	var distinct int64
+	for _, iv := range []*parser.IndexedVar{lhs, rhs} {
+		rel := o.relationForColumn(iv.Idx)
+		col := o.scans[rel].cols[iv.Idx-o.firstCol[rel]]
+		if stat := findColumnStat(o.tableStats[rel], col.ID); stat != nil && stat.DistinctCount > distinct {
+			distinct = stat.DistinctCount
+		}
+	}
+	if distinct == 0 {
+		return defaultEqualitySelectivity
+	}
+	return 1 / float64(distinct)
+}
+
+// rebuild plans the joins of the given tree and returns a renderNode that
+// produces the columns of the original tree.
+func (o *joinOrderer) rebuild(ctx context.Context, t *joinTree) (planNode, error) {
+	src, cols, err := o.build(ctx, t)
+	if err != nil {
+		return nil, err
+	}
+	// The joins of the original tree are discarded; their operands are reused.
+	for _, j := range o.joins {
+		j.closeBuffers(ctx)
+	}
+
+	r := &renderNode{
+		planner:    o.p,
+		source:     src,
+		sourceInfo: multiSourceInfo{src.info},
+	}
+	r.ivarHelper = parser.MakeIndexedVarHelper(r, len(cols))
+	newIdx := make([]int, len(cols))
+	for i, col := range cols {
+		newIdx[col] = i
+	}
+	for col, resCol := range o.info.sourceColumns {
+		r.addRenderColumn(r.ivarHelper.IndexedVar(newIdx[col]), resCol)
+	}
+	return r, nil
+}
+
+// build plans the joins of the given tree. It returns the data source for the
+// tree and, for each of its columns, the index of the column in the original
+// tree.
+func (o *joinOrderer) build(ctx context.Context, t *joinTree) (planDataSource, []int, error) {
+	if t.left == nil {
+		src := o.sources[t.rel]
+		cols := make([]int, len(src.info.sourceColumns))
+		for i := range cols {
+			cols[i] = o.firstCol[t.rel] + i
+		}
+		return src, cols, nil
+	}
+	left, leftCols, err := o.build(ctx, t.left)
+	if err != nil {
+		return planDataSource{}, nil, err
+	}
+	right, rightCols, err := o.build(ctx, t.right)
+	if err != nil {
+		return planDataSource{}, nil, err
+	}
+	src, err := o.p.makeJoin(ctx, "CROSS JOIN", left, right, nil)
+	if err != nil {
+		return planDataSource{}, nil, err
+	}
+	n := src.plan.(*joinNode)
+	n.costBased = true
+
+	cols := append(append([]int(nil), leftCols...), rightCols...)
+	newIdx := make(map[int]int, len(cols))
+	for i, col := range cols {
+		newIdx[col] = i
+	}
+
+	// Add the conjuncts which refer to relations on both sides of the join.
+	var onCond parser.TypedExpr
+	for _, c := range o.graph.conjuncts {
+		if !t.rels.contains(c.rels) || t.left.rels.contains(c.rels) || t.right.rels.contains(c.rels) {
+			continue
+		}
+		e := exprConvertVars(c.expr, func(v parser.VariableExpr) (bool, parser.Expr) {
+			return true, n.pred.iVarHelper.IndexedVar(newIdx[v.(*parser.IndexedVar).Idx])
+		})
+		if !n.pred.tryAddEqualityFilter(e, left.info, right.info) {
+			onCond = mergeConj(onCond, e)
+		}
+	}
+	n.pred.onCond = n.pred.iVarHelper.Rebind(onCond, true, false)
+	return src, cols, nil
+}
+
+// estimateScanRows estimates the number of rows produced by a scan from the
+// statistics of the table, taking into account the selectivity of the filter
+// on the indexes of the table. It also returns the statistics, which are nil
+// if there are none (in which case no estimate is made).
+func (p *planner) estimateScanRows(
+	ctx context.Context, s *scanNode,
+) (float64, []*TableStatistic) {
+	if s.desc.IsEmpty() {
+		return 0, nil
+	}
+	tableStats := p.getTableStats(ctx, &s.desc)
+	if len(tableStats) == 0 {
+		return 0, nil
+	}
+	// The statistics are sorted by decreasing creation time, so the first one
+	// has the most recent row count.
+	rows := float64(tableStats[0].RowCount)
+	if rows < 1 {
+		rows = 1
+	}
+	if s.filter == nil {
+		return rows, tableStats
+	}
+
+	exprs, _ := analyzeExpr(&p.evalCtx, s.filter)
+	if len(exprs) == 1 && len(exprs[0]) == 1 {
+		if d, ok := exprs[0][0].(*parser.DBool); ok && bool(!*d) {
+			return 1, tableStats
+		}
+	}
+	selectivity := 1.0
+	indexes := append([]sqlbase.IndexDescriptor{s.desc.PrimaryIndex}, s.desc.Indexes...)
+	for i := range indexes {
+		if indexes[i].Type == sqlbase.IndexDescriptor_INVERTED {
+			continue
+		}
+		v := &indexInfo{desc: &s.desc, index: &indexes[i], tableStats: tableStats}
+		if err := v.makeOrConstraints(exprs); err != nil {
+			return rows, tableStats
+		}
+		if len(v.constraints) == 0 {
+			continue
+		}
+		if sel, ok := v.estimateSelectivity(&p.evalCtx); ok && sel < selectivity {
+			selectivity = sel
+		}
+	}
+	rows *= selectivity
+	if rows < 1 {
+		rows = 1
+	}
+	return rows, tableStats
+}
+
+// chooseAlgorithm chooses the algorithm of a join once its operands have
+// been expanded, given the numbers of rows of the operands estimated before
+// their expansion: a lookup join if one is possible and estimated to be
+// cheaper than scanning the right side (see joinNode.preferLookupJoin),
+// otherwise a merge join if the join was planned by the join reordering pass
+// and both operands are ordered on the equality columns, or a hash join.
+func (n *joinNode) chooseAlgorithm(est joinRowEstimates) {
+	n.algorithm = hashJoin
+	n.leftMergeOrdering, n.rightMergeOrdering = nil, nil
+	n.lookup = lookupJoinInfo{}
+	if lookup, ok := n.lookupJoinIndex(); ok && n.preferLookupJoin(est) {
+		n.algorithm = lookupJoin
+		n.lookup = lookup
+		return
+	}
+	numEq := len(n.pred.leftEqualityIndices)
+	if !n.costBased || n.joinType != joinTypeInner || numEq == 0 {
+		return
+	}
+	leftOrdering := n.left.plan.Ordering().ordering
+	rightOrdering := n.right.plan.Ordering().ordering
+	if len(leftOrdering) < numEq || len(rightOrdering) < numEq {
+		return
+	}
+	// The i-th column of both orderings must be the two columns of the same
+	// equality, in the same direction.
+	matched := make([]bool, numEq)
+	for i := 0; i < numEq; i++ {
+		l, r := leftOrdering[i], rightOrdering[i]
+		if l.Direction != r.Direction {
+			return
+		}
+		found := false
+		for j := range matched {
+			if !matched[j] && n.pred.leftEqualityIndices[j] == l.ColIdx &&
+				n.pred.rightEqualityIndices[j] == r.ColIdx {
+				matched[j] = true
+				found = true
+				break
+			}
+		}
+		if !found {
+			return
+		}
+	}
+	n.algorithm = mergeJoin
+	n.leftMergeOrdering = leftOrdering[:numEq]
+	n.rightMergeOrdering = rightOrdering[:numEq]
+}
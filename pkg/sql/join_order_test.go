@@ -0,0 +1,119 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+)
+
+// formatJoinTree formats a join tree as nested parentheses of relation
+// indexes, e.g. "(0 (1 2))".
+func formatJoinTree(t *joinTree) string {
+	if t.left == nil {
+		return fmt.Sprint(t.rel)
+	}
+	return fmt.Sprintf("(%s %s)", formatJoinTree(t.left), formatJoinTree(t.right))
+}
+
+func TestJoinOrder(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	testCases := []struct {
+		rows      []float64
+		conjuncts []joinConjunct
+		expected  string
+	}{
+		// A single join: the smaller relation is on the right.
+		{
+			rows:      []float64{10, 1000},
+			conjuncts: []joinConjunct{{rels: 1<<0 | 1<<1, selectivity: 0.001}},
+			expected:  "(1 0)",
+		},
+		// A chain 0 - 1 - 2: the join of the two small relations comes first.
+		{
+			rows: []float64{1000, 100, 10},
+			conjuncts: []joinConjunct{
+				{rels: 1<<0 | 1<<1, selectivity: 0.001},
+				{rels: 1<<1 | 1<<2, selectivity: 0.01},
+			},
+			expected: "(0 (1 2))",
+		},
+		// A chain 0 - 2 - 1: the cross product of 0 and 1 is avoided.
+		{
+			rows: []float64{1000, 100, 500},
+			conjuncts: []joinConjunct{
+				{rels: 1<<0 | 1<<2, selectivity: 0.001},
+				{rels: 1<<1 | 1<<2, selectivity: 0.002},
+			},
+			expected: "(0 (2 1))",
+		},
+		// A star around 0: the most selective join comes first.
+		{
+			rows: []float64{10000, 100, 200, 300},
+			conjuncts: []joinConjunct{
+				{rels: 1<<0 | 1<<1, selectivity: 0.01},
+				{rels: 1<<0 | 1<<2, selectivity: 0.0001},
+				{rels: 1<<0 | 1<<3, selectivity: 0.001},
+			},
+			expected: "(1 (3 (0 2)))",
+		},
+	}
+
+	for i, tc := range testCases {
+		for _, greedy := range []bool{false, true} {
+			g := joinGraph{rows: tc.rows, conjuncts: tc.conjuncts}
+			var best *joinTree
+			if greedy {
+				best = g.greedyOrder()
+			} else {
+				best = g.dpOrder()
+			}
+			if s := formatJoinTree(best); s != tc.expected {
+				t.Errorf("%d (greedy=%t): expected %s, got %s", i, greedy, tc.expected, s)
+			}
+		}
+	}
+}
+
+// TestJoinOrderDPNotWorseThanGreedy verifies that the join order found by
+// dynamic programming is never more expensive than the greedy one.
+func TestJoinOrderDPNotWorseThanGreedy(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	for n := 2; n <= joinOrderMaxDPRelations; n++ {
+		g := joinGraph{}
+		for i := 0; i < n; i++ {
+			g.rows = append(g.rows, float64((i*37)%11+1)*100)
+			if i > 0 {
+				g.conjuncts = append(g.conjuncts, joinConjunct{
+					rels:        relSet(1)<<uint(i) | relSet(1)<<uint((i*7)%i),
+					selectivity: 1 / float64((i*13)%7+2) / 100,
+				})
+			}
+		}
+		dp, greedy := g.dpOrder(), g.greedyOrder()
+		if dp.cost > greedy.cost {
+			t.Errorf("%d relations: DP order %s (cost %f) is worse than greedy order %s (cost %f)",
+				n, formatJoinTree(dp), dp.cost, formatJoinTree(greedy), greedy.cost)
+		}
+		if n == 2 && !dp.equal(greedy) {
+			t.Errorf("expected the same order for two relations, got %s and %s",
+				formatJoinTree(dp), formatJoinTree(greedy))
+		}
+	}
+}
@@ -128,6 +128,13 @@ func applyLimit(plan planNode, numRows int64, soft bool) {
 	case *ordinalityNode:
 		applyLimit(n.source, numRows, soft)
 
+	case *copyToNode:
+		setUnlimited(n.source)
+
+	case *recursiveCTENode:
+		setUnlimited(n.initial)
+		setUnlimited(n.recursive)
+
 	case *deleteNode:
 		setUnlimited(n.run.rows)
 	case *updateNode:
@@ -164,16 +171,25 @@ func applyLimit(plan planNode, numRows int64, soft bool) {
 
 	case *valuesNode:
 	case *alterTableNode:
+	case *cancelQueryNode:
+	case *cancelSessionNode:
+	case *controlJobNode:
 	case *copyNode:
 	case *createDatabaseNode:
 	case *createIndexNode:
+	case *createSequenceNode:
+	case *createStatsNode:
 	case *createUserNode:
 	case *dropDatabaseNode:
 	case *dropIndexNode:
+	case *dropRoleNode:
+	case *dropSequenceNode:
 	case *dropTableNode:
 	case *dropViewNode:
 	case *emptyNode:
 	case *hookFnNode:
+	case *grantRoleNode:
+	case *revokeRoleNode:
 	case *valueGenerator:
 	case *showRangesNode:
 	case *scatterNode:
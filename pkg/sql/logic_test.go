@@ -47,6 +47,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/server"
 	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/distsqlrun"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/testutils"
 	"github.com/cockroachdb/cockroach/pkg/testutils/distsqlutils"
@@ -248,6 +249,9 @@ type testClusterConfig struct {
 	// using RETURNING NOTHING syntax will be parallelized transparently.
 	// See logicStatement.parallelizeStmts.
 	parallelStmts bool
+	// if set, DistSQL processors that support it spill their rows to disk
+	// right away.
+	distSQLUseDisk bool
 }
 
 // logicTestConfigs contains all possible cluster configs. A test file can
@@ -260,6 +264,7 @@ var logicTestConfigs = []testClusterConfig{
 	{name: "default", numNodes: 1, overrideDistSQLMode: "Off"},
 	{name: "parallel-stmts", numNodes: 1, parallelStmts: true, overrideDistSQLMode: "Off"},
 	{name: "distsql", numNodes: 3, useFakeSpanResolver: true, overrideDistSQLMode: "On"},
+	{name: "distsql-disk", numNodes: 3, useFakeSpanResolver: true, overrideDistSQLMode: "On", distSQLUseDisk: true},
 	{name: "5node", numNodes: 5, overrideDistSQLMode: "Off"},
 }
 
@@ -640,7 +645,10 @@ func (t *logicTest) setUser(user string) func() {
 }
 
 func (t *logicTest) setup(
-	numNodes int, useFakeSpanResolver bool, distSQLOverride *settings.EnumSetting,
+	numNodes int,
+	useFakeSpanResolver bool,
+	distSQLOverride *settings.EnumSetting,
+	distSQLUseDisk bool,
 ) {
 	// TODO(pmattis): Add a flag to make it easy to run the tests against a local
 	// MySQL or Postgres instance.
@@ -659,6 +667,9 @@ func (t *logicTest) setup(
 					CheckStmtStringChange: true,
 					OverrideDistSQLMode:   distSQLOverride,
 				},
+				DistSQL: &distsqlrun.TestingKnobs{
+					ForceDiskSpill: distSQLUseDisk,
+				},
 			},
 		},
 		// For distributed SQL tests, we use the fake span resolver; it doesn't
@@ -1555,7 +1566,7 @@ func TestLogic(t *testing.T) {
 						distSQLOverrideEnum = &settings.EnumSetting{}
 						settings.TestingSetEnum(&distSQLOverrideEnum, int64(sql.DistSQLExecModeFromString(cfg.overrideDistSQLMode)))
 					}
-					lt.setup(cfg.numNodes, cfg.useFakeSpanResolver, distSQLOverrideEnum)
+					lt.setup(cfg.numNodes, cfg.useFakeSpanResolver, distSQLOverrideEnum, cfg.distSQLUseDisk)
 					lt.runFile(path, cfg)
 
 					progress.Lock()
@@ -0,0 +1,173 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"golang.org/x/net/context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+)
+
+// lookupJoinInfo describes how a join is executed as a lookup join: for each
+// row of the left side, the matching rows of the right side are retrieved
+// from an index of the right side's table.
+type lookupJoinInfo struct {
+	// indexIdx is 0 for the primary index, or 1 to <num-indexes> for a
+	// secondary index of the right side's table.
+	indexIdx int
+	// eqIndices contains, for each of the leading columns of the index used for
+	// the lookup, the equality constraint (an index in
+	// pred.{left,right}EqualityIndices) which provides its value.
+	eqIndices []int
+}
+
+// lookupJoinRowCost is the estimated cost of looking up the rows matching a
+// row of the left side of a lookup join, relative to the cost of scanning a
+// row of the right side's table.
+const lookupJoinRowCost = 10
+
+// joinRowEstimates are the numbers of rows of the operands of a join which
+// are used to choose its algorithm.
+type joinRowEstimates struct {
+	// left is the estimated number of rows produced by the left operand.
+	left float64
+	// right is the number of rows of the table scanned by the right
+	// operand, all of which are read by a hash or merge join.
+	right float64
+	// ok is false if there are no statistics to estimate the rows from.
+	ok bool
+}
+
+// estimateJoinRows estimates the numbers of rows of the operands of a join
+// which could be executed as a lookup join, from table statistics. It must
+// be called before the operands are expanded, while the filters of their
+// scans can still be analyzed.
+func (p *planner) estimateJoinRows(ctx context.Context, n *joinNode) joinRowEstimates {
+	if n.joinType != joinTypeInner && n.joinType != joinTypeLeftOuter {
+		return joinRowEstimates{}
+	}
+	if len(n.pred.leftEqualityIndices) == 0 {
+		return joinRowEstimates{}
+	}
+	left, ok := n.left.plan.(*scanNode)
+	if !ok {
+		return joinRowEstimates{}
+	}
+	right, ok := n.right.plan.(*scanNode)
+	if !ok || right.desc.IsEmpty() {
+		return joinRowEstimates{}
+	}
+	leftRows, leftStats := p.estimateScanRows(ctx, left)
+	if leftStats == nil {
+		return joinRowEstimates{}
+	}
+	rightStats := p.getTableStats(ctx, &right.desc)
+	if len(rightStats) == 0 {
+		return joinRowEstimates{}
+	}
+	return joinRowEstimates{left: leftRows, right: float64(rightStats[0].RowCount), ok: true}
+}
+
+// preferLookupJoin returns true if a lookup join is estimated to be cheaper
+// than a join which reads the entire right side. Without statistics, a lookup
+// join is preferred if the left side is a selective scan, which is expected
+// to produce few rows.
+func (n *joinNode) preferLookupJoin(est joinRowEstimates) bool {
+	if !est.ok {
+		return isSelectiveScan(n.left.plan)
+	}
+	return est.left*lookupJoinRowCost < est.right
+}
+
+// lookupJoinIndex determines whether a join can be executed as a lookup join,
+// and with which index. This is the case when:
+//  - the join is an INNER or LEFT OUTER join with equality constraints;
+//  - the left side is a scan;
+//  - the right side is a scan of the entire table (possibly with a filter),
+//    and the table has an index whose leading columns are constrained by the
+//    equality columns and which contains all the needed columns.
+// Among the candidate indexes, the one with the most constrained columns is
+// used. Whether a lookup join is actually used is decided by
+// joinNode.chooseAlgorithm.
+func (n *joinNode) lookupJoinIndex() (lookupJoinInfo, bool) {
+	if n.joinType != joinTypeInner && n.joinType != joinTypeLeftOuter {
+		return lookupJoinInfo{}, false
+	}
+	if _, ok := n.left.plan.(*scanNode); !ok || len(n.pred.leftEqualityIndices) == 0 {
+		return lookupJoinInfo{}, false
+	}
+	right, ok := n.right.plan.(*scanNode)
+	if !ok || right.scanVisibility != publicColumns || right.hardLimit != 0 ||
+		right.softLimit != 0 || !isFullIndexScan(right) {
+		return lookupJoinInfo{}, false
+	}
+
+	leftColumns := n.left.plan.Columns()
+	var best lookupJoinInfo
+	for indexIdx := 0; indexIdx <= len(right.desc.Indexes); indexIdx++ {
+		index := &right.desc.PrimaryIndex
+		if indexIdx > 0 {
+			index = &right.desc.Indexes[indexIdx-1]
+			if index.Type == sqlbase.IndexDescriptor_INVERTED || !indexCoversScan(index, right) {
+				continue
+			}
+		}
+		var eqIndices []int
+	IndexColLoop:
+		for _, colID := range index.ColumnIDs {
+			for i, rightCol := range n.pred.rightEqualityIndices {
+				if right.desc.Columns[rightCol].ID != colID {
+					continue
+				}
+				leftTyp := leftColumns[n.pred.leftEqualityIndices[i]].Typ
+				if leftTyp.Equivalent(right.resultColumns[rightCol].Typ) {
+					eqIndices = append(eqIndices, i)
+					continue IndexColLoop
+				}
+			}
+			break
+		}
+		if len(eqIndices) > len(best.eqIndices) {
+			best = lookupJoinInfo{indexIdx: indexIdx, eqIndices: eqIndices}
+		}
+	}
+	return best, len(best.eqIndices) > 0
+}
+
+// isSelectiveScan returns true if the plan is a scan which only reads part
+// of its table or filters its rows.
+func isSelectiveScan(plan planNode) bool {
+	scan, ok := plan.(*scanNode)
+	if !ok {
+		return false
+	}
+	return scan.hardLimit != 0 || scan.filter != nil || !isFullIndexScan(scan)
+}
+
+// isFullIndexScan returns true if the scan reads its entire index.
+func isFullIndexScan(scan *scanNode) bool {
+	return len(scan.spans) == 1 && scan.spans[0].Equal(scan.desc.IndexSpan(scan.index.ID))
+}
+
+// indexCoversScan returns true if the given index of the scan's table contains
+// all the columns needed from the scan.
+func indexCoversScan(index *sqlbase.IndexDescriptor, scan *scanNode) bool {
+	for i, needed := range scan.valNeededForCol {
+		if needed && !index.ContainsColumnID(scan.desc.Columns[i].ID) {
+			return false
+		}
+	}
+	return true
+}
@@ -84,6 +84,15 @@ func setNeededColumns(plan planNode, needed []bool) {
 		setNeededColumns(n.source, needed[:len(needed)-1])
 		markOmitted(n.columns[:len(needed)-1], needed[:len(needed)-1])
 
+	case *copyToNode:
+		// All the columns are sent to the client.
+		setNeededColumns(n.source, allColumns(n.source))
+
+	case *recursiveCTENode:
+		// All the columns of an iteration are needed by the next one.
+		setNeededColumns(n.initial, allColumns(n.initial))
+		setNeededColumns(n.recursive, allColumns(n.recursive))
+
 	case *valuesNode:
 		markOmitted(n.columns, needed)
 
@@ -191,17 +200,26 @@ func setNeededColumns(plan planNode, needed []bool) {
 		setNeededColumns(n.rows, allColumns(n.rows))
 
 	case *alterTableNode:
+	case *cancelQueryNode:
+	case *cancelSessionNode:
+	case *controlJobNode:
 	case *copyNode:
 	case *createDatabaseNode:
 	case *createIndexNode:
+	case *createSequenceNode:
+	case *createStatsNode:
 	case *createUserNode:
 	case *delayedNode:
 	case *dropDatabaseNode:
 	case *dropIndexNode:
+	case *dropRoleNode:
+	case *dropSequenceNode:
 	case *dropTableNode:
 	case *dropViewNode:
 	case *emptyNode:
 	case *hookFnNode:
+	case *grantRoleNode:
+	case *revokeRoleNode:
 	case *valueGenerator:
 	case *showRangesNode:
 	case *scatterNode:
@@ -65,6 +65,7 @@ func (*AlterTableAddConstraint) alterTableCmd()      {}
 func (*AlterTableDropColumn) alterTableCmd()         {}
 func (*AlterTableDropConstraint) alterTableCmd()     {}
 func (*AlterTableDropNotNull) alterTableCmd()        {}
+func (*AlterTableSetAudit) alterTableCmd()           {}
 func (*AlterTableSetDefault) alterTableCmd()         {}
 func (*AlterTableValidateConstraint) alterTableCmd() {}
 
@@ -73,6 +74,7 @@ var _ AlterTableCmd = &AlterTableAddConstraint{}
 var _ AlterTableCmd = &AlterTableDropColumn{}
 var _ AlterTableCmd = &AlterTableDropConstraint{}
 var _ AlterTableCmd = &AlterTableDropNotNull{}
+var _ AlterTableCmd = &AlterTableSetAudit{}
 var _ AlterTableCmd = &AlterTableSetDefault{}
 var _ AlterTableCmd = &AlterTableValidateConstraint{}
 
@@ -229,3 +231,33 @@ func (node *AlterTableDropNotNull) Format(buf *bytes.Buffer, f FmtFlags) {
 	FormatNode(buf, f, node.Column)
 	buf.WriteString(" DROP NOT NULL")
 }
+
+// AuditMode represents the auditing mode of a table, set by ALTER TABLE
+// ... EXPERIMENTAL_AUDIT.
+type AuditMode int
+
+// AuditMode values.
+const (
+	AuditModeDisable AuditMode = iota
+	AuditModeReadWrite
+)
+
+var auditModeName = [...]string{
+	AuditModeDisable:   "OFF",
+	AuditModeReadWrite: "READ WRITE",
+}
+
+func (m AuditMode) String() string {
+	return auditModeName[m]
+}
+
+// AlterTableSetAudit represents an EXPERIMENTAL_AUDIT SET command.
+type AlterTableSetAudit struct {
+	Mode AuditMode
+}
+
+// Format implements the NodeFormatter interface.
+func (node *AlterTableSetAudit) Format(buf *bytes.Buffer, f FmtFlags) {
+	buf.WriteString("EXPERIMENTAL_AUDIT SET ")
+	buf.WriteString(node.Mode.String())
+}
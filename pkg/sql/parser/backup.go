@@ -77,6 +77,35 @@ func (node *Restore) Format(buf *bytes.Buffer, f FmtFlags) {
 	}
 }
 
+// Import represents an IMPORT statement.
+type Import struct {
+	Table      NormalizableTableName
+	CreateDefs TableDefs
+	FileFormat string
+	Files      Exprs
+	Options    KVOptions
+}
+
+var _ Statement = &Import{}
+
+// Format implements the NodeFormatter interface.
+func (node *Import) Format(buf *bytes.Buffer, f FmtFlags) {
+	buf.WriteString("IMPORT TABLE ")
+	FormatNode(buf, f, node.Table)
+	buf.WriteString(" (")
+	FormatNode(buf, f, node.CreateDefs)
+	buf.WriteString(") ")
+	buf.WriteString(node.FileFormat)
+	buf.WriteString(" DATA (")
+	FormatNode(buf, f, node.Files)
+	buf.WriteString(")")
+	if node.Options != nil {
+		buf.WriteString(" WITH OPTIONS (")
+		FormatNode(buf, f, node.Options)
+		buf.WriteString(")")
+	}
+}
+
 // KVOption is a key-value option.
 type KVOption struct {
 	Key   string
@@ -79,11 +79,14 @@ const (
 var _ = NormalClass
 
 const (
+	categoryArray         = "Array"
 	categoryComparison    = "Comparison"
 	categoryCompatibility = "Compatibility"
 	categoryDateAndTime   = "Date and Time"
 	categoryIDGeneration  = "ID Generation"
+	categoryJSON          = "JSONB"
 	categoryMath          = "Math and Numeric"
+	categorySequences     = "Sequence"
 	categoryString        = "String and Byte"
 	categorySystemInfo    = "System Info"
 )
@@ -221,6 +224,7 @@ func init() {
 	initWindowBuiltins()
 	initGeneratorBuiltins()
 	initPGBuiltins()
+	initJSONBuiltins()
 
 	names := make([]string, 0, len(Builtins))
 	funDefs = make(map[string]*FunctionDefinition)
@@ -844,6 +848,154 @@ var Builtins = map[string][]Builtin{
 
 	"experimental_uuid_v4": {uuidV4Impl},
 	"uuid_v4":              {uuidV4Impl},
+	"gen_random_uuid":      {uuidV4Impl},
+
+	// Sequence functions.
+
+	"nextval": {
+		Builtin{
+			Types:            ArgTypes{{"sequence_name", TypeString}},
+			ReturnType:       fixedReturnType(TypeInt),
+			category:         categorySequences,
+			impure:           true,
+			distsqlBlacklist: true,
+			fn: func(ctx *EvalContext, args Datums) (Datum, error) {
+				name := MustBeDString(args[0])
+				seqName, err := ParseTableName(string(name))
+				if err != nil {
+					return nil, err
+				}
+				planner, err := sequencePlanner(ctx)
+				if err != nil {
+					return nil, err
+				}
+				res, err := planner.IncrementSequence(ctx.Ctx(), seqName)
+				if err != nil {
+					return nil, err
+				}
+				return NewDInt(DInt(res)), nil
+			},
+			Info: "Advances the given sequence and returns its new value.",
+		},
+	},
+
+	"currval": {
+		Builtin{
+			Types:            ArgTypes{{"sequence_name", TypeString}},
+			ReturnType:       fixedReturnType(TypeInt),
+			category:         categorySequences,
+			impure:           true,
+			distsqlBlacklist: true,
+			fn: func(ctx *EvalContext, args Datums) (Datum, error) {
+				name := MustBeDString(args[0])
+				seqName, err := ParseTableName(string(name))
+				if err != nil {
+					return nil, err
+				}
+				planner, err := sequencePlanner(ctx)
+				if err != nil {
+					return nil, err
+				}
+				res, err := planner.GetLatestValueInSessionForSequence(ctx.Ctx(), seqName)
+				if err != nil {
+					return nil, err
+				}
+				return NewDInt(DInt(res)), nil
+			},
+			Info: "Returns the latest value obtained with nextval for this sequence in this session.",
+		},
+	},
+
+	"setval": {
+		Builtin{
+			Types:            ArgTypes{{"sequence_name", TypeString}, {"value", TypeInt}},
+			ReturnType:       fixedReturnType(TypeInt),
+			category:         categorySequences,
+			impure:           true,
+			distsqlBlacklist: true,
+			fn: func(ctx *EvalContext, args Datums) (Datum, error) {
+				name := MustBeDString(args[0])
+				seqName, err := ParseTableName(string(name))
+				if err != nil {
+					return nil, err
+				}
+				newVal := MustBeDInt(args[1])
+				planner, err := sequencePlanner(ctx)
+				if err != nil {
+					return nil, err
+				}
+				if err := planner.SetSequenceValue(
+					ctx.Ctx(), seqName, int64(newVal), true /* isCalled */); err != nil {
+					return nil, err
+				}
+				return args[1], nil
+			},
+			Info: "Set the given sequence's current value. The next call to nextval will return " +
+				"`value + Increment`",
+		},
+		Builtin{
+			Types: ArgTypes{
+				{"sequence_name", TypeString}, {"value", TypeInt}, {"is_called", TypeBool},
+			},
+			ReturnType:       fixedReturnType(TypeInt),
+			category:         categorySequences,
+			impure:           true,
+			distsqlBlacklist: true,
+			fn: func(ctx *EvalContext, args Datums) (Datum, error) {
+				name := MustBeDString(args[0])
+				seqName, err := ParseTableName(string(name))
+				if err != nil {
+					return nil, err
+				}
+				isCalled := bool(*args[2].(*DBool))
+				newVal := MustBeDInt(args[1])
+				planner, err := sequencePlanner(ctx)
+				if err != nil {
+					return nil, err
+				}
+				if err := planner.SetSequenceValue(
+					ctx.Ctx(), seqName, int64(newVal), isCalled); err != nil {
+					return nil, err
+				}
+				return args[1], nil
+			},
+			Info: "Set the given sequence's current value. If is_called is false, the next call " +
+				"to nextval will return `value`; otherwise `value + Increment`.",
+		},
+	},
+
+	"family": {
+		Builtin{
+			Types:      ArgTypes{{"val", TypeINet}},
+			ReturnType: fixedReturnType(TypeInt),
+			fn: func(_ *EvalContext, args Datums) (Datum, error) {
+				return NewDInt(DInt(args[0].(*DIPAddr).Family)), nil
+			},
+			Info: "Extracts the IP family of the value; 4 for IPv4, 6 for IPv6.",
+		},
+	},
+
+	"host": {
+		Builtin{
+			Types:      ArgTypes{{"val", TypeINet}},
+			ReturnType: fixedReturnType(TypeString),
+			fn: func(_ *EvalContext, args Datums) (Datum, error) {
+				return NewDString(args[0].(*DIPAddr).Host()), nil
+			},
+			Info: "Extracts the address part of the combined address/prefixlen value as text.",
+		},
+	},
+
+	"masklen": {
+		Builtin{
+			Types:      ArgTypes{{"val", TypeINet}},
+			ReturnType: fixedReturnType(TypeInt),
+			fn: func(_ *EvalContext, args Datums) (Datum, error) {
+				return NewDInt(DInt(args[0].(*DIPAddr).Mask)), nil
+			},
+			Info: "Retrieves the prefix length stored in `val`.",
+		},
+	},
 
 	"greatest": {
 		Builtin{
@@ -1519,6 +1671,30 @@ var Builtins = map[string][]Builtin{
 		},
 	},
 
+	"array_append": arrayBuiltin(func(typ Type) Builtin {
+		return Builtin{
+			Types:      ArgTypes{{"array", TArray{typ}}, {"elem", typ}},
+			ReturnType: fixedReturnType(TArray{typ}),
+			category:   categoryArray,
+			fn: func(_ *EvalContext, args Datums) (Datum, error) {
+				return concatArrays(typ, MustBeDArray(args[0]).Array, args[1:2])
+			},
+			Info: "Appends `elem` to `array`, returning the result.",
+		}
+	}),
+
+	"array_cat": arrayBuiltin(func(typ Type) Builtin {
+		return Builtin{
+			Types:      ArgTypes{{"left", TArray{typ}}, {"right", TArray{typ}}},
+			ReturnType: fixedReturnType(TArray{typ}),
+			category:   categoryArray,
+			fn: func(_ *EvalContext, args Datums) (Datum, error) {
+				return concatArrays(typ, MustBeDArray(args[0]).Array, MustBeDArray(args[1]).Array)
+			},
+			Info: "Appends two arrays.",
+		}
+	}),
+
 	// Metadata functions.
 
 	"version": {
@@ -1595,6 +1771,23 @@ var Builtins = map[string][]Builtin{
 		},
 	},
 
+	"current_user": {
+		Builtin{
+			Types:            ArgTypes{},
+			ReturnType:       fixedReturnType(TypeString),
+			distsqlBlacklist: true,
+			category:         categorySystemInfo,
+			fn: func(ctx *EvalContext, args Datums) (Datum, error) {
+				if len(ctx.User) == 0 {
+					return DNull, nil
+				}
+				return NewDString(ctx.User), nil
+			},
+			Info: "Returns the current user. This function is called by CURRENT_USER " +
+				"and CURRENT_ROLE.",
+		},
+	},
+
 	"crdb_internal.force_internal_error": {
 		Builtin{
 			Types:      ArgTypes{{"msg", TypeString}},
@@ -1781,15 +1974,26 @@ var substringImpls = []Builtin{
 	},
 }
 
+// sequencePlanner returns the planner used by the sequence builtins.
+// Sequences can only be accessed from within a SQL session; in particular,
+// they cannot be used by a schema change backfill.
+func sequencePlanner(ctx *EvalContext) (EvalPlanner, error) {
+	if ctx.Planner == nil {
+		return nil, pgerror.NewError(pgerror.CodeFeatureNotSupportedError,
+			"sequence functions cannot be used outside of a SQL session")
+	}
+	return ctx.Planner, nil
+}
+
 var uuidV4Impl = Builtin{
 	Types:      ArgTypes{},
-	ReturnType: fixedReturnType(TypeBytes),
+	ReturnType: fixedReturnType(TypeUUID),
 	category:   categoryIDGeneration,
 	impure:     true,
 	fn: func(_ *EvalContext, args Datums) (Datum, error) {
-		return NewDBytes(DBytes(uuid.MakeV4().GetBytes())), nil
+		return NewDUuid(DUuid{uuid.MakeV4()}), nil
 	},
-	Info: "Returns a UUID.",
+	Info: "Generates a random UUID and returns it as a value of UUID type.",
 }
 
 var ceilImpl = []Builtin{
@@ -2413,6 +2617,31 @@ func arrayLength(arr *DArray, dim int64) Datum {
 
 var intOne = NewDInt(DInt(1))
 
+// arrayBuiltin returns one overload of an array builtin for each array
+// element type.
+func arrayBuiltin(impl func(Type) Builtin) []Builtin {
+	result := make([]Builtin, 0, len(TypesAnyNonArray))
+	for _, typ := range TypesAnyNonArray {
+		result = append(result, impl(typ))
+	}
+	return result
+}
+
+// concatArrays returns a new array of the given element type containing the
+// elements of left followed by those of right.
+func concatArrays(typ Type, left, right Datums) (Datum, error) {
+	result := NewDArray(typ)
+	result.Array = make(Datums, 0, len(left)+len(right))
+	for _, elems := range []Datums{left, right} {
+		for _, d := range elems {
+			if err := result.Append(d); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return result, nil
+}
+
 func arrayLower(arr *DArray, dim int64) Datum {
 	if arr.Len() == 0 || dim < 1 {
 		return DNull
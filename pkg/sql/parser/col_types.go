@@ -49,6 +49,9 @@ func (*DateColType) columnType()           {}
 func (*TimestampColType) columnType()      {}
 func (*TimestampTZColType) columnType()    {}
 func (*IntervalColType) columnType()       {}
+func (*JSONColType) columnType()           {}
+func (*UUIDColType) columnType()           {}
+func (*INetColType) columnType()           {}
 func (*StringColType) columnType()         {}
 func (*NameColType) columnType()           {}
 func (*BytesColType) columnType()          {}
@@ -66,6 +69,9 @@ func (*DateColType) castTargetType()           {}
 func (*TimestampColType) castTargetType()      {}
 func (*TimestampTZColType) castTargetType()    {}
 func (*IntervalColType) castTargetType()       {}
+func (*JSONColType) castTargetType()           {}
+func (*UUIDColType) castTargetType()           {}
+func (*INetColType) castTargetType()           {}
 func (*StringColType) castTargetType()         {}
 func (*NameColType) castTargetType()           {}
 func (*BytesColType) castTargetType()          {}
@@ -278,6 +284,42 @@ func (node *IntervalColType) Format(buf *bytes.Buffer, f FmtFlags) {
 	buf.WriteString("INTERVAL")
 }
 
+// Pre-allocated immutable JSON column type.
+var jsonColType = &JSONColType{}
+
+// JSONColType represents the JSONB type.
+type JSONColType struct {
+}
+
+// Format implements the NodeFormatter interface.
+func (node *JSONColType) Format(buf *bytes.Buffer, f FmtFlags) {
+	buf.WriteString("JSONB")
+}
+
+// Pre-allocated immutable UUID column type.
+var uuidColTypeUUID = &UUIDColType{}
+
+// UUIDColType represents a UUID type.
+type UUIDColType struct {
+}
+
+// Format implements the NodeFormatter interface.
+func (node *UUIDColType) Format(buf *bytes.Buffer, f FmtFlags) {
+	buf.WriteString("UUID")
+}
+
+// Pre-allocated immutable INET column type.
+var ipnetColTypeINet = &INetColType{}
+
+// INetColType represents an INET type.
+type INetColType struct {
+}
+
+// Format implements the NodeFormatter interface.
+func (node *INetColType) Format(buf *bytes.Buffer, f FmtFlags) {
+	buf.WriteString("INET")
+}
+
 // Pre-allocated immutable string column types.
 var (
 	stringColTypeChar    = &StringColType{Name: "CHAR"}
@@ -360,14 +402,11 @@ func (node *ArrayColType) Format(buf *bytes.Buffer, f FmtFlags) {
 }
 
 func arrayOf(colType ColumnType, boundsExprs Exprs) (ColumnType, error) {
-	switch colType {
-	case intColTypeInt:
-		return &ArrayColType{Name: "INT[]", ParamType: intColTypeInt, BoundsExprs: boundsExprs}, nil
-	case stringColTypeString:
-		return &ArrayColType{Name: "STRING[]", ParamType: stringColTypeString, BoundsExprs: boundsExprs}, nil
-	default:
-		return nil, errors.Errorf("cannot make array for column type %s", colType)
+	switch colType.(type) {
+	case *ArrayColType, *VectorColType:
+		return nil, errors.Errorf("nested arrays are not supported")
 	}
+	return &ArrayColType{Name: colType.String() + "[]", ParamType: colType, BoundsExprs: boundsExprs}, nil
 }
 
 // VectorColType is the base for VECTOR column types, which are Postgres's
@@ -461,6 +500,9 @@ func (node *DateColType) String() string           { return AsString(node) }
 func (node *TimestampColType) String() string      { return AsString(node) }
 func (node *TimestampTZColType) String() string    { return AsString(node) }
 func (node *IntervalColType) String() string       { return AsString(node) }
+func (node *JSONColType) String() string           { return AsString(node) }
+func (node *UUIDColType) String() string           { return AsString(node) }
+func (node *INetColType) String() string           { return AsString(node) }
 func (node *StringColType) String() string         { return AsString(node) }
 func (node *NameColType) String() string           { return AsString(node) }
 func (node *BytesColType) String() string          { return AsString(node) }
@@ -488,6 +530,12 @@ func DatumTypeToColumnType(t Type) (ColumnType, error) {
 		return timestampTzColTypeTimestampWithTZ, nil
 	case TypeInterval:
 		return intervalColTypeInterval, nil
+	case TypeJSON:
+		return jsonColType, nil
+	case TypeUUID:
+		return uuidColTypeUUID, nil
+	case TypeINet:
+		return ipnetColTypeINet, nil
 	case TypeDate:
 		return dateColTypeDate, nil
 	case TypeString:
@@ -544,6 +592,12 @@ func CastTargetToDatumType(t CastTargetType) Type {
 		return TypeTimestampTZ
 	case *IntervalColType:
 		return TypeInterval
+	case *JSONColType:
+		return TypeJSON
+	case *UUIDColType:
+		return TypeUUID
+	case *INetColType:
+		return TypeINet
 	case *CollatedStringColType:
 		return TCollatedString{Locale: ct.Locale}
 	case *ArrayColType:
@@ -368,9 +368,12 @@ var (
 		TypeTimestamp,
 		TypeTimestampTZ,
 		TypeInterval,
+		TypeJSON,
+		TypeUUID,
+		TypeINet,
 	}
-	strValAvailBytesString = []Type{TypeBytes, TypeString}
-	strValAvailBytes       = []Type{TypeBytes}
+	strValAvailBytesString = []Type{TypeBytes, TypeString, TypeUUID}
+	strValAvailBytes       = []Type{TypeBytes, TypeUUID}
 )
 
 // AvailableTypes implements the Constant interface.
@@ -434,6 +437,15 @@ func (expr *StrVal) ResolveAsType(ctx *SemaContext, typ Type) (Datum, error) {
 		return ParseDTimestampTZ(expr.s, ctx.getLocation(), time.Microsecond)
 	case TypeInterval:
 		return ParseDInterval(expr.s)
+	case TypeJSON:
+		return ParseDJSON(expr.s)
+	case TypeUUID:
+		if expr.bytesEsc {
+			return ParseDUuidFromBytes([]byte(expr.s))
+		}
+		return ParseDUuidFromString(expr.s)
+	case TypeINet:
+		return ParseDIPAddrFromINetString(expr.s)
 	default:
 		return nil, fmt.Errorf("could not resolve %T %v into a %T", expr, expr, typ)
 	}
@@ -39,3 +39,41 @@ func (node *CopyFrom) Format(buf *bytes.Buffer, f FmtFlags) {
 		buf.WriteString("STDIN")
 	}
 }
+
+// CopyTo represents a COPY TO statement.
+type CopyTo struct {
+	Table   NormalizableTableName
+	Columns UnresolvedNames
+	// Query is set instead of Table and Columns for COPY (query) TO.
+	Query  *Select
+	Stdout bool
+	// FileFormat is the name of the format of the data, or empty for the
+	// default text format.
+	FileFormat string
+}
+
+// Format implements the NodeFormatter interface.
+func (node *CopyTo) Format(buf *bytes.Buffer, f FmtFlags) {
+	buf.WriteString("COPY ")
+	if node.Query != nil {
+		buf.WriteByte('(')
+		FormatNode(buf, f, node.Query)
+		buf.WriteByte(')')
+	} else {
+		FormatNode(buf, f, node.Table)
+		if len(node.Columns) > 0 {
+			buf.WriteString(" (")
+			FormatNode(buf, f, node.Columns)
+			buf.WriteString(")")
+		}
+	}
+	buf.WriteString(" TO ")
+	if node.Stdout {
+		buf.WriteString("STDOUT")
+	}
+	if node.FileFormat != "" {
+		buf.WriteString(" WITH (FORMAT ")
+		FormatNode(buf, f, Name(node.FileFormat))
+		buf.WriteString(")")
+	}
+}
@@ -100,12 +100,14 @@ type CreateIndex struct {
 	Name        Name
 	Table       NormalizableTableName
 	Unique      bool
+	Inverted    bool
 	IfNotExists bool
 	Columns     IndexElemList
 	// Extra columns to be stored together with the indexed ones as an optimization
 	// for improved reading performance.
-	Storing    NameList
-	Interleave *InterleaveDef
+	Storing     NameList
+	Interleave  *InterleaveDef
+	PartitionBy *PartitionBy
 }
 
 // Format implements the NodeFormatter interface.
@@ -114,6 +116,9 @@ func (node *CreateIndex) Format(buf *bytes.Buffer, f FmtFlags) {
 	if node.Unique {
 		buf.WriteString("UNIQUE ")
 	}
+	if node.Inverted {
+		buf.WriteString("INVERTED ")
+	}
 	buf.WriteString("INDEX ")
 	if node.IfNotExists {
 		buf.WriteString("IF NOT EXISTS ")
@@ -135,6 +140,9 @@ func (node *CreateIndex) Format(buf *bytes.Buffer, f FmtFlags) {
 	if node.Interleave != nil {
 		FormatNode(buf, f, node.Interleave)
 	}
+	if node.PartitionBy != nil {
+		FormatNode(buf, f, node.PartitionBy)
+	}
 }
 
 // TableDef represents a column, index or constraint definition within a CREATE
@@ -196,6 +204,7 @@ type ColumnTableDef struct {
 		Table          NormalizableTableName
 		Col            Name
 		ConstraintName Name
+		Actions        ReferenceActions
 	}
 	Family struct {
 		Name        Name
@@ -271,6 +280,7 @@ func newColumnTableDef(
 			d.References.Table = t.Table
 			d.References.Col = t.Col
 			d.References.ConstraintName = c.Name
+			d.References.Actions = t.Actions
 		case *ColumnFamilyConstraint:
 			if d.HasColumnFamily() {
 				return nil, errors.Errorf("multiple column families specified for column %q", name)
@@ -353,6 +363,7 @@ func (node *ColumnTableDef) Format(buf *bytes.Buffer, f FmtFlags) {
 			FormatNode(buf, f, node.References.Col)
 			buf.WriteByte(')')
 		}
+		FormatNode(buf, f, node.References.Actions)
 	}
 	if node.HasColumnFamily() {
 		if node.Family.Create {
@@ -417,8 +428,9 @@ type ColumnCheckConstraint struct {
 
 // ColumnFKConstraint represents a FK-constaint on a column.
 type ColumnFKConstraint struct {
-	Table NormalizableTableName
-	Col   Name // empty-string means use PK
+	Table   NormalizableTableName
+	Col     Name // empty-string means use PK
+	Actions ReferenceActions
 }
 
 // ColumnFamilyConstraint represents FAMILY on a column.
@@ -431,10 +443,12 @@ type ColumnFamilyConstraint struct {
 // IndexTableDef represents an index definition within a CREATE TABLE
 // statement.
 type IndexTableDef struct {
-	Name       Name
-	Columns    IndexElemList
-	Storing    NameList
-	Interleave *InterleaveDef
+	Name        Name
+	Columns     IndexElemList
+	Storing     NameList
+	Interleave  *InterleaveDef
+	Inverted    bool
+	PartitionBy *PartitionBy
 }
 
 func (node *IndexTableDef) setName(name Name) {
@@ -443,6 +457,9 @@ func (node *IndexTableDef) setName(name Name) {
 
 // Format implements the NodeFormatter interface.
 func (node *IndexTableDef) Format(buf *bytes.Buffer, f FmtFlags) {
+	if node.Inverted {
+		buf.WriteString("INVERTED ")
+	}
 	buf.WriteString("INDEX ")
 	if node.Name != "" {
 		FormatNode(buf, f, node.Name)
@@ -459,6 +476,9 @@ func (node *IndexTableDef) Format(buf *bytes.Buffer, f FmtFlags) {
 	if node.Interleave != nil {
 		FormatNode(buf, f, node.Interleave)
 	}
+	if node.PartitionBy != nil {
+		FormatNode(buf, f, node.PartitionBy)
+	}
 }
 
 // ConstraintTableDef represents a constraint definition within a CREATE TABLE
@@ -502,6 +522,9 @@ func (node *UniqueConstraintTableDef) Format(buf *bytes.Buffer, f FmtFlags) {
 	if node.Interleave != nil {
 		FormatNode(buf, f, node.Interleave)
 	}
+	if node.PartitionBy != nil {
+		FormatNode(buf, f, node.PartitionBy)
+	}
 }
 
 // ForeignKeyConstraintTableDef represents a FOREIGN KEY constraint in the AST.
@@ -510,6 +533,7 @@ type ForeignKeyConstraintTableDef struct {
 	Table    NormalizableTableName
 	FromCols NameList
 	ToCols   NameList
+	Actions  ReferenceActions
 }
 
 // Format implements the NodeFormatter interface.
@@ -530,6 +554,51 @@ func (node *ForeignKeyConstraintTableDef) Format(buf *bytes.Buffer, f FmtFlags)
 		FormatNode(buf, f, node.ToCols)
 		buf.WriteByte(')')
 	}
+	FormatNode(buf, f, node.Actions)
+}
+
+// ReferenceAction is the action taken on the referencing rows of a
+// foreign key when the referenced row is deleted or updated.
+type ReferenceAction int
+
+// The values for ReferenceAction.
+const (
+	NoAction ReferenceAction = iota
+	Restrict
+	SetNull
+	SetDefault
+	Cascade
+)
+
+var referenceActionName = [...]string{
+	NoAction:   "NO ACTION",
+	Restrict:   "RESTRICT",
+	SetNull:    "SET NULL",
+	SetDefault: "SET DEFAULT",
+	Cascade:    "CASCADE",
+}
+
+func (ra ReferenceAction) String() string {
+	return referenceActionName[ra]
+}
+
+// ReferenceActions contains the actions of a foreign key for deletes and
+// updates of the referenced row.
+type ReferenceActions struct {
+	Delete ReferenceAction
+	Update ReferenceAction
+}
+
+// Format implements the NodeFormatter interface.
+func (node ReferenceActions) Format(buf *bytes.Buffer, f FmtFlags) {
+	if node.Delete != NoAction {
+		buf.WriteString(" ON DELETE ")
+		buf.WriteString(node.Delete.String())
+	}
+	if node.Update != NoAction {
+		buf.WriteString(" ON UPDATE ")
+		buf.WriteString(node.Update.String())
+	}
 }
 
 func (node *ForeignKeyConstraintTableDef) setName(name Name) {
@@ -614,11 +683,88 @@ func (node *InterleaveDef) Format(buf *bytes.Buffer, f FmtFlags) {
 	}
 }
 
+// PartitionBy represents a PARTITION BY definition within a CREATE TABLE,
+// CREATE INDEX or index definition.
+type PartitionBy struct {
+	Fields NameList
+	// Exactly one of List or Range is required to be non-empty.
+	List  []ListPartition
+	Range []RangePartition
+}
+
+// Format implements the NodeFormatter interface.
+func (node *PartitionBy) Format(buf *bytes.Buffer, f FmtFlags) {
+	buf.WriteString(" PARTITION BY ")
+	if node.List != nil {
+		buf.WriteString("LIST")
+	} else {
+		buf.WriteString("RANGE")
+	}
+	buf.WriteString(" (")
+	FormatNode(buf, f, node.Fields)
+	buf.WriteString(") (")
+	for i, p := range node.List {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		FormatNode(buf, f, p)
+	}
+	for i, p := range node.Range {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		FormatNode(buf, f, p)
+	}
+	buf.WriteString(")")
+}
+
+// ListPartition represents a PARTITION definition within a PARTITION BY LIST.
+type ListPartition struct {
+	Name Name
+	// Exprs contains a value (or a tuple of values, if the partitioning has
+	// more than one column) for each tuple in the partition, or DefaultVal to
+	// match all tuples not in another partition.
+	Exprs Exprs
+}
+
+// Format implements the NodeFormatter interface.
+func (node ListPartition) Format(buf *bytes.Buffer, f FmtFlags) {
+	buf.WriteString("PARTITION ")
+	FormatNode(buf, f, node.Name)
+	buf.WriteString(" VALUES IN (")
+	FormatNode(buf, f, node.Exprs)
+	buf.WriteByte(')')
+}
+
+// RangePartition represents a PARTITION definition within a PARTITION BY
+// RANGE.
+type RangePartition struct {
+	Name Name
+	// Exprs is the exclusive upper bound of the partition, with one value for
+	// each partitioning column. It is nil for MAXVALUE.
+	Exprs Exprs
+}
+
+// Format implements the NodeFormatter interface.
+func (node RangePartition) Format(buf *bytes.Buffer, f FmtFlags) {
+	buf.WriteString("PARTITION ")
+	FormatNode(buf, f, node.Name)
+	buf.WriteString(" VALUES < ")
+	if node.Exprs == nil {
+		buf.WriteString("MAXVALUE")
+	} else {
+		buf.WriteByte('(')
+		FormatNode(buf, f, node.Exprs)
+		buf.WriteByte(')')
+	}
+}
+
 // CreateTable represents a CREATE TABLE statement.
 type CreateTable struct {
 	IfNotExists   bool
 	Table         NormalizableTableName
 	Interleave    *InterleaveDef
+	PartitionBy   *PartitionBy
 	Defs          TableDefs
 	AsSource      *Select
 	AsColumnNames NameList // Only to be used in conjunction with AsSource
@@ -652,6 +798,9 @@ func (node *CreateTable) Format(buf *bytes.Buffer, f FmtFlags) {
 		if node.Interleave != nil {
 			FormatNode(buf, f, node.Interleave)
 		}
+		if node.PartitionBy != nil {
+			FormatNode(buf, f, node.PartitionBy)
+		}
 	}
 }
 
@@ -680,6 +829,34 @@ func (node *CreateUser) Format(buf *bytes.Buffer, f FmtFlags) {
 	}
 }
 
+// CreateRole represents a CREATE ROLE statement.
+type CreateRole struct {
+	Name Name
+}
+
+// Format implements the NodeFormatter interface.
+func (node *CreateRole) Format(buf *bytes.Buffer, f FmtFlags) {
+	buf.WriteString("CREATE ROLE ")
+	FormatNode(buf, f, node.Name)
+}
+
+// CreateStats represents a CREATE STATISTICS statement.
+type CreateStats struct {
+	Name        Name
+	ColumnNames NameList
+	Table       NormalizableTableName
+}
+
+// Format implements the NodeFormatter interface.
+func (node *CreateStats) Format(buf *bytes.Buffer, f FmtFlags) {
+	buf.WriteString("CREATE STATISTICS ")
+	FormatNode(buf, f, node.Name)
+	buf.WriteString(" ON ")
+	FormatNode(buf, f, node.ColumnNames)
+	buf.WriteString(" FROM ")
+	FormatNode(buf, f, node.Table)
+}
+
 // CreateView represents a CREATE VIEW statement.
 type CreateView struct {
 	Name        NormalizableTableName
@@ -702,3 +879,67 @@ func (node *CreateView) Format(buf *bytes.Buffer, f FmtFlags) {
 	buf.WriteString(" AS ")
 	FormatNode(buf, f, node.AsSource)
 }
+
+// CreateSequence represents a CREATE SEQUENCE statement.
+type CreateSequence struct {
+	IfNotExists bool
+	Name        NormalizableTableName
+	Options     SequenceOptions
+}
+
+// Format implements the NodeFormatter interface.
+func (node *CreateSequence) Format(buf *bytes.Buffer, f FmtFlags) {
+	buf.WriteString("CREATE SEQUENCE ")
+	if node.IfNotExists {
+		buf.WriteString("IF NOT EXISTS ")
+	}
+	FormatNode(buf, f, node.Name)
+	FormatNode(buf, f, node.Options)
+}
+
+// SequenceOptions represents a list of sequence options.
+type SequenceOptions []SequenceOption
+
+// Format implements the NodeFormatter interface.
+func (node SequenceOptions) Format(buf *bytes.Buffer, f FmtFlags) {
+	for _, option := range node {
+		buf.WriteByte(' ')
+		switch option.Name {
+		case SeqOptCycle, SeqOptNoCycle:
+			buf.WriteString(option.Name)
+		case SeqOptCache:
+			fmt.Fprintf(buf, "%s %d", option.Name, *option.IntVal)
+		case SeqOptIncrement:
+			fmt.Fprintf(buf, "%s BY %d", option.Name, *option.IntVal)
+		case SeqOptMinValue, SeqOptMaxValue:
+			if option.IntVal == nil {
+				fmt.Fprintf(buf, "NO %s", option.Name)
+			} else {
+				fmt.Fprintf(buf, "%s %d", option.Name, *option.IntVal)
+			}
+		case SeqOptStart:
+			fmt.Fprintf(buf, "%s WITH %d", option.Name, *option.IntVal)
+		default:
+			panic(fmt.Sprintf("unexpected sequence option: %v", option))
+		}
+	}
+}
+
+// SequenceOption represents an option on a CREATE SEQUENCE statement.
+type SequenceOption struct {
+	Name string
+	// IntVal is the value of the option, if it takes one. It is nil for
+	// NO MINVALUE and NO MAXVALUE.
+	IntVal *int64
+}
+
+// Names of options on CREATE SEQUENCE.
+const (
+	SeqOptCycle     = "CYCLE"
+	SeqOptNoCycle   = "NO CYCLE"
+	SeqOptCache     = "CACHE"
+	SeqOptIncrement = "INCREMENT"
+	SeqOptMinValue  = "MINVALUE"
+	SeqOptMaxValue  = "MAXVALUE"
+	SeqOptStart     = "START"
+)
@@ -37,6 +37,9 @@ import (
 	"github.com/cockroachdb/apd"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/util/duration"
+	"github.com/cockroachdb/cockroach/pkg/util/ipaddr"
+	"github.com/cockroachdb/cockroach/pkg/util/json"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 )
 
 var (
@@ -184,6 +187,42 @@ func makeParseError(s string, typ Type, err error) error {
 	return fmt.Errorf("could not parse '%s' as type %s%s", s, typ, suffix)
 }
 
+// ParseStringAs parses s as a datum of type t. It is the inverse of
+// formatting a datum of type t without quotes, so it is suitable for reading
+// values back out of text formats such as CSV.
+func ParseStringAs(t Type, s string, location *time.Location) (Datum, error) {
+	switch t {
+	case TypeBool:
+		return ParseDBool(s)
+	case TypeInt:
+		return ParseDInt(s)
+	case TypeFloat:
+		return ParseDFloat(s)
+	case TypeDecimal:
+		return ParseDDecimal(s)
+	case TypeString:
+		return NewDString(s), nil
+	case TypeBytes:
+		return NewDBytes(DBytes(s)), nil
+	case TypeDate:
+		return ParseDDate(s, location)
+	case TypeTimestamp:
+		return ParseDTimestamp(s, time.Microsecond)
+	case TypeTimestampTZ:
+		return ParseDTimestampTZ(s, location, time.Microsecond)
+	case TypeInterval:
+		return ParseDInterval(s)
+	case TypeUUID:
+		return ParseDUuidFromString(s)
+	case TypeINet:
+		return ParseDIPAddrFromINetString(s)
+	case TypeJSON:
+		return ParseDJSON(s)
+	default:
+		return nil, makeParseError(s, t, errors.New("unsupported type"))
+	}
+}
+
 func makeUnsupportedComparisonMessage(d1, d2 Datum) string {
 	return fmt.Sprintf("unsupported comparison: %s to %s", d1.ResolvedType(), d2.ResolvedType())
 }
@@ -947,6 +986,235 @@ func (d *DBytes) Size() uintptr {
 	return unsafe.Sizeof(*d) + uintptr(len(*d))
 }
 
+// DUuid is the UUID Datum.
+type DUuid struct {
+	uuid.UUID
+}
+
+// NewDUuid is a helper routine to create a *DUuid initialized from its
+// argument.
+func NewDUuid(d DUuid) *DUuid {
+	return &d
+}
+
+// ParseDUuidFromString parses and returns the *DUuid Datum value represented
+// by the provided input string, or an error.
+func ParseDUuidFromString(s string) (*DUuid, error) {
+	uv, err := uuid.FromString(s)
+	if err != nil {
+		return nil, makeParseError(s, TypeUUID, err)
+	}
+	return NewDUuid(DUuid{uv}), nil
+}
+
+// ParseDUuidFromBytes parses and returns the *DUuid Datum value represented
+// by the provided input bytes, or an error.
+func ParseDUuidFromBytes(b []byte) (*DUuid, error) {
+	uv, err := uuid.FromBytes(b)
+	if err != nil {
+		return nil, makeParseError(string(b), TypeUUID, err)
+	}
+	return NewDUuid(DUuid{uv}), nil
+}
+
+// ResolvedType implements the TypedExpr interface.
+func (*DUuid) ResolvedType() Type {
+	return TypeUUID
+}
+
+// Compare implements the Datum interface.
+func (d *DUuid) Compare(ctx *EvalContext, other Datum) int {
+	if other == DNull {
+		// NULL is less than any non-NULL value.
+		return 1
+	}
+	v, ok := other.(*DUuid)
+	if !ok {
+		panic(makeUnsupportedComparisonMessage(d, other))
+	}
+	return bytes.Compare(d.GetBytes(), v.GetBytes())
+}
+
+// Prev implements the Datum interface.
+func (d *DUuid) Prev() (Datum, bool) {
+	if d.IsMin() {
+		return nil, false
+	}
+	u := d.UUID
+	for i := len(u.UUID) - 1; i >= 0; i-- {
+		u.UUID[i]--
+		if u.UUID[i] != 0xff {
+			break
+		}
+	}
+	return NewDUuid(DUuid{u}), true
+}
+
+// Next implements the Datum interface.
+func (d *DUuid) Next() (Datum, bool) {
+	if d.IsMax() {
+		return nil, false
+	}
+	u := d.UUID
+	for i := len(u.UUID) - 1; i >= 0; i-- {
+		u.UUID[i]++
+		if u.UUID[i] != 0 {
+			break
+		}
+	}
+	return NewDUuid(DUuid{u}), true
+}
+
+var (
+	dMinUuid = NewDUuid(DUuid{})
+	dMaxUuid = func() *DUuid {
+		d := NewDUuid(DUuid{})
+		for i := range d.UUID.UUID {
+			d.UUID.UUID[i] = 0xff
+		}
+		return d
+	}()
+)
+
+// IsMax implements the Datum interface.
+func (d *DUuid) IsMax() bool {
+	return d.UUID == dMaxUuid.UUID
+}
+
+// IsMin implements the Datum interface.
+func (d *DUuid) IsMin() bool {
+	return d.UUID == dMinUuid.UUID
+}
+
+// min implements the Datum interface.
+func (d *DUuid) min() (Datum, bool) {
+	return dMinUuid, true
+}
+
+// max implements the Datum interface.
+func (d *DUuid) max() (Datum, bool) {
+	return dMaxUuid, true
+}
+
+// AmbiguousFormat implements the Datum interface.
+func (*DUuid) AmbiguousFormat() bool { return true }
+
+// Format implements the NodeFormatter interface.
+func (d *DUuid) Format(buf *bytes.Buffer, f FmtFlags) {
+	if !f.bareStrings {
+		buf.WriteByte('\'')
+	}
+	buf.WriteString(d.UUID.String())
+	if !f.bareStrings {
+		buf.WriteByte('\'')
+	}
+}
+
+// Size implements the Datum interface.
+func (d *DUuid) Size() uintptr {
+	return unsafe.Sizeof(*d)
+}
+
+// DIPAddr is the IPAddr Datum.
+type DIPAddr struct {
+	ipaddr.IPAddr
+}
+
+// NewDIPAddr is a helper routine to create a *DIPAddr initialized from its
+// argument.
+func NewDIPAddr(d DIPAddr) *DIPAddr {
+	return &d
+}
+
+// ParseDIPAddrFromINetString parses and returns the *DIPAddr Datum value
+// represented by the provided input INet string, or an error.
+func ParseDIPAddrFromINetString(s string) (*DIPAddr, error) {
+	ipAddr, err := ipaddr.ParseINet(s)
+	if err != nil {
+		return nil, makeParseError(s, TypeINet, err)
+	}
+	return NewDIPAddr(DIPAddr{ipAddr}), nil
+}
+
+// ResolvedType implements the TypedExpr interface.
+func (*DIPAddr) ResolvedType() Type {
+	return TypeINet
+}
+
+// Compare implements the Datum interface.
+func (d *DIPAddr) Compare(ctx *EvalContext, other Datum) int {
+	if other == DNull {
+		// NULL is less than any non-NULL value.
+		return 1
+	}
+	v, ok := other.(*DIPAddr)
+	if !ok {
+		panic(makeUnsupportedComparisonMessage(d, other))
+	}
+	return d.IPAddr.Compare(v.IPAddr)
+}
+
+// Prev implements the Datum interface.
+func (d *DIPAddr) Prev() (Datum, bool) {
+	return nil, false
+}
+
+// Next implements the Datum interface.
+func (d *DIPAddr) Next() (Datum, bool) {
+	return nil, false
+}
+
+var (
+	dMinIPAddr = NewDIPAddr(DIPAddr{ipaddr.IPAddr{Family: ipaddr.IPv4family}})
+	dMaxIPAddr = func() *DIPAddr {
+		d := NewDIPAddr(DIPAddr{ipaddr.IPAddr{Family: ipaddr.IPv6family}})
+		for i := range d.Addr {
+			d.Addr[i] = 0xff
+		}
+		d.Mask = ipaddr.IPv6family.MaxMask()
+		return d
+	}()
+)
+
+// IsMax implements the Datum interface.
+func (d *DIPAddr) IsMax() bool {
+	return d.IPAddr == dMaxIPAddr.IPAddr
+}
+
+// IsMin implements the Datum interface.
+func (d *DIPAddr) IsMin() bool {
+	return d.IPAddr == dMinIPAddr.IPAddr
+}
+
+// min implements the Datum interface.
+func (d *DIPAddr) min() (Datum, bool) {
+	return dMinIPAddr, true
+}
+
+// max implements the Datum interface.
+func (d *DIPAddr) max() (Datum, bool) {
+	return dMaxIPAddr, true
+}
+
+// AmbiguousFormat implements the Datum interface.
+func (*DIPAddr) AmbiguousFormat() bool { return true }
+
+// Format implements the NodeFormatter interface.
+func (d *DIPAddr) Format(buf *bytes.Buffer, f FmtFlags) {
+	if !f.bareStrings {
+		buf.WriteByte('\'')
+	}
+	buf.WriteString(d.IPAddr.String())
+	if !f.bareStrings {
+		buf.WriteByte('\'')
+	}
+}
+
+// Size implements the Datum interface.
+func (d *DIPAddr) Size() uintptr {
+	return unsafe.Sizeof(*d)
+}
+
 // DDate is the date Datum represented as the number of days after
 // the Unix epoch.
 type DDate int64
@@ -1584,6 +1852,148 @@ func (d *DInterval) Size() uintptr {
 	return unsafe.Sizeof(*d)
 }
 
+// DJSON is the JSON Datum.
+type DJSON struct {
+	json.JSON
+}
+
+// NewDJSON is a helper routine to create a DJSON initialized from its argument.
+func NewDJSON(j json.JSON) *DJSON {
+	return &DJSON{j}
+}
+
+// ParseDJSON takes a string of JSON and returns a DJSON value.
+func ParseDJSON(s string) (*DJSON, error) {
+	j, err := json.ParseJSON(s)
+	if err != nil {
+		return nil, makeParseError(s, TypeJSON, err)
+	}
+	return NewDJSON(j), nil
+}
+
+// MustBeDJSON attempts to retrieve a DJSON from an Expr, panicking if the
+// assertion fails.
+func MustBeDJSON(e Expr) DJSON {
+	i, ok := e.(*DJSON)
+	if !ok {
+		panic(fmt.Errorf("expected *DJSON, found %T", e))
+	}
+	return *i
+}
+
+// AsJSON converts a datum into its JSON representation. Numbers, strings,
+// booleans and NULL map onto the corresponding JSON types, arrays onto JSON
+// arrays, and other values onto the JSON string of their text representation.
+func AsJSON(d Datum) (json.JSON, error) {
+	switch t := d.(type) {
+	case *DBool:
+		return json.FromBool(bool(*t)), nil
+	case *DInt:
+		return json.FromInt(int64(*t)), nil
+	case *DFloat:
+		return json.FromFloat64(float64(*t))
+	case *DDecimal:
+		return json.FromDecimal(t.Decimal), nil
+	case *DString:
+		return json.FromString(string(*t)), nil
+	case *DCollatedString:
+		return json.FromString(t.Contents), nil
+	case *DJSON:
+		return t.JSON, nil
+	case *DArray:
+		elems := make([]json.JSON, len(t.Array))
+		for i, e := range t.Array {
+			var err error
+			if elems[i], err = AsJSON(e); err != nil {
+				return nil, err
+			}
+		}
+		return json.FromArray(elems), nil
+	case *DBytes, *DDate, *DTimestamp, *DTimestampTZ, *DInterval, *DUuid, *DIPAddr, *DOid:
+		return json.FromString(AsStringWithFlags(t, FmtBareStrings)), nil
+	case *DOidWrapper:
+		return AsJSON(t.Wrapped)
+	case dNull:
+		return json.NullJSONValue, nil
+	default:
+		return nil, errors.Errorf("unexpected type %T for AsJSON", d)
+	}
+}
+
+// ResolvedType implements the TypedExpr interface.
+func (*DJSON) ResolvedType() Type {
+	return TypeJSON
+}
+
+// Compare implements the Datum interface.
+func (d *DJSON) Compare(ctx *EvalContext, other Datum) int {
+	if other == DNull {
+		// NULL is less than any non-NULL value.
+		return 1
+	}
+	v, ok := other.(*DJSON)
+	if !ok {
+		panic(makeUnsupportedComparisonMessage(d, other))
+	}
+	return d.JSON.Compare(v.JSON)
+}
+
+// Prev implements the Datum interface.
+func (d *DJSON) Prev() (Datum, bool) {
+	return nil, false
+}
+
+// Next implements the Datum interface.
+func (d *DJSON) Next() (Datum, bool) {
+	return nil, false
+}
+
+// IsMax implements the Datum interface.
+func (d *DJSON) IsMax() bool {
+	return false
+}
+
+// IsMin implements the Datum interface.
+func (d *DJSON) IsMin() bool {
+	return d.JSON == json.NullJSONValue
+}
+
+// max implements the Datum interface.
+func (d *DJSON) max() (Datum, bool) {
+	return nil, false
+}
+
+// min implements the Datum interface.
+func (d *DJSON) min() (Datum, bool) {
+	return &DJSON{json.NullJSONValue}, true
+}
+
+// IsComposite implements the CompositeDatum interface. Numbers are key
+// encoded in their canonical form, so 1.0 and 1 have the same key encoding.
+func (d *DJSON) IsComposite() bool {
+	return json.CanonicalString(d.JSON) != d.JSON.String()
+}
+
+// AmbiguousFormat implements the Datum interface.
+func (*DJSON) AmbiguousFormat() bool { return true }
+
+// Format implements the NodeFormatter interface.
+func (d *DJSON) Format(buf *bytes.Buffer, f FmtFlags) {
+	// Bare strings are used when the value doesn't need to be parsed back by
+	// SQL (e.g. pgwire text results), in which case no escaping is needed.
+	s := d.JSON.String()
+	if f.bareStrings {
+		buf.WriteString(s)
+	} else {
+		encodeSQLStringWithFlags(buf, s, f)
+	}
+}
+
+// Size implements the Datum interface.
+func (d *DJSON) Size() uintptr {
+	return unsafe.Sizeof(*d) + d.JSON.Size()
+}
+
 // DTuple is the tuple Datum.
 type DTuple struct {
 	D Datums
@@ -59,6 +59,21 @@ func (node *DropDatabase) Format(buf *bytes.Buffer, f FmtFlags) {
 	FormatNode(buf, f, node.Name)
 }
 
+// DropRole represents a DROP ROLE statement.
+type DropRole struct {
+	Names    NameList
+	IfExists bool
+}
+
+// Format implements the NodeFormatter interface.
+func (node *DropRole) Format(buf *bytes.Buffer, f FmtFlags) {
+	buf.WriteString("DROP ROLE ")
+	if node.IfExists {
+		buf.WriteString("IF EXISTS ")
+	}
+	FormatNode(buf, f, node.Names)
+}
+
 // DropIndex represents a DROP INDEX statement.
 type DropIndex struct {
 	IndexList    TableNameWithIndexList
@@ -99,6 +114,26 @@ func (node *DropTable) Format(buf *bytes.Buffer, f FmtFlags) {
 	}
 }
 
+// DropSequence represents a DROP SEQUENCE statement.
+type DropSequence struct {
+	Names        TableNameReferences
+	IfExists     bool
+	DropBehavior DropBehavior
+}
+
+// Format implements the NodeFormatter interface.
+func (node *DropSequence) Format(buf *bytes.Buffer, f FmtFlags) {
+	buf.WriteString("DROP SEQUENCE ")
+	if node.IfExists {
+		buf.WriteString("IF EXISTS ")
+	}
+	FormatNode(buf, f, node.Names)
+	if node.DropBehavior != DropDefault {
+		buf.WriteByte(' ')
+		buf.WriteString(node.DropBehavior.String())
+	}
+}
+
 // DropView represents a DROP VIEW statement.
 type DropView struct {
 	Names        TableNameReferences
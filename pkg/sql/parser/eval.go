@@ -34,6 +34,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util"
 	"github.com/cockroachdb/cockroach/pkg/util/duration"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/json"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 )
 
@@ -224,6 +225,18 @@ var BinOps = map[BinaryOperator]binOpOverload{
 				return NewDInt(MustBeDInt(left) & MustBeDInt(right)), nil
 			},
 		},
+		BinOp{
+			LeftType:   TypeINet,
+			RightType:  TypeINet,
+			ReturnType: TypeINet,
+			fn: func(_ *EvalContext, left Datum, right Datum) (Datum, error) {
+				ipAddr, err := left.(*DIPAddr).And(right.(*DIPAddr).IPAddr)
+				if err != nil {
+					return nil, err
+				}
+				return NewDIPAddr(DIPAddr{ipAddr}), nil
+			},
+		},
 	},
 
 	Bitor: {
@@ -883,6 +896,14 @@ var BinOps = map[BinaryOperator]binOpOverload{
 				return NewDInt(MustBeDInt(left) << uint(MustBeDInt(right))), nil
 			},
 		},
+		BinOp{
+			LeftType:   TypeINet,
+			RightType:  TypeINet,
+			ReturnType: TypeBool,
+			fn: func(_ *EvalContext, left Datum, right Datum) (Datum, error) {
+				return MakeDBool(DBool(left.(*DIPAddr).ContainedBy(right.(*DIPAddr).IPAddr))), nil
+			},
+		},
 	},
 
 	RShift: {
@@ -894,6 +915,52 @@ var BinOps = map[BinaryOperator]binOpOverload{
 				return NewDInt(MustBeDInt(left) >> uint(MustBeDInt(right))), nil
 			},
 		},
+		BinOp{
+			LeftType:   TypeINet,
+			RightType:  TypeINet,
+			ReturnType: TypeBool,
+			fn: func(_ *EvalContext, left Datum, right Datum) (Datum, error) {
+				return MakeDBool(DBool(left.(*DIPAddr).Contains(right.(*DIPAddr).IPAddr))), nil
+			},
+		},
+	},
+
+	JSONFetchVal: {
+		BinOp{
+			LeftType:   TypeJSON,
+			RightType:  TypeString,
+			ReturnType: TypeJSON,
+			fn: func(_ *EvalContext, left Datum, right Datum) (Datum, error) {
+				return jsonFetchResult(left.(*DJSON).FetchValKey(string(MustBeDString(right)))), nil
+			},
+		},
+		BinOp{
+			LeftType:   TypeJSON,
+			RightType:  TypeInt,
+			ReturnType: TypeJSON,
+			fn: func(_ *EvalContext, left Datum, right Datum) (Datum, error) {
+				return jsonFetchResult(left.(*DJSON).FetchValIdx(int(MustBeDInt(right)))), nil
+			},
+		},
+	},
+
+	JSONFetchText: {
+		BinOp{
+			LeftType:   TypeJSON,
+			RightType:  TypeString,
+			ReturnType: TypeString,
+			fn: func(_ *EvalContext, left Datum, right Datum) (Datum, error) {
+				return jsonFetchTextResult(left.(*DJSON).FetchValKey(string(MustBeDString(right)))), nil
+			},
+		},
+		BinOp{
+			LeftType:   TypeJSON,
+			RightType:  TypeInt,
+			ReturnType: TypeString,
+			fn: func(_ *EvalContext, left Datum, right Datum) (Datum, error) {
+				return jsonFetchTextResult(left.(*DJSON).FetchValIdx(int(MustBeDInt(right)))), nil
+			},
+		},
 	},
 
 	Pow: {
@@ -1129,6 +1196,21 @@ var CmpOps = map[ComparisonOperator]cmpOpOverload{
 			RightType: TypeInterval,
 			fn:        cmpOpScalarEQFn,
 		},
+		CmpOp{
+			LeftType:  TypeJSON,
+			RightType: TypeJSON,
+			fn:        cmpOpScalarEQFn,
+		},
+		CmpOp{
+			LeftType:  TypeUUID,
+			RightType: TypeUUID,
+			fn:        cmpOpScalarEQFn,
+		},
+		CmpOp{
+			LeftType:  TypeINet,
+			RightType: TypeINet,
+			fn:        cmpOpScalarEQFn,
+		},
 		CmpOp{
 			LeftType:  TypeOid,
 			RightType: TypeOid,
@@ -1259,6 +1341,21 @@ var CmpOps = map[ComparisonOperator]cmpOpOverload{
 			RightType: TypeInterval,
 			fn:        cmpOpScalarLTFn,
 		},
+		CmpOp{
+			LeftType:  TypeJSON,
+			RightType: TypeJSON,
+			fn:        cmpOpScalarLTFn,
+		},
+		CmpOp{
+			LeftType:  TypeUUID,
+			RightType: TypeUUID,
+			fn:        cmpOpScalarLTFn,
+		},
+		CmpOp{
+			LeftType:  TypeINet,
+			RightType: TypeINet,
+			fn:        cmpOpScalarLTFn,
+		},
 		CmpOp{
 			LeftType:  TypeTuple,
 			RightType: TypeTuple,
@@ -1384,6 +1481,21 @@ var CmpOps = map[ComparisonOperator]cmpOpOverload{
 			RightType: TypeInterval,
 			fn:        cmpOpScalarLEFn,
 		},
+		CmpOp{
+			LeftType:  TypeJSON,
+			RightType: TypeJSON,
+			fn:        cmpOpScalarLEFn,
+		},
+		CmpOp{
+			LeftType:  TypeUUID,
+			RightType: TypeUUID,
+			fn:        cmpOpScalarLEFn,
+		},
+		CmpOp{
+			LeftType:  TypeINet,
+			RightType: TypeINet,
+			fn:        cmpOpScalarLEFn,
+		},
 		CmpOp{
 			LeftType:  TypeTuple,
 			RightType: TypeTuple,
@@ -1405,6 +1517,9 @@ var CmpOps = map[ComparisonOperator]cmpOpOverload{
 		makeEvalTupleIn(TypeTimestamp),
 		makeEvalTupleIn(TypeTimestampTZ),
 		makeEvalTupleIn(TypeInterval),
+		makeEvalTupleIn(TypeJSON),
+		makeEvalTupleIn(TypeUUID),
+		makeEvalTupleIn(TypeINet),
 		makeEvalTupleIn(TypeTuple),
 	},
 
@@ -1460,6 +1575,48 @@ var CmpOps = map[ComparisonOperator]cmpOpOverload{
 			},
 		},
 	},
+
+	Contains: {
+		CmpOp{
+			LeftType:  TypeJSON,
+			RightType: TypeJSON,
+			fn: func(_ *EvalContext, left Datum, right Datum) (Datum, error) {
+				return MakeDBool(DBool(left.(*DJSON).Contains(right.(*DJSON).JSON))), nil
+			},
+		},
+	},
+
+	JSONExists: {
+		CmpOp{
+			LeftType:  TypeJSON,
+			RightType: TypeString,
+			fn: func(_ *EvalContext, left Datum, right Datum) (Datum, error) {
+				return MakeDBool(DBool(left.(*DJSON).Exists(string(MustBeDString(right))))), nil
+			},
+		},
+	},
+}
+
+// jsonFetchResult converts the result of fetching a JSON field or element
+// to a Datum, using NULL if there was nothing to fetch.
+func jsonFetchResult(j json.JSON) Datum {
+	if j == nil {
+		return DNull
+	}
+	return NewDJSON(j)
+}
+
+// jsonFetchTextResult is like jsonFetchResult, but converts the fetched JSON
+// value to text. Like in Postgres, a JSON null is converted to NULL.
+func jsonFetchTextResult(j json.JSON) Datum {
+	if j == nil {
+		return DNull
+	}
+	s, ok := j.AsText()
+	if !ok {
+		return DNull
+	}
+	return NewDString(s)
 }
 
 func isNaN(d Datum) bool {
@@ -1680,6 +1837,19 @@ type EvalPlanner interface {
 	// QualifyWithDatabase resolves a possibly unqualified table name into a
 	// table name that is qualified by database.
 	QualifyWithDatabase(ctx context.Context, t *NormalizableTableName) (*TableName, error)
+
+	// IncrementSequence increments the given sequence and returns the
+	// result. It returns an error if the given name is not a sequence.
+	IncrementSequence(ctx context.Context, seqName *TableName) (int64, error)
+
+	// GetLatestValueInSessionForSequence returns the value most recently
+	// obtained by nextval() for the given sequence in this session.
+	GetLatestValueInSessionForSequence(ctx context.Context, seqName *TableName) (int64, error)
+
+	// SetSequenceValue sets the sequence's value. If isCalled is false, the
+	// next call to nextval() returns newVal; otherwise it returns newVal
+	// plus the sequence's increment.
+	SetSequenceValue(ctx context.Context, seqName *TableName, newVal int64, isCalled bool) error
 }
 
 // contextHolder is a wrapper that returns a Context.
@@ -1707,6 +1877,9 @@ type EvalContext struct {
 	Location **time.Location
 	// Database is the database in the current Session.
 	Database string
+	// User is the user of the current Session. It is not marshalled by
+	// DistSQL.
+	User string
 	// SearchPath is the search path for databases used when encountering an
 	// unqualified table name. Names in the search path are normalized already.
 	// This must not be modified (this is shared from the session).
@@ -2214,7 +2387,7 @@ func (expr *CastExpr) Eval(ctx *EvalContext) (Datum, error) {
 		switch t := d.(type) {
 		case *DBool, *DInt, *DFloat, *DDecimal, dNull:
 			s = d.String()
-		case *DTimestamp, *DTimestampTZ, *DDate:
+		case *DTimestamp, *DTimestampTZ, *DDate, *DUuid, *DIPAddr:
 			s = AsStringWithFlags(d, FmtBareStrings)
 		case *DInterval:
 			// When converting an interval to string, we need a string representation
@@ -2232,6 +2405,8 @@ func (expr *CastExpr) Eval(ctx *EvalContext) (Datum, error) {
 			s = string(*t)
 		case *DOid:
 			s = t.name
+		case *DJSON:
+			s = t.JSON.String()
 		}
 		switch c := expr.Type.(type) {
 		case *StringColType:
@@ -2260,6 +2435,30 @@ func (expr *CastExpr) Eval(ctx *EvalContext) (Datum, error) {
 			return NewDBytes(DBytes(t.Contents)), nil
 		case *DBytes:
 			return d, nil
+		case *DUuid:
+			return NewDBytes(DBytes(t.GetBytes())), nil
+		}
+
+	case *UUIDColType:
+		switch t := d.(type) {
+		case *DString:
+			return ParseDUuidFromString(string(*t))
+		case *DCollatedString:
+			return ParseDUuidFromString(t.Contents)
+		case *DBytes:
+			return ParseDUuidFromBytes([]byte(*t))
+		case *DUuid:
+			return d, nil
+		}
+
+	case *INetColType:
+		switch t := d.(type) {
+		case *DString:
+			return ParseDIPAddrFromINetString(string(*t))
+		case *DCollatedString:
+			return ParseDIPAddrFromINetString(t.Contents)
+		case *DIPAddr:
+			return d, nil
 		}
 
 	case *DateColType:
@@ -2326,6 +2525,15 @@ func (expr *CastExpr) Eval(ctx *EvalContext) (Datum, error) {
 		case *DInterval:
 			return d, nil
 		}
+	case *JSONColType:
+		switch v := d.(type) {
+		case *DString:
+			return ParseDJSON(string(*v))
+		case *DCollatedString:
+			return ParseDJSON(v.Contents)
+		case *DJSON:
+			return d, nil
+		}
 	case *OidColType:
 		switch v := d.(type) {
 		case *DOid:
@@ -2806,6 +3014,21 @@ func (t *DInterval) Eval(_ *EvalContext) (Datum, error) {
 	return t, nil
 }
 
+// Eval implements the TypedExpr interface.
+func (t *DJSON) Eval(_ *EvalContext) (Datum, error) {
+	return t, nil
+}
+
+// Eval implements the TypedExpr interface.
+func (t *DUuid) Eval(_ *EvalContext) (Datum, error) {
+	return t, nil
+}
+
+// Eval implements the TypedExpr interface.
+func (t *DIPAddr) Eval(_ *EvalContext) (Datum, error) {
+	return t, nil
+}
+
 // Eval implements the TypedExpr interface.
 func (t dNull) Eval(_ *EvalContext) (Datum, error) {
 	return t, nil
@@ -2926,6 +3149,9 @@ func foldComparisonExpr(
 		// Note the special handling of NULLs and IS is needed before this
 		// expression fold.
 		return EQ, left, right, false, false
+	case ContainedBy:
+		// ContainedBy(left, right) is implemented as Contains(right, left)
+		return Contains, right, left, true, false
 	case IsNot:
 		// IsNot(left, right) is implemented as !EQ(left, right)
 		//
@@ -295,6 +295,9 @@ const (
 	IsNotDistinctFrom
 	Is
 	IsNot
+	Contains
+	ContainedBy
+	JSONExists
 
 	// The following operators will always be used with an associated SubOperator.
 	// If Go had algebraic data types they would be defined in a self-contained
@@ -337,6 +340,9 @@ var comparisonOpName = [...]string{
 	IsNotDistinctFrom: "IS NOT DISTINCT FROM",
 	Is:                "IS",
 	IsNot:             "IS NOT",
+	Contains:          "@>",
+	ContainedBy:       "<@",
+	JSONExists:        "?",
 	Any:               "ANY",
 	Some:              "SOME",
 	All:               "ALL",
@@ -750,6 +756,8 @@ const (
 	Concat
 	LShift
 	RShift
+	JSONFetchVal
+	JSONFetchText
 )
 
 var binaryOpName = [...]string{
@@ -766,6 +774,9 @@ var binaryOpName = [...]string{
 	Concat:   "||",
 	LShift:   "<<",
 	RShift:   ">>",
+
+	JSONFetchVal:  "->",
+	JSONFetchText: "->>",
 }
 
 func (i BinaryOperator) String() string {
@@ -1081,12 +1092,15 @@ var (
 	decimalCastTypes = []Type{TypeNull, TypeBool, TypeInt, TypeFloat, TypeDecimal, TypeString, TypeCollatedString,
 		TypeTimestamp, TypeTimestampTZ, TypeDate, TypeInterval}
 	stringCastTypes = []Type{TypeNull, TypeBool, TypeInt, TypeFloat, TypeDecimal, TypeString, TypeCollatedString,
-		TypeBytes, TypeTimestamp, TypeTimestampTZ, TypeInterval, TypeDate, TypeOid}
-	bytesCastTypes     = []Type{TypeNull, TypeString, TypeCollatedString, TypeBytes}
+		TypeBytes, TypeTimestamp, TypeTimestampTZ, TypeInterval, TypeDate, TypeOid, TypeJSON, TypeUUID, TypeINet}
+	bytesCastTypes     = []Type{TypeNull, TypeString, TypeCollatedString, TypeBytes, TypeUUID}
 	dateCastTypes      = []Type{TypeNull, TypeString, TypeCollatedString, TypeDate, TypeTimestamp, TypeTimestampTZ, TypeInt}
 	timestampCastTypes = []Type{TypeNull, TypeString, TypeCollatedString, TypeDate, TypeTimestamp, TypeTimestampTZ, TypeInt}
 	intervalCastTypes  = []Type{TypeNull, TypeString, TypeCollatedString, TypeInt, TypeInterval}
 	oidCastTypes       = []Type{TypeNull, TypeString, TypeCollatedString, TypeInt, TypeOid}
+	jsonCastTypes      = []Type{TypeNull, TypeString, TypeCollatedString, TypeJSON}
+	uuidCastTypes      = []Type{TypeNull, TypeString, TypeCollatedString, TypeBytes, TypeUUID}
+	inetCastTypes      = []Type{TypeNull, TypeString, TypeCollatedString, TypeINet}
 )
 
 // validCastTypes returns a set of types that can be cast into the provided type.
@@ -1110,6 +1124,12 @@ func validCastTypes(t Type) []Type {
 		return timestampCastTypes
 	case TypeInterval:
 		return intervalCastTypes
+	case TypeJSON:
+		return jsonCastTypes
+	case TypeUUID:
+		return uuidCastTypes
+	case TypeINet:
+		return inetCastTypes
 	case TypeOid, TypeRegClass, TypeRegNamespace, TypeRegProc, TypeRegProcedure, TypeRegType:
 		return oidCastTypes
 	default:
@@ -1221,10 +1241,13 @@ func (node *DDecimal) String() string         { return AsString(node) }
 func (node *DFloat) String() string           { return AsString(node) }
 func (node *DInt) String() string             { return AsString(node) }
 func (node *DInterval) String() string        { return AsString(node) }
+func (node *DJSON) String() string            { return AsString(node) }
 func (node *DString) String() string          { return AsString(node) }
 func (node *DCollatedString) String() string  { return AsString(node) }
 func (node *DTimestamp) String() string       { return AsString(node) }
 func (node *DTimestampTZ) String() string     { return AsString(node) }
+func (node *DUuid) String() string            { return AsString(node) }
+func (node *DIPAddr) String() string          { return AsString(node) }
 func (node *DTuple) String() string           { return AsString(node) }
 func (node *DArray) String() string           { return AsString(node) }
 func (node *DTable) String() string           { return AsString(node) }
@@ -19,6 +19,8 @@ package parser
 import (
 	"errors"
 	"fmt"
+
+	"github.com/cockroachdb/cockroach/pkg/util/json"
 )
 
 // Table generators, also called "set-generating functions", are
@@ -80,6 +82,7 @@ type ValueGenerator interface {
 
 var _ ValueGenerator = &seriesValueGenerator{}
 var _ ValueGenerator = &arrayValueGenerator{}
+var _ ValueGenerator = &jsonValueGenerator{}
 
 func initGeneratorBuiltins() {
 	// Add all windows to the Builtins map after a few sanity checks.
@@ -127,6 +130,30 @@ var Generators = map[string][]Builtin{
 			"Returns the input array as a set of rows",
 		),
 	},
+	"jsonb_array_elements": {
+		makeGeneratorBuiltin(
+			ArgTypes{{"input", TypeJSON}},
+			TTuple{TypeJSON},
+			makeJSONArrayElementsGenerator(false),
+			"Expands a JSON array to a set of JSON values.",
+		),
+	},
+	"jsonb_array_elements_text": {
+		makeGeneratorBuiltin(
+			ArgTypes{{"input", TypeJSON}},
+			TTuple{TypeString},
+			makeJSONArrayElementsGenerator(true),
+			"Expands a JSON array to a set of text values.",
+		),
+	},
+	"jsonb_object_keys": {
+		makeGeneratorBuiltin(
+			ArgTypes{{"input", TypeJSON}},
+			TTuple{TypeString},
+			makeJSONObjectKeysGenerator,
+			"Returns the set of keys in the outermost JSON object.",
+		),
+	},
 }
 
 func makeGeneratorBuiltin(in ArgTypes, ret TTuple, g generatorFactory, info string) Builtin {
@@ -238,3 +265,68 @@ func (s *arrayValueGenerator) Next() (bool, error) {
 func (s *arrayValueGenerator) Values() Datums {
 	return Datums{s.array.Array[s.nextIndex]}
 }
+
+func makeJSONArrayElementsGenerator(asText bool) generatorFactory {
+	return func(_ *EvalContext, args Datums) (ValueGenerator, error) {
+		elems, ok := json.AsArray(MustBeDJSON(args[0]).JSON)
+		if !ok {
+			return nil, errors.New("cannot extract elements from a non-array")
+		}
+		typ := TypeJSON
+		if asText {
+			typ = TypeString
+		}
+		values := make(Datums, len(elems))
+		for i, e := range elems {
+			if asText {
+				values[i] = jsonFetchTextResult(e)
+			} else {
+				values[i] = NewDJSON(e)
+			}
+		}
+		return &jsonValueGenerator{typ: typ, values: values}, nil
+	}
+}
+
+func makeJSONObjectKeysGenerator(_ *EvalContext, args Datums) (ValueGenerator, error) {
+	keys, ok := json.ObjectKeys(MustBeDJSON(args[0]).JSON)
+	if !ok {
+		return nil, errors.New("cannot call jsonb_object_keys on a non-object")
+	}
+	values := make(Datums, len(keys))
+	for i, k := range keys {
+		values[i] = NewDString(k)
+	}
+	return &jsonValueGenerator{typ: TypeString, values: values}, nil
+}
+
+// jsonValueGenerator is a value generator that returns each of a precomputed
+// list of values derived from a JSON document.
+type jsonValueGenerator struct {
+	typ       Type
+	values    Datums
+	nextIndex int
+}
+
+// ColumnTypes implements the ValueGenerator interface.
+func (s *jsonValueGenerator) ColumnTypes() TTuple { return TTuple{s.typ} }
+
+// Start implements the ValueGenerator interface.
+func (s *jsonValueGenerator) Start() error {
+	s.nextIndex = -1
+	return nil
+}
+
+// Close implements the ValueGenerator interface.
+func (s *jsonValueGenerator) Close() {}
+
+// Next implements the ValueGenerator interface.
+func (s *jsonValueGenerator) Next() (bool, error) {
+	s.nextIndex++
+	return s.nextIndex < len(s.values), nil
+}
+
+// Values implements the ValueGenerator interface.
+func (s *jsonValueGenerator) Values() Datums {
+	return Datums{s.values[s.nextIndex]}
+}
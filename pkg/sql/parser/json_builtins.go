@@ -0,0 +1,200 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package parser
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/cockroachdb/cockroach/pkg/util/json"
+)
+
+func initJSONBuiltins() {
+	for k, v := range jsonBuiltins {
+		for i := range v {
+			v[i].category = categoryJSON
+		}
+		Builtins[k] = v
+	}
+}
+
+var errJSONObjectOddArgs = errors.New("argument list must have even number of elements")
+
+// See https://www.postgresql.org/docs/9.6/static/functions-json.html.
+var jsonBuiltins = map[string][]Builtin{
+	"to_jsonb": {
+		Builtin{
+			Types:      ArgTypes{{"val", TypeAny}},
+			ReturnType: fixedReturnType(TypeJSON),
+			fn: func(_ *EvalContext, args Datums) (Datum, error) {
+				if args[0] == DNull {
+					return DNull, nil
+				}
+				j, err := AsJSON(args[0])
+				if err != nil {
+					return nil, err
+				}
+				return NewDJSON(j), nil
+			},
+			Info: "Returns the value as JSON.",
+		},
+	},
+
+	"jsonb_build_array": {
+		Builtin{
+			Types:      VariadicType{Typ: TypeAny},
+			ReturnType: fixedReturnType(TypeJSON),
+			fn: func(_ *EvalContext, args Datums) (Datum, error) {
+				elems := make([]json.JSON, len(args))
+				for i, arg := range args {
+					var err error
+					if elems[i], err = AsJSON(arg); err != nil {
+						return nil, err
+					}
+				}
+				return NewDJSON(json.FromArray(elems)), nil
+			},
+			Info: "Builds a possibly-heterogeneously-typed JSON array out of a variadic argument list.",
+		},
+	},
+
+	"jsonb_build_object": {
+		Builtin{
+			Types:      VariadicType{Typ: TypeAny},
+			ReturnType: fixedReturnType(TypeJSON),
+			fn: func(_ *EvalContext, args Datums) (Datum, error) {
+				if len(args)%2 != 0 {
+					return nil, errJSONObjectOddArgs
+				}
+				m := make(map[string]json.JSON, len(args)/2)
+				for i := 0; i < len(args); i += 2 {
+					if args[i] == DNull {
+						return nil, errors.Errorf("argument %d cannot be null", i+1)
+					}
+					key, err := AsJSON(args[i])
+					if err != nil {
+						return nil, err
+					}
+					keyText, _ := key.AsText()
+					if m[keyText], err = AsJSON(args[i+1]); err != nil {
+						return nil, err
+					}
+				}
+				return NewDJSON(json.FromMap(m)), nil
+			},
+			Info: "Builds a JSON object out of a variadic argument list. By convention, the " +
+				"argument list consists of alternating keys and values.",
+		},
+	},
+
+	"jsonb_typeof": {
+		Builtin{
+			Types:      ArgTypes{{"val", TypeJSON}},
+			ReturnType: fixedReturnType(TypeString),
+			fn: func(_ *EvalContext, args Datums) (Datum, error) {
+				return NewDString(MustBeDJSON(args[0]).Type().String()), nil
+			},
+			Info: "Returns the type of the outermost JSON value as a text string.",
+		},
+	},
+
+	"jsonb_array_length": {
+		Builtin{
+			Types:      ArgTypes{{"json", TypeJSON}},
+			ReturnType: fixedReturnType(TypeInt),
+			fn: func(_ *EvalContext, args Datums) (Datum, error) {
+				j := MustBeDJSON(args[0])
+				elems, ok := json.AsArray(j.JSON)
+				if !ok {
+					return nil, errors.Errorf("cannot get array length of a non-array")
+				}
+				return NewDInt(DInt(len(elems))), nil
+			},
+			Info: "Returns the number of elements in the outermost JSON array.",
+		},
+	},
+
+	"jsonb_pretty": {
+		Builtin{
+			Types:      ArgTypes{{"val", TypeJSON}},
+			ReturnType: fixedReturnType(TypeString),
+			fn: func(_ *EvalContext, args Datums) (Datum, error) {
+				s, err := json.Pretty(MustBeDJSON(args[0]).JSON)
+				if err != nil {
+					return nil, err
+				}
+				return NewDString(s), nil
+			},
+			Info: "Returns the given JSON value as a STRING indented and with newlines.",
+		},
+	},
+
+	"jsonb_extract_path": {
+		Builtin{
+			Types:      VariadicType{FixedTypes: []Type{TypeJSON}, Typ: TypeString},
+			ReturnType: fixedReturnType(TypeJSON),
+			fn: func(_ *EvalContext, args Datums) (Datum, error) {
+				j, ok := jsonExtractPath(args)
+				if !ok {
+					return DNull, nil
+				}
+				return NewDJSON(j), nil
+			},
+			Info: "Returns the JSON value pointed to by the variadic arguments.",
+		},
+	},
+
+	"jsonb_extract_path_text": {
+		Builtin{
+			Types:      VariadicType{FixedTypes: []Type{TypeJSON}, Typ: TypeString},
+			ReturnType: fixedReturnType(TypeString),
+			fn: func(_ *EvalContext, args Datums) (Datum, error) {
+				j, ok := jsonExtractPath(args)
+				if !ok {
+					return DNull, nil
+				}
+				return jsonFetchTextResult(j), nil
+			},
+			Info: "Returns the JSON value as text pointed to by the variadic arguments.",
+		},
+	},
+}
+
+// jsonExtractPath follows the path given by args[1:] from the JSON value in
+// args[0]. Path elements are used as object keys, or as array indexes if they
+// can be parsed as integers. False is returned if any argument is NULL or if
+// the path doesn't exist.
+func jsonExtractPath(args Datums) (json.JSON, bool) {
+	if args[0] == DNull {
+		return nil, false
+	}
+	j := MustBeDJSON(args[0]).JSON
+	for _, arg := range args[1:] {
+		if arg == DNull {
+			return nil, false
+		}
+		key := string(MustBeDString(arg))
+		next := j.FetchValKey(key)
+		if _, isArray := json.AsArray(j); isArray {
+			if idx, err := ParseDInt(key); err == nil {
+				next = j.FetchValIdx(int(*idx))
+			}
+		}
+		if next == nil {
+			return nil, false
+		}
+		j = next
+	}
+	return j, true
+}
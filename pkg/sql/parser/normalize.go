@@ -449,6 +449,7 @@ func (expr *ComparisonExpr) normalize(v *normalizeVisitor) TypedExpr {
 		SimilarTo, NotSimilarTo,
 		RegMatch, NotRegMatch,
 		RegIMatch, NotRegIMatch,
+		Contains, ContainedBy, JSONExists,
 		Any, Some, All:
 		if expr.TypedLeft() == DNull || expr.TypedRight() == DNull {
 			return DNull
@@ -716,6 +717,33 @@ func ContainsVars(expr Expr) bool {
 	return v.containsVars
 }
 
+type containsImpureVisitor struct {
+	containsImpure bool
+}
+
+var _ Visitor = &containsImpureVisitor{}
+
+func (v *containsImpureVisitor) VisitPre(expr Expr) (recurse bool, newExpr Expr) {
+	if f, ok := expr.(*FuncExpr); ok && f.IsImpure() {
+		v.containsImpure = true
+	}
+	if v.containsImpure {
+		return false, expr
+	}
+	return true, expr
+}
+
+func (*containsImpureVisitor) VisitPost(expr Expr) Expr { return expr }
+
+// ContainsImpureFunctions returns true if the expression calls any impure
+// function, i.e. a function whose result or side effects may differ
+// between evaluations (e.g. now() or nextval()).
+func ContainsImpureFunctions(expr Expr) bool {
+	v := containsImpureVisitor{containsImpure: false}
+	WalkExprConst(&v, expr)
+	return v.containsImpure
+}
+
 // DecimalOne represents the constant 1 as DECIMAL.
 var DecimalOne DDecimal
 
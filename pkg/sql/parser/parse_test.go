@@ -78,7 +78,12 @@ func TestParse(t *testing.T) {
 		{`CREATE UNIQUE INDEX a ON b (c)`},
 		{`CREATE UNIQUE INDEX a ON b (c) STORING (d)`},
 		{`CREATE UNIQUE INDEX a ON b (c) INTERLEAVE IN PARENT d (e, f)`},
+		{`CREATE INDEX a ON b (c) PARTITION BY LIST (c) (PARTITION p1 VALUES IN (1), PARTITION p2 VALUES IN (2, 3))`},
+		{`CREATE INDEX IF NOT EXISTS a ON b (c, d) PARTITION BY RANGE (c, d) (PARTITION p1 VALUES < (1, 2))`},
 		{`CREATE UNIQUE INDEX a ON b.c (d)`},
+		{`CREATE INVERTED INDEX a ON b (c)`},
+		{`CREATE INVERTED INDEX a ON b.c (d)`},
+		{`CREATE INVERTED INDEX IF NOT EXISTS a ON b (c)`},
 
 		{`CREATE TABLE a ()`},
 		{`CREATE TABLE a (b INT)`},
@@ -89,6 +94,14 @@ func TestParse(t *testing.T) {
 		{`CREATE TABLE a (b VARCHAR(3))`},
 		{`CREATE TABLE a (b STRING)`},
 		{`CREATE TABLE a (b STRING(3))`},
+		{`CREATE TABLE a (b JSONB)`},
+		{`CREATE TABLE a (b JSONB, INVERTED INDEX (b))`},
+		{`CREATE TABLE a (b JSONB, INVERTED INDEX c (b))`},
+		{`CREATE TABLE a (b UUID)`},
+		{`CREATE TABLE a (b UUID PRIMARY KEY DEFAULT gen_random_uuid())`},
+		{`CREATE TABLE a (b INET)`},
+		{`CREATE TABLE a (b INT[])`},
+		{`CREATE TABLE a (b STRING[], c TIMESTAMP[])`},
 		{`CREATE TABLE a (b FLOAT)`},
 		{`CREATE TABLE a (b SERIAL)`},
 		{`CREATE TABLE a (b SMALLSERIAL)`},
@@ -116,6 +129,9 @@ func TestParse(t *testing.T) {
 		{`CREATE TABLE a (b INT, c TEXT, FOREIGN KEY (b, c) REFERENCES other)`},
 		{`CREATE TABLE a (b INT, c TEXT, FOREIGN KEY (b, c) REFERENCES other (x, y))`},
 		{`CREATE TABLE a (b INT, c TEXT, CONSTRAINT s FOREIGN KEY (b, c) REFERENCES other (x, y))`},
+		{`CREATE TABLE a (b INT, c TEXT, FOREIGN KEY (b) REFERENCES other ON DELETE CASCADE)`},
+		{`CREATE TABLE a (b INT, c TEXT, FOREIGN KEY (b) REFERENCES other ON UPDATE SET NULL)`},
+		{`CREATE TABLE a (b INT, c TEXT, FOREIGN KEY (b, c) REFERENCES other (x, y) ON DELETE SET DEFAULT ON UPDATE RESTRICT)`},
 		{`CREATE TABLE a (b INT, c TEXT, INDEX (b, c))`},
 		{`CREATE TABLE a (b INT, c TEXT, INDEX d (b, c))`},
 		{`CREATE TABLE a (b INT, c TEXT, CONSTRAINT d UNIQUE (b, c))`},
@@ -126,6 +142,8 @@ func TestParse(t *testing.T) {
 		{`CREATE TABLE a (b INT, c INT REFERENCES foo)`},
 		{`CREATE TABLE a (b INT, c INT CONSTRAINT ref REFERENCES foo)`},
 		{`CREATE TABLE a (b INT, c INT REFERENCES foo (bar))`},
+		{`CREATE TABLE a (b INT, c INT REFERENCES foo ON DELETE CASCADE)`},
+		{`CREATE TABLE a (b INT, c INT REFERENCES foo (bar) ON DELETE SET NULL ON UPDATE CASCADE)`},
 		{`CREATE TABLE a (b INT, INDEX (b) STORING (c))`},
 		{`CREATE TABLE a (b INT, c TEXT, INDEX (b ASC, c DESC) STORING (c))`},
 		{`CREATE TABLE a (b INT, INDEX (b) INTERLEAVE IN PARENT c (d, e))`},
@@ -133,6 +151,12 @@ func TestParse(t *testing.T) {
 		{`CREATE TABLE a (b INT, c STRING, FAMILY foo (b), FAMILY (c))`},
 		{`CREATE TABLE a (b INT) INTERLEAVE IN PARENT foo (c, d)`},
 		{`CREATE TABLE a (b INT) INTERLEAVE IN PARENT foo (c) CASCADE`},
+		{`CREATE TABLE a (b INT) PARTITION BY LIST (b) (PARTITION p1 VALUES IN (1, 2), PARTITION p2 VALUES IN (DEFAULT))`},
+		{`CREATE TABLE a (b INT, c INT) PARTITION BY LIST (b, c) (PARTITION p1 VALUES IN ((1, 2), (3, 4)))`},
+		{`CREATE TABLE a (b INT) PARTITION BY RANGE (b) (PARTITION p1 VALUES < (1), PARTITION p2 VALUES < MAXVALUE)`},
+		{`CREATE TABLE a (b INT) INTERLEAVE IN PARENT foo (b) PARTITION BY RANGE (b) (PARTITION p1 VALUES < (1))`},
+		{`CREATE TABLE a (b INT, c STRING, INDEX (c) PARTITION BY LIST (c) (PARTITION p1 VALUES IN ('x')))`},
+		{`CREATE TABLE a (b INT, c STRING, CONSTRAINT d UNIQUE (c) PARTITION BY RANGE (c) (PARTITION p1 VALUES < ('x')))`},
 		{`CREATE TABLE a.b (b INT)`},
 		{`CREATE TABLE IF NOT EXISTS a (b INT)`},
 
@@ -160,6 +184,23 @@ func TestParse(t *testing.T) {
 		{`CREATE VIEW a (x, y) AS VALUES (1, 'one'), (2, 'two')`},
 		{`CREATE VIEW a AS TABLE b`},
 
+		{`CREATE SEQUENCE a`},
+		{`CREATE SEQUENCE a.b`},
+		{`CREATE SEQUENCE IF NOT EXISTS a`},
+		{`CREATE SEQUENCE a NO CYCLE`},
+		{`CREATE SEQUENCE a CACHE 10`},
+		{`CREATE SEQUENCE a INCREMENT BY 5`},
+		{`CREATE SEQUENCE a INCREMENT BY -1`},
+		{`CREATE SEQUENCE a MINVALUE -100 MAXVALUE 100`},
+		{`CREATE SEQUENCE a NO MINVALUE NO MAXVALUE`},
+		{`CREATE SEQUENCE a START WITH 1000`},
+		{`CREATE SEQUENCE a INCREMENT BY 2 MINVALUE 1 MAXVALUE 9 START WITH 3 CACHE 2`},
+
+		{`CREATE ROLE foo`},
+
+		{`CREATE STATISTICS a ON col1 FROM t`},
+		{`CREATE STATISTICS a ON col1, col2 FROM d.t`},
+
 		{`DELETE FROM a`},
 		{`DELETE FROM a.b`},
 		{`DELETE FROM a WHERE a = b`},
@@ -198,6 +239,13 @@ func TestParse(t *testing.T) {
 		{`DROP VIEW IF EXISTS a, b RESTRICT`},
 		{`DROP VIEW a.b CASCADE`},
 		{`DROP VIEW a, b CASCADE`},
+		{`DROP SEQUENCE a`},
+		{`DROP SEQUENCE a.b, c`},
+		{`DROP SEQUENCE IF EXISTS a RESTRICT`},
+		{`DROP SEQUENCE a CASCADE`},
+		{`DROP ROLE foo`},
+		{`DROP ROLE foo, bar`},
+		{`DROP ROLE IF EXISTS foo`},
 
 		{`EXPLAIN SELECT 1`},
 		{`EXPLAIN EXPLAIN SELECT 1`},
@@ -226,6 +274,20 @@ func TestParse(t *testing.T) {
 		{`SHOW CONSTRAINTS FROM a.b.c`},
 		{`SHOW TABLES FROM a; SHOW COLUMNS FROM b`},
 		{`SHOW USERS`},
+		{`SHOW CLUSTER QUERIES`},
+		{`SHOW LOCAL QUERIES`},
+		{`CANCEL QUERY 'f6f0d2a2d8db4b5b0000000100000001'`},
+		{`CANCEL QUERY $1`},
+		{`SHOW CLUSTER SESSIONS`},
+		{`SHOW LOCAL SESSIONS`},
+		{`CANCEL SESSION 'f6f0d2a2d8db4b5b0000000100000001'`},
+		{`CANCEL SESSION $1`},
+		{`PAUSE JOB 1`},
+		{`PAUSE JOB $1`},
+		{`RESUME JOB 1`},
+		{`RESUME JOB $1`},
+		{`CANCEL JOB 1`},
+		{`CANCEL JOB $1`},
 		{`SHOW TESTING_RANGES FROM TABLE d.t`},
 		{`SHOW TESTING_RANGES FROM TABLE t`},
 		{`SHOW TESTING_RANGES FROM INDEX d.t@i`},
@@ -269,6 +331,9 @@ func TestParse(t *testing.T) {
 		{`GRANT SELECT, INSERT ON DATABASE bar TO foo, bar, baz`},
 		{`GRANT SELECT, INSERT ON DATABASE db1, db2 TO foo, bar, baz`},
 		{`GRANT SELECT, INSERT ON DATABASE db1, db2 TO "test-user"`},
+		{`GRANT rolea TO usera`},
+		{`GRANT rolea, roleb TO usera, userb`},
+		{`GRANT rolea TO usera WITH ADMIN OPTION`},
 
 		// Tables are the default, but can also be specified with
 		// REVOKE x ON TABLE y. However, the stringer does not output TABLE.
@@ -278,6 +343,9 @@ func TestParse(t *testing.T) {
 		{`REVOKE ALL ON DATABASE foo FROM root, test`},
 		{`REVOKE SELECT, INSERT ON DATABASE bar FROM foo, bar, baz`},
 		{`REVOKE SELECT, INSERT ON DATABASE db1, db2 FROM foo, bar, baz`},
+		{`REVOKE rolea FROM usera`},
+		{`REVOKE rolea, roleb FROM usera, userb`},
+		{`REVOKE ADMIN OPTION FOR rolea FROM usera`},
 
 		{`INSERT INTO a VALUES (1)`},
 		{`INSERT INTO a.b VALUES (1)`},
@@ -379,6 +447,9 @@ func TestParse(t *testing.T) {
 		{`SELECT '1':::INT`},
 
 		{`SELECT '1'::INT`},
+		{`SELECT '63616b65-2d69-732d-612d-6c6965212121'::UUID`},
+		{`SELECT '192.168.1.2/24'::INET`},
+		{`SELECT family(a), host(a), masklen(a) FROM t`},
 		{`SELECT BOOL 'foo'`},
 		{`SELECT INT 'foo'`},
 		{`SELECT REAL 'foo'`},
@@ -453,6 +524,11 @@ func TestParse(t *testing.T) {
 		{`SELECT a FROM t WHERE a ~ b`},
 		{`SELECT a FROM t WHERE a !~ b`},
 		{`SELECT a FROM t WHERE a ~* c`},
+		{`SELECT a FROM t WHERE a @> b`},
+		{`SELECT a FROM t WHERE a <@ b`},
+		{`SELECT a FROM t WHERE a ? b`},
+		{`SELECT a -> b FROM t`},
+		{`SELECT a ->> b FROM t`},
 		{`SELECT a FROM t WHERE a !~* c`},
 		{`SELECT a FROM t WHERE a BETWEEN b AND c`},
 		{`SELECT a FROM t WHERE a NOT BETWEEN b AND c`},
@@ -519,6 +595,17 @@ func TestParse(t *testing.T) {
 		{`SELECT a FROM t INTERSECT SELECT 1 FROM t`},
 		{`SELECT a FROM t INTERSECT ALL SELECT 1 FROM t`},
 
+		{`WITH a AS (SELECT 1) SELECT * FROM a`},
+		{`WITH a (x, y) AS (SELECT 1, 2), b AS (SELECT y FROM a) SELECT * FROM b`},
+		{`WITH RECURSIVE a (n) AS (SELECT 1 UNION ALL SELECT n + 1 FROM a WHERE n < 10) SELECT * FROM a`},
+		{`WITH a AS (SELECT 1) SELECT * FROM a ORDER BY 1 LIMIT 1`},
+		{`SELECT * FROM (WITH a AS (SELECT 1) SELECT * FROM a)`},
+		{`WITH a AS (SELECT 1) INSERT INTO t SELECT * FROM a`},
+		{`WITH a AS (SELECT 1) UPSERT INTO t SELECT * FROM a`},
+		{`WITH a AS (SELECT 1) UPDATE t SET b = 1 WHERE c IN (SELECT * FROM a)`},
+		{`WITH a AS (SELECT 1) DELETE FROM t WHERE b IN (SELECT * FROM a) RETURNING b`},
+		{`WITH a AS (INSERT INTO t VALUES (1) RETURNING b) SELECT * FROM a`},
+
 		{`SELECT a FROM t1 JOIN t2 ON a = b`},
 		{`SELECT a FROM t1 JOIN t2 USING (a)`},
 		{`SELECT a FROM t1 LEFT JOIN t2 ON a = b`},
@@ -633,6 +720,8 @@ func TestParse(t *testing.T) {
 		{`ALTER TABLE a DROP CONSTRAINT b CASCADE`},
 		{`ALTER TABLE a DROP CONSTRAINT IF EXISTS b RESTRICT`},
 		{`ALTER TABLE a VALIDATE CONSTRAINT a`},
+		{`ALTER TABLE a EXPERIMENTAL_AUDIT SET READ WRITE`},
+		{`ALTER TABLE a EXPERIMENTAL_AUDIT SET OFF`},
 
 		{`ALTER TABLE a ALTER COLUMN b SET DEFAULT 42`},
 		{`ALTER TABLE a ALTER COLUMN b SET DEFAULT NULL`},
@@ -642,6 +731,12 @@ func TestParse(t *testing.T) {
 
 		{`COPY t FROM STDIN`},
 		{`COPY t (a, b, c) FROM STDIN`},
+		{`COPY t TO STDOUT`},
+		{`COPY t (a, b, c) TO STDOUT`},
+		{`COPY (SELECT a FROM t WHERE b > 1) TO STDOUT`},
+		{`COPY t TO STDOUT WITH (FORMAT csv)`},
+		{`COPY (VALUES (1)) TO STDOUT WITH (FORMAT text)`},
+		{`COPY ((SELECT 1)) TO STDOUT`},
 
 		{`ALTER TABLE a SPLIT AT VALUES (1)`},
 		{`ALTER TABLE a SPLIT AT SELECT * FROM t`},
@@ -678,6 +773,8 @@ func TestParse(t *testing.T) {
 		{`RESTORE DATABASE foo, baz FROM 'bar' AS OF SYSTEM TIME '1'`},
 		{`BACKUP foo TO 'bar' WITH OPTIONS ('key1', 'key2'='value')`},
 		{`RESTORE foo FROM 'bar' WITH OPTIONS ('key1', 'key2'='value')`},
+		{`IMPORT TABLE foo (id INT PRIMARY KEY, email STRING, age INT) CSV DATA ('path/to/some/file', $1) WITH OPTIONS ('delimiter'='|')`},
+		{`IMPORT TABLE foo (id INT, INDEX (id)) CSV DATA ('a', 'b')`},
 		{`SET ROW (1, true, NULL)`},
 	}
 	for _, d := range testData {
@@ -706,11 +803,23 @@ func TestParse2(t *testing.T) {
 			`CREATE DATABASE a TEMPLATE = 'template0'`},
 		{`CREATE DATABASE a TEMPLATE = invalid`,
 			`CREATE DATABASE a TEMPLATE = 'invalid'`},
+		{`CREATE SEQUENCE a INCREMENT 5 START 3`,
+			`CREATE SEQUENCE a INCREMENT BY 5 START WITH 3`},
+		{`CREATE SEQUENCE a START WITH +1 MINVALUE 0`,
+			`CREATE SEQUENCE a START WITH 1 MINVALUE 0`},
 		{`CREATE TABLE a (b INT, UNIQUE INDEX foo (b))`,
 			`CREATE TABLE a (b INT, CONSTRAINT foo UNIQUE (b))`},
 		{`CREATE TABLE a (b INT, UNIQUE INDEX foo (b) INTERLEAVE IN PARENT c (d))`,
 			`CREATE TABLE a (b INT, CONSTRAINT foo UNIQUE (b) INTERLEAVE IN PARENT c (d))`},
 		{`CREATE INDEX ON a (b) COVERING (c)`, `CREATE INDEX ON a (b) STORING (c)`},
+		{`CREATE TABLE a (b INT, FOREIGN KEY (b) REFERENCES other ON UPDATE CASCADE ON DELETE SET NULL)`,
+			`CREATE TABLE a (b INT, FOREIGN KEY (b) REFERENCES other ON DELETE SET NULL ON UPDATE CASCADE)`},
+		{`CREATE TABLE a (b INT REFERENCES other ON DELETE NO ACTION)`,
+			`CREATE TABLE a (b INT REFERENCES other)`},
+
+		{`CREATE TABLE a (b INT ARRAY)`, `CREATE TABLE a (b INT[])`},
+		{`CREATE TABLE a (b INT ARRAY[3])`, `CREATE TABLE a (b INT[])`},
+		{`CREATE TABLE a (b STRING[4])`, `CREATE TABLE a (b STRING[])`},
 
 		{`SELECT TIMESTAMP WITHOUT TIME ZONE 'foo'`, `SELECT TIMESTAMP 'foo'`},
 		{`SELECT CAST('foo' AS TIMESTAMP WITHOUT TIME ZONE)`, `SELECT CAST('foo' AS TIMESTAMP)`},
@@ -735,6 +844,12 @@ func TestParse2(t *testing.T) {
 		{`SELECT a FROM t WHERE a = b | c`, `SELECT a FROM t WHERE a = (b | c)`},
 		{`SELECT a FROM t WHERE a = b # c`, `SELECT a FROM t WHERE a = (b # c)`},
 		{`SELECT a FROM t WHERE a = b ^ c`, `SELECT a FROM t WHERE a = (b ^ c)`},
+		{`SELECT a->'b'->>'c' FROM t`, `SELECT (a -> 'b') ->> 'c' FROM t`},
+		{`SELECT a FROM t WHERE a->'b' @> c`, `SELECT a FROM t WHERE (a -> 'b') @> c`},
+		{`SELECT a FROM t WHERE a->>'b' = c`, `SELECT a FROM t WHERE (a ->> 'b') = c`},
+		{`SELECT a FROM t WHERE a@>b`, `SELECT a FROM t WHERE a @> b`},
+		{`SELECT a FROM t WHERE a?'b'`, `SELECT a FROM t WHERE a ? 'b'`},
+		{`CREATE TABLE a (b JSON)`, `CREATE TABLE a (b JSONB)`},
 		{`SELECT a FROM t WHERE a = b + c`, `SELECT a FROM t WHERE a = (b + c)`},
 		{`SELECT a FROM t WHERE a = b - c`, `SELECT a FROM t WHERE a = (b - c)`},
 		{`SELECT a FROM t WHERE a = b * c`, `SELECT a FROM t WHERE a = (b * c)`},
@@ -788,6 +903,10 @@ func TestParse2(t *testing.T) {
 			`SELECT current_timestamp()`},
 		{`SELECT CURRENT_DATE`,
 			`SELECT current_date()`},
+		{`SELECT CURRENT_USER`,
+			`SELECT current_user()`},
+		{`SELECT CURRENT_ROLE`,
+			`SELECT current_user()`},
 		{`SELECT POSITION(a IN b)`,
 			`SELECT strpos(b, a)`},
 		{`SELECT TRIM(BOTH a FROM b)`,
@@ -922,7 +1041,13 @@ func TestParse2(t *testing.T) {
 		{`RESTORE DATABASE foo FROM bar`,
 			`RESTORE DATABASE foo FROM 'bar'`},
 
+		{`COPY t TO stdout CSV`, `COPY t TO STDOUT WITH (FORMAT csv)`},
+		{`COPY t (a) TO STDOUT WITH CSV`, `COPY t (a) TO STDOUT WITH (FORMAT csv)`},
+
 		{`SHOW ALL CLUSTER SETTINGS`, `SHOW CLUSTER SETTING all`},
+
+		{`SHOW QUERIES`, `SHOW CLUSTER QUERIES`},
+		{`SHOW SESSIONS`, `SHOW CLUSTER SESSIONS`},
 	}
 	for _, d := range testData {
 		stmts, err := Parse(d.sql)
@@ -1195,6 +1320,13 @@ SELECT CAST(1.2+2.3 AS notatype)
 			`syntax error at or near "notatype"
 SELECT ANNOTATE_TYPE(1.2+2.3, notatype)
                               ^
+`,
+		},
+		{
+			`GRANT SELECT, FOO ON t TO bar`,
+			`not a valid privilege: "foo" at or near "on"
+GRANT SELECT, FOO ON t TO bar
+                  ^
 `,
 		},
 		{
@@ -37,10 +37,13 @@ var typeBuiltinsHaveUnderscore = map[oid.Oid]struct{}{
 	TypeAny.Oid():         {},
 	TypeDate.Oid():        {},
 	TypeDecimal.Oid():     {},
+	TypeINet.Oid():        {},
 	TypeInterval.Oid():    {},
+	TypeJSON.Oid():        {},
 	TypeTimestamp.Oid():   {},
 	TypeTimestampTZ.Oid(): {},
 	TypeTuple.Oid():       {},
+	TypeUUID.Oid():        {},
 }
 
 // PGIOBuiltinPrefix returns the string prefix to a type's IO functions. This
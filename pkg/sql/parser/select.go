@@ -40,6 +40,7 @@ func (*ValuesClause) selectStatement() {}
 
 // Select represents a SelectStatement with an ORDER and/or LIMIT.
 type Select struct {
+	With    *With
 	Select  SelectStatement
 	OrderBy OrderBy
 	Limit   *Limit
@@ -47,6 +48,7 @@ type Select struct {
 
 // Format implements the NodeFormatter interface.
 func (node *Select) Format(buf *bytes.Buffer, f FmtFlags) {
+	FormatNode(buf, f, node.With)
 	FormatNode(buf, f, node.Select)
 	FormatNode(buf, f, node.OrderBy)
 	FormatNode(buf, f, node.Limit)
@@ -162,6 +164,40 @@ func (a AliasClause) Format(buf *bytes.Buffer, f FmtFlags) {
 	}
 }
 
+// With represents a WITH statement: a list of common table expressions
+// that can be referenced by name from the statement that follows.
+type With struct {
+	Recursive bool
+	CTEList   []*CTE
+}
+
+// CTE represents a common table expression inside of a WITH clause.
+type CTE struct {
+	Name AliasClause
+	Stmt Statement
+}
+
+// Format implements the NodeFormatter interface.
+func (node *With) Format(buf *bytes.Buffer, f FmtFlags) {
+	if node == nil {
+		return
+	}
+	buf.WriteString("WITH ")
+	if node.Recursive {
+		buf.WriteString("RECURSIVE ")
+	}
+	for i, cte := range node.CTEList {
+		if i != 0 {
+			buf.WriteString(", ")
+		}
+		FormatNode(buf, f, cte.Name)
+		buf.WriteString(" AS (")
+		FormatNode(buf, f, cte.Stmt)
+		buf.WriteByte(')')
+	}
+	buf.WriteByte(' ')
+}
+
 // AsOfClause represents an as of time.
 type AsOfClause struct {
 	Expr Expr
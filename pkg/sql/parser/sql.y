@@ -170,6 +170,15 @@ func (u *sqlSymUnion) stmts() []Statement {
 func (u *sqlSymUnion) slct() *Select {
     return u.val.(*Select)
 }
+func (u *sqlSymUnion) with() *With {
+    return u.val.(*With)
+}
+func (u *sqlSymUnion) cte() *CTE {
+    return u.val.(*CTE)
+}
+func (u *sqlSymUnion) ctes() []*CTE {
+    return u.val.([]*CTE)
+}
 func (u *sqlSymUnion) selectStmt() SelectStatement {
     return u.val.(SelectStatement)
 }
@@ -269,9 +278,6 @@ func (u *sqlSymUnion) targetList() TargetList {
 func (u *sqlSymUnion) targetListPtr() *TargetList {
     return u.val.(*TargetList)
 }
-func (u *sqlSymUnion) privilegeType() privilege.Kind {
-    return u.val.(privilege.Kind)
-}
 func (u *sqlSymUnion) privilegeList() privilege.List {
     return u.val.(privilege.List)
 }
@@ -320,6 +326,21 @@ func (u *sqlSymUnion) validationBehavior() ValidationBehavior {
 func (u *sqlSymUnion) interleave() *InterleaveDef {
     return u.val.(*InterleaveDef)
 }
+func (u *sqlSymUnion) partitionBy() *PartitionBy {
+    return u.val.(*PartitionBy)
+}
+func (u *sqlSymUnion) listPartition() ListPartition {
+    return u.val.(ListPartition)
+}
+func (u *sqlSymUnion) listPartitions() []ListPartition {
+    return u.val.([]ListPartition)
+}
+func (u *sqlSymUnion) rangePartition() RangePartition {
+    return u.val.(RangePartition)
+}
+func (u *sqlSymUnion) rangePartitions() []RangePartition {
+    return u.val.([]RangePartition)
+}
 func (u *sqlSymUnion) windowDef() *WindowDef {
     return u.val.(*WindowDef)
 }
@@ -344,6 +365,21 @@ func (u *sqlSymUnion) kvOptions() []KVOption {
     }
     return nil
 }
+func (u *sqlSymUnion) referenceAction() ReferenceAction {
+    return u.val.(ReferenceAction)
+}
+func (u *sqlSymUnion) referenceActions() ReferenceActions {
+    return u.val.(ReferenceActions)
+}
+func (u *sqlSymUnion) int64() int64 {
+    return u.val.(int64)
+}
+func (u *sqlSymUnion) seqOpt() SequenceOption {
+    return u.val.(SequenceOption)
+}
+func (u *sqlSymUnion) seqOpts() SequenceOptions {
+    return u.val.(SequenceOptions)
+}
 
 %}
 
@@ -361,12 +397,17 @@ func (u *sqlSymUnion) kvOptions() []KVOption {
 
 %type <Statement> alter_table_stmt
 %type <Statement> backup_stmt
+%type <Statement> cancel_stmt
 %type <Statement> copy_from_stmt
+%type <Statement> copy_to_stmt
 %type <Statement> create_stmt
 %type <Statement> create_database_stmt
 %type <Statement> create_index_stmt
+%type <Statement> create_sequence_stmt
+%type <Statement> create_stats_stmt
 %type <Statement> create_table_stmt
 %type <Statement> create_table_as_stmt
+%type <Statement> create_role_stmt
 %type <Statement> create_user_stmt
 %type <Statement> create_view_stmt
 %type <Statement> delete_stmt
@@ -374,15 +415,18 @@ func (u *sqlSymUnion) kvOptions() []KVOption {
 %type <Statement> explain_stmt
 %type <Statement> explainable_stmt
 %type <Statement> help_stmt
+%type <Statement> pause_stmt
 %type <Statement> prepare_stmt
 %type <Statement> preparable_stmt
 %type <Statement> execute_stmt
 %type <Statement> deallocate_stmt
 %type <Statement> grant_stmt
+%type <Statement> import_stmt
 %type <Statement> insert_stmt
 %type <Statement> release_stmt
 %type <Statement> rename_stmt
 %type <Statement> reset_stmt
+%type <Statement> resume_stmt
 %type <Statement> revoke_stmt
 %type <*Select> select_stmt
 %type <Statement> savepoint_stmt
@@ -399,6 +443,7 @@ func (u *sqlSymUnion) kvOptions() []KVOption {
 %type <KVOption> kv_option
 %type <[]KVOption> kv_option_list opt_with_options
 %type <str> opt_equal_value
+%type <str> opt_copy_format
 
 %type <*Select> select_no_parens
 %type <SelectStatement> select_clause select_with_parens simple_select values_clause
@@ -558,6 +603,9 @@ func (u *sqlSymUnion) kvOptions() []KVOption {
 %type <empty> opt_varying
 
 %type <*NumVal>  signed_iconst
+%type <int64>   signed_iconst64
+%type <SequenceOptions> opt_sequence_option_list sequence_option_list
+%type <SequenceOption> sequence_option_elem
 %type <Expr>  opt_boolean_or_string
 %type <Exprs> var_list
 %type <UnresolvedName> var_name
@@ -578,13 +626,22 @@ func (u *sqlSymUnion) kvOptions() []KVOption {
 %type <[]NamedColumnQualification> col_qual_list
 %type <NamedColumnQualification> col_qualification
 %type <ColumnQualification> col_qualification_elem
-%type <empty> key_actions key_delete key_match key_update key_action
+%type <empty> key_match
+%type <ReferenceActions> key_actions
+%type <ReferenceAction> key_action key_delete key_update
+%type <*PartitionBy> opt_partition_by partition_by
+%type <ListPartition> list_partition
+%type <[]ListPartition> list_partitions
+%type <RangePartition> range_partition
+%type <[]RangePartition> range_partitions
 
 %type <Expr>  func_application func_expr_common_subexpr
 %type <Expr>  func_expr func_expr_windowless
-%type <empty> common_table_expr
-%type <empty> with_clause opt_with opt_with_clause
-%type <empty> cte_list
+%type <*CTE> common_table_expr
+%type <*With> with_clause opt_with_clause
+%type <empty> opt_with
+%type <empty> opt_by
+%type <[]*CTE> cte_list
 
 %type <empty> within_group_clause
 %type <Expr> filter_clause
@@ -599,8 +656,9 @@ func (u *sqlSymUnion) kvOptions() []KVOption {
 %type <TargetList>    targets
 %type <*TargetList> on_privilege_target_clause
 %type <NameList>       grantee_list for_grantee_clause
-%type <privilege.List> privileges privilege_list
-%type <privilege.Kind> privilege
+%type <privilege.List> privileges
+%type <NameList>       privilege_list
+%type <str>            privilege
 
 // Non-keyword token types.
 %token <str>   IDENT SCONST BCONST
@@ -609,6 +667,7 @@ func (u *sqlSymUnion) kvOptions() []KVOption {
 %token <str>   TYPECAST TYPEANNOTATE DOT_DOT
 %token <str>   LESS_EQUALS GREATER_EQUALS NOT_EQUALS
 %token <str>   NOT_REGMATCH REGIMATCH NOT_REGIMATCH
+%token <str>   FETCHVAL FETCHTEXT CONTAINS CONTAINED_BY
 %token <str>   ERROR
 
 // If you want to make any keyword changes, update the keyword table in
@@ -617,18 +676,18 @@ func (u *sqlSymUnion) kvOptions() []KVOption {
 // "Keyword category lists".
 
 // Ordinary key words in alphabetical order.
-%token <str>   ACTION ADD
+%token <str>   ACTION ADD ADMIN
 %token <str>   ALL ALTER ANALYSE ANALYZE AND ANY ANNOTATE_TYPE ARRAY AS ASC
 %token <str>   ASYMMETRIC AT
 
 %token <str>   BACKUP BEGIN BETWEEN BIGINT BIGSERIAL BIT
 %token <str>   BLOB BOOL BOOLEAN BOTH BY BYTEA BYTES
 
-%token <str>   CASCADE CASE CAST CHAR
+%token <str>   CACHE CANCEL CASCADE CASE CAST CHAR
 %token <str>   CHARACTER CHARACTERISTICS CHECK
 %token <str>   CLUSTER COALESCE COLLATE COLLATION COLUMN COLUMNS COMMIT
 %token <str>   COMMITTED CONCAT CONFLICT CONSTRAINT CONSTRAINTS
-%token <str>   COPY COVERING CREATE
+%token <str>   COPY COVERING CREATE CSV
 %token <str>   CROSS CUBE CURRENT CURRENT_CATALOG CURRENT_DATE
 %token <str>   CURRENT_ROLE CURRENT_TIME CURRENT_TIMESTAMP
 %token <str>   CURRENT_USER CYCLE
@@ -638,50 +697,52 @@ func (u *sqlSymUnion) kvOptions() []KVOption {
 %token <str>   DISTINCT DO DOUBLE DROP
 
 %token <str>   ELSE ENCODING END ESCAPE EXCEPT
-%token <str>   EXISTS EXECUTE EXPLAIN EXTRACT EXTRACT_DURATION
+%token <str>   EXISTS EXECUTE EXPERIMENTAL_AUDIT EXPLAIN EXTRACT EXTRACT_DURATION
 
 %token <str>   FALSE FAMILY FETCH FILTER FIRST FLOAT FLOORDIV FOLLOWING FOR
-%token <str>   FORCE_INDEX FOREIGN FROM FULL
+%token <str>   FORCE_INDEX FOREIGN FORMAT FROM FULL
 
 %token <str>   GRANT GRANTS GREATEST GROUP GROUPING
 
 %token <str>   HAVING HELP HIGH HOUR
 
-%token <str>   INCREMENTAL IF IFNULL ILIKE IN INTERLEAVE
-%token <str>   INDEX INDEXES INITIALLY
+%token <str>   IMPORT INCREMENT INCREMENTAL IF IFNULL ILIKE IN INTERLEAVE
+%token <str>   INDEX INDEXES INET INITIALLY
 %token <str>   INNER INSERT INT INT2VECTOR INT8 INT64 INTEGER
-%token <str>   INTERSECT INTERVAL INTO IS ISOLATION
+%token <str>   INTERSECT INTERVAL INTO INVERTED IS ISOLATION
 
-%token <str>   JOIN
+%token <str>   JOB JOIN JSON JSONB
 
 %token <str>   KEY KEYS
 
 %token <str>   LATERAL LC_CTYPE LC_COLLATE
-%token <str>   LEADING LEAST LEFT LEVEL LIKE LIMIT LOCAL
+%token <str>   LEADING LEAST LEFT LEVEL LIKE LIMIT LIST LOCAL
 %token <str>   LOCALTIME LOCALTIMESTAMP LOW LSHIFT
 
-%token <str>   MATCH MINUTE MONTH
+%token <str>   MATCH MAXVALUE MINUTE MINVALUE MONTH
 
 %token <str>   NAN NAME NAMES NATURAL NEXT NO NO_INDEX_JOIN NORMAL
 %token <str>   NOT NOTHING NULL NULLIF
 %token <str>   NULLS NUMERIC
 
-%token <str>   OF OFF OFFSET OID ON ONLY OPTIONS OR
+%token <str>   OF OFF OFFSET OID ON ONLY OPTION OPTIONS OR
 %token <str>   ORDER ORDINALITY OUT OUTER OVER OVERLAPS OVERLAY
 
-%token <str>   PARENT PARTIAL PARTITION PASSWORD PLACING POSITION
+%token <str>   PARENT PARTIAL PARTITION PASSWORD PAUSE PLACING POSITION
 %token <str>   PRECEDING PRECISION PREPARE PRIMARY PRIORITY
 
+%token <str>   QUERIES QUERY
+
 %token <str>   RANGE READ REAL RECURSIVE REF REFERENCES
 %token <str>   REGCLASS REGPROC REGPROCEDURE REGNAMESPACE REGTYPE
 %token <str>   RENAME REPEATABLE
-%token <str>   RELEASE RESET RESTORE RESTRICT RETURNING REVOKE RIGHT ROLLBACK ROLLUP
+%token <str>   RELEASE RESET RESTORE RESTRICT RESUME RETURNING REVOKE RIGHT ROLE ROLLBACK ROLLUP
 %token <str>   ROW ROWS RSHIFT
 
-%token <str>   SAVEPOINT SCATTER SEARCH SECOND SELECT
-%token <str>   SERIAL SERIALIZABLE SESSION SESSION_USER SET SETTING SETTINGS SHOW
+%token <str>   SAVEPOINT SCATTER SEARCH SECOND SELECT SEQUENCE
+%token <str>   SERIAL SERIALIZABLE SESSION SESSIONS SESSION_USER SET SETTING SETTINGS SHOW
 %token <str>   SIMILAR SIMPLE SMALLINT SMALLSERIAL SNAPSHOT SOME SPLIT SQL
-%token <str>   START STATUS STDIN STRICT STRING STORING SUBSTRING
+%token <str>   START STATISTICS STATUS STDIN STDOUT STRICT STRING STORING SUBSTRING
 %token <str>   SYMMETRIC SYSTEM
 
 %token <str>   TABLE TABLES TEMPLATE TESTING_RANGES TESTING_RELOCATE TEXT THEN
@@ -689,11 +750,11 @@ func (u *sqlSymUnion) kvOptions() []KVOption {
 %token <str>   TRUNCATE TYPE
 
 %token <str>   UNBOUNDED UNCOMMITTED UNION UNIQUE UNKNOWN
-%token <str>   UPDATE UPSERT USER USERS USING
+%token <str>   UPDATE UPSERT USER USERS USING UUID
 
 %token <str>   VALID VALIDATE VALUE VALUES VARCHAR VARIADIC VIEW VARYING
 
-%token <str>   WHEN WHERE WINDOW WITH WITHIN WITHOUT
+%token <str>   WHEN WHERE WINDOW WITH WITHIN WITHOUT WRITE
 
 %token <str>   YEAR
 
@@ -718,7 +779,7 @@ func (u *sqlSymUnion) kvOptions() []KVOption {
 %left      AND
 %right     NOT
 %nonassoc  IS                  // IS sets precedence for IS NULL, etc
-%nonassoc  '<' '>' '=' LESS_EQUALS GREATER_EQUALS NOT_EQUALS
+%nonassoc  '<' '>' '=' LESS_EQUALS GREATER_EQUALS NOT_EQUALS CONTAINS CONTAINED_BY '?'
 %nonassoc  '~' BETWEEN IN LIKE ILIKE SIMILAR NOT_REGMATCH REGIMATCH NOT_REGIMATCH NOT_LA
 %nonassoc  ESCAPE              // ESCAPE must be just above LIKE/ILIKE/SIMILAR
 %nonassoc  OVERLAPS
@@ -748,7 +809,7 @@ func (u *sqlSymUnion) kvOptions() []KVOption {
 // funny behavior of UNBOUNDED on the SQL standard, though.
 %nonassoc  UNBOUNDED         // ideally should have same precedence as IDENT
 %nonassoc  IDENT NULL PARTITION RANGE ROWS PRECEDING FOLLOWING CUBE ROLLUP
-%left      CONCAT       // multi-character ops
+%left      CONCAT FETCHVAL FETCHTEXT // multi-character ops
 %left      '|'
 %left      '#'
 %left      '&'
@@ -799,16 +860,20 @@ stmt_list:
 stmt:
   alter_table_stmt
 | backup_stmt
+| cancel_stmt
 | copy_from_stmt
+| copy_to_stmt
 | create_stmt
 | delete_stmt
 | drop_stmt
 | explain_stmt
 | help_stmt
+| pause_stmt
 | prepare_stmt
 | execute_stmt
 | deallocate_stmt
 | grant_stmt
+| import_stmt
 | insert_stmt
 | rename_stmt
 | revoke_stmt
@@ -825,6 +890,7 @@ stmt:
 | transaction_stmt
 | release_stmt
 | reset_stmt
+| resume_stmt
 | truncate_stmt
 | update_stmt
 | /* EMPTY */
@@ -943,6 +1009,16 @@ alter_table_cmd:
       DropBehavior: $4.dropBehavior(),
     }
   }
+  // ALTER TABLE <name> EXPERIMENTAL_AUDIT SET READ WRITE
+| EXPERIMENTAL_AUDIT SET READ WRITE
+  {
+    $$.val = &AlterTableSetAudit{Mode: AuditModeReadWrite}
+  }
+  // ALTER TABLE <name> EXPERIMENTAL_AUDIT SET OFF
+| EXPERIMENTAL_AUDIT SET OFF
+  {
+    $$.val = &AlterTableSetAudit{Mode: AuditModeDisable}
+  }
 
 alter_column_default:
   SET DEFAULT a_expr
@@ -996,6 +1072,13 @@ backup_stmt:
     $$.val = &Restore{Targets: $2.targetList(), From: $4.exprs(), AsOf: $5.asOfClause(), Options: $6.kvOptions()}
   }
 
+// IMPORT TABLE <name> (<table elements>) CSV DATA (<file URIs>) [WITH OPTIONS (...)]
+import_stmt:
+  IMPORT TABLE any_name '(' opt_table_elem_list ')' CSV DATA '(' string_or_placeholder_list ')' opt_with_options
+  {
+    $$.val = &Import{Table: $3.normalizableTableName(), CreateDefs: $5.tblDefs(), FileFormat: "CSV", Files: $10.exprs(), Options: $12.kvOptions()}
+  }
+
 string_or_placeholder:
   non_reserved_word_or_sconst
   {
@@ -1059,6 +1142,20 @@ opt_with_options:
   }
 | /* EMPTY */ {}
 
+cancel_stmt:
+  CANCEL JOB a_expr
+  {
+    $$.val = &CancelJob{ID: $3.expr()}
+  }
+| CANCEL QUERY a_expr
+  {
+    $$.val = &CancelQuery{ID: $3.expr()}
+  }
+| CANCEL SESSION a_expr
+  {
+    $$.val = &CancelSession{ID: $3.expr()}
+  }
+
 copy_from_stmt:
   COPY qualified_name FROM STDIN
   {
@@ -1073,12 +1170,46 @@ copy_from_stmt:
     $$.val = &CopyFrom{Table: $2.normalizableTableName(), Columns: $4.unresolvedNames(), Stdin: true}
   }
 
-// CREATE [DATABASE|INDEX|TABLE|TABLE AS|VIEW]
+copy_to_stmt:
+  COPY qualified_name TO STDOUT opt_copy_format
+  {
+    $$.val = &CopyTo{Table: $2.normalizableTableName(), Stdout: true, FileFormat: $5}
+  }
+| COPY qualified_name '(' qualified_name_list ')' TO STDOUT opt_copy_format
+  {
+    $$.val = &CopyTo{Table: $2.normalizableTableName(), Columns: $4.unresolvedNames(), Stdout: true, FileFormat: $8}
+  }
+| COPY select_with_parens TO STDOUT opt_copy_format
+  {
+    $$.val = &CopyTo{Query: $2.selectStmt().(*ParenSelect).Select, Stdout: true, FileFormat: $5}
+  }
+
+// The format can be given with either the pre-9.0 or the current syntax of
+// postgres: COPY ... TO STDOUT [WITH] CSV, or COPY ... TO STDOUT WITH (FORMAT
+// name).
+opt_copy_format:
+  opt_with CSV
+  {
+    $$ = "csv"
+  }
+| WITH '(' FORMAT name ')'
+  {
+    $$ = $4
+  }
+| /* EMPTY */
+  {
+    $$ = ""
+  }
+
+// CREATE [DATABASE|INDEX|STATISTICS|TABLE|TABLE AS|VIEW]
 create_stmt:
   create_database_stmt
 | create_index_stmt
+| create_sequence_stmt
+| create_stats_stmt
 | create_table_stmt
 | create_table_as_stmt
+| create_role_stmt
 | create_user_stmt
 | create_view_stmt
 
@@ -1086,7 +1217,7 @@ create_stmt:
 delete_stmt:
   opt_with_clause DELETE FROM relation_expr_opt_alias where_clause returning_clause
   {
-    $$.val = &Delete{Table: $4.tblExpr(), Where: newWhere(astWhere, $5.expr()), Returning: $6.retClause()}
+    $$.val = &Delete{With: $1.with(), Table: $4.tblExpr(), Where: newWhere(astWhere, $5.expr()), Returning: $6.retClause()}
   }
 
 // DROP itemtype [ IF EXISTS ] itemname [, itemname ...] [ RESTRICT | CASCADE ]
@@ -1131,6 +1262,22 @@ drop_stmt:
   {
     $$.val = &DropView{Names: $5.tableNameReferences(), IfExists: true, DropBehavior: $6.dropBehavior()}
   }
+| DROP SEQUENCE table_name_list opt_drop_behavior
+  {
+    $$.val = &DropSequence{Names: $3.tableNameReferences(), IfExists: false, DropBehavior: $4.dropBehavior()}
+  }
+| DROP SEQUENCE IF EXISTS table_name_list opt_drop_behavior
+  {
+    $$.val = &DropSequence{Names: $5.tableNameReferences(), IfExists: true, DropBehavior: $6.dropBehavior()}
+  }
+| DROP ROLE name_list
+  {
+    $$.val = &DropRole{Names: $3.nameList(), IfExists: false}
+  }
+| DROP ROLE IF EXISTS name_list
+  {
+    $$.val = &DropRole{Names: $5.nameList(), IfExists: true}
+  }
 
 table_name_list:
   any_name
@@ -1204,6 +1351,13 @@ explain_option_list:
 explain_option_name:
   non_reserved_word
 
+// PAUSE JOB <job_id>
+pause_stmt:
+  PAUSE JOB a_expr
+  {
+    $$.val = &PauseJob{ID: $3.expr()}
+  }
+
 // PREPARE <plan_name> [(args, ...)] AS <query>
 prepare_stmt:
   PREPARE name prep_type_clause AS preparable_stmt
@@ -1280,18 +1434,36 @@ deallocate_stmt:
   }
 
 // GRANT privileges ON targets TO grantee_list
+// GRANT role_list TO grantee_list [WITH ADMIN OPTION]
 grant_stmt:
   GRANT privileges ON targets TO grantee_list
   {
     $$.val = &Grant{Privileges: $2.privilegeList(), Grantees: $6.nameList(), Targets: $4.targetList()}
   }
+| GRANT privilege_list TO grantee_list
+  {
+    $$.val = &GrantRole{Roles: $2.nameList(), Members: $4.nameList(), AdminOption: false}
+  }
+| GRANT privilege_list TO grantee_list WITH ADMIN OPTION
+  {
+    $$.val = &GrantRole{Roles: $2.nameList(), Members: $4.nameList(), AdminOption: true}
+  }
 
 // REVOKE privileges ON targets FROM grantee_list
+// REVOKE [ADMIN OPTION FOR] role_list FROM grantee_list
 revoke_stmt:
   REVOKE privileges ON targets FROM grantee_list
   {
     $$.val = &Revoke{Privileges: $2.privilegeList(), Grantees: $6.nameList(), Targets: $4.targetList()}
   }
+| REVOKE privilege_list FROM grantee_list
+  {
+    $$.val = &RevokeRole{Roles: $2.nameList(), Members: $4.nameList(), AdminOption: false}
+  }
+| REVOKE ADMIN OPTION FOR privilege_list FROM grantee_list
+  {
+    $$.val = &RevokeRole{Roles: $5.nameList(), Members: $7.nameList(), AdminOption: true}
+  }
 
 
 targets:
@@ -1314,48 +1486,37 @@ privileges:
   {
     $$.val = privilege.List{privilege.ALL}
   }
-  | privilege_list { }
+| privilege_list
+  {
+    privList, err := privilege.ListFromStrings($1.nameList().ToStrings())
+    if err != nil {
+      sqllex.Error(err.Error())
+      return 1
+    }
+    $$.val = privList
+  }
 
+// A privilege_list is also used as the list of roles in GRANT and REVOKE of
+// role memberships: the two cannot be told apart before ON or TO/FROM.
 privilege_list:
   privilege
   {
-    $$.val = privilege.List{$1.privilegeType()}
+    $$.val = NameList{Name($1)}
   }
-  | privilege_list ',' privilege
+| privilege_list ',' privilege
   {
-    $$.val = append($1.privilegeList(), $3.privilegeType())
+    $$.val = append($1.nameList(), Name($3))
   }
 
-// This list must match the list of privileges in sql/privilege/privilege.go.
+// Privileges are parsed as names so that roles can be named like
+// unreserved keywords. CREATE, GRANT and SELECT are reserved keywords and
+// must be listed explicitly. The names are checked against the list of
+// privileges in sql/privilege/privilege.go.
 privilege:
-  CREATE
-  {
-    $$.val = privilege.CREATE
-  }
-| DROP
-  {
-    $$.val = privilege.DROP
-  }
+  name
+| CREATE
 | GRANT
-  {
-    $$.val = privilege.GRANT
-  }
 | SELECT
-  {
-    $$.val = privilege.SELECT
-  }
-| INSERT
-  {
-    $$.val = privilege.INSERT
-  }
-| DELETE
-  {
-    $$.val = privilege.DELETE
-  }
-| UPDATE
-  {
-    $$.val = privilege.UPDATE
-  }
 
 // TODO(marc): this should not be 'name', but should instead be a
 // type just for usernames.
@@ -1376,6 +1537,13 @@ reset_stmt:
     $$.val = &Set{Name: $2.unresolvedName(), SetMode: SetModeReset}
   }
 
+// RESUME JOB <job_id>
+resume_stmt:
+  RESUME JOB a_expr
+  {
+    $$.val = &ResumeJob{ID: $3.expr()}
+  }
+
 // SET name TO 'var_value'
 // SET TIME ZONE 'var_value'
 set_stmt:
@@ -1687,6 +1855,30 @@ show_stmt:
   {
     $$.val = &ShowUsers{}
   }
+| SHOW QUERIES
+  {
+    $$.val = &ShowQueries{Cluster: true}
+  }
+| SHOW CLUSTER QUERIES
+  {
+    $$.val = &ShowQueries{Cluster: true}
+  }
+| SHOW LOCAL QUERIES
+  {
+    $$.val = &ShowQueries{Cluster: false}
+  }
+| SHOW SESSIONS
+  {
+    $$.val = &ShowSessions{Cluster: true}
+  }
+| SHOW CLUSTER SESSIONS
+  {
+    $$.val = &ShowSessions{Cluster: true}
+  }
+| SHOW LOCAL SESSIONS
+  {
+    $$.val = &ShowSessions{Cluster: false}
+  }
 | SHOW TESTING_RANGES FROM TABLE qualified_name
   {
     /* SKIP DOC */
@@ -1771,13 +1963,13 @@ scatter_stmt:
 
 // CREATE TABLE relname
 create_table_stmt:
-  CREATE TABLE any_name '(' opt_table_elem_list ')' opt_interleave
+  CREATE TABLE any_name '(' opt_table_elem_list ')' opt_interleave opt_partition_by
   {
-    $$.val = &CreateTable{Table: $3.normalizableTableName(), IfNotExists: false, Interleave: $7.interleave(), Defs: $5.tblDefs(), AsSource: nil, AsColumnNames: nil}
+    $$.val = &CreateTable{Table: $3.normalizableTableName(), IfNotExists: false, Interleave: $7.interleave(), Defs: $5.tblDefs(), AsSource: nil, AsColumnNames: nil, PartitionBy: $8.partitionBy()}
   }
-| CREATE TABLE IF NOT EXISTS any_name '(' opt_table_elem_list ')' opt_interleave
+| CREATE TABLE IF NOT EXISTS any_name '(' opt_table_elem_list ')' opt_interleave opt_partition_by
   {
-    $$.val = &CreateTable{Table: $6.normalizableTableName(), IfNotExists: true, Interleave: $10.interleave(), Defs: $8.tblDefs(), AsSource: nil, AsColumnNames: nil}
+    $$.val = &CreateTable{Table: $6.normalizableTableName(), IfNotExists: true, Interleave: $10.interleave(), Defs: $8.tblDefs(), AsSource: nil, AsColumnNames: nil, PartitionBy: $11.partitionBy()}
   }
 
 create_table_as_stmt:
@@ -1833,6 +2025,73 @@ opt_interleave:
     $$.val = (*InterleaveDef)(nil)
   }
 
+opt_partition_by:
+  partition_by
+| /* EMPTY */
+  {
+    $$.val = (*PartitionBy)(nil)
+  }
+
+partition_by:
+  PARTITION BY LIST '(' name_list ')' '(' list_partitions ')'
+  {
+    $$.val = &PartitionBy{
+      Fields: $5.nameList(),
+      List: $8.listPartitions(),
+    }
+  }
+| PARTITION BY RANGE '(' name_list ')' '(' range_partitions ')'
+  {
+    $$.val = &PartitionBy{
+      Fields: $5.nameList(),
+      Range: $8.rangePartitions(),
+    }
+  }
+
+list_partition:
+  PARTITION name VALUES IN '(' ctext_expr_list ')'
+  {
+    $$.val = ListPartition{
+      Name: Name($2),
+      Exprs: $6.exprs(),
+    }
+  }
+
+list_partitions:
+  list_partition
+  {
+    $$.val = []ListPartition{$1.listPartition()}
+  }
+| list_partitions ',' list_partition
+  {
+    $$.val = append($1.listPartitions(), $3.listPartition())
+  }
+
+range_partition:
+  PARTITION name VALUES '<' '(' expr_list ')'
+  {
+    $$.val = RangePartition{
+      Name: Name($2),
+      Exprs: $6.exprs(),
+    }
+  }
+| PARTITION name VALUES '<' MAXVALUE
+  {
+    $$.val = RangePartition{
+      Name: Name($2),
+    }
+  }
+
+range_partitions:
+  range_partition
+  {
+    $$.val = []RangePartition{$1.rangePartition()}
+  }
+| range_partitions ',' range_partition
+  {
+    $$.val = append($1.rangePartitions(), $3.rangePartition())
+  }
+
 // TODO(dan): This can be removed in favor of opt_drop_behavior when #7854 is fixed.
 opt_interleave_drop_behavior:
   CASCADE
@@ -1939,20 +2198,22 @@ col_qualification_elem:
     $$.val = &ColumnFKConstraint{
       Table: $2.normalizableTableName(),
       Col: Name($3),
+      Actions: $5.referenceActions(),
     }
  }
 
 index_def:
-  INDEX opt_name '(' index_params ')' opt_storing opt_interleave
+  INDEX opt_name '(' index_params ')' opt_storing opt_interleave opt_partition_by
   {
     $$.val = &IndexTableDef{
       Name:    Name($2),
       Columns: $4.idxElems(),
       Storing: $6.nameList(),
       Interleave: $7.interleave(),
+      PartitionBy: $8.partitionBy(),
     }
   }
-| UNIQUE INDEX opt_name '(' index_params ')' opt_storing opt_interleave
+| UNIQUE INDEX opt_name '(' index_params ')' opt_storing opt_interleave opt_partition_by
   {
     $$.val = &UniqueConstraintTableDef{
       IndexTableDef: IndexTableDef {
@@ -1960,9 +2221,18 @@ index_def:
         Columns: $5.idxElems(),
         Storing: $7.nameList(),
         Interleave: $8.interleave(),
+        PartitionBy: $9.partitionBy(),
       },
     }
   }
+| INVERTED INDEX opt_name '(' index_params ')'
+  {
+    $$.val = &IndexTableDef{
+      Name:     Name($3),
+      Columns:  $5.idxElems(),
+      Inverted: true,
+    }
+  }
 
 family_def:
   FAMILY opt_name '(' name_list ')'
@@ -1994,13 +2264,14 @@ constraint_elem:
       Expr: $3.expr(),
     }
   }
-| UNIQUE '(' index_params ')' opt_storing opt_interleave
+| UNIQUE '(' index_params ')' opt_storing opt_interleave opt_partition_by
   {
     $$.val = &UniqueConstraintTableDef{
       IndexTableDef: IndexTableDef{
         Columns: $3.idxElems(),
         Storing: $5.nameList(),
         Interleave: $6.interleave(),
+        PartitionBy: $7.partitionBy(),
       },
     }
   }
@@ -2020,6 +2291,7 @@ constraint_elem:
       Table: $7.normalizableTableName(),
       FromCols: $4.nameList(),
       ToCols: $8.nameList(),
+      Actions: $10.referenceActions(),
     }
   }
 
@@ -2066,26 +2338,60 @@ key_match:
 // simplicity of parsing, and then break them down again in the calling
 // production.
 key_actions:
-  key_update {}
-| key_delete {}
-| key_update key_delete {}
-| key_delete key_update {}
-| /* EMPTY */ {}
+  key_update
+  {
+    $$.val = ReferenceActions{Update: $1.referenceAction()}
+  }
+| key_delete
+  {
+    $$.val = ReferenceActions{Delete: $1.referenceAction()}
+  }
+| key_update key_delete
+  {
+    $$.val = ReferenceActions{Delete: $2.referenceAction(), Update: $1.referenceAction()}
+  }
+| key_delete key_update
+  {
+    $$.val = ReferenceActions{Delete: $1.referenceAction(), Update: $2.referenceAction()}
+  }
+| /* EMPTY */
+  {
+    $$.val = ReferenceActions{}
+  }
 
 key_update:
-  ON UPDATE key_action {}
+  ON UPDATE key_action
+  {
+    $$.val = $3.referenceAction()
+  }
 
 key_delete:
-  ON DELETE key_action {}
+  ON DELETE key_action
+  {
+    $$.val = $3.referenceAction()
+  }
 
 key_action:
-  NO ACTION { return unimplemented(sqllex) }
-// RESTRICT is currently the default and only supported ON DELETE/UPDATE
-// behavior and thus needs no special handling.
-| RESTRICT {}
-| CASCADE { return unimplemented(sqllex) }
-| SET NULL { return unimplemented(sqllex) }
-| SET DEFAULT { return unimplemented(sqllex) }
+  NO ACTION
+  {
+    $$.val = NoAction
+  }
+| RESTRICT
+  {
+    $$.val = Restrict
+  }
+| CASCADE
+  {
+    $$.val = Cascade
+  }
+| SET NULL
+  {
+    $$.val = SetNull
+  }
+| SET DEFAULT
+  {
+    $$.val = SetDefault
+  }
 
 numeric_only:
   FCONST
@@ -2108,6 +2414,20 @@ truncate_stmt:
     $$.val = &Truncate{Tables: $3.tableNameReferences(), DropBehavior: $4.dropBehavior()}
   }
 
+// CREATE STATISTICS
+create_stats_stmt:
+  CREATE STATISTICS name ON name_list FROM qualified_name
+  {
+    $$.val = &CreateStats{Name: Name($3), ColumnNames: $5.nameList(), Table: $7.normalizableTableName()}
+  }
+
+// CREATE ROLE
+create_role_stmt:
+  CREATE ROLE name
+  {
+    $$.val = &CreateRole{Name: Name($3)}
+  }
+
 // CREATE USER
 create_user_stmt:
   CREATE USER name opt_with opt_password
@@ -2138,9 +2458,66 @@ create_view_stmt:
 
 // TODO(a-robinson): CREATE OR REPLACE VIEW support (#2971).
 
+// CREATE SEQUENCE
+create_sequence_stmt:
+  CREATE SEQUENCE any_name opt_sequence_option_list
+  {
+    $$.val = &CreateSequence{
+      Name: $3.normalizableTableName(),
+      Options: $4.seqOpts(),
+    }
+  }
+| CREATE SEQUENCE IF NOT EXISTS any_name opt_sequence_option_list
+  {
+    $$.val = &CreateSequence{
+      Name: $6.normalizableTableName(),
+      Options: $7.seqOpts(),
+      IfNotExists: true,
+    }
+  }
+
+opt_sequence_option_list:
+  sequence_option_list
+| /* EMPTY */ { $$.val = SequenceOptions(nil) }
+
+sequence_option_list:
+  sequence_option_elem                       { $$.val = SequenceOptions{$1.seqOpt()} }
+| sequence_option_list sequence_option_elem  { $$.val = append($1.seqOpts(), $2.seqOpt()) }
+
+sequence_option_elem:
+  CYCLE       { $$.val = SequenceOption{Name: SeqOptCycle} }
+| NO CYCLE    { $$.val = SequenceOption{Name: SeqOptNoCycle} }
+| NO MINVALUE { $$.val = SequenceOption{Name: SeqOptMinValue} }
+| NO MAXVALUE { $$.val = SequenceOption{Name: SeqOptMaxValue} }
+| CACHE signed_iconst64
+  {
+    x := $2.int64()
+    $$.val = SequenceOption{Name: SeqOptCache, IntVal: &x}
+  }
+| INCREMENT opt_by signed_iconst64
+  {
+    x := $3.int64()
+    $$.val = SequenceOption{Name: SeqOptIncrement, IntVal: &x}
+  }
+| MINVALUE signed_iconst64
+  {
+    x := $2.int64()
+    $$.val = SequenceOption{Name: SeqOptMinValue, IntVal: &x}
+  }
+| MAXVALUE signed_iconst64
+  {
+    x := $2.int64()
+    $$.val = SequenceOption{Name: SeqOptMaxValue, IntVal: &x}
+  }
+| START opt_with signed_iconst64
+  {
+    x := $3.int64()
+    $$.val = SequenceOption{Name: SeqOptStart, IntVal: &x}
+  }
+
 // CREATE INDEX
 create_index_stmt:
-  CREATE opt_unique INDEX opt_name ON qualified_name '(' index_params ')' opt_storing opt_interleave
+  CREATE opt_unique INDEX opt_name ON qualified_name '(' index_params ')' opt_storing opt_interleave opt_partition_by
   {
     $$.val = &CreateIndex{
       Name:    Name($4),
@@ -2149,9 +2526,10 @@ create_index_stmt:
       Columns: $8.idxElems(),
       Storing: $10.nameList(),
       Interleave: $11.interleave(),
+      PartitionBy: $12.partitionBy(),
     }
   }
-| CREATE opt_unique INDEX IF NOT EXISTS name ON qualified_name '(' index_params ')' opt_storing opt_interleave
+| CREATE opt_unique INDEX IF NOT EXISTS name ON qualified_name '(' index_params ')' opt_storing opt_interleave opt_partition_by
   {
     $$.val = &CreateIndex{
       Name:        Name($7),
@@ -2161,6 +2539,26 @@ create_index_stmt:
       Columns:     $11.idxElems(),
       Storing:     $13.nameList(),
       Interleave: $14.interleave(),
+      PartitionBy: $15.partitionBy(),
+    }
+  }
+| CREATE INVERTED INDEX opt_name ON qualified_name '(' index_params ')'
+  {
+    $$.val = &CreateIndex{
+      Name:     Name($4),
+      Table:    $6.normalizableTableName(),
+      Inverted: true,
+      Columns:  $8.idxElems(),
+    }
+  }
+| CREATE INVERTED INDEX IF NOT EXISTS name ON qualified_name '(' index_params ')'
+  {
+    $$.val = &CreateIndex{
+      Name:        Name($7),
+      Table:       $9.normalizableTableName(),
+      Inverted:    true,
+      IfNotExists: true,
+      Columns:     $11.idxElems(),
     }
   }
 
@@ -2438,12 +2836,14 @@ insert_stmt:
   opt_with_clause INSERT INTO insert_target insert_rest returning_clause
   {
     $$.val = $5.stmt()
+    $$.val.(*Insert).With = $1.with()
     $$.val.(*Insert).Table = $4.tblExpr()
     $$.val.(*Insert).Returning = $6.retClause()
   }
 | opt_with_clause INSERT INTO insert_target insert_rest on_conflict returning_clause
   {
     $$.val = $5.stmt()
+    $$.val.(*Insert).With = $1.with()
     $$.val.(*Insert).Table = $4.tblExpr()
     $$.val.(*Insert).OnConflict = $6.onConflict()
     $$.val.(*Insert).Returning = $7.retClause()
@@ -2451,6 +2851,7 @@ insert_stmt:
 | opt_with_clause UPSERT INTO insert_target insert_rest returning_clause
   {
     $$.val = $5.stmt()
+    $$.val.(*Insert).With = $1.with()
     $$.val.(*Insert).Table = $4.tblExpr()
     $$.val.(*Insert).OnConflict = &OnConflict{}
     $$.val.(*Insert).Returning = $6.retClause()
@@ -2525,7 +2926,13 @@ update_stmt:
   opt_with_clause UPDATE relation_expr_opt_alias
     SET set_clause_list update_from_clause where_clause returning_clause
   {
-    $$.val = &Update{Table: $3.tblExpr(), Exprs: $5.updateExprs(), Where: newWhere(astWhere, $7.expr()), Returning: $8.retClause()}
+    $$.val = &Update{
+      With: $1.with(),
+      Table: $3.tblExpr(),
+      Exprs: $5.updateExprs(),
+      Where: newWhere(astWhere, $7.expr()),
+      Returning: $8.retClause(),
+    }
   }
 
 // Mark this as unimplemented until the normal from_clause is supported here.
@@ -2647,15 +3054,15 @@ select_no_parens:
   }
 | with_clause select_clause
   {
-    $$.val = &Select{Select: $2.selectStmt()}
+    $$.val = &Select{With: $1.with(), Select: $2.selectStmt()}
   }
 | with_clause select_clause sort_clause
   {
-    $$.val = &Select{Select: $2.selectStmt(), OrderBy: $3.orderBy()}
+    $$.val = &Select{With: $1.with(), Select: $2.selectStmt(), OrderBy: $3.orderBy()}
   }
 | with_clause select_clause opt_sort_clause select_limit
   {
-    $$.val = &Select{Select: $2.selectStmt(), OrderBy: $3.orderBy(), Limit: $4.limit()}
+    $$.val = &Select{With: $1.with(), Select: $2.selectStmt(), OrderBy: $3.orderBy(), Limit: $4.limit()}
   }
 
 select_clause:
@@ -2758,25 +3165,53 @@ simple_select:
 //
 // Recognizing WITH_LA here allows a CTE to be named TIME or ORDINALITY.
 with_clause:
-WITH cte_list { return unimplemented(sqllex) }
-| WITH_LA cte_list { return unimplemented(sqllex) }
-| WITH RECURSIVE cte_list { return unimplemented(sqllex) }
+  WITH cte_list
+  {
+    $$.val = &With{CTEList: $2.ctes()}
+  }
+| WITH_LA cte_list
+  {
+    $$.val = &With{CTEList: $2.ctes()}
+  }
+| WITH RECURSIVE cte_list
+  {
+    $$.val = &With{Recursive: true, CTEList: $3.ctes()}
+  }
 
 cte_list:
-  common_table_expr { return unimplemented(sqllex) }
-| cte_list ',' common_table_expr { return unimplemented(sqllex) }
+  common_table_expr
+  {
+    $$.val = []*CTE{$1.cte()}
+  }
+| cte_list ',' common_table_expr
+  {
+    $$.val = append($1.ctes(), $3.cte())
+  }
 
 common_table_expr:
-  name opt_name_list AS '(' preparable_stmt ')' { return unimplemented(sqllex) }
+  name opt_name_list AS '(' preparable_stmt ')'
+  {
+    $$.val = &CTE{
+      Name: AliasClause{Alias: Name($1), Cols: $2.nameList()},
+      Stmt: $5.stmt(),
+    }
+  }
 
 opt_with:
   WITH {}
 | /* EMPTY */ {}
 
-opt_with_clause:
-  with_clause { return unimplemented(sqllex) }
+opt_by:
+  BY {}
 | /* EMPTY */ {}
 
+opt_with_clause:
+  with_clause
+| /* EMPTY */
+  {
+    $$.val = (*With)(nil)
+  }
+
 opt_table:
   TABLE {}
 | /* EMPTY */ {}
@@ -3339,8 +3774,24 @@ typename:
     }
   }
   // SQL standard syntax, currently only one-dimensional
-| simple_typename ARRAY '[' ICONST ']' { return unimplementedWithIssue(sqllex, 2115) }
-| simple_typename ARRAY { return unimplementedWithIssue(sqllex, 2115) }
+| simple_typename ARRAY '[' ICONST ']'
+  {
+    var err error
+    $$.val, err = arrayOf($1.colType(), Exprs{NewDInt(DInt(-1))})
+    if err != nil {
+      sqllex.Error(err.Error())
+      return 1
+    }
+  }
+| simple_typename ARRAY
+  {
+    var err error
+    $$.val, err = arrayOf($1.colType(), Exprs{NewDInt(DInt(-1))})
+    if err != nil {
+      sqllex.Error(err.Error())
+      return 1
+    }
+  }
 
 cast_target:
   typename
@@ -3354,7 +3805,8 @@ cast_target:
 
 opt_array_bounds:
   opt_array_bounds '[' ']' { $$.val = Exprs{NewDInt(DInt(-1))} }
-| opt_array_bounds '[' ICONST ']' { return unimplementedWithIssue(sqllex, 2115) }
+  // As in Postgres, the declared size of an array is ignored.
+| opt_array_bounds '[' ICONST ']' { $$.val = Exprs{NewDInt(DInt(-1))} }
 | /* EMPTY */ { $$.val = Exprs(nil) }
 
 simple_typename:
@@ -3404,6 +3856,22 @@ simple_typename:
   {
     $$.val = int2vectorColType
   }
+| JSON
+  {
+    $$.val = jsonColType
+  }
+| JSONB
+  {
+    $$.val = jsonColType
+  }
+| UUID
+  {
+    $$.val = uuidColTypeUUID
+  }
+| INET
+  {
+    $$.val = ipnetColTypeINet
+  }
 
 // We have a separate const_typename to allow defaulting fixed-length types
 // such as CHAR() and BIT() to an unspecified length. SQL9x requires that these
@@ -3862,6 +4330,14 @@ a_expr:
   {
     $$.val = &BinaryExpr{Operator: RShift, Left: $1.expr(), Right: $3.expr()}
   }
+| a_expr FETCHVAL a_expr
+  {
+    $$.val = &BinaryExpr{Operator: JSONFetchVal, Left: $1.expr(), Right: $3.expr()}
+  }
+| a_expr FETCHTEXT a_expr
+  {
+    $$.val = &BinaryExpr{Operator: JSONFetchText, Left: $1.expr(), Right: $3.expr()}
+  }
 | a_expr LESS_EQUALS a_expr
   {
     $$.val = &ComparisonExpr{Operator: LE, Left: $1.expr(), Right: $3.expr()}
@@ -3874,6 +4350,18 @@ a_expr:
   {
     $$.val = &ComparisonExpr{Operator: NE, Left: $1.expr(), Right: $3.expr()}
   }
+| a_expr CONTAINS a_expr
+  {
+    $$.val = &ComparisonExpr{Operator: Contains, Left: $1.expr(), Right: $3.expr()}
+  }
+| a_expr CONTAINED_BY a_expr
+  {
+    $$.val = &ComparisonExpr{Operator: ContainedBy, Left: $1.expr(), Right: $3.expr()}
+  }
+| a_expr '?' a_expr
+  {
+    $$.val = &ComparisonExpr{Operator: JSONExists, Left: $1.expr(), Right: $3.expr()}
+  }
 | a_expr AND a_expr
   {
     $$.val = &AndExpr{Left: $1.expr(), Right: $3.expr()}
@@ -4123,6 +4611,14 @@ b_expr:
   {
     $$.val = &BinaryExpr{Operator: RShift, Left: $1.expr(), Right: $3.expr()}
   }
+| b_expr FETCHVAL b_expr
+  {
+    $$.val = &BinaryExpr{Operator: JSONFetchVal, Left: $1.expr(), Right: $3.expr()}
+  }
+| b_expr FETCHTEXT b_expr
+  {
+    $$.val = &BinaryExpr{Operator: JSONFetchText, Left: $1.expr(), Right: $3.expr()}
+  }
 | b_expr LESS_EQUALS b_expr
   {
     $$.val = &ComparisonExpr{Operator: LE, Left: $1.expr(), Right: $3.expr()}
@@ -4135,6 +4631,18 @@ b_expr:
   {
     $$.val = &ComparisonExpr{Operator: NE, Left: $1.expr(), Right: $3.expr()}
   }
+| b_expr CONTAINS b_expr
+  {
+    $$.val = &ComparisonExpr{Operator: Contains, Left: $1.expr(), Right: $3.expr()}
+  }
+| b_expr CONTAINED_BY b_expr
+  {
+    $$.val = &ComparisonExpr{Operator: ContainedBy, Left: $1.expr(), Right: $3.expr()}
+  }
+| b_expr '?' b_expr
+  {
+    $$.val = &ComparisonExpr{Operator: JSONExists, Left: $1.expr(), Right: $3.expr()}
+  }
 | b_expr IS DISTINCT FROM b_expr %prec IS
   {
     $$.val = &ComparisonExpr{Operator: IsDistinctFrom, Left: $1.expr(), Right: $5.expr()}
@@ -4301,8 +4809,18 @@ func_expr_common_subexpr:
   {
     $$.val = &FuncExpr{Func: wrapFunction($1)}
   }
-| CURRENT_ROLE { return unimplemented(sqllex) }
-| CURRENT_USER { return unimplemented(sqllex) }
+| CURRENT_ROLE
+  {
+    $$.val = &FuncExpr{Func: wrapFunction("CURRENT_USER")}
+  }
+| CURRENT_USER
+  {
+    $$.val = &FuncExpr{Func: wrapFunction($1)}
+  }
+| CURRENT_USER '(' ')'
+  {
+    $$.val = &FuncExpr{Func: wrapFunction($1)}
+  }
 | SESSION_USER { return unimplemented(sqllex) }
 | USER { return unimplemented(sqllex) }
 | CAST '(' a_expr AS cast_target ')'
@@ -4374,6 +4892,12 @@ func_expr_common_subexpr:
   {
     $$.val = &FuncExpr{Func: wrapFunction($1), Exprs: $3.exprs()}
   }
+// FAMILY is a reserved keyword because of its use in column family
+// definitions, so the INET family() builtin needs its own production.
+| FAMILY '(' a_expr ')'
+  {
+    $$.val = &FuncExpr{Func: wrapFunction($1), Exprs: Exprs{$3.expr()}}
+  }
 
 // Aggregate decoration clauses
 within_group_clause:
@@ -5018,7 +5542,10 @@ opt_name_list:
   {
     $$.val = $2.nameList()
   }
-| /* EMPTY */ {}
+| /* EMPTY */
+  {
+    $$.val = NameList(nil)
+  }
 
 // The production for a qualified func_name has to exactly match the production
 // for a qualified name, because we cannot tell which we are parsing until
@@ -5088,6 +5615,17 @@ signed_iconst:
     $$.val = &NumVal{Value: constant.UnaryOp(token.SUB, $2.numVal().Value, 0)}
   }
 
+signed_iconst64:
+  signed_iconst
+  {
+    val, err := $1.numVal().AsInt64()
+    if err != nil {
+      sqllex.Error(err.Error())
+      return 1
+    }
+    $$.val = val
+  }
+
 interval:
   const_interval SCONST opt_interval
   {
@@ -5176,12 +5714,15 @@ unrestricted_name:
 unreserved_keyword:
   ACTION
 | ADD
+| ADMIN
 | ALTER
 | AT
 | BACKUP
 | BEGIN
 | BLOB
 | BY
+| CACHE
+| CANCEL
 | CASCADE
 | CLUSTER
 | COLUMNS
@@ -5191,6 +5732,7 @@ unreserved_keyword:
 | CONSTRAINTS
 | COPY
 | COVERING
+| CSV
 | CUBE
 | CURRENT
 | CYCLE
@@ -5204,30 +5746,42 @@ unreserved_keyword:
 | DROP
 | ENCODING
 | EXECUTE
+| EXPERIMENTAL_AUDIT
 | EXPLAIN
 | FILTER
 | FIRST
 | FOLLOWING
 | FORCE_INDEX
+| FORMAT
 | GRANTS
 | HELP
 | HIGH
 | HOUR
+| IMPORT
+| INCREMENT
 | INCREMENTAL
 | INDEXES
+| INET
 | INSERT
 | INT2VECTOR
 | INTERLEAVE
+| INVERTED
 | ISOLATION
+| JSON
+| JSONB
+| JOB
 | KEY
 | KEYS
 | LC_COLLATE
 | LC_CTYPE
 | LEVEL
+| LIST
 | LOCAL
 | LOW
 | MATCH
+| MAXVALUE
 | MINUTE
+| MINVALUE
 | MONTH
 | NAMES
 | NAN
@@ -5239,6 +5793,7 @@ unreserved_keyword:
 | OF
 | OFF
 | OID
+| OPTION
 | OPTIONS
 | ORDINALITY
 | OVER
@@ -5246,9 +5801,12 @@ unreserved_keyword:
 | PARTIAL
 | PARTITION
 | PASSWORD
+| PAUSE
 | PRECEDING
 | PREPARE
 | PRIORITY
+| QUERIES
+| QUERY
 | RANGE
 | READ
 | RECURSIVE
@@ -5264,7 +5822,9 @@ unreserved_keyword:
 | RESET
 | RESTORE
 | RESTRICT
+| RESUME
 | REVOKE
+| ROLE
 | ROLLBACK
 | ROLLUP
 | ROWS
@@ -5275,15 +5835,19 @@ unreserved_keyword:
 | SCATTER
 | SEARCH
 | SECOND
+| SEQUENCE
 | SERIALIZABLE
 | SESSION
+| SESSIONS
 | SET
 | SHOW
 | SIMPLE
 | SNAPSHOT
 | SQL
 | START
+| STATISTICS
 | STDIN
+| STDOUT
 | STORING
 | STRICT
 | SPLIT
@@ -5302,12 +5866,14 @@ unreserved_keyword:
 | UPDATE
 | UPSERT
 | USERS
+| UUID
 | VALID
 | VALIDATE
 | VALUE
 | VARYING
 | WITHIN
 | WITHOUT
+| WRITE
 | YEAR
 | ZONE
 
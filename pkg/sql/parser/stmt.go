@@ -51,6 +51,8 @@ const (
 	Rows
 	// CopyIn indicates a COPY FROM statement.
 	CopyIn
+	// CopyOut indicates a COPY TO statement.
+	CopyOut
 	// Unknown indicates that the statement does not have a known
 	// return style at the time of parsing. This is not first in the
 	// enumeration because it is more convenient to have Ack as a zero
@@ -107,6 +109,28 @@ func (*BeginTransaction) StatementTag() string { return "BEGIN" }
 
 func (*BeginTransaction) hiddenFromStats() {}
 
+// StatementType implements the Statement interface.
+func (*CancelJob) StatementType() StatementType { return Ack }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*CancelJob) StatementTag() string { return "CANCEL JOB" }
+
+// StatementType implements the Statement interface.
+func (*CancelQuery) StatementType() StatementType { return Ack }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*CancelQuery) StatementTag() string { return "CANCEL QUERY" }
+
+func (*CancelQuery) independentFromParallelizedPriors() {}
+
+// StatementType implements the Statement interface.
+func (*CancelSession) StatementType() StatementType { return Ack }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*CancelSession) StatementTag() string { return "CANCEL SESSION" }
+
+func (*CancelSession) independentFromParallelizedPriors() {}
+
 // StatementType implements the Statement interface.
 func (*CommitTransaction) StatementType() StatementType { return Ack }
 
@@ -121,6 +145,12 @@ func (*CopyFrom) StatementType() StatementType { return CopyIn }
 // StatementTag returns a short string identifying the type of statement.
 func (*CopyFrom) StatementTag() string { return "COPY" }
 
+// StatementType implements the Statement interface.
+func (*CopyTo) StatementType() StatementType { return CopyOut }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*CopyTo) StatementTag() string { return "COPY" }
+
 // StatementType implements the Statement interface.
 func (*CreateDatabase) StatementType() StatementType { return DDL }
 
@@ -144,6 +174,24 @@ func (n *CreateTable) StatementTag() string {
 	return "CREATE TABLE"
 }
 
+// StatementType implements the Statement interface.
+func (*CreateSequence) StatementType() StatementType { return DDL }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*CreateSequence) StatementTag() string { return "CREATE SEQUENCE" }
+
+// StatementType implements the Statement interface.
+func (*CreateRole) StatementType() StatementType { return Ack }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*CreateRole) StatementTag() string { return "CREATE ROLE" }
+
+// StatementType implements the Statement interface.
+func (*CreateStats) StatementType() StatementType { return DDL }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*CreateStats) StatementTag() string { return "CREATE STATISTICS" }
+
 // StatementType implements the Statement interface.
 func (*CreateUser) StatementType() StatementType { return Ack }
 
@@ -188,6 +236,18 @@ func (*DropIndex) StatementType() StatementType { return DDL }
 // StatementTag returns a short string identifying the type of statement.
 func (*DropIndex) StatementTag() string { return "DROP INDEX" }
 
+// StatementType implements the Statement interface.
+func (*DropRole) StatementType() StatementType { return Ack }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*DropRole) StatementTag() string { return "DROP ROLE" }
+
+// StatementType implements the Statement interface.
+func (*DropSequence) StatementType() StatementType { return DDL }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*DropSequence) StatementTag() string { return "DROP SEQUENCE" }
+
 // StatementType implements the Statement interface.
 func (*DropTable) StatementType() StatementType { return DDL }
 
@@ -222,6 +282,20 @@ func (*Grant) StatementTag() string { return "GRANT" }
 
 func (*Grant) hiddenFromStats() {}
 
+// StatementType implements the Statement interface.
+func (*GrantRole) StatementType() StatementType { return DDL }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*GrantRole) StatementTag() string { return "GRANT" }
+
+func (*GrantRole) hiddenFromStats() {}
+
+// StatementType implements the Statement interface.
+func (*Import) StatementType() StatementType { return Rows }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*Import) StatementTag() string { return "IMPORT" }
+
 // StatementType implements the Statement interface.
 func (n *Insert) StatementType() StatementType { return n.Returning.statementType() }
 
@@ -234,6 +308,12 @@ func (*ParenSelect) StatementType() StatementType { return Rows }
 // StatementTag returns a short string identifying the type of statement.
 func (*ParenSelect) StatementTag() string { return "SELECT" }
 
+// StatementType implements the Statement interface.
+func (*PauseJob) StatementType() StatementType { return Ack }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*PauseJob) StatementTag() string { return "PAUSE JOB" }
+
 // StatementType implements the Statement interface.
 func (*Prepare) StatementType() StatementType { return Ack }
 
@@ -291,6 +371,12 @@ func (*Restore) StatementType() StatementType { return Rows }
 // StatementTag returns a short string identifying the type of statement.
 func (*Restore) StatementTag() string { return "RESTORE" }
 
+// StatementType implements the Statement interface.
+func (*ResumeJob) StatementType() StatementType { return Ack }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*ResumeJob) StatementTag() string { return "RESUME JOB" }
+
 // StatementType implements the Statement interface.
 func (*Revoke) StatementType() StatementType { return DDL }
 
@@ -299,6 +385,14 @@ func (*Revoke) StatementTag() string { return "REVOKE" }
 
 func (*Revoke) hiddenFromStats() {}
 
+// StatementType implements the Statement interface.
+func (*RevokeRole) StatementType() StatementType { return DDL }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*RevokeRole) StatementTag() string { return "REVOKE" }
+
+func (*RevokeRole) hiddenFromStats() {}
+
 // StatementType implements the Statement interface.
 func (*RollbackToSavepoint) StatementType() StatementType { return Ack }
 
@@ -452,6 +546,24 @@ func (*ShowUsers) StatementTag() string { return "SHOW USERS" }
 func (*ShowUsers) hiddenFromStats()                   {}
 func (*ShowUsers) independentFromParallelizedPriors() {}
 
+// StatementType implements the Statement interface.
+func (*ShowQueries) StatementType() StatementType { return Rows }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*ShowQueries) StatementTag() string { return "SHOW QUERIES" }
+
+func (*ShowQueries) hiddenFromStats()                   {}
+func (*ShowQueries) independentFromParallelizedPriors() {}
+
+// StatementType implements the Statement interface.
+func (*ShowSessions) StatementType() StatementType { return Rows }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*ShowSessions) StatementTag() string { return "SHOW SESSIONS" }
+
+func (*ShowSessions) hiddenFromStats()                   {}
+func (*ShowSessions) independentFromParallelizedPriors() {}
+
 // StatementType implements the Statement interface.
 func (*ShowRanges) StatementType() StatementType { return Rows }
 
@@ -527,25 +639,37 @@ func (n *AlterTableDropNotNull) String() string    { return AsString(n) }
 func (n *AlterTableSetDefault) String() string     { return AsString(n) }
 func (n *Backup) String() string                   { return AsString(n) }
 func (n *BeginTransaction) String() string         { return AsString(n) }
+func (n *CancelJob) String() string                { return AsString(n) }
+func (n *CancelQuery) String() string              { return AsString(n) }
+func (n *CancelSession) String() string            { return AsString(n) }
 func (n *CommitTransaction) String() string        { return AsString(n) }
 func (n *CopyFrom) String() string                 { return AsString(n) }
+func (n *CopyTo) String() string                   { return AsString(n) }
 func (n *CreateDatabase) String() string           { return AsString(n) }
 func (n *CreateIndex) String() string              { return AsString(n) }
+func (n *CreateSequence) String() string           { return AsString(n) }
 func (n *CreateTable) String() string              { return AsString(n) }
+func (n *CreateRole) String() string               { return AsString(n) }
+func (n *CreateStats) String() string              { return AsString(n) }
 func (n *CreateUser) String() string               { return AsString(n) }
 func (n *CreateView) String() string               { return AsString(n) }
 func (n *Deallocate) String() string               { return AsString(n) }
 func (n *Delete) String() string                   { return AsString(n) }
 func (n *DropDatabase) String() string             { return AsString(n) }
 func (n *DropIndex) String() string                { return AsString(n) }
+func (n *DropRole) String() string                 { return AsString(n) }
+func (n *DropSequence) String() string             { return AsString(n) }
 func (n *DropTable) String() string                { return AsString(n) }
 func (n *DropView) String() string                 { return AsString(n) }
 func (n *Execute) String() string                  { return AsString(n) }
 func (n *Explain) String() string                  { return AsString(n) }
 func (n *Grant) String() string                    { return AsString(n) }
+func (n *GrantRole) String() string                { return AsString(n) }
 func (n *Help) String() string                     { return AsString(n) }
+func (n *Import) String() string                   { return AsString(n) }
 func (n *Insert) String() string                   { return AsString(n) }
 func (n *ParenSelect) String() string              { return AsString(n) }
+func (n *PauseJob) String() string                 { return AsString(n) }
 func (n *Prepare) String() string                  { return AsString(n) }
 func (n *ReleaseSavepoint) String() string         { return AsString(n) }
 func (n *Relocate) String() string                 { return AsString(n) }
@@ -554,7 +678,9 @@ func (n *RenameDatabase) String() string           { return AsString(n) }
 func (n *RenameIndex) String() string              { return AsString(n) }
 func (n *RenameTable) String() string              { return AsString(n) }
 func (n *Restore) String() string                  { return AsString(n) }
+func (n *ResumeJob) String() string                { return AsString(n) }
 func (n *Revoke) String() string                   { return AsString(n) }
+func (n *RevokeRole) String() string               { return AsString(n) }
 func (n *RollbackToSavepoint) String() string      { return AsString(n) }
 func (n *RollbackTransaction) String() string      { return AsString(n) }
 func (n *Savepoint) String() string                { return AsString(n) }
@@ -576,6 +702,8 @@ func (n *ShowConstraints) String() string          { return AsString(n) }
 func (n *ShowTables) String() string               { return AsString(n) }
 func (n *ShowTransactionStatus) String() string    { return AsString(n) }
 func (n *ShowUsers) String() string                { return AsString(n) }
+func (n *ShowQueries) String() string              { return AsString(n) }
+func (n *ShowSessions) String() string             { return AsString(n) }
 func (n *ShowRanges) String() string               { return AsString(n) }
 func (n *Split) String() string                    { return AsString(n) }
 func (l StatementList) String() string             { return AsString(l) }
@@ -89,6 +89,12 @@ var (
 	TypeTimestampTZ Type = tTimestampTZ{}
 	// TypeInterval is the type of a DInterval. Can be compared with ==.
 	TypeInterval Type = tInterval{}
+	// TypeJSON is the type of a DJSON. Can be compared with ==.
+	TypeJSON Type = tJSON{}
+	// TypeUUID is the type of a DUuid. Can be compared with ==.
+	TypeUUID Type = tUUID{}
+	// TypeINet is the type of a DIPAddr. Can be compared with ==.
+	TypeINet Type = tINet{}
 	// TypeTuple is the type family of a DTuple. CANNOT be compared with ==.
 	TypeTuple Type = TTuple(nil)
 	// TypeTable is the type family of a DTable. CANNOT be compared with ==.
@@ -143,6 +149,9 @@ var (
 		TypeTimestamp,
 		TypeTimestampTZ,
 		TypeInterval,
+		TypeJSON,
+		TypeUUID,
+		TypeINet,
 		TypeOid,
 	}
 )
@@ -165,11 +174,13 @@ var OidToType = map[oid.Oid]Type{
 	oid.T_date:         TypeDate,
 	oid.T_float4:       typeFloat4,
 	oid.T_float8:       TypeFloat,
+	oid.T_inet:         TypeINet,
 	oid.T_int2:         typeInt2,
 	oid.T_int4:         typeInt4,
 	oid.T_int8:         TypeInt,
 	oid.T_int2vector:   TypeIntVector,
 	oid.T_interval:     TypeInterval,
+	oid.T_jsonb:        TypeJSON,
 	oid.T_name:         TypeName,
 	oid.T_numeric:      TypeDecimal,
 	oid.T_oid:          TypeOid,
@@ -178,14 +189,25 @@ var OidToType = map[oid.Oid]Type{
 	oid.T_regproc:      TypeRegProc,
 	oid.T_regprocedure: TypeRegProcedure,
 	oid.T_regtype:      TypeRegType,
-	oid.T__text:        TypeStringArray,
+	oid.T__bool:        TArray{TypeBool},
+	oid.T__bytea:       TArray{TypeBytes},
+	oid.T__date:        TArray{TypeDate},
+	oid.T__float8:      TArray{TypeFloat},
+	oid.T__inet:        TArray{TypeINet},
 	oid.T__int2:        typeInt2Array,
 	oid.T__int4:        typeInt4Array,
 	oid.T__int8:        TypeIntArray,
+	oid.T__interval:    TArray{TypeInterval},
+	oid.T__numeric:     TArray{TypeDecimal},
+	oid.T__text:        TypeStringArray,
+	oid.T__timestamp:   TArray{TypeTimestamp},
+	oid.T__timestamptz: TArray{TypeTimestampTZ},
+	oid.T__uuid:        TArray{TypeUUID},
 	oid.T_record:       TypeTuple,
 	oid.T_text:         TypeString,
 	oid.T_timestamp:    TypeTimestamp,
 	oid.T_timestamptz:  TypeTimestampTZ,
+	oid.T_uuid:         TypeUUID,
 	oid.T_varchar:      typeVarChar,
 }
 
@@ -205,10 +227,21 @@ var aliasedOidToName = map[oid.Oid]string{
 	oid.T_varchar:    "varchar",
 	oid.T_numeric:    "numeric",
 	oid.T_record:     "record",
-	oid.T__int2:      "_int2",
-	oid.T__int4:      "_int4",
-	oid.T__int8:      "_int8",
-	oid.T__text:      "_text",
+
+	oid.T__bool:        "_bool",
+	oid.T__bytea:       "_bytea",
+	oid.T__date:        "_date",
+	oid.T__float8:      "_float8",
+	oid.T__inet:        "_inet",
+	oid.T__int2:        "_int2",
+	oid.T__int4:        "_int4",
+	oid.T__int8:        "_int8",
+	oid.T__interval:    "_interval",
+	oid.T__numeric:     "_numeric",
+	oid.T__text:        "_text",
+	oid.T__timestamp:   "_timestamp",
+	oid.T__timestamptz: "_timestamptz",
+	oid.T__uuid:        "_uuid",
 }
 
 // PGDisplayName returns the Postgres display name for a given type.
@@ -383,6 +416,42 @@ func (tInterval) Oid() oid.Oid                { return oid.T_interval }
 func (tInterval) SQLName() string             { return "interval" }
 func (tInterval) IsAmbiguous() bool           { return false }
 
+type tJSON struct{}
+
+func (tJSON) String() string { return "jsonb" }
+func (tJSON) Equivalent(other Type) bool {
+	return UnwrapType(other) == TypeJSON || other == TypeAny
+}
+func (tJSON) FamilyEqual(other Type) bool { return UnwrapType(other) == TypeJSON }
+func (tJSON) Size() (uintptr, bool)       { return unsafe.Sizeof(DJSON{}), variableSize }
+func (tJSON) Oid() oid.Oid                { return oid.T_jsonb }
+func (tJSON) SQLName() string             { return "jsonb" }
+func (tJSON) IsAmbiguous() bool           { return false }
+
+type tUUID struct{}
+
+func (tUUID) String() string { return "uuid" }
+func (tUUID) Equivalent(other Type) bool {
+	return UnwrapType(other) == TypeUUID || other == TypeAny
+}
+func (tUUID) FamilyEqual(other Type) bool { return UnwrapType(other) == TypeUUID }
+func (tUUID) Size() (uintptr, bool)       { return unsafe.Sizeof(DUuid{}), fixedSize }
+func (tUUID) Oid() oid.Oid                { return oid.T_uuid }
+func (tUUID) SQLName() string             { return "uuid" }
+func (tUUID) IsAmbiguous() bool           { return false }
+
+type tINet struct{}
+
+func (tINet) String() string { return "inet" }
+func (tINet) Equivalent(other Type) bool {
+	return UnwrapType(other) == TypeINet || other == TypeAny
+}
+func (tINet) FamilyEqual(other Type) bool { return UnwrapType(other) == TypeINet }
+func (tINet) Size() (uintptr, bool)       { return unsafe.Sizeof(DIPAddr{}), fixedSize }
+func (tINet) Oid() oid.Oid                { return oid.T_inet }
+func (tINet) SQLName() string             { return "inet" }
+func (tINet) IsAmbiguous() bool           { return false }
+
 // TTuple is the type of a DTuple.
 type TTuple []Type
 
@@ -518,11 +587,21 @@ func (TArray) Size() (uintptr, bool) {
 
 // oidToArrayOid maps scalar type Oids to their corresponding array type Oid.
 var oidToArrayOid = map[oid.Oid]oid.Oid{
-	oid.T_int2: oid.T__int2,
-	oid.T_int4: oid.T__int4,
-	oid.T_int8: oid.T__int8,
-	oid.T_text: oid.T__text,
-	oid.T_name: oid.T__name,
+	oid.T_bool:        oid.T__bool,
+	oid.T_bytea:       oid.T__bytea,
+	oid.T_date:        oid.T__date,
+	oid.T_float8:      oid.T__float8,
+	oid.T_inet:        oid.T__inet,
+	oid.T_int2:        oid.T__int2,
+	oid.T_int4:        oid.T__int4,
+	oid.T_int8:        oid.T__int8,
+	oid.T_interval:    oid.T__interval,
+	oid.T_numeric:     oid.T__numeric,
+	oid.T_text:        oid.T__text,
+	oid.T_name:        oid.T__name,
+	oid.T_timestamp:   oid.T__timestamp,
+	oid.T_timestamptz: oid.T__timestamptz,
+	oid.T_uuid:        oid.T__uuid,
 }
 
 // Oid implements the Type interface.
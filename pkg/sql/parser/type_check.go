@@ -221,7 +221,7 @@ func (expr *CastExpr) TypeCheck(ctx *SemaContext, _ Type) (TypedExpr, error) {
 			// precision), the CastExpr becomes a no-op and can be elided.
 			switch expr.Type.(type) {
 			case *BoolColType, *DateColType, *TimestampColType, *TimestampTZColType,
-				*IntervalColType, *BytesColType:
+				*IntervalColType, *BytesColType, *JSONColType, *UUIDColType, *INetColType:
 				return expr.Expr.TypeCheck(ctx, returnType)
 			}
 		}
@@ -795,6 +795,18 @@ func (d *DTimestampTZ) TypeCheck(_ *SemaContext, _ Type) (TypedExpr, error) { re
 // identity function for Datum.
 func (d *DInterval) TypeCheck(_ *SemaContext, _ Type) (TypedExpr, error) { return d, nil }
 
+// TypeCheck implements the Expr interface. It is implemented as an idempotent
+// identity function for Datum.
+func (d *DJSON) TypeCheck(_ *SemaContext, _ Type) (TypedExpr, error) { return d, nil }
+
+// TypeCheck implements the Expr interface. It is implemented as an idempotent
+// identity function for Datum.
+func (d *DUuid) TypeCheck(_ *SemaContext, _ Type) (TypedExpr, error) { return d, nil }
+
+// TypeCheck implements the Expr interface. It is implemented as an idempotent
+// identity function for Datum.
+func (d *DIPAddr) TypeCheck(_ *SemaContext, _ Type) (TypedExpr, error) { return d, nil }
+
 // TypeCheck implements the Expr interface. It is implemented as an idempotent
 // identity function for Datum.
 func (d *DTuple) TypeCheck(_ *SemaContext, _ Type) (TypedExpr, error) { return d, nil }
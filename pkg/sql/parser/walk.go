@@ -508,6 +508,15 @@ func (expr *DInt) Walk(_ Visitor) Expr { return expr }
 // Walk implements the Expr interface.
 func (expr *DInterval) Walk(_ Visitor) Expr { return expr }
 
+// Walk implements the Expr interface.
+func (expr *DJSON) Walk(_ Visitor) Expr { return expr }
+
+// Walk implements the Expr interface.
+func (expr *DUuid) Walk(_ Visitor) Expr { return expr }
+
+// Walk implements the Expr interface.
+func (expr *DIPAddr) Walk(_ Visitor) Expr { return expr }
+
 // Walk implements the Expr interface.
 func (expr dNull) Walk(_ Visitor) Expr { return expr }
 
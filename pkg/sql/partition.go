@@ -0,0 +1,192 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+)
+
+// addPartitioning populates the Partitioning of the given index of tableDesc
+// from a PARTITION BY clause. The partitioning columns must be a prefix of
+// the index columns and each partition value is stored key-encoded in the
+// direction of its column, so that it can be turned directly into a span of
+// the index.
+func addPartitioning(
+	tableDesc *sqlbase.TableDescriptor,
+	index *sqlbase.IndexDescriptor,
+	partBy *parser.PartitionBy,
+	searchPath parser.SearchPath,
+	evalCtx *parser.EvalContext,
+) error {
+	if len(index.Interleave.Ancestors) > 0 {
+		return fmt.Errorf("interleaved indexes cannot be partitioned")
+	}
+	if len(partBy.Fields) > len(index.ColumnNames) {
+		return fmt.Errorf("declared partition columns (%s) exceed the number of columns "+
+			"in index being partitioned (%s)",
+			parser.AsString(partBy.Fields), quoteNames(index.ColumnNames...))
+	}
+	for i, field := range partBy.Fields {
+		if field.Normalize() != parser.ReNormalizeName(index.ColumnNames[i]) {
+			return fmt.Errorf("declared partition columns (%s) do not match first %d columns "+
+				"in index being partitioned (%s)",
+				parser.AsString(partBy.Fields), len(partBy.Fields), quoteNames(index.ColumnNames...))
+		}
+	}
+
+	// encodeValues type checks and key encodes one value for each partitioning
+	// column.
+	encodeValues := func(partition parser.Name, exprs parser.Exprs) ([]byte, error) {
+		if len(exprs) != len(partBy.Fields) {
+			return nil, fmt.Errorf("partition %q: number of values (%d) does not match "+
+				"number of partition columns (%d)", partition, len(exprs), len(partBy.Fields))
+		}
+		var key []byte
+		for i, expr := range exprs {
+			col, err := tableDesc.FindColumnByID(index.ColumnIDs[i])
+			if err != nil {
+				return nil, err
+			}
+			typedExpr, err := sqlbase.SanitizeVarFreeExpr(
+				expr, col.Type.ToDatumType(), "partition", searchPath)
+			if err != nil {
+				return nil, fmt.Errorf("partition %q: %v", partition, err)
+			}
+			datum, err := typedExpr.Eval(evalCtx)
+			if err != nil {
+				return nil, err
+			}
+			dir, err := index.ColumnDirections[i].ToEncodingDirection()
+			if err != nil {
+				return nil, err
+			}
+			if key, err = sqlbase.EncodeTableKey(key, datum, dir); err != nil {
+				return nil, err
+			}
+		}
+		return key, nil
+	}
+
+	part := sqlbase.PartitioningDescriptor{NumColumns: uint32(len(partBy.Fields))}
+	for _, l := range partBy.List {
+		p := sqlbase.PartitioningDescriptor_List{Name: string(l.Name)}
+		for _, expr := range l.Exprs {
+			if _, ok := expr.(parser.DefaultVal); ok {
+				// An empty value is used for DEFAULT.
+				p.Values = append(p.Values, nil)
+				continue
+			}
+			values := parser.Exprs{expr}
+			if t, ok := expr.(*parser.Tuple); ok && len(partBy.Fields) > 1 {
+				values = t.Exprs
+			}
+			value, err := encodeValues(l.Name, values)
+			if err != nil {
+				return err
+			}
+			p.Values = append(p.Values, value)
+		}
+		part.List = append(part.List, p)
+	}
+	for _, r := range partBy.Range {
+		p := sqlbase.PartitioningDescriptor_Range{Name: string(r.Name)}
+		if r.Exprs != nil {
+			upperBound, err := encodeValues(r.Name, r.Exprs)
+			if err != nil {
+				return err
+			}
+			p.UpperBound = upperBound
+		}
+		part.Range = append(part.Range, p)
+	}
+	index.Partitioning = part
+	return nil
+}
+
+// showCreatePartitioning returns a PARTITION BY clause for the specified
+// index, if applicable.
+func showCreatePartitioning(
+	tableDesc *sqlbase.TableDescriptor, index *sqlbase.IndexDescriptor,
+) (string, error) {
+	part := &index.Partitioning
+	if part.NumColumns == 0 {
+		return "", nil
+	}
+
+	var a sqlbase.DatumAlloc
+	decodeValues := func(key []byte) (parser.Exprs, error) {
+		exprs := make(parser.Exprs, part.NumColumns)
+		for i := range exprs {
+			col, err := tableDesc.FindColumnByID(index.ColumnIDs[i])
+			if err != nil {
+				return nil, err
+			}
+			dir, err := index.ColumnDirections[i].ToEncodingDirection()
+			if err != nil {
+				return nil, err
+			}
+			exprs[i], key, err = sqlbase.DecodeTableKey(&a, col.Type.ToDatumType(), key, dir)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return exprs, nil
+	}
+
+	partBy := parser.PartitionBy{
+		Fields: make(parser.NameList, part.NumColumns),
+	}
+	for i := range partBy.Fields {
+		partBy.Fields[i] = parser.Name(index.ColumnNames[i])
+	}
+	for _, l := range part.List {
+		p := parser.ListPartition{Name: parser.Name(l.Name)}
+		for _, v := range l.Values {
+			if len(v) == 0 {
+				p.Exprs = append(p.Exprs, parser.DefaultVal{})
+				continue
+			}
+			exprs, err := decodeValues(v)
+			if err != nil {
+				return "", err
+			}
+			if len(exprs) == 1 {
+				p.Exprs = append(p.Exprs, exprs[0])
+			} else {
+				p.Exprs = append(p.Exprs, &parser.Tuple{Exprs: exprs})
+			}
+		}
+		partBy.List = append(partBy.List, p)
+	}
+	for _, r := range part.Range {
+		p := parser.RangePartition{Name: parser.Name(r.Name)}
+		if len(r.UpperBound) > 0 {
+			exprs, err := decodeValues(r.UpperBound)
+			if err != nil {
+				return "", err
+			}
+			p.Exprs = exprs
+		}
+		partBy.Range = append(partBy.Range, p)
+	}
+
+	var buf bytes.Buffer
+	parser.FormatNode(&buf, parser.FmtSimple, &partBy)
+	return buf.String(), nil
+}
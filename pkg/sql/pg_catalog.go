@@ -56,6 +56,7 @@ var pgCatalog = virtualSchema{
 		pgCatalogAmTable,
 		pgCatalogAttrDefTable,
 		pgCatalogAttributeTable,
+		pgCatalogAuthMembersTable,
 		pgCatalogClassTable,
 		pgCatalogCollationTable,
 		pgCatalogConstraintTable,
@@ -220,11 +221,37 @@ CREATE TABLE pg_catalog.pg_attribute (
 }
 
 var (
-	relKindTable = parser.NewDString("r")
-	relKindIndex = parser.NewDString("i")
-	relKindView  = parser.NewDString("v")
+	relKindTable    = parser.NewDString("r")
+	relKindIndex    = parser.NewDString("i")
+	relKindView     = parser.NewDString("v")
+	relKindSequence = parser.NewDString("S")
 )
 
+// See: https://www.postgresql.org/docs/9.6/static/catalog-pg-auth-members.html.
+var pgCatalogAuthMembersTable = virtualSchemaTable{
+	schema: `
+CREATE TABLE pg_catalog.pg_auth_members (
+	roleid OID,
+	member OID,
+	grantor OID,
+	admin_option BOOL
+);
+`,
+	populate: func(ctx context.Context, p *planner, addRow func(...parser.Datum) error) error {
+		// As for pg_roles, role memberships are visible to every user.
+		h := makeOidHasher()
+		return forEachRoleMembership(ctx, p,
+			func(role, member string, isAdmin bool) error {
+				return addRow(
+					h.UserOid(role),                         // roleid
+					h.UserOid(member),                       // member
+					parser.DNull,                            // grantor
+					parser.MakeDBool(parser.DBool(isAdmin)), // admin_option
+				)
+			})
+	},
+}
+
 // See: https://www.postgresql.org/docs/9.6/static/catalog-pg-class.html.
 var pgCatalogClassTable = virtualSchemaTable{
 	schema: `
@@ -265,6 +292,8 @@ CREATE TABLE pg_catalog.pg_class (
 			if table.IsView() {
 				// The only difference between tables and views is the relkind column.
 				relKind = relKindView
+			} else if table.IsSequence() {
+				relKind = relKindSequence
 			}
 			if err := addRow(
 				h.TableOid(db, table),       // oid
@@ -1130,10 +1159,10 @@ CREATE TABLE pg_catalog.pg_proc (
 
 				var argmodes parser.Datum
 				var variadicType parser.Datum
-				switch argTypes.(type) {
+				switch v := argTypes.(type) {
 				case parser.VariadicType:
 					argmodes = proArgModeVariadic
-					argType := argTypes.Types()[0]
+					argType := v.Typ
 					oid := argType.Oid()
 					variadicType = parser.NewDOid(parser.DInt(oid))
 				case parser.HomogeneousType:
@@ -1231,21 +1260,21 @@ CREATE TABLE pg_catalog.pg_roles (
 		// include sensitive information such as password hashes.
 		h := makeOidHasher()
 		return forEachUser(ctx, p,
-			func(username string) error {
+			func(username string, isRole bool) error {
 				isRoot := parser.DBool(username == security.RootUser)
 				return addRow(
-					h.UserOid(username),           // oid
-					parser.NewDName(username),     // rolname
-					parser.MakeDBool(isRoot),      // rolsuper
-					parser.MakeDBool(false),       // rolinherit
-					parser.MakeDBool(isRoot),      // rolcreaterole
-					parser.MakeDBool(isRoot),      // rolcreatedb
-					parser.MakeDBool(false),       // rolcatupdate
-					parser.MakeDBool(true),        // rolcanlogin
-					negOneVal,                     // rolconnlimit
-					parser.NewDString("********"), // rolpassword
-					parser.DNull,                  // rolvaliduntil
-					parser.NewDString("{}"),       // rolconfig
+					h.UserOid(username),                     // oid
+					parser.NewDName(username),               // rolname
+					parser.MakeDBool(isRoot),                // rolsuper
+					parser.MakeDBool(true),                  // rolinherit
+					parser.MakeDBool(isRoot),                // rolcreaterole
+					parser.MakeDBool(isRoot),                // rolcreatedb
+					parser.MakeDBool(false),                 // rolcatupdate
+					parser.MakeDBool(parser.DBool(!isRole)), // rolcanlogin
+					negOneVal,                               // rolconnlimit
+					parser.NewDString("********"),           // rolpassword
+					parser.DNull,                            // rolvaliduntil
+					parser.NewDString("{}"),                 // rolconfig
 				)
 			})
 	},
@@ -1326,7 +1355,7 @@ CREATE TABLE pg_catalog.pg_tables (
 `,
 	populate: func(ctx context.Context, p *planner, addRow func(...parser.Datum) error) error {
 		return forEachTableDesc(ctx, p, func(db *sqlbase.DatabaseDescriptor, table *sqlbase.TableDescriptor) error {
-			if table.IsView() {
+			if !table.IsTable() {
 				return nil
 			}
 			return addRow(
@@ -1498,7 +1527,17 @@ func typOid(typ parser.Type) parser.Datum {
 	return parser.NewDOid(parser.DInt(typ.Oid()))
 }
 
+// pgVarlenaTypes contains the types which have a fixed size in CockroachDB but
+// are variable-length ("varlena") types in Postgres. Clients read typlen and
+// typbyval, so these must report what Postgres does.
+var pgVarlenaTypes = map[parser.Type]struct{}{
+	parser.TypeINet: {},
+}
+
 func typLen(typ parser.Type) *parser.DInt {
+	if _, ok := pgVarlenaTypes[typ]; ok {
+		return negOneVal
+	}
 	if sz, variable := typ.Size(); !variable {
 		return parser.NewDInt(parser.DInt(sz))
 	}
@@ -1506,6 +1545,9 @@ func typLen(typ parser.Type) *parser.DInt {
 }
 
 func typByVal(typ parser.Type) parser.Datum {
+	if _, ok := pgVarlenaTypes[typ]; ok {
+		return parser.DBoolFalse
+	}
 	_, variable := typ.Size()
 	return parser.MakeDBool(parser.DBool(!variable))
 }
@@ -1542,6 +1584,9 @@ var datumToTypeCategory = map[reflect.Type]*parser.DString{
 	reflect.TypeOf(parser.TypeTuple):       typCategoryPseudo,
 	reflect.TypeOf(parser.TypeTable):       typCategoryPseudo,
 	reflect.TypeOf(parser.TypeOid):         typCategoryNumeric,
+	reflect.TypeOf(parser.TypeJSON):        typCategoryUserDefined,
+	reflect.TypeOf(parser.TypeUUID):        typCategoryUserDefined,
+	reflect.TypeOf(parser.TypeINet):        typCategoryNetworkAddr,
 }
 
 func typCategory(typ parser.Type) parser.Datum {
@@ -125,6 +125,28 @@ func TestBinaryDate(t *testing.T) {
 	})
 }
 
+func TestBinaryUUID(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	testBinaryDatumType(t, "uuid", func(val string) parser.Datum {
+		u, err := parser.ParseDUuidFromString(val)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return u
+	})
+}
+
+func TestBinaryINet(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	testBinaryDatumType(t, "inet", func(val string) parser.Datum {
+		ipAddr, err := parser.ParseDIPAddrFromINetString(val)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return ipAddr
+	})
+}
+
 func TestBinaryIntArray(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	buf := writeBuffer{bytecount: metric.NewCounter(metric.Metadata{})}
@@ -147,6 +169,48 @@ func TestBinaryIntArray(t *testing.T) {
 	}
 }
 
+func TestBinaryArray(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	buf := writeBuffer{bytecount: metric.NewCounter(metric.Metadata{})}
+	testCases := []struct {
+		typ   parser.Type
+		elems parser.Datums
+	}{
+		{parser.TypeBool, parser.Datums{parser.DBoolTrue, parser.DNull, parser.DBoolFalse}},
+		{parser.TypeString, parser.Datums{parser.NewDString("foo"), parser.NewDString("")}},
+		{parser.TypeFloat, parser.Datums{parser.NewDFloat(1.5)}},
+		{parser.TypeDate, parser.Datums{parser.NewDDate(17000), parser.NewDDate(0)}},
+		{parser.TypeUUID, parser.Datums{parser.DNull}},
+		{parser.TypeBytes, parser.Datums{}},
+	}
+	for _, tc := range testCases {
+		d := parser.NewDArray(tc.typ)
+		for _, elem := range tc.elems {
+			if err := d.Append(elem); err != nil {
+				t.Fatal(err)
+			}
+		}
+		buf.wrapped.Reset()
+		buf.writeBinaryDatum(d, time.UTC)
+		if buf.err != nil {
+			t.Fatal(buf.err)
+		}
+
+		b := buf.wrapped.Bytes()
+		arrOid := d.ResolvedType().Oid()
+		if arrOid == oid.T_anyarray {
+			t.Fatalf("%s: no array OID for element type %s", d, tc.typ)
+		}
+		got, err := decodeOidDatum(arrOid, formatBinary, b[4:])
+		if err != nil {
+			t.Fatalf("%s: %s", d, err)
+		}
+		if got.Compare(&parser.EvalContext{}, d) != 0 {
+			t.Errorf("expected %s, got %s", d, got)
+		}
+	}
+}
+
 var generateBinaryCmd = flag.String("generate-binary", "", "generate-binary command invocation")
 
 func TestRandomBinaryDecimal(t *testing.T) {
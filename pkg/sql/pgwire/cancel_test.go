@@ -0,0 +1,245 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package pgwire_test
+
+import (
+	"bufio"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+)
+
+// rawConn is a minimal client of the PostgreSQL wire protocol, which gives
+// access to the BackendKeyData message that lib/pq doesn't expose.
+type rawConn struct {
+	conn net.Conn
+	rd   *bufio.Reader
+	// processID and secret are the contents of the BackendKeyData message.
+	processID, secret uint32
+}
+
+func writeRawMsg(w io.Writer, typ byte, body []byte) error {
+	var buf []byte
+	if typ != 0 {
+		buf = append(buf, typ)
+	}
+	var length [4]byte
+	binary.BigEndian.PutUint32(length[:], uint32(len(body)+4))
+	buf = append(buf, length[:]...)
+	buf = append(buf, body...)
+	_, err := w.Write(buf)
+	return err
+}
+
+func (c *rawConn) readMsg() (byte, []byte, error) {
+	var header [5]byte
+	if _, err := io.ReadFull(c.rd, header[:]); err != nil {
+		return 0, nil, err
+	}
+	body := make([]byte, binary.BigEndian.Uint32(header[1:])-4)
+	if _, err := io.ReadFull(c.rd, body); err != nil {
+		return 0, nil, err
+	}
+	return header[0], body, nil
+}
+
+// readUntil reads messages until one of the given type or an ErrorResponse
+// is received, and returns the type of that message.
+func (c *rawConn) readUntil(typ byte) (byte, error) {
+	for {
+		msgTyp, body, err := c.readMsg()
+		if err != nil {
+			return 0, err
+		}
+		switch msgTyp {
+		case typ, 'E':
+			return msgTyp, nil
+		case 'K':
+			c.processID = binary.BigEndian.Uint32(body)
+			c.secret = binary.BigEndian.Uint32(body[4:])
+		}
+	}
+}
+
+// openRawConn opens a connection to the server at the given address as root,
+// using the root client certificate of the given URL.
+func openRawConn(addr string, pgURL url.URL) (*rawConn, error) {
+	options := pgURL.Query()
+	cert, err := tls.LoadX509KeyPair(options.Get("sslcert"), options.Get("sslkey"))
+	if err != nil {
+		return nil, err
+	}
+	caPEM, err := ioutil.ReadFile(options.Get("sslrootcert"))
+	if err != nil {
+		return nil, err
+	}
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(caPEM) {
+		return nil, errors.New("could not parse CA certificate")
+	}
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	// SSLRequest.
+	var sslRequest [4]byte
+	binary.BigEndian.PutUint32(sslRequest[:], 80877103)
+	if err := writeRawMsg(conn, 0, sslRequest[:]); err != nil {
+		return nil, err
+	}
+	var response [1]byte
+	if _, err := io.ReadFull(conn, response[:]); err != nil {
+		return nil, err
+	}
+	if response[0] != 'S' {
+		return nil, errors.Errorf("unexpected SSLRequest response %q", response[0])
+	}
+	tlsConn := tls.Client(conn, &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		RootCAs:      roots,
+		ServerName:   host,
+	})
+
+	// StartupMessage for protocol version 3.0.
+	startup := []byte{0, 3, 0, 0}
+	startup = append(startup, "user\x00"+security.RootUser+"\x00\x00"...)
+	if err := writeRawMsg(tlsConn, 0, startup); err != nil {
+		return nil, err
+	}
+	c := &rawConn{conn: tlsConn, rd: bufio.NewReader(tlsConn)}
+	if typ, err := c.readUntil('Z'); err != nil {
+		return nil, err
+	} else if typ != 'Z' {
+		return nil, errors.New("could not open session")
+	}
+	return c, nil
+}
+
+// sendCancelRequest sends a CancelRequest with the given key to the server at
+// the given address.
+func sendCancelRequest(addr string, processID, secret uint32) error {
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	var body [12]byte
+	binary.BigEndian.PutUint32(body[:], 80877102)
+	binary.BigEndian.PutUint32(body[4:], processID)
+	binary.BigEndian.PutUint32(body[8:], secret)
+	if err := writeRawMsg(conn, 0, body[:]); err != nil {
+		return err
+	}
+	// The server closes the connection without responding.
+	_, err = conn.Read(make([]byte, 1))
+	if err == io.EOF {
+		return nil
+	}
+	return err
+}
+
+// TestPGWireCancelRequestForwarding verifies that a CancelRequest sent to a
+// node other than the one holding the session is forwarded to that node.
+func TestPGWireCancelRequestForwarding(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	tc := serverutils.StartTestCluster(t, 2, base.TestClusterArgs{})
+	defer tc.Stopper().Stop(context.TODO())
+
+	sqlDB := sqlutils.MakeSQLRunner(t, tc.ServerConn(0))
+	sqlDB.Exec(`CREATE DATABASE d; CREATE TABLE d.t (k INT PRIMARY KEY)`)
+
+	// Leave an intent on the table, so that reading it blocks.
+	tx, err := tc.ServerConn(0).Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	if _, err := tx.Exec(`INSERT INTO d.t VALUES (1)`); err != nil {
+		t.Fatal(err)
+	}
+
+	addr := tc.Server(1).ServingAddr()
+	pgURL, cleanupFn := sqlutils.PGUrl(t, addr, t.Name(), url.User(security.RootUser))
+	defer cleanupFn()
+	c, err := openRawConn(addr, pgURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.conn.Close()
+
+	// The node ID of the session is encoded in the cancel key.
+	if nodeID := roachpb.NodeID(c.processID); nodeID != tc.Server(1).NodeID() {
+		t.Fatalf("expected node ID %d in cancel key, got %d", tc.Server(1).NodeID(), nodeID)
+	}
+
+	if err := writeRawMsg(c.conn, 'Q', []byte("SELECT * FROM d.t\x00")); err != nil {
+		t.Fatal(err)
+	}
+	type result struct {
+		typ byte
+		err error
+	}
+	resultCh := make(chan result, 1)
+	go func() {
+		typ, err := c.readUntil('C')
+		resultCh <- result{typ, err}
+	}()
+
+	// Send cancel requests to the other node until the query, which blocks on
+	// the intent, returns. A request sent before the query started is a no-op.
+	deadline := time.After(45 * time.Second)
+	for {
+		if err := sendCancelRequest(
+			tc.Server(0).ServingAddr(), c.processID, c.secret,
+		); err != nil {
+			t.Fatal(err)
+		}
+		select {
+		case res := <-resultCh:
+			if res.err != nil {
+				t.Fatal(res.err)
+			}
+			if res.typ != 'E' {
+				t.Fatal("expected the query to be canceled")
+			}
+			return
+		case <-time.After(100 * time.Millisecond):
+		case <-deadline:
+			t.Fatal("query was not canceled")
+		}
+	}
+}
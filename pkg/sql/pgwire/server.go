@@ -44,6 +44,10 @@ const (
 	// ErrDraining is returned when a client attempts to connect to a server
 	// which is not accepting client connections.
 	ErrDraining = "server is not accepting clients"
+
+	// ErrSessionCanceled is returned to a client whose session has been
+	// canceled through CANCEL SESSION.
+	ErrSessionCanceled = "terminating connection due to CANCEL SESSION"
 )
 
 // Fully-qualified names for metrics.
@@ -60,8 +64,9 @@ var (
 )
 
 const (
-	version30  = 196608
-	versionSSL = 80877103
+	version30     = 196608
+	versionCancel = 80877102
+	versionSSL    = 80877103
 )
 
 const (
@@ -191,7 +196,7 @@ func Match(rd io.Reader) bool {
 	if err != nil {
 		return false
 	}
-	return version == version30 || version == versionSSL
+	return version == version30 || version == versionSSL || version == versionCancel
 }
 
 // IsDraining returns true if the server is not currently accepting
@@ -346,6 +351,27 @@ func (s *Server) ServeConn(ctx context.Context, conn net.Conn) error {
 	if err != nil {
 		return err
 	}
+	if version == versionCancel {
+		// A CancelRequest carries the key of the session whose query is to be
+		// canceled, which may be open on another node. As in Postgres, no
+		// response is sent, whether or not the key matched a session.
+		processID, err := buf.getUint32()
+		if err != nil {
+			return err
+		}
+		secret, err := buf.getUint32()
+		if err != nil {
+			return err
+		}
+		found, err := s.executor.CancelQueriesByKey(ctx, uint64(processID)<<32|uint64(secret))
+		if err != nil {
+			log.Warningf(ctx, "pgwire: cancel request failed: %v", err)
+		} else if !found {
+			log.VEventf(ctx, 1, "pgwire: no session found for cancel request")
+		}
+		return conn.Close()
+	}
+
 	errSSLRequired := false
 	if version == versionSSL {
 		if len(buf.msg) > 0 {
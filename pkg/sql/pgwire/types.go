@@ -30,6 +30,7 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
 	"github.com/cockroachdb/cockroach/pkg/util/duration"
+	"github.com/cockroachdb/cockroach/pkg/util/ipaddr"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/lib/pq"
 	"github.com/lib/pq/oid"
@@ -89,6 +90,18 @@ func pgTypeForParserType(t parser.Type) pgType {
 
 const secondsInDay = 24 * 60 * 60
 
+// jsonbBinaryVersion is the version of the binary format of JSONB values,
+// which is the only one Postgres supports.
+const jsonbBinaryVersion = 1
+
+// Address family identifiers used by the binary format of INET values. These
+// are Postgres' PGSQL_AF_INET and PGSQL_AF_INET6, which deliberately differ
+// from any platform's AF_INET and AF_INET6.
+const (
+	pgBinaryIPv4family byte = 2
+	pgBinaryIPv6family byte = 3
+)
+
 func (b *writeBuffer) writeTextDatum(d parser.Datum, sessionLoc *time.Location) {
 	if log.V(2) {
 		log.Infof(context.TODO(), "pgwire writing TEXT datum of type: %T, %#v", d, d)
@@ -202,6 +215,15 @@ func (b *writeBuffer) writeTextDatum(d parser.Datum, sessionLoc *time.Location)
 	case *parser.DOid:
 		b.writeLengthPrefixedDatum(v)
 
+	case *parser.DJSON:
+		b.writeLengthPrefixedString(v.JSON.String())
+
+	case *parser.DUuid:
+		b.writeLengthPrefixedString(v.UUID.String())
+
+	case *parser.DIPAddr:
+		b.writeLengthPrefixedString(v.IPAddr.String())
+
 	default:
 		b.setError(errors.Errorf("unsupported type %T", d))
 	}
@@ -335,7 +357,8 @@ func (b *writeBuffer) writeBinaryDatum(d parser.Datum, sessionLoc *time.Location
 		subWriter.putInt32(int32(hasNulls))
 		subWriter.putInt32(int32(v.ParamTyp.Oid()))
 		subWriter.putInt32(int32(v.Len()))
-		subWriter.putInt32(int32(v.Len()))
+		// Lower bound, we only support a lower bound of 1.
+		subWriter.putInt32(1)
 		for _, elem := range v.Array {
 			subWriter.writeBinaryDatum(elem, sessionLoc)
 		}
@@ -344,6 +367,31 @@ func (b *writeBuffer) writeBinaryDatum(d parser.Datum, sessionLoc *time.Location
 	case *parser.DOid:
 		b.putInt32(4)
 		b.putInt32(int32(v.DInt))
+	case *parser.DJSON:
+		// The binary format of JSONB is a version number followed by the text
+		// format.
+		s := v.JSON.String()
+		b.putInt32(int32(len(s) + 1))
+		b.writeByte(jsonbBinaryVersion)
+		b.writeString(s)
+	case *parser.DUuid:
+		b.putInt32(16)
+		b.write(v.GetBytes())
+	case *parser.DIPAddr:
+		// The binary format of INET values is the address family, the mask
+		// length, a flag that is set for CIDR values, the number of address
+		// bytes and finally the address itself.
+		ip := v.IP()
+		family := pgBinaryIPv4family
+		if v.Family == ipaddr.IPv6family {
+			family = pgBinaryIPv6family
+		}
+		b.putInt32(int32(4 + len(ip)))
+		b.writeByte(family)
+		b.writeByte(v.Mask)
+		b.writeByte(0)
+		b.writeByte(byte(len(ip)))
+		b.write(ip)
 	default:
 		b.setError(errors.Errorf("unsupported type %T", d))
 	}
@@ -526,6 +574,24 @@ func decodeOidDatum(id oid.Oid, code formatCode, b []byte) (parser.Datum, error)
 				return nil, errors.Errorf("could not parse string %q as interval", b)
 			}
 			return d, nil
+		case oid.T_jsonb:
+			d, err := parser.ParseDJSON(string(b))
+			if err != nil {
+				return nil, errors.Errorf("could not parse string %q as jsonb", b)
+			}
+			return d, nil
+		case oid.T_uuid:
+			d, err := parser.ParseDUuidFromString(string(b))
+			if err != nil {
+				return nil, errors.Errorf("could not parse string %q as uuid", b)
+			}
+			return d, nil
+		case oid.T_inet:
+			d, err := parser.ParseDIPAddrFromINetString(string(b))
+			if err != nil {
+				return nil, errors.Errorf("could not parse string %q as inet", b)
+			}
+			return d, nil
 		case oid.T__int2, oid.T__int4, oid.T__int8:
 			var arr pq.Int64Array
 			if err := (&arr).Scan(b); err != nil {
@@ -700,8 +766,27 @@ func decodeOidDatum(id oid.Oid, code formatCode, b []byte) (parser.Datum, error)
 			}
 			i := int32(binary.BigEndian.Uint32(b))
 			return pgBinaryToDate(i), nil
-		case oid.T__int2, oid.T__int4, oid.T__int8, oid.T__text, oid.T__name:
+		case oid.T__bool, oid.T__bytea, oid.T__date, oid.T__float8, oid.T__inet,
+			oid.T__int2, oid.T__int4, oid.T__int8, oid.T__name, oid.T__numeric,
+			oid.T__text, oid.T__timestamp, oid.T__timestamptz, oid.T__uuid:
 			return decodeBinaryArray(b, code)
+		case oid.T_jsonb:
+			if len(b) < 1 || b[0] != jsonbBinaryVersion {
+				return nil, errors.Errorf("unsupported binary jsonb format")
+			}
+			d, err := parser.ParseDJSON(string(b[1:]))
+			if err != nil {
+				return nil, errors.Errorf("could not parse string %q as jsonb", b[1:])
+			}
+			return d, nil
+		case oid.T_uuid:
+			d, err := parser.ParseDUuidFromBytes(b)
+			if err != nil {
+				return nil, errors.Errorf("could not parse bytes %x as uuid", b)
+			}
+			return d, nil
+		case oid.T_inet:
+			return decodeBinaryINet(b)
 		}
 	default:
 		return nil, errors.Errorf("unsupported format code: %s", code)
@@ -718,6 +803,28 @@ func decodeOidDatum(id oid.Oid, code formatCode, b []byte) (parser.Datum, error)
 	}
 }
 
+func decodeBinaryINet(b []byte) (parser.Datum, error) {
+	if len(b) < 4 {
+		return nil, errors.Errorf("insufficient bytes to decode inet: %d", len(b))
+	}
+	var ipAddr ipaddr.IPAddr
+	switch b[0] {
+	case pgBinaryIPv4family:
+		ipAddr.Family = ipaddr.IPv4family
+	case pgBinaryIPv6family:
+		ipAddr.Family = ipaddr.IPv6family
+	default:
+		return nil, errors.Errorf("unknown inet address family %d", b[0])
+	}
+	ipAddr.Mask = b[1]
+	n := int(b[3])
+	if n != len(ipAddr.IP()) || len(b) != 4+n || ipAddr.Mask > ipAddr.Family.MaxMask() {
+		return nil, errors.Errorf("invalid binary inet value %x", b)
+	}
+	copy(ipAddr.Addr[len(ipAddr.Addr)-n:], b[4:])
+	return parser.NewDIPAddr(parser.DIPAddr{IPAddr: ipAddr}), nil
+}
+
 func decodeBinaryArray(b []byte, code formatCode) (parser.Datum, error) {
 	hdr := struct {
 		Ndims int32
@@ -741,12 +848,23 @@ func decodeBinaryArray(b []byte, code formatCode) (parser.Datum, error) {
 	}
 
 	elemOid := oid.Oid(hdr.ElemOid)
-	arr := parser.NewDArray(parser.OidToType[elemOid])
+	elemTyp, ok := parser.OidToType[elemOid]
+	if !ok {
+		return nil, errors.Errorf("unsupported array element type OID %d", elemOid)
+	}
+	arr := parser.NewDArray(elemTyp)
 	var vlen int32
 	for i := int32(0); i < hdr.DimSize; i++ {
 		if err := binary.Read(r, binary.BigEndian, &vlen); err != nil {
 			return nil, err
 		}
+		if vlen < 0 {
+			// A length of -1 denotes a NULL element.
+			if err := arr.Append(parser.DNull); err != nil {
+				return nil, err
+			}
+			continue
+		}
 		buf := r.Next(int(vlen))
 		elem, err := decodeOidDatum(elemOid, code, buf)
 		if err != nil {
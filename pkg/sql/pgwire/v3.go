@@ -19,6 +19,7 @@ package pgwire
 
 import (
 	"bufio"
+	"bytes"
 	"crypto/tls"
 	"fmt"
 	"net"
@@ -64,10 +65,14 @@ const (
 	clientMsgTerminate   clientMessageType = 'X'
 
 	serverMsgAuth                 serverMessageType = 'R'
+	serverMsgBackendKeyData       serverMessageType = 'K'
 	serverMsgBindComplete         serverMessageType = '2'
 	serverMsgCommandComplete      serverMessageType = 'C'
 	serverMsgCloseComplete        serverMessageType = '3'
+	serverMsgCopyData             serverMessageType = 'd'
+	serverMsgCopyDone             serverMessageType = 'c'
 	serverMsgCopyInResponse       serverMessageType = 'G'
+	serverMsgCopyOutResponse      serverMessageType = 'H'
 	serverMsgDataRow              serverMessageType = 'D'
 	serverMsgEmptyQuery           serverMessageType = 'I'
 	serverMsgErrorResponse        serverMessageType = 'E'
@@ -183,6 +188,12 @@ type v3Conn struct {
 	metrics *ServerMetrics
 
 	sqlMemoryPool *mon.MemoryMonitor
+
+	// copyOutFormat is the format of the COPY TO in progress, if any.
+	// copyOutBuf and copyOutRow are scratch space used to encode its rows.
+	copyOutFormat sql.CopyFormat
+	copyOutBuf    writeBuffer
+	copyOutRow    []byte
 }
 
 func makeV3Conn(
@@ -318,6 +329,7 @@ func (c *v3Conn) setupSession(ctx context.Context, reserved mon.BoundAccount) er
 		ctx, c.sessionArgs, c.executor, c.conn.RemoteAddr(), &c.metrics.SQLMemMetrics,
 	)
 	c.session.StartMonitor(c.sqlMemoryPool, reserved)
+	c.session.SetCopyOutWriter(c)
 	return nil
 }
 
@@ -355,14 +367,33 @@ func (c *v3Conn) serve(ctx context.Context, draining func() bool, reserved mon.B
 		c.closeSession(ctx)
 	}()
 
+	// The session's cancel key is sent to the client in a BackendKeyData
+	// message, as the process ID and secret key.
+	if key, ok, err := c.session.RegisterCancelKey(); err != nil {
+		return err
+	} else if ok {
+		c.writeBuf.initMsg(serverMsgBackendKeyData)
+		c.writeBuf.putInt32(int32(key >> 32))
+		c.writeBuf.putInt32(int32(key))
+		if err := c.writeBuf.finishMsg(c.wr); err != nil {
+			return err
+		}
+	}
+
 	// Once a session has been set up, the underlying net.Conn is switched to
-	// a conn that exits if the session's context is cancelled or if the server
-	// is draining and the session does not have an ongoing transaction.
+	// a conn that exits if the session's context is cancelled, if the session
+	// has been canceled through CANCEL SESSION, or if the server is draining
+	// and the session does not have an ongoing transaction.
 	c.conn = newReadTimeoutConn(c.conn, func() error {
 		if err := func() error {
 			if draining() && c.session.TxnState.State == sql.NoTxn {
 				return errors.New(ErrDraining)
 			}
+			select {
+			case <-c.session.Canceled():
+				return errors.New(ErrSessionCanceled)
+			default:
+			}
 			return c.session.Ctx().Err()
 		}(); err != nil {
 			return newAdminShutdownErr(err)
@@ -1020,6 +1051,21 @@ func (c *v3Conn) sendResponse(
 				return err
 			}
 
+		case parser.CopyOut:
+			// The rows have already been sent during execution; see
+			// WriteCopyRow.
+			c.writeBuf.initMsg(serverMsgCopyDone)
+			if err := c.writeBuf.finishMsg(c.wr); err != nil {
+				return err
+			}
+
+			// Send CommandComplete.
+			tag = append(tag, ' ')
+			tag = strconv.AppendInt(tag, int64(result.RowsAffected), 10)
+			if err := c.sendCommandComplete(tag); err != nil {
+				return err
+			}
+
 		default:
 			panic(fmt.Sprintf("unexpected result type %v", result.Type))
 		}
@@ -1127,6 +1173,109 @@ func (c *v3Conn) copyIn(ctx context.Context, columns []sqlbase.ResultColumn) (in
 	}
 }
 
+var _ sql.CopyOutWriter = &v3Conn{}
+
+// BeginCopyOut implements the sql.CopyOutWriter interface. It switches the
+// connection to the copy-out mode, which lasts until the CopyDone message
+// sent along with the result of the COPY TO, or until an error is sent.
+// See: https://www.postgresql.org/docs/current/static/protocol-flow.html#PROTOCOL-COPY
+func (c *v3Conn) BeginCopyOut(columns sqlbase.ResultColumns, format sql.CopyFormat) error {
+	c.copyOutFormat = format
+	c.writeBuf.initMsg(serverMsgCopyOutResponse)
+	c.writeBuf.writeByte(byte(formatText))
+	c.writeBuf.putInt16(int16(len(columns)))
+	for range columns {
+		c.writeBuf.putInt16(int16(formatText))
+	}
+	return c.writeBuf.finishMsg(c.wr)
+}
+
+// WriteCopyRow implements the sql.CopyOutWriter interface. It sends a row
+// of a COPY TO in a CopyData message.
+func (c *v3Conn) WriteCopyRow(row parser.Datums) error {
+	buf := c.copyOutRow[:0]
+	for i, d := range row {
+		if i > 0 {
+			buf = append(buf, copyDelimiter(c.copyOutFormat))
+		}
+		if d == parser.DNull {
+			if c.copyOutFormat == sql.CopyFormatText {
+				buf = append(buf, `\N`...)
+			}
+			continue
+		}
+		// writeTextDatum prefixes the value with its length.
+		c.copyOutBuf.reset()
+		c.copyOutBuf.writeTextDatum(d, c.session.Location)
+		if c.copyOutBuf.err != nil {
+			return c.copyOutBuf.err
+		}
+		buf = appendCopyField(buf, c.copyOutBuf.wrapped.Bytes()[4:], c.copyOutFormat)
+	}
+	buf = append(buf, '\n')
+	c.copyOutRow = buf
+
+	c.writeBuf.initMsg(serverMsgCopyData)
+	c.writeBuf.write(buf)
+	return c.writeBuf.finishMsg(c.wr)
+}
+
+// copyDelimiter returns the delimiter between the fields of a row in the
+// given COPY format.
+func copyDelimiter(format sql.CopyFormat) byte {
+	if format == sql.CopyFormatCSV {
+		return ','
+	}
+	return '\t'
+}
+
+// appendCopyField appends a non-NULL field of a COPY TO row, escaped
+// according to the given format, to buf.
+func appendCopyField(buf []byte, field []byte, format sql.CopyFormat) []byte {
+	if format == sql.CopyFormatCSV {
+		// Fields are quoted when they contain special characters, and also
+		// when empty to distinguish them from NULLs. Quotes are doubled.
+		quote := len(field) == 0 || bytes.Equal(field, []byte(`\.`)) ||
+			bytes.IndexAny(field, ",\"\r\n") >= 0
+		if !quote {
+			return append(buf, field...)
+		}
+		buf = append(buf, '"')
+		for _, ch := range field {
+			if ch == '"' {
+				buf = append(buf, '"')
+			}
+			buf = append(buf, ch)
+		}
+		return append(buf, '"')
+	}
+
+	for _, ch := range field {
+		var esc byte
+		switch ch {
+		case '\\':
+			esc = '\\'
+		case '\b':
+			esc = 'b'
+		case '\f':
+			esc = 'f'
+		case '\n':
+			esc = 'n'
+		case '\r':
+			esc = 'r'
+		case '\t':
+			esc = 't'
+		case '\v':
+			esc = 'v'
+		default:
+			buf = append(buf, ch)
+			continue
+		}
+		buf = append(buf, '\\', esc)
+	}
+	return buf
+}
+
 func newUnrecognizedMsgTypeErr(typ clientMessageType) error {
 	return pgerror.NewErrorf(
 		pgerror.CodeProtocolViolationError, "unrecognized client message type %v", typ)
@@ -175,3 +175,29 @@ func TestReadTimeoutConnExits(t *testing.T) {
 		t.Fatalf("unexpected error: %v", err)
 	}
 }
+
+func TestAppendCopyField(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	testData := []struct {
+		field    string
+		format   sql.CopyFormat
+		expected string
+	}{
+		{`abc`, sql.CopyFormatText, `abc`},
+		{"", sql.CopyFormatText, ""},
+		{"a\tb\nc", sql.CopyFormatText, `a\tb\nc`},
+		{"a\\b\rc\bd\fe\vf", sql.CopyFormatText, `a\\b\rc\bd\fe\vf`},
+		{`abc`, sql.CopyFormatCSV, `abc`},
+		{"", sql.CopyFormatCSV, `""`},
+		{`a,b`, sql.CopyFormatCSV, `"a,b"`},
+		{`a"b`, sql.CopyFormatCSV, `"a""b"`},
+		{"a\nb", sql.CopyFormatCSV, "\"a\nb\""},
+		{`\.`, sql.CopyFormatCSV, `"\."`},
+		{"a\tb\\c", sql.CopyFormatCSV, "a\tb\\c"},
+	}
+	for _, d := range testData {
+		if actual := string(appendCopyField(nil, []byte(d.field), d.format)); actual != d.expected {
+			t.Errorf("%q (format %d): expected %q, got %q", d.field, d.format, d.expected, actual)
+		}
+	}
+}
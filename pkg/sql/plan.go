@@ -168,9 +168,15 @@ type planNodeFastPath interface {
 }
 
 var _ planNode = &alterTableNode{}
+var _ planNode = &cancelQueryNode{}
+var _ planNode = &cancelSessionNode{}
+var _ planNode = &controlJobNode{}
 var _ planNode = &copyNode{}
+var _ planNode = &copyToNode{}
 var _ planNode = &createDatabaseNode{}
 var _ planNode = &createIndexNode{}
+var _ planNode = &createSequenceNode{}
+var _ planNode = &createStatsNode{}
 var _ planNode = &createTableNode{}
 var _ planNode = &createViewNode{}
 var _ planNode = &delayedNode{}
@@ -178,6 +184,8 @@ var _ planNode = &deleteNode{}
 var _ planNode = &distinctNode{}
 var _ planNode = &dropDatabaseNode{}
 var _ planNode = &dropIndexNode{}
+var _ planNode = &dropRoleNode{}
+var _ planNode = &dropSequenceNode{}
 var _ planNode = &dropTableNode{}
 var _ planNode = &dropViewNode{}
 var _ planNode = &emptyNode{}
@@ -187,6 +195,7 @@ var _ planNode = &explainPlanNode{}
 var _ planNode = &explainTraceNode{}
 var _ planNode = &hookFnNode{}
 var _ planNode = &filterNode{}
+var _ planNode = &grantRoleNode{}
 var _ planNode = &groupNode{}
 var _ planNode = &hookFnNode{}
 var _ planNode = &indexJoinNode{}
@@ -194,8 +203,10 @@ var _ planNode = &insertNode{}
 var _ planNode = &joinNode{}
 var _ planNode = &limitNode{}
 var _ planNode = &ordinalityNode{}
+var _ planNode = &recursiveCTENode{}
 var _ planNode = &relocateNode{}
 var _ planNode = &renderNode{}
+var _ planNode = &revokeRoleNode{}
 var _ planNode = &scanNode{}
 var _ planNode = &scatterNode{}
 var _ planNode = &showRangesNode{}
@@ -288,14 +299,28 @@ func (p *planner) newPlan(
 		return p.AlterTable(ctx, n)
 	case *parser.BeginTransaction:
 		return p.BeginTransaction(n)
+	case *parser.CancelJob:
+		return p.CancelJob(ctx, n)
+	case *parser.CancelQuery:
+		return p.CancelQuery(ctx, n)
+	case *parser.CancelSession:
+		return p.CancelSession(ctx, n)
 	case CopyDataBlock:
 		return p.CopyData(ctx, n)
 	case *parser.CopyFrom:
 		return p.CopyFrom(ctx, n)
+	case *parser.CopyTo:
+		return p.CopyTo(ctx, n)
 	case *parser.CreateDatabase:
 		return p.CreateDatabase(n)
 	case *parser.CreateIndex:
 		return p.CreateIndex(ctx, n)
+	case *parser.CreateRole:
+		return p.CreateRole(ctx, n)
+	case *parser.CreateSequence:
+		return p.CreateSequence(ctx, n)
+	case *parser.CreateStats:
+		return p.CreateStatistics(ctx, n)
 	case *parser.CreateTable:
 		return p.CreateTable(ctx, n)
 	case *parser.CreateUser:
@@ -308,6 +333,10 @@ func (p *planner) newPlan(
 		return p.DropDatabase(ctx, n)
 	case *parser.DropIndex:
 		return p.DropIndex(ctx, n)
+	case *parser.DropRole:
+		return p.DropRole(ctx, n)
+	case *parser.DropSequence:
+		return p.DropSequence(ctx, n)
 	case *parser.DropTable:
 		return p.DropTable(ctx, n)
 	case *parser.DropView:
@@ -316,12 +345,16 @@ func (p *planner) newPlan(
 		return p.Explain(ctx, n)
 	case *parser.Grant:
 		return p.Grant(ctx, n)
+	case *parser.GrantRole:
+		return p.GrantRole(ctx, n)
 	case *parser.Help:
 		return p.Help(ctx, n)
 	case *parser.Insert:
 		return p.Insert(ctx, n, desiredTypes)
 	case *parser.ParenSelect:
 		return p.newPlan(ctx, n.Select, desiredTypes)
+	case *parser.PauseJob:
+		return p.PauseJob(ctx, n)
 	case *parser.Relocate:
 		return p.Relocate(ctx, n)
 	case *parser.RenameColumn:
@@ -332,8 +365,12 @@ func (p *planner) newPlan(
 		return p.RenameIndex(ctx, n)
 	case *parser.RenameTable:
 		return p.RenameTable(ctx, n)
+	case *parser.ResumeJob:
+		return p.ResumeJob(ctx, n)
 	case *parser.Revoke:
 		return p.Revoke(ctx, n)
+	case *parser.RevokeRole:
+		return p.RevokeRole(ctx, n)
 	case *parser.Scatter:
 		return p.Scatter(ctx, n)
 	case *parser.Select:
@@ -368,6 +405,10 @@ func (p *planner) newPlan(
 		return p.ShowTables(ctx, n)
 	case *parser.ShowUsers:
 		return p.ShowUsers(ctx, n)
+	case *parser.ShowQueries:
+		return p.ShowQueries(ctx, n)
+	case *parser.ShowSessions:
+		return p.ShowSessions(ctx, n)
 	case *parser.ShowRanges:
 		return p.ShowRanges(ctx, n)
 	case *parser.Split:
@@ -395,6 +436,12 @@ func (p *planner) prepare(ctx context.Context, stmt parser.Statement) (planNode,
 	}
 
 	switch n := stmt.(type) {
+	case *parser.CancelJob:
+		return p.CancelJob(ctx, n)
+	case *parser.CancelQuery:
+		return p.CancelQuery(ctx, n)
+	case *parser.CancelSession:
+		return p.CancelSession(ctx, n)
 	case *parser.Delete:
 		return p.Delete(ctx, n, nil)
 	case *parser.Explain:
@@ -403,6 +450,10 @@ func (p *planner) prepare(ctx context.Context, stmt parser.Statement) (planNode,
 		return p.Help(ctx, n)
 	case *parser.Insert:
 		return p.Insert(ctx, n, nil)
+	case *parser.PauseJob:
+		return p.PauseJob(ctx, n)
+	case *parser.ResumeJob:
+		return p.ResumeJob(ctx, n)
 	case *parser.Select:
 		return p.Select(ctx, n, nil)
 	case *parser.SelectClause:
@@ -427,6 +478,10 @@ func (p *planner) prepare(ctx context.Context, stmt parser.Statement) (planNode,
 		return p.ShowTables(ctx, n)
 	case *parser.ShowUsers:
 		return p.ShowUsers(ctx, n)
+	case *parser.ShowQueries:
+		return p.ShowQueries(ctx, n)
+	case *parser.ShowSessions:
+		return p.ShowSessions(ctx, n)
 	case *parser.ShowRanges:
 		return p.ShowRanges(ctx, n)
 	case *parser.Split:
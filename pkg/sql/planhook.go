@@ -48,6 +48,7 @@ type PlanHookState interface {
 	TypeAsString(e parser.Expr, op string) (func() (string, error), error)
 	TypeAsStringArray(e parser.Exprs, op string) (func() ([]string, error), error)
 	User() string
+	EvalContext() *parser.EvalContext
 	AuthorizationAccessor
 }
 
@@ -59,6 +59,10 @@ type planner struct {
 	// initializing plans to read from a table. This should be used with care.
 	skipSelectPrivilegeChecks bool
 
+	// cteNameEnvironment collects the common table expressions (WITH
+	// clauses) in scope for the statement being planned. See with.go.
+	cteNameEnvironment cteNameEnvironment
+
 	// autoCommit indicates whether we're planning for a spontaneous transaction.
 	// If autoCommit is true, the plan is allowed (but not required) to
 	// commit the transaction along with other KV operations.
@@ -70,6 +74,10 @@ type planner struct {
 	// See executor_statement_metrics.go for details.
 	phaseTimes phaseTimes
 
+	// auditEvents accumulates the accesses to tables with auditing enabled
+	// performed by the statement. See exec_log.go.
+	auditEvents []auditEvent
+
 	// Avoid allocations by embedding commonly used objects and visitors.
 	parser                parser.Parser
 	subqueryVisitor       subqueryVisitor
@@ -149,6 +157,11 @@ func (p *planner) User() string {
 	return p.session.User
 }
 
+// EvalContext implements the PlanHookState interface.
+func (p *planner) EvalContext() *parser.EvalContext {
+	return &p.evalCtx
+}
+
 // setTxn resets the current transaction in the planner and
 // initializes the timestamps used by SQL built-in functions from
 // the new txn object, if any.
@@ -257,19 +270,28 @@ func (p *planner) exec(ctx context.Context, sql string, args ...interface{}) (in
 	return countRowsAffected(ctx, plan)
 }
 
+// fillFKTableMap looks up the tables of the map, as well as the other
+// tables that the referential actions of their foreign keys may need.
 func (p *planner) fillFKTableMap(ctx context.Context, m sqlbase.TableLookupsByID) error {
-	for tableID := range m {
-		table, err := p.session.leases.getTableLeaseByID(ctx, p.txn, tableID)
-		if err == errTableAdding {
-			m[tableID] = sqlbase.TableLookup{IsAdding: true}
-			continue
+	for {
+		for tableID, lookup := range m {
+			if lookup.Table != nil || lookup.IsAdding {
+				continue
+			}
+			table, err := p.session.leases.getTableLeaseByID(ctx, p.txn, tableID)
+			if err == errTableAdding {
+				m[tableID] = sqlbase.TableLookup{IsAdding: true}
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			m[tableID] = sqlbase.TableLookup{Table: table}
 		}
-		if err != nil {
-			return err
+		if !m.AddTablesNeededForCascades() {
+			return nil
 		}
-		m[tableID] = sqlbase.TableLookup{Table: table}
 	}
-	return nil
 }
 
 // isDatabaseVisible returns true if the given database is visible to the
@@ -59,6 +59,18 @@ var ByValue = [...]Kind{
 	ALL, CREATE, DROP, GRANT, SELECT, INSERT, DELETE, UPDATE,
 }
 
+// ByName is a map of string -> kind value.
+var ByName = map[string]Kind{
+	"ALL":    ALL,
+	"CREATE": CREATE,
+	"DROP":   DROP,
+	"GRANT":  GRANT,
+	"SELECT": SELECT,
+	"INSERT": INSERT,
+	"DELETE": DELETE,
+	"UPDATE": UPDATE,
+}
+
 // List is a list of privileges.
 type List []Kind
 
@@ -140,6 +152,21 @@ func ListFromBitField(m uint32) List {
 	return ret
 }
 
+// ListFromStrings takes a list of strings and attempts to build a list of
+// Kind. The strings are matched case-insensitively. An error is returned
+// for any string which is not the name of a privilege.
+func ListFromStrings(strs []string) (List, error) {
+	ret := make(List, len(strs))
+	for i, s := range strs {
+		k, ok := ByName[strings.ToUpper(s)]
+		if !ok {
+			return nil, fmt.Errorf("not a valid privilege: %q", s)
+		}
+		ret[i] = k
+	}
+	return ret, nil
+}
+
 // Lists is a list of privilege lists
 type Lists []List
 
@@ -62,3 +62,21 @@ func TestPrivilegeDecode(t *testing.T) {
 		}
 	}
 }
+
+func TestListFromStrings(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	pl, err := privilege.ListFromStrings([]string{"select", "INSERT", "Drop"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e := (privilege.List{privilege.SELECT, privilege.INSERT, privilege.DROP}); pl.String() != e.String() {
+		t.Fatalf("expected %s, got %s", e, pl)
+	}
+
+	if _, err := privilege.ListFromStrings([]string{"select", "foo"}); err == nil {
+		t.Fatal("expected an error for an unknown privilege")
+	} else if e := `not a valid privilege: "foo"`; err.Error() != e {
+		t.Fatalf("expected %q, got %q", e, err)
+	}
+}
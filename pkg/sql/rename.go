@@ -54,7 +54,7 @@ func (p *planner) RenameDatabase(ctx context.Context, n *parser.RenameDatabase)
 		return nil, err
 	}
 
-	if err := p.CheckPrivilege(dbDesc, privilege.DROP); err != nil {
+	if err := p.CheckPrivilege(ctx, dbDesc, privilege.DROP); err != nil {
 		return nil, err
 	}
 
@@ -167,7 +167,7 @@ func (p *planner) RenameTable(ctx context.Context, n *parser.RenameTable) (planN
 		}
 	}
 
-	if err := p.CheckPrivilege(tableDesc, privilege.DROP); err != nil {
+	if err := p.CheckPrivilege(ctx, tableDesc, privilege.DROP); err != nil {
 		return nil, err
 	}
 
@@ -186,7 +186,7 @@ func (p *planner) RenameTable(ctx context.Context, n *parser.RenameTable) (planN
 		return nil, err
 	}
 
-	if err := p.CheckPrivilege(targetDbDesc, privilege.CREATE); err != nil {
+	if err := p.CheckPrivilege(ctx, targetDbDesc, privilege.CREATE); err != nil {
 		return nil, err
 	}
 
@@ -274,7 +274,7 @@ func (p *planner) RenameIndex(ctx context.Context, n *parser.RenameIndex) (planN
 		return nil, err
 	}
 
-	if err := p.CheckPrivilege(tableDesc, privilege.CREATE); err != nil {
+	if err := p.CheckPrivilege(ctx, tableDesc, privilege.CREATE); err != nil {
 		return nil, err
 	}
 
@@ -343,7 +343,7 @@ func (p *planner) RenameColumn(ctx context.Context, n *parser.RenameColumn) (pla
 		return nil, fmt.Errorf("table %q does not exist", tn.Table())
 	}
 
-	if err := p.CheckPrivilege(tableDesc, privilege.CREATE); err != nil {
+	if err := p.CheckPrivilege(ctx, tableDesc, privilege.CREATE); err != nil {
 		return nil, err
 	}
 
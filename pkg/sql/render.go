@@ -169,11 +169,24 @@ func (r *renderNode) IndexedVarFormat(buf *bytes.Buffer, f parser.FmtFlags, idx
 func (p *planner) Select(
 	ctx context.Context, n *parser.Select, desiredTypes []parser.Type,
 ) (planNode, error) {
+	popWith, err := p.initWith(ctx, n.With)
+	if err != nil {
+		return nil, err
+	}
+	defer popWith(ctx)
+
 	wrapped := n.Select
 	limit := n.Limit
 	orderBy := n.OrderBy
 
 	for s, ok := wrapped.(*parser.ParenSelect); ok; s, ok = wrapped.(*parser.ParenSelect) {
+		if s.Select.With != nil {
+			popInnerWith, err := p.initWith(ctx, s.Select.With)
+			if err != nil {
+				return nil, err
+			}
+			defer popInnerWith(ctx)
+		}
 		wrapped = s.Select.Select
 		if s.Select.OrderBy != nil {
 			if orderBy != nil {
@@ -0,0 +1,347 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+)
+
+// Roles are stored in system.users next to the users, with the isRole
+// column set; they cannot log in. The members of each role, which can be
+// users or other roles, are stored in system.role_members. A user has the
+// privileges granted to the roles it is a member of, directly or through
+// other roles.
+
+// CreateRole creates a role.
+// Privileges: INSERT on system.users.
+func (p *planner) CreateRole(ctx context.Context, n *parser.CreateRole) (planNode, error) {
+	if n.Name == "" {
+		return nil, errors.New("no role name specified")
+	}
+	if err := p.checkUsersTablePrivilege(ctx, privilege.INSERT); err != nil {
+		return nil, err
+	}
+	return &createUserNode{p: p, name: n.Name, isRole: true}, nil
+}
+
+type dropRoleNode struct {
+	p        *planner
+	names    parser.NameList
+	ifExists bool
+}
+
+// DropRole removes roles, along with their memberships.
+// Privileges: DELETE on system.users.
+//   notes: postgres also refuses to drop roles which still have privileges.
+func (p *planner) DropRole(ctx context.Context, n *parser.DropRole) (planNode, error) {
+	if err := p.checkUsersTablePrivilege(ctx, privilege.DELETE); err != nil {
+		return nil, err
+	}
+	return &dropRoleNode{p: p, names: n.Names, ifExists: n.IfExists}, nil
+}
+
+func (n *dropRoleNode) Start(ctx context.Context) error {
+	descs, err := getAllDescriptors(ctx, n.p.txn)
+	if err != nil {
+		return err
+	}
+
+	internalExecutor := InternalExecutor{LeaseManager: n.p.LeaseMgr()}
+	for _, name := range n.names {
+		role := name.Normalize()
+		for _, desc := range descs {
+			if desc.GetPrivileges().AnyPrivilege(role) {
+				return errors.Errorf("cannot drop role %s: it has privileges on %s %s",
+					role, desc.TypeName(), desc.GetName())
+			}
+		}
+
+		rowsAffected, err := internalExecutor.ExecuteStatementInTransaction(
+			ctx, "drop-role", n.p.txn,
+			`DELETE FROM system.users WHERE username = $1 AND "isRole" = true`, role,
+		)
+		if err != nil {
+			return err
+		}
+		if rowsAffected == 0 {
+			if n.ifExists {
+				continue
+			}
+			return errors.Errorf("role %s does not exist", role)
+		}
+
+		if _, err := internalExecutor.ExecuteStatementInTransaction(
+			ctx, "drop-role", n.p.txn,
+			`DELETE FROM system.role_members WHERE "role" = $1 OR "member" = $1`, role,
+		); err != nil {
+			return err
+		}
+	}
+	n.p.session.TxnState.roleMemberships = nil
+	return nil
+}
+
+func (*dropRoleNode) Next(context.Context) (bool, error) { return false, nil }
+func (*dropRoleNode) Close(context.Context)              {}
+func (*dropRoleNode) Columns() sqlbase.ResultColumns     { return make(sqlbase.ResultColumns, 0) }
+func (*dropRoleNode) Ordering() orderingInfo             { return orderingInfo{} }
+func (*dropRoleNode) Values() parser.Datums              { return parser.Datums{} }
+func (*dropRoleNode) DebugValues() debugValues           { return debugValues{} }
+func (*dropRoleNode) MarkDebug(mode explainMode)         {}
+
+func (*dropRoleNode) Spans(context.Context) (_, _ roachpb.Spans, _ error) {
+	panic("unimplemented")
+}
+
+type grantRoleNode struct {
+	p           *planner
+	roles       []string
+	members     []string
+	adminOption bool
+}
+
+// GrantRole adds users or roles as members of roles.
+// Privileges: the admin option on the roles.
+//   notes: root has the admin option on every role.
+func (p *planner) GrantRole(ctx context.Context, n *parser.GrantRole) (planNode, error) {
+	roles, members, err := p.resolveRoleMembers(ctx, n.Roles, n.Members)
+	if err != nil {
+		return nil, err
+	}
+	return &grantRoleNode{p: p, roles: roles, members: members, adminOption: n.AdminOption}, nil
+}
+
+func (n *grantRoleNode) Start(ctx context.Context) error {
+	stmt := `INSERT INTO system.role_members ("role", "member", "isAdmin") VALUES ($1, $2, false) ` +
+		`ON CONFLICT ("role", "member") DO NOTHING`
+	if n.adminOption {
+		stmt = `UPSERT INTO system.role_members ("role", "member", "isAdmin") VALUES ($1, $2, true)`
+	}
+
+	internalExecutor := InternalExecutor{LeaseManager: n.p.LeaseMgr()}
+	for _, role := range n.roles {
+		// The role must not already be a member of any of the new members,
+		// directly or indirectly, or membership would be circular.
+		memberOf, err := n.p.memberOf(ctx, role)
+		if err != nil {
+			return err
+		}
+		for _, member := range n.members {
+			if _, ok := memberOf[member]; ok || member == role {
+				return errors.Errorf("making %s a member of %s would create a cycle", member, role)
+			}
+			if _, err := internalExecutor.ExecuteStatementInTransaction(
+				ctx, "grant-role", n.p.txn, stmt, role, member,
+			); err != nil {
+				return err
+			}
+		}
+		// The memberships cached by memberOf are stale once this role has
+		// new members.
+		n.p.session.TxnState.roleMemberships = nil
+	}
+	return nil
+}
+
+func (*grantRoleNode) Next(context.Context) (bool, error) { return false, nil }
+func (*grantRoleNode) Close(context.Context)              {}
+func (*grantRoleNode) Columns() sqlbase.ResultColumns     { return make(sqlbase.ResultColumns, 0) }
+func (*grantRoleNode) Ordering() orderingInfo             { return orderingInfo{} }
+func (*grantRoleNode) Values() parser.Datums              { return parser.Datums{} }
+func (*grantRoleNode) DebugValues() debugValues           { return debugValues{} }
+func (*grantRoleNode) MarkDebug(mode explainMode)         {}
+
+func (*grantRoleNode) Spans(context.Context) (_, _ roachpb.Spans, _ error) {
+	panic("unimplemented")
+}
+
+type revokeRoleNode struct {
+	p           *planner
+	roles       []string
+	members     []string
+	adminOption bool
+}
+
+// RevokeRole removes members from roles, or only their admin option.
+// Privileges: the admin option on the roles.
+//   notes: root has the admin option on every role.
+func (p *planner) RevokeRole(ctx context.Context, n *parser.RevokeRole) (planNode, error) {
+	roles, members, err := p.resolveRoleMembers(ctx, n.Roles, n.Members)
+	if err != nil {
+		return nil, err
+	}
+	return &revokeRoleNode{p: p, roles: roles, members: members, adminOption: n.AdminOption}, nil
+}
+
+func (n *revokeRoleNode) Start(ctx context.Context) error {
+	stmt := `DELETE FROM system.role_members WHERE "role" = $1 AND "member" = $2`
+	if n.adminOption {
+		stmt = `UPDATE system.role_members SET "isAdmin" = false WHERE "role" = $1 AND "member" = $2`
+	}
+
+	internalExecutor := InternalExecutor{LeaseManager: n.p.LeaseMgr()}
+	for _, role := range n.roles {
+		for _, member := range n.members {
+			if _, err := internalExecutor.ExecuteStatementInTransaction(
+				ctx, "revoke-role", n.p.txn, stmt, role, member,
+			); err != nil {
+				return err
+			}
+		}
+	}
+	n.p.session.TxnState.roleMemberships = nil
+	return nil
+}
+
+func (*revokeRoleNode) Next(context.Context) (bool, error) { return false, nil }
+func (*revokeRoleNode) Close(context.Context)              {}
+func (*revokeRoleNode) Columns() sqlbase.ResultColumns     { return make(sqlbase.ResultColumns, 0) }
+func (*revokeRoleNode) Ordering() orderingInfo             { return orderingInfo{} }
+func (*revokeRoleNode) Values() parser.Datums              { return parser.Datums{} }
+func (*revokeRoleNode) DebugValues() debugValues           { return debugValues{} }
+func (*revokeRoleNode) MarkDebug(mode explainMode)         {}
+
+func (*revokeRoleNode) Spans(context.Context) (_, _ roachpb.Spans, _ error) {
+	panic("unimplemented")
+}
+
+// resolveRoleMembers normalizes the roles and members of a GRANT or REVOKE
+// of role memberships. It checks that the roles and members exist, and that
+// the session user has the admin option on every role.
+func (p *planner) resolveRoleMembers(
+	ctx context.Context, roleNames, memberNames parser.NameList,
+) (roles []string, members []string, _ error) {
+	var memberOf map[string]bool
+	if user := p.session.User; user != security.RootUser && user != security.NodeUser {
+		var err error
+		if memberOf, err = p.memberOf(ctx, user); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	for _, name := range roleNames {
+		role := name.Normalize()
+		exists, isRole, err := p.lookupUser(ctx, role)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !exists || !isRole {
+			return nil, nil, errors.Errorf("role %s does not exist", role)
+		}
+		if memberOf != nil && !memberOf[role] {
+			return nil, nil, errors.Errorf("%s must have admin option on role %s", p.session.User, role)
+		}
+		roles = append(roles, role)
+	}
+	for _, name := range memberNames {
+		member := name.Normalize()
+		exists, _, err := p.lookupUser(ctx, member)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !exists {
+			return nil, nil, errors.Errorf("user or role %s does not exist", member)
+		}
+		members = append(members, member)
+	}
+	return roles, members, nil
+}
+
+// checkUsersTablePrivilege verifies that the session user has the given
+// privilege on system.users.
+func (p *planner) checkUsersTablePrivilege(ctx context.Context, priv privilege.Kind) error {
+	tDesc, err := getTableDesc(ctx, p.txn, p.getVirtualTabler(), &parser.TableName{DatabaseName: "system", TableName: "users"})
+	if err != nil {
+		return err
+	}
+	return p.CheckPrivilege(ctx, tDesc, priv)
+}
+
+// lookupUser returns whether a user or role with the given normalized name
+// exists, and whether it is a role.
+func (p *planner) lookupUser(ctx context.Context, name string) (exists bool, isRole bool, _ error) {
+	// The root user is not in system.users.
+	if name == security.RootUser {
+		return true, false, nil
+	}
+	internalExecutor := InternalExecutor{LeaseManager: p.LeaseMgr()}
+	row, err := internalExecutor.QueryRowInTransaction(
+		ctx, "lookup-user", p.txn, `SELECT "isRole" FROM system.users WHERE username = $1`, name,
+	)
+	if err != nil || row == nil {
+		return false, false, err
+	}
+	return true, bool(*row[0].(*parser.DBool)), nil
+}
+
+// memberOf returns the roles the given user or role is a member of, directly
+// or through other roles. The value of each entry is set if the member has
+// the admin option on the role, which, as in Postgres, is the case if the
+// role was granted with the admin option to the member or to any of the
+// roles the member is a member of. The results are cached for the duration
+// of the transaction.
+func (p *planner) memberOf(ctx context.Context, member string) (map[string]bool, error) {
+	ts := &p.session.TxnState
+	if roles, ok := ts.roleMemberships[member]; ok {
+		return roles, nil
+	}
+
+	internalExecutor := InternalExecutor{LeaseManager: p.LeaseMgr()}
+	roles := make(map[string]bool)
+	toVisit := []string{member}
+	for len(toVisit) > 0 {
+		cur := toVisit[0]
+		toVisit = toVisit[1:]
+		rows, err := internalExecutor.QueryRowsInTransaction(
+			ctx, "expand-roles", p.txn,
+			`SELECT "role", "isAdmin" FROM system.role_members WHERE "member" = $1`, cur,
+		)
+		if err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			role := string(parser.MustBeDString(row[0]))
+			isAdmin := bool(*row[1].(*parser.DBool))
+			if _, ok := roles[role]; !ok {
+				toVisit = append(toVisit, role)
+			}
+			roles[role] = roles[role] || isAdmin
+		}
+	}
+
+	if ts.roleMemberships == nil {
+		ts.roleMemberships = make(map[string]map[string]bool)
+	}
+	ts.roleMemberships[member] = roles
+	return roles, nil
+}
+
+// sessionUserRoles returns the roles the session user is a member of, for
+// the purpose of privilege checks. The roles of root and node are not
+// expanded, and neither are roles outside of a transaction.
+func (p *planner) sessionUserRoles(ctx context.Context) (map[string]bool, error) {
+	user := p.session.User
+	if p.txn == nil || user == security.RootUser || user == security.NodeUser {
+		return nil, nil
+	}
+	return p.memberOf(ctx, user)
+}
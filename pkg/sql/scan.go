@@ -240,6 +240,7 @@ func (n *scanNode) Next(ctx context.Context) (bool, error) {
 
 // Initializes a scanNode with a table descriptor.
 func (n *scanNode) initTable(
+	ctx context.Context,
 	p *planner,
 	desc *sqlbase.TableDescriptor,
 	indexHints *parser.IndexHints,
@@ -248,8 +249,10 @@ func (n *scanNode) initTable(
 ) error {
 	n.desc = *desc
 
+	// Tables read through views are audited as well.
+	p.maybeAudit(desc, false /* writing */)
 	if !p.skipSelectPrivilegeChecks {
-		if err := p.CheckPrivilege(&n.desc, privilege.SELECT); err != nil {
+		if err := p.CheckPrivilege(ctx, &n.desc, privilege.SELECT); err != nil {
 			return err
 		}
 	}
@@ -405,6 +408,11 @@ func (n *scanNode) computeOrdering(
 	index *sqlbase.IndexDescriptor, exactPrefix int, reverse bool,
 ) orderingInfo {
 	var ordering orderingInfo
+	if index.Type == sqlbase.IndexDescriptor_INVERTED {
+		// The entries of an inverted index are ordered by inverted index key,
+		// which doesn't correspond to any ordering of the indexed column.
+		return ordering
+	}
 
 	columnIDs, dirs := index.FullColumnIDs()
 
@@ -0,0 +1,348 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"math"
+
+	"golang.org/x/net/context"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+)
+
+// sequenceState holds the per-session state of the sequences used in the
+// session: the values most recently returned by nextval() (for currval())
+// and the values allocated but not yet handed out when CACHE is above 1.
+type sequenceState struct {
+	mu syncutil.Mutex
+	// latestValues maps sequence IDs to the value most recently obtained by
+	// nextval() in this session.
+	latestValues map[sqlbase.ID]int64
+	// caches maps sequence IDs to values allocated by this session and
+	// not yet returned by nextval().
+	caches map[sqlbase.ID]sequenceCache
+}
+
+// sequenceCache is a run of sequence values allocated by a single
+// Increment request: remaining values, the first of which is next.
+type sequenceCache struct {
+	next      int64
+	remaining int64
+}
+
+// nextCachedValue returns the next value cached for the sequence, if any.
+func (ss *sequenceState) nextCachedValue(id sqlbase.ID, increment int64) (int64, bool) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	c, ok := ss.caches[id]
+	if !ok || c.remaining == 0 {
+		return 0, false
+	}
+	ss.caches[id] = sequenceCache{next: c.next + increment, remaining: c.remaining - 1}
+	return c.next, true
+}
+
+// setCache replaces the values cached for the sequence.
+func (ss *sequenceState) setCache(id sqlbase.ID, c sequenceCache) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	if ss.caches == nil {
+		ss.caches = make(map[sqlbase.ID]sequenceCache)
+	}
+	ss.caches[id] = c
+}
+
+// clearCache discards the values cached for the sequence.
+func (ss *sequenceState) clearCache(id sqlbase.ID) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	delete(ss.caches, id)
+}
+
+// recordValue records the latest value obtained for the sequence.
+func (ss *sequenceState) recordValue(id sqlbase.ID, val int64) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	if ss.latestValues == nil {
+		ss.latestValues = make(map[sqlbase.ID]int64)
+	}
+	ss.latestValues[id] = val
+}
+
+// getLastValue returns the latest value obtained for the sequence.
+func (ss *sequenceState) getLastValue(id sqlbase.ID) (int64, bool) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	val, ok := ss.latestValues[id]
+	return val, ok
+}
+
+// getSequenceDesc returns the descriptor of the named sequence, or an error
+// if the name does not designate a sequence.
+func (p *planner) getSequenceDesc(
+	ctx context.Context, seqName *parser.TableName,
+) (*sqlbase.TableDescriptor, error) {
+	if err := seqName.QualifyWithDatabase(p.session.Database); err != nil {
+		return nil, err
+	}
+	descFunc := p.session.leases.getTableLease
+	if p.avoidCachedDescriptors {
+		descFunc = mustGetTableOrViewDesc
+	}
+	desc, err := descFunc(ctx, p.txn, p.getVirtualTabler(), seqName)
+	if err != nil {
+		return nil, err
+	}
+	if !desc.IsSequence() {
+		return nil, sqlbase.NewWrongObjectTypeError(seqName.String(), "sequence")
+	}
+	return desc, nil
+}
+
+// IncrementSequence implements the parser.EvalPlanner interface.
+//
+// The sequence value is incremented outside of the SQL transaction, so
+// that concurrent transactions never block each other on a sequence and
+// values are never handed out twice, even if the transaction that
+// obtained them aborts.
+func (p *planner) IncrementSequence(ctx context.Context, seqName *parser.TableName) (int64, error) {
+	descriptor, err := p.getSequenceDesc(ctx, seqName)
+	if err != nil {
+		return 0, err
+	}
+	if err := p.CheckPrivilege(ctx, descriptor, privilege.UPDATE); err != nil {
+		return 0, err
+	}
+
+	seqOpts := descriptor.SequenceOpts
+	val, ok := p.session.sequenceState.nextCachedValue(descriptor.ID, seqOpts.Increment)
+	if !ok {
+		seqValueKey := keys.MakeSequenceKey(uint32(descriptor.ID))
+		res, err := p.session.execCfg.DB.Inc(ctx, seqValueKey, seqOpts.Increment*seqOpts.Cache)
+		if err != nil {
+			return 0, err
+		}
+		// The Increment request allocated Cache values; return the first one
+		// and keep the rest for subsequent calls.
+		val = res.ValueInt() - seqOpts.Increment*(seqOpts.Cache-1)
+		if seqOpts.Cache > 1 {
+			p.session.sequenceState.setCache(descriptor.ID, sequenceCache{
+				next:      val + seqOpts.Increment,
+				remaining: seqOpts.Cache - 1,
+			})
+		}
+	}
+
+	if val > seqOpts.MaxValue {
+		return 0, pgerror.NewErrorf(pgerror.CodeSequenceGeneratorLimitExceededError,
+			"reached maximum value of sequence %q (%d)", descriptor.Name, seqOpts.MaxValue)
+	}
+	if val < seqOpts.MinValue {
+		return 0, pgerror.NewErrorf(pgerror.CodeSequenceGeneratorLimitExceededError,
+			"reached minimum value of sequence %q (%d)", descriptor.Name, seqOpts.MinValue)
+	}
+
+	p.session.sequenceState.recordValue(descriptor.ID, val)
+	return val, nil
+}
+
+// GetLatestValueInSessionForSequence implements the parser.EvalPlanner
+// interface.
+func (p *planner) GetLatestValueInSessionForSequence(
+	ctx context.Context, seqName *parser.TableName,
+) (int64, error) {
+	descriptor, err := p.getSequenceDesc(ctx, seqName)
+	if err != nil {
+		return 0, err
+	}
+	if err := p.CheckPrivilege(ctx, descriptor, privilege.SELECT); err != nil {
+		return 0, err
+	}
+
+	val, ok := p.session.sequenceState.getLastValue(descriptor.ID)
+	if !ok {
+		return 0, pgerror.NewErrorf(pgerror.CodeObjectNotInPrerequisiteStateError,
+			"currval of sequence %q is not yet defined in this session", descriptor.Name)
+	}
+	return val, nil
+}
+
+// SetSequenceValue implements the parser.EvalPlanner interface.
+func (p *planner) SetSequenceValue(
+	ctx context.Context, seqName *parser.TableName, newVal int64, isCalled bool,
+) error {
+	descriptor, err := p.getSequenceDesc(ctx, seqName)
+	if err != nil {
+		return err
+	}
+	if err := p.CheckPrivilege(ctx, descriptor, privilege.UPDATE); err != nil {
+		return err
+	}
+
+	seqOpts := descriptor.SequenceOpts
+	if newVal > seqOpts.MaxValue || newVal < seqOpts.MinValue {
+		return pgerror.NewErrorf(pgerror.CodeNumericValueOutOfRangeError,
+			"value %d is out of bounds for sequence %q (%d..%d)",
+			newVal, descriptor.Name, seqOpts.MinValue, seqOpts.MaxValue)
+	}
+
+	// The stored value is the last value handed out; if isCalled is false,
+	// the next call to nextval() must return newVal itself.
+	storedVal := newVal
+	if !isCalled {
+		storedVal = newVal - seqOpts.Increment
+	}
+	seqValueKey := keys.MakeSequenceKey(uint32(descriptor.ID))
+	if err := p.session.execCfg.DB.Put(ctx, seqValueKey, storedVal); err != nil {
+		return err
+	}
+
+	// Values cached by this session were allocated before the new value was
+	// set and must not be handed out anymore.
+	p.session.sequenceState.clearCache(descriptor.ID)
+	if isCalled {
+		p.session.sequenceState.recordValue(descriptor.ID, newVal)
+	}
+	return nil
+}
+
+// MakeSequenceTableDesc creates a table descriptor for a sequence with the
+// given options. A sequence has a single column and no indexes: its value
+// is not stored in a table row but in the key given by
+// keys.MakeSequenceKey.
+func MakeSequenceTableDesc(
+	sequenceName string,
+	sequenceOptions parser.SequenceOptions,
+	parentID sqlbase.ID,
+	id sqlbase.ID,
+	privileges *sqlbase.PrivilegeDescriptor,
+) (sqlbase.TableDescriptor, error) {
+	desc := sqlbase.TableDescriptor{
+		ID:            id,
+		Name:          sequenceName,
+		ParentID:      parentID,
+		FormatVersion: sqlbase.FamilyFormatVersion,
+		Version:       1,
+		Privileges:    privileges,
+	}
+	desc.AddColumn(sqlbase.ColumnDescriptor{
+		Name: "value",
+		Type: sqlbase.ColumnType{SemanticType: sqlbase.ColumnType_INT},
+	})
+
+	opts := &sqlbase.TableDescriptor_SequenceOpts{}
+	if err := assignSequenceOptions(opts, sequenceOptions); err != nil {
+		return desc, err
+	}
+	desc.SequenceOpts = opts
+
+	return desc, desc.AllocateIDs()
+}
+
+// assignSequenceOptions fills opts from the options of a CREATE SEQUENCE
+// statement, applying the postgres defaults for any option not given.
+func assignSequenceOptions(
+	opts *sqlbase.TableDescriptor_SequenceOpts, optsNode parser.SequenceOptions,
+) error {
+	seen := make(map[string]bool, len(optsNode))
+	for _, option := range optsNode {
+		// NO CYCLE and CYCLE are the same option.
+		name := option.Name
+		if name == parser.SeqOptNoCycle {
+			name = parser.SeqOptCycle
+		}
+		if seen[name] {
+			return pgerror.NewError(pgerror.CodeSyntaxError, "conflicting or redundant options")
+		}
+		seen[name] = true
+	}
+
+	// The increment determines the defaults of the other options, so it is
+	// processed first.
+	opts.Increment = 1
+	for _, option := range optsNode {
+		if option.Name == parser.SeqOptIncrement {
+			opts.Increment = *option.IntVal
+		}
+	}
+	if opts.Increment == 0 {
+		return pgerror.NewError(pgerror.CodeInvalidParameterValueError, "INCREMENT must not be zero")
+	}
+	isAscending := opts.Increment > 0
+	if isAscending {
+		opts.MinValue = 1
+		opts.MaxValue = math.MaxInt64
+	} else {
+		opts.MinValue = math.MinInt64
+		opts.MaxValue = -1
+	}
+	opts.Cache = 1
+
+	startSet := false
+	for _, option := range optsNode {
+		switch option.Name {
+		case parser.SeqOptCycle:
+			return pgerror.NewError(pgerror.CodeFeatureNotSupportedError,
+				"CYCLE option is not supported")
+		case parser.SeqOptNoCycle, parser.SeqOptIncrement:
+			// Already handled.
+		case parser.SeqOptCache:
+			if v := *option.IntVal; v < 1 {
+				return pgerror.NewErrorf(pgerror.CodeInvalidParameterValueError,
+					"CACHE (%d) must be greater than zero", v)
+			}
+			opts.Cache = *option.IntVal
+		case parser.SeqOptMinValue:
+			// A nil value means NO MINVALUE, i.e. the default.
+			if option.IntVal != nil {
+				opts.MinValue = *option.IntVal
+			}
+		case parser.SeqOptMaxValue:
+			// A nil value means NO MAXVALUE, i.e. the default.
+			if option.IntVal != nil {
+				opts.MaxValue = *option.IntVal
+			}
+		case parser.SeqOptStart:
+			opts.Start = *option.IntVal
+			startSet = true
+		}
+	}
+
+	if opts.MinValue >= opts.MaxValue {
+		return pgerror.NewErrorf(pgerror.CodeInvalidParameterValueError,
+			"MINVALUE (%d) must be less than MAXVALUE (%d)", opts.MinValue, opts.MaxValue)
+	}
+	if !startSet {
+		if isAscending {
+			opts.Start = opts.MinValue
+		} else {
+			opts.Start = opts.MaxValue
+		}
+	}
+	if opts.Start < opts.MinValue {
+		return pgerror.NewErrorf(pgerror.CodeInvalidParameterValueError,
+			"START value (%d) cannot be less than MINVALUE (%d)", opts.Start, opts.MinValue)
+	}
+	if opts.Start > opts.MaxValue {
+		return pgerror.NewErrorf(pgerror.CodeInvalidParameterValueError,
+			"START value (%d) cannot be greater than MAXVALUE (%d)", opts.Start, opts.MaxValue)
+	}
+	return nil
+}
@@ -18,6 +18,8 @@
 package sql
 
 import (
+	"crypto/rand"
+	"encoding/binary"
 	"fmt"
 	"net"
 	"strings"
@@ -33,6 +35,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/internal/client"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/server/serverpb"
 	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/sql/mon"
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
@@ -42,6 +45,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/envutil"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/retry"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/cockroach/pkg/util/tracing"
 )
@@ -238,6 +242,45 @@ type Session struct {
 
 	// If set, contains the in progress COPY FROM columns.
 	copyFrom *copyNode
+	// If set, the rows of a COPY TO are sent to copyOut. See
+	// SetCopyOutWriter.
+	copyOut CopyOutWriter
+
+	// sequenceState holds the values obtained by nextval() in this session,
+	// for currval(), and the sequence values cached by this session.
+	sequenceState sequenceState
+
+	// id is the cluster-wide unique ID of the session. See generateID().
+	id string
+
+	// clientAddr is the address of the client connection, if any.
+	clientAddr string
+
+	// cancelKey is the key with which the session is registered in the
+	// SessionRegistry's cancelKeys, or 0 if none. See RegisterCancelKey().
+	cancelKey uint64
+
+	// canceledCh is closed by CancelSession() to signal to the client
+	// connection serving the session that it should be closed.
+	canceledCh chan struct{}
+
+	mu struct {
+		syncutil.RWMutex
+
+		// ActiveQueries contains all queries in flight, keyed by query ID.
+		ActiveQueries map[string]*queryMeta
+
+		// LastActiveQuery is the statement most recently started by the
+		// session, or nil if none has been.
+		LastActiveQuery parser.Statement
+
+		// txnStart is the time at which the session's open explicit SQL
+		// transaction began, or the zero time if there is none.
+		txnStart time.Time
+
+		// canceled is set once canceledCh has been closed.
+		canceled bool
+	}
 
 	//
 	// Testing state.
@@ -328,6 +371,18 @@ func NewSession(
 		s.eventLog = trace.NewEventLog(fmt.Sprintf("sql [%s]", args.User), remoteStr)
 	}
 	s.context, s.cancel = context.WithCancel(ctx)
+	s.canceledCh = make(chan struct{})
+	s.mu.ActiveQueries = make(map[string]*queryMeta)
+
+	if remote != nil {
+		s.id = e.generateID()
+		s.clientAddr = remote.String()
+		// Only sessions serving clients are registered; internal sessions
+		// are not of interest to SHOW QUERIES or SHOW SESSIONS.
+		if e.cfg.SessionRegistry != nil {
+			e.cfg.SessionRegistry.register(s)
+		}
+	}
 
 	return s
 }
@@ -361,7 +416,8 @@ func (s *Session) Finish(e *Executor) {
 	_ = s.parallelizeQueue.Wait()
 
 	// If we're inside a txn, roll it back.
-	if s.TxnState.State.kvTxnIsOpen() {
+	if s.TxnState.State.kvTxnIsOpen() || s.TxnState.canRollbackToSavepoint() {
+		s.TxnState.savepoints = nil
 		s.TxnState.updateStateAndCleanupOnErr(
 			errors.Errorf("session closing"), e)
 	}
@@ -374,6 +430,10 @@ func (s *Session) Finish(e *Executor) {
 	// addressed, there might be leases accumulated by preparing statements.
 	s.leases.releaseLeases(s.context)
 
+	if e.cfg.SessionRegistry != nil {
+		e.cfg.SessionRegistry.deregister(s)
+	}
+
 	s.ClearStatementsAndPortals(s.context)
 	s.sessionMon.Stop(s.context)
 	s.mon.Stop(s.context)
@@ -451,6 +511,7 @@ func (s *Session) evalCtx() parser.EvalContext {
 	return parser.EvalContext{
 		Location:   &s.Location,
 		Database:   s.Database,
+		User:       s.User,
 		SearchPath: s.SearchPath,
 		Ctx:        s.Ctx,
 		Mon:        &s.TxnState.mon,
@@ -580,9 +641,19 @@ type txnState struct {
 	// the same batch), but not if the error needs to be reported to the user.
 	commitSeen bool
 
+	// The savepoints established in the txn, innermost last. The special
+	// cockroach_restart savepoint is not included; it's tracked by
+	// retryIntent.
+	savepoints []savepoint
+
 	// The schema change closures to run when this txn is done.
 	schemaChangers schemaChangerCollection
 
+	// roleMemberships caches the roles of the users and roles whose
+	// memberships were looked up in the txn. It is cleared whenever the txn
+	// modifies memberships or discards its writes. See memberOf.
+	roleMemberships map[string]map[string]bool
+
 	sp opentracing.Span
 	// When sql.trace.txn.threshold is >0, trace accumulates spans as
 	// they're closed. All the spans pertain to the current txn.
@@ -599,6 +670,16 @@ type txnState struct {
 	mon mon.MemoryMonitor
 }
 
+// savepoint is a savepoint established by SAVEPOINT. Rolling back to it
+// undoes the writes the KV txn performed after the sequence number seq, and
+// drops the schema changers queued after the first numSchemaChangers: the
+// descriptor writes of the statements which queued them are undone.
+type savepoint struct {
+	name              string
+	seq               int32
+	numSchemaChangers int
+}
+
 // resetForNewSQLTxn (re)initializes the txnState for a new transaction.
 // It creates a new client.Txn and initializes it using the session defaults.
 // txnState.State will be set to Open.
@@ -612,6 +693,8 @@ func (ts *txnState) resetForNewSQLTxn(e *Executor, s *Session) {
 	ts.retryIntent = false
 	ts.autoRetry = false
 	ts.commitSeen = false
+	ts.savepoints = nil
+	ts.roleMemberships = nil
 
 	// Create a context for this transaction. It will include a
 	// root span that will contain everything executed as part of the
@@ -681,6 +764,15 @@ func (ts *txnState) resetStateAndTxn(state TxnStateEnum) {
 	}
 	ts.State = state
 	ts.txn = nil
+	ts.savepoints = nil
+	ts.roleMemberships = nil
+}
+
+// canRollbackToSavepoint returns true if the txn is Aborted, but its KV txn
+// was kept open so that the txn can be resumed by rolling back to one of its
+// savepoints.
+func (ts *txnState) canRollbackToSavepoint() bool {
+	return ts.State == Aborted && ts.txn != nil
 }
 
 // finishSQLTxn closes the root span for the current SQL txn.
@@ -709,7 +801,8 @@ func (ts *txnState) finishSQLTxn(sessionCtx context.Context) {
 // updateStateAndCleanupOnErr updates txnState based on the type of error that we
 // received. If it's a retriable error and we're going to retry the txn,
 // then the state moves to RestartWait. Otherwise, the state moves to Aborted
-// and the KV txn is cleaned up.
+// and the KV txn is cleaned up, unless the txn has savepoints it can be
+// rolled back to.
 func (ts *txnState) updateStateAndCleanupOnErr(err error, e *Executor) {
 	if err == nil {
 		panic("updateStateAndCleanupOnErr called with no error")
@@ -718,6 +811,14 @@ func (ts *txnState) updateStateAndCleanupOnErr(err error, e *Executor) {
 		!ts.willBeRetried() ||
 		!ts.txn.IsRetryableErrMeantForTxn(*retErr) {
 
+		if !ok && len(ts.savepoints) > 0 && !ts.txn.IsFinalized() {
+			// The writes performed since a savepoint can still be undone, so
+			// keep the KV txn open until the txn is either rolled back to a
+			// savepoint or rolled back altogether.
+			ts.State = Aborted
+			return
+		}
+
 		// We can't or don't want to retry this txn, so the txn is over.
 		e.TxnAbortCount.Inc(1)
 		// This call rolls back a PENDING transaction and cleans up all its
@@ -828,3 +929,292 @@ func (scc *schemaChangerCollection) execSchemaChanges(
 	}
 	scc.schemaChangers = scc.schemaChangers[:0]
 }
+
+// queryMeta stores metadata about a query. Stored as reference in
+// session.mu.ActiveQueries.
+type queryMeta struct {
+	// The timestamp when this query began execution.
+	start time.Time
+
+	// The statement being executed.
+	stmt parser.Statement
+
+	// States whether this query is distributed. Note that all queries,
+	// including those that are distributed, have this field set to false until
+	// start of execution; only at that point can we can actually determine
+	// whether this query will be distributed or not.
+	isDistributed bool
+
+	// Cancellation function for the context associated with this query's
+	// statement.
+	ctxCancel context.CancelFunc
+}
+
+// addActiveQuery adds a running query to the session's list of active
+// queries.
+func (s *Session) addActiveQuery(queryID string, queryMeta *queryMeta) {
+	s.mu.Lock()
+	s.mu.ActiveQueries[queryID] = queryMeta
+	s.mu.LastActiveQuery = queryMeta.stmt
+	s.mu.Unlock()
+}
+
+// removeActiveQuery removes a query from the session's list of active
+// queries.
+func (s *Session) removeActiveQuery(queryID string) {
+	s.mu.Lock()
+	delete(s.mu.ActiveQueries, queryID)
+	s.mu.Unlock()
+}
+
+// setQueryExecutionMode marks the given query as distributed or not.
+func (s *Session) setQueryExecutionMode(queryID string, isDistributed bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if queryMeta, ok := s.mu.ActiveQueries[queryID]; ok {
+		queryMeta.isDistributed = isDistributed
+	}
+}
+
+// CancelQuery cancels the active query with the given ID, if it belongs to
+// this session. It returns whether the query was found.
+func (s *Session) CancelQuery(queryID string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if queryMeta, ok := s.mu.ActiveQueries[queryID]; ok {
+		queryMeta.ctxCancel()
+		return true
+	}
+	return false
+}
+
+// RegisterCancelKey assigns a cancel key to the session, which a pgwire client
+// can use to cancel the session's queries from another connection, possibly
+// to another node (see Executor.CancelQueriesByKey). The key is released when
+// the session finishes. It returns false if the session is not registered in
+// a SessionRegistry.
+func (s *Session) RegisterCancelKey() (uint64, bool, error) {
+	registry := s.execCfg.SessionRegistry
+	if registry == nil || s.id == "" {
+		return 0, false, nil
+	}
+	key, err := registry.registerCancelKey(s, s.execCfg.NodeID.Get())
+	if err != nil {
+		return 0, false, err
+	}
+	return key, true, nil
+}
+
+// CancelActiveQueries cancels all currently active queries of this session.
+// It returns whether any query was canceled.
+func (s *Session) CancelActiveQueries() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, queryMeta := range s.mu.ActiveQueries {
+		queryMeta.ctxCancel()
+	}
+	return len(s.mu.ActiveQueries) > 0
+}
+
+// setTxnStart records the time at which the session's explicit SQL
+// transaction began, for SHOW SESSIONS. The zero time indicates that no
+// explicit transaction is open.
+func (s *Session) setTxnStart(txnStart time.Time) {
+	s.mu.Lock()
+	s.mu.txnStart = txnStart
+	s.mu.Unlock()
+}
+
+// CancelSession cancels all active queries of this session and signals the
+// client connection serving it to close. The session's open transaction, if
+// any, is rolled back when the connection closes and the session finishes.
+func (s *Session) CancelSession() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, queryMeta := range s.mu.ActiveQueries {
+		queryMeta.ctxCancel()
+	}
+	if !s.mu.canceled {
+		s.mu.canceled = true
+		close(s.canceledCh)
+	}
+}
+
+// Canceled returns a channel which is closed once the session has been
+// canceled through CancelSession().
+func (s *Session) Canceled() <-chan struct{} {
+	return s.canceledCh
+}
+
+// serialize returns the serverpb representation of the session, for use by
+// the status server.
+func (s *Session) serialize() serverpb.Session {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	activeQueries := make([]serverpb.ActiveQuery, 0, len(s.mu.ActiveQueries))
+	for id, query := range s.mu.ActiveQueries {
+		activeQueries = append(activeQueries, serverpb.ActiveQuery{
+			ID:            id,
+			Start:         query.start.UTC(),
+			Sql:           query.stmt.String(),
+			IsDistributed: query.isDistributed,
+		})
+	}
+
+	var activeTxnStart *time.Time
+	if !s.mu.txnStart.IsZero() {
+		txnStart := s.mu.txnStart.UTC()
+		activeTxnStart = &txnStart
+	}
+
+	lastActiveQuery := ""
+	if s.mu.LastActiveQuery != nil {
+		lastActiveQuery = s.mu.LastActiveQuery.String()
+	}
+
+	return serverpb.Session{
+		NodeID:          s.execCfg.NodeID.Get(),
+		Username:        s.User,
+		ClientAddress:   s.clientAddr,
+		ApplicationName: s.ApplicationName,
+		ActiveQueries:   activeQueries,
+		ID:              s.id,
+		Start:           s.phaseTimes[sessionInit].UTC(),
+		ActiveTxnStart:  activeTxnStart,
+		LastActiveQuery: lastActiveQuery,
+	}
+}
+
+// SessionRegistry stores a set of all sessions on this node.
+// Use register() and deregister() to modify this registry.
+type SessionRegistry struct {
+	syncutil.Mutex
+	store map[*Session]struct{}
+	// cancelKeys maps the cancel keys sent to pgwire clients in BackendKeyData
+	// messages to their sessions.
+	cancelKeys map[uint64]*Session
+}
+
+// MakeSessionRegistry creates a new SessionRegistry with an empty set
+// of sessions.
+func MakeSessionRegistry() *SessionRegistry {
+	return &SessionRegistry{
+		store:      make(map[*Session]struct{}),
+		cancelKeys: make(map[uint64]*Session),
+	}
+}
+
+func (r *SessionRegistry) register(s *Session) {
+	r.Lock()
+	r.store[s] = struct{}{}
+	r.Unlock()
+}
+
+func (r *SessionRegistry) deregister(s *Session) {
+	r.Lock()
+	delete(r.store, s)
+	if s.cancelKey != 0 {
+		delete(r.cancelKeys, s.cancelKey)
+	}
+	r.Unlock()
+}
+
+// registerCancelKey assigns an unused cancel key to the session and returns
+// it. The upper 32 bits of the key are the ID of the node on which the
+// session runs, so that a cancel request received by any node can be
+// forwarded to it. The lower 32 bits are random.
+func (r *SessionRegistry) registerCancelKey(s *Session, nodeID roachpb.NodeID) (uint64, error) {
+	r.Lock()
+	defer r.Unlock()
+	for {
+		var secret uint32
+		if err := binary.Read(rand.Reader, binary.BigEndian, &secret); err != nil {
+			return 0, err
+		}
+		key := uint64(uint32(nodeID))<<32 | uint64(secret)
+		if _, ok := r.cancelKeys[key]; !ok && key != 0 {
+			r.cancelKeys[key] = s
+			s.cancelKey = key
+			return key, nil
+		}
+	}
+}
+
+// cancelKeyNodeID returns the ID of the node on which the session with the
+// given cancel key runs. See registerCancelKey().
+func cancelKeyNodeID(key uint64) roachpb.NodeID {
+	return roachpb.NodeID(key >> 32)
+}
+
+// CancelQueriesByKey cancels the active queries of the session with the
+// given cancel key. It returns whether the session was found.
+func (r *SessionRegistry) CancelQueriesByKey(key uint64) bool {
+	r.Lock()
+	session, ok := r.cancelKeys[key]
+	r.Unlock()
+	if ok {
+		session.CancelActiveQueries()
+	}
+	return ok
+}
+
+// CancelQuery looks up the query with the given ID and cancels it. Users
+// other than root may only cancel their own queries. It returns whether the
+// query was found and canceled.
+func (r *SessionRegistry) CancelQuery(queryID string, username string) (bool, error) {
+	r.Lock()
+	defer r.Unlock()
+
+	for session := range r.store {
+		if !(username == security.RootUser || username == session.User) {
+			// Skip this session.
+			continue
+		}
+
+		if session.CancelQuery(queryID) {
+			return true, nil
+		}
+	}
+
+	return false, errors.Errorf("query ID %s not found", queryID)
+}
+
+// CancelSession looks up the session with the given ID and cancels it. Users
+// other than root may only cancel their own sessions. It returns whether the
+// session was found and canceled.
+func (r *SessionRegistry) CancelSession(sessionID string, username string) (bool, error) {
+	r.Lock()
+	defer r.Unlock()
+
+	for session := range r.store {
+		if session.id != sessionID {
+			continue
+		}
+		if !(username == security.RootUser || username == session.User) {
+			break
+		}
+		session.CancelSession()
+		return true, nil
+	}
+
+	return false, errors.Errorf("session ID %s not found", sessionID)
+}
+
+// SerializeAll returns a slice of all sessions in the registry, converted to
+// serverpb.Sessions. Users other than root only see their own sessions.
+func (r *SessionRegistry) SerializeAll(username string) []serverpb.Session {
+	r.Lock()
+	defer r.Unlock()
+
+	response := make([]serverpb.Session, 0, len(r.store))
+
+	for s := range r.store {
+		if !(username == security.RootUser || username == s.User) {
+			continue
+		}
+		response = append(response, s.serialize())
+	}
+
+	return response
+}
@@ -368,7 +368,7 @@ func (p *planner) ShowCreateTable(
 	if err != nil {
 		return nil, err
 	}
-	if err := p.anyPrivilege(desc); err != nil {
+	if err := p.anyPrivilege(ctx, desc); err != nil {
 		return nil, err
 	}
 
@@ -439,6 +439,10 @@ func (p *planner) showCreateTable(
 		if err != nil {
 			return "", err
 		}
+		partitioning, err := showCreatePartitioning(desc, &idx)
+		if err != nil {
+			return "", err
+		}
 		if fk := idx.ForeignKey; fk.IsSet() {
 			fkTable, err := p.session.leases.getTableLeaseByID(ctx, p.txn, fk.Table)
 			if err != nil {
@@ -448,19 +452,26 @@ func (p *planner) showCreateTable(
 			if err != nil {
 				return "", err
 			}
-			fmt.Fprintf(&buf, ",\n\tCONSTRAINT %s FOREIGN KEY (%s) REFERENCES %s (%s)",
+			fmt.Fprintf(&buf, ",\n\tCONSTRAINT %s FOREIGN KEY (%s) REFERENCES %s (%s)%s",
 				parser.Name(fk.Name),
 				quoteNames(idx.ColumnNames...),
 				parser.Name(fkTable.Name),
 				quoteNames(fkIdx.ColumnNames...),
+				parser.AsString(fk.Actions()),
+			)
+		} else if idx.Type == sqlbase.IndexDescriptor_INVERTED {
+			fmt.Fprintf(&buf, ",\n\tINVERTED INDEX %s (%s)",
+				quoteNames(idx.Name),
+				quoteNames(idx.ColumnNames...),
 			)
 		} else {
-			fmt.Fprintf(&buf, ",\n\t%sINDEX %s (%s)%s%s",
+			fmt.Fprintf(&buf, ",\n\t%sINDEX %s (%s)%s%s%s",
 				isUnique[idx.Unique],
 				quoteNames(idx.Name),
 				makeIndexColNames(idx),
 				storing,
 				interleave,
+				partitioning,
 			)
 		}
 	}
@@ -493,6 +504,12 @@ func (p *planner) showCreateTable(
 	}
 	buf.WriteString(interleave)
 
+	partitioning, err := showCreatePartitioning(desc, &desc.PrimaryIndex)
+	if err != nil {
+		return "", err
+	}
+	buf.WriteString(partitioning)
+
 	return buf.String(), nil
 }
 
@@ -530,7 +547,7 @@ func (p *planner) ShowCreateView(ctx context.Context, n *parser.ShowCreateView)
 	if err != nil {
 		return nil, err
 	}
-	if err := p.anyPrivilege(desc); err != nil {
+	if err := p.anyPrivilege(ctx, desc); err != nil {
 		return nil, err
 	}
 
@@ -823,7 +840,7 @@ func (p *planner) ShowConstraints(
 	if err != nil {
 		return nil, err
 	}
-	if err := p.anyPrivilege(desc); err != nil {
+	if err := p.anyPrivilege(ctx, desc); err != nil {
 		return nil, err
 	}
 
@@ -921,7 +938,41 @@ func (p *planner) ShowTables(ctx context.Context, n *parser.ShowTables) (planNod
 // ShowUsers returns all the users.
 // Privileges: SELECT on system.users.
 func (p *planner) ShowUsers(ctx context.Context, n *parser.ShowUsers) (planNode, error) {
-	stmt, err := parser.ParseOne(`SELECT username FROM system.users ORDER BY 1`)
+	stmt, err := parser.ParseOne(`SELECT username FROM system.users WHERE "isRole" = false ORDER BY 1`)
+	if err != nil {
+		return nil, err
+	}
+	return p.newPlan(ctx, stmt, nil)
+}
+
+// ShowQueries returns all the queries in the cluster, or on the local node
+// only for SHOW LOCAL QUERIES.
+// Privileges: None.
+//   Notes: users other than root only see their own queries.
+func (p *planner) ShowQueries(ctx context.Context, n *parser.ShowQueries) (planNode, error) {
+	const template = `SELECT * FROM crdb_internal.%s`
+	table := "node_queries"
+	if n.Cluster {
+		table = "cluster_queries"
+	}
+	stmt, err := parser.ParseOne(fmt.Sprintf(template, table))
+	if err != nil {
+		return nil, err
+	}
+	return p.newPlan(ctx, stmt, nil)
+}
+
+// ShowSessions returns all the sessions in the cluster, or on the local node
+// only for SHOW LOCAL SESSIONS.
+// Privileges: None.
+//   Notes: users other than root only see their own sessions.
+func (p *planner) ShowSessions(ctx context.Context, n *parser.ShowSessions) (planNode, error) {
+	const template = `SELECT * FROM crdb_internal.%s`
+	table := "node_sessions"
+	if n.Cluster {
+		table = "cluster_sessions"
+	}
+	stmt, err := parser.ParseOne(fmt.Sprintf(template, table))
 	if err != nil {
 		return nil, err
 	}
@@ -58,7 +58,7 @@ func MakeDefaultExprs(
 			continue
 		}
 		expr := exprs[defExprIdx]
-		typedExpr, err := parser.TypeCheck(expr, nil, col.Type.ToDatumType())
+		typedExpr, err := TypeCheckDefaultExpr(expr, col.Type.ToDatumType(), nil)
 		if err != nil {
 			return nil, err
 		}
@@ -70,3 +70,27 @@ func MakeDefaultExprs(
 	}
 	return defaultExprs, nil
 }
+
+// TypeCheckDefaultExpr type checks a DEFAULT expression for a column of the
+// given type.
+//
+// uuid_v4() returned BYTES before the UUID type was introduced, so DEFAULT
+// expressions like `uuid_v4()` on BYTES columns may be stored in existing table
+// descriptors. To keep them working, a UUID expression is accepted as the
+// default of a BYTES column and is evaluated with a cast to BYTES.
+func TypeCheckDefaultExpr(
+	expr parser.Expr, colType parser.Type, ctx *parser.SemaContext,
+) (parser.TypedExpr, error) {
+	typedExpr, err := parser.TypeCheck(expr, ctx, colType)
+	if err != nil {
+		return nil, err
+	}
+	if colType == parser.TypeBytes && typedExpr.ResolvedType() == parser.TypeUUID {
+		castType, err := parser.DatumTypeToColumnType(parser.TypeBytes)
+		if err != nil {
+			return nil, err
+		}
+		return parser.TypeCheck(&parser.CastExpr{Expr: expr, Type: castType}, ctx, colType)
+	}
+	return typedExpr, nil
+}
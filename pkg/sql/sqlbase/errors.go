@@ -136,6 +136,11 @@ func NewUndefinedViewError(name string) error {
 	return pgerror.NewErrorf(pgerror.CodeUndefinedTableError, "view %q does not exist", name)
 }
 
+// NewUndefinedSequenceError creates an error that represents a missing sequence.
+func NewUndefinedSequenceError(name string) error {
+	return pgerror.NewErrorf(pgerror.CodeUndefinedTableError, "sequence %q does not exist", name)
+}
+
 // IsUndefinedTableError returns true if the error is for an undefined table.
 func IsUndefinedTableError(err error) bool {
 	return errHasCode(err, pgerror.CodeUndefinedTableError)
@@ -181,6 +186,11 @@ func NewRangeUnavailableError(
 		rangeID, nodeIDs, origErr)
 }
 
+// NewQueryCanceledError creates a query cancellation error.
+func NewQueryCanceledError() error {
+	return pgerror.NewError(pgerror.CodeQueryCanceledError, "query execution canceled")
+}
+
 // NewWindowingError creates a windowing error.
 func NewWindowingError(in string) error {
 	return pgerror.NewErrorf(pgerror.CodeWindowingError, "window functions are not allowed in %s", in)
@@ -16,6 +16,7 @@ package sqlbase
 
 import (
 	"fmt"
+	"sort"
 
 	"github.com/pkg/errors"
 	"golang.org/x/net/context"
@@ -79,6 +80,40 @@ func TablesNeededForFKs(table TableDescriptor, usage FKCheck) TableLookupsByID {
 	return ret
 }
 
+// AddTablesNeededForCascades adds to the map the IDs of the additional
+// tables needed to run the referential actions (e.g. ON DELETE CASCADE) of
+// the foreign keys held by the tables of the map: a table whose rows can be
+// modified by such an action needs the tables TablesNeededForFKs returns
+// for its own updates. It returns whether any ID was added, in which case
+// the caller should fill in the new entries and call it again, until all
+// the tables reachable through referential actions have been added.
+func (m TableLookupsByID) AddTablesNeededForCascades() bool {
+	added := false
+	for _, lookup := range m {
+		if lookup.Table == nil || !lookup.Table.hasCascadingFKs() {
+			continue
+		}
+		for id := range TablesNeededForFKs(*lookup.Table, CheckUpdates) {
+			if _, ok := m[id]; !ok {
+				m[id] = TableLookup{}
+				added = true
+			}
+		}
+	}
+	return added
+}
+
+// hasCascadingFKs returns whether any of the foreign keys held by the
+// table has a referential action that modifies the rows of the table.
+func (desc *TableDescriptor) hasCascadingFKs() bool {
+	for _, idx := range desc.AllNonDropIndexes() {
+		if fk := idx.ForeignKey; fk.IsSet() && (fk.OnDelete.IsCascading() || fk.OnUpdate.IsCascading()) {
+			return true
+		}
+	}
+	return false
+}
+
 type fkInsertHelper map[IndexID][]baseFKHelper
 
 var errSkipUnusedFK = errors.New("no columns involved in FK included in writer")
@@ -151,7 +186,11 @@ func (fks fkInsertHelper) CollectSpans() (reads roachpb.Spans, writes roachpb.Sp
 type fkDeleteHelper map[IndexID][]baseFKHelper
 
 func makeFKDeleteHelper(
-	txn *client.Txn, table TableDescriptor, otherTables TableLookupsByID, colMap map[ColumnID]int,
+	txn *client.Txn,
+	table TableDescriptor,
+	otherTables TableLookupsByID,
+	colMap map[ColumnID]int,
+	cascader *fkCascader,
 ) (fkDeleteHelper, error) {
 	var fks fkDeleteHelper
 	for _, idx := range table.AllNonDropIndexes() {
@@ -168,6 +207,7 @@ func makeFKDeleteHelper(
 			if err != nil {
 				return fks, err
 			}
+			fk.cascader = cascader
 			if fks == nil {
 				fks = make(fkDeleteHelper)
 			}
@@ -187,7 +227,27 @@ func (fks fkDeleteHelper) checkAll(ctx context.Context, row parser.Datums) error
 }
 
 func (fks fkDeleteHelper) checkIdx(ctx context.Context, idx IndexID, row parser.Datums) error {
+	return fks.checkOrCascadeIdx(ctx, idx, row, nil /* newValues */)
+}
+
+// checkOrCascadeIdx checks that the values of the given index in the row
+// being deleted (newValues == nil) or updated are not referenced, or runs
+// the referential actions of the foreign keys referencing them.
+func (fks fkDeleteHelper) checkOrCascadeIdx(
+	ctx context.Context, idx IndexID, row, newValues parser.Datums,
+) error {
 	for _, fk := range fks[idx] {
+		action := fk.searchIdx.ForeignKey.OnDelete
+		if newValues != nil {
+			action = fk.searchIdx.ForeignKey.OnUpdate
+		}
+		if row != nil && fk.cascader != nil && action.IsCascading() {
+			if err := fk.cascader.cascade(ctx, fk, action, row, newValues); err != nil {
+				return err
+			}
+			continue
+		}
+
 		found, err := fk.check(ctx, row)
 		if err != nil {
 			return err
@@ -220,11 +280,15 @@ type fkUpdateHelper struct {
 }
 
 func makeFKUpdateHelper(
-	txn *client.Txn, table TableDescriptor, otherTables TableLookupsByID, colMap map[ColumnID]int,
+	txn *client.Txn,
+	table TableDescriptor,
+	otherTables TableLookupsByID,
+	colMap map[ColumnID]int,
+	cascader *fkCascader,
 ) (fkUpdateHelper, error) {
 	ret := fkUpdateHelper{}
 	var err error
-	if ret.inbound, err = makeFKDeleteHelper(txn, table, otherTables, colMap); err != nil {
+	if ret.inbound, err = makeFKDeleteHelper(txn, table, otherTables, colMap, cascader); err != nil {
 		return ret, err
 	}
 	ret.outbound, err = makeFKInsertHelper(txn, table, otherTables, colMap)
@@ -234,7 +298,7 @@ func makeFKUpdateHelper(
 func (fks fkUpdateHelper) checkIdx(
 	ctx context.Context, idx IndexID, oldValues, newValues parser.Datums,
 ) error {
-	if err := fks.inbound.checkIdx(ctx, idx, oldValues); err != nil {
+	if err := fks.inbound.checkOrCascadeIdx(ctx, idx, oldValues, newValues); err != nil {
 		return err
 	}
 	return fks.outbound.checkIdx(ctx, idx, newValues)
@@ -256,6 +320,7 @@ type baseFKHelper struct {
 	writeIdx     IndexDescriptor  // the index we want to modify
 	searchPrefix []byte           // prefix of keys in searchIdx
 	ids          map[ColumnID]int // col IDs
+	cascader     *fkCascader      // runs referential actions, if not nil
 }
 
 func makeBaseFKHelper(
@@ -331,7 +396,20 @@ func (f baseFKHelper) check(ctx context.Context, values parser.Datums) (parser.D
 // CollectSpans implements the FkSpanCollector interface.
 func (f baseFKHelper) CollectSpans() (reads roachpb.Spans, writes roachpb.Spans) {
 	key := roachpb.Key(f.searchPrefix)
-	return roachpb.Spans{roachpb.Span{Key: key, EndKey: key.PrefixEnd()}}, nil
+	reads = roachpb.Spans{roachpb.Span{Key: key, EndKey: key.PrefixEnd()}}
+	if f.cascader != nil {
+		if ref := f.searchIdx.ForeignKey; ref.OnDelete.IsCascading() || ref.OnUpdate.IsCascading() {
+			// Referential actions modify the referencing table and, through
+			// the actions of its own foreign keys, possibly any of the other
+			// tables.
+			for _, lookup := range f.cascader.otherTables {
+				if lookup.Table != nil {
+					writes = append(writes, lookup.Table.AllIndexSpans()...)
+				}
+			}
+		}
+	}
+	return reads, writes
 }
 
 // FkSpanCollector can collect the spans that foreign key validation will touch.
@@ -356,3 +434,304 @@ func collectSpansForFKMap(
 	}
 	return reads, writes
 }
+
+// maxFKCascadeDepth bounds the nesting of referential actions, which can
+// otherwise recurse forever through cycles of ON UPDATE CASCADE foreign
+// keys.
+const maxFKCascadeDepth = 1000
+
+// fkCascader runs the referential actions (CASCADE, SET NULL and SET
+// DEFAULT) of the foreign keys referencing the rows deleted or updated by
+// a table writer, in the writer's transaction. The rows of the referencing
+// tables are modified through writers that share the fkCascader, so that
+// the actions of their own foreign keys run in turn.
+type fkCascader struct {
+	txn         *client.Txn
+	otherTables TableLookupsByID
+	evalCtx     *parser.EvalContext
+
+	// deleters and updaters cache the writers for the referencing tables.
+	deleters map[ID]*RowDeleter
+	updaters map[fkCascadeKey]*RowUpdater
+
+	// deleted holds the primary keys of the rows deleted by ON DELETE
+	// CASCADE actions, so that cycles of such foreign keys terminate.
+	deleted map[string]struct{}
+	depth   int
+}
+
+// fkCascadeKey identifies the RowUpdater used to run the ON DELETE or ON
+// UPDATE action of a foreign key. The columns it updates are those of the
+// referencing index.
+type fkCascadeKey struct {
+	table  ID
+	index  IndexID
+	action ForeignKeyReference_Action
+}
+
+func newFKCascader(
+	txn *client.Txn, otherTables TableLookupsByID, evalCtx *parser.EvalContext,
+) *fkCascader {
+	return &fkCascader{txn: txn, otherTables: otherTables, evalCtx: evalCtx}
+}
+
+// cascade runs the referential action of fk for the referenced row being
+// deleted (newValues == nil) or updated from oldValues to newValues.
+//
+// The modifications are run in their own batch right away: later lookups
+// of referencing rows, including those of the actions of other foreign
+// keys on the same rows, must see them.
+func (c *fkCascader) cascade(
+	ctx context.Context,
+	fk baseFKHelper,
+	action ForeignKeyReference_Action,
+	oldValues, newValues parser.Datums,
+) error {
+	c.depth++
+	defer func() { c.depth-- }()
+	if c.depth > maxFKCascadeDepth {
+		return errors.Errorf("foreign key actions on table %q nested too deeply", fk.searchTable.Name)
+	}
+
+	b := c.txn.NewBatch()
+	if newValues == nil && action == ForeignKeyReference_CASCADE {
+		if err := c.deleteReferencingRows(ctx, b, fk, oldValues); err != nil {
+			return err
+		}
+	} else {
+		if err := c.updateReferencingRows(ctx, b, fk, action, oldValues, newValues); err != nil {
+			return err
+		}
+	}
+	return c.txn.Run(ctx, b)
+}
+
+// deleteReferencingRows adds to the batch the deletion of the rows
+// referencing the given values through fk.
+func (c *fkCascader) deleteReferencingRows(
+	ctx context.Context, b *client.Batch, fk baseFKHelper, values parser.Datums,
+) error {
+	rd, err := c.deleter(fk.searchTable)
+	if err != nil {
+		return err
+	}
+	rows, err := c.referencingRows(ctx, fk, values, rd.FetchCols, rd.FetchColIDtoRowIndex)
+	if err != nil {
+		return err
+	}
+	primaryPrefix := MakeIndexKeyPrefix(fk.searchTable, fk.searchTable.PrimaryIndex.ID)
+	for _, row := range rows {
+		key, _, err := EncodeIndexKey(
+			fk.searchTable, &fk.searchTable.PrimaryIndex, rd.FetchColIDtoRowIndex, row, primaryPrefix)
+		if err != nil {
+			return err
+		}
+		if _, ok := c.deleted[string(key)]; ok {
+			continue
+		}
+		if c.deleted == nil {
+			c.deleted = make(map[string]struct{})
+		}
+		c.deleted[string(key)] = struct{}{}
+		if err := rd.DeleteRow(ctx, b, row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// updateReferencingRows adds to the batch the update of the rows
+// referencing oldValues through fk, according to the given action.
+func (c *fkCascader) updateReferencingRows(
+	ctx context.Context,
+	b *client.Batch,
+	fk baseFKHelper,
+	action ForeignKeyReference_Action,
+	oldValues, newValues parser.Datums,
+) error {
+	ru, err := c.updater(fk, action)
+	if err != nil {
+		return err
+	}
+	rows, err := c.referencingRows(ctx, fk, oldValues, ru.FetchCols, ru.FetchColIDtoRowIndex)
+	if err != nil {
+		return err
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+
+	var defaultExprs []parser.TypedExpr
+	if action == ForeignKeyReference_SET_DEFAULT {
+		var parse parser.Parser
+		if defaultExprs, err = MakeDefaultExprs(ru.UpdateCols, &parse, c.evalCtx); err != nil {
+			return err
+		}
+	}
+	updateValues := make(parser.Datums, len(ru.UpdateCols))
+	for _, row := range rows {
+		for i, col := range ru.UpdateCols {
+			switch action {
+			case ForeignKeyReference_CASCADE:
+				updateValues[i] = newValues[fk.ids[col.ID]]
+			case ForeignKeyReference_SET_DEFAULT:
+				if defaultExprs == nil {
+					updateValues[i] = parser.DNull
+				} else if updateValues[i], err = defaultExprs[i].Eval(c.evalCtx); err != nil {
+					return err
+				}
+			default:
+				updateValues[i] = parser.DNull
+			}
+		}
+		if _, err := ru.UpdateRow(ctx, b, row, updateValues); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleter returns the RowDeleter used to delete rows of the given table.
+func (c *fkCascader) deleter(table *TableDescriptor) (*RowDeleter, error) {
+	if rd, ok := c.deleters[table.ID]; ok {
+		return rd, nil
+	}
+	rd, err := makeRowDeleter(c.txn, table, c.otherTables, table.Columns, CheckFKs, c)
+	if err != nil {
+		return nil, err
+	}
+	if c.deleters == nil {
+		c.deleters = make(map[ID]*RowDeleter)
+	}
+	c.deleters[table.ID] = &rd
+	return &rd, nil
+}
+
+// updater returns the RowUpdater used to set the referencing columns of
+// fk according to the given action.
+func (c *fkCascader) updater(fk baseFKHelper, action ForeignKeyReference_Action) (*RowUpdater, error) {
+	key := fkCascadeKey{table: fk.searchTable.ID, index: fk.searchIdx.ID, action: action}
+	if ru, ok := c.updaters[key]; ok {
+		return ru, nil
+	}
+	updateCols := make([]ColumnDescriptor, fk.prefixLen)
+	for i, colID := range fk.searchIdx.ColumnIDs[:fk.prefixLen] {
+		col, err := fk.searchTable.FindColumnByID(colID)
+		if err != nil {
+			return nil, err
+		}
+		updateCols[i] = *col
+	}
+	ru, err := makeRowUpdater(
+		c.txn, fk.searchTable, c.otherTables, updateCols, fk.searchTable.Columns, RowUpdaterDefault, c,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if action == ForeignKeyReference_CASCADE {
+		// The new values of the referencing columns are those of the
+		// referenced row, which is only written once its referencing rows
+		// have been updated: the foreign key cannot be checked yet.
+		delete(ru.Fks.outbound, fk.searchIdx.ID)
+	}
+	if c.updaters == nil {
+		c.updaters = make(map[fkCascadeKey]*RowUpdater)
+	}
+	c.updaters[key] = &ru
+	return &ru, nil
+}
+
+// referencingRows returns the rows of the referencing table of fk whose
+// foreign key columns hold the given values of the referenced row. The
+// returned rows hold the values of the given columns.
+func (c *fkCascader) referencingRows(
+	ctx context.Context,
+	fk baseFKHelper,
+	values parser.Datums,
+	cols []ColumnDescriptor,
+	colIDtoRowIndex map[ColumnID]int,
+) ([]parser.Datums, error) {
+	table := fk.searchTable
+	key, containsNull, err := EncodePartialIndexKey(
+		table, fk.searchIdx, fk.prefixLen, fk.ids, values, fk.searchPrefix)
+	if err != nil {
+		return nil, err
+	}
+	if containsNull {
+		// A NULL is never referenced.
+		return nil, nil
+	}
+	spans := roachpb.Spans{{Key: key, EndKey: roachpb.Key(key).PrefixEnd()}}
+	if fk.searchIdx.ID != table.PrimaryIndex.ID {
+		if spans, err = c.primaryKeySpans(ctx, table, fk.searchIdx, spans); err != nil {
+			return nil, err
+		}
+		if len(spans) == 0 {
+			return nil, nil
+		}
+	}
+
+	valNeededForCol := make([]bool, len(cols))
+	for i := range valNeededForCol {
+		valNeededForCol[i] = true
+	}
+	var rf RowFetcher
+	if err := rf.Init(
+		table, colIDtoRowIndex, &table.PrimaryIndex, false /* reverse */, false, /* isSecondaryIndex */
+		cols, valNeededForCol, false /* returnRangeInfo */); err != nil {
+		return nil, err
+	}
+	if err := rf.StartScan(ctx, c.txn, spans, true /* limit batches */, 0); err != nil {
+		return nil, err
+	}
+	var rows []parser.Datums
+	for {
+		row, err := rf.NextRowDecoded(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if row == nil {
+			return rows, nil
+		}
+		rows = append(rows, append(parser.Datums(nil), row...))
+	}
+}
+
+// primaryKeySpans scans the given spans of a secondary index and returns
+// the spans of the primary index holding the rows found.
+func (c *fkCascader) primaryKeySpans(
+	ctx context.Context, table *TableDescriptor, index *IndexDescriptor, spans roachpb.Spans,
+) (roachpb.Spans, error) {
+	colIDtoRowIndex := ColIDtoRowIndexFromCols(table.Columns)
+	valNeededForCol := make([]bool, len(table.Columns))
+	for _, colID := range table.PrimaryIndex.ColumnIDs {
+		valNeededForCol[colIDtoRowIndex[colID]] = true
+	}
+	var rf RowFetcher
+	if err := rf.Init(
+		table, colIDtoRowIndex, index, false /* reverse */, true, /* isSecondaryIndex */
+		table.Columns, valNeededForCol, false /* returnRangeInfo */); err != nil {
+		return nil, err
+	}
+	if err := rf.StartScan(ctx, c.txn, spans, true /* limit batches */, 0); err != nil {
+		return nil, err
+	}
+	primaryPrefix := MakeIndexKeyPrefix(table, table.PrimaryIndex.ID)
+	var primarySpans roachpb.Spans
+	for {
+		row, err := rf.NextRowDecoded(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if row == nil {
+			break
+		}
+		key, _, err := EncodeIndexKey(table, &table.PrimaryIndex, colIDtoRowIndex, row, primaryPrefix)
+		if err != nil {
+			return nil, err
+		}
+		primarySpans = append(primarySpans, roachpb.Span{Key: key, EndKey: roachpb.Key(key).PrefixEnd()})
+	}
+	sort.Sort(primarySpans)
+	return primarySpans, nil
+}
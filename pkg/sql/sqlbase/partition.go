@@ -0,0 +1,238 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sqlbase
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	"github.com/cockroachdb/cockroach/pkg/config"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+)
+
+// PartitionNames returns the names of the partitions of the index, in the
+// order they were declared.
+func (p *PartitioningDescriptor) PartitionNames() []string {
+	var names []string
+	for _, l := range p.List {
+		names = append(names, l.Name)
+	}
+	for _, r := range p.Range {
+		names = append(names, r.Name)
+	}
+	return names
+}
+
+// FindIndexByPartitionName finds the non-drop index containing the partition
+// with the given name. It returns nil if there is no such partition.
+func (desc *TableDescriptor) FindIndexByPartitionName(partition string) *IndexDescriptor {
+	normName := parser.ReNormalizeName(partition)
+	if hasPartition(&desc.PrimaryIndex, normName) {
+		return &desc.PrimaryIndex
+	}
+	for i := range desc.Indexes {
+		if hasPartition(&desc.Indexes[i], normName) {
+			return &desc.Indexes[i]
+		}
+	}
+	return nil
+}
+
+func hasPartition(index *IndexDescriptor, normName string) bool {
+	for _, name := range index.Partitioning.PartitionNames() {
+		if parser.ReNormalizeName(name) == normName {
+			return true
+		}
+	}
+	return false
+}
+
+// validatePartitioning checks that the partitioning of the index is well
+// formed and that its partition names are not used by any other partition of
+// the table, as recorded in partitionNames.
+func (desc *TableDescriptor) validatePartitioning(
+	index IndexDescriptor, partitionNames map[string]string,
+) error {
+	part := &index.Partitioning
+	if part.NumColumns == 0 {
+		if len(part.List) > 0 || len(part.Range) > 0 {
+			return fmt.Errorf("index %q has partitions but no partitioning columns", index.Name)
+		}
+		return nil
+	}
+	if int(part.NumColumns) > len(index.ColumnIDs) {
+		return fmt.Errorf("index %q is partitioned by %d columns but only has %d",
+			index.Name, part.NumColumns, len(index.ColumnIDs))
+	}
+	if len(part.List) > 0 && len(part.Range) > 0 {
+		return fmt.Errorf("index %q has both LIST and RANGE partitions", index.Name)
+	}
+	if len(part.List) == 0 && len(part.Range) == 0 {
+		return fmt.Errorf("index %q must contain at least 1 partition", index.Name)
+	}
+
+	for _, name := range part.PartitionNames() {
+		if err := validateName(name, "partition"); err != nil {
+			return err
+		}
+		normName := parser.ReNormalizeName(name)
+		if other, ok := partitionNames[normName]; ok {
+			return fmt.Errorf("partition %q is defined by both index %q and index %q",
+				name, other, index.Name)
+		}
+		partitionNames[normName] = index.Name
+	}
+
+	listValues := map[string]struct{}{}
+	for _, l := range part.List {
+		if len(l.Values) == 0 {
+			return fmt.Errorf("partition %q must contain at least 1 value", l.Name)
+		}
+		for _, v := range l.Values {
+			if _, ok := listValues[string(v)]; ok {
+				if len(v) == 0 {
+					return fmt.Errorf("index %q has more than one DEFAULT partition", index.Name)
+				}
+				return fmt.Errorf("partition %q contains a value that is already "+
+					"present in another partition of index %q", l.Name, index.Name)
+			}
+			listValues[string(v)] = struct{}{}
+		}
+	}
+
+	var prevBound []byte
+	for i, r := range part.Range {
+		if len(r.UpperBound) == 0 {
+			if i != len(part.Range)-1 {
+				return fmt.Errorf("MAXVALUE partition %q must be the last partition of index %q",
+					r.Name, index.Name)
+			}
+			continue
+		}
+		if i > 0 && bytes.Compare(prevBound, r.UpperBound) >= 0 {
+			return fmt.Errorf("partition %q must have a greater upper bound than partition %q",
+				r.Name, part.Range[i-1].Name)
+		}
+		prevBound = r.UpperBound
+	}
+	return nil
+}
+
+// GenerateSubzoneSpans constructs the SubzoneSpans of a zone config from its
+// subzones and the indexes and partitions of the table it applies to.
+//
+// Every key in the table is covered by the most specific subzone that applies
+// to it: the subzone of its partition if there is one, otherwise the subzone
+// of its index. Keys not covered by any subzone do not appear in the spans.
+// The returned spans are sorted and non-overlapping, as required by
+// config.ZoneConfig.GetSubzoneForKeySuffix.
+func GenerateSubzoneSpans(
+	tableDesc *TableDescriptor, subzones []config.Subzone,
+) []config.SubzoneSpan {
+	subzoneIndex := func(indexID IndexID, partition string) int32 {
+		for i, s := range subzones {
+			if IndexID(s.IndexID) == indexID && s.PartitionName == partition {
+				return int32(i)
+			}
+		}
+		return -1
+	}
+
+	var spans []config.SubzoneSpan
+	addSpan := func(key, endKey roachpb.Key, subzone int32) {
+		if subzone < 0 || bytes.Compare(key, endKey) >= 0 {
+			return
+		}
+		if n := len(spans); n > 0 {
+			prev := &spans[n-1]
+			if prev.SubzoneIndex == subzone && prev.EndKey.Equal(key) {
+				prev.EndKey = endKey
+				return
+			}
+		}
+		spans = append(spans, config.SubzoneSpan{Key: key, EndKey: endKey, SubzoneIndex: subzone})
+	}
+
+	indexes := tableDesc.AllNonDropIndexes()
+	sort.Slice(indexes, func(i, j int) bool { return indexes[i].ID < indexes[j].ID })
+	for _, index := range indexes {
+		indexPrefix := encoding.EncodeUvarintAscending(nil, uint64(index.ID))
+		makeKey := func(suffix []byte) roachpb.Key {
+			key := make(roachpb.Key, 0, len(indexPrefix)+len(suffix))
+			return append(append(key, indexPrefix...), suffix...)
+		}
+		indexStart, indexEnd := makeKey(nil), makeKey(nil).PrefixEnd()
+		indexSubzone := subzoneIndex(index.ID, "")
+		partitionSubzone := func(name string) int32 {
+			if s := subzoneIndex(index.ID, name); s >= 0 {
+				return s
+			}
+			return indexSubzone
+		}
+
+		// Collect the spans of the partitions of the index. Keys between them
+		// belong to the DEFAULT partition, if there is one, or else just to the
+		// index.
+		type partitionSpan struct {
+			key, endKey roachpb.Key
+			subzone     int32
+		}
+		var partitionSpans []partitionSpan
+		defaultSubzone := indexSubzone
+		for _, l := range index.Partitioning.List {
+			subzone := partitionSubzone(l.Name)
+			for _, v := range l.Values {
+				if len(v) == 0 {
+					defaultSubzone = subzone
+					continue
+				}
+				key := makeKey(v)
+				partitionSpans = append(partitionSpans, partitionSpan{key, key.PrefixEnd(), subzone})
+			}
+		}
+		lowerBound := indexStart
+		for _, r := range index.Partitioning.Range {
+			upperBound := indexEnd
+			if len(r.UpperBound) > 0 {
+				upperBound = makeKey(r.UpperBound)
+			}
+			partitionSpans = append(partitionSpans,
+				partitionSpan{lowerBound, upperBound, partitionSubzone(r.Name)})
+			lowerBound = upperBound
+		}
+		sort.Slice(partitionSpans, func(i, j int) bool {
+			return partitionSpans[i].key.Compare(partitionSpans[j].key) < 0
+		})
+
+		cursor := indexStart
+		for _, s := range partitionSpans {
+			addSpan(cursor, s.key, defaultSubzone)
+			addSpan(s.key, s.endKey, s.subzone)
+			cursor = s.endKey
+		}
+		addSpan(cursor, indexEnd, defaultSubzone)
+	}
+
+	// An EndKey of Key.PrefixEnd() is implied when it is left empty.
+	for i := range spans {
+		if spans[i].EndKey.Equal(spans[i].Key.PrefixEnd()) {
+			spans[i].EndKey = nil
+		}
+	}
+	return spans
+}
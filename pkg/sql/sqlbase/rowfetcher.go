@@ -140,7 +140,14 @@ func (rf *RowFetcher) Init(
 
 	if isSecondaryIndex {
 		for i, needed := range valNeededForCol {
-			if needed && !index.ContainsColumnID(rf.cols[i].ID) {
+			if !needed {
+				continue
+			}
+			id := rf.cols[i].ID
+			// The values of the indexed column of an inverted index can't be
+			// decoded from its keys.
+			if !index.ContainsColumnID(id) ||
+				(index.Type == IndexDescriptor_INVERTED && id == index.ColumnIDs[0]) {
 				return errors.Errorf("requested column %s not in index", rf.cols[i].Name)
 			}
 		}
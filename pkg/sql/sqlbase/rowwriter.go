@@ -44,7 +44,7 @@ const (
 type rowHelper struct {
 	TableDesc    *TableDescriptor
 	Indexes      []IndexDescriptor
-	indexEntries []IndexEntry
+	indexEntries [][]IndexEntry
 
 	// Computed and cached.
 	primaryIndexKeyPrefix []byte
@@ -53,11 +53,11 @@ type rowHelper struct {
 }
 
 // encodeIndexes encodes the primary and secondary index keys. The
-// secondaryIndexEntries are only valid until the next call to encodeIndexes or
-// encodeSecondaryIndexes.
+// secondaryIndexEntries hold the entries of each index in rh.Indexes and are
+// only valid until the next call to encodeIndexes or encodeSecondaryIndexes.
 func (rh *rowHelper) encodeIndexes(
 	colIDtoRowIndex map[ColumnID]int, values []parser.Datum,
-) (primaryIndexKey []byte, secondaryIndexEntries []IndexEntry, err error) {
+) (primaryIndexKey []byte, secondaryIndexEntries [][]IndexEntry, err error) {
 	if rh.primaryIndexKeyPrefix == nil {
 		rh.primaryIndexKeyPrefix = MakeIndexKeyPrefix(rh.TableDesc,
 			rh.TableDesc.PrimaryIndex.ID)
@@ -75,18 +75,20 @@ func (rh *rowHelper) encodeIndexes(
 }
 
 // encodeSecondaryIndexes encodes the secondary index keys. The
-// secondaryIndexEntries are only valid until the next call to encodeIndexes or
-// encodeSecondaryIndexes.
+// secondaryIndexEntries hold the entries of each index in rh.Indexes and are
+// only valid until the next call to encodeIndexes or encodeSecondaryIndexes.
 func (rh *rowHelper) encodeSecondaryIndexes(
 	colIDtoRowIndex map[ColumnID]int, values []parser.Datum,
-) (secondaryIndexEntries []IndexEntry, err error) {
+) (secondaryIndexEntries [][]IndexEntry, err error) {
 	if len(rh.indexEntries) != len(rh.Indexes) {
-		rh.indexEntries = make([]IndexEntry, len(rh.Indexes))
+		rh.indexEntries = make([][]IndexEntry, len(rh.Indexes))
 	}
-	err = EncodeSecondaryIndexes(
-		rh.TableDesc, rh.Indexes, colIDtoRowIndex, values, rh.indexEntries)
-	if err != nil {
-		return nil, err
+	for i := range rh.Indexes {
+		rh.indexEntries[i], err = EncodeSecondaryIndex(
+			rh.TableDesc, &rh.Indexes[i], colIDtoRowIndex, values)
+		if err != nil {
+			return nil, err
+		}
 	}
 	return rh.indexEntries, nil
 }
@@ -331,9 +333,11 @@ func (ri *RowInserter) InsertRow(
 		ri.key = nil
 	}
 
-	for i := range secondaryIndexEntries {
-		e := &secondaryIndexEntries[i]
-		putFn(ctx, b, &e.Key, &e.Value)
+	for _, entries := range secondaryIndexEntries {
+		for i := range entries {
+			e := &entries[i]
+			putFn(ctx, b, &e.Key, &e.Value)
+		}
 	}
 
 	return nil
@@ -358,7 +362,7 @@ type RowUpdater struct {
 	marshalled      []roachpb.Value
 	newValues       []parser.Datum
 	key             roachpb.Key
-	indexEntriesBuf []IndexEntry
+	indexEntriesBuf [][]IndexEntry
 	valueBuf        []byte
 	value           roachpb.Value
 }
@@ -382,6 +386,9 @@ const (
 // The returned RowUpdater contains a FetchCols field that defines the
 // expectation of which values are passed as oldValues to UpdateRow. Any column
 // passed in requestedCols will be included in FetchCols.
+//
+// The evalCtx is used to evaluate the default values stored by the ON
+// UPDATE SET DEFAULT actions of the foreign keys referencing the table.
 func MakeRowUpdater(
 	txn *client.Txn,
 	tableDesc *TableDescriptor,
@@ -389,6 +396,22 @@ func MakeRowUpdater(
 	updateCols []ColumnDescriptor,
 	requestedCols []ColumnDescriptor,
 	updateType rowUpdaterType,
+	evalCtx *parser.EvalContext,
+) (RowUpdater, error) {
+	return makeRowUpdater(
+		txn, tableDesc, fkTables, updateCols, requestedCols, updateType,
+		newFKCascader(txn, fkTables, evalCtx),
+	)
+}
+
+func makeRowUpdater(
+	txn *client.Txn,
+	tableDesc *TableDescriptor,
+	fkTables TableLookupsByID,
+	updateCols []ColumnDescriptor,
+	requestedCols []ColumnDescriptor,
+	updateType rowUpdaterType,
+	cascader *fkCascader,
 ) (RowUpdater, error) {
 	updateColIDtoRowIndex := ColIDtoRowIndexFromCols(updateCols)
 
@@ -465,7 +488,7 @@ func MakeRowUpdater(
 		var err error
 		// When changing the primary key, we delete the old values and reinsert
 		// them, so request them all.
-		if ru.rd, err = MakeRowDeleter(txn, tableDesc, fkTables, tableDesc.Columns, SkipFKs); err != nil {
+		if ru.rd, err = makeRowDeleter(txn, tableDesc, fkTables, tableDesc.Columns, SkipFKs, nil); err != nil {
 			return RowUpdater{}, err
 		}
 		ru.FetchCols = ru.rd.FetchCols
@@ -517,7 +540,7 @@ func MakeRowUpdater(
 	}
 
 	var err error
-	if ru.Fks, err = makeFKUpdateHelper(txn, *tableDesc, fkTables, ru.FetchColIDtoRowIndex); err != nil {
+	if ru.Fks, err = makeFKUpdateHelper(txn, *tableDesc, fkTables, ru.FetchColIDtoRowIndex, cascader); err != nil {
 		return RowUpdater{}, err
 	}
 	return ru, nil
@@ -589,7 +612,7 @@ func (ru *RowUpdater) UpdateRow(
 			return nil, err
 		}
 		for i := range newSecondaryIndexEntries {
-			if !bytes.Equal(newSecondaryIndexEntries[i].Key, secondaryIndexEntries[i].Key) {
+			if !indexEntryKeysEqual(newSecondaryIndexEntries[i], secondaryIndexEntries[i]) {
 				if err := ru.Fks.checkIdx(ctx, ru.Helper.Indexes[i].ID, oldValues, ru.newValues); err != nil {
 					return nil, err
 				}
@@ -703,8 +726,13 @@ func (ru *RowUpdater) UpdateRow(
 	}
 
 	// Update secondary indexes.
-	for i, newSecondaryIndexEntry := range newSecondaryIndexEntries {
-		secondaryIndexEntry := secondaryIndexEntries[i]
+	for i, newEntries := range newSecondaryIndexEntries {
+		if ru.Helper.Indexes[i].Type == IndexDescriptor_INVERTED {
+			ru.updateInvertedIndex(ctx, b, i, secondaryIndexEntries[i], newEntries)
+			continue
+		}
+		newSecondaryIndexEntry := newEntries[0]
+		secondaryIndexEntry := secondaryIndexEntries[i][0]
 		var expValue interface{}
 		if !bytes.Equal(newSecondaryIndexEntry.Key, secondaryIndexEntry.Key) {
 			if err := ru.Fks.checkIdx(ctx, ru.Helper.Indexes[i].ID, oldValues, ru.newValues); err != nil {
@@ -732,6 +760,57 @@ func (ru *RowUpdater) UpdateRow(
 	return ru.newValues, nil
 }
 
+// updateInvertedIndex adds to the batch the kv operations necessary to
+// replace the oldEntries of the i-th index with its newEntries, both of which
+// must be sorted by key. Entries present in both are left untouched.
+func (ru *RowUpdater) updateInvertedIndex(
+	ctx context.Context, b *client.Batch, i int, oldEntries, newEntries []IndexEntry,
+) {
+	_, deleteOnly := ru.deleteOnlyIndex[i]
+	for len(oldEntries) > 0 || len(newEntries) > 0 {
+		c := 1
+		if len(oldEntries) == 0 {
+			c = -1
+		} else if len(newEntries) > 0 {
+			c = bytes.Compare(newEntries[0].Key, oldEntries[0].Key)
+		}
+		switch {
+		case c == 0:
+			oldEntries, newEntries = oldEntries[1:], newEntries[1:]
+		case c > 0:
+			if log.V(2) {
+				log.Infof(ctx, "Del %s", oldEntries[0].Key)
+			}
+			b.Del(oldEntries[0].Key)
+			oldEntries = oldEntries[1:]
+		default:
+			// Do not update Indexes in the DELETE_ONLY state.
+			if !deleteOnly {
+				e := &newEntries[0]
+				if log.V(2) {
+					log.Infof(ctx, "CPut %s -> %v", e.Key, e.Value.PrettyPrint())
+				}
+				b.CPut(e.Key, &e.Value, nil)
+			}
+			newEntries = newEntries[1:]
+		}
+	}
+}
+
+// indexEntryKeysEqual returns whether the two lists of index entries have the
+// same keys.
+func indexEntryKeysEqual(a, b []IndexEntry) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !bytes.Equal(a[i].Key, b[i].Key) {
+			return false
+		}
+	}
+	return true
+}
+
 // IsColumnOnlyUpdate returns true if this RowUpdater is only updating column
 // data (in contrast to updating the primary key or other indexes).
 func (ru *RowUpdater) IsColumnOnlyUpdate() bool {
@@ -760,12 +839,31 @@ type RowDeleter struct {
 // The returned RowDeleter contains a FetchCols field that defines the
 // expectation of which values are passed as values to DeleteRow. Any column
 // passed in requestedCols will be included in FetchCols.
+//
+// The evalCtx is used to evaluate the default values stored by the ON
+// DELETE SET DEFAULT actions of the foreign keys referencing the table.
 func MakeRowDeleter(
 	txn *client.Txn,
 	tableDesc *TableDescriptor,
 	fkTables TableLookupsByID,
 	requestedCols []ColumnDescriptor,
 	checkFKs bool,
+	evalCtx *parser.EvalContext,
+) (RowDeleter, error) {
+	var cascader *fkCascader
+	if checkFKs {
+		cascader = newFKCascader(txn, fkTables, evalCtx)
+	}
+	return makeRowDeleter(txn, tableDesc, fkTables, requestedCols, checkFKs, cascader)
+}
+
+func makeRowDeleter(
+	txn *client.Txn,
+	tableDesc *TableDescriptor,
+	fkTables TableLookupsByID,
+	requestedCols []ColumnDescriptor,
+	checkFKs bool,
+	cascader *fkCascader,
 ) (RowDeleter, error) {
 	indexes := tableDesc.Indexes
 	for _, m := range tableDesc.Mutations {
@@ -814,7 +912,7 @@ func MakeRowDeleter(
 	}
 	if checkFKs {
 		var err error
-		if rd.Fks, err = makeFKDeleteHelper(txn, *tableDesc, fkTables, fetchColIDtoRowIndex); err != nil {
+		if rd.Fks, err = makeFKDeleteHelper(txn, *tableDesc, fkTables, fetchColIDtoRowIndex, cascader); err != nil {
 			return RowDeleter{}, err
 		}
 	}
@@ -834,11 +932,13 @@ func (rd *RowDeleter) DeleteRow(ctx context.Context, b *client.Batch, values []p
 		return err
 	}
 
-	for _, secondaryIndexEntry := range secondaryIndexEntries {
-		if log.V(2) {
-			log.Infof(ctx, "Del %s", secondaryIndexEntry.Key)
+	for _, entries := range secondaryIndexEntries {
+		for _, secondaryIndexEntry := range entries {
+			if log.V(2) {
+				log.Infof(ctx, "Del %s", secondaryIndexEntry.Key)
+			}
+			b.Del(secondaryIndexEntry.Key)
 		}
-		b.Del(secondaryIndexEntry.Key)
 	}
 
 	// Delete the row.
@@ -861,15 +961,17 @@ func (rd *RowDeleter) DeleteIndexRow(
 	if err := rd.Fks.checkAll(ctx, values); err != nil {
 		return err
 	}
-	secondaryIndexEntry, err := EncodeSecondaryIndex(
+	secondaryIndexEntries, err := EncodeSecondaryIndex(
 		rd.Helper.TableDesc, idx, rd.FetchColIDtoRowIndex, values)
 	if err != nil {
 		return err
 	}
-	if log.V(2) {
-		log.Infof(ctx, "Del %s", secondaryIndexEntry.Key)
+	for _, secondaryIndexEntry := range secondaryIndexEntries {
+		if log.V(2) {
+			log.Infof(ctx, "Del %s", secondaryIndexEntry.Key)
+		}
+		b.Del(secondaryIndexEntry.Key)
 	}
-	b.Del(secondaryIndexEntry.Key)
 	return nil
 }
 
@@ -288,6 +288,9 @@ func (desc *TableDescriptor) TypeName() string {
 	if desc.IsView() {
 		return "view"
 	}
+	if desc.IsSequence() {
+		return "sequence"
+	}
 	return "table"
 }
 
@@ -303,9 +306,10 @@ func (desc *TableDescriptor) IsEmpty() bool {
 }
 
 // IsTable returns true if the TableDescriptor actually describes a
-// Table resource, as opposed to a different resource (like a View).
+// Table resource, as opposed to a different resource (like a View or a
+// Sequence).
 func (desc *TableDescriptor) IsTable() bool {
-	return !desc.IsView()
+	return !desc.IsView() && !desc.IsSequence()
 }
 
 // IsView returns true if the TableDescriptor actually describes a
@@ -314,6 +318,12 @@ func (desc *TableDescriptor) IsView() bool {
 	return desc.ViewQuery != ""
 }
 
+// IsSequence returns true if the TableDescriptor actually describes a
+// Sequence resource rather than a Table.
+func (desc *TableDescriptor) IsSequence() bool {
+	return desc.SequenceOpts != nil
+}
+
 // IsVirtualTable returns true if the TableDescriptor describes a
 // virtual Table (like the information_schema tables) and thus doesn't
 // need to be physically stored.
@@ -557,7 +567,9 @@ func HasCompositeKeyEncoding(kind ColumnType_Kind) bool {
 	switch kind {
 	case ColumnType_COLLATEDSTRING,
 		ColumnType_FLOAT,
-		ColumnType_DECIMAL:
+		ColumnType_DECIMAL,
+		ColumnType_JSON,
+		ColumnType_ARRAY:
 		return true
 	}
 	return false
@@ -661,9 +673,13 @@ func (desc *TableDescriptor) allocateIndexIDs(columnNames map[string]ColumnID) e
 		}
 
 		index.CompositeColumnIDs = nil
-		for _, colID := range index.ColumnIDs {
-			if _, ok := isCompositeColumn[colID]; ok {
-				index.CompositeColumnIDs = append(index.CompositeColumnIDs, colID)
+		// The values of the indexed column of an inverted index are not encoded
+		// in its keys, so they can't be composite.
+		if index.Type != IndexDescriptor_INVERTED {
+			for _, colID := range index.ColumnIDs {
+				if _, ok := isCompositeColumn[colID]; ok {
+					index.CompositeColumnIDs = append(index.CompositeColumnIDs, colID)
+				}
 			}
 		}
 		for _, colID := range index.ExtraColumnIDs {
@@ -1123,6 +1139,7 @@ func (desc *TableDescriptor) validateTableIndexes(
 
 	indexNames := map[string]struct{}{}
 	indexIDs := map[IndexID]string{}
+	partitionNames := map[string]string{}
 	for _, index := range desc.AllNonDropIndexes() {
 		if err := validateName(index.Name, "index"); err != nil {
 			return err
@@ -1171,6 +1188,14 @@ func (desc *TableDescriptor) validateTableIndexes(
 					index.Name, name, colID, index.ColumnIDs[i])
 			}
 		}
+
+		if err := desc.validateIndexType(index); err != nil {
+			return err
+		}
+
+		if err := desc.validatePartitioning(index, partitionNames); err != nil {
+			return err
+		}
 	}
 
 	for _, colID := range desc.PrimaryIndex.ColumnIDs {
@@ -1183,6 +1208,55 @@ func (desc *TableDescriptor) validateTableIndexes(
 	return nil
 }
 
+// validateIndexType checks that the columns of the index can be indexed by an
+// index of its type: ARRAY columns cannot be indexed, JSON columns can only be
+// part of inverted indexes, and inverted indexes can only index a single JSON
+// column.
+func (desc *TableDescriptor) validateIndexType(index IndexDescriptor) error {
+	if index.Type != IndexDescriptor_INVERTED {
+		for _, colID := range index.ColumnIDs {
+			col, err := desc.FindColumnByID(colID)
+			if err != nil {
+				return err
+			}
+			switch col.Type.Kind {
+			case ColumnType_JSON:
+				return fmt.Errorf("column \"%s\" of type %s is not indexable; "+
+					"use an inverted index instead", col.Name, col.Type.SQLString())
+			case ColumnType_ARRAY:
+				return fmt.Errorf("column \"%s\" of type %s is not indexable",
+					col.Name, col.Type.SQLString())
+			}
+		}
+		return nil
+	}
+
+	if index.ID == desc.PrimaryIndex.ID {
+		return fmt.Errorf("primary key \"%s\" cannot be an inverted index", index.Name)
+	}
+	if len(index.ColumnIDs) != 1 {
+		return fmt.Errorf("inverted index \"%s\" must contain exactly 1 column", index.Name)
+	}
+	col, err := desc.FindColumnByID(index.ColumnIDs[0])
+	if err != nil {
+		return err
+	}
+	if col.Type.Kind != ColumnType_JSON {
+		return fmt.Errorf("column \"%s\" of type %s cannot be part of an inverted index",
+			col.Name, col.Type.SQLString())
+	}
+	if index.Unique {
+		return fmt.Errorf("inverted index \"%s\" cannot be unique", index.Name)
+	}
+	if len(index.StoreColumnNames) > 0 {
+		return fmt.Errorf("inverted index \"%s\" cannot store columns", index.Name)
+	}
+	if len(index.Interleave.Ancestors) > 0 {
+		return fmt.Errorf("inverted index \"%s\" cannot be interleaved", index.Name)
+	}
+	return nil
+}
+
 // FamilyHeuristicTargetBytes is the target total byte size of columns that the
 // current heuristic will assign to a family.
 const FamilyHeuristicTargetBytes = 256
@@ -1208,6 +1282,14 @@ func upperBoundColumnValueEncodedSize(col ColumnDescriptor) (int, bool) {
 		typ, size = encoding.Bytes, int(col.Type.Width)
 	case ColumnType_DECIMAL:
 		typ, size = encoding.Decimal, int(col.Type.Precision)
+	case ColumnType_JSON:
+		typ = encoding.JSON
+	case ColumnType_UUID:
+		typ = encoding.UUID
+	case ColumnType_INET:
+		typ = encoding.IPAddr
+	case ColumnType_ARRAY:
+		typ = encoding.Array
 	default:
 		panic(errors.Errorf("unknown column type: %s", col.Type.Kind))
 	}
@@ -1667,8 +1749,10 @@ func (c *ColumnType) SQLString() string {
 			return fmt.Sprintf("%s(%d) COLLATE %s", ColumnType_STRING.String(), c.Width, *c.Locale)
 		}
 		return fmt.Sprintf("%s COLLATE %s", ColumnType_STRING.String(), *c.Locale)
-	case ColumnType_INT_ARRAY:
-		return "INT[]"
+	case ColumnType_ARRAY:
+		return c.elementColumnType().SQLString() + "[]"
+	case ColumnType_JSON:
+		return "JSONB"
 	}
 	return c.Kind.String()
 }
@@ -1760,18 +1844,26 @@ func DatumTypeToColumnType(ptyp parser.Type) ColumnType {
 		ctyp.Kind = ColumnType_TIMESTAMPTZ
 	case parser.TypeInterval:
 		ctyp.Kind = ColumnType_INTERVAL
+	case parser.TypeJSON:
+		ctyp.Kind = ColumnType_JSON
+	case parser.TypeUUID:
+		ctyp.Kind = ColumnType_UUID
+	case parser.TypeINet:
+		ctyp.Kind = ColumnType_INET
 	case parser.TypeOid:
 		ctyp.Kind = ColumnType_OID
 	case parser.TypeNull:
 		ctyp.Kind = ColumnType_NULL
-	case parser.TypeIntArray:
-		ctyp.Kind = ColumnType_INT_ARRAY
 	case parser.TypeIntVector:
 		ctyp.Kind = ColumnType_INT2VECTOR
 	default:
 		if t, ok := ptyp.(parser.TCollatedString); ok {
 			ctyp.Kind = ColumnType_COLLATEDSTRING
 			ctyp.Locale = &t.Locale
+		} else if t, ok := ptyp.(parser.TArray); ok {
+			ctyp.Kind = ColumnType_ARRAY
+			contents := DatumTypeToColumnType(parser.UnwrapType(t.Typ)).Kind
+			ctyp.ArrayContents = &contents
 		} else {
 			panic(fmt.Sprintf("unsupported result type: %s", ptyp))
 		}
@@ -1803,6 +1895,12 @@ func (c *ColumnType) ToDatumType() parser.Type {
 		return parser.TypeTimestampTZ
 	case ColumnType_INTERVAL:
 		return parser.TypeInterval
+	case ColumnType_JSON:
+		return parser.TypeJSON
+	case ColumnType_UUID:
+		return parser.TypeUUID
+	case ColumnType_INET:
+		return parser.TypeINet
 	case ColumnType_COLLATEDSTRING:
 		if c.Locale == nil {
 			panic("locale is required for COLLATEDSTRING")
@@ -1814,14 +1912,22 @@ func (c *ColumnType) ToDatumType() parser.Type {
 		return parser.TypeOid
 	case ColumnType_NULL:
 		return parser.TypeNull
-	case ColumnType_INT_ARRAY:
-		return parser.TypeIntArray
+	case ColumnType_ARRAY:
+		return parser.TArray{Typ: c.elementColumnType().ToDatumType()}
 	case ColumnType_INT2VECTOR:
 		return parser.TypeIntVector
 	}
 	return nil
 }
 
+// elementColumnType returns the ColumnType of the elements of an ARRAY.
+func (c *ColumnType) elementColumnType() *ColumnType {
+	if c.ArrayContents == nil {
+		panic("array_contents is required for ARRAY")
+	}
+	return &ColumnType{Kind: *c.ArrayContents}
+}
+
 // SetID implements the DescriptorProto interface.
 func (desc *DatabaseDescriptor) SetID(id ID) {
 	desc.ID = id
@@ -1880,6 +1986,44 @@ func (f ForeignKeyReference) IsSet() bool {
 	return f.Table != 0
 }
 
+// ForeignKeyReferenceActionValue maps parser.ReferenceAction values to
+// the corresponding ForeignKeyReference_Action values.
+var ForeignKeyReferenceActionValue = [...]ForeignKeyReference_Action{
+	parser.NoAction:   ForeignKeyReference_NO_ACTION,
+	parser.Restrict:   ForeignKeyReference_RESTRICT,
+	parser.SetNull:    ForeignKeyReference_SET_NULL,
+	parser.SetDefault: ForeignKeyReference_SET_DEFAULT,
+	parser.Cascade:    ForeignKeyReference_CASCADE,
+}
+
+// ForeignKeyReferenceActionType maps ForeignKeyReference_Action values to
+// the corresponding parser.ReferenceAction values.
+var ForeignKeyReferenceActionType = [...]parser.ReferenceAction{
+	ForeignKeyReference_NO_ACTION:   parser.NoAction,
+	ForeignKeyReference_RESTRICT:    parser.Restrict,
+	ForeignKeyReference_SET_NULL:    parser.SetNull,
+	ForeignKeyReference_SET_DEFAULT: parser.SetDefault,
+	ForeignKeyReference_CASCADE:     parser.Cascade,
+}
+
+// Actions returns the referential actions of the foreign key.
+func (f ForeignKeyReference) Actions() parser.ReferenceActions {
+	return parser.ReferenceActions{
+		Delete: ForeignKeyReferenceActionType[f.OnDelete],
+		Update: ForeignKeyReferenceActionType[f.OnUpdate],
+	}
+}
+
+// IsCascading returns whether the referential action modifies the
+// referencing rows, as opposed to rejecting the change.
+func (a ForeignKeyReference_Action) IsCascading() bool {
+	switch a {
+	case ForeignKeyReference_CASCADE, ForeignKeyReference_SET_NULL, ForeignKeyReference_SET_DEFAULT:
+		return true
+	}
+	return false
+}
+
 // InvalidateFKConstraints sets all FK constraints to un-validated.
 func (desc *TableDescriptor) InvalidateFKConstraints() {
 	// We don't use GetConstraintInfo because we want to edit the passed desc.
@@ -853,6 +853,12 @@ func TestColumnTypeSQLString(t *testing.T) {
 		{ColumnType{Kind: ColumnType_STRING}, "STRING"},
 		{ColumnType{Kind: ColumnType_STRING, Width: 10}, "STRING(10)"},
 		{ColumnType{Kind: ColumnType_BYTES}, "BYTES"},
+		{ColumnType{Kind: ColumnType_JSON}, "JSONB"},
+		{ColumnType{Kind: ColumnType_UUID}, "UUID"},
+		{ColumnType{Kind: ColumnType_INET}, "INET"},
+		{ColumnType{Kind: ColumnType_ARRAY, ArrayContents: ColumnType_INT.Enum()}, "INT[]"},
+		{ColumnType{Kind: ColumnType_ARRAY, ArrayContents: ColumnType_TIMESTAMPTZ.Enum()},
+			"TIMESTAMP WITH TIME ZONE[]"},
 	}
 	for i, d := range testData {
 		sql := d.colType.SQLString()
@@ -881,6 +887,10 @@ func TestColumnValueEncodedSize(t *testing.T) {
 		{ColumnType{Kind: ColumnType_STRING}, -1},
 		{ColumnType{Kind: ColumnType_STRING, Width: 100}, 110},
 		{ColumnType{Kind: ColumnType_BYTES}, -1},
+		{ColumnType{Kind: ColumnType_JSON}, -1},
+		{ColumnType{Kind: ColumnType_UUID}, 18},
+		{ColumnType{Kind: ColumnType_INET}, 20},
+		{ColumnType{Kind: ColumnType_ARRAY, ArrayContents: ColumnType_INT.Enum()}, -1},
 	}
 	for i, test := range tests {
 		testIsBounded := test.size != -1
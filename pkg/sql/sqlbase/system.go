@@ -51,7 +51,8 @@ CREATE TABLE system.descriptor (
 	UsersTableSchema = `
 CREATE TABLE system.users (
   username         STRING PRIMARY KEY,
-  "hashedPassword" BYTES
+  "hashedPassword" BYTES,
+  "isRole"         BOOL NOT NULL DEFAULT false
 );`
 
 	// Zone settings per DB/Table.
@@ -89,7 +90,7 @@ CREATE TABLE system.eventlog (
   "targetID"    INT        NOT NULL,
   "reportingID" INT        NOT NULL,
   info          STRING,
-  "uniqueID"    BYTES      DEFAULT uuid_v4(),
+  "uniqueID"    BYTES      DEFAULT uuid_v4()::BYTES,
   PRIMARY KEY (timestamp, "uniqueID")
 );`
 
@@ -123,6 +124,35 @@ CREATE TABLE system.jobs (
 	INDEX (status, created),
 	FAMILY (id, status, created, payload)
 );`
+
+	// role_members records the members of each role. A member is a user or
+	// another role.
+	RoleMembersTableSchema = `
+CREATE TABLE system.role_members (
+	"role"    STRING NOT NULL,
+	"member"  STRING NOT NULL,
+	"isAdmin" BOOL   NOT NULL,
+	PRIMARY KEY ("role", "member"),
+	INDEX ("member")
+);`
+
+	// table_statistics stores the statistics collected by CREATE STATISTICS
+	// on a set of columns of a table. The histogram is only collected for
+	// statistics on a single column.
+	TableStatisticsTableSchema = `
+CREATE TABLE system.table_statistics (
+	"tableID"       INT       NOT NULL,
+	"statisticID"   INT       NOT NULL DEFAULT unique_rowid(),
+	name            STRING,
+	"columnIDs"     INT[]     NOT NULL,
+	"createdAt"     TIMESTAMP NOT NULL DEFAULT now(),
+	"rowCount"      INT       NOT NULL,
+	"distinctCount" INT       NOT NULL,
+	"nullCount"     INT       NOT NULL,
+	histogram       BYTES,
+	PRIMARY KEY ("tableID", "statisticID"),
+	FAMILY ("tableID", "statisticID", name, "columnIDs", "createdAt", "rowCount", "distinctCount", "nullCount", histogram)
+);`
 )
 
 func pk(name string) IndexDescriptor {
@@ -174,7 +204,9 @@ var SystemAllowedPrivileges = map[ID]privilege.Lists{
 	// users will be able to modify system tables' schemas at will. CREATE and
 	// DROP privileges are allowed on the above system tables for backwards
 	// compatibility reasons only!
-	keys.JobsTableID: {privilege.ReadWriteData},
+	keys.JobsTableID:            {privilege.ReadWriteData},
+	keys.RoleMembersTableID:     {privilege.ReadWriteData},
+	keys.TableStatisticsTableID: {privilege.ReadWriteData},
 }
 
 // SystemDesiredPrivileges returns the desired privilege list (i.e., the
@@ -188,10 +220,14 @@ func SystemDesiredPrivileges(id ID) privilege.List {
 
 // Helpers used to make some of the TableDescriptor literals below more concise.
 var (
+	colKindInt = ColumnType_INT
+
+	colTypeBool      = ColumnType{Kind: ColumnType_BOOL}
 	colTypeInt       = ColumnType{Kind: ColumnType_INT}
 	colTypeString    = ColumnType{Kind: ColumnType_STRING}
 	colTypeBytes     = ColumnType{Kind: ColumnType_BYTES}
 	colTypeTimestamp = ColumnType{Kind: ColumnType_TIMESTAMP}
+	colTypeIntArray  = ColumnType{Kind: ColumnType_ARRAY, ArrayContents: &colKindInt}
 	singleASC        = []IndexDescriptor_Direction{IndexDescriptor_ASC}
 	singleID1        = []ColumnID{1}
 )
@@ -264,6 +300,8 @@ var (
 		NextMutationID: 1,
 	}
 
+	falseBoolString = "false"
+
 	// UsersTable is the descriptor for the users table.
 	UsersTable = TableDescriptor{
 		Name:     "users",
@@ -273,14 +311,16 @@ var (
 		Columns: []ColumnDescriptor{
 			{Name: "username", ID: 1, Type: colTypeString},
 			{Name: "hashedPassword", ID: 2, Type: colTypeBytes, Nullable: true},
+			{Name: "isRole", ID: 3, Type: colTypeBool, DefaultExpr: &falseBoolString},
 		},
-		NextColumnID: 3,
+		NextColumnID: 4,
 		Families: []ColumnFamilyDescriptor{
 			{Name: "primary", ID: 0, ColumnNames: []string{"username"}, ColumnIDs: singleID1},
 			{Name: "fam_2_hashedPassword", ID: 2, ColumnNames: []string{"hashedPassword"}, ColumnIDs: []ColumnID{2}, DefaultColumnID: 2},
+			{Name: "fam_3_isRole", ID: 3, ColumnNames: []string{"isRole"}, ColumnIDs: []ColumnID{3}, DefaultColumnID: 3},
 		},
 		PrimaryIndex:   pk("username"),
-		NextFamilyID:   3,
+		NextFamilyID:   4,
 		NextIndexID:    2,
 		Privileges:     NewPrivilegeDescriptor(security.RootUser, SystemDesiredPrivileges(keys.UsersTableID)),
 		FormatVersion:  InterleavedFormatVersion,
@@ -376,7 +416,7 @@ var (
 		NextMutationID: 1,
 	}
 
-	uuidV4String = "uuid_v4()"
+	uuidV4String = "uuid_v4()::BYTES"
 
 	// EventLogTable is the descriptor for the event log table.
 	EventLogTable = TableDescriptor{
@@ -522,6 +562,99 @@ var (
 		FormatVersion:  InterleavedFormatVersion,
 		NextMutationID: 1,
 	}
+
+	// RoleMembersTable is the descriptor for the role_members table.
+	RoleMembersTable = TableDescriptor{
+		Name:     "role_members",
+		ID:       keys.RoleMembersTableID,
+		ParentID: 1,
+		Version:  1,
+		Columns: []ColumnDescriptor{
+			{Name: "role", ID: 1, Type: colTypeString},
+			{Name: "member", ID: 2, Type: colTypeString},
+			{Name: "isAdmin", ID: 3, Type: colTypeBool},
+		},
+		NextColumnID: 4,
+		Families: []ColumnFamilyDescriptor{
+			{Name: "primary", ID: 0, ColumnNames: []string{"role", "member"}, ColumnIDs: []ColumnID{1, 2}},
+			{Name: "fam_3_isAdmin", ID: 3, ColumnNames: []string{"isAdmin"}, ColumnIDs: []ColumnID{3}, DefaultColumnID: 3},
+		},
+		NextFamilyID: 4,
+		PrimaryIndex: IndexDescriptor{
+			Name:             "primary",
+			ID:               1,
+			Unique:           true,
+			ColumnNames:      []string{"role", "member"},
+			ColumnDirections: []IndexDescriptor_Direction{IndexDescriptor_ASC, IndexDescriptor_ASC},
+			ColumnIDs:        []ColumnID{1, 2},
+		},
+		Indexes: []IndexDescriptor{
+			{
+				Name:             "role_members_member_idx",
+				ID:               2,
+				Unique:           false,
+				ColumnNames:      []string{"member"},
+				ColumnDirections: singleASC,
+				ColumnIDs:        []ColumnID{2},
+				ExtraColumnIDs:   []ColumnID{1},
+			},
+		},
+		NextIndexID:    3,
+		Privileges:     NewPrivilegeDescriptor(security.RootUser, SystemDesiredPrivileges(keys.RoleMembersTableID)),
+		FormatVersion:  InterleavedFormatVersion,
+		NextMutationID: 1,
+	}
+
+	// TableStatisticsTable is the descriptor for the table_statistics table.
+	TableStatisticsTable = TableDescriptor{
+		Name:     "table_statistics",
+		ID:       keys.TableStatisticsTableID,
+		ParentID: 1,
+		Version:  1,
+		Columns: []ColumnDescriptor{
+			{Name: "tableID", ID: 1, Type: colTypeInt},
+			{Name: "statisticID", ID: 2, Type: colTypeInt, DefaultExpr: &uniqueRowIDString},
+			{Name: "name", ID: 3, Type: colTypeString, Nullable: true},
+			{Name: "columnIDs", ID: 4, Type: colTypeIntArray},
+			{Name: "createdAt", ID: 5, Type: colTypeTimestamp, DefaultExpr: &nowString},
+			{Name: "rowCount", ID: 6, Type: colTypeInt},
+			{Name: "distinctCount", ID: 7, Type: colTypeInt},
+			{Name: "nullCount", ID: 8, Type: colTypeInt},
+			{Name: "histogram", ID: 9, Type: colTypeBytes, Nullable: true},
+		},
+		NextColumnID: 10,
+		Families: []ColumnFamilyDescriptor{
+			{
+				Name: "fam_0_tableID_statisticID_name_columnIDs_createdAt_rowCount_distinctCount_nullCount_histogram",
+				ID:   0,
+				ColumnNames: []string{
+					"tableID",
+					"statisticID",
+					"name",
+					"columnIDs",
+					"createdAt",
+					"rowCount",
+					"distinctCount",
+					"nullCount",
+					"histogram",
+				},
+				ColumnIDs: []ColumnID{1, 2, 3, 4, 5, 6, 7, 8, 9},
+			},
+		},
+		NextFamilyID: 1,
+		PrimaryIndex: IndexDescriptor{
+			Name:             "primary",
+			ID:               1,
+			Unique:           true,
+			ColumnNames:      []string{"tableID", "statisticID"},
+			ColumnDirections: []IndexDescriptor_Direction{IndexDescriptor_ASC, IndexDescriptor_ASC},
+			ColumnIDs:        []ColumnID{1, 2},
+		},
+		NextIndexID:    2,
+		Privileges:     NewPrivilegeDescriptor(security.RootUser, SystemDesiredPrivileges(keys.TableStatisticsTableID)),
+		FormatVersion:  InterleavedFormatVersion,
+		NextMutationID: 1,
+	}
 )
 
 // Create the key/value pair for the default zone config entry.
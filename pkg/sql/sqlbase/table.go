@@ -32,6 +32,9 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
 	"github.com/cockroachdb/cockroach/pkg/util/duration"
 	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/cockroach/pkg/util/ipaddr"
+	"github.com/cockroachdb/cockroach/pkg/util/json"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 )
 
 func exprContainsVarsError(context string, Expr parser.Expr) error {
@@ -68,6 +71,29 @@ func SanitizeVarFreeExpr(
 	return typedExpr, nil
 }
 
+// SanitizeDefaultExpr verifies that a DEFAULT expression is valid, has a type
+// compatible with the column type (see TypeCheckDefaultExpr) and contains no
+// variable expressions.
+func SanitizeDefaultExpr(
+	expr parser.Expr, colType parser.Type, searchPath parser.SearchPath,
+) (parser.TypedExpr, error) {
+	if parser.ContainsVars(expr) {
+		return nil, exprContainsVarsError("DEFAULT", expr)
+	}
+	ctx := parser.SemaContext{SearchPath: searchPath}
+	typedExpr, err := TypeCheckDefaultExpr(expr, colType, &ctx)
+	if err != nil {
+		return nil, err
+	}
+	defaultType := typedExpr.ResolvedType()
+	if !colType.Equivalent(defaultType) && typedExpr != parser.DNull {
+		// The DEFAULT expression must match the column type exactly unless it is a
+		// constant NULL value.
+		return nil, incompatibleExprTypeError("DEFAULT", colType, defaultType)
+	}
+	return typedExpr, nil
+}
+
 // MakeColumnDefDescs creates the column descriptor for a column, as well as the
 // index descriptor if the column is a primary key or unique.
 // The search path is used for name resolution for DEFAULT expressions.
@@ -79,17 +105,7 @@ func MakeColumnDefDescs(
 		Nullable: d.Nullable.Nullability != parser.NotNull && !d.PrimaryKey,
 	}
 
-	// Prevent unsupported array types from falling through.
-	// TODO(jordan): This is ugly and a workaround for incompatibility between
-	// CastTargetToDatumType and structured.go's DatumTypeToColumnType. See #15813
-	switch t := d.Type.(type) {
-	case *parser.ArrayColType:
-		if _, ok := t.ParamType.(*parser.IntColType); !ok {
-			return nil, nil, errors.Errorf("arrays of type %s are unsupported", t.ParamType)
-		}
-	}
-
-	// Set Type.Kind and Type.Locale.
+	// Set Type.Kind, Type.Locale and Type.ArrayContents.
 	colDatumType := parser.CastTargetToDatumType(d.Type)
 	col.Type = DatumTypeToColumnType(colDatumType)
 
@@ -134,9 +150,6 @@ func MakeColumnDefDescs(
 	case *parser.CollatedStringColType:
 		col.Type.Width = int32(t.N)
 	case *parser.ArrayColType:
-		if _, ok := t.ParamType.(*parser.IntColType); !ok {
-			return nil, nil, errors.Errorf("arrays of type %s are unsupported", t.ParamType)
-		}
 		for i, e := range t.BoundsExprs {
 			ctx := parser.SemaContext{SearchPath: searchPath}
 			te, err := parser.TypeCheckAndRequire(e, &ctx, parser.TypeInt, "array bounds")
@@ -155,6 +168,9 @@ func MakeColumnDefDescs(
 			return nil, nil, errors.Errorf("vectors of type %s are unsupported", t.ParamType)
 		}
 	case *parser.OidColType:
+	case *parser.JSONColType:
+	case *parser.UUIDColType:
+	case *parser.INetColType:
 	default:
 		return nil, nil, errors.Errorf("unexpected type %T", t)
 	}
@@ -170,9 +186,7 @@ func MakeColumnDefDescs(
 
 	if d.HasDefaultExpr() {
 		// Verify the default expression type is compatible with the column type.
-		if _, err := SanitizeVarFreeExpr(
-			d.DefaultExpr.Expr, colDatumType, "DEFAULT", searchPath,
-		); err != nil {
+		if _, err := SanitizeDefaultExpr(d.DefaultExpr.Expr, colDatumType, searchPath); err != nil {
 			return nil, nil, err
 		}
 		var p parser.Parser
@@ -183,7 +197,7 @@ func MakeColumnDefDescs(
 		}
 
 		// Type check and simplify: this performs constant folding and reduces the expression.
-		typedExpr, err := parser.TypeCheck(d.DefaultExpr.Expr, nil, col.Type.ToDatumType())
+		typedExpr, err := TypeCheckDefaultExpr(d.DefaultExpr.Expr, col.Type.ToDatumType(), nil)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -193,9 +207,12 @@ func MakeColumnDefDescs(
 		// Try to evaluate once. If it is aimed to succeed during a
 		// backfill, it must succeed here too. This tries to ensure that
 		// we don't end up failing the evaluation during the schema change
-		// proper.
-		if _, err := typedExpr.Eval(evalCtx); err != nil {
-			return nil, nil, err
+		// proper. Impure expressions are not evaluated, since evaluating
+		// them can have side effects (e.g. nextval() advances a sequence).
+		if !parser.ContainsImpureFunctions(typedExpr) {
+			if _, err := typedExpr.Eval(evalCtx); err != nil {
+				return nil, nil, err
+			}
 		}
 		d.DefaultExpr.Expr = typedExpr
 
@@ -385,7 +402,9 @@ func appendEncDatumsToKey(
 
 // MakeKeyFromEncDatums creates a key by concatenating keyPrefix with the
 // encodings of the given EncDatum values. The values correspond to
-// index.ColumnIDs.
+// index.ColumnIDs; fewer values than index columns can be passed, in which case
+// the result is a key prefix which covers all the index keys that start with
+// the given values.
 //
 // If a table or index is interleaved, `encoding.encodedNullDesc` is used in
 // place of the family id (a varint) to signal the next component of the key.
@@ -402,9 +421,10 @@ func MakeKeyFromEncDatums(
 	alloc *DatumAlloc,
 ) (roachpb.Key, error) {
 	dirs := index.ColumnDirections
-	if len(values) != len(dirs) {
+	if len(values) > len(dirs) {
 		return nil, errors.Errorf("%d values, %d directions", len(values), len(dirs))
 	}
+	dirs = dirs[:len(values)]
 	// We know we will append to the key which will cause the capacity to grow
 	// so make it bigger from the get-go.
 	key := make(roachpb.Key, len(keyPrefix), len(keyPrefix)*2)
@@ -419,6 +439,10 @@ func MakeKeyFromEncDatums(
 			}
 
 			length := int(ancestor.SharedPrefixLen)
+			if length > len(values) {
+				// We ran out of values; the key is a prefix of an ancestor key.
+				return appendEncDatumsToKey(key, values, dirs, alloc)
+			}
 			var err error
 			key, err = appendEncDatumsToKey(key, values[:length], dirs[:length], alloc)
 			if err != nil {
@@ -557,6 +581,25 @@ func EncodeTableKey(b []byte, val parser.Datum, dir encoding.Direction) ([]byte,
 			return encoding.EncodeVarintAscending(b, int64(t.DInt)), nil
 		}
 		return encoding.EncodeVarintDescending(b, int64(t.DInt)), nil
+	case *parser.DJSON:
+		// JSON keys only need to group equal values together (they are never
+		// part of an ordered index), so they only have an ascending encoding.
+		// The canonical representation makes numerically equal values (e.g. 1
+		// and 1.0) encode to the same key.
+		if dir == encoding.Ascending {
+			return encoding.EncodeJSONAscending(b, []byte(json.CanonicalString(t.JSON))), nil
+		}
+		return nil, errors.Errorf("unable to encode JSON table key in descending order")
+	case *parser.DUuid:
+		if dir == encoding.Ascending {
+			return encoding.EncodeBytesAscending(b, t.GetBytes()), nil
+		}
+		return encoding.EncodeBytesDescending(b, t.GetBytes()), nil
+	case *parser.DIPAddr:
+		if dir == encoding.Ascending {
+			return encoding.EncodeIPAddrAscending(b, t.IPAddr), nil
+		}
+		return encoding.EncodeIPAddrDescending(b, t.IPAddr), nil
 	}
 	return nil, errors.Errorf("unable to encode table key: %T", val)
 }
@@ -594,10 +637,60 @@ func EncodeTableValue(appendTo []byte, colID ColumnID, val parser.Datum) ([]byte
 		return encoding.EncodeBytesValue(appendTo, uint32(colID), []byte(t.Contents)), nil
 	case *parser.DOid:
 		return encoding.EncodeIntValue(appendTo, uint32(colID), int64(t.DInt)), nil
+	case *parser.DJSON:
+		return encoding.EncodeJSONValue(appendTo, uint32(colID), []byte(t.JSON.String())), nil
+	case *parser.DUuid:
+		return encoding.EncodeUUIDValue(appendTo, uint32(colID), t.UUID), nil
+	case *parser.DIPAddr:
+		return encoding.EncodeIPAddrValue(appendTo, uint32(colID), t.IPAddr), nil
+	case *parser.DArray:
+		a, err := encodeArray(t, nil)
+		if err != nil {
+			return nil, err
+		}
+		return encoding.EncodeArrayValue(appendTo, uint32(colID), a), nil
 	}
 	return nil, errors.Errorf("unable to encode table value: %T", val)
 }
 
+// encodeArray produces the value encoding of an array, without the value
+// header, appending it to scratch. The encoding is the number of elements
+// followed by the value encoding of each element.
+func encodeArray(d *parser.DArray, scratch []byte) ([]byte, error) {
+	scratch = encoding.EncodeNonsortingUvarint(scratch, uint64(d.Len()))
+	for _, e := range d.Array {
+		var err error
+		if scratch, err = EncodeTableValue(scratch, ColumnID(encoding.NoColumnID), e); err != nil {
+			return nil, err
+		}
+	}
+	return scratch, nil
+}
+
+// decodeArray decodes the value encoding of an array produced by encodeArray
+// into a DArray of the given element type.
+func decodeArray(a *DatumAlloc, elementType parser.Type, b []byte) (parser.Datum, error) {
+	b, _, n, err := encoding.DecodeNonsortingUvarint(b)
+	if err != nil {
+		return nil, err
+	}
+	result := parser.NewDArray(elementType)
+	result.Array = make(parser.Datums, 0, n)
+	for i := uint64(0); i < n; i++ {
+		var d parser.Datum
+		if d, b, err = DecodeTableValue(a, elementType, b); err != nil {
+			return nil, err
+		}
+		if err := result.Append(d); err != nil {
+			return nil, err
+		}
+	}
+	if len(b) != 0 {
+		return nil, errors.Errorf("%d trailing bytes in encoded array", len(b))
+	}
+	return result, nil
+}
+
 // MakeEncodedKeyVals returns a slice of EncDatums with the correct types for
 // the given columns.
 func MakeEncodedKeyVals(desc *TableDescriptor, columnIDs []ColumnID) ([]EncDatum, error) {
@@ -890,6 +983,8 @@ type DatumAlloc struct {
 	dtimestampAlloc   []parser.DTimestamp
 	dtimestampTzAlloc []parser.DTimestampTZ
 	dintervalAlloc    []parser.DInterval
+	duuidAlloc        []parser.DUuid
+	dipnetAlloc       []parser.DIPAddr
 	doidAlloc         []parser.DOid
 	env               parser.CollationEnvironment
 }
@@ -1007,6 +1102,30 @@ func (a *DatumAlloc) NewDInterval(v parser.DInterval) *parser.DInterval {
 	return r
 }
 
+// NewDUuid allocates a DUuid.
+func (a *DatumAlloc) NewDUuid(v parser.DUuid) *parser.DUuid {
+	buf := &a.duuidAlloc
+	if len(*buf) == 0 {
+		*buf = make([]parser.DUuid, datumAllocSize)
+	}
+	r := &(*buf)[0]
+	*r = v
+	*buf = (*buf)[1:]
+	return r
+}
+
+// NewDIPAddr allocates a DIPAddr.
+func (a *DatumAlloc) NewDIPAddr(v parser.DIPAddr) *parser.DIPAddr {
+	buf := &a.dipnetAlloc
+	if len(*buf) == 0 {
+		*buf = make([]parser.DIPAddr, datumAllocSize)
+	}
+	r := &(*buf)[0]
+	*r = v
+	*buf = (*buf)[1:]
+	return r
+}
+
 // NewDOid allocates a DOid.
 func (a *DatumAlloc) NewDOid(v parser.DOid) parser.Datum {
 	buf := &a.doidAlloc
@@ -1132,6 +1251,40 @@ func DecodeTableKey(
 			rkey, i, err = encoding.DecodeVarintDescending(key)
 		}
 		return a.NewDOid(parser.MakeDOid(parser.DInt(i))), rkey, err
+	case parser.TypeJSON:
+		if dir != encoding.Ascending {
+			return nil, nil, errors.Errorf("unable to decode JSON table key in descending order")
+		}
+		var r []byte
+		rkey, r, err = encoding.DecodeJSONAscending(key, nil)
+		if err != nil {
+			return nil, nil, err
+		}
+		d, err := parser.ParseDJSON(string(r))
+		if err != nil {
+			return nil, nil, err
+		}
+		return d, rkey, nil
+	case parser.TypeUUID:
+		var r []byte
+		if dir == encoding.Ascending {
+			rkey, r, err = encoding.DecodeBytesAscending(key, nil)
+		} else {
+			rkey, r, err = encoding.DecodeBytesDescending(key, nil)
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		u, err := uuid.FromBytes(r)
+		return a.NewDUuid(parser.DUuid{UUID: u}), rkey, err
+	case parser.TypeINet:
+		var ipAddr ipaddr.IPAddr
+		if dir == encoding.Ascending {
+			rkey, ipAddr, err = encoding.DecodeIPAddrAscending(key)
+		} else {
+			rkey, ipAddr, err = encoding.DecodeIPAddrDescending(key)
+		}
+		return a.NewDIPAddr(parser.DIPAddr{IPAddr: ipAddr}), rkey, err
 	default:
 		if _, ok := valType.(parser.TCollatedString); ok {
 			var r string
@@ -1206,11 +1359,39 @@ func DecodeTableValue(a *DatumAlloc, valType parser.Type, b []byte) (parser.Datu
 		var i int64
 		b, i, err = encoding.DecodeIntValue(b)
 		return a.NewDOid(parser.MakeDOid(parser.DInt(i))), b, err
+	case parser.TypeJSON:
+		var data []byte
+		b, data, err = encoding.DecodeJSONValue(b)
+		if err != nil {
+			return nil, b, err
+		}
+		d, err := parser.ParseDJSON(string(data))
+		if err != nil {
+			return nil, b, err
+		}
+		return d, b, nil
+	case parser.TypeUUID:
+		var u uuid.UUID
+		b, u, err = encoding.DecodeUUIDValue(b)
+		return a.NewDUuid(parser.DUuid{UUID: u}), b, err
+	case parser.TypeINet:
+		var ipAddr ipaddr.IPAddr
+		b, ipAddr, err = encoding.DecodeIPAddrValue(b)
+		return a.NewDIPAddr(parser.DIPAddr{IPAddr: ipAddr}), b, err
 	default:
-		if typ, ok := valType.(parser.TCollatedString); ok {
+		switch typ := valType.(type) {
+		case parser.TCollatedString:
 			var data []byte
 			b, data, err = encoding.DecodeBytesValue(b)
 			return parser.NewDCollatedString(string(data), typ.Locale, &a.env), b, err
+		case parser.TArray:
+			var data []byte
+			b, data, err = encoding.DecodeArrayValue(b)
+			if err != nil {
+				return nil, b, err
+			}
+			d, err := decodeArray(a, typ.Typ, data)
+			return d, b, err
 		}
 		return nil, nil, errors.Errorf("TODO(pmattis): decoded index value: %s", valType)
 	}
@@ -1238,26 +1419,34 @@ func (a byID) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
 func (a byID) Less(i, j int) bool { return a[i].id < a[j].id }
 
 // EncodeSecondaryIndex encodes key/values for a secondary index. colMap maps
-// ColumnIDs to indices in `values`.
+// ColumnIDs to indices in `values`. A forward index always has a single entry
+// per row, while an inverted index has one entry per inverted index key of
+// the indexed value (and none if the value is NULL).
 func EncodeSecondaryIndex(
 	tableDesc *TableDescriptor,
 	secondaryIndex *IndexDescriptor,
 	colMap map[ColumnID]int,
 	values []parser.Datum,
-) (IndexEntry, error) {
+) ([]IndexEntry, error) {
 	secondaryIndexKeyPrefix := MakeIndexKeyPrefix(tableDesc, secondaryIndex.ID)
-	secondaryIndexKey, containsNull, err := EncodeIndexKey(
-		tableDesc, secondaryIndex, colMap, values, secondaryIndexKeyPrefix)
-	if err != nil {
-		return IndexEntry{}, err
-	}
 
 	// Add the extra columns - they are encoded ascendingly which is done by
 	// passing nil for the encoding directions.
 	extraKey, _, err := EncodeColumns(secondaryIndex.ExtraColumnIDs, nil,
 		colMap, values, nil)
 	if err != nil {
-		return IndexEntry{}, err
+		return nil, err
+	}
+
+	if secondaryIndex.Type == IndexDescriptor_INVERTED {
+		return encodeInvertedIndexEntries(secondaryIndex, colMap, values,
+			secondaryIndexKeyPrefix, extraKey)
+	}
+
+	secondaryIndexKey, containsNull, err := EncodeIndexKey(
+		tableDesc, secondaryIndex, colMap, values, secondaryIndexKeyPrefix)
+	if err != nil {
+		return nil, err
 	}
 
 	entry := IndexEntry{Key: secondaryIndexKey}
@@ -1309,33 +1498,67 @@ func EncodeSecondaryIndex(
 		lastColID = col.id
 		entryValue, err = EncodeTableValue(entryValue, colIDDiff, val)
 		if err != nil {
-			return IndexEntry{}, err
+			return nil, err
 		}
 	}
 	entry.Value.SetBytes(entryValue)
 
-	return entry, nil
+	return []IndexEntry{entry}, nil
+}
+
+// encodeInvertedIndexEntries encodes the entries of an inverted index, which
+// indexes a single JSON column. Each entry's key is made of keyPrefix, one of
+// the inverted index keys of the JSON value and the extra columns in
+// extraKey. The values are empty since inverted indexes can't be unique and
+// don't store any column.
+func encodeInvertedIndexEntries(
+	index *IndexDescriptor,
+	colMap map[ColumnID]int,
+	values []parser.Datum,
+	keyPrefix []byte,
+	extraKey []byte,
+) ([]IndexEntry, error) {
+	if len(index.ColumnIDs) != 1 {
+		return nil, errors.Errorf("inverted index %q must have a single column", index.Name)
+	}
+	i, ok := colMap[index.ColumnIDs[0]]
+	if !ok || values[i] == parser.DNull {
+		return nil, nil
+	}
+	j, ok := parser.UnwrapDatum(values[i]).(*parser.DJSON)
+	if !ok {
+		return nil, errors.Errorf("unable to encode inverted index key: %T", values[i])
+	}
+
+	invertedKeys := json.EncodeInvertedIndexKeys(keyPrefix, j.JSON)
+	entries := make([]IndexEntry, len(invertedKeys))
+	for k, key := range invertedKeys {
+		entries[k].Key = keys.MakeRowSentinelKey(append(key, extraKey...))
+		// The zero value for an index-key is a 0-length bytes value.
+		entries[k].Value.SetBytes([]byte{})
+	}
+	return entries, nil
 }
 
 // EncodeSecondaryIndexes encodes key/values for the secondary indexes. colMap
-// maps ColumnIDs to indices in `values`. secondaryIndexEntries is the return
-// value (passed as a parameter so the caller can reuse between rows) and is
-// expected to be the same length as indexes.
+// maps ColumnIDs to indices in `values`. The entries of all the indexes are
+// appended to secondaryIndexEntries (passed as a parameter so the caller can
+// reuse it between rows), which is returned.
 func EncodeSecondaryIndexes(
 	tableDesc *TableDescriptor,
 	indexes []IndexDescriptor,
 	colMap map[ColumnID]int,
 	values []parser.Datum,
 	secondaryIndexEntries []IndexEntry,
-) error {
+) ([]IndexEntry, error) {
 	for i := range indexes {
-		var err error
-		secondaryIndexEntries[i], err = EncodeSecondaryIndex(tableDesc, &indexes[i], colMap, values)
+		entries, err := EncodeSecondaryIndex(tableDesc, &indexes[i], colMap, values)
 		if err != nil {
-			return err
+			return nil, err
 		}
+		secondaryIndexEntries = append(secondaryIndexEntries, entries...)
 	}
-	return nil
+	return secondaryIndexEntries, nil
 }
 
 // CheckColumnType verifies that a given value is compatible
@@ -1440,6 +1663,33 @@ func MarshalColumnValue(col ColumnDescriptor, val parser.Datum) (roachpb.Value,
 			r.SetInt(int64(v.DInt))
 			return r, nil
 		}
+	case ColumnType_JSON:
+		if v, ok := val.(*parser.DJSON); ok {
+			r.SetString(v.JSON.String())
+			return r, nil
+		}
+	case ColumnType_UUID:
+		if v, ok := val.(*parser.DUuid); ok {
+			r.SetBytes(v.GetBytes())
+			return r, nil
+		}
+	case ColumnType_INET:
+		if v, ok := val.(*parser.DIPAddr); ok {
+			r.SetBytes(v.ToBuffer(nil))
+			return r, nil
+		}
+	case ColumnType_ARRAY:
+		if v, ok := val.(*parser.DArray); ok {
+			if !v.ResolvedType().Equivalent(col.Type.ToDatumType()) {
+				break
+			}
+			b, err := encodeArray(v, nil)
+			if err != nil {
+				return r, err
+			}
+			r.SetBytes(b)
+			return r, nil
+		}
 	default:
 		return r, errors.Errorf("unsupported column type: %s", col.Type.Kind)
 	}
@@ -1537,6 +1787,42 @@ func UnmarshalColumnValue(
 			return nil, err
 		}
 		return a.NewDOid(parser.MakeDOid(parser.DInt(v))), nil
+	case ColumnType_JSON:
+		v, err := value.GetBytes()
+		if err != nil {
+			return nil, err
+		}
+		d, err := parser.ParseDJSON(string(v))
+		if err != nil {
+			return nil, err
+		}
+		return d, nil
+	case ColumnType_UUID:
+		v, err := value.GetBytes()
+		if err != nil {
+			return nil, err
+		}
+		u, err := uuid.FromBytes(v)
+		if err != nil {
+			return nil, err
+		}
+		return a.NewDUuid(parser.DUuid{UUID: u}), nil
+	case ColumnType_INET:
+		v, err := value.GetBytes()
+		if err != nil {
+			return nil, err
+		}
+		ipAddr, _, err := ipaddr.FromBuffer(v)
+		if err != nil {
+			return nil, err
+		}
+		return a.NewDIPAddr(parser.DIPAddr{IPAddr: ipAddr}), nil
+	case ColumnType_ARRAY:
+		v, err := value.GetBytes()
+		if err != nil {
+			return nil, err
+		}
+		return decodeArray(a, typ.elementColumnType().ToDatumType(), v)
 	default:
 		return nil, errors.Errorf("unsupported column type: %s", typ.Kind)
 	}
@@ -31,6 +31,9 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
 	"github.com/cockroachdb/cockroach/pkg/util/duration"
+	"github.com/cockroachdb/cockroach/pkg/util/ipaddr"
+	"github.com/cockroachdb/cockroach/pkg/util/json"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 )
 
 // This file contains utility functions for tests (in other packages).
@@ -135,16 +138,72 @@ func RandDatum(rng *rand.Rand, typ ColumnType, null bool) parser.Datum {
 		return parser.NewDName(string(p))
 	case ColumnType_OID:
 		return parser.NewDOid(parser.DInt(rng.Int63()))
+	case ColumnType_JSON:
+		return parser.NewDJSON(randJSON(rng, 2))
+	case ColumnType_UUID:
+		return parser.NewDUuid(parser.DUuid{UUID: *uuid.NewPopulatedUUID(rng)})
+	case ColumnType_INET:
+		return parser.NewDIPAddr(parser.DIPAddr{IPAddr: ipaddr.RandIPAddr(rng)})
 	case ColumnType_NULL:
 		return parser.DNull
-	case ColumnType_INT_ARRAY, ColumnType_INT2VECTOR:
-		// TODO(cuongdo): we don't support for persistence of arrays or vectors yet
+	case ColumnType_ARRAY:
+		elemTyp := typ.elementColumnType()
+		arr := parser.NewDArray(elemTyp.ToDatumType())
+		for i := rng.Intn(5); i > 0; i-- {
+			if err := arr.Append(RandDatum(rng, *elemTyp, true)); err != nil {
+				panic(err)
+			}
+		}
+		return arr
+	case ColumnType_INT2VECTOR:
+		// TODO(cuongdo): we don't support for persistence of vectors yet
 		return parser.DNull
 	default:
 		panic(fmt.Sprintf("invalid type %s", typ.String()))
 	}
 }
 
+// randJSON generates a random JSON document, nesting arrays and objects at
+// most depth levels deep.
+func randJSON(rng *rand.Rand, depth int) json.JSON {
+	n := 5
+	if depth > 0 {
+		n = 7
+	}
+	switch rng.Intn(n) {
+	case 0:
+		return json.NullJSONValue
+	case 1:
+		return json.FromBool(rng.Intn(2) == 1)
+	case 2:
+		return json.FromInt(rng.Int63n(1000) - 500)
+	case 3:
+		j, err := json.FromFloat64(rng.NormFloat64())
+		if err != nil {
+			panic(err)
+		}
+		return j
+	case 4:
+		p := make([]byte, rng.Intn(5))
+		for i := range p {
+			p[i] = byte('a' + rng.Intn(26))
+		}
+		return json.FromString(string(p))
+	case 5:
+		elems := make([]json.JSON, rng.Intn(4))
+		for i := range elems {
+			elems[i] = randJSON(rng, depth-1)
+		}
+		return json.FromArray(elems)
+	default:
+		m := make(map[string]json.JSON)
+		for i := rng.Intn(4); i > 0; i-- {
+			m[string('a'+rune(rng.Intn(26)))] = randJSON(rng, depth-1)
+		}
+		return json.FromMap(m)
+	}
+}
+
 var (
 	columnKinds      []ColumnType_Kind
 	collationLocales = [...]string{"da", "de", "en"}
@@ -152,6 +211,11 @@ var (
 
 func init() {
 	for k := range ColumnType_Kind_name {
+		// Arrays don't have a key encoding, so they can't be used in places
+		// where RandColumnType is used.
+		if ColumnType_Kind(k) == ColumnType_ARRAY {
+			continue
+		}
 		columnKinds = append(columnKinds, ColumnType_Kind(k))
 	}
 }
@@ -0,0 +1,183 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package stats
+
+import (
+	"sort"
+
+	"github.com/pkg/errors"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+)
+
+// EquiDepthHistogram creates a histogram where each bucket contains roughly
+// the same number of samples (though it can vary when a boundary value has
+// high frequency).
+//
+// numRows is the total number of (non-NULL) rows of the table; the counts in
+// the buckets are scaled from the samples to numRows. The samples must not
+// contain NULLs, and are sorted in place.
+func EquiDepthHistogram(
+	evalCtx *parser.EvalContext, samples parser.Datums, numRows int64, maxBuckets int,
+) (HistogramData, error) {
+	numSamples := len(samples)
+	if maxBuckets < 2 {
+		return HistogramData{}, errors.Errorf("histogram requires at least two buckets")
+	}
+	if numSamples == 0 {
+		return HistogramData{}, nil
+	}
+	if numRows < int64(numSamples) {
+		return HistogramData{}, errors.Errorf("more samples than rows")
+	}
+	for _, d := range samples {
+		if d == parser.DNull {
+			return HistogramData{}, errors.Errorf("NULL values not allowed in histogram")
+		}
+	}
+	sort.Slice(samples, func(i, j int) bool {
+		return samples[i].Compare(evalCtx, samples[j]) < 0
+	})
+	numBuckets := maxBuckets
+	if maxBuckets > numSamples {
+		numBuckets = numSamples
+	}
+	h := HistogramData{
+		Buckets: make([]HistogramData_Bucket, 0, numBuckets),
+	}
+	// i keeps track of the current sample and advances as we form buckets.
+	for i, b := 0, 0; b < numBuckets && i < numSamples; b++ {
+		// num is the number of samples in this bucket.
+		num := (numSamples - i) / (numBuckets - b)
+		if num < 1 {
+			num = 1
+		}
+		upper := samples[i+num-1]
+		// numLess is the number of samples less than upper (in this bucket).
+		numLess := 0
+		for ; numLess < num-1; numLess++ {
+			if samples[i+numLess].Compare(evalCtx, upper) == 0 {
+				break
+			}
+		}
+		// Advance the boundary of the bucket to cover all samples equal to
+		// upper.
+		for ; i+num < numSamples; num++ {
+			if samples[i+num].Compare(evalCtx, upper) != 0 {
+				break
+			}
+		}
+		encoded, err := sqlbase.EncodeTableKey(nil, upper, encoding.Ascending)
+		if err != nil {
+			return HistogramData{}, err
+		}
+		h.Buckets = append(h.Buckets, HistogramData_Bucket{
+			NumEq:      int64(num-numLess) * numRows / int64(numSamples),
+			NumRange:   int64(numLess) * numRows / int64(numSamples),
+			UpperBound: encoded,
+		})
+		i += num
+	}
+	return h, nil
+}
+
+// Histogram is a HistogramData with the upper bounds of the buckets decoded,
+// which is used to estimate the number of rows matching constraints on the
+// column.
+type Histogram struct {
+	buckets []bucket
+}
+
+type bucket struct {
+	numEq      int64
+	numRange   int64
+	upperBound parser.Datum
+}
+
+// DecodeHistogram decodes the upper bounds of the buckets of a histogram on a
+// column of type typ.
+func DecodeHistogram(
+	a *sqlbase.DatumAlloc, typ parser.Type, data *HistogramData,
+) (*Histogram, error) {
+	h := &Histogram{buckets: make([]bucket, len(data.Buckets))}
+	for i, b := range data.Buckets {
+		d, _, err := sqlbase.DecodeTableKey(a, typ, b.UpperBound, encoding.Ascending)
+		if err != nil {
+			return nil, err
+		}
+		h.buckets[i] = bucket{numEq: b.NumEq, numRange: b.NumRange, upperBound: d}
+	}
+	return h, nil
+}
+
+// TotalRows returns the number of rows represented by the histogram.
+func (h *Histogram) TotalRows() int64 {
+	var n int64
+	for _, b := range h.buckets {
+		n += b.numEq + b.numRange
+	}
+	return n
+}
+
+// EqualRows estimates the number of rows equal to d. The second return value
+// is false if d is not a bucket boundary, in which case the histogram does not
+// have an accurate estimate; the caller should fall back on the distinct
+// count.
+func (h *Histogram) EqualRows(evalCtx *parser.EvalContext, d parser.Datum) (float64, bool) {
+	i := sort.Search(len(h.buckets), func(i int) bool {
+		return h.buckets[i].upperBound.Compare(evalCtx, d) >= 0
+	})
+	if i == len(h.buckets) {
+		// The value is larger than all the values in the sample.
+		return 0, true
+	}
+	if h.buckets[i].upperBound.Compare(evalCtx, d) == 0 {
+		return float64(h.buckets[i].numEq), true
+	}
+	return 0, false
+}
+
+// RangeRows estimates the number of rows in the range [lower, upper]. A nil
+// bound means the range is unbounded on that side. The values inside a bucket
+// are not known, so buckets which partially overlap the range contribute half
+// of their rows.
+func (h *Histogram) RangeRows(evalCtx *parser.EvalContext, lower, upper parser.Datum) float64 {
+	var rows float64
+	var prevUpper parser.Datum
+	for _, b := range h.buckets {
+		// The values equal to the upper bound of the bucket.
+		if (lower == nil || lower.Compare(evalCtx, b.upperBound) <= 0) &&
+			(upper == nil || b.upperBound.Compare(evalCtx, upper) <= 0) {
+			rows += float64(b.numEq)
+		}
+		// The values in the range (prevUpper, b.upperBound).
+		switch {
+		case upper != nil && prevUpper != nil && upper.Compare(evalCtx, prevUpper) <= 0:
+			// The bucket is after the range.
+		case lower != nil && lower.Compare(evalCtx, b.upperBound) >= 0:
+			// The bucket is before the range.
+		case (lower == nil || (prevUpper != nil && lower.Compare(evalCtx, prevUpper) <= 0)) &&
+			(upper == nil || b.upperBound.Compare(evalCtx, upper) <= 0):
+			// The bucket is contained in the range.
+			rows += float64(b.numRange)
+		default:
+			rows += float64(b.numRange) / 2
+		}
+		prevUpper = b.upperBound
+	}
+	return rows
+}
@@ -0,0 +1,185 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package stats
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+)
+
+type expBucket struct {
+	upper    int
+	numEq    int64
+	numRange int64
+}
+
+func TestEquiDepthHistogram(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	testCases := []struct {
+		samples    []int
+		numRows    int64
+		maxBuckets int
+		buckets    []expBucket
+	}{
+		{
+			samples:    []int{1, 2, 4, 5, 5, 9},
+			numRows:    6,
+			maxBuckets: 10,
+			buckets: []expBucket{
+				{upper: 1, numEq: 1, numRange: 0},
+				{upper: 2, numEq: 1, numRange: 0},
+				{upper: 4, numEq: 1, numRange: 0},
+				{upper: 5, numEq: 2, numRange: 0},
+				{upper: 9, numEq: 1, numRange: 0},
+			},
+		},
+		{
+			// The samples are scaled to the number of rows.
+			samples:    []int{10, 9, 8, 7, 6, 5, 4, 3, 2, 1},
+			numRows:    100,
+			maxBuckets: 5,
+			buckets: []expBucket{
+				{upper: 2, numEq: 10, numRange: 10},
+				{upper: 4, numEq: 10, numRange: 10},
+				{upper: 6, numEq: 10, numRange: 10},
+				{upper: 8, numEq: 10, numRange: 10},
+				{upper: 10, numEq: 10, numRange: 10},
+			},
+		},
+		{
+			// Buckets are extended to include all the values equal to their
+			// upper bound.
+			samples:    []int{4, 1, 1, 2, 3, 1, 4, 2, 1, 4},
+			numRows:    10,
+			maxBuckets: 3,
+			buckets: []expBucket{
+				{upper: 1, numEq: 4, numRange: 0},
+				{upper: 3, numEq: 1, numRange: 2},
+				{upper: 4, numEq: 3, numRange: 0},
+			},
+		},
+		{
+			samples:    []int{},
+			numRows:    0,
+			maxBuckets: 10,
+			buckets:    []expBucket{},
+		},
+	}
+
+	evalCtx := &parser.EvalContext{}
+	for i, tc := range testCases {
+		samples := make(parser.Datums, len(tc.samples))
+		for j := range tc.samples {
+			samples[j] = parser.NewDInt(parser.DInt(tc.samples[j]))
+		}
+		h, err := EquiDepthHistogram(evalCtx, samples, tc.numRows, tc.maxBuckets)
+		if err != nil {
+			t.Fatalf("%d: %s", i, err)
+		}
+		if len(h.Buckets) != len(tc.buckets) {
+			t.Fatalf("%d: expected %d buckets, got %d: %v", i, len(tc.buckets), len(h.Buckets), h)
+		}
+		for j, b := range h.Buckets {
+			_, val, err := encoding.DecodeVarintAscending(b.UpperBound)
+			if err != nil {
+				t.Fatal(err)
+			}
+			exp := tc.buckets[j]
+			if int(val) != exp.upper || b.NumEq != exp.numEq || b.NumRange != exp.numRange {
+				t.Errorf("%d: bucket %d: expected %+v, got upper:%d numEq:%d numRange:%d",
+					i, j, exp, val, b.NumEq, b.NumRange)
+			}
+		}
+	}
+
+	t.Run("errors", func(t *testing.T) {
+		samples := parser.Datums{parser.NewDInt(1), parser.DNull}
+		if _, err := EquiDepthHistogram(evalCtx, samples, 2, 10); err == nil {
+			t.Error("expected error with NULL sample")
+		}
+		samples = parser.Datums{parser.NewDInt(1), parser.NewDInt(2)}
+		if _, err := EquiDepthHistogram(evalCtx, samples, 1, 10); err == nil {
+			t.Error("expected error with more samples than rows")
+		}
+		if _, err := EquiDepthHistogram(evalCtx, samples, 2, 1); err == nil {
+			t.Error("expected error with a single bucket")
+		}
+	})
+}
+
+func TestHistogramEstimates(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	evalCtx := &parser.EvalContext{}
+
+	var samples parser.Datums
+	for _, v := range []int{4, 1, 1, 2, 3, 1, 4, 2, 1, 4} {
+		samples = append(samples, parser.NewDInt(parser.DInt(v)))
+	}
+	data, err := EquiDepthHistogram(evalCtx, samples, 10, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h, err := DecodeHistogram(&sqlbase.DatumAlloc{}, parser.TypeInt, &data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rows := h.TotalRows(); rows != 10 {
+		t.Errorf("expected 10 rows, got %d", rows)
+	}
+
+	d := func(v int) parser.Datum {
+		return parser.NewDInt(parser.DInt(v))
+	}
+
+	eqTestCases := []struct {
+		val  int
+		rows float64
+		ok   bool
+	}{
+		{val: 0, rows: 0, ok: false},
+		{val: 1, rows: 4, ok: true},
+		{val: 2, rows: 0, ok: false},
+		{val: 4, rows: 3, ok: true},
+		{val: 5, rows: 0, ok: true},
+	}
+	for _, tc := range eqTestCases {
+		rows, ok := h.EqualRows(evalCtx, d(tc.val))
+		if rows != tc.rows || ok != tc.ok {
+			t.Errorf("EqualRows(%d): expected %v, %t; got %v, %t", tc.val, tc.rows, tc.ok, rows, ok)
+		}
+	}
+
+	rangeTestCases := []struct {
+		lower, upper parser.Datum
+		rows         float64
+	}{
+		{lower: nil, upper: nil, rows: 10},
+		{lower: nil, upper: d(1), rows: 4},
+		{lower: d(2), upper: d(3), rows: 2},
+		{lower: d(4), upper: nil, rows: 3},
+		{lower: d(5), upper: nil, rows: 0},
+	}
+	for _, tc := range rangeTestCases {
+		if rows := h.RangeRows(evalCtx, tc.lower, tc.upper); rows != tc.rows {
+			t.Errorf("RangeRows(%v, %v): expected %v, got %v", tc.lower, tc.upper, tc.rows, rows)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package stats
+
+import (
+	"container/heap"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+)
+
+// SampledRow is a row that was sampled.
+type SampledRow struct {
+	Row  sqlbase.EncDatumRow
+	Rank uint64
+}
+
+// SampleReservoir implements reservoir sampling using random sort. Each
+// row is assigned a rank (which should be a uniformly generated random value),
+// and rows with the smallest K ranks are retained.
+//
+// This is implemented as a max-heap of the smallest K ranks; each row can
+// replace the row with the maximum rank. Because the ranks are kept with the
+// rows, the samples of several reservoirs (e.g. from different nodes) can be
+// merged into a single reservoir which is equivalent to a reservoir that
+// sampled all the rows.
+type SampleReservoir struct {
+	size    int
+	samples []SampledRow
+	ra      sqlbase.EncDatumRowAlloc
+}
+
+var _ heap.Interface = &SampleReservoir{}
+
+// Init initializes a SampleReservoir which retains at most size rows.
+func (sr *SampleReservoir) Init(size int) {
+	sr.size = size
+	sr.samples = make([]SampledRow, 0, size)
+}
+
+// Len is part of heap.Interface.
+func (sr *SampleReservoir) Len() int {
+	return len(sr.samples)
+}
+
+// Less is part of heap.Interface. The ranks are compared in reverse so that
+// the heap keeps the row with the maximum rank at the root.
+func (sr *SampleReservoir) Less(i, j int) bool {
+	return sr.samples[i].Rank > sr.samples[j].Rank
+}
+
+// Swap is part of heap.Interface.
+func (sr *SampleReservoir) Swap(i, j int) {
+	sr.samples[i], sr.samples[j] = sr.samples[j], sr.samples[i]
+}
+
+// Push is part of heap.Interface, but we're not using it.
+func (sr *SampleReservoir) Push(x interface{}) { panic("unimplemented") }
+
+// Pop is part of heap.Interface, but we're not using it.
+func (sr *SampleReservoir) Pop() interface{} { panic("unimplemented") }
+
+// SampleRow looks at a row and either drops it or adds it to the reservoir.
+// The row is copied if it is retained.
+func (sr *SampleReservoir) SampleRow(row sqlbase.EncDatumRow, rank uint64) {
+	if len(sr.samples) < sr.size {
+		sr.samples = append(sr.samples, SampledRow{Row: sr.ra.CopyRow(row), Rank: rank})
+		if len(sr.samples) == sr.size {
+			heap.Init(sr)
+		}
+		return
+	}
+	// Replace the max rank if ours is smaller.
+	if sr.size > 0 && rank < sr.samples[0].Rank {
+		sr.samples[0] = SampledRow{Row: sr.ra.CopyRow(row), Rank: rank}
+		heap.Fix(sr, 0)
+	}
+}
+
+// Get returns the sampled rows, in no particular order.
+func (sr *SampleReservoir) Get() []SampledRow {
+	return sr.samples
+}
@@ -0,0 +1,71 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package stats
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/randutil"
+)
+
+func TestSampleReservoir(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	rng, _ := randutil.NewPseudoRand()
+	intType := sqlbase.ColumnType{Kind: sqlbase.ColumnType_INT}
+	for _, n := range []int{10, 100, 1000, 10000} {
+		for _, k := range []int{1, 5, 10, 100} {
+			t.Run(fmt.Sprintf("%d/%d", n, k), func(t *testing.T) {
+				// Sample the values 0 to n-1, with the value as the rank: the
+				// sample must consist of the values with the smallest k ranks.
+				perm := rng.Perm(n)
+				var sr SampleReservoir
+				sr.Init(k)
+				for _, v := range perm {
+					row := sqlbase.EncDatumRow{sqlbase.DatumToEncDatum(intType, parser.NewDInt(parser.DInt(v)))}
+					sr.SampleRow(row, uint64(v))
+				}
+				samples := sr.Get()
+				sampledValues := make([]int, len(samples))
+				for i, s := range samples {
+					v := int(*s.Row[0].Datum.(*parser.DInt))
+					if uint64(v) != s.Rank {
+						t.Fatalf("sampled row %d has rank %d", v, s.Rank)
+					}
+					sampledValues[i] = v
+				}
+				sort.Ints(sampledValues)
+
+				expected := k
+				if n < k {
+					expected = n
+				}
+				if len(sampledValues) != expected {
+					t.Fatalf("expected %d samples, got %v", expected, sampledValues)
+				}
+				for i, v := range sampledValues {
+					if v != i {
+						t.Fatalf("expected the values with the smallest ranks, got %v", sampledValues)
+					}
+				}
+			})
+		}
+	}
+}
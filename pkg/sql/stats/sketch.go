@@ -0,0 +1,132 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package stats
+
+import (
+	"math"
+
+	"github.com/pkg/errors"
+)
+
+// sketchPrecision is the number of bits of the hash used to select a
+// register. The sketch uses 2^sketchPrecision registers (16KB), which gives
+// a standard error of about 0.8% for the distinct count estimate.
+const sketchPrecision = 14
+
+const sketchRegisters = 1 << sketchPrecision
+
+// Sketch is a HyperLogLog sketch used to estimate the number of distinct
+// values in a column (or set of columns) without keeping the values around.
+// Sketches built on different nodes over disjoint parts of a table can be
+// merged.
+//
+// See: P. Flajolet, E. Fusy, O. Gandouet and F. Meunier, "HyperLogLog: the
+// analysis of a near-optimal cardinality estimation algorithm".
+type Sketch struct {
+	registers [sketchRegisters]uint8
+}
+
+// NewSketch returns an empty sketch.
+func NewSketch() *Sketch {
+	return &Sketch{}
+}
+
+// Insert adds a value to the sketch. The value is given by its encoding;
+// values that are equal must have the same encoding.
+func (s *Sketch) Insert(encoded []byte) {
+	s.insertHash(hashBytes(encoded))
+}
+
+func (s *Sketch) insertHash(h uint64) {
+	idx := h >> (64 - sketchPrecision)
+	// rho is the position of the leftmost 1 bit in the remaining bits of the
+	// hash.
+	rho := uint8(1)
+	for w := h << sketchPrecision; rho <= 64-sketchPrecision && w&(1<<63) == 0; w <<= 1 {
+		rho++
+	}
+	if rho > s.registers[idx] {
+		s.registers[idx] = rho
+	}
+}
+
+// Merge adds all the values of other to the sketch.
+func (s *Sketch) Merge(other *Sketch) {
+	for i, r := range other.registers {
+		if r > s.registers[i] {
+			s.registers[i] = r
+		}
+	}
+}
+
+// Estimate returns the estimated number of distinct values inserted in the
+// sketch.
+func (s *Sketch) Estimate() int64 {
+	const m = float64(sketchRegisters)
+	alpha := 0.7213 / (1 + 1.079/m)
+
+	sum := 0.0
+	zeros := 0
+	for _, r := range s.registers {
+		sum += 1 / float64(uint64(1)<<r)
+		if r == 0 {
+			zeros++
+		}
+	}
+	estimate := alpha * m * m / sum
+	if estimate <= 2.5*m && zeros > 0 {
+		// Use linear counting for small cardinalities, for which the raw
+		// estimate is biased.
+		estimate = m * math.Log(m/float64(zeros))
+	}
+	return int64(estimate + 0.5)
+}
+
+// MarshalBinary encodes the sketch so that it can be sent to another node.
+func (s *Sketch) MarshalBinary() ([]byte, error) {
+	data := make([]byte, len(s.registers))
+	copy(data, s.registers[:])
+	return data, nil
+}
+
+// UnmarshalBinary decodes a sketch encoded by MarshalBinary.
+func (s *Sketch) UnmarshalBinary(data []byte) error {
+	if len(data) != len(s.registers) {
+		return errors.Errorf("invalid sketch encoding: %d bytes", len(data))
+	}
+	copy(s.registers[:], data)
+	return nil
+}
+
+// hashBytes returns a 64-bit hash of b. It uses FNV-1a followed by a
+// finalizer which mixes the bits of the hash, as FNV alone does not spread
+// short inputs over the high bits well enough for the sketch.
+func hashBytes(b []byte) uint64 {
+	const offset64 = 14695981039346656037
+	const prime64 = 1099511628211
+
+	h := uint64(offset64)
+	for _, c := range b {
+		h ^= uint64(c)
+		h *= prime64
+	}
+	// The finalizer of MurmurHash3.
+	h ^= h >> 33
+	h *= 0xff51afd7ed558ccd
+	h ^= h >> 33
+	h *= 0xc4ceb9fe1a85ec53
+	h ^= h >> 33
+	return h
+}
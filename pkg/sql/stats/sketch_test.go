@@ -0,0 +1,83 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package stats
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+)
+
+func TestSketchEstimate(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	for _, n := range []int{0, 1, 10, 100, 1000, 10000, 100000, 1000000} {
+		t.Run(fmt.Sprintf("%d", n), func(t *testing.T) {
+			s := NewSketch()
+			var buf [8]byte
+			for i := 0; i < n; i++ {
+				binary.BigEndian.PutUint64(buf[:], uint64(i))
+				// Insert every value twice; duplicates must not be counted.
+				s.Insert(buf[:])
+				s.Insert(buf[:])
+			}
+			estimate := s.Estimate()
+			if err := math.Abs(float64(estimate-int64(n))) / math.Max(float64(n), 1); err > 0.05 {
+				t.Errorf("estimate %d for %d distinct values (error %.2f%%)", estimate, n, err*100)
+			}
+		})
+	}
+}
+
+func TestSketchMerge(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	// Build two sketches over overlapping ranges of values and merge them
+	// through their encoding, as is done when sketches are sent between nodes.
+	s1, s2 := NewSketch(), NewSketch()
+	var buf [8]byte
+	for i := 0; i < 6000; i++ {
+		binary.BigEndian.PutUint64(buf[:], uint64(i))
+		s1.Insert(buf[:])
+	}
+	for i := 4000; i < 10000; i++ {
+		binary.BigEndian.PutUint64(buf[:], uint64(i))
+		s2.Insert(buf[:])
+	}
+
+	data, err := s2.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded Sketch
+	if err := decoded.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if decoded != *s2 {
+		t.Fatal("sketch changed after encoding")
+	}
+	s1.Merge(&decoded)
+
+	if estimate := s1.Estimate(); estimate < 9500 || estimate > 10500 {
+		t.Errorf("expected about 10000 distinct values, got %d", estimate)
+	}
+
+	if err := decoded.UnmarshalBinary(data[1:]); err == nil {
+		t.Error("expected error decoding a truncated sketch")
+	}
+}
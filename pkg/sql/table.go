@@ -643,7 +643,7 @@ func (p *planner) getTableAndIndex(
 	if tableDesc == nil {
 		return nil, nil, sqlbase.NewUndefinedTableError(tn.String())
 	}
-	if err := p.CheckPrivilege(tableDesc, privilege); err != nil {
+	if err := p.CheckPrivilege(ctx, tableDesc, privilege); err != nil {
 		return nil, nil, err
 	}
 
@@ -0,0 +1,207 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+
+	"github.com/cockroachdb/cockroach/pkg/internal/client"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/stats"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+)
+
+// tableStatsCacheTTL is the time after which the cached statistics of a table
+// are reloaded from system.table_statistics. Statistics created on other nodes
+// become visible to the planner on this node after at most this long.
+const tableStatsCacheTTL = time.Minute
+
+// TableStatistic is a statistic on a set of columns of a table, as stored in
+// system.table_statistics.
+type TableStatistic struct {
+	StatisticID   int64
+	Name          string
+	ColumnIDs     []sqlbase.ColumnID
+	CreatedAt     time.Time
+	RowCount      int64
+	DistinctCount int64
+	NullCount     int64
+	// Histogram is only set for statistics on a single column.
+	Histogram *stats.HistogramData
+}
+
+// TableStatisticsCache caches the most recent statistics of the tables used by
+// the planner, so that the planner does not need to query
+// system.table_statistics for every statement.
+type TableStatisticsCache struct {
+	db       *client.DB
+	leaseMgr *LeaseManager
+
+	mu struct {
+		syncutil.Mutex
+		entries map[sqlbase.ID]tableStatsCacheEntry
+	}
+}
+
+type tableStatsCacheEntry struct {
+	stats     []*TableStatistic
+	expiresAt time.Time
+}
+
+// NewTableStatisticsCache creates a TableStatisticsCache.
+func NewTableStatisticsCache(db *client.DB, leaseMgr *LeaseManager) *TableStatisticsCache {
+	c := &TableStatisticsCache{db: db, leaseMgr: leaseMgr}
+	c.mu.entries = make(map[sqlbase.ID]tableStatsCacheEntry)
+	return c
+}
+
+// GetTableStats returns the most recent statistic for each set of columns of
+// the given table, or nil if the table has no statistics. The statistics of
+// system tables are never loaded.
+func (c *TableStatisticsCache) GetTableStats(
+	ctx context.Context, tableID sqlbase.ID,
+) ([]*TableStatistic, error) {
+	if sqlbase.IsReservedID(tableID) {
+		// Don't try to get statistics for system tables (most importantly,
+		// for system.table_statistics itself).
+		return nil, nil
+	}
+	now := timeutil.Now()
+	c.mu.Lock()
+	e, ok := c.mu.entries[tableID]
+	c.mu.Unlock()
+	if ok && now.Before(e.expiresAt) {
+		return e.stats, nil
+	}
+
+	var res []*TableStatistic
+	if err := c.db.Txn(ctx, func(ctx context.Context, txn *client.Txn) error {
+		var err error
+		res, err = c.loadTableStats(ctx, txn, tableID)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.mu.entries[tableID] = tableStatsCacheEntry{stats: res, expiresAt: now.Add(tableStatsCacheTTL)}
+	c.mu.Unlock()
+	return res, nil
+}
+
+// InvalidateTableStats removes the statistics of the given table from the
+// cache, so that they are reloaded on the next use.
+func (c *TableStatisticsCache) InvalidateTableStats(tableID sqlbase.ID) {
+	c.mu.Lock()
+	delete(c.mu.entries, tableID)
+	c.mu.Unlock()
+}
+
+// loadTableStats reads the statistics of a table from system.table_statistics,
+// keeping only the most recent statistic for each set of columns.
+func (c *TableStatisticsCache) loadTableStats(
+	ctx context.Context, txn *client.Txn, tableID sqlbase.ID,
+) ([]*TableStatistic, error) {
+	const getTableStatisticsStmt = `
+SELECT "statisticID", name, "columnIDs", "createdAt", "rowCount", "distinctCount", "nullCount", histogram
+FROM system.table_statistics
+WHERE "tableID" = $1
+ORDER BY "createdAt" DESC
+`
+	ie := InternalExecutor{LeaseManager: c.leaseMgr}
+	rows, err := ie.QueryRowsInTransaction(ctx, "get-table-statistics", txn, getTableStatisticsStmt, int(tableID))
+	if err != nil {
+		return nil, err
+	}
+
+	var res []*TableStatistic
+	seen := make(map[string]struct{})
+	for _, row := range rows {
+		stat, err := parseTableStatistic(row)
+		if err != nil {
+			return nil, err
+		}
+		key := fmt.Sprint(stat.ColumnIDs)
+		if _, ok := seen[key]; ok {
+			// We already have a more recent statistic on these columns.
+			continue
+		}
+		seen[key] = struct{}{}
+		res = append(res, stat)
+	}
+	return res, nil
+}
+
+// parseTableStatistic converts a row of system.table_statistics, in the order
+// of the columns selected by loadTableStats, to a TableStatistic.
+func parseTableStatistic(row parser.Datums) (*TableStatistic, error) {
+	if len(row) != 8 {
+		return nil, errors.Errorf("expected 8 columns in table statistic, got %d", len(row))
+	}
+	stat := &TableStatistic{
+		StatisticID:   int64(*row[0].(*parser.DInt)),
+		CreatedAt:     row[3].(*parser.DTimestamp).Time,
+		RowCount:      int64(*row[4].(*parser.DInt)),
+		DistinctCount: int64(*row[5].(*parser.DInt)),
+		NullCount:     int64(*row[6].(*parser.DInt)),
+	}
+	if row[1] != parser.DNull {
+		stat.Name = string(*row[1].(*parser.DString))
+	}
+	for _, d := range row[2].(*parser.DArray).Array {
+		stat.ColumnIDs = append(stat.ColumnIDs, sqlbase.ColumnID(*d.(*parser.DInt)))
+	}
+	if row[7] != parser.DNull {
+		stat.Histogram = &stats.HistogramData{}
+		if err := stat.Histogram.Unmarshal([]byte(*row[7].(*parser.DBytes))); err != nil {
+			return nil, err
+		}
+	}
+	return stat, nil
+}
+
+// findColumnStat returns the statistic on the single column colID, or nil if
+// there isn't one.
+func findColumnStat(tableStats []*TableStatistic, colID sqlbase.ColumnID) *TableStatistic {
+	for _, s := range tableStats {
+		if len(s.ColumnIDs) == 1 && s.ColumnIDs[0] == colID {
+			return s
+		}
+	}
+	return nil
+}
+
+// getTableStats returns the statistics of the table, or nil if there are none
+// or they are not available in this context (e.g. for internal planners). Errors
+// loading the statistics are not returned, as statistics only improve
+// planning and are not required for it.
+func (p *planner) getTableStats(ctx context.Context, desc *sqlbase.TableDescriptor) []*TableStatistic {
+	if desc.IsVirtualTable() || p.session.execCfg == nil || p.session.execCfg.TableStatsCache == nil {
+		return nil
+	}
+	tableStats, err := p.session.execCfg.TableStatsCache.GetTableStats(ctx, desc.ID)
+	if err != nil {
+		log.Warningf(ctx, "could not load statistics for table %s: %v", desc.Name, err)
+		return nil
+	}
+	return tableStats
+}
@@ -215,6 +215,7 @@ type tableUpserter struct {
 	txn                   *client.Txn
 	tableDesc             *sqlbase.TableDescriptor
 	fkTables              sqlbase.TableLookupsByID // for fk checks in update case
+	evalCtx               *parser.EvalContext      // for fk actions in update case
 	ru                    sqlbase.RowUpdater
 	updateColIDtoRowIndex map[sqlbase.ColumnID]int
 	a                     sqlbase.DatumAlloc
@@ -279,6 +280,7 @@ func (tu *tableUpserter) init(txn *client.Txn) error {
 		var err error
 		tu.ru, err = sqlbase.MakeRowUpdater(
 			txn, tu.tableDesc, tu.fkTables, tu.updateCols, requestedCols, sqlbase.RowUpdaterDefault,
+			tu.evalCtx,
 		)
 		if err != nil {
 			return err
@@ -404,11 +406,14 @@ func (tu *tableUpserter) upsertRowPKs(ctx context.Context) ([]roachpb.Key, error
 	// others will be conflicting rows.
 	b := tu.txn.NewBatch()
 	for _, insertRow := range tu.insertRows {
-		entry, err := sqlbase.EncodeSecondaryIndex(
+		entries, err := sqlbase.EncodeSecondaryIndex(
 			tu.tableDesc, &tu.conflictIndex, tu.ri.InsertColIDtoRowIndex, insertRow)
 		if err != nil {
 			return nil, err
 		}
+		// The conflict index is unique, so it isn't inverted and has exactly
+		// one entry per row.
+		entry := entries[0]
 		if log.V(2) {
 			log.Infof(ctx, "Get %s\n", entry.Key)
 		}
@@ -800,9 +805,7 @@ func collectTableWriterSpans(
 	// conservative and assume anything in the table might change. See TODO on
 	// tableWriter.spans for discussion on constraining spans wherever possible.
 	tableSpans := desc.AllIndexSpans()
+	// Foreign key actions (e.g. ON DELETE CASCADE) can modify other tables.
 	fkReads, fkWrites := fks.CollectSpans()
-	if len(fkWrites) > 0 {
-		return nil, nil, errors.Errorf("unexpected foreign key span writes: %v", fkWrites)
-	}
-	return fkReads, tableSpans, nil
+	return fkReads, append(tableSpans, fkWrites...), nil
 }
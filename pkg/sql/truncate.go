@@ -61,7 +61,7 @@ func (p *planner) Truncate(ctx context.Context, n *parser.Truncate) (planNode, e
 			return nil, errors.Errorf("cannot run TRUNCATE on view %q - views are not updateable", tn)
 		}
 
-		if err := p.CheckPrivilege(tableDesc, privilege.DROP); err != nil {
+		if err := p.CheckPrivilege(ctx, tableDesc, privilege.DROP); err != nil {
 			return nil, err
 		}
 		toTruncate[tableDesc.ID] = tableDesc
@@ -85,7 +85,7 @@ func (p *planner) Truncate(ctx context.Context, n *parser.Truncate) (planNode, e
 				if n.DropBehavior != parser.DropCascade {
 					return nil, errors.Errorf("%q is referenced by foreign key from table %q", tableDesc.Name, other.Name)
 				}
-				if err := p.CheckPrivilege(other, privilege.DROP); err != nil {
+				if err := p.CheckPrivilege(ctx, other, privilege.DROP); err != nil {
 					return nil, err
 				}
 				toTruncate[other.ID] = other
@@ -106,7 +106,7 @@ func (p *planner) Truncate(ctx context.Context, n *parser.Truncate) (planNode, e
 // deletes a range of data for the table, which includes the PK and all
 // indexes.
 func truncateTable(tableDesc *sqlbase.TableDescriptor, txn *client.Txn) error {
-	rd, err := sqlbase.MakeRowDeleter(txn, tableDesc, nil, nil, false)
+	rd, err := sqlbase.MakeRowDeleter(txn, tableDesc, nil, nil, false, nil)
 	if err != nil {
 		return err
 	}
@@ -131,7 +131,7 @@ func truncateTableInChunks(
 			log.Infof(ctx, "table %s truncate at row: %d, span: %s", tableDesc.Name, row, resume)
 		}
 		if err := db.Txn(ctx, func(ctx context.Context, txn *client.Txn) error {
-			rd, err := sqlbase.MakeRowDeleter(txn, tableDesc, nil, nil, false)
+			rd, err := sqlbase.MakeRowDeleter(txn, tableDesc, nil, nil, false, nil)
 			if err != nil {
 				return err
 			}
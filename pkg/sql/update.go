@@ -53,7 +53,8 @@ func (p *planner) makeEditNode(
 			errors.Errorf("cannot run %s on view %q - views are not updateable", priv, tn)
 	}
 
-	if err := p.CheckPrivilege(tableDesc, priv); err != nil {
+	p.maybeAudit(tableDesc, true /* writing */)
+	if err := p.CheckPrivilege(ctx, tableDesc, priv); err != nil {
 		return editNodeBase{}, err
 	}
 
@@ -225,6 +226,12 @@ func (p *planner) Update(
 ) (planNode, error) {
 	tracing.AnnotateTrace()
 
+	popWith, err := p.initWith(ctx, n.With)
+	if err != nil {
+		return nil, err
+	}
+	defer popWith(ctx)
+
 	tn, err := p.getAliasedTableName(n.Table)
 	if err != nil {
 		return nil, err
@@ -272,7 +279,10 @@ func (p *planner) Update(
 	if err := p.fillFKTableMap(ctx, fkTables); err != nil {
 		return nil, err
 	}
-	ru, err := sqlbase.MakeRowUpdater(p.txn, en.tableDesc, fkTables, updateCols, requestedCols, sqlbase.RowUpdaterDefault)
+	p.auditFKTables(en.tableDesc, fkTables, sqlbase.CheckUpdates)
+	ru, err := sqlbase.MakeRowUpdater(
+		p.txn, en.tableDesc, fkTables, updateCols, requestedCols, sqlbase.RowUpdaterDefault, &p.evalCtx,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -203,6 +203,9 @@ func (v *planVisitor) visit(plan planNode) {
 				buf.WriteByte(')')
 				v.observer.attr(name, "equality", buf.String())
 			}
+			if n.costBased {
+				v.observer.attr(name, "algorithm", n.algorithm.String())
+			}
 		}
 		subplans := v.expr(name, "pred", -1, n.pred.onCond, nil)
 		v.subqueries(name, subplans)
@@ -273,6 +276,13 @@ func (v *planVisitor) visit(plan planNode) {
 		v.visit(n.left)
 		v.visit(n.right)
 
+	case *recursiveCTENode:
+		if v.observer.attr != nil {
+			v.observer.attr(name, "name", n.name.Alias.String())
+		}
+		v.visit(n.initial)
+		v.visit(n.recursive)
+
 	case *splitNode:
 		v.visit(n.rows)
 
@@ -376,6 +386,9 @@ func (v *planVisitor) visit(plan planNode) {
 	case *ordinalityNode:
 		v.visit(n.source)
 
+	case *copyToNode:
+		v.visit(n.source)
+
 	case *explainTraceNode:
 		v.visit(n.plan)
 
@@ -492,9 +505,15 @@ func nodeName(plan planNode) string {
 // be changed without changing the output of "EXPLAIN".
 var planNodeNames = map[reflect.Type]string{
 	reflect.TypeOf(&alterTableNode{}):     "alter table",
+	reflect.TypeOf(&cancelQueryNode{}):    "cancel query",
+	reflect.TypeOf(&cancelSessionNode{}):  "cancel session",
+	reflect.TypeOf(&controlJobNode{}):     "control job",
 	reflect.TypeOf(&copyNode{}):           "copy",
+	reflect.TypeOf(&copyToNode{}):         "copy to",
 	reflect.TypeOf(&createDatabaseNode{}): "create database",
 	reflect.TypeOf(&createIndexNode{}):    "create index",
+	reflect.TypeOf(&createSequenceNode{}): "create sequence",
+	reflect.TypeOf(&createStatsNode{}):    "create statistics",
 	reflect.TypeOf(&createTableNode{}):    "create table",
 	reflect.TypeOf(&createUserNode{}):     "create user",
 	reflect.TypeOf(&createViewNode{}):     "create view",
@@ -503,6 +522,8 @@ var planNodeNames = map[reflect.Type]string{
 	reflect.TypeOf(&distinctNode{}):       "distinct",
 	reflect.TypeOf(&dropDatabaseNode{}):   "drop database",
 	reflect.TypeOf(&dropIndexNode{}):      "drop index",
+	reflect.TypeOf(&dropRoleNode{}):       "drop role",
+	reflect.TypeOf(&dropSequenceNode{}):   "drop sequence",
 	reflect.TypeOf(&dropTableNode{}):      "drop table",
 	reflect.TypeOf(&dropViewNode{}):       "drop view",
 	reflect.TypeOf(&emptyNode{}):          "empty",
@@ -511,6 +532,7 @@ var planNodeNames = map[reflect.Type]string{
 	reflect.TypeOf(&explainPlanNode{}):    "explain plan",
 	reflect.TypeOf(&explainTraceNode{}):   "explain trace",
 	reflect.TypeOf(&filterNode{}):         "filter",
+	reflect.TypeOf(&grantRoleNode{}):      "grant role",
 	reflect.TypeOf(&groupNode{}):          "group",
 	reflect.TypeOf(&hookFnNode{}):         "plugin",
 	reflect.TypeOf(&indexJoinNode{}):      "index-join",
@@ -518,8 +540,10 @@ var planNodeNames = map[reflect.Type]string{
 	reflect.TypeOf(&joinNode{}):           "join",
 	reflect.TypeOf(&limitNode{}):          "limit",
 	reflect.TypeOf(&ordinalityNode{}):     "ordinality",
+	reflect.TypeOf(&recursiveCTENode{}):   "recursive cte",
 	reflect.TypeOf(&relocateNode{}):       "relocate",
 	reflect.TypeOf(&renderNode{}):         "render",
+	reflect.TypeOf(&revokeRoleNode{}):     "revoke role",
 	reflect.TypeOf(&scanNode{}):           "scan",
 	reflect.TypeOf(&scatterNode{}):        "scatter",
 	reflect.TypeOf(&showRangesNode{}):     "showRanges",
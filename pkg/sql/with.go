@@ -0,0 +1,525 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"fmt"
+
+	"golang.org/x/net/context"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+)
+
+// Common table expressions (CTEs) are named subqueries introduced by a
+// WITH clause ahead of a SELECT, INSERT, UPDATE or DELETE statement:
+//
+//     WITH a AS (SELECT x FROM t), b(y) AS (SELECT x+1 FROM a) SELECT * FROM b
+//
+// While a statement is being planned, the CTEs in scope are kept in the
+// planner's cteNameEnvironment. A table name in a FROM clause that is
+// not qualified with a database name is first looked up in this
+// environment (innermost WITH clause first) before it is resolved
+// against the database.
+//
+// A CTE is planned once when its WITH clause is initialized, so that
+// errors are reported even for CTEs that are never used. This first plan
+// serves the first reference to the CTE; any further reference re-plans
+// the CTE's statement, in the scope of the WITH clause that defined it.
+//
+// A CTE defined by WITH RECURSIVE whose statement has the form
+//
+//     <initial term> UNION [ALL] <recursive term>
+//
+// and whose recursive term refers to the CTE itself is planned as a
+// recursiveCTENode. The initial term is run first; then the recursive
+// term is evaluated over and over, each time with the CTE name bound to
+// the "working table" containing the rows produced by the previous
+// iteration, until an iteration produces no new rows.
+
+// cteSource describes a common table expression that is in scope while
+// planning a statement.
+type cteSource struct {
+	// name is the name of the CTE, possibly with column aliases.
+	name parser.AliasClause
+	// stmt is the statement defining the CTE.
+	stmt parser.Statement
+	// recursive is set if the CTE was introduced by WITH RECURSIVE.
+	recursive bool
+	// plan, if set, is a plan for stmt built when the WITH clause was
+	// initialized. It is consumed by the first reference to the CTE.
+	plan planNode
+	// working, if set, indicates that the name currently designates the
+	// working table of a recursive CTE being evaluated.
+	working *cteWorkingTable
+}
+
+// cteNameEnvironment is the list of CTEs in scope for the statement
+// being planned. Later entries shadow earlier entries with the same
+// name.
+type cteNameEnvironment []cteSource
+
+// cteWorkingTable holds the rows produced by the previous iteration of
+// a recursive CTE.
+type cteWorkingTable struct {
+	columns sqlbase.ResultColumns
+	rows    *sqlbase.RowContainer
+	// refs counts the references to the working table from the recursive
+	// term.
+	refs int
+}
+
+// initWith brings the CTEs defined by the given WITH clause in scope. If
+// the clause is not nil, the returned function must be called once the
+// statement that follows the clause has been planned to remove the CTEs
+// from scope again.
+func (p *planner) initWith(
+	ctx context.Context, with *parser.With,
+) (popWith func(context.Context), err error) {
+	if with == nil {
+		return func(context.Context) {}, nil
+	}
+	saved := p.cteNameEnvironment
+	popWith = func(ctx context.Context) {
+		for i := len(saved); i < len(p.cteNameEnvironment); i++ {
+			if plan := p.cteNameEnvironment[i].plan; plan != nil {
+				plan.Close(ctx)
+				p.cteNameEnvironment[i].plan = nil
+			}
+		}
+		p.cteNameEnvironment = saved
+	}
+	defer func() {
+		if err != nil {
+			popWith(ctx)
+		}
+	}()
+
+	for _, cte := range with.CTEList {
+		name := cte.Name.Alias.Normalize()
+		for _, prev := range p.cteNameEnvironment[len(saved):] {
+			if prev.name.Alias.Normalize() == name {
+				return nil, pgerror.NewErrorf(pgerror.CodeDuplicateAliasError,
+					"WITH query name %q specified more than once", string(cte.Name.Alias))
+			}
+		}
+		if _, ok := cte.Stmt.(*parser.Select); !ok {
+			return nil, pgerror.NewErrorf(pgerror.CodeFeatureNotSupportedError,
+				"%s statements are not supported in WITH", cte.Stmt.StatementTag())
+		}
+
+		src := cteSource{name: cte.Name, stmt: cte.Stmt, recursive: with.Recursive}
+		plan, err := p.planCTE(ctx, len(p.cteNameEnvironment), src)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := src.columns(plan); err != nil {
+			plan.Close(ctx)
+			return nil, err
+		}
+		src.plan = plan
+		p.cteNameEnvironment = append(p.cteNameEnvironment, src)
+	}
+	return popWith, nil
+}
+
+// planCTE builds a plan for the statement of the given CTE, in the scope
+// formed by the first envLen entries of the CTE name environment.
+func (p *planner) planCTE(ctx context.Context, envLen int, src cteSource) (planNode, error) {
+	saved := p.cteNameEnvironment
+	// Cap the slice so that CTEs defined while planning src do not
+	// overwrite the entries that follow it.
+	p.cteNameEnvironment = saved[:envLen:envLen]
+	defer func() { p.cteNameEnvironment = saved }()
+
+	if src.recursive {
+		if plan, ok, err := p.planRecursiveCTE(ctx, src); ok || err != nil {
+			return plan, err
+		}
+	}
+	return p.newPlan(ctx, src.stmt, nil)
+}
+
+// getCTEDataSource looks up the given table name among the CTEs in
+// scope. The boolean return value indicates whether a CTE with this name
+// was found.
+func (p *planner) getCTEDataSource(
+	ctx context.Context, tn *parser.TableName,
+) (planDataSource, bool, error) {
+	if tn.DatabaseName != "" {
+		// A qualified name never designates a CTE.
+		return planDataSource{}, false, nil
+	}
+	name := tn.TableName.Normalize()
+	for i := len(p.cteNameEnvironment) - 1; i >= 0; i-- {
+		src := p.cteNameEnvironment[i]
+		if src.name.Alias.Normalize() != name {
+			continue
+		}
+
+		var plan planNode
+		var err error
+		switch {
+		case src.working != nil:
+			plan, err = src.working.newValuesNode(ctx, p, string(src.name.Alias))
+		case src.plan != nil:
+			plan = src.plan
+			p.cteNameEnvironment[i].plan = nil
+		default:
+			plan, err = p.planCTE(ctx, i, src)
+		}
+		if err != nil {
+			return planDataSource{}, true, err
+		}
+
+		columns, err := src.columns(plan)
+		if err != nil {
+			plan.Close(ctx)
+			return planDataSource{}, true, err
+		}
+		cteName := parser.TableName{TableName: src.name.Alias, DBNameOriginallyOmitted: true}
+		return planDataSource{
+			info: newSourceInfoForSingleTable(cteName, columns),
+			plan: plan,
+		}, true, nil
+	}
+	return planDataSource{}, false, nil
+}
+
+// columns returns the result columns of the given plan for the CTE,
+// renamed according to the column aliases of the CTE if there are any.
+func (src *cteSource) columns(plan planNode) (sqlbase.ResultColumns, error) {
+	if src.working != nil {
+		// The columns of the working table have been renamed already.
+		return plan.Columns(), nil
+	}
+	colAlias := src.name.Cols
+	if len(colAlias) == 0 {
+		return plan.Columns(), nil
+	}
+
+	// Make a copy of the slice since we are about to modify the contents.
+	columns := append(sqlbase.ResultColumns(nil), plan.Columns()...)
+	aliasIdx := 0
+	for colIdx := range columns {
+		if aliasIdx == len(colAlias) {
+			break
+		}
+		if columns[colIdx].Hidden {
+			continue
+		}
+		columns[colIdx].Name = string(colAlias[aliasIdx])
+		aliasIdx++
+	}
+	if aliasIdx < len(colAlias) {
+		return nil, pgerror.NewErrorf(pgerror.CodeInvalidColumnReferenceError,
+			"WITH query %q has %d columns available but %d columns specified",
+			string(src.name.Alias), aliasIdx, len(colAlias))
+	}
+	return columns, nil
+}
+
+// newValuesNode returns a valuesNode that produces the rows of the
+// working table.
+func (w *cteWorkingTable) newValuesNode(
+	ctx context.Context, p *planner, name string,
+) (planNode, error) {
+	w.refs++
+	if w.refs > 1 {
+		return nil, pgerror.NewErrorf(pgerror.CodeInvalidRecursionError,
+			"recursive reference to query %q must not appear more than once", name)
+	}
+	// The valuesNode may mark some of its columns as omitted, so it gets
+	// its own copy of the column descriptions.
+	v := p.newContainerValuesNode(append(sqlbase.ResultColumns(nil), w.columns...), w.rows.Len())
+	for i := 0; i < w.rows.Len(); i++ {
+		if _, err := v.rows.AddRow(ctx, w.rows.At(i)); err != nil {
+			v.Close(ctx)
+			return nil, err
+		}
+	}
+	return v, nil
+}
+
+// planRecursiveCTE plans a CTE introduced by WITH RECURSIVE. The boolean
+// return value is false if the CTE's statement does not have the form
+// <initial term> UNION [ALL] <recursive term> with a recursive term
+// referring to the CTE, in which case it must be planned as a regular
+// CTE instead.
+func (p *planner) planRecursiveCTE(
+	ctx context.Context, src cteSource,
+) (planNode, bool, error) {
+	sel := src.stmt.(*parser.Select)
+	union, ok := sel.Select.(*parser.UnionClause)
+	if !ok || union.Type != parser.UnionOp ||
+		sel.With != nil || sel.OrderBy != nil || sel.Limit != nil {
+		return nil, false, nil
+	}
+
+	initial, err := p.newPlan(ctx, union.Left, nil)
+	if err != nil {
+		return nil, false, err
+	}
+	workingCols, err := src.columns(initial)
+	if err != nil {
+		initial.Close(ctx)
+		return nil, false, err
+	}
+
+	// Plan the recursive term once with an empty working table. This
+	// checks the validity of the recursive term and determines whether it
+	// actually refers to the CTE. The resulting plan is never run; it is
+	// only used to describe the query in EXPLAIN and to report the spans
+	// it will read.
+	working := &cteWorkingTable{
+		columns: workingCols,
+		rows: sqlbase.NewRowContainer(
+			p.session.TxnState.makeBoundAccount(), sqlbase.ColTypeInfoFromResCols(workingCols), 0,
+		),
+	}
+	defer working.rows.Close(ctx)
+	recursive, err := p.planRecursiveTerm(ctx, src.name, union.Right, working)
+	if err != nil {
+		initial.Close(ctx)
+		return nil, false, err
+	}
+	if working.refs == 0 {
+		recursive.Close(ctx)
+		initial.Close(ctx)
+		return nil, false, nil
+	}
+
+	// Each iteration re-plans the recursive term in the current scope,
+	// which may be gone by the time the node runs. Keep a copy of it, in
+	// which every CTE must be re-planned.
+	env := append(cteNameEnvironment(nil), p.cteNameEnvironment...)
+	for i := range env {
+		env[i].plan = nil
+	}
+
+	return &recursiveCTENode{
+		p:             p,
+		name:          src.name,
+		columns:       append(sqlbase.ResultColumns(nil), initial.Columns()...),
+		workingCols:   workingCols,
+		initial:       initial,
+		recursive:     recursive,
+		recursiveTerm: union.Right,
+		env:           env,
+		distinct:      !union.All,
+	}, true, nil
+}
+
+// planRecursiveTerm builds a plan for the recursive term of a recursive
+// CTE, with the CTE name bound to the given working table.
+func (p *planner) planRecursiveTerm(
+	ctx context.Context, name parser.AliasClause, stmt parser.SelectStatement, working *cteWorkingTable,
+) (planNode, error) {
+	saved := p.cteNameEnvironment
+	p.cteNameEnvironment = append(saved[:len(saved):len(saved)], cteSource{name: name, working: working})
+	defer func() { p.cteNameEnvironment = saved }()
+
+	plan, err := p.newPlan(ctx, stmt, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	columns := plan.Columns()
+	if len(columns) != len(working.columns) {
+		plan.Close(ctx)
+		return nil, pgerror.NewErrorf(pgerror.CodeDatatypeMismatchError,
+			"each UNION query must have the same number of columns: %d vs %d",
+			len(working.columns), len(columns))
+	}
+	for i, col := range columns {
+		typ := working.columns[i].Typ
+		if col.Typ != parser.TypeNull && !col.Typ.Equivalent(typ) {
+			plan.Close(ctx)
+			return nil, pgerror.NewErrorf(pgerror.CodeDatatypeMismatchError,
+				"recursive query %q column %d has type %s in non-recursive term but type %s overall",
+				string(name.Alias), i+1, typ, col.Typ)
+		}
+	}
+	return plan, nil
+}
+
+// recursiveCTENode is a planNode that evaluates a recursive CTE:
+// it produces the rows of its initial term, then the rows of successive
+// evaluations of its recursive term over the rows produced by the
+// previous evaluation, until an evaluation produces no rows.
+type recursiveCTENode struct {
+	p    *planner
+	name parser.AliasClause
+
+	// columns are the columns produced by the node.
+	columns sqlbase.ResultColumns
+	// workingCols are the columns of the working table, i.e. columns
+	// renamed after the CTE's column aliases.
+	workingCols sqlbase.ResultColumns
+
+	// initial is the plan for the initial, non-recursive term.
+	initial planNode
+	// recursive is a plan for the recursive term over an empty working
+	// table. It is never run: each iteration uses a new plan built from
+	// recursiveTerm in the scope env.
+	recursive     planNode
+	recursiveTerm parser.SelectStatement
+	env           cteNameEnvironment
+
+	// distinct is set for UNION (as opposed to UNION ALL), in which case
+	// duplicate rows are eliminated across all the iterations.
+	distinct bool
+
+	run struct {
+		// cur is the plan currently producing rows: the initial term, then
+		// the plan for each successive iteration.
+		cur planNode
+		// curRows accumulates the rows produced by cur; they form the
+		// working table of the next iteration.
+		curRows *sqlbase.RowContainer
+		row     parser.Datums
+		rowIdx  int
+
+		seen    map[string]struct{}
+		scratch []byte
+	}
+}
+
+func (n *recursiveCTENode) Columns() sqlbase.ResultColumns { return n.columns }
+func (n *recursiveCTENode) Ordering() orderingInfo         { return orderingInfo{} }
+func (n *recursiveCTENode) Values() parser.Datums          { return n.run.row }
+func (n *recursiveCTENode) MarkDebug(_ explainMode)        {}
+
+func (n *recursiveCTENode) DebugValues() debugValues {
+	return debugValues{
+		rowIdx: n.run.rowIdx - 1,
+		key:    fmt.Sprintf("%d", n.run.rowIdx-1),
+		value:  n.run.row.String(),
+		output: debugValueRow,
+	}
+}
+
+func (n *recursiveCTENode) Spans(ctx context.Context) (reads, writes roachpb.Spans, err error) {
+	initialReads, initialWrites, err := n.initial.Spans(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	recursiveReads, recursiveWrites, err := n.recursive.Spans(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	return append(initialReads, recursiveReads...), append(initialWrites, recursiveWrites...), nil
+}
+
+func (n *recursiveCTENode) Start(ctx context.Context) error {
+	if n.distinct {
+		n.run.seen = make(map[string]struct{})
+	}
+	n.run.curRows = n.newRowContainer()
+	n.run.cur = n.initial
+	return n.initial.Start(ctx)
+}
+
+func (n *recursiveCTENode) newRowContainer() *sqlbase.RowContainer {
+	return sqlbase.NewRowContainer(
+		n.p.session.TxnState.makeBoundAccount(), sqlbase.ColTypeInfoFromResCols(n.workingCols), 0,
+	)
+}
+
+func (n *recursiveCTENode) Next(ctx context.Context) (bool, error) {
+	for {
+		if n.run.cur == nil {
+			if n.run.curRows.Len() == 0 {
+				// The last iteration did not produce any rows: we're done.
+				return false, nil
+			}
+			if err := n.startIteration(ctx); err != nil {
+				return false, err
+			}
+		}
+
+		next, err := n.run.cur.Next(ctx)
+		if err != nil {
+			return false, err
+		}
+		if !next {
+			if n.run.cur != n.initial {
+				n.run.cur.Close(ctx)
+			}
+			n.run.cur = nil
+			continue
+		}
+
+		values := n.run.cur.Values()
+		if n.distinct {
+			n.run.scratch = n.run.scratch[:0]
+			if n.run.scratch, err = sqlbase.EncodeDatums(n.run.scratch, values); err != nil {
+				return false, err
+			}
+			if _, ok := n.run.seen[string(n.run.scratch)]; ok {
+				continue
+			}
+			n.run.seen[string(n.run.scratch)] = struct{}{}
+		}
+		if n.run.row, err = n.run.curRows.AddRow(ctx, values); err != nil {
+			return false, err
+		}
+		n.run.rowIdx++
+		return true, nil
+	}
+}
+
+// startIteration plans and starts the next evaluation of the recursive
+// term, over the rows produced by the previous evaluation.
+func (n *recursiveCTENode) startIteration(ctx context.Context) error {
+	working := &cteWorkingTable{columns: n.workingCols, rows: n.run.curRows}
+	n.run.curRows = n.newRowContainer()
+	defer working.rows.Close(ctx)
+
+	p := n.p
+	saved := p.cteNameEnvironment
+	p.cteNameEnvironment = n.env
+	plan, err := p.planRecursiveTerm(ctx, n.name, n.recursiveTerm, working)
+	p.cteNameEnvironment = saved
+	if err != nil {
+		return err
+	}
+	plan, err = p.optimizePlan(ctx, plan, allColumns(plan))
+	if err != nil {
+		plan.Close(ctx)
+		return err
+	}
+	if err := p.startPlan(ctx, plan); err != nil {
+		plan.Close(ctx)
+		return err
+	}
+	n.run.cur = plan
+	return nil
+}
+
+func (n *recursiveCTENode) Close(ctx context.Context) {
+	if n.run.cur != nil && n.run.cur != n.initial {
+		n.run.cur.Close(ctx)
+	}
+	n.run.cur = nil
+	if n.run.curRows != nil {
+		n.run.curRows.Close(ctx)
+		n.run.curRows = nil
+	}
+	n.initial.Close(ctx)
+	n.recursive.Close(ctx)
+}
@@ -48,6 +48,12 @@ const (
 	// that store.
 	baseRebalanceThreshold = 0.05
 
+	// minQPSLeaseRebalanceSurplus is the minimum number of queries per second
+	// by which a store must exceed the mean before leases are transferred away
+	// from it to balance QPS. It prevents lease churn in lightly loaded
+	// clusters.
+	minQPSLeaseRebalanceSurplus = 100
+
 	// priorities for various repair operations.
 	addMissingReplicaPriority  float64 = 10000
 	removeDeadReplicaPriority  float64 = 1000
@@ -406,9 +412,13 @@ func (a Allocator) RebalanceTarget(
 // TransferLeaseTarget returns a suitable replica to transfer the range lease
 // to from the provided list. It excludes the current lease holder replica
 // unless asked to do otherwise by the checkTransferLeaseSource parameter.
+//
+// The lease preferences of the zone take precedence over the lease counts and
+// load of the stores: if some live replica satisfies a lease preference, only
+// the replicas satisfying the first such preference are considered.
 func (a *Allocator) TransferLeaseTarget(
 	ctx context.Context,
-	constraints config.Constraints,
+	zone config.ZoneConfig,
 	existing []roachpb.ReplicaDescriptor,
 	leaseStoreID roachpb.StoreID,
 	rangeID roachpb.RangeID,
@@ -417,7 +427,7 @@ func (a *Allocator) TransferLeaseTarget(
 	checkCandidateFullness bool,
 ) roachpb.ReplicaDescriptor {
 	sl, _, _ := a.storePool.getStoreList(rangeID)
-	sl = sl.filter(constraints)
+	sl = sl.filter(zone.Constraints)
 
 	// Filter stores that are on nodes containing existing replicas, but leave
 	// the stores containing the existing replicas in place. This excludes stores
@@ -447,6 +457,29 @@ func (a *Allocator) TransferLeaseTarget(
 		return roachpb.ReplicaDescriptor{}
 	}
 
+	if preferred := a.preferredLeaseholders(zone, rangeID, existing); len(preferred) > 0 {
+		if !storeHasReplica(leaseStoreID, preferred) {
+			// The lease isn't on a preferred store, so move it to the preferred
+			// store with the fewest leases regardless of the other heuristics.
+			return a.leastLeasesReplica(preferred)
+		}
+		if len(preferred) > 1 {
+			existing = preferred
+		} else if checkTransferLeaseSource {
+			// The lease is already on the only preferred store.
+			return roachpb.ReplicaDescriptor{}
+		}
+		// Otherwise the lease has to be transferred away from the only preferred
+		// store (e.g. because its replica is being removed), so fall back to
+		// considering all replicas.
+	}
+
+	// Balancing the QPS of the stores takes precedence over the locality of
+	// the requests and the lease counts.
+	if repl := a.leaseTargetForQPS(ctx, sl, source, existing, stats); repl != (roachpb.ReplicaDescriptor{}) {
+		return repl
+	}
+
 	// Try to pick a replica to transfer the lease to while also determining
 	// whether we actually should be transferring the lease. The transfer
 	// decision is only needed if we've been asked to check the source.
@@ -492,12 +525,13 @@ func (a *Allocator) TransferLeaseTarget(
 	return candidates[a.randGen.Intn(len(candidates))]
 }
 
-// ShouldTransferLease returns true if the specified store is overfull in terms
-// of leases with respect to the other stores matching the specified
-// attributes.
+// ShouldTransferLease returns true if the specified store doesn't satisfy the
+// lease preferences of the zone while another replica's store does, or if it
+// is overfull in terms of leases with respect to the other stores matching the
+// specified attributes.
 func (a *Allocator) ShouldTransferLease(
 	ctx context.Context,
-	constraints config.Constraints,
+	zone config.ZoneConfig,
 	existing []roachpb.ReplicaDescriptor,
 	leaseStoreID roachpb.StoreID,
 	rangeID roachpb.RangeID,
@@ -507,12 +541,32 @@ func (a *Allocator) ShouldTransferLease(
 	if !ok {
 		return false
 	}
+	if preferred := a.preferredLeaseholders(zone, rangeID, existing); len(preferred) > 0 {
+		if !storeHasReplica(leaseStoreID, preferred) {
+			if log.V(3) {
+				log.Infof(ctx, "ShouldTransferLease (lease-holder=%d): lease preferences not satisfied",
+					leaseStoreID)
+			}
+			return true
+		}
+		if len(preferred) == 1 {
+			return false
+		}
+		existing = preferred
+	}
 	sl, _, _ := a.storePool.getStoreList(rangeID)
-	sl = sl.filter(constraints)
+	sl = sl.filter(zone.Constraints)
 	if log.V(3) {
 		log.Infof(ctx, "ShouldTransferLease (lease-holder=%d):\n%s", leaseStoreID, sl)
 	}
 
+	if repl := a.leaseTargetForQPS(ctx, sl, source, existing, stats); repl != (roachpb.ReplicaDescriptor{}) {
+		if log.V(3) {
+			log.Infof(ctx, "ShouldTransferLease (lease-holder=%d): store QPS is overfull", leaseStoreID)
+		}
+		return true
+	}
+
 	transferDec, _ := a.shouldTransferLeaseUsingStats(ctx, sl, source, existing, stats)
 	var result bool
 	switch transferDec {
@@ -532,6 +586,106 @@ func (a *Allocator) ShouldTransferLease(
 	return result
 }
 
+// preferredLeaseholders returns the replicas in existing whose stores are live
+// and satisfy the first lease preference of the zone that is satisfied by any
+// such replica. It returns nil if the zone has no lease preferences or none of
+// them can currently be satisfied.
+func (a *Allocator) preferredLeaseholders(
+	zone config.ZoneConfig, rangeID roachpb.RangeID, existing []roachpb.ReplicaDescriptor,
+) []roachpb.ReplicaDescriptor {
+	if len(zone.LeasePreferences) == 0 {
+		return nil
+	}
+	liveReplicas, _ := a.storePool.liveAndDeadReplicas(rangeID, existing)
+	for _, preference := range zone.LeasePreferences {
+		var preferred []roachpb.ReplicaDescriptor
+		for _, repl := range liveReplicas {
+			storeDesc, ok := a.storePool.getStoreDescriptor(repl.StoreID)
+			if ok && storeMatchesLeasePreference(storeDesc, preference) {
+				preferred = append(preferred, repl)
+			}
+		}
+		if len(preferred) > 0 {
+			return preferred
+		}
+	}
+	return nil
+}
+
+// leastLeasesReplica returns the replica whose store holds the fewest leases.
+func (a *Allocator) leastLeasesReplica(
+	replicas []roachpb.ReplicaDescriptor,
+) roachpb.ReplicaDescriptor {
+	var best roachpb.ReplicaDescriptor
+	bestLeases := int32(math.MaxInt32)
+	for _, repl := range replicas {
+		storeDesc, ok := a.storePool.getStoreDescriptor(repl.StoreID)
+		if !ok {
+			continue
+		}
+		if storeDesc.Capacity.LeaseCount < bestLeases {
+			best = repl
+			bestLeases = storeDesc.Capacity.LeaseCount
+		}
+	}
+	return best
+}
+
+// leaseTargetForQPS returns the replica to transfer the lease to in order to
+// even out the queries per second served by the stores, or an empty
+// descriptor if the source store is not sufficiently overloaded. Together
+// with load-based splitting, this spreads the load of a hot range: both
+// halves of the split start out with the same leaseholder, and moving one of
+// the leases moves its share of the load.
+func (a Allocator) leaseTargetForQPS(
+	ctx context.Context,
+	sl StoreList,
+	source roachpb.StoreDescriptor,
+	existing []roachpb.ReplicaDescriptor,
+	stats *replicaStats,
+) roachpb.ReplicaDescriptor {
+	if stats == nil || !EnableLoadBasedLeaseRebalancing.Get() {
+		return roachpb.ReplicaDescriptor{}
+	}
+	rangeQPS, dur := stats.avgQPS()
+	if dur < MinLeaseTransferStatsDuration || rangeQPS <= 0 {
+		return roachpb.ReplicaDescriptor{}
+	}
+	sourceQPS := source.Capacity.QueriesPerSecond
+	overfullQPS := math.Max(sl.candidateQPS.mean*(1+baseRebalanceThreshold),
+		sl.candidateQPS.mean+minQPSLeaseRebalanceSurplus)
+	if sourceQPS <= overfullQPS {
+		return roachpb.ReplicaDescriptor{}
+	}
+
+	var bestRepl roachpb.ReplicaDescriptor
+	bestQPS := math.MaxFloat64
+	for _, repl := range existing {
+		if repl.StoreID == source.StoreID {
+			continue
+		}
+		storeDesc, ok := a.storePool.getStoreDescriptor(repl.StoreID)
+		if !ok {
+			continue
+		}
+		// Don't move the lease if that would leave the target store serving
+		// more queries than the source store, which would just move the hotspot.
+		qps := storeDesc.Capacity.QueriesPerSecond
+		if qps+rangeQPS >= sourceQPS-rangeQPS {
+			continue
+		}
+		if qps < bestQPS {
+			bestRepl = repl
+			bestQPS = qps
+		}
+	}
+	if log.V(3) && bestRepl != (roachpb.ReplicaDescriptor{}) {
+		log.Infof(ctx, "transferring lease to balance QPS: range-qps=%.2f source-qps=%.2f "+
+			"target-qps=%.2f overfull-qps=%.2f", rangeQPS, sourceQPS, bestQPS, overfullQPS)
+	}
+	return bestRepl
+}
+
 func (a Allocator) shouldTransferLeaseUsingStats(
 	ctx context.Context,
 	sl StoreList,
@@ -979,7 +979,7 @@ func TestAllocatorTransferLeaseTarget(t *testing.T) {
 		t.Run("", func(t *testing.T) {
 			target := a.TransferLeaseTarget(
 				context.Background(),
-				config.Constraints{},
+				config.ZoneConfig{},
 				c.existing,
 				c.leaseholder,
 				0,
@@ -1032,7 +1032,7 @@ func TestAllocatorTransferLeaseTargetMultiStore(t *testing.T) {
 		t.Run("", func(t *testing.T) {
 			target := a.TransferLeaseTarget(
 				context.Background(),
-				config.Constraints{},
+				config.ZoneConfig{},
 				existing,
 				c.leaseholder,
 				0,
@@ -1094,7 +1094,7 @@ func TestAllocatorShouldTransferLease(t *testing.T) {
 		t.Run("", func(t *testing.T) {
 			result := a.ShouldTransferLease(
 				context.Background(),
-				config.Constraints{},
+				config.ZoneConfig{},
 				c.existing,
 				c.leaseholder,
 				0,
@@ -1107,6 +1107,202 @@ func TestAllocatorShouldTransferLease(t *testing.T) {
 	}
 }
 
+func TestAllocatorLeasePreferences(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	stopper, g, _, a, _ := createTestAllocator( /* deterministic */ true)
+	defer stopper.Stop(context.Background())
+
+	// 4 stores where the lease count for each store is equal to 10x the store
+	// ID.
+	regions := []string{"us-west", "us-east", "us-east", "eu"}
+	var stores []*roachpb.StoreDescriptor
+	for i := 1; i <= 4; i++ {
+		stores = append(stores, &roachpb.StoreDescriptor{
+			StoreID: roachpb.StoreID(i),
+			Node: roachpb.NodeDescriptor{
+				NodeID: roachpb.NodeID(i),
+				Locality: roachpb.Locality{
+					Tiers: []roachpb.Tier{{Key: "region", Value: regions[i-1]}},
+				},
+			},
+			Capacity: roachpb.StoreCapacity{LeaseCount: int32(10 * i)},
+		})
+	}
+	sg := gossiputil.NewStoreGossiper(g)
+	sg.GossipStores(stores, t)
+
+	replicas := func(storeIDs ...roachpb.StoreID) []roachpb.ReplicaDescriptor {
+		var r []roachpb.ReplicaDescriptor
+		for _, storeID := range storeIDs {
+			r = append(r, roachpb.ReplicaDescriptor{
+				NodeID:  roachpb.NodeID(storeID),
+				StoreID: storeID,
+			})
+		}
+		return r
+	}
+	preferences := func(regions ...string) []config.LeasePreference {
+		var p []config.LeasePreference
+		for _, region := range regions {
+			p = append(p, config.LeasePreference{
+				Constraints: []config.Constraint{
+					{Type: config.Constraint_REQUIRED, Key: "region", Value: region},
+				},
+			})
+		}
+		return p
+	}
+
+	testCases := []struct {
+		leaseholder    roachpb.StoreID
+		existing       []roachpb.ReplicaDescriptor
+		preferences    []config.LeasePreference
+		expectTransfer bool
+		expectTarget   roachpb.StoreID
+	}{
+		// The leaseholder isn't preferred, so the lease moves to the preferred
+		// store with the fewest leases.
+		{1, replicas(1, 2, 3), preferences("us-east"), true, 2},
+		{1, replicas(1, 2, 3), preferences("eu", "us-east"), true, 2},
+		{2, replicas(1, 2, 4), preferences("eu", "us-east"), true, 4},
+		// The leaseholder is the only preferred store.
+		{2, replicas(1, 2), preferences("us-east"), false, 0},
+		{4, replicas(1, 2, 4), preferences("eu", "us-east"), false, 0},
+		// No preference can be satisfied, so the lease counts decide.
+		{1, replicas(1, 2, 3), preferences("asia"), false, 0},
+		{4, replicas(1, 4), preferences("asia"), true, 1},
+	}
+	for _, c := range testCases {
+		t.Run("", func(t *testing.T) {
+			zone := config.ZoneConfig{LeasePreferences: c.preferences}
+			result := a.ShouldTransferLease(
+				context.Background(),
+				zone,
+				c.existing,
+				c.leaseholder,
+				0,
+				nil, /* replicaStats */
+			)
+			if c.expectTransfer != result {
+				t.Errorf("expected %v, but found %v", c.expectTransfer, result)
+			}
+			target := a.TransferLeaseTarget(
+				context.Background(),
+				zone,
+				c.existing,
+				c.leaseholder,
+				0,
+				nil,  /* replicaStats */
+				true, /* checkTransferLeaseSource */
+				true, /* checkCandidateFullness */
+			)
+			if c.expectTarget != target.StoreID {
+				t.Errorf("expected target s%d, but found s%d", c.expectTarget, target.StoreID)
+			}
+		})
+	}
+
+	// A lease that must move off the only preferred store (e.g. because its
+	// replica is being removed) falls back to the other replicas.
+	target := a.TransferLeaseTarget(
+		context.Background(),
+		config.ZoneConfig{LeasePreferences: preferences("us-east")},
+		replicas(1, 2),
+		2,
+		0,
+		nil,   /* replicaStats */
+		false, /* checkTransferLeaseSource */
+		true,  /* checkCandidateFullness */
+	)
+	if target.StoreID != 1 {
+		t.Errorf("expected target s1, but found s%d", target.StoreID)
+	}
+}
+
+// TestAllocatorTransferLeaseTargetQPS verifies that leases are transferred
+// away from stores serving many more queries per second than the others.
+func TestAllocatorTransferLeaseTargetQPS(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	stopper, g, _, a, _ := createTestAllocator( /* deterministic */ true)
+	defer stopper.Stop(context.Background())
+
+	storeQPS := []float64{1000, 200, 100, 500}
+	var stores []*roachpb.StoreDescriptor
+	for i, qps := range storeQPS {
+		stores = append(stores, &roachpb.StoreDescriptor{
+			StoreID:  roachpb.StoreID(i + 1),
+			Node:     roachpb.NodeDescriptor{NodeID: roachpb.NodeID(i + 1)},
+			Capacity: roachpb.StoreCapacity{LeaseCount: 10, QueriesPerSecond: qps},
+		})
+	}
+	sg := gossiputil.NewStoreGossiper(g)
+	sg.GossipStores(stores, t)
+
+	// The range receives 300 queries per second.
+	manual := hlc.NewManualClock(123)
+	clock := hlc.NewClock(manual.UnixNano, time.Nanosecond)
+	stats := newReplicaStats(clock, func(roachpb.NodeID) string { return "" })
+	for i := 0; i < 300*int(MinLeaseTransferStatsDuration/time.Second); i++ {
+		stats.record(1)
+	}
+	manual.Increment(int64(MinLeaseTransferStatsDuration))
+
+	replicas := func(storeIDs ...roachpb.StoreID) []roachpb.ReplicaDescriptor {
+		var r []roachpb.ReplicaDescriptor
+		for _, storeID := range storeIDs {
+			r = append(r, roachpb.ReplicaDescriptor{
+				NodeID:  roachpb.NodeID(storeID),
+				StoreID: storeID,
+			})
+		}
+		return r
+	}
+
+	testCases := []struct {
+		leaseholder    roachpb.StoreID
+		existing       []roachpb.ReplicaDescriptor
+		expectTransfer bool
+		expectTarget   roachpb.StoreID
+	}{
+		// The leaseholder is overloaded, so the lease moves to the store serving
+		// the fewest queries.
+		{1, replicas(1, 2, 3), true, 3},
+		{1, replicas(1, 2), true, 2},
+		// Moving the lease would just move the hotspot.
+		{1, replicas(1, 4), false, 0},
+		// The leaseholder isn't overloaded.
+		{3, replicas(1, 2, 3), false, 0},
+	}
+	for _, c := range testCases {
+		t.Run("", func(t *testing.T) {
+			result := a.ShouldTransferLease(
+				context.Background(),
+				config.ZoneConfig{},
+				c.existing,
+				c.leaseholder,
+				0,
+				stats,
+			)
+			if c.expectTransfer != result {
+				t.Errorf("expected %v, but found %v", c.expectTransfer, result)
+			}
+			target := a.TransferLeaseTarget(
+				context.Background(),
+				config.ZoneConfig{},
+				c.existing,
+				c.leaseholder,
+				0,
+				stats,
+				true, /* checkTransferLeaseSource */
+				true, /* checkCandidateFullness */
+			)
+			if c.expectTarget != target.StoreID {
+				t.Errorf("expected target s%d, but found s%d", c.expectTarget, target.StoreID)
+			}
+		})
+	}
+}
+
 // Test out the load-based lease transfer algorithm against a variety of
 // request distributions and inter-node latencies.
 func TestAllocatorTransferLeaseTargetLoadBased(t *testing.T) {
@@ -1260,7 +1456,7 @@ func TestAllocatorTransferLeaseTargetLoadBased(t *testing.T) {
 			})
 			target := a.TransferLeaseTarget(
 				context.Background(),
-				config.Constraints{},
+				config.ZoneConfig{},
 				existing,
 				c.leaseholder,
 				0,
@@ -20,15 +20,18 @@ import (
 	"bytes"
 	"fmt"
 	"reflect"
+	"sort"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/pkg/errors"
 	"golang.org/x/net/context"
 
 	"github.com/cockroachdb/cockroach/pkg/internal/client"
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/storage"
 	"github.com/cockroachdb/cockroach/pkg/storage/engine"
 	"github.com/cockroachdb/cockroach/pkg/testutils"
@@ -435,6 +438,214 @@ func TestStoreRangeMergeStats(t *testing.T) {
 	}
 }
 
+// raftDropHandler drops the Raft requests of one range addressed to a store.
+type raftDropHandler struct {
+	rangeID roachpb.RangeID
+	storage.RaftMessageHandler
+}
+
+func (h *raftDropHandler) HandleRaftRequest(
+	ctx context.Context,
+	req *storage.RaftMessageRequest,
+	respStream storage.RaftMessageResponseStream,
+) *roachpb.Error {
+	if req.RangeID == h.rangeID {
+		return nil
+	}
+	return h.RaftMessageHandler.HandleRaftRequest(ctx, req, respStream)
+}
+
+// TestStoreRangeMergeReplicaGCRace verifies that the replica GC queue does
+// not remove the replica of a merged-away range from a store before the
+// subsuming range has applied the merge on that store, as applying the merge
+// requires it.
+func TestStoreRangeMergeReplicaGCRace(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	sc := storage.TestStoreConfig(nil)
+	sc.TestingKnobs.DisableSplitQueue = true
+	sc.TestingKnobs.DisableReplicaGCQueue = true
+	mtc := &multiTestContext{storeConfig: &sc}
+	defer mtc.Stop()
+	mtc.Start(t, 3)
+	ctx := context.Background()
+
+	if err := mtc.dbs[0].AdminSplit(ctx, "b"); err != nil {
+		t.Fatal(err)
+	}
+	if err := mtc.dbs[0].AdminSplit(ctx, "c"); err != nil {
+		t.Fatal(err)
+	}
+	lhs := mtc.stores[0].LookupReplica(roachpb.RKey("b"), nil)
+	rhs := mtc.stores[0].LookupReplica(roachpb.RKey("c"), nil)
+	mtc.replicateRange(lhs.RangeID, 1, 2)
+	mtc.replicateRange(rhs.RangeID, 1, 2)
+
+	// Keep the left-hand range on store 2 from applying the merge.
+	store2 := mtc.stores[2]
+	mtc.transport.Stop(store2.Ident.StoreID)
+	mtc.transport.Listen(store2.Ident.StoreID, &raftDropHandler{
+		rangeID:            lhs.RangeID,
+		RaftMessageHandler: store2,
+	})
+
+	if err := mtc.dbs[0].AdminMerge(ctx, "b"); err != nil {
+		t.Fatal(err)
+	}
+
+	// The right-hand range has been merged away, but its replica on store 2 is
+	// still needed to apply the merge.
+	rhsRepl2, err := store2.GetReplica(rhs.RangeID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store2.ReplicaGCQueueProcess(ctx, rhsRepl2); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store2.GetReplica(rhs.RangeID); err != nil {
+		t.Fatalf("replica removed before the merge was applied: %s", err)
+	}
+
+	// Once the left-hand range receives its Raft log, it applies the merge and
+	// removes the right-hand replica itself.
+	mtc.transport.Stop(store2.Ident.StoreID)
+	mtc.transport.Listen(store2.Ident.StoreID, store2)
+	testutils.SucceedsSoon(t, func() error {
+		if repl := store2.LookupReplica(roachpb.RKey("c"), nil); repl.RangeID != lhs.RangeID {
+			return errors.Errorf("merge not yet applied on store 2: %s", repl)
+		}
+		if _, err := store2.GetReplica(rhs.RangeID); err == nil {
+			return errors.Errorf("r%d not yet removed from store 2", rhs.RangeID)
+		}
+		return nil
+	})
+}
+
+// TestMergeQueue verifies that the merge queue merges a range which is smaller
+// than the minimum size of its zone into its right-hand neighbor once the
+// queue is enabled.
+func TestMergeQueue(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	storeCfg := storage.TestStoreConfig(nil)
+	storeCfg.TestingKnobs.DisableSplitQueue = true
+	stopper := stop.NewStopper()
+	defer stopper.Stop(context.TODO())
+	store := createTestStoreWithConfig(t, stopper, storeCfg)
+	ctx := context.Background()
+
+	// Ranges [b, c) and [c, d) are smaller than the default minimum range size.
+	// Range [d, /Max) contains the system tables, so it is not merged with
+	// them.
+	for _, key := range []string{"b", "c", "d"} {
+		if err := store.DB().AdminSplit(ctx, key); err != nil {
+			t.Fatal(err)
+		}
+	}
+	values := map[string]string{"b1": "one", "c1": "two"}
+	for k, v := range values {
+		if err := store.DB().Put(ctx, k, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The queue is disabled by default.
+	store.ForceMergeScanAndProcess()
+	if repl := store.LookupReplica(roachpb.RKey("c"), nil); !repl.Desc().StartKey.Equal(roachpb.RKey("c")) {
+		t.Fatalf("unexpected merge: %s", repl)
+	}
+
+	defer settings.TestingSetBool(&storage.MergeQueueEnabled, true)()
+	// The ranges have only just been split, so their load is unknown.
+	defer settings.TestingSetInt(&storage.SplitByLoadQPSThreshold, 0)()
+	testutils.SucceedsSoon(t, func() error {
+		store.ForceMergeScanAndProcess()
+		repl := store.LookupReplica(roachpb.RKey("c"), nil)
+		if desc := repl.Desc(); !desc.StartKey.Equal(roachpb.RKey("b")) ||
+			!desc.EndKey.Equal(roachpb.RKey("d")) {
+			return errors.Errorf("[b, d) not merged: %s", repl)
+		}
+		return nil
+	})
+	if repl := store.LookupReplica(roachpb.RKey("d"), nil); !repl.Desc().StartKey.Equal(roachpb.RKey("d")) {
+		t.Fatalf("unexpected merge: %s", repl)
+	}
+	for k, v := range values {
+		kv, err := store.DB().Get(ctx, k)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if actual := string(kv.ValueBytes()); actual != v {
+			t.Errorf("expected %q for %s, got %q", v, k, actual)
+		}
+	}
+}
+
+// TestMergeQueueProcessRelocate verifies that mergeQueue.process moves the
+// replicas of the right-hand range onto the stores of the left-hand range
+// before merging the two.
+func TestMergeQueueProcessRelocate(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer settings.TestingSetBool(&storage.MergeQueueEnabled, true)()
+	// The ranges have only just been split, so their load is unknown.
+	defer settings.TestingSetInt(&storage.SplitByLoadQPSThreshold, 0)()
+	sc := storage.TestStoreConfig(nil)
+	sc.TestingKnobs.DisableSplitQueue = true
+	sc.TestingKnobs.DisableMergeQueue = true
+	mtc := &multiTestContext{storeConfig: &sc}
+	defer mtc.Stop()
+	mtc.Start(t, 4)
+	store := mtc.stores[0]
+	ctx := context.Background()
+
+	for _, key := range []string{"b", "c", "d"} {
+		if err := mtc.dbs[0].AdminSplit(ctx, key); err != nil {
+			t.Fatal(err)
+		}
+	}
+	lhs := store.LookupReplica(roachpb.RKey("b"), nil)
+	rhs := store.LookupReplica(roachpb.RKey("c"), nil)
+	mtc.replicateRange(lhs.RangeID, 1, 2)
+	mtc.replicateRange(rhs.RangeID, 1, 3)
+
+	storeIDs := func(desc *roachpb.RangeDescriptor) []roachpb.StoreID {
+		var ids []roachpb.StoreID
+		for _, r := range desc.Replicas {
+			ids = append(ids, r.StoreID)
+		}
+		sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+		return ids
+	}
+	lhsStores := storeIDs(lhs.Desc())
+
+	// The first pass relocates the right-hand range onto the stores of the
+	// left-hand range.
+	testutils.SucceedsSoon(t, func() error {
+		if err := store.MergeQueueProcess(ctx, lhs); err != nil {
+			return err
+		}
+		if rhsStores := storeIDs(rhs.Desc()); !reflect.DeepEqual(rhsStores, lhsStores) {
+			return errors.Errorf("expected r%d on stores %v, got %v", rhs.RangeID, lhsStores, rhsStores)
+		}
+		return nil
+	})
+	if desc := lhs.Desc(); !desc.EndKey.Equal(roachpb.RKey("c")) {
+		t.Fatalf("unexpected merge before relocation: %s", lhs)
+	}
+
+	// A later pass merges the two ranges.
+	testutils.SucceedsSoon(t, func() error {
+		if err := store.MergeQueueProcess(ctx, lhs); err != nil {
+			return err
+		}
+		if desc := lhs.Desc(); !desc.EndKey.Equal(roachpb.RKey("d")) {
+			return errors.Errorf("[b, d) not merged: %s", lhs)
+		}
+		return nil
+	})
+	if mergedStores := storeIDs(lhs.Desc()); !reflect.DeepEqual(mergedStores, lhsStores) {
+		t.Fatalf("expected the merged range on stores %v, got %v", lhsStores, mergedStores)
+	}
+}
+
 func BenchmarkStoreRangeMerge(b *testing.B) {
 	defer tracing.Disable()()
 	storeCfg := storage.TestStoreConfig(nil)
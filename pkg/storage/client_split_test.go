@@ -201,6 +201,43 @@ func TestStoreRangeSplitInsideRow(t *testing.T) {
 	}
 }
 
+// TestStoreRangeSplitOutsideBounds verifies that a split key which is
+// contained in the range but precedes its start key once the column family
+// suffix is stripped is rejected.
+func TestStoreRangeSplitOutsideBounds(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	storeCfg := storage.TestStoreConfig(nil)
+	storeCfg.TestingKnobs.DisableSplitQueue = true
+	stopper := stop.NewStopper()
+	defer stopper.Stop(context.TODO())
+	store := createTestStoreWithConfig(t, stopper, storeCfg)
+
+	indexKey := encoding.EncodeUvarintAscending(keys.MakeTablePrefix(keys.MaxReservedDescID+1), 1)
+	rowKey := roachpb.Key(encoding.EncodeUvarintAscending(append([]byte(nil), indexKey...), 100))
+	args := adminSplitArgs(rowKey, rowKey)
+	if _, pErr := client.SendWrapped(context.Background(), rg1(store), args); pErr != nil {
+		t.Fatalf("%s: split unexpected error: %s", rowKey, pErr)
+	}
+
+	// The last byte of badKey looks like a column family suffix of length one,
+	// so stripping it leaves indexKey, which precedes the range start key.
+	badKey := encoding.EncodeUvarintAscending(append([]byte(nil), rowKey...), 1)
+	if safeKey, err := keys.EnsureSafeSplitKey(badKey); err != nil {
+		t.Fatal(err)
+	} else if !safeKey.Equal(indexKey) {
+		t.Fatalf("expected %s to be truncated to %s, got %s",
+			roachpb.Key(badKey), roachpb.Key(indexKey), safeKey)
+	}
+	args = adminSplitArgs(badKey, badKey)
+	_, pErr := client.SendWrapped(context.Background(), rg1(store), args)
+	if !testutils.IsPError(pErr, "outside of its bounds") {
+		t.Fatalf("%s: expected split to be rejected, got %v", roachpb.Key(badKey), pErr)
+	}
+	if startKey := store.LookupReplica(roachpb.RKey(rowKey), nil).Desc().StartKey; !startKey.Equal(rowKey) {
+		t.Fatalf("expected range to start at %s, found %s", rowKey, startKey)
+	}
+}
+
 // TestStoreRangeSplitIntents executes a split of a range and verifies
 // that all intents are cleared and the transaction record cleaned up.
 func TestStoreRangeSplitIntents(t *testing.T) {
@@ -34,6 +34,7 @@
 #include "cockroach/pkg/storage/engine/enginepb/rocksdb.pb.h"
 #include "cockroach/pkg/storage/engine/enginepb/mvcc.pb.h"
 #include "db.h"
+#include "db_internal.h"
 #include "encoding.h"
 #include "eventlistener.h"
 
@@ -63,6 +64,7 @@ struct DBEngine {
   virtual DBStatus Get(DBKey key, DBString* value) = 0;
   virtual DBIterator* NewIter(rocksdb::ReadOptions*) = 0;
   virtual DBStatus GetStats(DBStatsResult* stats) = 0;
+  virtual DBStatus GetEncryptionStatus(DBEncryptionStatus* status) = 0;
 
   DBSSTable* GetSSTables(int* n);
   DBString GetUserProperties();
@@ -70,17 +72,20 @@ struct DBEngine {
 
 struct DBImpl : public DBEngine {
   std::unique_ptr<rocksdb::Env> memenv;
+  std::unique_ptr<EncryptedEnv> encrypted_env;
   std::unique_ptr<rocksdb::DB> rep_deleter;
   std::shared_ptr<rocksdb::Cache> block_cache;
   std::shared_ptr<DBEventListener> event_listener;
 
-  // Construct a new DBImpl from the specified DB and Env. Both the DB
-  // and Env will be deleted when the DBImpl is deleted. It is ok to
-  // pass NULL for the Env.
-  DBImpl(rocksdb::DB* r, rocksdb::Env* m, std::shared_ptr<rocksdb::Cache> bc,
+  // Construct a new DBImpl from the specified DB and Envs. The DB and
+  // Envs will be deleted when the DBImpl is deleted. It is ok to pass
+  // NULL for either Env.
+  DBImpl(rocksdb::DB* r, rocksdb::Env* m, EncryptedEnv* e,
+    std::shared_ptr<rocksdb::Cache> bc,
     std::shared_ptr<DBEventListener> event_listener)
       : DBEngine(r),
         memenv(m),
+        encrypted_env(e),
         rep_deleter(r),
         block_cache(bc),
         event_listener(event_listener) {
@@ -103,6 +108,7 @@ struct DBImpl : public DBEngine {
   virtual DBStatus Get(DBKey key, DBString* value);
   virtual DBIterator* NewIter(rocksdb::ReadOptions*);
   virtual DBStatus GetStats(DBStatsResult* stats);
+  virtual DBStatus GetEncryptionStatus(DBEncryptionStatus* status);
 };
 
 struct DBBatch : public DBEngine {
@@ -123,6 +129,7 @@ struct DBBatch : public DBEngine {
   virtual DBStatus Get(DBKey key, DBString* value);
   virtual DBIterator* NewIter(rocksdb::ReadOptions*);
   virtual DBStatus GetStats(DBStatsResult* stats);
+  virtual DBStatus GetEncryptionStatus(DBEncryptionStatus* status);
 };
 
 struct DBWriteOnlyBatch : public DBEngine {
@@ -143,6 +150,7 @@ struct DBWriteOnlyBatch : public DBEngine {
   virtual DBStatus Get(DBKey key, DBString* value);
   virtual DBIterator* NewIter(rocksdb::ReadOptions*);
   virtual DBStatus GetStats(DBStatsResult* stats);
+  virtual DBStatus GetEncryptionStatus(DBEncryptionStatus* status);
 };
 
 struct DBSnapshot : public DBEngine {
@@ -165,6 +173,7 @@ struct DBSnapshot : public DBEngine {
   virtual DBStatus Get(DBKey key, DBString* value);
   virtual DBIterator* NewIter(rocksdb::ReadOptions*);
   virtual DBStatus GetStats(DBStatsResult* stats);
+  virtual DBStatus GetEncryptionStatus(DBEncryptionStatus* status);
 };
 
 struct DBIterator {
@@ -1618,6 +1627,14 @@ rocksdb::Options DBMakeOptions(DBOptions db_opts) {
   return options;
 }
 
+// NewEncryptedEnv is overridden by the CCL implementation of encryption at
+// rest (see ccl/storageccl/engineccl/encrypted_env.cc) when CCL code is
+// linked into the binary.
+__attribute__((weak)) rocksdb::Status NewEncryptedEnv(
+    rocksdb::Env* base_env, const DBOptions& db_opts, EncryptedEnv** env) {
+  return rocksdb::Status::NotSupported("encryption at rest requires a CCL binary");
+}
+
 DBStatus DBOpen(DBEngine **db, DBSlice dir, DBOptions db_opts) {
   rocksdb::Options options = DBMakeOptions(db_opts);
 
@@ -1631,12 +1648,26 @@ DBStatus DBOpen(DBEngine **db, DBSlice dir, DBOptions db_opts) {
     options.env = memenv.get();
   }
 
+  // Wrap the env in an encrypted env if encryption at rest is enabled.
+  // This covers all the files RocksDB writes, including the WAL (and
+  // thus the raft log), sstables and the manifest.
+  std::unique_ptr<EncryptedEnv> encrypted_env;
+  if (db_opts.use_encryption) {
+    EncryptedEnv* env_ptr;
+    rocksdb::Status status = NewEncryptedEnv(options.env, db_opts, &env_ptr);
+    if (!status.ok()) {
+      return ToDBStatus(status);
+    }
+    encrypted_env.reset(env_ptr);
+    options.env = encrypted_env.get();
+  }
+
   rocksdb::DB *db_ptr;
   rocksdb::Status status = rocksdb::DB::Open(options, ToString(dir), &db_ptr);
   if (!status.ok()) {
     return ToDBStatus(status);
   }
-  *db = new DBImpl(db_ptr, memenv.release(),
+  *db = new DBImpl(db_ptr, memenv.release(), encrypted_env.release(),
       db_opts.cache != nullptr ? db_opts.cache->rep : nullptr,
       event_listener);
   return kSuccess;
@@ -1994,6 +2025,71 @@ DBStatus DBSnapshot::GetStats(DBStatsResult* stats) {
   return FmtStatus("unsupported");
 }
 
+// GetEncryptionStatus counts the live files of the database, and those
+// among them which are encrypted with the active key.
+DBStatus DBImpl::GetEncryptionStatus(DBEncryptionStatus* status) {
+  if (encrypted_env == nullptr) {
+    return FmtStatus("encryption at rest is not enabled");
+  }
+
+  // GetLiveFiles returns the names of the sstables, the current manifest
+  // and a few small metadata files, relative to the database directory.
+  std::vector<std::string> files;
+  uint64_t manifest_size;
+  rocksdb::Status s = rep->GetLiveFiles(files, &manifest_size, false /* flush_memtable */);
+  if (!s.ok()) {
+    return ToDBStatus(s);
+  }
+  for (auto& f : files) {
+    f = rep->GetName() + f;
+  }
+  rocksdb::VectorLogPtr wal_files;
+  s = rep->GetSortedWalFiles(wal_files);
+  if (!s.ok()) {
+    return ToDBStatus(s);
+  }
+  for (const auto& wal : wal_files) {
+    files.push_back(rep->GetOptions().wal_dir + wal->PathName());
+  }
+
+  const std::string active_key_id = encrypted_env->ActiveKeyID();
+  *status = DBEncryptionStatus();
+  for (const auto& f : files) {
+    uint64_t size;
+    std::string key_id;
+    s = encrypted_env->GetFileSize(f, &size);
+    if (s.ok()) {
+      s = encrypted_env->GetFileKeyID(f, &key_id);
+    }
+    if (s.IsNotFound()) {
+      // The file was deleted concurrently, e.g. by a compaction.
+      continue;
+    }
+    if (!s.ok()) {
+      return ToDBStatus(s);
+    }
+    status->total_files++;
+    status->total_bytes += size;
+    if (key_id == active_key_id) {
+      status->active_key_files++;
+      status->active_key_bytes += size;
+    }
+  }
+  return kSuccess;
+}
+
+DBStatus DBBatch::GetEncryptionStatus(DBEncryptionStatus* status) {
+  return FmtStatus("unsupported");
+}
+
+DBStatus DBWriteOnlyBatch::GetEncryptionStatus(DBEncryptionStatus* status) {
+  return FmtStatus("unsupported");
+}
+
+DBStatus DBSnapshot::GetEncryptionStatus(DBEncryptionStatus* status) {
+  return FmtStatus("unsupported");
+}
+
 DBIterator* DBNewIter(DBEngine* db, bool prefix) {
   rocksdb::ReadOptions opts;
   opts.prefix_same_as_start = prefix;
@@ -2275,6 +2371,10 @@ DBStatus DBGetStats(DBEngine* db, DBStatsResult* stats) {
   return db->GetStats(stats);
 }
 
+DBStatus DBGetEncryptionStatus(DBEngine* db, DBEncryptionStatus* status) {
+  return db->GetEncryptionStatus(status);
+}
+
 DBSSTable* DBGetSSTables(DBEngine* db, int* n) {
   return db->GetSSTables(n);
 }
@@ -2286,6 +2386,8 @@ DBString DBGetUserProperties(DBEngine* db) {
 DBStatus DBEngineAddFile(DBEngine* db, DBSlice path) {
   const std::vector<std::string> paths = { ToString(path) };
   rocksdb::IngestExternalFileOptions ifo;
+  // Copy the file instead of linking it, so that it is rewritten through the
+  // env of the database, which encrypts it if encryption at rest is enabled.
   ifo.move_files = false;
   ifo.snapshot_consistency = true;
   ifo.allow_global_seqno = false;
@@ -65,6 +65,14 @@ typedef struct DBCache DBCache;
 typedef struct DBEngine DBEngine;
 typedef struct DBIterator DBIterator;
 
+// DBEncryptionKey is a key used to encrypt files at rest. The id is
+// recorded in the header of every file encrypted with the key. An empty
+// key denotes plaintext.
+typedef struct {
+  DBSlice id;
+  DBSlice key;
+} DBEncryptionKey;
+
 // DBOptions contains local database options.
 typedef struct {
   DBCache *cache;
@@ -74,6 +82,12 @@ typedef struct {
   bool logging_enabled;
   int num_cpu;
   int max_open_files;
+  // If use_encryption is set, new files are encrypted with current_key and
+  // files encrypted with either current_key or old_key can be read.
+  // Encryption at rest is only supported by CCL builds.
+  bool use_encryption;
+  DBEncryptionKey current_key;
+  DBEncryptionKey old_key;
 } DBOptions;
 
 // Create a new cache with the specified size.
@@ -238,6 +252,21 @@ typedef struct {
 
 DBStatus DBGetStats(DBEngine* db, DBStatsResult* stats);
 
+// DBEncryptionStatus describes the live files of an encrypted database:
+// its sstables, write-ahead logs and manifest. The active_key_* fields
+// count the files encrypted with the current key (or written in plaintext
+// if there is no current key).
+typedef struct {
+  int64_t total_files;
+  int64_t total_bytes;
+  int64_t active_key_files;
+  int64_t active_key_bytes;
+} DBEncryptionStatus;
+
+// DBGetEncryptionStatus computes the encryption status of a database
+// opened with use_encryption.
+DBStatus DBGetEncryptionStatus(DBEngine* db, DBEncryptionStatus* status);
+
 typedef struct {
   int level;
   uint64_t size;
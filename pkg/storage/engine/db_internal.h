@@ -17,6 +17,7 @@
 #include "db.h"
 #include "rocksdb/iterator.h"
 #include "rocksdb/comparator.h"
+#include "rocksdb/env.h"
 #include "rocksdb/write_batch.h"
 #include "rocksdb/write_batch_base.h"
 
@@ -46,3 +47,28 @@ const ::rocksdb::Comparator* CockroachComparator();
 // Stats are only computed for keys between the given range.
 MVCCStatsResult MVCCComputeStatsInternal(
     ::rocksdb::Iterator* const iter_rep, DBKey start, DBKey end, int64_t now_nanos);
+
+// EncryptedEnv is an Env which encrypts the files it writes with its active
+// key and can read files encrypted with any of its keys as well as plaintext
+// files. It is implemented in CCL code (see
+// ccl/storageccl/engineccl/encrypted_env.cc).
+class EncryptedEnv : public ::rocksdb::EnvWrapper {
+ public:
+  explicit EncryptedEnv(::rocksdb::Env* base_env) : ::rocksdb::EnvWrapper(base_env) {}
+  virtual ~EncryptedEnv() {}
+
+  // ActiveKeyID returns the ID of the key used to encrypt new files, or the
+  // empty string if new files are written in plaintext.
+  virtual std::string ActiveKeyID() const = 0;
+
+  // GetFileKeyID stores in *id the ID of the key the named file is
+  // encrypted with, or the empty string if the file is plaintext.
+  virtual ::rocksdb::Status GetFileKeyID(const std::string& fname, std::string* id) = 0;
+};
+
+// NewEncryptedEnv returns in *env a new EncryptedEnv wrapping base_env which
+// uses the keys of db_opts. OSS builds contain a weak definition of this
+// function which returns an error, as encryption at rest is an enterprise
+// feature.
+::rocksdb::Status NewEncryptedEnv(
+    ::rocksdb::Env* base_env, const DBOptions& db_opts, EncryptedEnv** env);
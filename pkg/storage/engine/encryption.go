@@ -0,0 +1,90 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package engine
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+
+	"github.com/pkg/errors"
+)
+
+// EncryptionOptions configures the encryption at rest of a RocksDB instance.
+// Every encrypted file records the ID of the key it is encrypted with, so
+// keys can be rotated: new files are encrypted with the new key while files
+// written before the rotation remain readable with the old key until they
+// are rewritten by compactions. Encryption at rest is an enterprise feature
+// which is only available in CCL builds.
+type EncryptionOptions struct {
+	// KeyFile is the path of the file containing the key used to encrypt new
+	// files. New files are written in plaintext if it is empty.
+	KeyFile string
+	// OldKeyFile is the path of the file containing the key which was active
+	// before the last rotation. It is empty if files were previously written
+	// in plaintext.
+	OldKeyFile string
+}
+
+// EncryptionStatus describes the progress of the encryption of the live
+// files of a RocksDB instance: its sstables, write-ahead logs and manifest.
+type EncryptionStatus struct {
+	// ActiveKeyID is the ID of the key used to encrypt new files. It is empty
+	// if new files are written in plaintext.
+	ActiveKeyID string
+	// TotalFiles and TotalBytes are the number and size of the live files.
+	TotalFiles int64
+	TotalBytes int64
+	// ActiveKeyFiles and ActiveKeyBytes are the number and size of the live
+	// files which are encrypted with the active key (or are in plaintext if
+	// there is no active key). Once they are equal to TotalFiles and
+	// TotalBytes, the old key is no longer needed.
+	ActiveKeyFiles int64
+	ActiveKeyBytes int64
+}
+
+// encryptionKey is an AES key read from a key file.
+type encryptionKey struct {
+	// id identifies the key in the headers of the files it encrypts. It is
+	// the SHA-256 hash of the key.
+	id  []byte
+	key []byte
+}
+
+// String returns the hex-encoded ID of the key.
+func (k encryptionKey) String() string {
+	return hex.EncodeToString(k.id)
+}
+
+// readEncryptionKey reads the key contained in the file at the given path,
+// which must be a raw AES-128, AES-192 or AES-256 key. An empty path denotes
+// plaintext, for which an empty key is returned.
+func readEncryptionKey(path string) (encryptionKey, error) {
+	if path == "" {
+		return encryptionKey{}, nil
+	}
+	key, err := ioutil.ReadFile(path)
+	if err != nil {
+		return encryptionKey{}, errors.Wrap(err, "could not read encryption key")
+	}
+	switch len(key) {
+	case 16, 24, 32:
+	default:
+		return encryptionKey{}, errors.Errorf(
+			"encryption key %s must be 16, 24 or 32 bytes long, found %d bytes", path, len(key))
+	}
+	id := sha256.Sum256(key)
+	return encryptionKey{id: id[:], key: key}, nil
+}
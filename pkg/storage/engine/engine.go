@@ -187,6 +187,9 @@ type Engine interface {
 	Flush() error
 	// GetStats retrieves stats from the engine.
 	GetStats() (*Stats, error)
+	// GetEncryptionStatus retrieves the encryption at rest status of the
+	// engine. It returns nil if the engine is not encrypted.
+	GetEncryptionStatus() (*EncryptionStatus, error)
 	// GetTempDir returns a path under which tempdirs or tempfiles can be created.
 	GetTempDir() string
 	// NewBatch returns a new instance of a batched engine which wraps
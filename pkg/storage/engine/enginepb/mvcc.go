@@ -25,6 +25,17 @@ func (t TxnMeta) Short() string {
 	return "<nil>"
 }
 
+// IsSeqIgnored returns true if the given sequence number was rolled back
+// by the transaction, that is if it falls into one of its ignored ranges.
+func (t TxnMeta) IsSeqIgnored(seq int32) bool {
+	for _, r := range t.IgnoredSeqNums {
+		if r.Start <= seq && seq <= r.End {
+			return true
+		}
+	}
+	return false
+}
+
 // Total returns the range size as the sum of the key and value
 // bytes. This includes all non-live keys and all versioned values.
 func (ms MVCCStats) Total() int64 {
@@ -126,3 +137,15 @@ func (ms *MVCCStats) Subtract(oms MVCCStats) {
 func (meta MVCCMetadata) IsInline() bool {
 	return meta.RawBytes != nil
 }
+
+// LatestValidIntent returns the most recent value in the intent history
+// which was written at a sequence number that is not ignored by txn. The
+// returned boolean is false if there is no such value.
+func (meta MVCCMetadata) LatestValidIntent(txn TxnMeta) (MVCCMetadata_SequencedIntent, bool) {
+	for i := len(meta.IntentHistory) - 1; i >= 0; i-- {
+		if intent := meta.IntentHistory[i]; !txn.IsSeqIgnored(intent.Sequence) {
+			return intent, true
+		}
+	}
+	return MVCCMetadata_SequencedIntent{}, false
+}
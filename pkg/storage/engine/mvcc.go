@@ -734,6 +734,23 @@ func mvccGetInternal(
 					txn.Epoch, meta.Txn.Epoch)
 			}
 			seekKey = seekKey.Next()
+		} else if ownIntent && txn.IsSeqIgnored(meta.Txn.Sequence) {
+			// The intent was written at a sequence number which the txn has
+			// since rolled back to a savepoint. Read the latest value it wrote
+			// before that instead, or skip the intent if there is none.
+			if intent, ok := meta.LatestValidIntent(txn.TxnMeta); ok {
+				if len(intent.Value) == 0 {
+					// Value is deleted.
+					return nil, ignoredIntents, safeValue, nil
+				}
+				value := &buf.value
+				*value = roachpb.Value{RawBytes: intent.Value, Timestamp: meta.Timestamp}
+				if err := value.Verify(metaKey.Key); err != nil {
+					return nil, nil, safeValue, err
+				}
+				return value, ignoredIntents, safeValue, nil
+			}
+			seekKey = seekKey.Next()
 		}
 	} else if txn != nil && timestamp.Less(txn.MaxTimestamp) {
 		// In this branch, the latest timestamp is ahead, and so the read of an
@@ -1002,6 +1019,35 @@ func maybeGetValue(
 	return valueFn(exVal)
 }
 
+// mvccIntentHistory returns the intent history to store with a new
+// intent which replaces an older intent of the same txn and epoch. The
+// value of the older intent, located at versionKey, is appended to its
+// history, and all values written at sequence numbers ignored by txn are
+// dropped.
+func mvccIntentHistory(
+	iter Iterator, versionKey MVCCKey, meta *enginepb.MVCCMetadata, txn *roachpb.Transaction,
+) ([]enginepb.MVCCMetadata_SequencedIntent, error) {
+	var history []enginepb.MVCCMetadata_SequencedIntent
+	for _, intent := range meta.IntentHistory {
+		if !txn.IsSeqIgnored(intent.Sequence) {
+			history = append(history, intent)
+		}
+	}
+	if txn.IsSeqIgnored(meta.Txn.Sequence) {
+		return history, nil
+	}
+	iter.Seek(versionKey)
+	if ok, err := iter.Valid(); err != nil {
+		return nil, err
+	} else if !ok || !iter.UnsafeKey().Equal(versionKey) {
+		return nil, errors.Errorf("intent value missing for %s", versionKey)
+	}
+	return append(history, enginepb.MVCCMetadata_SequencedIntent{
+		Sequence: meta.Txn.Sequence,
+		Value:    iter.Value(),
+	}), nil
+}
+
 // mvccPutInternal adds a new timestamped value to the specified key.
 // If value is nil, creates a deletion tombstone value. valueFn is
 // an optional alternative to supplying value directly. It is passed
@@ -1060,6 +1106,7 @@ func mvccPutInternal(
 	}
 
 	var meta *enginepb.MVCCMetadata
+	var intentHistory []enginepb.MVCCMetadata_SequencedIntent
 	var maybeTooOldErr error
 	if ok {
 		// There is existing metadata for this key; ensure our write is permitted.
@@ -1091,9 +1138,18 @@ func mvccPutInternal(
 			// We are replacing our own older write intent. If we are
 			// writing at the same timestamp we can simply overwrite it;
 			// otherwise we must explicitly delete the obsolete intent.
+			versionKey := metaKey
+			versionKey.Timestamp = meta.Timestamp
+			if txn.Epoch == meta.Txn.Epoch && txn.Sequence != meta.Txn.Sequence {
+				// Keep the value of the older intent in the intent history,
+				// so that it can be restored if the txn rolls back to a
+				// savepoint taken before this write. Values written at
+				// sequence numbers which were rolled back are dropped.
+				if intentHistory, err = mvccIntentHistory(iter, versionKey, meta, txn); err != nil {
+					return err
+				}
+			}
 			if timestamp != meta.Timestamp {
-				versionKey := metaKey
-				versionKey.Timestamp = meta.Timestamp
 				if err = engine.Clear(versionKey); err != nil {
 					return err
 				}
@@ -1144,7 +1200,11 @@ func mvccPutInternal(
 		if txn != nil {
 			txnMeta = &txn.TxnMeta
 		}
-		buf.newMeta = enginepb.MVCCMetadata{Txn: txnMeta, Timestamp: timestamp}
+		buf.newMeta = enginepb.MVCCMetadata{
+			Txn:           txnMeta,
+			Timestamp:     timestamp,
+			IntentHistory: intentHistory,
+		}
 	}
 	newMeta := &buf.newMeta
 
@@ -1862,6 +1922,37 @@ func mvccResolveWriteIntent(
 	timestampsValid := !intent.Txn.Timestamp.Less(meta.Timestamp)
 	commit := intent.Status == roachpb.COMMITTED && epochsMatch && timestampsValid
 
+	if commit && intent.Txn.IsSeqIgnored(meta.Txn.Sequence) {
+		// The write of the intent was rolled back to a savepoint. If the txn
+		// wrote to the key before that, restore the latest such value before
+		// committing it; otherwise, remove the intent as if it was aborted.
+		if prev, ok := meta.LatestValidIntent(intent.Txn); ok {
+			versionKey := MVCCKey{Key: intent.Key, Timestamp: meta.Timestamp}
+			if err := engine.Put(versionKey, prev.Value); err != nil {
+				return err
+			}
+			restoredTxn := *meta.Txn
+			restoredTxn.Sequence = prev.Sequence
+			buf.newMeta = *meta
+			buf.newMeta.Txn = &restoredTxn
+			buf.newMeta.ValBytes = int64(len(prev.Value))
+			buf.newMeta.Deleted = len(prev.Value) == 0
+			buf.newMeta.IntentHistory = nil
+			metaKeySize, metaValSize, err := buf.putMeta(engine, metaKey, &buf.newMeta)
+			if err != nil {
+				return err
+			}
+			if ms != nil {
+				ms.Add(updateStatsOnPut(intent.Key, origMetaKeySize, origMetaValSize,
+					metaKeySize, metaValSize, meta, &buf.newMeta))
+			}
+			*meta = buf.newMeta
+			origMetaKeySize, origMetaValSize = metaKeySize, metaValSize
+		} else {
+			commit = false
+		}
+	}
+
 	// Note the small difference to commit epoch handling here: We allow a push
 	// from a previous epoch to move a newer intent. That's not necessary, but
 	// useful. Consider the following, where B reads at a timestamp that's
@@ -1903,8 +1994,12 @@ func mvccResolveWriteIntent(
 		var metaKeySize, metaValSize int64
 		var err error
 		if pushed {
-			// Keep intent if we're pushing timestamp.
+			// Keep intent if we're pushing timestamp. The sequence number
+			// the intent was written at is retained, as it determines
+			// whether the write is rolled back to a savepoint.
 			buf.newTxn = intent.Txn
+			buf.newTxn.Sequence = meta.Txn.Sequence
+			buf.newTxn.BatchIndex = meta.Txn.BatchIndex
 			buf.newMeta.Txn = &buf.newTxn
 			metaKeySize, metaValSize, err = buf.putMeta(engine, metaKey, &buf.newMeta)
 		} else {
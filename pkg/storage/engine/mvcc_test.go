@@ -2709,6 +2709,138 @@ func TestMVCCWriteWithSequenceAndBatchIndex(t *testing.T) {
 	}
 }
 
+// TestMVCCReadWithIgnoredSeqNums verifies that a txn reading its own
+// intent sees the latest value it wrote at a sequence number which was
+// not rolled back to a savepoint.
+func TestMVCCReadWithIgnoredSeqNums(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	engine := createTestEngine()
+	defer engine.Close()
+
+	ts := hlc.Timestamp{Logical: 1}
+	txn := *txn1
+	for i, v := range []roachpb.Value{value1, value2, value3} {
+		txn.Sequence = int32(i + 1)
+		if err := MVCCPut(context.Background(), engine, nil, testKey1, ts, v, &txn); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Delete the key at sequence 4.
+	txn.Sequence = 4
+	if err := MVCCDelete(context.Background(), engine, nil, testKey1, ts, &txn); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		ignored  []enginepb.IgnoredSeqNumRange
+		expValue *roachpb.Value
+	}{
+		{nil, nil},
+		{[]enginepb.IgnoredSeqNumRange{{Start: 4, End: 4}}, &value3},
+		{[]enginepb.IgnoredSeqNumRange{{Start: 3, End: 4}}, &value2},
+		{[]enginepb.IgnoredSeqNumRange{{Start: 2, End: 4}}, &value1},
+		{[]enginepb.IgnoredSeqNumRange{{Start: 1, End: 4}}, nil},
+		{[]enginepb.IgnoredSeqNumRange{{Start: 2, End: 2}, {Start: 4, End: 4}}, &value3},
+		{[]enginepb.IgnoredSeqNumRange{{Start: 3, End: 3}, {Start: 4, End: 4}}, &value2},
+	}
+	for i, tc := range testCases {
+		txn.IgnoredSeqNums = tc.ignored
+		value, _, err := MVCCGet(context.Background(), engine, testKey1, ts, true, &txn)
+		if err != nil {
+			t.Fatalf("%d: %s", i, err)
+		}
+		if tc.expValue == nil {
+			if value != nil {
+				t.Errorf("%d: expected no value; got %q", i, value.RawBytes)
+			}
+		} else if value == nil || !bytes.Equal(value.RawBytes, tc.expValue.RawBytes) {
+			t.Errorf("%d: expected value %q; got %+v", i, tc.expValue.RawBytes, value)
+		}
+	}
+}
+
+// TestMVCCPutWithIgnoredSeqNums verifies that the values written at
+// sequence numbers rolled back to a savepoint are dropped from the intent
+// history on the next write.
+func TestMVCCPutWithIgnoredSeqNums(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	engine := createTestEngine()
+	defer engine.Close()
+
+	ts := hlc.Timestamp{Logical: 1}
+	txn := *txn1
+	for i, v := range []roachpb.Value{value1, value2} {
+		txn.Sequence = int32(i + 1)
+		if err := MVCCPut(context.Background(), engine, nil, testKey1, ts, v, &txn); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Roll back the write at sequence 2 and write again.
+	txn.IgnoredSeqNums = []enginepb.IgnoredSeqNumRange{{Start: 2, End: 2}}
+	txn.Sequence = 3
+	if err := MVCCPut(context.Background(), engine, nil, testKey1, ts, value3, &txn); err != nil {
+		t.Fatal(err)
+	}
+
+	var meta enginepb.MVCCMetadata
+	if ok, _, _, err := engine.GetProto(MakeMVCCMetadataKey(testKey1), &meta); err != nil || !ok {
+		t.Fatalf("expected intent; got %t, %v", ok, err)
+	}
+	if len(meta.IntentHistory) != 1 || meta.IntentHistory[0].Sequence != 1 {
+		t.Fatalf("expected intent history with sequence 1 only; got %+v", meta.IntentHistory)
+	}
+
+	// Rolling back the latest write makes the first value visible again.
+	txn.IgnoredSeqNums = []enginepb.IgnoredSeqNumRange{{Start: 2, End: 3}}
+	value, _, err := MVCCGet(context.Background(), engine, testKey1, ts, true, &txn)
+	if err != nil || value == nil || !bytes.Equal(value.RawBytes, value1.RawBytes) {
+		t.Errorf("expected value %q, err nil; got %+v, %v", value1.RawBytes, value, err)
+	}
+}
+
+// TestMVCCResolveWithIgnoredSeqNums verifies that committing an intent
+// whose latest write was rolled back to a savepoint commits the latest
+// value written before the savepoint, or removes the intent if there is
+// none.
+func TestMVCCResolveWithIgnoredSeqNums(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	engine := createTestEngine()
+	defer engine.Close()
+
+	ts := hlc.Timestamp{Logical: 1}
+	txn := *txn1
+	txn.Sequence = 1
+	if err := MVCCPut(context.Background(), engine, nil, testKey1, ts, value1, &txn); err != nil {
+		t.Fatal(err)
+	}
+	txn.Sequence = 2
+	for _, key := range []roachpb.Key{testKey1, testKey2} {
+		if err := MVCCPut(context.Background(), engine, nil, key, ts, value2, &txn); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	commitTxn := txn1Commit.TxnMeta
+	commitTxn.IgnoredSeqNums = []enginepb.IgnoredSeqNumRange{{Start: 2, End: 2}}
+	num, err := MVCCResolveWriteIntentRange(context.Background(), engine, nil,
+		roachpb.Intent{Span: roachpb.Span{Key: testKey1, EndKey: testKey2.Next()}, Txn: commitTxn, Status: roachpb.COMMITTED}, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if num != 2 {
+		t.Errorf("expected 2 rows resolved; got %d", num)
+	}
+
+	value, _, err := MVCCGet(context.Background(), engine, testKey1, ts, true, nil)
+	if err != nil || value == nil || !bytes.Equal(value.RawBytes, value1.RawBytes) {
+		t.Errorf("expected value %q, err nil; got %+v, %v", value1.RawBytes, value, err)
+	}
+	value, _, err = MVCCGet(context.Background(), engine, testKey2, ts, true, nil)
+	if err != nil || value != nil {
+		t.Errorf("expected no value, err nil; got %+v, %v", value, err)
+	}
+}
+
 // TestMVCCReadWithPushedTimestamp verifies that a read for a value
 // written by the transaction, but then subsequently pushed, can still
 // be read by the txn at the later timestamp, even if an earlier
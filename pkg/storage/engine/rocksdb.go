@@ -297,6 +297,8 @@ type RocksDB struct {
 	maxSize      int64              // Used for calculating rebalancing and free space.
 	maxOpenFiles int                // The maximum number of open files this instance will use.
 	deallocated  chan struct{}      // Closed when the underlying handle is deallocated.
+	encryption   *EncryptionOptions // Encryption at rest options, if enabled.
+	activeKeyID  string             // The hex-encoded ID of the active encryption key.
 
 	commit struct {
 		syncutil.Mutex
@@ -319,6 +321,31 @@ var _ Engine = &RocksDB{}
 // needed.
 func NewRocksDB(
 	attrs roachpb.Attributes, dir string, cache RocksDBCache, maxSize int64, maxOpenFiles int,
+) (*RocksDB, error) {
+	return newRocksDB(attrs, dir, cache, maxSize, maxOpenFiles, nil /* encryption */)
+}
+
+// NewEncryptedRocksDB is like NewRocksDB, but encrypts the files of the
+// database at rest as specified by the encryption options. It returns an
+// error unless CCL code is linked into the binary.
+func NewEncryptedRocksDB(
+	attrs roachpb.Attributes,
+	dir string,
+	cache RocksDBCache,
+	maxSize int64,
+	maxOpenFiles int,
+	encryption EncryptionOptions,
+) (*RocksDB, error) {
+	return newRocksDB(attrs, dir, cache, maxSize, maxOpenFiles, &encryption)
+}
+
+func newRocksDB(
+	attrs roachpb.Attributes,
+	dir string,
+	cache RocksDBCache,
+	maxSize int64,
+	maxOpenFiles int,
+	encryption *EncryptionOptions,
 ) (*RocksDB, error) {
 	if dir == "" {
 		panic("dir must be non-empty")
@@ -331,6 +358,7 @@ func NewRocksDB(
 		maxSize:      maxSize,
 		maxOpenFiles: maxOpenFiles,
 		deallocated:  make(chan struct{}),
+		encryption:   encryption,
 	}
 
 	temp := filepath.Join(dir, "tmp")
@@ -401,16 +429,35 @@ func (r *RocksDB) open() error {
 	blockSize := envutil.EnvOrDefaultBytes("COCKROACH_ROCKSDB_BLOCK_SIZE", defaultBlockSize)
 	walTTL := envutil.EnvOrDefaultDuration("COCKROACH_ROCKSDB_WAL_TTL", 0).Seconds()
 
-	status := C.DBOpen(&r.rdb, goToCSlice([]byte(r.dir)),
-		C.DBOptions{
-			cache:             r.cache.cache,
-			block_size:        C.uint64_t(blockSize),
-			wal_ttl_seconds:   C.uint64_t(walTTL),
-			use_direct_writes: C.bool(useDirectWrites),
-			logging_enabled:   C.bool(log.V(3)),
-			num_cpu:           C.int(runtime.NumCPU()),
-			max_open_files:    C.int(r.maxOpenFiles),
-		})
+	opts := C.DBOptions{
+		cache:             r.cache.cache,
+		block_size:        C.uint64_t(blockSize),
+		wal_ttl_seconds:   C.uint64_t(walTTL),
+		use_direct_writes: C.bool(useDirectWrites),
+		logging_enabled:   C.bool(log.V(3)),
+		num_cpu:           C.int(runtime.NumCPU()),
+		max_open_files:    C.int(r.maxOpenFiles),
+	}
+	if r.encryption != nil {
+		currentKey, err := readEncryptionKey(r.encryption.KeyFile)
+		if err != nil {
+			return err
+		}
+		oldKey, err := readEncryptionKey(r.encryption.OldKeyFile)
+		if err != nil {
+			return err
+		}
+		opts.use_encryption = C.bool(true)
+		opts.current_key = goToCEncryptionKey(currentKey)
+		opts.old_key = goToCEncryptionKey(oldKey)
+		if len(currentKey.key) > 0 {
+			r.activeKeyID = currentKey.String()
+		}
+		log.Infof(context.TODO(), "encryption at rest enabled for rocksdb instance at %q, active key: %q",
+			r.dir, r.activeKeyID)
+	}
+
+	status := C.DBOpen(&r.rdb, goToCSlice([]byte(r.dir)), opts)
 	if err := statusToError(status); err != nil {
 		return errors.Errorf("could not open rocksdb instance: %s", err)
 	}
@@ -718,6 +765,25 @@ func (r *RocksDB) GetStats() (*Stats, error) {
 	}, nil
 }
 
+// GetEncryptionStatus returns the encryption at rest status of the engine,
+// or nil if the engine was not opened with encryption options.
+func (r *RocksDB) GetEncryptionStatus() (*EncryptionStatus, error) {
+	if r.encryption == nil {
+		return nil, nil
+	}
+	var s C.DBEncryptionStatus
+	if err := statusToError(C.DBGetEncryptionStatus(r.rdb, &s)); err != nil {
+		return nil, err
+	}
+	return &EncryptionStatus{
+		ActiveKeyID:    r.activeKeyID,
+		TotalFiles:     int64(s.total_files),
+		TotalBytes:     int64(s.total_bytes),
+		ActiveKeyFiles: int64(s.active_key_files),
+		ActiveKeyBytes: int64(s.active_key_bytes),
+	}, nil
+}
+
 type rocksDBSnapshot struct {
 	parent *RocksDB
 	handle *C.DBEngine
@@ -1494,6 +1560,13 @@ func goToCSlice(b []byte) C.DBSlice {
 	}
 }
 
+func goToCEncryptionKey(k encryptionKey) C.DBEncryptionKey {
+	return C.DBEncryptionKey{
+		id:  goToCSlice(k.id),
+		key: goToCSlice(k.key),
+	}
+}
+
 func goToCKey(key MVCCKey) C.DBKey {
 	return C.DBKey{
 		key:       goToCSlice(key.Key),
@@ -245,6 +245,86 @@ func openRocksDBWithVersion(t *testing.T, hasVersionFile bool, ver Version) erro
 	return err
 }
 
+func TestReadEncryptionKey(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	dir, err := ioutil.TempDir("", t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := os.RemoveAll(dir); err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	testCases := []struct {
+		keyLen      int
+		expectedErr string
+	}{
+		{16, ""},
+		{24, ""},
+		{32, ""},
+		{0, "must be 16, 24 or 32 bytes long, found 0 bytes"},
+		{31, "must be 16, 24 or 32 bytes long, found 31 bytes"},
+	}
+
+	for i, testCase := range testCases {
+		path := filepath.Join(dir, fmt.Sprintf("key%d", i))
+		if err := ioutil.WriteFile(path, make([]byte, testCase.keyLen), 0600); err != nil {
+			t.Fatal(err)
+		}
+		key, err := readEncryptionKey(path)
+		if !testutils.IsError(err, testCase.expectedErr) {
+			t.Errorf("%d: expected error '%s', actual '%v'", i, testCase.expectedErr, err)
+			continue
+		}
+		if err == nil && (len(key.key) != testCase.keyLen || len(key.id) != 32) {
+			t.Errorf("%d: unexpected key %+v", i, key)
+		}
+	}
+
+	// An empty path denotes plaintext.
+	if key, err := readEncryptionKey(""); err != nil || key.key != nil {
+		t.Errorf("expected empty key for plaintext, got %+v, %v", key, err)
+	}
+}
+
+// TestEncryptedRocksDBRequiresCCL verifies that encryption at rest can only
+// be enabled when the CCL implementation of the encrypted Env is linked in.
+func TestEncryptedRocksDBRequiresCCL(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	dir, err := ioutil.TempDir("", t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := os.RemoveAll(dir); err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	keyFile := filepath.Join(dir, "key")
+	if err := ioutil.WriteFile(keyFile, make([]byte, 16), 0600); err != nil {
+		t.Fatal(err)
+	}
+	rocksdb, err := NewEncryptedRocksDB(
+		roachpb.Attributes{},
+		filepath.Join(dir, "db"),
+		RocksDBCache{},
+		0,
+		DefaultMaxOpenFiles,
+		EncryptionOptions{KeyFile: keyFile},
+	)
+	if err == nil {
+		rocksdb.Close()
+	}
+	if !testutils.IsError(err, "encryption at rest requires a CCL binary") {
+		t.Fatalf("expected CCL error, got %v", err)
+	}
+}
+
 func TestSSTableInfosString(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 
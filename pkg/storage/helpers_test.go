@@ -26,6 +26,7 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/pkg/errors"
 	"golang.org/x/net/context"
 
 	"github.com/cockroachdb/cockroach/pkg/config"
@@ -95,6 +96,32 @@ func (s *Store) ForceSplitScanAndProcess() {
 	forceScanAndProcess(s, s.splitQueue.baseQueue)
 }
 
+// ForceMergeScanAndProcess iterates over all ranges and enqueues any that
+// may need to be merged.
+func (s *Store) ForceMergeScanAndProcess() {
+	forceScanAndProcess(s, s.mergeQueue.baseQueue)
+}
+
+// MergeQueueProcess runs the merge queue's process method on the given
+// replica, regardless of whether it would have been queued.
+func (s *Store) MergeQueueProcess(ctx context.Context, repl *Replica) error {
+	cfg, ok := s.cfg.Gossip.GetSystemConfig()
+	if !ok {
+		return errors.New("system config not available")
+	}
+	return s.mergeQueue.process(ctx, repl, cfg)
+}
+
+// ReplicaGCQueueProcess runs the replica GC queue's process method on the
+// given replica, regardless of whether it would have been queued.
+func (s *Store) ReplicaGCQueueProcess(ctx context.Context, repl *Replica) error {
+	cfg, ok := s.cfg.Gossip.GetSystemConfig()
+	if !ok {
+		return errors.New("system config not available")
+	}
+	return s.replicaGCQueue.process(ctx, repl, cfg)
+}
+
 // ForceRaftLogScanAndProcess iterates over all ranges and enqueues any that
 // need their raft logs truncated and then process each of them.
 func (s *Store) ForceRaftLogScanAndProcess() {
@@ -161,6 +188,11 @@ func (s *Store) SetSplitQueueActive(active bool) {
 	s.setSplitQueueActive(active)
 }
 
+// SetMergeQueueActive enables or disables the merge queue.
+func (s *Store) SetMergeQueueActive(active bool) {
+	s.setMergeQueueActive(active)
+}
+
 // SetRaftSnapshotQueueActive enables or disables the raft snapshot queue.
 func (s *Store) SetRaftSnapshotQueueActive(active bool) {
 	s.setRaftSnapshotQueueActive(active)
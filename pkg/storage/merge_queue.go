@@ -0,0 +1,271 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package storage
+
+import (
+	"time"
+
+	"github.com/coreos/etcd/raft"
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+
+	"github.com/cockroachdb/cockroach/pkg/config"
+	"github.com/cockroachdb/cockroach/pkg/gossip"
+	"github.com/cockroachdb/cockroach/pkg/internal/client"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+)
+
+const (
+	// mergeQueueTimerDuration is the duration between merges of queued ranges.
+	mergeQueueTimerDuration = 0 // zero duration to process merges greedily.
+
+	// mergeMaxQPSFraction is the fraction of the load-based split threshold
+	// below which the QPS of both ranges must lie for them to be merged. This
+	// keeps the merged range below the threshold so that ranges split due to
+	// load are not merged and split again in a loop.
+	mergeMaxQPSFraction = 0.5
+)
+
+// MergeQueueEnabled controls whether ranges below the minimum size of their
+// zone are automatically merged into their right-hand neighbor. It is off by
+// default, as merges are not yet safe in the presence of concurrent reads on
+// the right-hand side (see #8630) and manual splits cannot be protected from
+// being merged away.
+var MergeQueueEnabled = settings.RegisterBoolSetting(
+	"kv.range_merge.queue_enabled",
+	"if enabled, ranges smaller than the zone's range_min_bytes are merged with their right neighbor",
+	false,
+)
+
+// mergeQueue manages a queue of ranges slated to be merged with their
+// right-hand neighbor because they have fallen below the minimum size for
+// their zone.
+type mergeQueue struct {
+	*baseQueue
+	db *client.DB
+}
+
+// newMergeQueue returns a new instance of mergeQueue.
+func newMergeQueue(store *Store, db *client.DB, gossip *gossip.Gossip) *mergeQueue {
+	mq := &mergeQueue{
+		db: db,
+	}
+	mq.baseQueue = newBaseQueue(
+		"merge", mq, store, gossip,
+		queueConfig{
+			maxSize:              defaultQueueMaxSize,
+			needsLease:           true,
+			acceptsUnsplitRanges: false,
+			successes:            store.metrics.MergeQueueSuccesses,
+			failures:             store.metrics.MergeQueueFailures,
+			pending:              store.metrics.MergeQueuePending,
+			processingNanos:      store.metrics.MergeQueueProcessingNanos,
+		},
+	)
+	return mq
+}
+
+// shouldQueue determines whether a range should be queued for merging. This
+// is true if the range is smaller than the minimum size for its zone, is not
+// the last range and neither it nor its right-hand neighbor receives too much
+// load. Smaller ranges are given a higher priority.
+func (mq *mergeQueue) shouldQueue(
+	ctx context.Context, now hlc.Timestamp, repl *Replica, sysCfg config.SystemConfig,
+) (shouldQ bool, priority float64) {
+	if !MergeQueueEnabled.Get() {
+		return false, 0
+	}
+	desc := repl.Desc()
+	if !mergeableRange(desc) || sysCfg.NeedsSplit(desc.StartKey, desc.EndKey) {
+		return false, 0
+	}
+	zone, err := sysCfg.GetZoneConfigForKey(desc.StartKey)
+	if err != nil {
+		log.Error(ctx, err)
+		return false, 0
+	}
+	if zone.RangeMinBytes <= 0 {
+		return false, 0
+	}
+	size := repl.GetMVCCStats().Total()
+	if size >= zone.RangeMinBytes {
+		return false, 0
+	}
+	if tooHotToMerge(repl) {
+		return false, 0
+	}
+	if rhsRepl := repl.store.LookupReplica(desc.EndKey, nil); rhsRepl != nil && tooHotToMerge(rhsRepl) {
+		return false, 0
+	}
+	return true, 1 - float64(size)/float64(zone.RangeMinBytes)
+}
+
+// tooHotToMerge returns whether the given replica receives too much load to
+// be merged with another range. This is also the case if its requests have
+// not been tracked for long enough to tell, as happens right after a split.
+func tooHotToMerge(repl *Replica) bool {
+	threshold := SplitByLoadQPSThreshold.Get()
+	if threshold <= 0 || repl.stats == nil {
+		return false
+	}
+	qps, dur := repl.stats.avgQPS()
+	return dur < loadSplitMinStatsDuration || qps >= mergeMaxQPSFraction*float64(threshold)
+}
+
+// mergeableRange returns whether the range with the given descriptor may be
+// merged with its right-hand neighbor. The last range has no neighbor, and
+// ranges containing meta keys are never merged as their addressing is
+// special.
+func mergeableRange(desc *roachpb.RangeDescriptor) bool {
+	return !desc.EndKey.Equal(roachpb.RKeyMax) &&
+		!desc.StartKey.Less(roachpb.RKey(keys.MetaMax))
+}
+
+// process merges the range with its right-hand neighbor if the two ranges
+// together are not too large and are not separated by a zone config or table
+// boundary. If the replicas of the right-hand range are not located on the
+// same stores as those of the left-hand range, they are first relocated.
+//
+// The merge itself is only attempted once both ranges are fully replicated
+// to the same stores: every replica of both ranges must be caught up with
+// its Raft leader (which must be this store) and no snapshot may be in
+// flight for either range. Otherwise, an error is returned and the range is
+// reconsidered the next time it is queued.
+func (mq *mergeQueue) process(
+	ctx context.Context, lhsRepl *Replica, sysCfg config.SystemConfig,
+) error {
+	lhsDesc := lhsRepl.Desc()
+	if !mergeableRange(lhsDesc) {
+		return nil
+	}
+	zone, err := sysCfg.GetZoneConfigForKey(lhsDesc.StartKey)
+	if err != nil {
+		return err
+	}
+	lhsSize := lhsRepl.GetMVCCStats().Total()
+	if lhsSize >= zone.RangeMinBytes {
+		// The range has grown since it was queued.
+		return nil
+	}
+
+	var rhsDesc roachpb.RangeDescriptor
+	if err := mq.db.GetProto(
+		ctx, keys.RangeDescriptorKey(lhsDesc.EndKey), &rhsDesc,
+	); err != nil {
+		return err
+	}
+	if !rhsDesc.StartKey.Equal(lhsDesc.EndKey) {
+		return errors.Errorf("ranges are not adjacent: %s != %s", lhsDesc.EndKey, rhsDesc.StartKey)
+	}
+	if sysCfg.NeedsSplit(lhsDesc.StartKey, rhsDesc.EndKey) {
+		// The right-hand range lies in a different zone or table.
+		return nil
+	}
+
+	// AdminMerge requires the right-hand range to be present on this store.
+	// We also need its size, which we can only cheaply determine from a local
+	// replica. A range whose neighbor has no replica on this store is left as
+	// is; the neighbor's replicas are only relocated onto the stores of this
+	// range.
+	rhsRepl := lhsRepl.store.LookupReplica(rhsDesc.StartKey, nil)
+	if rhsRepl == nil || rhsRepl.RangeID != rhsDesc.RangeID {
+		log.VEventf(ctx, 2, "right-hand range r%d has no replica on this store", rhsDesc.RangeID)
+		return nil
+	}
+	if size := lhsSize + rhsRepl.GetMVCCStats().Total(); size >= zone.RangeMaxBytes {
+		log.VEventf(ctx, 2, "merged range would be too large: %d >= %d", size, zone.RangeMaxBytes)
+		return nil
+	}
+	if tooHotToMerge(lhsRepl) || tooHotToMerge(rhsRepl) {
+		log.VEventf(ctx, 2, "not merging r%d into this range due to load", rhsDesc.RangeID)
+		return nil
+	}
+
+	if !replicaSetsEqual(lhsDesc.Replicas, rhsDesc.Replicas) ||
+		!rhsRepl.ownsValidLease(lhsRepl.store.Clock().Now()) {
+		// Move the replicas of the right-hand range onto the stores of the
+		// left-hand range, with this store as the leaseholder. The lease
+		// transfer needs to happen before any replica is removed as the
+		// leaseholder might be among them.
+		targets := []roachpb.ReplicationTarget{{
+			NodeID:  lhsRepl.store.Ident.NodeID,
+			StoreID: lhsRepl.store.StoreID(),
+		}}
+		for _, r := range lhsDesc.Replicas {
+			if r.StoreID != lhsRepl.store.StoreID() {
+				targets = append(targets, roachpb.ReplicationTarget{
+					NodeID: r.NodeID, StoreID: r.StoreID,
+				})
+			}
+		}
+		log.Infof(ctx, "relocating r%d to %v before merging", rhsDesc.RangeID, targets)
+		if err := RelocateRange(ctx, mq.db, rhsDesc, targets); err != nil {
+			return errors.Wrapf(err, "unable to relocate r%d", rhsDesc.RangeID)
+		}
+		// Replicas removed from the right-hand range are cleaned up by the
+		// replica GC queue. The merge is attempted the next time the range is
+		// queued, once Raft leadership has followed the lease.
+		return nil
+	}
+
+	if err := checkMergeReady(lhsRepl, lhsDesc); err != nil {
+		return err
+	}
+	if err := checkMergeReady(rhsRepl, &rhsDesc); err != nil {
+		return err
+	}
+
+	log.Infof(ctx, "merging r%d into this range (lhs size=%d, min=%d)",
+		rhsDesc.RangeID, lhsSize, zone.RangeMinBytes)
+	if err := mq.db.AdminMerge(ctx, lhsDesc.StartKey.AsRawKey()); err != nil {
+		return errors.Wrapf(err, "unable to merge r%d into %s", rhsDesc.RangeID, lhsRepl)
+	}
+	return nil
+}
+
+// checkMergeReady returns an error unless the given replica is the Raft
+// leader of its range, every replica in desc is caught up with it and no
+// snapshot of the range is being sent. A replica which is behind could
+// otherwise receive a snapshot of the merged range while still holding its
+// old replica of the right-hand side.
+func checkMergeReady(repl *Replica, desc *roachpb.RangeDescriptor) error {
+	if repl.hasPendingSnapshot() {
+		return errors.Errorf("%s: snapshot in flight", repl)
+	}
+	raftStatus := repl.RaftStatus()
+	if raftStatus == nil || raftStatus.SoftState.RaftState != raft.StateLeader {
+		return errors.Errorf("%s: not the raft leader", repl)
+	}
+	upToDate := filterBehindReplicas(raftStatus, desc.Replicas)
+	if len(upToDate) != len(desc.Replicas) {
+		return errors.Errorf("%s: %d of %d replicas are not caught up",
+			repl, len(desc.Replicas)-len(upToDate), len(desc.Replicas))
+	}
+	return nil
+}
+
+// timer returns interval between processing successive queued merges.
+func (*mergeQueue) timer(_ time.Duration) time.Duration {
+	return mergeQueueTimerDuration
+}
+
+// purgatoryChan returns nil.
+func (*mergeQueue) purgatoryChan() <-chan struct{} {
+	return nil
+}
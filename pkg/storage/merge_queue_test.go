@@ -0,0 +1,178 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package storage
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/net/context"
+
+	"github.com/cockroachdb/cockroach/pkg/config"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/storage/engine/enginepb"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+)
+
+// TestMergeQueueShouldQueue verifies that shouldQueue only considers ranges
+// which are smaller than the minimum size of their zone and which can be
+// merged with their right-hand neighbor.
+func TestMergeQueueShouldQueue(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	tc := testContext{}
+	stopper := stop.NewStopper()
+	defer stopper.Stop(context.TODO())
+	tc.Start(t, stopper)
+
+	// Load is covered by TestMergeQueueShouldQueueLoad.
+	defer settings.TestingSetInt(&SplitByLoadQPSThreshold, 0)()
+
+	// Set zone configs.
+	config.TestingSetZoneConfig(2000, config.ZoneConfig{RangeMinBytes: 1 << 10, RangeMaxBytes: 32 << 20})
+	config.TestingSetZoneConfig(2002, config.ZoneConfig{RangeMinBytes: 1 << 10, RangeMaxBytes: 32 << 20})
+
+	tableStart := func(id uint32) roachpb.RKey {
+		return roachpb.RKey(keys.MakeTablePrefix(id))
+	}
+
+	testCases := []struct {
+		start, end roachpb.RKey
+		bytes      int64
+		enabled    bool
+		shouldQ    bool
+		priority   float64
+	}{
+		// Queue disabled.
+		{tableStart(2000), tableStart(2001), 0, false, false, 0},
+		// Empty range.
+		{tableStart(2000), tableStart(2001), 0, true, true, 1},
+		// Half of the minimum size.
+		{tableStart(2000), tableStart(2001), 1 << 9, true, true, 0.5},
+		// Exactly the minimum size.
+		{tableStart(2000), tableStart(2001), 1 << 10, true, false, 0},
+		// Last range.
+		{tableStart(2002), roachpb.RKeyMax, 0, true, false, 0},
+		// Range containing meta keys.
+		{roachpb.RKeyMin, tableStart(2000), 0, true, false, 0},
+		// Range intersected by a zone config.
+		{tableStart(2000), tableStart(2003), 0, true, false, 0},
+	}
+
+	mergeQ := newMergeQueue(tc.store, nil, tc.gossip)
+
+	cfg, ok := tc.gossip.GetSystemConfig()
+	if !ok {
+		t.Fatal("config not set")
+	}
+
+	for i, test := range testCases {
+		func() {
+			defer settings.TestingSetBool(&MergeQueueEnabled, test.enabled)()
+
+			// Create a replica for testing that is not hooked up to the store.
+			copy := *tc.repl.Desc()
+			copy.StartKey = test.start
+			copy.EndKey = test.end
+			repl, err := NewReplica(&copy, tc.store, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			repl.mu.Lock()
+			repl.mu.state.Stats = enginepb.MVCCStats{KeyBytes: test.bytes}
+			repl.mu.Unlock()
+
+			shouldQ, priority := mergeQ.shouldQueue(context.TODO(), hlc.Timestamp{}, repl, cfg)
+			if shouldQ != test.shouldQ {
+				t.Errorf("%d: should queue expected %t; got %t", i, test.shouldQ, shouldQ)
+			}
+			if math.Abs(priority-test.priority) > 0.00001 {
+				t.Errorf("%d: priority expected %f; got %f", i, test.priority, priority)
+			}
+		}()
+	}
+}
+
+// TestMergeQueueShouldQueueLoad verifies that shouldQueue does not consider
+// small ranges which receive a lot of load or whose right-hand neighbor does,
+// as they would be split based on load again right after being merged.
+func TestMergeQueueShouldQueueLoad(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	tc := testContext{}
+	stopper := stop.NewStopper()
+	defer stopper.Stop(context.TODO())
+	tc.Start(t, stopper)
+
+	defer settings.TestingSetBool(&MergeQueueEnabled, true)()
+	const threshold = 100
+	defer settings.TestingSetInt(&SplitByLoadQPSThreshold, threshold)()
+	config.TestingSetZoneConfig(2000, config.ZoneConfig{RangeMinBytes: 1 << 10, RangeMaxBytes: 32 << 20})
+
+	mergeQ := newMergeQueue(tc.store, nil, tc.gossip)
+	cfg, ok := tc.gossip.GetSystemConfig()
+	if !ok {
+		t.Fatal("config not set")
+	}
+
+	// Create an empty replica for testing that is not hooked up to the store.
+	// Its right-hand neighbor is tc.repl, which contains the rest of the
+	// keyspace.
+	copy := *tc.repl.Desc()
+	copy.StartKey = roachpb.RKey(keys.MakeTablePrefix(2000))
+	copy.EndKey = roachpb.RKey(keys.MakeTablePrefix(2001))
+	repl, err := NewReplica(&copy, tc.store, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		lhsQPS, rhsQPS float64
+		shouldQ        bool
+	}{
+		{0, 0, true},
+		{threshold * mergeMaxQPSFraction / 2, threshold * mergeMaxQPSFraction / 2, true},
+		{threshold, 0, false},
+		{threshold * mergeMaxQPSFraction * 1.2, 0, false},
+		{0, threshold, false},
+	}
+	for i, test := range testCases {
+		repl.stats.resetRequestCounts()
+		tc.repl.stats.resetRequestCounts()
+
+		// The ranges are not merged until their load has been tracked for
+		// long enough.
+		if shouldQ, _ := mergeQ.shouldQueue(context.TODO(), hlc.Timestamp{}, repl, cfg); shouldQ {
+			t.Errorf("%d: expected range with fresh stats not to be queued", i)
+		}
+
+		secs := loadSplitMinStatsDuration.Seconds()
+		for j := 0; j < int(test.lhsQPS*secs); j++ {
+			repl.stats.record(1)
+		}
+		for j := 0; j < int(test.rhsQPS*secs); j++ {
+			tc.repl.stats.record(1)
+		}
+		tc.manualClock.Increment(loadSplitMinStatsDuration.Nanoseconds())
+
+		shouldQ, _ := mergeQ.shouldQueue(context.TODO(), hlc.Timestamp{}, repl, cfg)
+		if shouldQ != test.shouldQ {
+			t.Errorf("%d: should queue expected %t; got %t", i, test.shouldQ, shouldQ)
+		}
+	}
+}
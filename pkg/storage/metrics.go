@@ -314,6 +314,18 @@ var (
 	metaGCQueueProcessingNanos = metric.Metadata{
 		Name: "queue.gc.processingnanos",
 		Help: "Nanoseconds spent processing replicas in the GC queue"}
+	metaMergeQueueSuccesses = metric.Metadata{
+		Name: "queue.merge.process.success",
+		Help: "Number of replicas successfully processed by the merge queue"}
+	metaMergeQueueFailures = metric.Metadata{
+		Name: "queue.merge.process.failure",
+		Help: "Number of replicas which failed processing in the merge queue"}
+	metaMergeQueuePending = metric.Metadata{
+		Name: "queue.merge.pending",
+		Help: "Number of pending replicas in the merge queue"}
+	metaMergeQueueProcessingNanos = metric.Metadata{
+		Name: "queue.merge.processingnanos",
+		Help: "Nanoseconds spent processing replicas in the merge queue"}
 	metaRaftLogQueueSuccesses = metric.Metadata{
 		Name: "queue.raftlog.process.success",
 		Help: "Number of replicas successfully processed by the Raft log queue"}
@@ -582,6 +594,10 @@ type StoreMetrics struct {
 	GCQueueFailures                           *metric.Counter
 	GCQueuePending                            *metric.Gauge
 	GCQueueProcessingNanos                    *metric.Counter
+	MergeQueueSuccesses                       *metric.Counter
+	MergeQueueFailures                        *metric.Counter
+	MergeQueuePending                         *metric.Gauge
+	MergeQueueProcessingNanos                 *metric.Counter
 	RaftLogQueueSuccesses                     *metric.Counter
 	RaftLogQueueFailures                      *metric.Counter
 	RaftLogQueuePending                       *metric.Gauge
@@ -758,6 +774,10 @@ func newStoreMetrics(histogramWindow time.Duration) *StoreMetrics {
 		GCQueueFailures:                           metric.NewCounter(metaGCQueueFailures),
 		GCQueuePending:                            metric.NewGauge(metaGCQueuePending),
 		GCQueueProcessingNanos:                    metric.NewCounter(metaGCQueueProcessingNanos),
+		MergeQueueSuccesses:                       metric.NewCounter(metaMergeQueueSuccesses),
+		MergeQueueFailures:                        metric.NewCounter(metaMergeQueueFailures),
+		MergeQueuePending:                         metric.NewGauge(metaMergeQueuePending),
+		MergeQueueProcessingNanos:                 metric.NewCounter(metaMergeQueueProcessingNanos),
 		RaftLogQueueSuccesses:                     metric.NewCounter(metaRaftLogQueueSuccesses),
 		RaftLogQueueFailures:                      metric.NewCounter(metaRaftLogQueueFailures),
 		RaftLogQueuePending:                       metric.NewGauge(metaRaftLogQueuePending),
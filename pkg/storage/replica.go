@@ -1397,7 +1397,13 @@ func (r *Replica) Send(
 	var br *roachpb.BatchResponse
 
 	if r.stats != nil && ba.Header.GatewayNodeID != 0 {
-		r.stats.record(ba.Header.GatewayNodeID)
+		var key roachpb.Key
+		if len(ba.Requests) > 0 {
+			if k := ba.Requests[0].GetInner().Header().Key; !keys.IsLocal(k) {
+				key = k
+			}
+		}
+		r.stats.recordRequest(ba.Header.GatewayNodeID, key)
 	}
 
 	if err := r.checkBatchRequest(ba); err != nil {
@@ -4640,6 +4646,14 @@ func (r *Replica) clearPendingSnapshotIndex() {
 	r.mu.Unlock()
 }
 
+// hasPendingSnapshot returns whether a preemptive snapshot of the replica is
+// currently being sent.
+func (r *Replica) hasPendingSnapshot() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.mu.pendingSnapshotIndex != 0
+}
+
 func (r *Replica) endKey() roachpb.RKey {
 	return r.Desc().EndKey
 }
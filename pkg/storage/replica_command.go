@@ -735,6 +735,9 @@ func evalEndTransaction(
 	if reply.Txn.Epoch < h.Txn.Epoch {
 		reply.Txn.Epoch = h.Txn.Epoch
 	}
+	// The writes the requester rolled back to savepoints must not be
+	// committed when the intents are resolved.
+	reply.Txn.IgnoredSeqNums = h.Txn.IgnoredSeqNums
 	// Take max of requested priority and existing priority. This isn't
 	// terribly useful, but we do it for completeness.
 	if reply.Txn.Priority < h.Txn.Priority {
@@ -2615,6 +2618,12 @@ func (r *Replica) adminSplitWithDescriptor(
 		log.Event(ctx, "range already split")
 		return reply, false, nil
 	}
+	// Stripping the column family suffix can move a key which was contained
+	// in the range before its start key, so check the bounds again.
+	if !desc.ContainsKey(splitKey) {
+		return reply, false, roachpb.NewErrorf("cannot split range at key %s outside of its bounds %s",
+			splitKey, desc.RSpan())
+	}
 	log.Event(ctx, "found split key")
 
 	// Create right hand side range descriptor with the newly-allocated Range ID.
@@ -213,6 +213,17 @@ func (rgcq *replicaGCQueue) process(
 		// away. But currentMember is true, so we are still a member of the
 		// subsuming range. Shut down raft processing for the former range
 		// and delete any remaining metadata, but do not delete the data.
+		//
+		// The subsuming replica on this store must apply the merge first: it
+		// needs this replica to merge in its timestamp cache (see
+		// Store.MergeRange), and removes it itself when it does.
+		if lhsRepl := repl.store.LookupReplica(desc.StartKey, nil); lhsRepl == nil ||
+			lhsRepl.RangeID != replyDesc.RangeID {
+			if log.V(1) {
+				log.Infof(ctx, "not gc'able, merge into r%d not yet applied", replyDesc.RangeID)
+			}
+			return nil
+		}
 		rgcq.metrics.RemoveReplicaCount.Inc(1)
 		if log.V(1) {
 			log.Infof(ctx, "removing merged range")
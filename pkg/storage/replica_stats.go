@@ -16,8 +16,11 @@ package storage
 
 import (
 	"math"
+	"math/rand"
+	"sort"
 	"time"
 
+	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
@@ -26,6 +29,10 @@ import (
 const (
 	replStatsRotateInterval = 5 * time.Minute
 	decayFactor             = 0.8
+
+	// replStatsKeySamples is the number of request keys sampled in order to
+	// find a split key which divides the load on a range evenly.
+	replStatsKeySamples = 64
 )
 
 type localityOracle func(roachpb.NodeID) string
@@ -35,7 +42,9 @@ type perLocalityCounts map[string]float64
 
 // replicaStats maintains statistics about the work done by a replica. Its
 // initial use is tracking the number of requests received from each
-// cluster locality in order to inform lease transfer decisions.
+// cluster locality in order to inform lease transfer decisions. It also
+// tracks the rate of requests and a sample of the keys they address, which
+// are used to split ranges based on load.
 type replicaStats struct {
 	clock           *hlc.Clock
 	getNodeLocality localityOracle
@@ -54,6 +63,13 @@ type replicaStats struct {
 		requests   [5]perLocalityCounts
 		lastRotate time.Time
 		lastReset  time.Time
+
+		// sampledKeys is a reservoir sample of the keys addressed by recorded
+		// requests. keysSeen is the number of keys offered to the reservoir,
+		// decayed along with the request counts on every rotation so that
+		// recent requests are more likely to be sampled than older ones.
+		sampledKeys []roachpb.Key
+		keysSeen    float64
 	}
 }
 
@@ -69,6 +85,12 @@ func newReplicaStats(clock *hlc.Clock, getNodeLocality localityOracle) *replicaS
 }
 
 func (rs *replicaStats) record(nodeID roachpb.NodeID) {
+	rs.recordRequest(nodeID, nil)
+}
+
+// recordRequest records a request received from the given node. If key is
+// non-nil, it is offered to the sample of request keys.
+func (rs *replicaStats) recordRequest(nodeID roachpb.NodeID, key roachpb.Key) {
 	locality := rs.getNodeLocality(nodeID)
 	now := time.Unix(0, rs.clock.PhysicalNow())
 
@@ -77,6 +99,15 @@ func (rs *replicaStats) record(nodeID roachpb.NodeID) {
 
 	rs.maybeRotateLocked(now)
 	rs.mu.requests[rs.mu.idx][locality]++
+
+	if key != nil {
+		rs.mu.keysSeen++
+		if len(rs.mu.sampledKeys) < replStatsKeySamples {
+			rs.mu.sampledKeys = append(rs.mu.sampledKeys, append(roachpb.Key(nil), key...))
+		} else if i := rand.Int63n(int64(rs.mu.keysSeen)); i < replStatsKeySamples {
+			rs.mu.sampledKeys[i] = append(roachpb.Key(nil), key...)
+		}
+	}
 }
 
 func (rs *replicaStats) maybeRotateLocked(now time.Time) {
@@ -89,6 +120,7 @@ func (rs *replicaStats) maybeRotateLocked(now time.Time) {
 func (rs *replicaStats) rotateLocked() {
 	rs.mu.idx = (rs.mu.idx + 1) % len(rs.mu.requests)
 	rs.mu.requests[rs.mu.idx] = make(perLocalityCounts)
+	rs.mu.keysSeen *= decayFactor
 }
 
 // getRequestCounts returns the current per-locality request counts and the
@@ -120,6 +152,85 @@ func (rs *replicaStats) getRequestCounts() (perLocalityCounts, time.Duration) {
 	return counts, now.Sub(rs.mu.lastReset)
 }
 
+// avgQPS returns the average number of requests per second received by the
+// replica, weighting recent requests more heavily than older ones in the same
+// way as getRequestCounts, and the amount of time over which the requests
+// were accumulated.
+func (rs *replicaStats) avgQPS() (float64, time.Duration) {
+	now := time.Unix(0, rs.clock.PhysicalNow())
+
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	rs.maybeRotateLocked(now)
+
+	fractionOfRotation := float64(now.Sub(rs.mu.lastRotate)) / float64(replStatsRotateInterval)
+
+	var sum, elapsed float64
+	for i := range rs.mu.requests {
+		requestsIdx := (rs.mu.idx + len(rs.mu.requests) - i) % len(rs.mu.requests)
+		cur := rs.mu.requests[requestsIdx]
+		if cur == nil {
+			continue
+		}
+		weight := math.Pow(decayFactor, float64(i)+fractionOfRotation)
+		for _, v := range cur {
+			sum += v * weight
+		}
+		// The current window has only been accumulating requests since the
+		// last rotation.
+		window := replStatsRotateInterval
+		if i == 0 {
+			window = now.Sub(rs.mu.lastRotate)
+		}
+		elapsed += window.Seconds() * weight
+	}
+	if elapsed == 0 {
+		return 0, 0
+	}
+	return sum / elapsed, now.Sub(rs.mu.lastReset)
+}
+
+// loadSplitKey returns a row boundary which divides the sampled request keys
+// as evenly as possible into those less than the key and those greater than or
+// equal to it. It returns nil if there are not enough samples or they all address the
+// same key, in which case splitting the range would not divide its load.
+func (rs *replicaStats) loadSplitKey() roachpb.Key {
+	rs.mu.Lock()
+	if len(rs.mu.sampledKeys) < replStatsKeySamples {
+		rs.mu.Unlock()
+		return nil
+	}
+	samples := make([]roachpb.Key, 0, len(rs.mu.sampledKeys))
+	for _, key := range rs.mu.sampledKeys {
+		// The sampled keys are the keys requests were addressed to and may fall
+		// inside a row, but a range may only be split at a row boundary.
+		if key, err := keys.EnsureSafeSplitKey(key); err == nil {
+			samples = append(samples, key)
+		}
+	}
+	rs.mu.Unlock()
+
+	sort.Slice(samples, func(i, j int) bool { return samples[i].Compare(samples[j]) < 0 })
+	var splitKey roachpb.Key
+	bestImbalance := len(samples)
+	for i := 1; i < len(samples); i++ {
+		if samples[i].Equal(samples[i-1]) {
+			continue
+		}
+		// Splitting at samples[i] puts i samples on the left-hand side.
+		imbalance := len(samples) - 2*i
+		if imbalance < 0 {
+			imbalance = -imbalance
+		}
+		if imbalance < bestImbalance {
+			bestImbalance = imbalance
+			splitKey = samples[i]
+		}
+	}
+	return splitKey
+}
+
 func (rs *replicaStats) resetRequestCounts() {
 	rs.mu.Lock()
 	defer rs.mu.Unlock()
@@ -130,4 +241,6 @@ func (rs *replicaStats) resetRequestCounts() {
 	rs.mu.requests[rs.mu.idx] = make(perLocalityCounts)
 	rs.mu.lastRotate = time.Unix(0, rs.clock.PhysicalNow())
 	rs.mu.lastReset = rs.mu.lastRotate
+	rs.mu.sampledKeys = nil
+	rs.mu.keysSeen = 0
 }
@@ -15,12 +15,15 @@
 package storage
 
 import (
+	"fmt"
 	"math"
 	"reflect"
 	"testing"
 	"time"
 
+	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
 	"github.com/kr/pretty"
@@ -341,3 +344,93 @@ func TestReplicaStatsDecaySmoothing(t *testing.T) {
 		}
 	}
 }
+
+func TestReplicaStatsQPS(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	manual := hlc.NewManualClock(123)
+	clock := hlc.NewClock(manual.UnixNano, time.Nanosecond)
+	rs := newReplicaStats(clock, func(roachpb.NodeID) string {
+		return ""
+	})
+
+	if qps, dur := rs.avgQPS(); qps != 0 || dur != 0 {
+		t.Errorf("expected no QPS, got %f over %v", qps, dur)
+	}
+
+	for i := 0; i < 100; i++ {
+		rs.record(1)
+	}
+	manual.Increment(int64(10 * time.Second))
+	qps, dur := rs.avgQPS()
+	if math.Abs(qps-10) > 0.00001 {
+		t.Errorf("expected QPS = 10, got %f", qps)
+	}
+	if dur != 10*time.Second {
+		t.Errorf("expected duration = 10s, got %v", dur)
+	}
+
+	rs.resetRequestCounts()
+	if qps, _ := rs.avgQPS(); qps != 0 {
+		t.Errorf("expected no QPS after resetting, got %f", qps)
+	}
+}
+
+func TestReplicaStatsLoadSplitKey(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	manual := hlc.NewManualClock(123)
+	clock := hlc.NewClock(manual.UnixNano, time.Nanosecond)
+	rs := newReplicaStats(clock, func(roachpb.NodeID) string {
+		return ""
+	})
+
+	// Too few samples.
+	for i := 0; i < replStatsKeySamples-1; i++ {
+		rs.recordRequest(1, roachpb.Key(fmt.Sprintf("k%02d", i)))
+	}
+	if key := rs.loadSplitKey(); key != nil {
+		t.Errorf("expected no split key, got %s", key)
+	}
+
+	// The samples are divided evenly at the median key.
+	rs.recordRequest(1, roachpb.Key(fmt.Sprintf("k%02d", replStatsKeySamples-1)))
+	expected := roachpb.Key(fmt.Sprintf("k%02d", replStatsKeySamples/2))
+	if key := rs.loadSplitKey(); !key.Equal(expected) {
+		t.Errorf("expected split key %s, got %s", expected, key)
+	}
+
+	// A single hot key can't be divided.
+	rs.resetRequestCounts()
+	for i := 0; i < 10*replStatsKeySamples; i++ {
+		rs.recordRequest(1, roachpb.Key("a"))
+	}
+	if key := rs.loadSplitKey(); key != nil {
+		t.Errorf("expected no split key, got %s", key)
+	}
+
+	// Two hot keys are divided regardless of which samples were kept.
+	rs.resetRequestCounts()
+	for i := 0; i < 10*replStatsKeySamples; i++ {
+		rs.recordRequest(1, roachpb.Key("a"))
+		rs.recordRequest(1, roachpb.Key("b"))
+	}
+	if key := rs.loadSplitKey(); !key.Equal(roachpb.Key("b")) {
+		t.Errorf("expected split key b, got %s", key)
+	}
+
+	// Keys inside a row are mapped to the start of the row.
+	rowKey := func(i int) roachpb.Key {
+		key := keys.MakeTablePrefix(keys.MaxReservedDescID + 1)
+		key = encoding.EncodeUvarintAscending(key, 1)
+		return encoding.EncodeUvarintAscending(key, uint64(i))
+	}
+	rs.resetRequestCounts()
+	for i := 0; i < replStatsKeySamples; i++ {
+		rs.recordRequest(1, keys.MakeFamilyKey(rowKey(i/2), uint32(i%2+1)))
+	}
+	expected = rowKey(replStatsKeySamples / 4)
+	if key := rs.loadSplitKey(); !key.Equal(expected) {
+		t.Errorf("expected split key %s, got %s", expected, key)
+	}
+}
@@ -178,7 +178,7 @@ func (rq *replicateQueue) shouldQueue(
 	if lease, _ := repl.getLease(); repl.IsLeaseValid(lease, now) {
 		if rq.canTransferLease() &&
 			rq.allocator.ShouldTransferLease(
-				ctx, zone.Constraints, desc.Replicas, lease.Replica.StoreID, desc.RangeID, repl.stats) {
+				ctx, zone, desc.Replicas, lease.Replica.StoreID, desc.RangeID, repl.stats) {
 			if log.V(2) {
 				log.Infof(ctx, "lease transfer needed, enqueuing")
 			}
@@ -442,7 +442,7 @@ func (rq *replicateQueue) transferLease(
 	candidates := filterBehindReplicas(repl.RaftStatus(), desc.Replicas)
 	if target := rq.allocator.TransferLeaseTarget(
 		ctx,
-		zone.Constraints,
+		zone,
 		candidates,
 		repl.store.StoreID(),
 		desc.RangeID,
@@ -526,6 +526,30 @@ func storeHasConstraint(store roachpb.StoreDescriptor, c config.Constraint) bool
 	return false
 }
 
+// storeMatchesLeasePreference returns whether a store satisfies all the
+// constraints of a lease preference. Positive constraints are treated as
+// required.
+func storeMatchesLeasePreference(
+	store roachpb.StoreDescriptor, preference config.LeasePreference,
+) bool {
+	for _, c := range preference.Constraints {
+		if storeHasConstraint(store, c) == (c.Type == config.Constraint_PROHIBITED) {
+			return false
+		}
+	}
+	return true
+}
+
+// storeHasReplica returns whether one of the replicas is on the given store.
+func storeHasReplica(storeID roachpb.StoreID, replicas []roachpb.ReplicaDescriptor) bool {
+	for _, r := range replicas {
+		if r.StoreID == storeID {
+			return true
+		}
+	}
+	return false
+}
+
 // constraintCheck returns true iff all required and prohibited constraints are
 // satisfied. Stores with attributes or localities that match the most positive
 // constraints return higher scores.
@@ -26,6 +26,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/gossip"
 	"github.com/cockroachdb/cockroach/pkg/internal/client"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 )
@@ -33,6 +34,20 @@ import (
 const (
 	// splitQueueTimerDuration is the duration between splits of queued ranges.
 	splitQueueTimerDuration = 0 // zero duration to process splits greedily.
+
+	// loadSplitMinStatsDuration is the minimum amount of time over which the
+	// requests to a range must have been tracked before it is split based on
+	// load. Since request stats are reset when a range is split, this also
+	// limits how often a range is split based on load.
+	loadSplitMinStatsDuration = time.Minute
+)
+
+// SplitByLoadQPSThreshold is the number of requests per second received by a
+// range above which the range is split in order to spread its load.
+var SplitByLoadQPSThreshold = settings.RegisterIntSetting(
+	"kv.range_split.load_qps_threshold",
+	"the QPS over which a range is split based on load; 0 disables load-based splitting",
+	2500,
 )
 
 // splitQueue manages a queue of ranges slated to be split due to size
@@ -64,7 +79,8 @@ func newSplitQueue(store *Store, db *client.DB, gossip *gossip.Gossip) *splitQue
 
 // shouldQueue determines whether a range should be queued for
 // splitting. This is true if the range is intersected by a zone config
-// prefix or if the range's size in bytes exceeds the limit for the zone.
+// prefix, if the range's size in bytes exceeds the limit for the zone or
+// if the range receives more requests than the load-based split threshold.
 func (sq *splitQueue) shouldQueue(
 	ctx context.Context, now hlc.Timestamp, repl *Replica, sysCfg config.SystemConfig,
 ) (shouldQ bool, priority float64) {
@@ -81,9 +97,31 @@ func (sq *splitQueue) shouldQueue(
 		priority += ratio
 		shouldQ = true
 	}
+
+	// Add priority based on the QPS of the range compared to the load-based
+	// split threshold.
+	if ratio := loadSplitRatio(repl); ratio > 1 {
+		priority += ratio
+		shouldQ = true
+	}
 	return
 }
 
+// loadSplitRatio returns the ratio of the QPS received by the replica to the
+// load-based split threshold, or 0 if load-based splitting is disabled or the
+// replica's requests have not been tracked for long enough.
+func loadSplitRatio(repl *Replica) float64 {
+	threshold := SplitByLoadQPSThreshold.Get()
+	if threshold <= 0 || repl.stats == nil {
+		return 0
+	}
+	qps, dur := repl.stats.avgQPS()
+	if dur < loadSplitMinStatsDuration {
+		return 0
+	}
+	return qps / float64(threshold)
+}
+
 // process synchronously invokes admin split for each proposed split key.
 func (sq *splitQueue) process(ctx context.Context, r *Replica, sysCfg config.SystemConfig) error {
 	// First handle case of splitting due to zone config maps.
@@ -128,6 +166,29 @@ func (sq *splitQueue) process(ctx context.Context, r *Replica, sysCfg config.Sys
 			}
 			r.SetMaxBytes(zone.RangeMaxBytes)
 		}
+		return nil
+	}
+
+	// Finally handle case of splitting due to load. The split key is chosen
+	// from a sample of the keys addressed by recent requests so that both
+	// halves receive a similar share of the load, which the allocator can then
+	// spread by moving one of the leases.
+	if ratio := loadSplitRatio(r); ratio > 1 {
+		splitKey := r.stats.loadSplitKey()
+		if splitKey == nil {
+			log.VEventf(ctx, 2, "no load-based split key found (qps ratio=%.2f)", ratio)
+			return nil
+		}
+		log.Infof(ctx, "splitting due to load (qps ratio=%.2f) at key %s", ratio, splitKey)
+		if _, _, pErr := r.adminSplitWithDescriptor(
+			ctx,
+			roachpb.AdminSplitRequest{
+				SplitKey: splitKey,
+			},
+			desc,
+		); pErr != nil {
+			return errors.Wrapf(pErr.GoError(), "unable to split %s at key %q", r, splitKey)
+		}
 	}
 	return nil
 }
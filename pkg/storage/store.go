@@ -393,6 +393,7 @@ type Store struct {
 	rangeIDAlloc       *idAllocator                // Range ID allocator
 	gcQueue            *gcQueue                    // Garbage collection queue
 	splitQueue         *splitQueue                 // Range splitting queue
+	mergeQueue         *mergeQueue                 // Range merging queue
 	replicateQueue     *replicateQueue             // Replication queue
 	replicaGCQueue     *replicaGCQueue             // Replica GC queue
 	raftLogQueue       *raftLogQueue               // Raft log truncation queue
@@ -777,6 +778,8 @@ type StoreTestingKnobs struct {
 	DisableReplicateQueue bool
 	// DisableSplitQueue disables the split queue.
 	DisableSplitQueue bool
+	// DisableMergeQueue disables the merge queue.
+	DisableMergeQueue bool
 	// DisableTimeSeriesMaintenanceQueue disables the time series maintenance
 	// queue.
 	DisableTimeSeriesMaintenanceQueue bool
@@ -935,13 +938,14 @@ func NewStore(cfg StoreConfig, eng engine.Engine, nodeDesc *roachpb.NodeDescript
 		)
 		s.gcQueue = newGCQueue(s, s.cfg.Gossip)
 		s.splitQueue = newSplitQueue(s, s.db, s.cfg.Gossip)
+		s.mergeQueue = newMergeQueue(s, s.db, s.cfg.Gossip)
 		s.replicateQueue = newReplicateQueue(s, s.cfg.Gossip, s.allocator, s.cfg.Clock)
 		s.replicaGCQueue = newReplicaGCQueue(s, s.db, s.cfg.Gossip)
 		s.raftLogQueue = newRaftLogQueue(s, s.db, s.cfg.Gossip)
 		s.raftSnapshotQueue = newRaftSnapshotQueue(s, s.cfg.Gossip, s.cfg.Clock)
 		s.consistencyQueue = newConsistencyQueue(s, s.cfg.Gossip)
 		s.scanner.AddQueues(
-			s.gcQueue, s.splitQueue, s.replicateQueue, s.replicaGCQueue,
+			s.gcQueue, s.splitQueue, s.mergeQueue, s.replicateQueue, s.replicaGCQueue,
 			s.raftLogQueue, s.raftSnapshotQueue, s.consistencyQueue)
 
 		if s.cfg.TimeSeriesDataStore != nil {
@@ -961,6 +965,9 @@ func NewStore(cfg StoreConfig, eng engine.Engine, nodeDesc *roachpb.NodeDescript
 	if cfg.TestingKnobs.DisableSplitQueue {
 		s.setSplitQueueActive(false)
 	}
+	if cfg.TestingKnobs.DisableMergeQueue {
+		s.setMergeQueueActive(false)
+	}
 	if cfg.TestingKnobs.DisableTimeSeriesMaintenanceQueue {
 		s.setTimeSeriesMaintenanceQueueActive(false)
 	}
@@ -1917,6 +1924,12 @@ func (s *Store) SplitRange(ctx context.Context, origRng, newRng *Replica) error
 	// pushTxnQueue after we clear it.
 	origRng.pushTxnQueue.Clear(false /* disable */)
 
+	// Reset the request stats of the LHS, which include requests to keys that
+	// now belong to the RHS.
+	if origRng.stats != nil {
+		origRng.stats.resetRequestCounts()
+	}
+
 	if kr := s.mu.replicasByKey.ReplaceOrInsert(origRng); kr != nil {
 		return errors.Errorf("replicasByKey unexpectedly contains %s when inserting replica %s", kr, origRng)
 	}
@@ -2268,7 +2281,17 @@ func (s *Store) Capacity() (roachpb.StoreCapacity, error) {
 	capacity, err := s.engine.Capacity()
 	if err == nil {
 		capacity.RangeCount = int32(s.ReplicaCount())
-		capacity.LeaseCount = int32(s.LeaseCount())
+		now := s.cfg.Clock.Now()
+		newStoreReplicaVisitor(s).Visit(func(r *Replica) bool {
+			if r.ownsValidLease(now) {
+				capacity.LeaseCount++
+				if r.stats != nil {
+					qps, _ := r.stats.avgQPS()
+					capacity.QueriesPerSecond += qps
+				}
+			}
+			return true
+		})
 	}
 	return capacity, err
 }
@@ -4042,6 +4065,9 @@ func (s *Store) setReplicateQueueActive(active bool) {
 func (s *Store) setSplitQueueActive(active bool) {
 	s.splitQueue.SetDisabled(!active)
 }
+func (s *Store) setMergeQueueActive(active bool) {
+	s.mergeQueue.SetDisabled(!active)
+}
 func (s *Store) setTimeSeriesMaintenanceQueueActive(active bool) {
 	s.tsMaintenanceQueue.SetDisabled(!active)
 }
@@ -51,6 +51,7 @@ export function getEventInfo(e: Event$Properties): SimplifiedEvent {
     DroppedTables: string[],
     IndexName: string,
     MutationID: string,
+    SequenceName: string,
     TableName: string,
     User: string,
     ViewName: string,
@@ -93,6 +94,12 @@ export function getEventInfo(e: Event$Properties): SimplifiedEvent {
     case eventTypes.DROP_VIEW:
       content = <span>View Dropped: User {info.User} dropped view {info.ViewName}</span>;
       break;
+    case eventTypes.CREATE_SEQUENCE:
+      content = <span>Sequence Created: User {info.User} created sequence {info.SequenceName}</span>;
+      break;
+    case eventTypes.DROP_SEQUENCE:
+      content = <span>Sequence Dropped: User {info.User} dropped sequence {info.SequenceName}</span>;
+      break;
     case eventTypes.REVERSE_SCHEMA_CHANGE:
       content = <span>Schema Change Reversed: Schema change with ID {info.MutationID} was reversed.</span>;
       break;
@@ -50,6 +50,9 @@ export type LogEntriesResponseMessage = protos.cockroach.server.serverpb.LogEntr
 export type LivenessRequestMessage = protos.cockroach.server.serverpb.LivenessRequest;
 export type LivenessResponseMessage = protos.cockroach.server.serverpb.LivenessResponse;
 
+export type SessionsRequestMessage = protos.cockroach.server.serverpb.ListSessionsRequest;
+export type SessionsResponseMessage = protos.cockroach.server.serverpb.ListSessionsResponse;
+
 // API constants
 
 export const API_PREFIX = "_admin/v1";
@@ -207,3 +210,8 @@ export function getLogs(req: LogsRequestMessage, timeout?: moment.Duration): Pro
 export function getLiveness(_: LivenessRequestMessage, timeout?: moment.Duration): Promise<LivenessResponseMessage> {
   return timeoutFetch(serverpb.LivenessResponse, `${API_PREFIX}/liveness`, null, timeout);
 }
+
+// getSessions gets the SQL sessions open on all nodes of the cluster.
+export function getSessions(_req: SessionsRequestMessage, timeout?: moment.Duration): Promise<SessionsResponseMessage> {
+  return timeoutFetch(serverpb.ListSessionsResponse, `${STATUS_PREFIX}/sessions`, null, timeout);
+}
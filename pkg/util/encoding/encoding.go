@@ -32,6 +32,8 @@ import (
 
 	"github.com/cockroachdb/apd"
 	"github.com/cockroachdb/cockroach/pkg/util/duration"
+	"github.com/cockroachdb/cockroach/pkg/util/ipaddr"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 )
 
 const (
@@ -69,6 +71,8 @@ const (
 	decimalNaNDesc          = decimalInfinity + 1 // NaN encoded descendingly
 	decimalTerminator       = 0x00
 
+	jsonMarker byte = decimalNaNDesc + 1
+
 	// IntMin is chosen such that the range of int tags does not overlap the
 	// ascii character set that is frequently used in testing.
 	IntMin      = 0x80
@@ -457,6 +461,7 @@ type escapes struct {
 var (
 	ascendingEscapes  = escapes{escape, escapedTerm, escaped00, escapedFF, bytesMarker}
 	descendingEscapes = escapes{^escape, ^escapedTerm, ^escaped00, ^escapedFF, bytesDescMarker}
+	jsonEscapes       = escapes{escape, escapedTerm, escaped00, escapedFF, jsonMarker}
 )
 
 // EncodeBytesAscending encodes the []byte value using an escape-based
@@ -465,7 +470,11 @@ var (
 // encoded value. The encoded bytes are append to the supplied buffer
 // and the resulting buffer is returned.
 func EncodeBytesAscending(b []byte, data []byte) []byte {
-	b = append(b, bytesMarker)
+	return encodeBytesAscendingWithMarker(b, data, bytesMarker)
+}
+
+func encodeBytesAscendingWithMarker(b []byte, data []byte, marker byte) []byte {
+	b = append(b, marker)
 	for {
 		// IndexByte is implemented by the go runtime in assembly and is
 		// much faster than looping over the bytes in the slice.
@@ -516,6 +525,42 @@ func DecodeBytesDescending(b []byte, r []byte) ([]byte, []byte, error) {
 	return b, r, err
 }
 
+// EncodeIPAddrAscending encodes an ipaddr.IPAddr value so that it sorts in
+// the order defined by ipaddr.IPAddr.Compare. The encoded bytes are appended
+// to the supplied buffer and the resulting buffer is returned.
+func EncodeIPAddrAscending(b []byte, ipAddr ipaddr.IPAddr) []byte {
+	return EncodeBytesAscending(b, ipAddr.ToBuffer(nil))
+}
+
+// EncodeIPAddrDescending is the descending version of EncodeIPAddrAscending.
+func EncodeIPAddrDescending(b []byte, ipAddr ipaddr.IPAddr) []byte {
+	return EncodeBytesDescending(b, ipAddr.ToBuffer(nil))
+}
+
+// DecodeIPAddrAscending decodes an ipaddr.IPAddr value from the input buffer
+// which was encoded using EncodeIPAddrAscending. The remainder of the input
+// buffer and the decoded value are returned.
+func DecodeIPAddrAscending(b []byte) ([]byte, ipaddr.IPAddr, error) {
+	b, data, err := DecodeBytesAscending(b, nil)
+	if err != nil {
+		return b, ipaddr.IPAddr{}, err
+	}
+	ipAddr, _, err := ipaddr.FromBuffer(data)
+	return b, ipAddr, err
+}
+
+// DecodeIPAddrDescending decodes an ipaddr.IPAddr value from the input buffer
+// which was encoded using EncodeIPAddrDescending. The remainder of the input
+// buffer and the decoded value are returned.
+func DecodeIPAddrDescending(b []byte) ([]byte, ipaddr.IPAddr, error) {
+	b, data, err := DecodeBytesDescending(b, nil)
+	if err != nil {
+		return b, ipaddr.IPAddr{}, err
+	}
+	ipAddr, _, err := ipaddr.FromBuffer(data)
+	return b, ipAddr, err
+}
+
 func decodeBytesInternal(b []byte, r []byte, e escapes, expectMarker bool) ([]byte, []byte, error) {
 	if expectMarker {
 		if len(b) == 0 || b[0] != e.marker {
@@ -571,6 +616,23 @@ func getBytesLength(b []byte, e escapes) (int, error) {
 	}
 }
 
+// EncodeJSONAscending encodes the already serialized JSON key component
+// data using the same escape-based encoding as EncodeBytesAscending, but with
+// a distinct marker. The key encodings of JSON values are only guaranteed to
+// sort equal values together; they do not follow the ordering of JSON
+// values. The encoded bytes are appended to the supplied buffer and the
+// resulting buffer is returned.
+func EncodeJSONAscending(b []byte, data []byte) []byte {
+	return encodeBytesAscendingWithMarker(b, data, jsonMarker)
+}
+
+// DecodeJSONAscending decodes a JSON key component which was encoded using
+// EncodeJSONAscending. The decoded bytes are appended to r. The remainder of
+// the input buffer and the decoded []byte are returned.
+func DecodeJSONAscending(b []byte, r []byte) ([]byte, []byte, error) {
+	return decodeBytesInternal(b, r, jsonEscapes, true)
+}
+
 // EncodeStringAscending encodes the string value using an escape-based encoding. See
 // EncodeBytes for details. The encoded bytes are append to the supplied buffer
 // and the resulting buffer is returned.
@@ -857,6 +919,10 @@ const (
 	False
 
 	SentinelType Type = 15 // Used in the Value encoding.
+	JSON         Type = 16
+	UUID         Type = 17
+	IPAddr       Type = 18
+	Array        Type = 19
 )
 
 // PeekType peeks at the type of the value encoded at the start of b.
@@ -872,6 +938,8 @@ func PeekType(b []byte) Type {
 			return Bytes
 		case m == bytesDescMarker:
 			return BytesDesc
+		case m == jsonMarker:
+			return JSON
 		case m == timeMarker:
 			return Time
 		case m == durationBigNegMarker, m == durationMarker, m == durationBigPosMarker:
@@ -930,6 +998,8 @@ func PeekLength(b []byte) (int, error) {
 		return getBytesLength(b, ascendingEscapes)
 	case bytesDescMarker:
 		return getBytesLength(b, descendingEscapes)
+	case jsonMarker:
+		return getBytesLength(b, jsonEscapes)
 	case timeMarker:
 		return GetMultiVarintLen(b, 2)
 	case durationBigNegMarker, durationMarker, durationBigPosMarker:
@@ -1013,6 +1083,13 @@ func prettyPrintFirstValue(b []byte) ([]byte, string, error) {
 			return b, "", err
 		}
 		return b, strconv.Quote(s), nil
+	case JSON:
+		var data []byte
+		b, data, err = DecodeJSONAscending(b, nil)
+		if err != nil {
+			return b, "", err
+		}
+		return b, strconv.Quote(string(data)), nil
 	case Time:
 		var t time.Time
 		b, t, err = DecodeTimeAscending(b)
@@ -1190,6 +1267,44 @@ func EncodeBytesValue(appendTo []byte, colID uint32, data []byte) []byte {
 	return append(appendTo, data...)
 }
 
+// EncodeJSONValue encodes an already serialized JSON value, appends it to the
+// supplied buffer, and returns the final buffer.
+func EncodeJSONValue(appendTo []byte, colID uint32, data []byte) []byte {
+	appendTo = encodeValueTag(appendTo, colID, JSON)
+	appendTo = EncodeNonsortingUvarint(appendTo, uint64(len(data)))
+	return append(appendTo, data...)
+}
+
+// EncodeArrayValue encodes an already serialized array value, appends it to
+// the supplied buffer, and returns the final buffer. The serialized array is
+// the number of elements followed by the value encoding of each element with
+// a zero column ID.
+func EncodeArrayValue(appendTo []byte, colID uint32, data []byte) []byte {
+	appendTo = encodeValueTag(appendTo, colID, Array)
+	appendTo = EncodeNonsortingUvarint(appendTo, uint64(len(data)))
+	return append(appendTo, data...)
+}
+
+const uuidValueEncodedLength = 16
+
+// ipAddrValueMaxEncodedLength is the size of an IPv6 address encoded by
+// ipaddr.IPAddr.ToBuffer: the family, 16 address bytes and the mask.
+const ipAddrValueMaxEncodedLength = 18
+
+// EncodeUUIDValue encodes a uuid.UUID value, appends it to the supplied buffer,
+// and returns the final buffer.
+func EncodeUUIDValue(appendTo []byte, colID uint32, u uuid.UUID) []byte {
+	appendTo = encodeValueTag(appendTo, colID, UUID)
+	return append(appendTo, u.GetBytes()...)
+}
+
+// EncodeIPAddrValue encodes an ipaddr.IPAddr value, appends it to the
+// supplied buffer, and returns the final buffer.
+func EncodeIPAddrValue(appendTo []byte, colID uint32, ipAddr ipaddr.IPAddr) []byte {
+	appendTo = encodeValueTag(appendTo, colID, IPAddr)
+	return ipAddr.ToBuffer(appendTo)
+}
+
 // EncodeTimeValue encodes a time.Time value, appends it to the supplied buffer,
 // and returns the final buffer.
 func EncodeTimeValue(appendTo []byte, colID uint32, t time.Time) []byte {
@@ -1326,6 +1441,57 @@ func DecodeBytesValue(b []byte) (remaining []byte, data []byte, err error) {
 	return b[int(i):], b[:int(i)], nil
 }
 
+// DecodeJSONValue decodes a value encoded by EncodeJSONValue.
+func DecodeJSONValue(b []byte) (remaining []byte, data []byte, err error) {
+	b, err = decodeValueTypeAssert(b, JSON)
+	if err != nil {
+		return b, nil, err
+	}
+	var i uint64
+	b, _, i, err = DecodeNonsortingUvarint(b)
+	if err != nil {
+		return b, nil, err
+	}
+	return b[int(i):], b[:int(i)], nil
+}
+
+// DecodeArrayValue decodes a value encoded by EncodeArrayValue.
+func DecodeArrayValue(b []byte) (remaining []byte, data []byte, err error) {
+	b, err = decodeValueTypeAssert(b, Array)
+	if err != nil {
+		return b, nil, err
+	}
+	var i uint64
+	b, _, i, err = DecodeNonsortingUvarint(b)
+	if err != nil {
+		return b, nil, err
+	}
+	return b[int(i):], b[:int(i)], nil
+}
+
+// DecodeUUIDValue decodes a value encoded by EncodeUUIDValue.
+func DecodeUUIDValue(b []byte) (remaining []byte, u uuid.UUID, err error) {
+	b, err = decodeValueTypeAssert(b, UUID)
+	if err != nil {
+		return b, u, err
+	}
+	if len(b) < uuidValueEncodedLength {
+		return b, u, errors.Errorf("insufficient bytes to decode uuid value: %d", len(b))
+	}
+	u, err = uuid.FromBytes(b[:uuidValueEncodedLength])
+	return b[uuidValueEncodedLength:], u, err
+}
+
+// DecodeIPAddrValue decodes a value encoded by EncodeIPAddrValue.
+func DecodeIPAddrValue(b []byte) (remaining []byte, ipAddr ipaddr.IPAddr, err error) {
+	b, err = decodeValueTypeAssert(b, IPAddr)
+	if err != nil {
+		return b, ipAddr, err
+	}
+	ipAddr, b, err = ipaddr.FromBuffer(b)
+	return b, ipAddr, err
+}
+
 // DecodeTimeValue decodes a value encoded by EncodeTimeValue.
 func DecodeTimeValue(b []byte) (remaining []byte, t time.Time, err error) {
 	b, err = decodeValueTypeAssert(b, Time)
@@ -1424,9 +1590,14 @@ func PeekValueLength(b []byte) (typeOffset int, length int, err error) {
 		return typeOffset, dataOffset + n, err
 	case Float:
 		return typeOffset, dataOffset + floatValueEncodedLength, nil
-	case Bytes, Decimal:
+	case Bytes, Decimal, JSON, Array:
 		_, n, i, err := DecodeNonsortingUvarint(b)
 		return typeOffset, dataOffset + n + int(i), err
+	case UUID:
+		return typeOffset, dataOffset + uuidValueEncodedLength, nil
+	case IPAddr:
+		_, rest, err := ipaddr.FromBuffer(b)
+		return typeOffset, dataOffset + len(b) - len(rest), err
 	case Time:
 		n, err := getMultiNonsortingVarintLen(b, 2)
 		return typeOffset, dataOffset + n, err
@@ -1456,6 +1627,12 @@ func UpperBoundValueEncodingSize(colID uint32, typ Type, size int) (int, bool) {
 			return len(encodedTag) + maxVarintSize + size, true
 		}
 		return 0, false
+	case JSON, Array:
+		return 0, false
+	case UUID:
+		return len(encodedTag) + uuidValueEncodedLength, true
+	case IPAddr:
+		return len(encodedTag) + ipAddrValueMaxEncodedLength, true
 	case Decimal:
 		if size > 0 {
 			return len(encodedTag) + maxVarintSize + upperBoundNonsortingDecimalUnscaledSize(size), true
@@ -1520,6 +1697,35 @@ func PrettyPrintValueEncoded(b []byte) ([]byte, string, error) {
 			return b, string(data), nil
 		}
 		return b, hex.EncodeToString(data), nil
+	case JSON:
+		var data []byte
+		b, data, err = DecodeJSONValue(b)
+		if err != nil {
+			return b, "", err
+		}
+		return b, string(data), nil
+	case Array:
+		var data []byte
+		b, data, err = DecodeArrayValue(b)
+		if err != nil {
+			return b, "", err
+		}
+		s, err := prettyPrintArrayValue(data)
+		return b, s, err
+	case UUID:
+		var u uuid.UUID
+		b, u, err = DecodeUUIDValue(b)
+		if err != nil {
+			return b, "", err
+		}
+		return b, u.String(), nil
+	case IPAddr:
+		var ipAddr ipaddr.IPAddr
+		b, ipAddr, err = DecodeIPAddrValue(b)
+		if err != nil {
+			return b, "", err
+		}
+		return b, ipAddr.String(), nil
 	case Time:
 		var t time.Time
 		b, t, err = DecodeTimeValue(b)
@@ -1538,3 +1744,27 @@ func PrettyPrintValueEncoded(b []byte) ([]byte, string, error) {
 		return b, "", errors.Errorf("unknown type %s", typ)
 	}
 }
+
+// prettyPrintArrayValue returns a string representation of a serialized array
+// value, as passed to EncodeArrayValue.
+func prettyPrintArrayValue(data []byte) (string, error) {
+	data, _, n, err := DecodeNonsortingUvarint(data)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i := uint64(0); i < n; i++ {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		var s string
+		data, s, err = PrettyPrintValueEncoded(data)
+		if err != nil {
+			return "", err
+		}
+		buf.WriteString(s)
+	}
+	buf.WriteByte('}')
+	return buf.String(), nil
+}
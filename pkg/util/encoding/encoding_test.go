@@ -28,8 +28,10 @@ import (
 
 	"github.com/cockroachdb/apd"
 	"github.com/cockroachdb/cockroach/pkg/util/duration"
+	"github.com/cockroachdb/cockroach/pkg/util/ipaddr"
 	"github.com/cockroachdb/cockroach/pkg/util/randutil"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 )
 
 func testBasicEncodeDecode32(
@@ -659,6 +661,53 @@ func TestEncodeDecodeUnsafeStringDescending(t *testing.T) {
 	}
 }
 
+func TestEncodeDecodeIPAddr(t *testing.T) {
+	// Listed in ascending order.
+	testCases := []string{
+		"0.0.0.0/0",
+		"10.0.0.0/8",
+		"10.0.0.1",
+		"192.168.0.1/24",
+		"255.255.255.255",
+		"::1",
+		"2001:db8::/32",
+		"2001:db8::1",
+	}
+	var lastAsc, lastDesc []byte
+	for i, s := range testCases {
+		ipAddr, err := ipaddr.ParseINet(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		asc := EncodeIPAddrAscending(nil, ipAddr)
+		desc := EncodeIPAddrDescending(nil, ipAddr)
+		if i > 0 {
+			if bytes.Compare(lastAsc, asc) >= 0 {
+				t.Errorf("%s: expected ascending encoding to sort after %x, got %x", s, lastAsc, asc)
+			}
+			if bytes.Compare(lastDesc, desc) <= 0 {
+				t.Errorf("%s: expected descending encoding to sort before %x, got %x", s, lastDesc, desc)
+			}
+		}
+		lastAsc, lastDesc = asc, desc
+
+		remaining, decoded, err := DecodeIPAddrAscending(append(asc, 'x'))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if decoded != ipAddr || !bytes.Equal(remaining, []byte{'x'}) {
+			t.Errorf("%s: ascending round trip produced %s, remaining %q", s, decoded, remaining)
+		}
+		remaining, decoded, err = DecodeIPAddrDescending(append(desc, 'x'))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if decoded != ipAddr || !bytes.Equal(remaining, []byte{'x'}) {
+			t.Errorf("%s: descending round trip produced %s, remaining %q", s, decoded, remaining)
+		}
+	}
+}
+
 func TestEncodeDecodeNull(t *testing.T) {
 	const hello = "hello"
 
@@ -860,6 +909,7 @@ func TestPeekType(t *testing.T) {
 		{EncodeDecimalDescending(nil, apd.New(0, 0)), Decimal},
 		{EncodeBytesAscending(nil, []byte("")), Bytes},
 		{EncodeBytesDescending(nil, []byte("")), BytesDesc},
+		{EncodeJSONAscending(nil, []byte("")), JSON},
 		{EncodeTimeAscending(nil, timeutil.Now()), Time},
 		{EncodeTimeDescending(nil, timeutil.Now()), Time},
 		{encodedDurationAscending, Duration},
@@ -1325,6 +1375,50 @@ func TestValueEncodeDecodeBytes(t *testing.T) {
 	}
 }
 
+func TestEncodeDecodeJSON(t *testing.T) {
+	testCases := [][]byte{
+		[]byte(""),
+		[]byte("\x00"),
+		[]byte("\x00\x01"),
+		[]byte("\xff\x00"),
+		[]byte(`{"a": [1, "b"]}`),
+	}
+	for _, c := range testCases {
+		enc := EncodeJSONAscending(nil, c)
+		enc = append(enc, "remainder"...)
+		if l, err := PeekLength(enc); err != nil {
+			t.Fatal(err)
+		} else if l != len(enc)-len("remainder") {
+			t.Errorf("%q: expected length %d, got %d", c, len(enc)-len("remainder"), l)
+		}
+		remainder, dec, err := DecodeJSONAscending(enc, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(dec, c) {
+			t.Errorf("expected %q, got %q", c, dec)
+		}
+		if string(remainder) != "remainder" {
+			t.Errorf("expected remainder %q, got %q", "remainder", remainder)
+		}
+		if _, _, err := DecodeBytesAscending(enc, nil); err == nil {
+			t.Errorf("%q: expected the JSON encoding not to decode as bytes", c)
+		}
+
+		val := EncodeJSONValue(nil, NoColumnID, c)
+		if _, l, err := PeekValueLength(val); err != nil {
+			t.Fatal(err)
+		} else if l != len(val) {
+			t.Errorf("%q: expected value length %d, got %d", c, len(val), l)
+		}
+		if _, dec, err := DecodeJSONValue(val); err != nil {
+			t.Fatal(err)
+		} else if !bytes.Equal(dec, c) {
+			t.Errorf("expected %q, got %q", c, dec)
+		}
+	}
+}
+
 func TestValueEncodeDecodeDecimal(t *testing.T) {
 	rng, seed := randutil.NewPseudoRand()
 	rd := randData{rng}
@@ -1363,6 +1457,76 @@ func TestValueEncodeDecodeTime(t *testing.T) {
 	}
 }
 
+func TestValueEncodeDecodeUUID(t *testing.T) {
+	rng, seed := randutil.NewPseudoRand()
+	tests := make([]uuid.UUID, 1000)
+	for i := range tests {
+		tests[i] = *uuid.NewPopulatedUUID(rng)
+	}
+	for _, test := range tests {
+		buf := EncodeUUIDValue(nil, NoColumnID, test)
+		remaining, x, err := DecodeUUIDValue(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(remaining) != 0 {
+			t.Errorf("seed %d: expected all bytes to be consumed but was left with %x", seed, remaining)
+		}
+		if x != test {
+			t.Errorf("seed %d: expected %v got %v", seed, test, x)
+		}
+	}
+}
+
+func TestValueEncodeDecodeIPAddr(t *testing.T) {
+	for _, s := range []string{"192.168.1.2/24", "10.0.0.1", "::1", "2001:db8::1/64"} {
+		ipAddr, err := ipaddr.ParseINet(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		buf := EncodeIPAddrValue(nil, NoColumnID, ipAddr)
+		_, l, err := PeekValueLength(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if l != len(buf) {
+			t.Errorf("%s: expected length %d got %d", s, len(buf), l)
+		}
+		remaining, x, err := DecodeIPAddrValue(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(remaining) != 0 {
+			t.Errorf("%s: expected all bytes to be consumed but was left with %x", s, remaining)
+		}
+		if x != ipAddr {
+			t.Errorf("expected %s got %s", ipAddr, x)
+		}
+	}
+}
+
+func TestValueEncodeDecodeArray(t *testing.T) {
+	data := EncodeBytesValue(EncodeNonsortingUvarint(nil, 1), NoColumnID, []byte("foo"))
+	buf := EncodeArrayValue(nil, NoColumnID, data)
+	_, l, err := PeekValueLength(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l != len(buf) {
+		t.Errorf("expected length %d got %d", len(buf), l)
+	}
+	remaining, x, err := DecodeArrayValue(append(buf, 'x'))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(remaining, []byte{'x'}) {
+		t.Errorf("expected remaining bytes %q but got %q", "x", remaining)
+	}
+	if !bytes.Equal(x, data) {
+		t.Errorf("expected %x got %x", data, x)
+	}
+}
+
 func TestValueEncodeDecodeDuration(t *testing.T) {
 	rng, seed := randutil.NewPseudoRand()
 	rd := randData{rng}
@@ -1756,6 +1920,9 @@ func TestUpperBoundValueEncodingSize(t *testing.T) {
 		{colID: 0, typ: Duration, size: 28},
 		{colID: 0, typ: Bytes, size: -1},
 		{colID: 0, typ: Bytes, width: 100, size: 110},
+		{colID: 0, typ: UUID, size: 18},
+		{colID: 0, typ: IPAddr, size: 20},
+		{colID: 0, typ: Array, size: -1},
 
 		{colID: 8, typ: True, size: 2},
 	}
@@ -1793,6 +1960,15 @@ func TestPrettyPrintValueEncoded(t *testing.T) {
 			duration.Duration{Months: 1, Days: 2, Nanos: 3}), "1mon2d3ns"},
 		{EncodeBytesValue(nil, NoColumnID, []byte{0x1, 0x2, 0xF, 0xFF}), "01020fff"},
 		{EncodeBytesValue(nil, NoColumnID, []byte("foo")), "foo"},
+		{EncodeUUIDValue(nil, NoColumnID, uuid.UUID{UUID: [16]byte{0x63, 0x61, 0x6b, 0x65,
+			0x2d, 0x69, 0x73, 0x2d, 0x61, 0x2d, 0x6c, 0x69, 0x65, 0x21, 0x21, 0x21}}),
+			"63616b65-2d69-732d-612d-6c6965212121"},
+		{EncodeIPAddrValue(nil, NoColumnID, ipaddr.IPAddr{Family: ipaddr.IPv4family,
+			Addr: [16]byte{12: 192, 13: 168, 14: 1, 15: 2}, Mask: 24}),
+			"192.168.1.2/24"},
+		{EncodeArrayValue(nil, NoColumnID, EncodeIntValue(
+			EncodeNullValue(EncodeIntValue(EncodeNonsortingUvarint(nil, 3), NoColumnID, 1), NoColumnID),
+			NoColumnID, 3)), "{1,NULL,3}"},
 	}
 	for i, test := range tests {
 		remaining, str, err := PrettyPrintValueEncoded(test.buf)
@@ -6,20 +6,21 @@ import "fmt"
 
 const (
 	_Type_name_0 = "UnknownNullNotNullIntFloatDecimalBytesBytesDescTimeDurationTrueFalse"
-	_Type_name_1 = "SentinelType"
+	_Type_name_1 = "SentinelTypeJSONUUIDIPAddrArray"
 )
 
 var (
 	_Type_index_0 = [...]uint8{0, 7, 11, 18, 21, 26, 33, 38, 47, 51, 59, 63, 68}
-	_Type_index_1 = [...]uint8{0, 12}
+	_Type_index_1 = [...]uint8{0, 12, 16, 20, 26, 31}
 )
 
 func (i Type) String() string {
 	switch {
 	case 0 <= i && i <= 11:
 		return _Type_name_0[_Type_index_0[i]:_Type_index_0[i+1]]
-	case i == 15:
-		return _Type_name_1
+	case 15 <= i && i <= 19:
+		i -= 15
+		return _Type_name_1[_Type_index_1[i]:_Type_index_1[i+1]]
 	default:
 		return fmt.Sprintf("Type(%d)", i)
 	}
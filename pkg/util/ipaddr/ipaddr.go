@@ -0,0 +1,243 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package ipaddr
+
+import (
+	"bytes"
+	"math/rand"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// IPFamily denotes which address space an IP address belongs to. The values
+// match those returned by the SQL family() builtin.
+type IPFamily byte
+
+const (
+	// IPv4family is for addresses in the IPv4 space.
+	IPv4family IPFamily = 4
+	// IPv6family is for addresses in the IPv6 space.
+	IPv6family IPFamily = 6
+)
+
+// IPAddr is an IPv4 or IPv6 address together with a subnet mask length, as
+// stored by the SQL INET type. IPv4 addresses are kept in the last four bytes
+// of Addr, so the zero value of a given family is the all-zeros address.
+type IPAddr struct {
+	Family IPFamily
+	Addr   [net.IPv6len]byte
+	Mask   byte
+}
+
+// MaxMask returns the number of bits in addresses of the given family.
+func (f IPFamily) MaxMask() byte {
+	if f == IPv4family {
+		return 8 * net.IPv4len
+	}
+	return 8 * net.IPv6len
+}
+
+// FromIP returns the IPAddr for the given net.IP, with a mask covering the
+// whole address.
+func FromIP(ip net.IP) IPAddr {
+	var ipAddr IPAddr
+	if ip4 := ip.To4(); ip4 != nil {
+		ipAddr.Family = IPv4family
+		copy(ipAddr.Addr[net.IPv6len-net.IPv4len:], ip4)
+	} else {
+		ipAddr.Family = IPv6family
+		copy(ipAddr.Addr[:], ip.To16())
+	}
+	ipAddr.Mask = ipAddr.Family.MaxMask()
+	return ipAddr
+}
+
+// ParseINet parses a postgres-style INET string, which is an address
+// optionally followed by a slash and a mask length (e.g. "10.0.0.1/8").
+func ParseINet(s string) (IPAddr, error) {
+	addr, maskStr, hasMask := s, "", false
+	if i := strings.IndexByte(s, '/'); i >= 0 {
+		addr, maskStr, hasMask = s[:i], s[i+1:], true
+	}
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return IPAddr{}, errors.Errorf("invalid IP address %q", addr)
+	}
+	ipAddr := FromIP(ip)
+	// IPv4-mapped IPv6 addresses written in IPv6 notation stay IPv6.
+	if strings.IndexByte(addr, ':') >= 0 && ipAddr.Family == IPv4family {
+		ipAddr.Family = IPv6family
+		copy(ipAddr.Addr[:], ip.To16())
+		ipAddr.Mask = ipAddr.Family.MaxMask()
+	}
+	if hasMask {
+		mask, err := strconv.ParseUint(maskStr, 10, 8)
+		if err != nil || byte(mask) > ipAddr.Family.MaxMask() {
+			return IPAddr{}, errors.Errorf("invalid mask length %q", maskStr)
+		}
+		ipAddr.Mask = byte(mask)
+	}
+	return ipAddr, nil
+}
+
+// IP returns the address as a net.IP of the family's natural length.
+func (ipAddr IPAddr) IP() net.IP {
+	if ipAddr.Family == IPv4family {
+		return net.IP(ipAddr.Addr[net.IPv6len-net.IPv4len:])
+	}
+	return net.IP(ipAddr.Addr[:])
+}
+
+// Host returns the address without its mask.
+func (ipAddr IPAddr) Host() string {
+	ip := ipAddr.IP()
+	if ipAddr.Family == IPv6family {
+		// net.IP.String prints IPv4-mapped addresses in dotted IPv4
+		// notation, which would lose the family on a round trip.
+		if ip4 := ip.To4(); ip4 != nil {
+			return "::ffff:" + ip4.String()
+		}
+	}
+	return ip.String()
+}
+
+// String implements the fmt.Stringer interface. As in postgres, the mask is
+// omitted when it covers the whole address.
+func (ipAddr IPAddr) String() string {
+	if ipAddr.Mask == ipAddr.Family.MaxMask() {
+		return ipAddr.Host()
+	}
+	return ipAddr.Host() + "/" + strconv.Itoa(int(ipAddr.Mask))
+}
+
+// Compare returns -1, 0 or 1 depending on whether ipAddr sorts before, equal
+// to or after other. IPv4 addresses sort before IPv6 addresses; within a
+// family, addresses are ordered by their bits and then by mask length.
+func (ipAddr IPAddr) Compare(other IPAddr) int {
+	if ipAddr.Family != other.Family {
+		if ipAddr.Family < other.Family {
+			return -1
+		}
+		return 1
+	}
+	if c := bytes.Compare(ipAddr.Addr[:], other.Addr[:]); c != 0 {
+		return c
+	}
+	if ipAddr.Mask < other.Mask {
+		return -1
+	} else if ipAddr.Mask > other.Mask {
+		return 1
+	}
+	return 0
+}
+
+// network returns the address with all bits past the first mask bits
+// cleared.
+func (ipAddr IPAddr) network(mask byte) [net.IPv6len]byte {
+	ip := ipAddr.IP()
+	n := ip.Mask(net.CIDRMask(int(mask), 8*len(ip)))
+	var res [net.IPv6len]byte
+	copy(res[net.IPv6len-len(n):], n)
+	return res
+}
+
+// ContainedBy returns whether ipAddr is strictly contained within the subnet
+// other, i.e. whether other has a shorter mask and the two agree on its bits.
+// This is the SQL << operator.
+func (ipAddr IPAddr) ContainedBy(other IPAddr) bool {
+	if ipAddr.Family != other.Family || ipAddr.Mask <= other.Mask {
+		return false
+	}
+	return ipAddr.network(other.Mask) == other.network(other.Mask)
+}
+
+// Contains returns whether other is strictly contained within the subnet
+// ipAddr. This is the SQL >> operator.
+func (ipAddr IPAddr) Contains(other IPAddr) bool {
+	return other.ContainedBy(ipAddr)
+}
+
+// And returns the bitwise AND of two addresses of the same family. The
+// result carries the longer of the two masks.
+func (ipAddr IPAddr) And(other IPAddr) (IPAddr, error) {
+	if ipAddr.Family != other.Family {
+		return IPAddr{}, errors.New("cannot AND inet values of different sizes")
+	}
+	res := ipAddr
+	for i := range res.Addr {
+		res.Addr[i] &= other.Addr[i]
+	}
+	if other.Mask > res.Mask {
+		res.Mask = other.Mask
+	}
+	return res, nil
+}
+
+// ToBuffer appends the binary representation of ipAddr to appendTo and
+// returns the result. The representation is the family, followed by the
+// address bytes in the family's natural length, followed by the mask.
+// Comparing two buffers bytewise yields the same order as Compare.
+func (ipAddr IPAddr) ToBuffer(appendTo []byte) []byte {
+	appendTo = append(appendTo, byte(ipAddr.Family))
+	appendTo = append(appendTo, ipAddr.IP()...)
+	return append(appendTo, ipAddr.Mask)
+}
+
+// FromBuffer decodes an IPAddr written by ToBuffer from the start of data and
+// returns it along with the remaining bytes.
+func FromBuffer(data []byte) (IPAddr, []byte, error) {
+	if len(data) < 1 {
+		return IPAddr{}, data, errors.New("insufficient bytes to decode IP address")
+	}
+	var ipAddr IPAddr
+	ipAddr.Family = IPFamily(data[0])
+	var n int
+	switch ipAddr.Family {
+	case IPv4family:
+		n = net.IPv4len
+	case IPv6family:
+		n = net.IPv6len
+	default:
+		return IPAddr{}, data, errors.Errorf("unknown IP family %d", data[0])
+	}
+	data = data[1:]
+	if len(data) < n+1 {
+		return IPAddr{}, data, errors.Errorf("insufficient bytes to decode IP address: %d", len(data))
+	}
+	copy(ipAddr.Addr[net.IPv6len-n:], data[:n])
+	ipAddr.Mask = data[n]
+	if ipAddr.Mask > ipAddr.Family.MaxMask() {
+		return IPAddr{}, data, errors.Errorf("invalid mask length %d", ipAddr.Mask)
+	}
+	return ipAddr, data[n+1:], nil
+}
+
+// RandIPAddr generates a random IPAddr, for use in tests.
+func RandIPAddr(rng *rand.Rand) IPAddr {
+	var ipAddr IPAddr
+	n := net.IPv6len
+	if rng.Intn(2) == 0 {
+		ipAddr.Family = IPv4family
+		n = net.IPv4len
+	} else {
+		ipAddr.Family = IPv6family
+	}
+	rng.Read(ipAddr.Addr[net.IPv6len-n:])
+	ipAddr.Mask = byte(rng.Intn(int(ipAddr.Family.MaxMask()) + 1))
+	return ipAddr
+}
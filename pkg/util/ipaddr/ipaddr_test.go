@@ -0,0 +1,158 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package ipaddr
+
+import (
+	"bytes"
+	"testing"
+)
+
+func mustParseINet(t *testing.T, s string) IPAddr {
+	ipAddr, err := ParseINet(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ipAddr
+}
+
+func TestParseINet(t *testing.T) {
+	testCases := []struct {
+		in     string
+		out    string
+		family IPFamily
+		mask   byte
+	}{
+		{"192.168.1.2", "192.168.1.2", IPv4family, 32},
+		{"192.168.1.2/32", "192.168.1.2", IPv4family, 32},
+		{"192.168.1.2/24", "192.168.1.2/24", IPv4family, 24},
+		{"10.0.0.0/8", "10.0.0.0/8", IPv4family, 8},
+		{"0.0.0.0/0", "0.0.0.0/0", IPv4family, 0},
+		{"::1", "::1", IPv6family, 128},
+		{"2001:db8::/32", "2001:db8::/32", IPv6family, 32},
+		{"2001:DB8:0:0:0:0:0:1", "2001:db8::1", IPv6family, 128},
+		{"::ffff:1.2.3.4", "::ffff:1.2.3.4", IPv6family, 128},
+		{"::ffff:1.2.3.4/120", "::ffff:1.2.3.4/120", IPv6family, 120},
+	}
+	for _, tc := range testCases {
+		ipAddr := mustParseINet(t, tc.in)
+		if ipAddr.Family != tc.family || ipAddr.Mask != tc.mask {
+			t.Errorf("%s: expected family %d mask %d, got %d %d",
+				tc.in, tc.family, tc.mask, ipAddr.Family, ipAddr.Mask)
+		}
+		if s := ipAddr.String(); s != tc.out {
+			t.Errorf("%s: expected %s, got %s", tc.in, tc.out, s)
+		}
+	}
+
+	for _, s := range []string{"", "1.2.3", "1.2.3.4/33", "::1/129", "1.2.3.4/", "1.2.3.4/x", "foo"} {
+		if _, err := ParseINet(s); err == nil {
+			t.Errorf("%q: expected error", s)
+		}
+	}
+}
+
+func TestIPAddrCompareAndBuffer(t *testing.T) {
+	// Listed in ascending order.
+	ordered := []string{
+		"0.0.0.0/0",
+		"0.0.0.0",
+		"10.0.0.0/8",
+		"10.0.0.0/16",
+		"10.0.0.1",
+		"192.168.1.2/24",
+		"255.255.255.255",
+		"::/0",
+		"::1",
+		"::ffff:1.2.3.4",
+		"2001:db8::/32",
+		"2001:db8::1",
+		"ffff:ffff:ffff:ffff:ffff:ffff:ffff:ffff",
+	}
+	var prev IPAddr
+	var prevBuf []byte
+	for i, s := range ordered {
+		ipAddr := mustParseINet(t, s)
+		buf := ipAddr.ToBuffer(nil)
+		decoded, rest, err := FromBuffer(append(buf, 'x'))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if decoded != ipAddr || !bytes.Equal(rest, []byte{'x'}) {
+			t.Errorf("%s: round trip produced %s, remaining %q", s, decoded, rest)
+		}
+		if c := ipAddr.Compare(ipAddr); c != 0 {
+			t.Errorf("%s: expected equal to itself, got %d", s, c)
+		}
+		if i > 0 {
+			if c := prev.Compare(ipAddr); c != -1 {
+				t.Errorf("expected %s < %s, got %d", prev, ipAddr, c)
+			}
+			if c := bytes.Compare(prevBuf, buf); c != -1 {
+				t.Errorf("expected encoding of %s < %s, got %d", prev, ipAddr, c)
+			}
+		}
+		prev, prevBuf = ipAddr, buf
+	}
+}
+
+func TestIPAddrContainment(t *testing.T) {
+	testCases := []struct {
+		a, b     string
+		expected bool
+	}{
+		{"192.168.1.5", "192.168.1.0/24", true},
+		{"192.168.1.0/24", "192.168.1.0/24", false},
+		{"192.168.1.0/24", "192.168.1.5", false},
+		{"192.168.2.5", "192.168.1.0/24", false},
+		{"10.1.2.3/16", "10.0.0.0/8", true},
+		{"10.1.2.3", "0.0.0.0/0", true},
+		{"2001:db8::1", "2001:db8::/32", true},
+		{"2001:db9::1", "2001:db8::/32", false},
+		{"::ffff:10.0.0.1", "10.0.0.0/8", false},
+	}
+	for _, tc := range testCases {
+		a, b := mustParseINet(t, tc.a), mustParseINet(t, tc.b)
+		if r := a.ContainedBy(b); r != tc.expected {
+			t.Errorf("%s << %s: expected %t, got %t", a, b, tc.expected, r)
+		}
+		if r := b.Contains(a); r != tc.expected {
+			t.Errorf("%s >> %s: expected %t, got %t", b, a, tc.expected, r)
+		}
+	}
+}
+
+func TestIPAddrAnd(t *testing.T) {
+	testCases := []struct {
+		a, b, expected string
+	}{
+		{"192.168.1.5", "255.255.255.0", "192.168.1.0"},
+		{"192.168.1.5/24", "0.0.255.255/16", "0.0.1.5/24"},
+		{"2001:db8::1", "ffff:ffff::", "2001:db8::"},
+	}
+	for _, tc := range testCases {
+		a, b := mustParseINet(t, tc.a), mustParseINet(t, tc.b)
+		r, err := a.And(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s := r.String(); s != tc.expected {
+			t.Errorf("%s & %s: expected %s, got %s", a, b, tc.expected, s)
+		}
+	}
+
+	if _, err := mustParseINet(t, "1.2.3.4").And(mustParseINet(t, "::1")); err == nil {
+		t.Error("expected error ANDing addresses of different families")
+	}
+}
@@ -0,0 +1,117 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package json
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/cockroachdb/apd"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+)
+
+// An inverted index on a JSON column holds one entry per scalar contained in
+// each document. The entry's key is made of the path leading to the scalar
+// followed by the scalar itself, all wrapped in a single escaped JSON key
+// component (see encoding.EncodeJSONAscending) so that the entry's key can be
+// skipped over to get to the primary key suffix.
+//
+// Within the component, the path is made of one string per object key and
+// one NotNull marker per array traversed. Array indexes are not part of the
+// path: containment doesn't depend on the position of elements in arrays.
+// Scalars use the same encodings as the corresponding SQL datums in keys,
+// except for NULL which is the JSON null (SQL NULLs are never indexed).
+//
+// Since every part is self-delimiting and the scalar is always last, two
+// entries are equal iff they describe the same path and scalar. A document
+// that contains another one thus has an entry for each of the other
+// document's entries, which is what allows containment queries to be
+// answered from the index.
+
+// EncodeInvertedIndexKeys returns the inverted index keys of j, each
+// prefixed by b. The keys are sorted and contain no duplicates.
+func EncodeInvertedIndexKeys(b []byte, j JSON) [][]byte {
+	inner := j.encodeInvertedIndexKeys(nil, nil)
+	sort.Slice(inner, func(i, k int) bool { return bytes.Compare(inner[i], inner[k]) < 0 })
+	keys := make([][]byte, 0, len(inner))
+	for i, k := range inner {
+		if i > 0 && bytes.Equal(k, inner[i-1]) {
+			continue
+		}
+		keys = append(keys, encoding.EncodeJSONAscending(append([]byte(nil), b...), k))
+	}
+	return keys
+}
+
+// EncodeContainingInvertedIndexKey returns a key, prefixed by b, which is
+// present in the inverted index for every document containing j. False is
+// returned if there is no such key, which is the case if j is a scalar (a
+// top-level array can contain a scalar) or if j doesn't contain any scalar
+// (e.g. {}).
+func EncodeContainingInvertedIndexKey(b []byte, j JSON) ([]byte, bool) {
+	switch j.Type() {
+	case ArrayJSONType, ObjectJSONType:
+	default:
+		return nil, false
+	}
+	keys := EncodeInvertedIndexKeys(b, j)
+	if len(keys) == 0 {
+		return nil, false
+	}
+	return keys[0], true
+}
+
+// pathFor returns a copy of path so that the callers can append to it
+// without clobbering each other's paths.
+func pathFor(path []byte) []byte {
+	return path[:len(path):len(path)]
+}
+
+func (jsonNull) encodeInvertedIndexKeys(path []byte, keys [][]byte) [][]byte {
+	return append(keys, encoding.EncodeNullAscending(pathFor(path)))
+}
+
+func (jsonFalse) encodeInvertedIndexKeys(path []byte, keys [][]byte) [][]byte {
+	return append(keys, encoding.EncodeVarintAscending(pathFor(path), 0))
+}
+
+func (jsonTrue) encodeInvertedIndexKeys(path []byte, keys [][]byte) [][]byte {
+	return append(keys, encoding.EncodeVarintAscending(pathFor(path), 1))
+}
+
+func (j jsonNumber) encodeInvertedIndexKeys(path []byte, keys [][]byte) [][]byte {
+	d := apd.Decimal(j)
+	return append(keys, encoding.EncodeDecimalAscending(pathFor(path), &d))
+}
+
+func (j jsonString) encodeInvertedIndexKeys(path []byte, keys [][]byte) [][]byte {
+	return append(keys, encoding.EncodeStringAscending(pathFor(path), string(j)))
+}
+
+func (j jsonArray) encodeInvertedIndexKeys(path []byte, keys [][]byte) [][]byte {
+	path = encoding.EncodeNotNullAscending(pathFor(path))
+	for _, e := range j {
+		keys = e.encodeInvertedIndexKeys(path, keys)
+	}
+	return keys
+}
+
+func (j jsonObject) encodeInvertedIndexKeys(path []byte, keys [][]byte) [][]byte {
+	for _, kv := range j {
+		keys = kv.v.encodeInvertedIndexKeys(
+			encoding.EncodeStringAscending(pathFor(path), string(kv.k)), keys)
+	}
+	return keys
+}
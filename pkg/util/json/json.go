@@ -0,0 +1,622 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package json implements the in-memory representation of JSON documents
+// used by the JSONB SQL type, along with their text and key encodings.
+package json
+
+import (
+	"bytes"
+	gojson "encoding/json"
+	"io"
+	"math/big"
+	"sort"
+	"strconv"
+	"unsafe"
+
+	"github.com/pkg/errors"
+
+	"github.com/cockroachdb/apd"
+)
+
+// Type represents a JSON type.
+type Type int
+
+// The JSON types, in the order in which they sort. This mirrors the
+// ordering used by Postgres for jsonb values.
+const (
+	NullJSONType Type = iota
+	StringJSONType
+	NumberJSONType
+	FalseJSONType
+	TrueJSONType
+	ArrayJSONType
+	ObjectJSONType
+)
+
+var typeName = [...]string{
+	NullJSONType:   "null",
+	StringJSONType: "string",
+	NumberJSONType: "number",
+	FalseJSONType:  "boolean",
+	TrueJSONType:   "boolean",
+	ArrayJSONType:  "array",
+	ObjectJSONType: "object",
+}
+
+// String implements the fmt.Stringer interface. The names match those
+// returned by Postgres' jsonb_typeof.
+func (t Type) String() string {
+	return typeName[t]
+}
+
+// JSON represents a JSON document. Values are immutable once constructed.
+type JSON interface {
+	// Type returns the JSON type of the receiver.
+	Type() Type
+	// Format writes the text representation of the receiver to buf.
+	Format(buf *bytes.Buffer)
+	// String returns the text representation of the receiver.
+	String() string
+	// Compare returns -1, 0 or 1 depending on whether the receiver sorts
+	// before, equal to or after other.
+	Compare(other JSON) int
+	// Size returns a lower bound on the number of bytes used by the receiver.
+	Size() uintptr
+
+	// FetchValKey returns the value associated with the given key if the
+	// receiver is an object, and nil otherwise.
+	FetchValKey(key string) JSON
+	// FetchValIdx returns the element at the given index if the receiver is
+	// an array, and nil otherwise. Negative indexes count from the end of the
+	// array.
+	FetchValIdx(idx int) JSON
+	// AsText returns the unquoted contents of a JSON string, or the text
+	// representation of any other value. The second return value is false if
+	// the receiver is the JSON null.
+	AsText() (string, bool)
+	// Exists returns whether the given string is a top-level key of the
+	// receiver if it is an object, or a string element of the receiver if it
+	// is an array.
+	Exists(key string) bool
+	// Contains returns whether the receiver contains other, following the
+	// semantics of the Postgres @> operator.
+	Contains(other JSON) bool
+
+	// format writes the text representation of the receiver. If canonical is
+	// set, numbers are written in a normalized form so that equal values
+	// have identical representations.
+	format(buf *bytes.Buffer, canonical bool)
+	// encodeInvertedIndexKeys appends the inverted index key of each scalar
+	// contained in the receiver to keys. Each key is path followed by the
+	// encoding of the scalar.
+	encodeInvertedIndexKeys(path []byte, keys [][]byte) [][]byte
+}
+
+type jsonNull struct{}
+type jsonFalse struct{}
+type jsonTrue struct{}
+type jsonNumber apd.Decimal
+type jsonString string
+type jsonArray []JSON
+
+// jsonObject is kept sorted by key and contains no duplicate keys.
+type jsonObject []jsonKeyValuePair
+
+type jsonKeyValuePair struct {
+	k jsonString
+	v JSON
+}
+
+var (
+	// NullJSONValue is the JSON null.
+	NullJSONValue JSON = jsonNull{}
+	// FalseJSONValue is the JSON false.
+	FalseJSONValue JSON = jsonFalse{}
+	// TrueJSONValue is the JSON true.
+	TrueJSONValue JSON = jsonTrue{}
+)
+
+// decimalCtx is the context used to parse JSON numbers. It matches the
+// limits used for the DECIMAL SQL type.
+var decimalCtx = &apd.Context{
+	Precision:   2000,
+	Rounding:    apd.RoundHalfUp,
+	MaxExponent: 2000,
+	MinExponent: -2000,
+	Traps:       apd.DefaultTraps,
+}
+
+// FromBool returns the JSON boolean for b.
+func FromBool(b bool) JSON {
+	if b {
+		return TrueJSONValue
+	}
+	return FalseJSONValue
+}
+
+// FromString returns a JSON string.
+func FromString(s string) JSON {
+	return jsonString(s)
+}
+
+// FromInt returns a JSON number.
+func FromInt(i int64) JSON {
+	return jsonNumber(*apd.New(i, 0))
+}
+
+// FromDecimal returns a JSON number.
+func FromDecimal(d apd.Decimal) JSON {
+	return jsonNumber(d)
+}
+
+// FromFloat64 returns a JSON number. An error is returned for infinities and
+// NaN, which have no JSON representation.
+func FromFloat64(f float64) (JSON, error) {
+	var d apd.Decimal
+	if _, err := d.SetFloat64(f); err != nil {
+		return nil, err
+	}
+	if d.Form != apd.Finite {
+		return nil, errors.Errorf("cannot convert %s to JSON", strconv.FormatFloat(f, 'g', -1, 64))
+	}
+	return jsonNumber(d), nil
+}
+
+// FromArray returns a JSON array holding the given elements.
+func FromArray(elems []JSON) JSON {
+	return jsonArray(elems)
+}
+
+// FromMap returns a JSON object holding the given key/value pairs.
+func FromMap(m map[string]JSON) JSON {
+	obj := make(jsonObject, 0, len(m))
+	for k, v := range m {
+		obj = append(obj, jsonKeyValuePair{k: jsonString(k), v: v})
+	}
+	sort.Sort(obj)
+	return obj
+}
+
+// AsArray returns the elements of j and true if j is an array, and false
+// otherwise.
+func AsArray(j JSON) ([]JSON, bool) {
+	arr, ok := j.(jsonArray)
+	return arr, ok
+}
+
+// ObjectKeys returns the keys of j in sorted order and true if j is an object,
+// and false otherwise.
+func ObjectKeys(j JSON) ([]string, bool) {
+	obj, ok := j.(jsonObject)
+	if !ok {
+		return nil, false
+	}
+	keys := make([]string, len(obj))
+	for i := range obj {
+		keys[i] = string(obj[i].k)
+	}
+	return keys, true
+}
+
+// ParseJSON parses the text representation of a JSON document.
+func ParseJSON(s string) (JSON, error) {
+	decoder := gojson.NewDecoder(bytes.NewReader([]byte(s)))
+	decoder.UseNumber()
+	var v interface{}
+	if err := decoder.Decode(&v); err != nil {
+		if err == io.EOF {
+			return nil, errors.New("unexpected end of JSON input")
+		}
+		return nil, err
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, errors.Errorf("trailing characters after JSON document: %q", s)
+	}
+	return fromGoValue(v)
+}
+
+// fromGoValue converts the output of encoding/json into a JSON.
+func fromGoValue(v interface{}) (JSON, error) {
+	switch t := v.(type) {
+	case nil:
+		return NullJSONValue, nil
+	case bool:
+		return FromBool(t), nil
+	case string:
+		return jsonString(t), nil
+	case gojson.Number:
+		var d apd.Decimal
+		if _, _, err := decimalCtx.SetString(&d, string(t)); err != nil {
+			return nil, err
+		}
+		return jsonNumber(d), nil
+	case []interface{}:
+		arr := make(jsonArray, len(t))
+		for i, e := range t {
+			var err error
+			if arr[i], err = fromGoValue(e); err != nil {
+				return nil, err
+			}
+		}
+		return arr, nil
+	case map[string]interface{}:
+		obj := make(jsonObject, 0, len(t))
+		for k, e := range t {
+			val, err := fromGoValue(e)
+			if err != nil {
+				return nil, err
+			}
+			obj = append(obj, jsonKeyValuePair{k: jsonString(k), v: val})
+		}
+		sort.Sort(obj)
+		return obj, nil
+	}
+	return nil, errors.Errorf("unexpected JSON value of type %T", v)
+}
+
+func (obj jsonObject) Len() int           { return len(obj) }
+func (obj jsonObject) Swap(i, j int)      { obj[i], obj[j] = obj[j], obj[i] }
+func (obj jsonObject) Less(i, j int) bool { return obj[i].k < obj[j].k }
+
+func (jsonNull) Type() Type   { return NullJSONType }
+func (jsonFalse) Type() Type  { return FalseJSONType }
+func (jsonTrue) Type() Type   { return TrueJSONType }
+func (jsonNumber) Type() Type { return NumberJSONType }
+func (jsonString) Type() Type { return StringJSONType }
+func (jsonArray) Type() Type  { return ArrayJSONType }
+func (jsonObject) Type() Type { return ObjectJSONType }
+
+func (j jsonNull) Format(buf *bytes.Buffer)   { j.format(buf, false) }
+func (j jsonFalse) Format(buf *bytes.Buffer)  { j.format(buf, false) }
+func (j jsonTrue) Format(buf *bytes.Buffer)   { j.format(buf, false) }
+func (j jsonNumber) Format(buf *bytes.Buffer) { j.format(buf, false) }
+func (j jsonString) Format(buf *bytes.Buffer) { j.format(buf, false) }
+func (j jsonArray) Format(buf *bytes.Buffer)  { j.format(buf, false) }
+func (j jsonObject) Format(buf *bytes.Buffer) { j.format(buf, false) }
+
+func (jsonNull) format(buf *bytes.Buffer, _ bool)  { buf.WriteString("null") }
+func (jsonFalse) format(buf *bytes.Buffer, _ bool) { buf.WriteString("false") }
+func (jsonTrue) format(buf *bytes.Buffer, _ bool)  { buf.WriteString("true") }
+
+func (j jsonNumber) format(buf *bytes.Buffer, canonical bool) {
+	d := apd.Decimal(j)
+	if canonical {
+		d.Reduce(&d)
+	}
+	buf.WriteString(d.ToStandard())
+}
+
+func (j jsonString) format(buf *bytes.Buffer, _ bool) {
+	encodeJSONString(buf, string(j))
+}
+
+func (j jsonArray) format(buf *bytes.Buffer, canonical bool) {
+	buf.WriteByte('[')
+	for i, e := range j {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		e.format(buf, canonical)
+	}
+	buf.WriteByte(']')
+}
+
+func (j jsonObject) format(buf *bytes.Buffer, canonical bool) {
+	buf.WriteByte('{')
+	for i, kv := range j {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		kv.k.format(buf, canonical)
+		buf.WriteString(": ")
+		kv.v.format(buf, canonical)
+	}
+	buf.WriteByte('}')
+}
+
+// encodeJSONString writes s as a quoted JSON string.
+func encodeJSONString(buf *bytes.Buffer, s string) {
+	// encoding/json escapes <, > and & for safe embedding in HTML, which
+	// Postgres doesn't do, so the quoting is done by hand.
+	const hex = "0123456789abcdef"
+	buf.WriteByte('"')
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch c {
+		case '"', '\\':
+			buf.WriteByte('\\')
+			buf.WriteByte(c)
+		case '\b':
+			buf.WriteString(`\b`)
+		case '\f':
+			buf.WriteString(`\f`)
+		case '\n':
+			buf.WriteString(`\n`)
+		case '\r':
+			buf.WriteString(`\r`)
+		case '\t':
+			buf.WriteString(`\t`)
+		default:
+			if c < 0x20 {
+				buf.WriteString(`\u00`)
+				buf.WriteByte(hex[c>>4])
+				buf.WriteByte(hex[c&0xf])
+			} else {
+				buf.WriteByte(c)
+			}
+		}
+	}
+	buf.WriteByte('"')
+}
+
+func asString(j JSON) string {
+	var buf bytes.Buffer
+	j.Format(&buf)
+	return buf.String()
+}
+
+func (j jsonNull) String() string   { return asString(j) }
+func (j jsonFalse) String() string  { return asString(j) }
+func (j jsonTrue) String() string   { return asString(j) }
+func (j jsonNumber) String() string { return asString(j) }
+func (j jsonString) String() string { return asString(j) }
+func (j jsonArray) String() string  { return asString(j) }
+func (j jsonObject) String() string { return asString(j) }
+
+// CanonicalString returns a text representation of j in which equal values
+// are guaranteed to be identical.
+func CanonicalString(j JSON) string {
+	var buf bytes.Buffer
+	j.format(&buf, true)
+	return buf.String()
+}
+
+// Pretty returns an indented text representation of j, as produced by
+// Postgres' jsonb_pretty.
+func Pretty(j JSON) (string, error) {
+	var buf bytes.Buffer
+	if err := gojson.Indent(&buf, []byte(j.String()), "", "    "); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+func cmpInt(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func (j jsonNull) Compare(other JSON) int  { return cmpInt(int(j.Type()), int(other.Type())) }
+func (j jsonFalse) Compare(other JSON) int { return cmpInt(int(j.Type()), int(other.Type())) }
+func (j jsonTrue) Compare(other JSON) int  { return cmpInt(int(j.Type()), int(other.Type())) }
+
+func (j jsonNumber) Compare(other JSON) int {
+	o, ok := other.(jsonNumber)
+	if !ok {
+		return cmpInt(int(j.Type()), int(other.Type()))
+	}
+	a, b := apd.Decimal(j), apd.Decimal(o)
+	return a.Cmp(&b)
+}
+
+func (j jsonString) Compare(other JSON) int {
+	o, ok := other.(jsonString)
+	if !ok {
+		return cmpInt(int(j.Type()), int(other.Type()))
+	}
+	switch {
+	case j < o:
+		return -1
+	case j > o:
+		return 1
+	}
+	return 0
+}
+
+// Compare implements the JSON interface. Longer arrays sort after shorter
+// ones; arrays of the same length are compared element by element.
+func (j jsonArray) Compare(other JSON) int {
+	o, ok := other.(jsonArray)
+	if !ok {
+		return cmpInt(int(j.Type()), int(other.Type()))
+	}
+	if c := cmpInt(len(j), len(o)); c != 0 {
+		return c
+	}
+	for i := range j {
+		if c := j[i].Compare(o[i]); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+// Compare implements the JSON interface. Objects with more keys sort after
+// objects with fewer; objects with the same number of keys are compared
+// key/value pair by key/value pair, in key order.
+func (j jsonObject) Compare(other JSON) int {
+	o, ok := other.(jsonObject)
+	if !ok {
+		return cmpInt(int(j.Type()), int(other.Type()))
+	}
+	if c := cmpInt(len(j), len(o)); c != 0 {
+		return c
+	}
+	for i := range j {
+		if c := j[i].k.Compare(o[i].k); c != 0 {
+			return c
+		}
+		if c := j[i].v.Compare(o[i].v); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+func (jsonNull) Size() uintptr  { return 0 }
+func (jsonFalse) Size() uintptr { return 0 }
+func (jsonTrue) Size() uintptr  { return 0 }
+
+func (j jsonNumber) Size() uintptr {
+	return unsafe.Sizeof(j) + uintptr(cap(j.Coeff.Bits()))*unsafe.Sizeof(big.Word(0))
+}
+
+func (j jsonString) Size() uintptr {
+	return unsafe.Sizeof(j) + uintptr(len(j))
+}
+
+func (j jsonArray) Size() uintptr {
+	sz := unsafe.Sizeof(j)
+	for _, e := range j {
+		sz += e.Size()
+	}
+	return sz
+}
+
+func (j jsonObject) Size() uintptr {
+	sz := unsafe.Sizeof(j)
+	for _, kv := range j {
+		sz += kv.k.Size() + kv.v.Size()
+	}
+	return sz
+}
+
+func (jsonNull) FetchValKey(string) JSON   { return nil }
+func (jsonFalse) FetchValKey(string) JSON  { return nil }
+func (jsonTrue) FetchValKey(string) JSON   { return nil }
+func (jsonNumber) FetchValKey(string) JSON { return nil }
+func (jsonString) FetchValKey(string) JSON { return nil }
+func (jsonArray) FetchValKey(string) JSON  { return nil }
+
+func (j jsonObject) FetchValKey(key string) JSON {
+	i := sort.Search(len(j), func(i int) bool { return string(j[i].k) >= key })
+	if i < len(j) && string(j[i].k) == key {
+		return j[i].v
+	}
+	return nil
+}
+
+func (jsonNull) FetchValIdx(int) JSON   { return nil }
+func (jsonFalse) FetchValIdx(int) JSON  { return nil }
+func (jsonTrue) FetchValIdx(int) JSON   { return nil }
+func (jsonNumber) FetchValIdx(int) JSON { return nil }
+func (jsonString) FetchValIdx(int) JSON { return nil }
+func (jsonObject) FetchValIdx(int) JSON { return nil }
+
+func (j jsonArray) FetchValIdx(idx int) JSON {
+	if idx < 0 {
+		idx += len(j)
+	}
+	if idx < 0 || idx >= len(j) {
+		return nil
+	}
+	return j[idx]
+}
+
+func (jsonNull) AsText() (string, bool)     { return "", false }
+func (j jsonFalse) AsText() (string, bool)  { return j.String(), true }
+func (j jsonTrue) AsText() (string, bool)   { return j.String(), true }
+func (j jsonNumber) AsText() (string, bool) { return j.String(), true }
+func (j jsonString) AsText() (string, bool) { return string(j), true }
+func (j jsonArray) AsText() (string, bool)  { return j.String(), true }
+func (j jsonObject) AsText() (string, bool) { return j.String(), true }
+
+func (jsonNull) Exists(string) bool   { return false }
+func (jsonFalse) Exists(string) bool  { return false }
+func (jsonTrue) Exists(string) bool   { return false }
+func (jsonNumber) Exists(string) bool { return false }
+
+func (j jsonString) Exists(key string) bool {
+	return string(j) == key
+}
+
+func (j jsonArray) Exists(key string) bool {
+	for _, e := range j {
+		if s, ok := e.(jsonString); ok && string(s) == key {
+			return true
+		}
+	}
+	return false
+}
+
+func (j jsonObject) Exists(key string) bool {
+	return j.FetchValKey(key) != nil
+}
+
+func (j jsonNull) Contains(other JSON) bool   { return j.Compare(other) == 0 }
+func (j jsonFalse) Contains(other JSON) bool  { return j.Compare(other) == 0 }
+func (j jsonTrue) Contains(other JSON) bool   { return j.Compare(other) == 0 }
+func (j jsonNumber) Contains(other JSON) bool { return j.Compare(other) == 0 }
+func (j jsonString) Contains(other JSON) bool { return j.Compare(other) == 0 }
+
+// Contains implements the JSON interface. As in Postgres, a top-level array
+// also contains any scalar which is one of its elements.
+func (j jsonArray) Contains(other JSON) bool {
+	switch o := other.(type) {
+	case jsonArray:
+		return j.containsArray(o)
+	case jsonObject:
+		return false
+	default:
+		return j.containsArray(jsonArray{other})
+	}
+}
+
+// containsArray returns whether every element of other is contained in some
+// element of j. Scalars are only matched against scalars.
+func (j jsonArray) containsArray(other jsonArray) bool {
+	for _, oe := range other {
+		found := false
+		for _, e := range j {
+			if e.Type() == oe.Type() && containsNested(e, oe) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func (j jsonObject) Contains(other JSON) bool {
+	o, ok := other.(jsonObject)
+	if !ok {
+		return false
+	}
+	for _, kv := range o {
+		v := j.FetchValKey(string(kv.k))
+		if v == nil || v.Type() != kv.v.Type() || !containsNested(v, kv.v) {
+			return false
+		}
+	}
+	return true
+}
+
+// containsNested is Contains without the special case allowing a top-level
+// array to contain a scalar.
+func containsNested(j, other JSON) bool {
+	if a, ok := j.(jsonArray); ok {
+		o, ok := other.(jsonArray)
+		return ok && a.containsArray(o)
+	}
+	return j.Contains(other)
+}
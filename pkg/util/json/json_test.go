@@ -0,0 +1,209 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package json
+
+import (
+	"bytes"
+	"testing"
+)
+
+func mustParse(t *testing.T, s string) JSON {
+	j, err := ParseJSON(s)
+	if err != nil {
+		t.Fatalf("%s: %v", s, err)
+	}
+	return j
+}
+
+func TestParseJSON(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected string
+	}{
+		{`null`, `null`},
+		{` true `, `true`},
+		{`false`, `false`},
+		{`1`, `1`},
+		{`1.50`, `1.50`},
+		{`-1e2`, `-100`},
+		{`"a\"b\n"`, `"a\"b\n"`},
+		{`"<&>"`, `"<&>"`},
+		{`[]`, `[]`},
+		{`[1,"a",[null]]`, `[1, "a", [null]]`},
+		{`{}`, `{}`},
+		{`{"b":1,"a":{"c":[]}}`, `{"a": {"c": []}, "b": 1}`},
+		{`{"a":1,"a":2}`, `{"a": 2}`},
+	}
+	for _, tc := range testCases {
+		if s := mustParse(t, tc.input).String(); s != tc.expected {
+			t.Errorf("%s: expected %s, got %s", tc.input, tc.expected, s)
+		}
+	}
+
+	for _, s := range []string{``, `{`, `[1,]`, `1 2`, `{"a"}`, `nul`} {
+		if _, err := ParseJSON(s); err == nil {
+			t.Errorf("%q: expected error", s)
+		}
+	}
+}
+
+func TestJSONCompare(t *testing.T) {
+	// Each value sorts strictly before the next one.
+	ordered := []string{
+		`null`,
+		`""`,
+		`"a"`,
+		`"b"`,
+		`-1`,
+		`1`,
+		`1.5`,
+		`false`,
+		`true`,
+		`[]`,
+		`[2]`,
+		`[1, 2]`,
+		`{}`,
+		`{"a": 2}`,
+		`{"b": 1}`,
+		`{"a": 1, "b": 1}`,
+	}
+	for i := range ordered {
+		for k := range ordered {
+			a, b := mustParse(t, ordered[i]), mustParse(t, ordered[k])
+			if c, e := a.Compare(b), cmpInt(i, k); c != e {
+				t.Errorf("%s vs %s: expected %d, got %d", a, b, e, c)
+			}
+		}
+	}
+
+	if c := mustParse(t, `1.0`).Compare(mustParse(t, `1`)); c != 0 {
+		t.Errorf("expected 1.0 and 1 to be equal, got %d", c)
+	}
+}
+
+func TestJSONFetch(t *testing.T) {
+	j := mustParse(t, `{"a": [1, "x", null], "b": {"c": true}}`)
+	if v := j.FetchValKey("a").FetchValIdx(1); v.String() != `"x"` {
+		t.Errorf("expected \"x\", got %s", v)
+	}
+	if v := j.FetchValKey("a").FetchValIdx(-1); v != NullJSONValue {
+		t.Errorf("expected null, got %s", v)
+	}
+	if v := j.FetchValKey("a").FetchValIdx(3); v != nil {
+		t.Errorf("expected nil, got %s", v)
+	}
+	if v := j.FetchValKey("z"); v != nil {
+		t.Errorf("expected nil, got %s", v)
+	}
+	if v := j.FetchValIdx(0); v != nil {
+		t.Errorf("expected nil, got %s", v)
+	}
+	if s, ok := j.FetchValKey("a").FetchValIdx(1).AsText(); !ok || s != "x" {
+		t.Errorf("expected x, got %s", s)
+	}
+	if _, ok := NullJSONValue.AsText(); ok {
+		t.Errorf("expected null not to have a text representation")
+	}
+	if !j.Exists("b") || j.Exists("c") {
+		t.Errorf("unexpected result for Exists on %s", j)
+	}
+	if !mustParse(t, `["a", 1]`).Exists("a") || mustParse(t, `["a", 1]`).Exists("1") {
+		t.Errorf("unexpected result for Exists on array")
+	}
+}
+
+func TestJSONContains(t *testing.T) {
+	testCases := []struct {
+		left, right string
+		expected    bool
+	}{
+		{`1`, `1`, true},
+		{`1`, `1.0`, true},
+		{`1`, `2`, false},
+		{`"a"`, `"a"`, true},
+		{`[1, 2, 3]`, `[3, 1]`, true},
+		{`[1, 2, 3]`, `[]`, true},
+		{`[1, 2, 3]`, `[4]`, false},
+		{`[1, 2, 3]`, `1`, true},
+		{`[[1, 2]]`, `[1]`, false},
+		{`[[1, 2]]`, `[[1]]`, true},
+		{`[1, [2]]`, `[[2], 1]`, true},
+		{`{"a": [1, 2]}`, `{"a": 1}`, false},
+		{`{"a": [1, 2]}`, `{"a": [1]}`, true},
+		{`{"a": 1, "b": {"c": "d"}}`, `{"b": {}}`, true},
+		{`{"a": 1, "b": {"c": "d"}}`, `{"b": {"c": "d"}}`, true},
+		{`{"a": 1, "b": {"c": "d"}}`, `{"b": {"c": "e"}}`, false},
+		{`{"a": 1}`, `{}`, true},
+		{`{"a": 1}`, `[]`, false},
+		{`[]`, `{}`, false},
+		{`{"a": null}`, `{"a": null}`, true},
+		{`{"a": false}`, `{"a": true}`, false},
+	}
+	for _, tc := range testCases {
+		left, right := mustParse(t, tc.left), mustParse(t, tc.right)
+		if res := left.Contains(right); res != tc.expected {
+			t.Errorf("%s @> %s: expected %t, got %t", left, right, tc.expected, res)
+		}
+
+		// Every document containing another one must have all of its inverted
+		// index keys.
+		if !tc.expected {
+			continue
+		}
+		leftKeys := EncodeInvertedIndexKeys(nil, left)
+		for _, k := range EncodeInvertedIndexKeys(nil, right) {
+			found := false
+			for _, l := range leftKeys {
+				if bytes.Equal(k, l) {
+					found = true
+				}
+			}
+			if !found && right.Type() != NumberJSONType {
+				t.Errorf("%s @> %s: missing inverted index key %q", left, right, k)
+			}
+		}
+	}
+}
+
+func TestEncodeInvertedIndexKeys(t *testing.T) {
+	j := mustParse(t, `{"a": [1, 1.0, "b"], "c": {"d": null}}`)
+	keys := EncodeInvertedIndexKeys([]byte("prefix"), j)
+	// 1 and 1.0 produce the same key.
+	if len(keys) != 3 {
+		t.Fatalf("expected 3 keys, got %d: %q", len(keys), keys)
+	}
+	for _, k := range keys {
+		if !bytes.HasPrefix(k, []byte("prefix")) {
+			t.Errorf("expected key %q to have the prefix", k)
+		}
+	}
+
+	for _, s := range []string{`1`, `"a"`, `{}`, `[]`, `{"a": []}`} {
+		if _, ok := EncodeContainingInvertedIndexKey(nil, mustParse(t, s)); ok {
+			t.Errorf("%s: expected no key", s)
+		}
+	}
+	k, ok := EncodeContainingInvertedIndexKey(nil, mustParse(t, `{"c": {"d": null}}`))
+	if !ok {
+		t.Fatal("expected a key")
+	}
+	found := false
+	for _, l := range EncodeInvertedIndexKeys(nil, j) {
+		found = found || bytes.Equal(k, l)
+	}
+	if !found {
+		t.Errorf("expected key %q to be one of the keys of %s", k, j)
+	}
+}
@@ -560,6 +560,7 @@ func init() {
 	logging.fileThreshold = Severity_INFO
 
 	logging.setVState(0, nil, false)
+	logging.prefix = program
 	logging.exitFunc = os.Exit
 	logging.gcNotify = make(chan struct{}, 1)
 
@@ -582,6 +583,9 @@ func StartGCDaemon() {
 // Flush flushes all pending log I/O.
 func Flush() {
 	logging.lockAndFlushAll()
+	secondaryLogRegistry.forEach(func(l *loggingT) {
+		l.lockAndFlushAll()
+	})
 }
 
 // SetSync configures whether logging synchronizes all writes.
@@ -601,6 +605,10 @@ type loggingT struct {
 
 	noStderrRedirect bool
 
+	// prefix is the prefix of the names of the log files: the name of the
+	// program for the main logger, see SecondaryLogger for the others.
+	prefix string
+
 	// Level flag for output to stderr. Handled atomically.
 	stderrThreshold Severity
 	// Level flag for output to files.
@@ -928,7 +936,7 @@ func (sb *syncBuffer) rotateFile(now time.Time) error {
 		}
 	}
 	var err error
-	sb.file, sb.lastRotation, _, err = create(now, sb.lastRotation)
+	sb.file, sb.lastRotation, _, err = create(sb.logger.prefix, now, sb.lastRotation)
 	sb.nbytes = 0
 	if err != nil {
 		return err
@@ -938,7 +946,7 @@ func (sb *syncBuffer) rotateFile(now time.Time) error {
 	// stack traces that are written by the Go runtime to stderr. Note that if
 	// --logtostderr is true we'll never enter this code path and panic stack
 	// traces will go to the original stderr as you would expect.
-	if logging.stderrThreshold > Severity_INFO && !logging.noStderrRedirect {
+	if sb.logger.stderrThreshold > Severity_INFO && !sb.logger.noStderrRedirect {
 		// NB: any concurrent output to stderr may straddle the old and new
 		// files. This doesn't apply to log messages as we won't reach this code
 		// unless we're not logging to stderr.
@@ -973,11 +981,11 @@ func (sb *syncBuffer) rotateFile(now time.Time) error {
 		if err != nil {
 			return err
 		}
-		logging.putBuffer(buf)
+		sb.logger.putBuffer(buf)
 	}
 
 	select {
-	case logging.gcNotify <- struct{}{}:
+	case sb.logger.gcNotify <- struct{}{}:
 	default:
 	}
 	return nil
@@ -997,6 +1005,10 @@ func (l *loggingT) closeFileLocked() error {
 		}
 		l.file = nil
 	}
+	if l.noStderrRedirect {
+		// Secondary loggers never redirect stderr.
+		return nil
+	}
 	return restoreStderr()
 }
 
@@ -1027,6 +1039,9 @@ func (l *loggingT) flushDaemon() {
 			l.flushAll()
 		}
 		l.mu.Unlock()
+		secondaryLogRegistry.forEach(func(sl *loggingT) {
+			sl.lockAndFlushAll()
+		})
 	}
 }
 
@@ -1061,6 +1076,13 @@ func (l *loggingT) gcDaemon() {
 			l.gcOldFiles()
 		}
 		l.mu.Unlock()
+		// Secondary loggers share the notification channel of the main
+		// logger.
+		secondaryLogRegistry.forEach(func(sl *loggingT) {
+			sl.mu.Lock()
+			sl.gcOldFiles()
+			sl.mu.Unlock()
+		})
 	}
 }
 
@@ -1077,8 +1099,17 @@ func (l *loggingT) gcOldFiles() {
 		return
 	}
 
+	// Only consider the files of this logger, so that the files of the main
+	// and secondary loggers are bounded separately.
+	ownFiles := allFiles[:0]
+	for _, f := range allFiles {
+		if f.Details.Program == removePeriods(l.prefix) {
+			ownFiles = append(ownFiles, f)
+		}
+	}
+
 	logFilesCombinedMaxSize := atomic.LoadInt64(&LogFilesCombinedMaxSize)
-	files := selectFiles(allFiles, math.MaxInt64)
+	files := selectFiles(ownFiles, math.MaxInt64)
 	if len(files) == 0 {
 		return
 	}
@@ -159,20 +159,20 @@ func removePeriods(s string) string {
 	return strings.Replace(s, ".", "", -1)
 }
 
-// logName returns a new log file name with start time t, and the name
-// for the symlink.
-func logName(t time.Time) (name, link string) {
+// logName returns a new log file name with the given prefix and start time
+// t, and the name for the symlink.
+func logName(prefix string, t time.Time) (name, link string) {
 	// Replace the ':'s in the time format with '_'s to allow for log files in
 	// Windows.
 	tFormatted := strings.Replace(t.Format(time.RFC3339), ":", "_", -1)
 
 	name = fmt.Sprintf("%s.%s.%s.%s.%06d.log",
-		removePeriods(program),
+		removePeriods(prefix),
 		removePeriods(host),
 		removePeriods(userName),
 		tFormatted,
 		pid)
-	return name, removePeriods(program) + ".log"
+	return name, removePeriods(prefix) + ".log"
 }
 
 var errMalformedName = errors.New("malformed log filename")
@@ -211,7 +211,7 @@ var errDirectoryNotSet = errors.New("log: log directory not set")
 // filename. If the file is created successfully, create also attempts
 // to update the symlink for that tag, ignoring errors.
 func create(
-	t time.Time, lastRotation int64,
+	prefix string, t time.Time, lastRotation int64,
 ) (f *os.File, updatedRotation int64, filename string, err error) {
 	dir, err := logDir.get()
 	if err != nil {
@@ -228,7 +228,7 @@ func create(
 	t = time.Unix(unix, 0)
 
 	// Generate the file name.
-	name, link := logName(t)
+	name, link := logName(prefix, t)
 	fname := filepath.Join(dir, name)
 	// Open the file os.O_APPEND|os.O_CREATE rather than use os.Create.
 	// Append is almost always more efficient than O_RDRW on most modern file systems.
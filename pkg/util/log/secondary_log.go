@@ -0,0 +1,86 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package log
+
+import (
+	"os"
+
+	"golang.org/x/net/context"
+
+	"github.com/cockroachdb/cockroach/pkg/util/caller"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+)
+
+// SecondaryLogger writes log entries to files of its own, separate from the
+// main log files but in the same log directory. It is used for channels
+// which must not be mixed with the general logging output, such as audit
+// logs. Its entries are never written to stderr.
+type SecondaryLogger struct {
+	logger loggingT
+}
+
+// secondaryLogRegistryT keeps track of the secondary loggers so that they
+// are flushed and garbage collected along with the main logger.
+type secondaryLogRegistryT struct {
+	mu      syncutil.Mutex
+	loggers []*SecondaryLogger
+}
+
+var secondaryLogRegistry secondaryLogRegistryT
+
+// forEach calls fn on the underlying logger of every secondary logger.
+func (r *secondaryLogRegistryT) forEach(fn func(l *loggingT)) {
+	r.mu.Lock()
+	loggers := r.loggers
+	r.mu.Unlock()
+	for _, l := range loggers {
+		fn(&l.logger)
+	}
+}
+
+// NewSecondaryLogger creates a secondary logger whose file names start with
+// the name of the program followed by the given prefix, e.g.
+// "cockroach-sql-audit". If forceSyncWrites is set, every entry is flushed
+// and synced to disk before the logging call returns.
+//
+// Secondary loggers are never released, so they should be created once per
+// process, typically in a package-level variable.
+func NewSecondaryLogger(fileNamePrefix string, forceSyncWrites bool) *SecondaryLogger {
+	l := &SecondaryLogger{
+		logger: loggingT{
+			prefix:           program + "-" + fileNamePrefix,
+			noStderrRedirect: true,
+			stderrThreshold:  Severity_NONE,
+			fileThreshold:    Severity_INFO,
+			syncWrites:       forceSyncWrites,
+			exitFunc:         os.Exit,
+			gcNotify:         logging.gcNotify,
+		},
+	}
+	secondaryLogRegistry.mu.Lock()
+	defer secondaryLogRegistry.mu.Unlock()
+	secondaryLogRegistry.loggers = append(secondaryLogRegistry.loggers, l)
+	return l
+}
+
+// Logf logs an informational entry on the files of the secondary logger,
+// prefixed with the log tags of ctx. The entry is also added to the trace
+// of ctx, if any.
+func (l *SecondaryLogger) Logf(ctx context.Context, format string, args ...interface{}) {
+	file, line, _ := caller.Lookup(1)
+	msg := MakeMessage(ctx, format, args)
+	eventInternal(ctx, false /* isErr */, false /* withTags */, "%s", msg)
+	l.logger.outputLogEntry(Severity_INFO, file, line, msg)
+}
@@ -0,0 +1,70 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package log
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/context"
+)
+
+func TestSecondaryLog(t *testing.T) {
+	s := ScopeWithoutShowLogs(t)
+	defer s.Close(t)
+	setFlags()
+
+	l := NewSecondaryLogger("testing", true /* forceSyncWrites */)
+	ctx := WithLogTagStr(context.Background(), "user", "alice")
+	l.Logf(ctx, "secondary %d", 1)
+	Infof(context.Background(), "main %d", 2)
+	Flush()
+
+	// The secondary logger writes to a file of its own.
+	secondaryContents, err := ioutil.ReadFile(l.logger.file.(*syncBuffer).file.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(secondaryContents), "[user=alice] secondary 1") {
+		t.Errorf("secondary log does not contain secondary entry\n%s", secondaryContents)
+	}
+	if strings.Contains(string(secondaryContents), "main 2") {
+		t.Errorf("secondary log contains main entry\n%s", secondaryContents)
+	}
+
+	mainContents, err := ioutil.ReadFile(logging.file.(*syncBuffer).file.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(mainContents), "secondary 1") {
+		t.Errorf("main log contains secondary entry\n%s", mainContents)
+	}
+
+	// Both files are listed, under different program names.
+	results, err := ListLogFiles()
+	if err != nil {
+		t.Fatal(err)
+	}
+	programs := map[string]bool{}
+	for _, f := range results {
+		programs[f.Details.Program] = true
+	}
+	for _, p := range []string{program, program + "-testing"} {
+		if !programs[removePeriods(p)] {
+			t.Errorf("no log file found for %s in %+v", p, results)
+		}
+	}
+}
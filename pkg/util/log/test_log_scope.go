@@ -167,7 +167,18 @@ func dirTestOverride(expected, newDir string) error {
 	// When we change the directory we close the current logging
 	// output, so that a rotation to the new directory is forced on
 	// the next logging event.
-	return logging.closeFileLocked()
+	var err error
+	secondaryLogRegistry.forEach(func(l *loggingT) {
+		l.mu.Lock()
+		if closeErr := l.closeFileLocked(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+		l.mu.Unlock()
+	})
+	if closeErr := logging.closeFileLocked(); closeErr != nil && err == nil {
+		err = closeErr
+	}
+	return err
 }
 
 func isDirEmpty(dirname string) (bool, error) {